@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"slices"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// childAnnotations collects annotation nodes of the given types found in the
+// children of node's container child (e.g. Java "modifiers"), or in node's
+// direct children when containerType is empty.
+func childAnnotations(node *sitter.Node, content []byte, containerType string, annotationTypes ...string) []string {
+	var result []string
+	collect := func(parent *sitter.Node) {
+		for i := 0; i < int(parent.NamedChildCount()); i++ {
+			c := parent.NamedChild(i)
+			if c == nil {
+				continue
+			}
+			for _, t := range annotationTypes {
+				if c.Type() == t {
+					result = append(result, NormalizeAnnotation(c.Content(content)))
+					break
+				}
+			}
+		}
+	}
+	if containerType == "" {
+		collect(node)
+		return result
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		c := node.NamedChild(i)
+		if c != nil && c.Type() == containerType {
+			collect(c)
+		}
+	}
+	return result
+}
+
+// NormalizeAnnotation strips annotation sigils and brackets so the same name
+// can be queried across languages: "@Service" -> "Service",
+// "#[derive(Debug)]" -> "derive(Debug)", "[ApiController]" -> "ApiController".
+func NormalizeAnnotation(raw string) string {
+	s := strings.TrimSpace(raw)
+	switch {
+	case strings.HasPrefix(s, "#!["):
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "#!["), "]")
+	case strings.HasPrefix(s, "#["):
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "#["), "]")
+	case strings.HasPrefix(s, "["):
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	}
+	s = strings.TrimPrefix(s, "@")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// AnnotationName returns the bare name of a normalized annotation, without
+// arguments: "RequestMapping(\"/api\")" -> "RequestMapping".
+func AnnotationName(annotation string) string {
+	if idx := strings.IndexAny(annotation, "( "); idx >= 0 {
+		return annotation[:idx]
+	}
+	return annotation
+}
+
+// MatchesAnnotation reports whether an annotation matches a queried name.
+// The query matches the bare name, its last dotted segment ("route" matches
+// "app.route"), or the full normalized text. Leading sigils in the query are ignored.
+func MatchesAnnotation(annotation, query string) bool {
+	q := NormalizeAnnotation(query)
+	if q == "" {
+		return false
+	}
+	if annotation == q {
+		return true
+	}
+	name := AnnotationName(annotation)
+	if name == q {
+		return true
+	}
+	if idx := strings.LastIndex(name, "."); idx >= 0 && name[idx+1:] == q {
+		return true
+	}
+	return false
+}
+
+// declarationHeader returns the source of a declaration up to its first
+// child of one of the stop types, with whitespace collapsed, such as
+// "data class User(val id: Int)" or "func load(id: Int) -> User". Leading
+// annotations of annotationType, in the declaration's modifiers or as its
+// first child (PHP's attribute lists), are left out; other modifiers such
+// as "public" or "suspend" are kept.
+func declarationHeader(node *sitter.Node, content []byte, annotationType string, stop ...string) string {
+	start, end := node.StartByte(), node.EndByte()
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child == nil {
+			continue
+		}
+		if i == 0 && child.Type() == annotationType {
+			start = child.EndByte()
+		}
+		if i == 0 && child.Type() == "modifiers" {
+			start = child.EndByte()
+			for j := 0; j < int(child.ChildCount()); j++ {
+				if mod := child.Child(j); mod != nil && mod.Type() != annotationType {
+					start = mod.StartByte()
+					break
+				}
+			}
+		}
+		if slices.Contains(stop, child.Type()) {
+			end = child.StartByte()
+			break
+		}
+	}
+	if start >= end {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(content[start:end])), " ")
+}
@@ -0,0 +1,248 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func findSymbol(symbols []Symbol, name string) *Symbol {
+	for i := range symbols {
+		if symbols[i].Name == name {
+			return &symbols[i]
+		}
+		if s := findSymbol(symbols[i].Children, name); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestAnnotationsJava(t *testing.T) {
+	src := `package demo;
+
+@RestController
+@RequestMapping("/api")
+public class UserController {
+    @Deprecated
+    public void legacy() {}
+
+    @GetMapping("/users")
+    public String list(@RequestParam String q) {
+        return q;
+    }
+
+    public void plain() {}
+}
+`
+	fa, err := NewJavaParser().Parse([]byte(src), "UserController.java")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"UserController", []string{"RestController", `RequestMapping("/api")`}},
+		{"legacy", []string{"Deprecated"}},
+		{"list", []string{`GetMapping("/users")`}},
+		{"plain", nil},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(fa.Symbols, tt.name)
+		if sym == nil {
+			t.Fatalf("symbol %q not found", tt.name)
+		}
+		if !reflect.DeepEqual(sym.Annotations, tt.want) {
+			t.Errorf("%s annotations = %v, want %v", tt.name, sym.Annotations, tt.want)
+		}
+	}
+}
+
+func TestAnnotationsRust(t *testing.T) {
+	src := `#[derive(Debug, Clone)]
+pub struct Config {
+    name: String,
+}
+
+/// Runs the thing.
+#[inline]
+#[must_use]
+pub fn run() -> bool {
+    true
+}
+
+pub fn plain() {}
+
+#[derive(Default)]
+pub struct Pair { left: i32, right: i32 }
+`
+	fa, err := NewRustParser().Parse([]byte(src), "lib.rs")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Config", []string{"derive(Debug, Clone)"}},
+		{"run", []string{"inline", "must_use"}},
+		{"plain", nil},
+		{"Pair", []string{"derive(Default)"}},
+		{"left", nil},
+		{"right", nil},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(fa.Symbols, tt.name)
+		if sym == nil {
+			t.Fatalf("symbol %q not found", tt.name)
+		}
+		if !reflect.DeepEqual(sym.Annotations, tt.want) {
+			t.Errorf("%s annotations = %v, want %v", tt.name, sym.Annotations, tt.want)
+		}
+	}
+}
+
+func TestAnnotationsKotlin(t *testing.T) {
+	src := `package demo
+
+@Service
+class Svc {
+    @Inject
+    lateinit var repo: Repo
+
+    @Transactional
+    fun save() {}
+
+    fun plain() {}
+}
+
+@Component class Inline
+
+@Suppress("unused")
+val topLevel = 42
+`
+	fa, err := NewKotlinParser().Parse([]byte(src), "Svc.kt")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Svc", []string{"Service"}},
+		{"repo", []string{"Inject"}},
+		{"save", []string{"Transactional"}},
+		{"plain", nil},
+		{"Inline", []string{"Component"}},
+		{"topLevel", []string{`Suppress("unused")`}},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(fa.Symbols, tt.name)
+		if sym == nil {
+			t.Fatalf("symbol %q not found", tt.name)
+		}
+		if !reflect.DeepEqual(sym.Annotations, tt.want) {
+			t.Errorf("%s annotations = %v, want %v", tt.name, sym.Annotations, tt.want)
+		}
+	}
+}
+
+func TestAnnotationsCSharp(t *testing.T) {
+	src := `namespace Demo
+{
+    [ApiController]
+    [Route("api/[controller]")]
+    public class UsersController
+    {
+        [HttpGet]
+        public string List() { return ""; }
+
+        [Required]
+        public string Name { get; set; }
+
+        public void Plain() {}
+    }
+
+    [Flags] public enum Access { Read, Write }
+}
+`
+	fa, err := NewCSharpParser().Parse([]byte(src), "UsersController.cs")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"UsersController", []string{"ApiController", `Route("api/[controller]")`}},
+		{"List", []string{"HttpGet"}},
+		{"Name", []string{"Required"}},
+		{"Plain", nil},
+		{"Access", []string{"Flags"}},
+		{"Read", nil},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(fa.Symbols, tt.name)
+		if sym == nil {
+			t.Fatalf("symbol %q not found", tt.name)
+		}
+		if !reflect.DeepEqual(sym.Annotations, tt.want) {
+			t.Errorf("%s annotations = %v, want %v", tt.name, sym.Annotations, tt.want)
+		}
+	}
+}
+
+func TestAnnotationsPythonDecoratorsStayOnTheirTarget(t *testing.T) {
+	src := `@dataclass
+class Point:
+    x: int
+
+    def norm(self):
+        return self.x
+
+
+@app.route("/")
+def index():
+    return "ok"
+`
+	fa, err := NewPythonParser().Parse([]byte(src), "app.py")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if sym := findSymbol(fa.Symbols, "Point"); sym == nil || !reflect.DeepEqual(sym.Annotations, []string{"dataclass"}) {
+		t.Errorf("Point annotations = %v, want [dataclass]", sym)
+	}
+	if sym := findSymbol(fa.Symbols, "norm"); sym == nil || len(sym.Annotations) != 0 {
+		t.Errorf("norm should not inherit class decorators, got %+v", sym)
+	}
+	if sym := findSymbol(fa.Symbols, "index"); sym == nil || !reflect.DeepEqual(sym.Annotations, []string{`app.route("/")`}) {
+		t.Errorf("index annotations = %+v", sym)
+	}
+}
+
+func TestMatchesAnnotation(t *testing.T) {
+	tests := []struct {
+		annotation string
+		query      string
+		want       bool
+	}{
+		{"Deprecated", "Deprecated", true},
+		{"Deprecated", "@Deprecated", true},
+		{`GetMapping("/users")`, "GetMapping", true},
+		{`app.route("/")`, "route", true},
+		{`app.route("/")`, "app.route", true},
+		{"derive(Debug)", "#[derive(Debug)]", true},
+		{"Deprecated", "Depre", false},
+		{"Deprecated", "", false},
+	}
+	for _, tt := range tests {
+		if got := MatchesAnnotation(tt.annotation, tt.query); got != tt.want {
+			t.Errorf("MatchesAnnotation(%q, %q) = %v, want %v", tt.annotation, tt.query, got, tt.want)
+		}
+	}
+}
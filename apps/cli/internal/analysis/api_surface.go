@@ -0,0 +1,248 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+)
+
+// APISurfaceVersion is the version of the API surface layout.
+const APISurfaceVersion = 1
+
+// apiSurfaceKind identifies an API surface artifact.
+const apiSurfaceKind = "palace/api-surface"
+
+// APISurface is the exported API of a workspace by package: the exported
+// symbols of its non-test source files with their structured signatures.
+// It leaves out line numbers, so it changes only when the API does and two
+// surfaces can be compared symbol by symbol through their IDs.
+type APISurface struct {
+	Kind     string       `json:"kind"`
+	Version  int          `json:"version"`
+	Packages []APIPackage `json:"packages"`
+}
+
+// APIPackage is the exported symbols of one package: the source files of a
+// directory in one language.
+type APIPackage struct {
+	Path     string      `json:"path"` // The directory, "." at the root
+	Language string      `json:"language"`
+	Symbols  []APISymbol `json:"symbols"`
+}
+
+// APISymbol is one exported symbol. Members are listed after their type
+// under a qualified name such as "Server.Serve".
+type APISymbol struct {
+	ID         string      `json:"id"` // As in symbol diffs; see SymbolID
+	Name       string      `json:"name"`
+	Kind       SymbolKind  `json:"kind"`
+	File       string      `json:"file"`
+	Signature  string      `json:"signature,omitempty"`
+	Params     []Parameter `json:"params,omitempty"`
+	Returns    []string    `json:"returns,omitempty"`
+	TypeParams []TypeParam `json:"typeParams,omitempty"`
+	Doc        string      `json:"doc,omitempty"`
+	Deprecated bool        `json:"deprecated,omitempty"`
+}
+
+// NewAPISurface returns an empty API surface.
+func NewAPISurface() *APISurface {
+	return &APISurface{Kind: apiSurfaceKind, Version: APISurfaceVersion, Packages: []APIPackage{}}
+}
+
+// HasAPI reports whether symbols in lang can form an API. Data, markup, and
+// configuration languages declare keys, headings, selectors, and resources
+// instead.
+func HasAPI(lang Language) bool {
+	switch lang {
+	case LangJSON, LangYAML, LangTOML, LangMarkdown, LangHTML, LangCSS,
+		LangDockerfile, LangHCL, LangSQL, LangGeneric, LangUnknown:
+		return false
+	}
+	return true
+}
+
+// Add adds the exported symbols of fa to the surface. Test files, languages
+// without an API, and members of unexported types are left out, as are
+// tests, findings, and config keys. Call Sort when done adding.
+func (s *APISurface) Add(fa *FileAnalysis) {
+	if fa == nil {
+		return
+	}
+	lang := Language(fa.Language)
+	if !HasAPI(lang) || fa.IsTest || IsTestFile(fa.Path, lang) {
+		return
+	}
+	dir := path.Dir(fa.Path)
+	var pkg *APIPackage
+	for i := range s.Packages {
+		if s.Packages[i].Path == dir && s.Packages[i].Language == fa.Language {
+			pkg = &s.Packages[i]
+		}
+	}
+	if pkg == nil {
+		s.Packages = append(s.Packages, APIPackage{Path: dir, Language: fa.Language, Symbols: []APISymbol{}})
+		pkg = &s.Packages[len(s.Packages)-1]
+	}
+
+	overloaded := overloadedNames(fa)
+	seen := make(map[string]int)
+	var walk func(syms []Symbol, prefix string)
+	walk = func(syms []Symbol, prefix string) {
+		for i := range syms {
+			sym := &syms[i]
+			if !sym.Exported || sym.Test || IsSyntheticKind(sym.Kind) {
+				continue
+			}
+			name := sym.Name
+			if prefix != "" {
+				name = prefix + "." + sym.Name
+			}
+			key := name
+			if overloaded[name] && isCallable(sym.Kind) {
+				key = OverloadKey(name, sym.Signature)
+			}
+			if n := seen[key]; n > 0 {
+				key = fmt.Sprintf("%s~%d", key, n)
+			}
+			seen[key]++
+
+			api := APISymbol{
+				ID:         SymbolID(fa.Path, key),
+				Name:       name,
+				Kind:       sym.Kind,
+				File:       fa.Path,
+				Signature:  sym.Signature,
+				TypeParams: sym.TypeParams,
+				Doc:        sym.DocComment,
+				Deprecated: sym.Deprecated,
+			}
+			if isCallable(sym.Kind) && sym.Signature != "" {
+				api.Params = ParseParameters(sym.Signature, lang)
+				api.Returns = ParseReturns(sym.Signature, lang)
+			}
+			pkg.Symbols = append(pkg.Symbols, api)
+			walk(sym.Children, name)
+		}
+	}
+	walk(fa.Symbols, "")
+}
+
+// Sort orders the packages by path and language and their symbols by ID,
+// and drops packages without exported symbols, so that the same API always
+// serializes the same way.
+func (s *APISurface) Sort() {
+	kept := s.Packages[:0]
+	for _, pkg := range s.Packages {
+		if len(pkg.Symbols) > 0 {
+			sort.SliceStable(pkg.Symbols, func(i, j int) bool { return pkg.Symbols[i].ID < pkg.Symbols[j].ID })
+			kept = append(kept, pkg)
+		}
+	}
+	s.Packages = kept
+	sort.Slice(s.Packages, func(i, j int) bool {
+		if s.Packages[i].Path != s.Packages[j].Path {
+			return s.Packages[i].Path < s.Packages[j].Path
+		}
+		return s.Packages[i].Language < s.Packages[j].Language
+	})
+}
+
+// Symbols returns the number of symbols in the surface.
+func (s *APISurface) Symbols() int {
+	n := 0
+	for _, pkg := range s.Packages {
+		n += len(pkg.Symbols)
+	}
+	return n
+}
+
+// WriteAPISurface writes s to w as indented JSON.
+func WriteAPISurface(w io.Writer, s *APISurface) error {
+	out := *s
+	out.Kind, out.Version = apiSurfaceKind, APISurfaceVersion
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&out)
+}
+
+// ReadAPISurface reads an API surface written by WriteAPISurface. Surfaces
+// of a newer version than this build understands are rejected rather than
+// misread.
+func ReadAPISurface(r io.Reader) (*APISurface, error) {
+	var s APISurface
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode API surface: %w", err)
+	}
+	if s.Kind != apiSurfaceKind {
+		return nil, errors.New("not an API surface")
+	}
+	if s.Version > APISurfaceVersion {
+		return nil, fmt.Errorf("API surface version %d is newer than supported version %d", s.Version, APISurfaceVersion)
+	}
+	return &s, nil
+}
+
+// DiffAPISurfaces reports the symbols added to, removed from, or given a new
+// signature between two API surfaces, matched by ID. Signature changes carry
+// the same parameter detail as symbol diffs, so breaking changes can be told
+// apart from compatible ones.
+func DiffAPISurfaces(old, cur *APISurface) []SymbolChange {
+	type entry struct {
+		sym  APISymbol
+		lang Language
+	}
+	index := func(s *APISurface) map[string]entry {
+		result := make(map[string]entry)
+		for _, pkg := range s.Packages {
+			for _, sym := range pkg.Symbols {
+				result[sym.ID] = entry{sym: sym, lang: Language(pkg.Language)}
+			}
+		}
+		return result
+	}
+	oldSyms, curSyms := index(old), index(cur)
+
+	var changes []SymbolChange
+	for id, n := range curSyms {
+		o, ok := oldSyms[id]
+		if !ok {
+			changes = append(changes, SymbolChange{
+				ID: id, File: n.sym.File, Name: n.sym.Name, SymbolKind: n.sym.Kind,
+				Change: SymbolAdded, NewSignature: n.sym.Signature,
+			})
+			continue
+		}
+		if o.sym.Signature == n.sym.Signature && o.sym.Kind == n.sym.Kind && typeParamsKey(o.sym.TypeParams) == typeParamsKey(n.sym.TypeParams) {
+			continue
+		}
+		change := SymbolChange{
+			ID: id, File: n.sym.File, Name: n.sym.Name, SymbolKind: n.sym.Kind,
+			Change: SymbolSignatureChanged, OldSignature: o.sym.Signature, NewSignature: n.sym.Signature,
+		}
+		if isCallable(o.sym.Kind) && isCallable(n.sym.Kind) {
+			change.Params = DiffParameters(o.sym.Signature, n.sym.Signature, n.lang)
+		}
+		change.TypeParams = DiffTypeParams(o.sym.TypeParams, n.sym.TypeParams)
+		changes = append(changes, change)
+	}
+	for id, o := range oldSyms {
+		if _, ok := curSyms[id]; !ok {
+			changes = append(changes, SymbolChange{
+				ID: id, File: o.sym.File, Name: o.sym.Name, SymbolKind: o.sym.Kind,
+				Change: SymbolRemoved, OldSignature: o.sym.Signature,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return changes[i].File < changes[j].File
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	return changes
+}
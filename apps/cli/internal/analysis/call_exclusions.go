@@ -0,0 +1,83 @@
+package analysis
+
+import "strings"
+
+// jsBuiltinCalls are the JavaScript globals that parse as calls but never
+// resolve to project code. TypeScript shares them.
+var jsBuiltinCalls = []string{
+	"console.*", "JSON.*", "Math.*", "Object.*", "Reflect.*", "Number.*", "Promise.*", "Array.isArray", "Array.from", "Array.of",
+	"parseInt", "parseFloat", "isNaN", "isFinite", "String", "Number", "Boolean", "Array", "Symbol", "BigInt",
+	"setTimeout", "setInterval", "clearTimeout", "clearInterval", "queueMicrotask", "structuredClone",
+	"encodeURIComponent", "decodeURIComponent", "encodeURI", "decodeURI",
+}
+
+// builtinCalls lists, by language, the builtins and call-like keywords that
+// parsers report as calls but that never resolve to project code, so they
+// are left out of the call graph. A name ending in ".*" excludes every
+// member of a builtin object, e.g. "console.*" covers console.log.
+var builtinCalls = map[Language][]string{
+	LangPython: {
+		"print", "len", "isinstance", "issubclass", "range", "enumerate", "zip", "map", "filter", "sorted", "reversed",
+		"sum", "min", "max", "abs", "any", "all", "round", "divmod", "pow",
+		"str", "int", "float", "bool", "complex", "list", "dict", "set", "frozenset", "tuple", "bytes", "bytearray",
+		"memoryview", "object", "slice", "type", "id", "hash", "repr", "ascii", "format", "chr", "ord", "hex", "oct", "bin",
+		"getattr", "setattr", "hasattr", "delattr", "iter", "next", "open", "input", "super", "vars", "dir", "callable",
+		"property", "staticmethod", "classmethod", "globals", "locals", "exec", "eval", "compile", "breakpoint", "help",
+	},
+	LangJavaScript: jsBuiltinCalls,
+	LangTypeScript: jsBuiltinCalls,
+}
+
+// SetCallExclusions adds project-specific names to leave out of call
+// relationships in Analyze, keyed by language name, with "*" applying to
+// every language. Names follow the builtin lists: exact call targets, or
+// "obj.*" for every member of obj. Passing nil keeps only the builtins.
+func SetCallExclusions(extra map[string][]string) {
+	defaultRegistry.SetCallExclusions(extra)
+}
+
+// SetCallExclusions adds project-specific names to leave out of call
+// relationships in Parse. See the package-level SetCallExclusions.
+func (r *ParserRegistry) SetCallExclusions(extra map[string][]string) {
+	r.mu.Lock()
+	r.callExclusions = extra
+	r.mu.Unlock()
+}
+
+// excludeCalls drops from fa the call relationships whose target is a
+// builtin of lang or one of the extra exclusions.
+func excludeCalls(fa *FileAnalysis, lang Language, extra map[string][]string) {
+	if fa == nil {
+		return
+	}
+	names := make(map[string]bool)
+	for _, list := range [][]string{builtinCalls[lang], extra[string(lang)], extra["*"]} {
+		for _, name := range list {
+			names[name] = true
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	kept := fa.Relationships[:0]
+	for _, rel := range fa.Relationships {
+		if rel.Kind == RelCall && excludedCall(rel.TargetSymbol, names) {
+			continue
+		}
+		kept = append(kept, rel)
+	}
+	fa.Relationships = kept
+}
+
+// excludedCall reports whether target is one of names, or a member of an
+// object excluded as "obj.*".
+func excludedCall(target string, names map[string]bool) bool {
+	if names[target] {
+		return true
+	}
+	if dot := strings.IndexByte(target, '.'); dot > 0 {
+		return names[target[:dot]+".*"]
+	}
+	return false
+}
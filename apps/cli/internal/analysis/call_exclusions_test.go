@@ -0,0 +1,54 @@
+package analysis
+
+import "testing"
+
+func callTargets(fa *FileAnalysis) map[string]bool {
+	targets := make(map[string]bool)
+	for _, rel := range fa.Relationships {
+		if rel.Kind == RelCall {
+			targets[rel.TargetSymbol] = true
+		}
+	}
+	return targets
+}
+
+func TestExcludeBuiltinCalls(t *testing.T) {
+	src := `def total(items):
+    return sum(items)
+
+def report(items):
+    print(len(items))
+    if isinstance(items, list):
+        log.debug(total(items))
+`
+	reg := NewParserRegistryWithPath("")
+	fa, err := reg.Parse([]byte(src), "report.py")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	calls := callTargets(fa)
+	for _, builtin := range []string{"print", "len", "isinstance", "sum"} {
+		if calls[builtin] {
+			t.Errorf("builtin %s() should not be a call relationship: %v", builtin, calls)
+		}
+	}
+	if !calls["total"] || !calls["log.debug"] {
+		t.Errorf("user-defined calls should be kept: %v", calls)
+	}
+
+	reg.SetCallExclusions(map[string][]string{"python": {"log.*"}})
+	if fa, err = reg.Parse([]byte(src), "report.py"); err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if calls = callTargets(fa); calls["log.debug"] || !calls["total"] {
+		t.Errorf("configured exclusions should drop log.debug only: %v", calls)
+	}
+
+	fa, err = reg.Parse([]byte("console.log(format(JSON.parse(raw)));\n"), "app.js")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if calls = callTargets(fa); len(calls) != 1 || !calls["format"] {
+		t.Errorf("expected only format() from JavaScript, got %v", calls)
+	}
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CommentedCodeRule names the finding emitted for commented-out code.
+const CommentedCodeRule = "commented-out-code"
+
+// minCommentedCodeLines is the shortest run of comment lines reported as
+// commented-out code. Single lines are too often examples or notes.
+const minCommentedCodeLines = 3
+
+var (
+	// Statements and declarations that open a line of code in common languages
+	codeLeadRe = regexp.MustCompile(`^(func|def|class|return|if|else|elif|for|while|switch|case|var|let|const|import|from|package|public|private|protected|static|fn|pub|use|struct|type|try|catch|except|finally|defer|go|await|async|yield|raise|throw|println|print|console\.)\b`)
+	// A call or assignment such as foo(bar) or x := y
+	codeCallRe   = regexp.MustCompile(`^[\w.$\[\]]+\s*\(.*\)\s*[;{]?$`)
+	codeAssignRe = regexp.MustCompile(`^[\w.$\[\]]+(\s*,\s*[\w.$]+)*\s*(:=|[-+*/|&]?=)\s*\S`)
+)
+
+// FindCommentedCode reports runs of own-line comments that read as code
+// rather than prose. A run must span at least minCommentedCodeLines lines,
+// most of its non-blank lines must look like code, and at least one must
+// carry structural punctuation (braces, semicolons, or a trailing colon),
+// which keeps wrapped prose that happens to mention code from matching.
+func FindCommentedCode(lang Language, content []byte) []Symbol {
+	var findings []Symbol
+	var run []Comment
+	flush := func() {
+		if sym, ok := commentedCodeRun(run); ok {
+			findings = append(findings, sym)
+		}
+		run = run[:0]
+	}
+	for _, c := range Comments(lang, content) {
+		if !c.OwnLine || (len(run) > 0 && c.Line != run[len(run)-1].Line+1) {
+			flush()
+		}
+		if c.OwnLine {
+			run = append(run, c)
+		}
+	}
+	flush()
+	return findings
+}
+
+// commentedCodeRun decides whether a run of consecutive comment lines is
+// commented-out code and, if so, returns the finding spanning it.
+func commentedCodeRun(run []Comment) (Symbol, bool) {
+	if len(run) < minCommentedCodeLines {
+		return Symbol{}, false
+	}
+	var lines, code int
+	structural := false
+	for _, c := range run {
+		text := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(c.Text), "*#/"))
+		if text == "" {
+			continue
+		}
+		lines++
+		if looksLikeCode(text) {
+			code++
+		}
+		if strings.ContainsAny(text, "{};") || (strings.HasSuffix(text, ":") && codeLeadRe.MatchString(text)) {
+			structural = true
+		}
+	}
+	// At least three code lines, making up over two thirds of the run
+	if code < minCommentedCodeLines || code*3 <= lines*2 || !structural {
+		return Symbol{}, false
+	}
+	first, last := run[0].Line, run[len(run)-1].Line
+	return Symbol{
+		Name:      CommentedCodeRule,
+		Kind:      KindFinding,
+		LineStart: first,
+		LineEnd:   last,
+		Signature: fmt.Sprintf("commented-out code (%d lines)", last-first+1),
+	}, true
+}
+
+// looksLikeCode reports whether a single comment line reads as code.
+func looksLikeCode(text string) bool {
+	switch {
+	case text == "}" || text == "{" || text == "};" || text == "})" || text == "end":
+		return true
+	case strings.HasSuffix(text, ".") && !strings.HasSuffix(text, ".."):
+		return false // Sentences end in periods; code lines almost never do
+	case codeLeadRe.MatchString(text), codeCallRe.MatchString(text), codeAssignRe.MatchString(text):
+		return true
+	case strings.HasSuffix(text, ";") || strings.HasSuffix(text, "{"):
+		return true
+	}
+	return false
+}
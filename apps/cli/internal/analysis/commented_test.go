@@ -0,0 +1,35 @@
+package analysis
+
+import "testing"
+
+func TestFindCommentedCode(t *testing.T) {
+	src := `package auth
+
+// Login checks the credentials against the store and returns a
+// session when they match. Sessions expire after an hour, and the
+// caller is expected to refresh them before then.
+func Login() {}
+
+// func legacyLogin(user string) error {
+//     token := issueToken(user)
+//     store.Save(token);
+//     return nil
+// }
+`
+	findings := FindCommentedCode(LangGo, []byte(src))
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %+v", findings)
+	}
+	f := findings[0]
+	if f.Name != CommentedCodeRule || f.Kind != KindFinding || f.LineStart != 8 || f.LineEnd != 12 {
+		t.Errorf("expected a finding spanning lines 8-12, got %+v", f)
+	}
+}
+
+func TestFindCommentedCodePython(t *testing.T) {
+	src := "# def old_handler(request):\n#     data = request.json()\n#     return process(data)\nx = 1  # trailing\n"
+	findings := FindCommentedCode(LangPython, []byte(src))
+	if len(findings) != 1 || findings[0].LineStart != 1 || findings[0].LineEnd != 3 {
+		t.Errorf("expected one finding on lines 1-3, got %+v", findings)
+	}
+}
@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// decisionPoint matches the branch keywords and boolean operators that
+// add a path through code in most languages.
+var decisionPoint = regexp.MustCompile(`\b(if|elif|elsif|for|foreach|while|until|unless|case|when|catch|except|rescue|guard)\b|&&|\|\||\band\b|\bor\b`)
+
+// EstimateComplexity approximates the cyclomatic complexity of source code:
+// one plus a point for every branch keyword and boolean operator outside
+// comments and string literals. It reads the text rather than a syntax tree,
+// so it works for every language at the cost of some precision, such as
+// counting "for" in a Python comprehension.
+func EstimateComplexity(source string) int {
+	complexity := 1
+	for _, line := range strings.Split(source, "\n") {
+		complexity += len(decisionPoint.FindAllStringIndex(codeOnly(line), -1))
+	}
+	return complexity
+}
+
+// codeOnly removes string literals and a trailing line comment from line.
+// Block comments spanning lines are not tracked; their lines that start
+// with "*" are dropped.
+func codeOnly(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "/*") {
+		return ""
+	}
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(line) && line[i+1] == '/',
+			c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return b.String()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
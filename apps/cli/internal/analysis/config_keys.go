@@ -0,0 +1,157 @@
+package analysis
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Configuration keys read by string are indexed as synthetic KindConfigKey
+// symbols, one per key and file at its first read, and every read as a
+// RelReference relationship targeting the key, so that the code reading a
+// setting can be found from the setting's name.
+
+// configAccessors match, per language, the code just before a string
+// literal that reads an environment variable or configuration value, such
+// as `os.environ[` before "DB_HOST". They are anchored at the literal.
+var configAccessors = map[Language][]*regexp.Regexp{
+	LangGo: {
+		regexp.MustCompile(`\bos\.(?:Getenv|LookupEnv)\(\s*$`),
+	},
+	LangPython: {
+		regexp.MustCompile(`\bos\.environ\[\s*$`),
+		regexp.MustCompile(`\bos\.(?:environ\.get|getenv)\(\s*$`),
+	},
+	LangJavaScript: {jsEnvAccessor},
+	LangTypeScript: {jsEnvAccessor},
+	LangSvelte:     {jsEnvAccessor},
+	LangRuby: {
+		regexp.MustCompile(`\bENV(?:\[|\.fetch\(|\.key\?\()\s*$`),
+	},
+	LangJava: {
+		regexp.MustCompile(`\bSystem\.(?:getenv|getProperty)\(\s*$`),
+		regexp.MustCompile(`@Value\(\s*$`),
+	},
+	LangKotlin: {
+		regexp.MustCompile(`\bSystem\.(?:getenv|getProperty)\(\s*$`),
+		regexp.MustCompile(`@Value\(\s*$`),
+	},
+	LangRust: {
+		regexp.MustCompile(`\b(?:std::)?env::var(?:_os)?\(\s*$`),
+		regexp.MustCompile(`\b(?:option_)?env!\(\s*$`),
+	},
+	LangPHP: {
+		regexp.MustCompile(`\b(?:getenv|env)\(\s*$`),
+		regexp.MustCompile(`\$_(?:ENV|SERVER)\[\s*$`),
+	},
+	LangCSharp: {
+		regexp.MustCompile(`\bEnvironment\.GetEnvironmentVariable\(\s*$`),
+		regexp.MustCompile(`\b[Cc]onfiguration\[\s*$`),
+	},
+	LangC:   {cEnvAccessor},
+	LangCPP: {cEnvAccessor},
+	LangSwift: {
+		regexp.MustCompile(`\bProcessInfo\.processInfo\.environment\[\s*$`),
+	},
+	LangDart: {
+		regexp.MustCompile(`\bPlatform\.environment\[\s*$`),
+		regexp.MustCompile(`\bString\.fromEnvironment\(\s*$`),
+	},
+}
+
+var (
+	jsEnvAccessor = regexp.MustCompile(`\bprocess\.env\[\s*$`)
+	cEnvAccessor  = regexp.MustCompile(`\b(?:std::)?(?:secure_)?getenv\(\s*$`)
+	// Configuration libraries in any language: config.get("db.host"),
+	// viper.GetString("db.host"), settings.get_int("workers"), ...
+	configGetAccessor = regexp.MustCompile(`\b(?i:config|conf|cfg|settings|configuration|viper)(?:\(\))?\.(?:get|Get)(?:_?[A-Za-z]+)?\(\s*$`)
+	// JavaScript's process.env.DB_HOST reads a key without a string
+	jsEnvMemberRe = regexp.MustCompile(`\bprocess\.env\.([A-Za-z_][A-Za-z0-9_]*)`)
+	// A literal that names a key rather than holding prose or a template
+	configKeyRe = regexp.MustCompile(`^[A-Za-z_][\w.\-:/]{0,99}$`)
+)
+
+// FindConfigKeys returns the configuration keys content reads by string:
+// one KindConfigKey symbol per key, at its first read, and a RelReference
+// to the key for every read.
+func FindConfigKeys(lang Language, content []byte) ([]Symbol, []Relationship) {
+	accessors := slices.Concat(configAccessors[lang], []*regexp.Regexp{configGetAccessor})
+	lines := strings.Split(string(content), "\n")
+
+	var symbols []Symbol
+	var refs []Relationship
+	seen := make(map[string]bool)
+	add := func(key string, line, col int, accessor string) {
+		refs = append(refs, Relationship{TargetSymbol: key, Kind: RelReference, Line: line, Column: col})
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		symbols = append(symbols, Symbol{
+			Name:      key,
+			Kind:      KindConfigKey,
+			LineStart: line,
+			LineEnd:   line,
+			ColStart:  col,
+			Signature: accessor,
+		})
+	}
+
+	for _, lit := range StringLiterals(lang, content) {
+		if lit.Line < 1 || lit.Line > len(lines) || lit.Column > len(lines[lit.Line-1]) {
+			continue
+		}
+		before := lines[lit.Line-1][:lit.Column]
+		key := configKey(lit.Value)
+		if key == "" {
+			continue
+		}
+		for _, accessor := range accessors {
+			if loc := accessor.FindStringIndex(before); loc != nil {
+				add(key, lit.Line, lit.Column+1, strings.TrimSpace(before[loc[0]:]))
+				break
+			}
+		}
+	}
+
+	if lang == LangJavaScript || lang == LangTypeScript || lang == LangSvelte {
+		comments := make(map[int]bool)
+		for _, c := range Comments(lang, content) {
+			if c.OwnLine {
+				comments[c.Line] = true
+			}
+		}
+		for i, line := range lines {
+			if comments[i+1] {
+				continue
+			}
+			for _, m := range jsEnvMemberRe.FindAllStringSubmatchIndex(line, -1) {
+				add(line[m[2]:m[3]], i+1, m[2], "process.env")
+			}
+		}
+	}
+	return symbols, refs
+}
+
+// configKey returns the key a string literal names, or "" if it does not
+// look like one. Spring's "${db.host:localhost}" names db.host.
+func configKey(value string) string {
+	if inner, ok := strings.CutPrefix(value, "${"); ok {
+		value, _, _ = strings.Cut(strings.TrimSuffix(inner, "}"), ":")
+	}
+	if !configKeyRe.MatchString(value) {
+		return ""
+	}
+	return value
+}
+
+// addConfigKeys appends the configuration keys fa's source reads; see
+// FindConfigKeys.
+func addConfigKeys(fa *FileAnalysis, lang Language, content []byte) {
+	if fa == nil {
+		return
+	}
+	symbols, refs := FindConfigKeys(lang, content)
+	fa.Symbols = append(fa.Symbols, symbols...)
+	fa.Relationships = append(fa.Relationships, refs...)
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import "testing"
+
+func TestFindConfigKeysPython(t *testing.T) {
+	src := "import os\n\n# os.environ[\"COMMENTED\"]\ndef connect():\n    host = os.environ[\"DB_HOST\"]\n    port = os.getenv('DB_PORT', '5432')\n    return os.environ.get(\"DB_HOST\"), print(\"DB_NAME\")\n"
+	symbols, refs := FindConfigKeys(LangPython, []byte(src))
+
+	var names []string
+	for _, s := range symbols {
+		if s.Kind != KindConfigKey {
+			t.Errorf("expected config_key symbols, got %+v", s)
+		}
+		names = append(names, s.Name)
+	}
+	if len(names) != 2 || names[0] != "DB_HOST" || names[1] != "DB_PORT" {
+		t.Fatalf("expected config keys DB_HOST and DB_PORT, got %v", names)
+	}
+	if symbols[0].LineStart != 5 || symbols[0].Signature != "os.environ[" {
+		t.Errorf("DB_HOST: expected first read on line 5 via os.environ[, got %+v", symbols[0])
+	}
+
+	var hostReads []int
+	for _, r := range refs {
+		if r.Kind != RelReference {
+			t.Errorf("expected reference relationships, got %+v", r)
+		}
+		if r.TargetSymbol == "DB_HOST" {
+			hostReads = append(hostReads, r.Line)
+		}
+	}
+	if len(refs) != 3 || len(hostReads) != 2 || hostReads[0] != 5 || hostReads[1] != 7 {
+		t.Errorf("expected references to DB_HOST on lines 5 and 7 and one to DB_PORT, got %+v", refs)
+	}
+}
+
+func TestFindConfigKeysAccessors(t *testing.T) {
+	tests := []struct {
+		lang Language
+		src  string
+		want string
+	}{
+		{LangGo, `addr := os.Getenv("LISTEN_ADDR")`, "LISTEN_ADDR"},
+		{LangJavaScript, `const url = process.env.API_URL;`, "API_URL"},
+		{LangJavaScript, `const url = process.env["API_URL"];`, "API_URL"},
+		{LangRuby, `secret = ENV.fetch("SECRET_KEY_BASE")`, "SECRET_KEY_BASE"},
+		{LangJava, `@Value("${db.host:localhost}") String host;`, "db.host"},
+		{LangRust, `let home = std::env::var("HOME")?;`, "HOME"},
+		{LangGo, `timeout := viper.GetDuration("server.timeout")`, "server.timeout"},
+		{LangPython, `workers = settings.get_int("workers")`, "workers"},
+	}
+	for _, tt := range tests {
+		symbols, refs := FindConfigKeys(tt.lang, []byte(tt.src+"\n"))
+		if len(symbols) != 1 || symbols[0].Name != tt.want || len(refs) != 1 || refs[0].TargetSymbol != tt.want {
+			t.Errorf("%s %q: expected config key %s, got %+v %+v", tt.lang, tt.src, tt.want, symbols, refs)
+		}
+	}
+
+	if symbols, _ := FindConfigKeys(LangGo, []byte(`log.Printf("DB_HOST=%s", host)`+"\n")); len(symbols) != 0 {
+		t.Errorf("expected no config keys outside accessors, got %+v", symbols)
+	}
+}
+
+func TestAnalyzeConfigKeys(t *testing.T) {
+	fa, err := Analyze([]byte("import os\n\n\ndef connect():\n    return os.environ[\"DB_HOST\"]\n"), "db.py")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	var key *Symbol
+	for i := range fa.Symbols {
+		if fa.Symbols[i].Kind == KindConfigKey {
+			key = &fa.Symbols[i]
+		}
+	}
+	if key == nil || key.Name != "DB_HOST" {
+		t.Fatalf("expected a DB_HOST config key symbol, got %+v", fa.Symbols)
+	}
+	for _, r := range fa.Relationships {
+		if r.Kind == RelReference && r.TargetSymbol == "DB_HOST" && r.Line == 5 {
+			return
+		}
+	}
+	t.Errorf("expected a reference to DB_HOST on line 5, got %+v", fa.Relationships)
+}
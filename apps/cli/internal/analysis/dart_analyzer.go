@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/limiter"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/logger"
 )
 
@@ -194,94 +196,138 @@ func (a *DartAnalyzer) ExtractAllCalls(files []string, progressFn func(current,
 
 // QuickCallScan performs a faster scan by only analyzing exported symbols
 func (a *DartAnalyzer) QuickCallScan(files []string, progressFn func(current, total int, file string)) ([]CallInfo, error) {
-	var allCalls []CallInfo
+	return a.QuickCallScanContext(context.Background(), files, progressFn)
+}
 
+// QuickCallScanContext is QuickCallScan under the limiter.Manager carried
+// by ctx: files are analyzed by as many workers as it allows concurrent
+// language server requests, each parse taking a CPU slot and each request
+// an LSP slot. progressFn is called as files finish, one call at a time.
+// When ctx is cancelled the calls found so far are returned with its error.
+func (a *DartAnalyzer) QuickCallScanContext(ctx context.Context, files []string, progressFn func(current, total int, file string)) ([]CallInfo, error) {
+	limits := limiter.From(ctx)
 	total := len(files)
 	logger.Info("Starting QuickCallScan on %d files", total)
 
-	for i, file := range files {
-		if progressFn != nil {
-			progressFn(i+1, total, file)
+	results := make([][]CallInfo, total)
+	next := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		progress sync.Mutex
+		done     int
+	)
+	for w := 0; w < min(limits.Limits().LSP, total); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = a.quickScanFile(ctx, limits, files[i])
+				progress.Lock()
+				done++
+				if progressFn != nil {
+					progressFn(done, total, files[i])
+				}
+				progress.Unlock()
+			}
+		}()
+	}
+	for i := range files {
+		if ctx.Err() != nil {
+			break
 		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
 
-		if !strings.HasSuffix(file, ".dart") {
-			continue
-		}
+	var allCalls []CallInfo
+	for _, calls := range results {
+		allCalls = append(allCalls, calls...)
+	}
+	logger.Info("QuickCallScan complete: %d total call relationships", len(allCalls))
+	return allCalls, ctx.Err()
+}
 
-		relFile := a.toRelativePath(file)
-		logger.Debug("Analyzing: %s", relFile)
-		startTime := time.Now()
+// quickScanFile extracts the calls of the exported functions, constructors,
+// and methods of one Dart file.
+func (a *DartAnalyzer) quickScanFile(ctx context.Context, limits *limiter.Manager, file string) []CallInfo {
+	if !strings.HasSuffix(file, ".dart") {
+		return nil
+	}
 
-		content, err := os.ReadFile(file)
-		if err != nil {
-			logger.Error("Failed to read file %s: %v", relFile, err)
-			continue
+	relFile := a.toRelativePath(file)
+	logger.Debug("Analyzing: %s", relFile)
+	startTime := time.Now()
+
+	release, err := limits.AcquireCPU(ctx)
+	if err != nil {
+		return nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		release()
+		logger.Error("Failed to read file %s: %v", relFile, err)
+		return nil
+	}
+	analysis, err := NewDartParser().Parse(content, file)
+	release()
+	if err != nil {
+		logger.Error("Failed to parse file %s: %v", relFile, err)
+		return nil
+	}
+
+	exportedCount := 0
+	for _, sym := range analysis.Symbols {
+		if sym.Exported {
+			exportedCount++
 		}
+	}
+	logger.Debug("  Parsed: %d symbols (%d exported)", len(analysis.Symbols), exportedCount)
 
-		parser := NewDartParser()
-		analysis, err := parser.Parse(content, file)
+	if err := a.client.OpenFile(file, string(content)); err != nil {
+		logger.Error("Failed to open file in LSP %s: %v", relFile, err)
+		return nil
+	}
+	defer a.client.CloseFile(file)
+
+	// Give LSP time to analyze the file
+	time.Sleep(50 * time.Millisecond)
+
+	var fileCalls []CallInfo
+	extract := func(name string, sym Symbol) {
+		release, err := limits.AcquireLSP(ctx)
 		if err != nil {
-			logger.Error("Failed to parse file %s: %v", relFile, err)
-			continue
+			return
 		}
-
-		symbolCount := len(analysis.Symbols)
-		exportedCount := 0
-		for _, sym := range analysis.Symbols {
-			if sym.Exported {
-				exportedCount++
-			}
+		defer release()
+		// Use ColStart for correct position
+		calls, err := a.client.ExtractCallsForSymbol(file, sym.LineStart-1, sym.ColStart)
+		if err != nil {
+			logger.Debug("  Failed to extract calls for %s: %v", name, err)
+			return
 		}
-		logger.Debug("  Parsed: %d symbols (%d exported)", symbolCount, exportedCount)
+		fileCalls = append(fileCalls, calls...)
+	}
 
-		if err := a.client.OpenFile(file, string(content)); err != nil {
-			logger.Error("Failed to open file in LSP %s: %v", relFile, err)
+	// Only analyze exported (public) symbols
+	for _, sym := range analysis.Symbols {
+		if !sym.Exported {
 			continue
 		}
-
-		// Give LSP time to analyze the file
-		time.Sleep(50 * time.Millisecond)
-
-		// Only analyze exported (public) symbols
-		fileCalls := 0
-		for _, sym := range analysis.Symbols {
-			if !sym.Exported {
-				continue
-			}
-
-			if sym.Kind == KindFunction || sym.Kind == KindClass {
-				// For classes, find constructors and public methods
-				if sym.Kind == KindClass {
-					for _, child := range sym.Children {
-						if child.Exported && (child.Kind == KindMethod || child.Kind == KindConstructor) {
-							// Use ColStart for correct position
-							calls, err := a.client.ExtractCallsForSymbol(file, child.LineStart-1, child.ColStart)
-							if err != nil {
-								logger.Debug("  Failed to extract calls for %s.%s: %v", sym.Name, child.Name, err)
-							} else {
-								fileCalls += len(calls)
-								allCalls = append(allCalls, calls...)
-							}
-						}
-					}
-				} else {
-					// Use ColStart for correct position
-					calls, err := a.client.ExtractCallsForSymbol(file, sym.LineStart-1, sym.ColStart)
-					if err != nil {
-						logger.Debug("  Failed to extract calls for %s: %v", sym.Name, err)
-					} else {
-						fileCalls += len(calls)
-						allCalls = append(allCalls, calls...)
-					}
+		switch sym.Kind {
+		case KindClass:
+			// For classes, find constructors and public methods
+			for _, child := range sym.Children {
+				if child.Exported && (child.Kind == KindMethod || child.Kind == KindConstructor) {
+					extract(sym.Name+"."+child.Name, child)
 				}
 			}
+		case KindFunction:
+			extract(sym.Name, sym)
 		}
-
-		a.client.CloseFile(file)
-		elapsed := time.Since(startTime).Round(time.Millisecond)
-		logger.Debug("  Found %d call relationships (took %v)", fileCalls, elapsed)
 	}
 
-	logger.Info("QuickCallScan complete: %d total call relationships", len(allCalls))
-	return allCalls, nil
+	elapsed := time.Since(startTime).Round(time.Millisecond)
+	logger.Debug("  Found %d call relationships (took %v)", len(fileCalls), elapsed)
+	return fileCalls
 }
@@ -25,6 +25,7 @@ type DartLSPClient struct {
 	requestID int64
 	responses map[int64]chan json.RawMessage
 	mu        sync.Mutex
+	writeMu   sync.Mutex // Keeps concurrent messages from interleaving on stdin
 	rootPath  string
 	ready     bool
 	ctx       context.Context
@@ -279,14 +280,7 @@ func (c *DartLSPClient) sendRequestWithContext(ctx context.Context, method strin
 	c.responses[id] = respChan
 	c.mu.Unlock()
 
-	// Send with Content-Length header
-	header := fmt.Sprintf("Content-Length: %d\r\n\r\n", len(body))
-	_, err = c.stdin.Write([]byte(header))
-	if err != nil {
-		return nil, err
-	}
-	_, err = c.stdin.Write(body)
-	if err != nil {
+	if err := c.write(body); err != nil {
 		return nil, err
 	}
 
@@ -314,12 +308,18 @@ func (c *DartLSPClient) sendNotification(method string, params interface{}) erro
 		return err
 	}
 
+	return c.write(body)
+}
+
+// write sends one message with its Content-Length header.
+func (c *DartLSPClient) write(body []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
 	header := fmt.Sprintf("Content-Length: %d\r\n\r\n", len(body))
-	_, err = c.stdin.Write([]byte(header))
-	if err != nil {
+	if _, err := c.stdin.Write([]byte(header)); err != nil {
 		return err
 	}
-	_, err = c.stdin.Write(body)
+	_, err := c.stdin.Write(body)
 	return err
 }
 
@@ -0,0 +1,256 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RelationshipProcessor adds the relationships a framework implies but the
+// source never spells out as a call or import, such as a router dispatching
+// to a decorated handler. Processors run on every file after parsing and
+// read what the parser already captured, mostly Symbol.Annotations.
+type RelationshipProcessor interface {
+	// Name identifies the rule set, e.g. "flask".
+	Name() string
+	// Languages lists the languages the rules apply to.
+	Languages() []Language
+	// Process returns the relationships to add to fa. content is the
+	// source fa was parsed from.
+	Process(fa *FileAnalysis, content []byte) []Relationship
+}
+
+// AddRelationshipProcessor registers a processor to run after parsing.
+// Processors run in the order they were added.
+func (r *ParserRegistry) AddRelationshipProcessor(p RelationshipProcessor) {
+	r.mu.Lock()
+	r.processors = append(r.processors, p)
+	r.mu.Unlock()
+}
+
+// applyProcessors appends the relationships the registered processors find
+// for lang, skipping any fa already has.
+func (r *ParserRegistry) applyProcessors(fa *FileAnalysis, lang Language, content []byte) {
+	if fa == nil || len(r.processors) == 0 {
+		return
+	}
+	type relKey struct {
+		source, target string
+		kind           RelationshipKind
+		line           int
+	}
+	seen := make(map[relKey]bool, len(fa.Relationships))
+	for _, rel := range fa.Relationships {
+		seen[relKey{rel.SourceSymbol, rel.TargetSymbol, rel.Kind, rel.Line}] = true
+	}
+	for _, p := range r.processors {
+		if !appliesTo(p, lang) {
+			continue
+		}
+		for _, rel := range p.Process(fa, content) {
+			key := relKey{rel.SourceSymbol, rel.TargetSymbol, rel.Kind, rel.Line}
+			if !seen[key] {
+				seen[key] = true
+				fa.Relationships = append(fa.Relationships, rel)
+			}
+		}
+	}
+}
+
+func appliesTo(p RelationshipProcessor, lang Language) bool {
+	for _, l := range p.Languages() {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// FlaskRoutes links a Flask app or blueprint to the view functions it
+// routes to: @app.route("/users") on list_users gives a RelRoute from "app"
+// to list_users. The Flask 2 shortcuts (@app.get, @bp.post, ...) count too.
+type FlaskRoutes struct{}
+
+// flaskRoute matches a route decorator as captured, without its "@".
+var flaskRoute = regexp.MustCompile(`^([A-Za-z_][\w.]*)\.(route|get|post|put|patch|delete)\(`)
+
+// Name implements RelationshipProcessor.
+func (FlaskRoutes) Name() string { return "flask" }
+
+// Languages implements RelationshipProcessor.
+func (FlaskRoutes) Languages() []Language { return []Language{LangPython} }
+
+// Process implements RelationshipProcessor.
+func (FlaskRoutes) Process(fa *FileAnalysis, _ []byte) []Relationship {
+	var rels []Relationship
+	walkSymbols(fa.Symbols, "", func(sym *Symbol, _ string) {
+		if sym.Kind != KindFunction && sym.Kind != KindMethod {
+			return
+		}
+		for _, a := range sym.Annotations {
+			if m := flaskRoute.FindStringSubmatch(a); m != nil {
+				rels = append(rels, Relationship{
+					SourceSymbol: m[1],
+					TargetFile:   fa.Path,
+					TargetSymbol: sym.Name,
+					Kind:         RelRoute,
+					Line:         sym.LineStart,
+				})
+			}
+		}
+	})
+	return rels
+}
+
+// SpringInjection links Spring beans to what they depend on and listen
+// for. A field, constructor, or setter marked @Autowired or @Inject gives a
+// RelInjects from the enclosing class to each injected type; an
+// @EventListener method gives a RelSubscribes from the method to its event
+// type.
+type SpringInjection struct{}
+
+// javaAnnotation matches an annotation and its arguments in Java source.
+var javaAnnotation = regexp.MustCompile(`@[\w.]+(\([^()]*\))?`)
+
+// Name implements RelationshipProcessor.
+func (SpringInjection) Name() string { return "spring" }
+
+// Languages implements RelationshipProcessor.
+func (SpringInjection) Languages() []Language { return []Language{LangJava} }
+
+// Process implements RelationshipProcessor.
+func (SpringInjection) Process(fa *FileAnalysis, content []byte) []Relationship {
+	lines := strings.Split(string(content), "\n")
+	var rels []Relationship
+	walkSymbols(fa.Symbols, "", func(sym *Symbol, owner string) {
+		switch {
+		case hasAnnotation(sym, "Autowired", "Inject"):
+			for _, typ := range injectedTypes(sym, symbolSource(lines, sym)) {
+				rels = append(rels, Relationship{
+					SourceSymbol: owner,
+					TargetSymbol: typ,
+					Kind:         RelInjects,
+					Line:         sym.LineStart,
+				})
+			}
+		case sym.Kind == KindMethod && hasAnnotation(sym, "EventListener"):
+			if params := ParseParameters(declaration(symbolSource(lines, sym), sym.Name), LangJava); len(params) > 0 {
+				rels = append(rels, Relationship{
+					SourceSymbol: sym.Name,
+					TargetSymbol: baseType(params[0].Type),
+					Kind:         RelSubscribes,
+					Line:         sym.LineStart,
+				})
+			}
+		}
+	})
+	return rels
+}
+
+// injectedTypes returns the types an injection point receives: a field's
+// type, or the parameter types of a constructor or setter.
+func injectedTypes(sym *Symbol, source string) []string {
+	if sym.Kind == KindProperty || sym.Kind == KindVariable {
+		decl := javaAnnotation.ReplaceAllString(source, "")
+		if i := strings.IndexAny(decl, "=;"); i >= 0 {
+			decl = decl[:i]
+		}
+		var fields []string
+		for _, f := range strings.Fields(collapseGenerics(decl)) {
+			if !declModifiers[f] && f != "transient" && f != "volatile" {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) < 2 {
+			return nil
+		}
+		return []string{baseType(fields[len(fields)-2])}
+	}
+	var types []string
+	for _, p := range ParseParameters(declaration(source, sym.Name), LangJava) {
+		if p.Type != "" {
+			types = append(types, baseType(p.Type))
+		}
+	}
+	return types
+}
+
+// walkSymbols calls fn for each symbol and its children, depth first, with
+// the name of the innermost enclosing class.
+func walkSymbols(symbols []Symbol, owner string, fn func(sym *Symbol, owner string)) {
+	for i := range symbols {
+		sym := &symbols[i]
+		fn(sym, owner)
+		childOwner := owner
+		if sym.Kind == KindClass || sym.Kind == KindInterface || sym.Kind == KindEnum {
+			childOwner = sym.Name
+		}
+		walkSymbols(sym.Children, childOwner, fn)
+	}
+}
+
+// hasAnnotation reports whether sym carries any of the named annotations,
+// with or without arguments or a package qualifier.
+func hasAnnotation(sym *Symbol, names ...string) bool {
+	for _, a := range sym.Annotations {
+		if i := strings.IndexByte(a, '('); i >= 0 {
+			a = a[:i]
+		}
+		a = a[strings.LastIndexByte(a, '.')+1:]
+		for _, n := range names {
+			if a == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// symbolSource returns the source lines sym spans, joined by spaces.
+func symbolSource(lines []string, sym *Symbol) string {
+	start, end := sym.LineStart-1, sym.LineEnd
+	if start < 0 || start >= len(lines) {
+		return ""
+	}
+	if end > len(lines) || end <= start {
+		end = start + 1
+	}
+	return strings.Join(lines[start:end], " ")
+}
+
+// declaration returns source from the callable's name on, so its first
+// parenthesized group is the parameter list rather than annotation
+// arguments.
+func declaration(source, name string) string {
+	if i := strings.Index(source, name+"("); i >= 0 {
+		return source[i:]
+	}
+	return source
+}
+
+// collapseGenerics removes the spaces inside type arguments, so
+// "Map<String, User> users" splits into a type and a name.
+func collapseGenerics(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		}
+		if depth > 0 && r == ' ' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// baseType strips type arguments and array brackets: "Repo<User>" is "Repo".
+func baseType(t string) string {
+	if i := strings.IndexAny(t, "<["); i >= 0 {
+		t = t[:i]
+	}
+	return strings.TrimSpace(t)
+}
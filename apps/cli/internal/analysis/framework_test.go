@@ -0,0 +1,84 @@
+package analysis
+
+import "testing"
+
+func TestFlaskRouteRelationship(t *testing.T) {
+	code := `from flask import Flask
+
+app = Flask(__name__)
+
+@app.route("/users", methods=["GET"])
+def list_users():
+    return []
+
+@bp.post("/users")
+def create_user():
+    return {}
+
+def helper():
+    pass
+`
+	fa, err := NewParserRegistry().Parse([]byte(code), "app.py")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	routes := make(map[string]Relationship)
+	for _, rel := range fa.Relationships {
+		if rel.Kind == RelRoute {
+			if _, dup := routes[rel.TargetSymbol]; dup {
+				t.Errorf("route to %s added twice", rel.TargetSymbol)
+			}
+			routes[rel.TargetSymbol] = rel
+		}
+	}
+	if len(routes) != 2 {
+		t.Fatalf("routes = %+v, want list_users and create_user", routes)
+	}
+	if rel := routes["list_users"]; rel.SourceSymbol != "app" || rel.TargetFile != "app.py" || rel.Line != 6 {
+		t.Errorf("list_users route = %+v, want from app in app.py at line 6", rel)
+	}
+	if rel := routes["create_user"]; rel.SourceSymbol != "bp" {
+		t.Errorf("create_user route = %+v, want from bp", rel)
+	}
+}
+
+func TestSpringInjectionRelationships(t *testing.T) {
+	code := `package com.example;
+
+@RestController
+public class UserController {
+    @Autowired
+    private UserService userService;
+
+    @Autowired
+    public UserController(AuditLog log, Repository<User> users) {}
+
+    @EventListener
+    public void onCreated(UserCreatedEvent event) {}
+}
+`
+	fa, err := NewParserRegistry().Parse([]byte(code), "UserController.java")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	injects := make(map[string]string)
+	subscribes := make(map[string]string)
+	for _, rel := range fa.Relationships {
+		switch rel.Kind {
+		case RelInjects:
+			injects[rel.TargetSymbol] = rel.SourceSymbol
+		case RelSubscribes:
+			subscribes[rel.TargetSymbol] = rel.SourceSymbol
+		}
+	}
+	for _, typ := range []string{"UserService", "AuditLog", "Repository"} {
+		if injects[typ] != "UserController" {
+			t.Errorf("injects %s from %q, want UserController (all: %v)", typ, injects[typ], injects)
+		}
+	}
+	if subscribes["UserCreatedEvent"] != "onCreated" {
+		t.Errorf("subscribes = %v, want onCreated on UserCreatedEvent", subscribes)
+	}
+}
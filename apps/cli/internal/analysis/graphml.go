@@ -0,0 +1,325 @@
+package analysis
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// graphMLHeader opens a GraphML document and declares every attribute key
+// its nodes and edges carry, as GraphML requires keys before the graph.
+const graphMLHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="name" for="node" attr.name="name" attr.type="string"/>
+  <key id="kind" for="node" attr.name="kind" attr.type="string"/>
+  <key id="file" for="node" attr.name="file" attr.type="string"/>
+  <key id="language" for="node" attr.name="language" attr.type="string"/>
+  <key id="line" for="node" attr.name="line" attr.type="int"/>
+  <key id="edge_kind" for="edge" attr.name="kind" attr.type="string"/>
+  <key id="confidence" for="edge" attr.name="confidence" attr.type="double">
+    <default>1</default>
+  </key>
+  <graph id="palace" edgedefault="directed">
+`
+
+const graphMLFooter = `  </graph>
+</graphml>
+`
+
+// relContains is the kind of the edges from a file to its symbols and from
+// a type to its members, which parsers do not report as relationships.
+const relContains RelationshipKind = "contains"
+
+// graphMaxTargets is the most symbols a relationship's target may match and
+// still get edges. A call of a common name such as Close matches a method of
+// half the types in a workspace, and an edge to each says nothing.
+const graphMaxTargets = 10
+
+// GraphMLExporter writes the code graph of a workspace as GraphML: a node
+// for each file and symbol, and an edge for each relationship between them.
+//
+// Nodes are written as files are added, so a large workspace is never held
+// in memory whole. Relationships name their targets rather than point at
+// them, so they are kept, without their files, until Close, which resolves
+// them against every symbol seen and writes the edges. A target matching n
+// symbols gets an edge to each, with a confidence of 1/n; targets matching
+// nothing in the workspace, such as library calls, or more symbols than
+// graphMaxTargets get no edge.
+type GraphMLExporter struct {
+	w     *bufio.Writer
+	err   error
+	nodes int
+	edges int
+
+	byName   map[string][]graphNode // Symbol nodes by bare name
+	bySuffix map[string][]graphNode // File nodes by trailing path segments, with and without extension
+	byDir    map[string][]graphNode // File nodes by directory
+	pending  []graphEdge
+}
+
+// graphNode is a node an edge may point at.
+type graphNode struct {
+	id   int
+	file string
+}
+
+// graphEdge is a relationship waiting for its target to be resolved.
+type graphEdge struct {
+	source int
+	file   string // The source's file, whose symbols are preferred as targets
+	target string
+	kind   RelationshipKind
+}
+
+// NewGraphMLExporter starts a GraphML document on w. Add files to it, then
+// call Close to write the edges and end the document.
+func NewGraphMLExporter(w io.Writer) *GraphMLExporter {
+	e := &GraphMLExporter{
+		w:        bufio.NewWriter(w),
+		byName:   make(map[string][]graphNode),
+		bySuffix: make(map[string][]graphNode),
+		byDir:    make(map[string][]graphNode),
+	}
+	_, e.err = e.w.WriteString(graphMLHeader)
+	return e
+}
+
+// Nodes returns the number of nodes written so far.
+func (e *GraphMLExporter) Nodes() int { return e.nodes }
+
+// Edges returns the number of edges written so far.
+func (e *GraphMLExporter) Edges() int { return e.edges }
+
+// Add writes the nodes of fa, a node for the file and one for each of its
+// symbols and their members, with the edges from the file to its symbols
+// and from each symbol to its members. Findings, subtests, and config keys
+// are left out, as they are not declarations.
+func (e *GraphMLExporter) Add(fa *FileAnalysis) error {
+	if fa == nil || e.err != nil {
+		return e.err
+	}
+	file := graphNode{id: e.node(path.Base(fa.Path), "file", fa.Path, fa.Language, 0), file: fa.Path}
+	suffixes := []string{fa.Path}
+	if noExt := strings.TrimSuffix(fa.Path, path.Ext(fa.Path)); noExt != fa.Path {
+		suffixes = append(suffixes, noExt)
+	}
+	for _, p := range suffixes {
+		for {
+			e.bySuffix[p] = append(e.bySuffix[p], file)
+			i := strings.Index(p, "/")
+			if i < 0 {
+				break
+			}
+			p = p[i+1:]
+		}
+	}
+	dir := path.Dir(fa.Path)
+	e.byDir[dir] = append(e.byDir[dir], file)
+
+	var local []graphSpan
+	e.addSymbols(fa, file.id, fa.Symbols, &local)
+
+	for _, rel := range fa.Relationships {
+		if rel.Kind == RelImport {
+			if rel.ImportKind == ImportStdlib || rel.ImportKind == ImportThirdParty || rel.TargetFile == "" {
+				continue
+			}
+			e.pending = append(e.pending, graphEdge{source: file.id, file: fa.Path, target: rel.TargetFile, kind: rel.Kind})
+			continue
+		}
+		target := rel.TargetSymbol
+		if target == "" {
+			continue
+		}
+		source := file.id
+		if rel.SourceSymbol != "" {
+			name := bareName(rel.SourceSymbol)
+			for _, span := range local {
+				if span.name == name {
+					source = span.id
+					break
+				}
+			}
+		} else if span := innermostSpan(local, rel.Line); span != nil {
+			source = span.id
+		}
+		e.pending = append(e.pending, graphEdge{source: source, file: fa.Path, target: target, kind: rel.Kind})
+	}
+	return e.err
+}
+
+// graphSpan is the lines of a symbol node, to find which symbol a
+// relationship comes from.
+type graphSpan struct {
+	id         int
+	name       string
+	start, end int
+}
+
+func (e *GraphMLExporter) addSymbols(fa *FileAnalysis, parent int, symbols []Symbol, local *[]graphSpan) {
+	for _, sym := range symbols {
+		if IsSyntheticKind(sym.Kind) {
+			continue
+		}
+		id := e.node(sym.Name, string(sym.Kind), fa.Path, fa.Language, sym.LineStart)
+		e.edge(parent, id, relContains, 1)
+		name := bareName(sym.Name)
+		e.byName[name] = append(e.byName[name], graphNode{id: id, file: fa.Path})
+		*local = append(*local, graphSpan{id: id, name: name, start: sym.LineStart, end: sym.LineEnd})
+		e.addSymbols(fa, id, sym.Children, local)
+	}
+}
+
+// innermostSpan returns the narrowest symbol enclosing line, or nil.
+func innermostSpan(spans []graphSpan, line int) *graphSpan {
+	var best *graphSpan
+	for i := range spans {
+		s := &spans[i]
+		if line < s.start || line > s.end {
+			continue
+		}
+		if best == nil || s.end-s.start < best.end-best.start {
+			best = s
+		}
+	}
+	return best
+}
+
+// bareName strips the qualifier from a name such as "pkg.Func",
+// "Type::method", or "obj->field".
+func bareName(name string) string {
+	name = strings.TrimSuffix(name, "()")
+	if i := strings.LastIndexAny(name, ".:>"); i >= 0 && i < len(name)-1 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Close writes the edges of the relationships added, ends the document, and
+// flushes it to the writer. It does not close the writer.
+func (e *GraphMLExporter) Close() error {
+	for _, p := range e.pending {
+		var targets []graphNode
+		if p.kind == RelImport {
+			targets = e.importTargets(p.file, p.target)
+		} else {
+			targets = e.symbolTargets(p.file, p.target)
+		}
+		if len(targets) > graphMaxTargets {
+			continue
+		}
+		for _, t := range targets {
+			e.edge(p.source, t.id, p.kind, 1/float64(len(targets)))
+		}
+	}
+	e.pending = nil
+	if e.err == nil {
+		_, e.err = e.w.WriteString(graphMLFooter)
+	}
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+	return e.err
+}
+
+// symbolTargets returns the symbols target may name, those of the source's
+// own file if it declares any.
+func (e *GraphMLExporter) symbolTargets(file, target string) []graphNode {
+	candidates := e.byName[bareName(target)]
+	var same []graphNode
+	for _, c := range candidates {
+		if c.file == file {
+			same = append(same, c)
+		}
+	}
+	if len(same) > 0 {
+		return same
+	}
+	return candidates
+}
+
+// importTargets returns the files an import of target from file may load:
+// files whose path ends in the target, with or without an extension, or else
+// the files of the deepest directory the target ends in, for languages that
+// import packages, such as Go.
+func (e *GraphMLExporter) importTargets(file, target string) []graphNode {
+	t := strings.Trim(target, `"'<>`)
+	if strings.HasPrefix(t, ".") {
+		t = path.Join(path.Dir(file), t)
+	} else if !strings.Contains(t, "/") {
+		// Dotted and scoped module names, such as Python's pkg.mod or
+		// Rust's crate::mod, name paths too
+		t = strings.ReplaceAll(strings.ReplaceAll(t, "::", "/"), ".", "/")
+	}
+	t = strings.TrimPrefix(t, "/")
+	if files := e.bySuffix[t]; len(files) > 0 {
+		return files
+	}
+	for p := t; p != ""; {
+		if files := e.byDir[p]; len(files) > 0 {
+			return files
+		}
+		i := strings.Index(p, "/")
+		if i < 0 {
+			break
+		}
+		p = p[i+1:]
+	}
+	return nil
+}
+
+// node writes a node and returns its ID.
+func (e *GraphMLExporter) node(name, kind, file, lang string, line int) int {
+	id := e.nodes
+	e.nodes++
+	if e.err != nil {
+		return id
+	}
+	fmt.Fprintf(e.w, "    <node id=\"n%d\">\n", id)
+	e.data("name", name)
+	e.data("kind", kind)
+	e.data("file", file)
+	e.data("language", lang)
+	if line > 0 {
+		e.data("line", strconv.Itoa(line))
+	}
+	_, e.err = e.w.WriteString("    </node>\n")
+	return id
+}
+
+// edge writes an edge between two nodes.
+func (e *GraphMLExporter) edge(source, target int, kind RelationshipKind, confidence float64) {
+	id := e.edges
+	e.edges++
+	if e.err != nil {
+		return
+	}
+	fmt.Fprintf(e.w, "    <edge id=\"e%d\" source=\"n%d\" target=\"n%d\">\n", id, source, target)
+	e.data("edge_kind", string(kind))
+	e.data("confidence", strconv.FormatFloat(confidence, 'g', 4, 64))
+	_, e.err = e.w.WriteString("    </edge>\n")
+}
+
+func (e *GraphMLExporter) data(key, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(e.w, "      <data key=%q>", key)
+	xml.EscapeText(e.w, []byte(value))
+	e.w.WriteString("</data>\n")
+}
+
+// ExportGraphML writes the code graph of files as a GraphML document.
+func ExportGraphML(w io.Writer, files ...*FileAnalysis) error {
+	e := NewGraphMLExporter(w)
+	for _, fa := range files {
+		if err := e.Add(fa); err != nil {
+			return err
+		}
+	}
+	return e.Close()
+}
@@ -0,0 +1,173 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// graphMLDoc is the part of a GraphML document the tests read.
+type graphMLDoc struct {
+	Keys []struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+	} `xml:"key"`
+	Graph struct {
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Nodes       []struct {
+			ID   string        `xml:"id,attr"`
+			Data []graphMLData `xml:"data"`
+		} `xml:"node"`
+		Edges []struct {
+			Source string        `xml:"source,attr"`
+			Target string        `xml:"target,attr"`
+			Data   []graphMLData `xml:"data"`
+		} `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func graphMLValue(data []graphMLData, key string) string {
+	for _, d := range data {
+		if d.Key == key {
+			return d.Value
+		}
+	}
+	return ""
+}
+
+func TestExportGraphML(t *testing.T) {
+	store := &FileAnalysis{
+		Path:     "store/store.go",
+		Language: "go",
+		Symbols: []Symbol{
+			{Name: "Store", Kind: KindClass, LineStart: 3, LineEnd: 5, Children: []Symbol{
+				{Name: "Get", Kind: KindMethod, LineStart: 4, LineEnd: 4},
+			}},
+			{Name: "AWS_SECRET", Kind: KindFinding, LineStart: 7, LineEnd: 7},
+		},
+	}
+	server := &FileAnalysis{
+		Path:     "cmd/server.go",
+		Language: "go",
+		Symbols: []Symbol{
+			{Name: "serve", Kind: KindFunction, LineStart: 5, LineEnd: 9},
+		},
+		Relationships: []Relationship{
+			{TargetFile: "example.com/app/store", Kind: RelImport, Line: 3, ImportKind: ImportLocal},
+			{TargetFile: "fmt", Kind: RelImport, Line: 4, ImportKind: ImportStdlib},
+			{TargetSymbol: "s.Get", Kind: RelCall, Line: 7},
+			{TargetSymbol: "fmt.Println", Kind: RelCall, Line: 8},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportGraphML(&buf, store, server); err != nil {
+		t.Fatalf("ExportGraphML: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Errorf("expected an XML declaration, got %q", out[:min(len(out), 60)])
+	}
+
+	var doc graphMLDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, out)
+	}
+
+	keys := make(map[string]string)
+	for _, k := range doc.Keys {
+		keys[k.For+"."+k.Name] = k.ID
+	}
+	for _, want := range []string{"node.name", "node.kind", "node.file", "node.language", "edge.kind", "edge.confidence"} {
+		if keys[want] == "" {
+			t.Errorf("key %s not declared in %+v", want, doc.Keys)
+		}
+	}
+	if doc.Graph.EdgeDefault != "directed" {
+		t.Errorf("edgedefault = %q, want directed", doc.Graph.EdgeDefault)
+	}
+
+	// Two files, Store, Get, and serve; the finding is no node
+	ids := make(map[string]string)
+	for _, n := range doc.Graph.Nodes {
+		ids[graphMLValue(n.Data, keys["node.name"])] = n.ID
+	}
+	if len(doc.Graph.Nodes) != 5 {
+		t.Errorf("expected 5 nodes, got %d: %v", len(doc.Graph.Nodes), ids)
+	}
+	for _, n := range doc.Graph.Nodes {
+		if graphMLValue(n.Data, keys["node.name"]) == "Get" {
+			if kind := graphMLValue(n.Data, keys["node.kind"]); kind != "method" {
+				t.Errorf("Get has kind %q, want method", kind)
+			}
+			if file := graphMLValue(n.Data, keys["node.file"]); file != "store/store.go" {
+				t.Errorf("Get has file %q, want store/store.go", file)
+			}
+			if lang := graphMLValue(n.Data, keys["node.language"]); lang != "go" {
+				t.Errorf("Get has language %q, want go", lang)
+			}
+		}
+	}
+
+	edges := make(map[string]string)
+	for _, e := range doc.Graph.Edges {
+		edges[e.Source+">"+e.Target] = graphMLValue(e.Data, keys["edge.kind"]) + " " + graphMLValue(e.Data, keys["edge.confidence"])
+	}
+	tests := []struct {
+		source, target, want string
+	}{
+		{"store.go", "Store", "contains 1"},
+		{"Store", "Get", "contains 1"},
+		{"server.go", "store.go", "import 1"}, // Go imports resolve to the package's files
+		{"serve", "Get", "call 1"},            // From the function enclosing the call
+	}
+	for _, tt := range tests {
+		if got := edges[ids[tt.source]+">"+ids[tt.target]]; got != tt.want {
+			t.Errorf("edge %s -> %s = %q, want %q", tt.source, tt.target, got, tt.want)
+		}
+	}
+	// Standard library imports and calls into it get no edge
+	if len(doc.Graph.Edges) != len(tests)+1 {
+		t.Errorf("expected %d edges, got %v", len(tests)+1, edges)
+	}
+}
+
+func TestGraphMLConfidence(t *testing.T) {
+	a := &FileAnalysis{Path: "a.py", Language: "python", Symbols: []Symbol{{Name: "run", Kind: KindFunction, LineStart: 1, LineEnd: 2}}}
+	b := &FileAnalysis{Path: "b.py", Language: "python", Symbols: []Symbol{{Name: "run", Kind: KindFunction, LineStart: 1, LineEnd: 2}}}
+	c := &FileAnalysis{Path: "c.py", Language: "python", Relationships: []Relationship{
+		{TargetSymbol: "run", Kind: RelCall, Line: 3},
+		{TargetFile: "a", Kind: RelImport, Line: 1},
+	}}
+
+	var buf bytes.Buffer
+	if err := ExportGraphML(&buf, a, b, c); err != nil {
+		t.Fatalf("ExportGraphML: %v", err)
+	}
+	var doc graphMLDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	calls, imports := 0, 0
+	for _, e := range doc.Graph.Edges {
+		switch graphMLValue(e.Data, "edge_kind") {
+		case "call":
+			calls++
+			if conf := graphMLValue(e.Data, "confidence"); conf != "0.5" {
+				t.Errorf("call to one of two run functions has confidence %s, want 0.5", conf)
+			}
+		case "import":
+			imports++
+		}
+	}
+	if calls != 2 || imports != 1 {
+		t.Errorf("expected 2 call edges and 1 import edge, got %d and %d", calls, imports)
+	}
+}
@@ -0,0 +1,392 @@
+package analysis
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ImportKind classifies where an imported module comes from.
+type ImportKind string
+
+// Import kinds.
+const (
+	ImportLocal      ImportKind = "local"      // Part of the project itself
+	ImportStdlib     ImportKind = "stdlib"     // The language's standard library
+	ImportThirdParty ImportKind = "thirdparty" // An external dependency
+)
+
+// ImportResolver classifies import relationships against a project.
+// The zero value knows nothing about the project, so only relative imports
+// are recognized as local.
+type ImportResolver struct {
+	// Roots holds the names of the project's top-level files and directories,
+	// without extensions, so that project-rooted imports such as
+	// "myapp.utils" or "src/lib" are recognized as local.
+	Roots map[string]bool
+	// Modules holds module paths the project declares (from its go.mod files
+	// and go.work), under which Go imports are local.
+	Modules []string
+	// GoModules holds the Go modules of the project, deepest first, so a
+	// file is resolved against the nearest one.
+	GoModules []GoModule
+}
+
+// GoModule is a Go module of the project.
+type GoModule struct {
+	Dir      string   // Slash-separated directory relative to the project root; "" for the root
+	Path     string   // Module path
+	Replaced []string // Module paths its go.mod replaces with local directories
+}
+
+// NewImportResolver builds a resolver for the project rooted at root. It
+// finds every go.mod below root, and the modules a go.work at root uses.
+func NewImportResolver(root string) ImportResolver {
+	r := ImportResolver{Roots: make(map[string]bool)}
+	// Packages under a src/ layout are imported by their own names
+	for _, dir := range []string{root, filepath.Join(root, "src")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			r.Roots[strings.TrimSuffix(name, filepath.Ext(name))] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	addModule := func(dir string) {
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			return
+		}
+		seen[dir] = true
+		mod, ok := readGoMod(filepath.Join(dir, "go.mod"))
+		if !ok {
+			return
+		}
+		if rel, err := filepath.Rel(root, dir); err == nil && rel != "." {
+			mod.Dir = filepath.ToSlash(rel)
+		}
+		r.GoModules = append(r.GoModules, mod)
+		r.Modules = append(r.Modules, mod.Path)
+	}
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == "go.mod" {
+			addModule(filepath.Dir(path))
+		}
+		return nil
+	})
+	for _, dir := range readGoWorkUses(filepath.Join(root, "go.work")) {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+		addModule(dir)
+	}
+	sort.SliceStable(r.GoModules, func(i, j int) bool {
+		return strings.Count(r.GoModules[i].Dir, "/") > strings.Count(r.GoModules[j].Dir, "/")
+	})
+	return r
+}
+
+// readGoMod reads the module path and local replacements of a go.mod.
+func readGoMod(path string) (GoModule, bool) {
+	var mod GoModule
+	f, err := os.Open(path)
+	if err != nil {
+		return mod, false
+	}
+	defer f.Close()
+	inReplace := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "module" && len(fields) == 2:
+			mod.Path = strings.Trim(fields[1], `"`)
+		case fields[0] == "replace" && len(fields) == 2 && fields[1] == "(":
+			inReplace = true
+		case inReplace && fields[0] == ")":
+			inReplace = false
+		case fields[0] == "replace" || inReplace:
+			if fields[0] == "replace" {
+				fields = fields[1:]
+			}
+			if from, to, ok := parseReplace(fields); ok && isLocalPath(to) {
+				mod.Replaced = append(mod.Replaced, from)
+			}
+		}
+	}
+	return mod, mod.Path != ""
+}
+
+// parseReplace splits the fields of a replace directive, "old [v] => new [v]",
+// into its old module path and its replacement.
+func parseReplace(fields []string) (from, to string, ok bool) {
+	for i, f := range fields {
+		if f == "=>" && i > 0 && i+1 < len(fields) {
+			return strings.Trim(fields[0], `"`), strings.Trim(fields[i+1], `"`), true
+		}
+	}
+	return "", "", false
+}
+
+// isLocalPath reports whether a replacement names a directory rather than a
+// module.
+func isLocalPath(p string) bool {
+	return strings.HasPrefix(p, "./") || strings.HasPrefix(p, "../") || filepath.IsAbs(p)
+}
+
+// readGoWorkUses returns the module directories a go.work uses.
+func readGoWorkUses(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var dirs []string
+	inUse := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "use" && len(fields) == 2 && fields[1] == "(":
+			inUse = true
+		case inUse && fields[0] == ")":
+			inUse = false
+		case fields[0] == "use" && len(fields) == 2:
+			dirs = append(dirs, strings.Trim(fields[1], `"`))
+		case inUse:
+			dirs = append(dirs, strings.Trim(fields[0], `"`))
+		}
+	}
+	return dirs
+}
+
+// goModuleFor returns the nearest module enclosing the file at the
+// slash-separated path relative to the project root, or nil.
+func (r ImportResolver) goModuleFor(path string) *GoModule {
+	for i := range r.GoModules {
+		m := &r.GoModules[i]
+		if m.Dir == "" || strings.HasPrefix(path, m.Dir+"/") {
+			return m
+		}
+	}
+	return nil
+}
+
+// ClassifyFile sets ImportKind on every import relationship in fa.
+func (r ImportResolver) ClassifyFile(fa *FileAnalysis) {
+	if fa == nil {
+		return
+	}
+	lang := Language(fa.Language)
+	var mod *GoModule
+	if lang == LangGo {
+		mod = r.goModuleFor(filepath.ToSlash(fa.Path))
+	}
+	for i := range fa.Relationships {
+		rel := &fa.Relationships[i]
+		if rel.Kind != RelImport {
+			continue
+		}
+		rel.ImportKind = r.Classify(lang, rel.TargetFile)
+		// A dependency the file's own module replaces with a local
+		// directory is part of the project
+		if mod != nil && rel.ImportKind == ImportThirdParty && underModule(rel.TargetFile, mod.Replaced) {
+			rel.ImportKind = ImportLocal
+		}
+	}
+}
+
+// underModule reports whether an import path lies in one of the modules.
+func underModule(target string, modules []string) bool {
+	for _, mod := range modules {
+		if target == mod || strings.HasPrefix(target, mod+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify decides whether an import target in the given language is local,
+// standard library, or third party. Relative and project-rooted imports are
+// local, imports found in the language's standard library set are stdlib,
+// and everything else is assumed to be a third-party dependency.
+func (r ImportResolver) Classify(lang Language, target string) ImportKind {
+	if target == "" {
+		return ""
+	}
+	if strings.HasPrefix(target, ".") || strings.HasPrefix(target, "/") {
+		return ImportLocal
+	}
+
+	switch lang {
+	case LangGo:
+		if underModule(target, r.Modules) {
+			return ImportLocal
+		}
+		// Standard library paths have no domain in their first element
+		if !strings.Contains(strings.SplitN(target, "/", 2)[0], ".") {
+			return ImportStdlib
+		}
+		return ImportThirdParty
+
+	case LangPython:
+		top := strings.SplitN(target, ".", 2)[0]
+		if r.Roots[top] {
+			return ImportLocal
+		}
+		if pythonStdlib[top] {
+			return ImportStdlib
+		}
+
+	case LangJavaScript, LangTypeScript:
+		if strings.HasPrefix(target, "node:") || nodeBuiltins[strings.SplitN(target, "/", 2)[0]] {
+			return ImportStdlib
+		}
+		// Common path aliases for the project's source root
+		if strings.HasPrefix(target, "@/") || strings.HasPrefix(target, "~/") {
+			return ImportLocal
+		}
+		if !strings.HasPrefix(target, "@") && r.Roots[strings.SplitN(target, "/", 2)[0]] {
+			return ImportLocal
+		}
+
+	case LangRust:
+		switch strings.SplitN(target, "::", 2)[0] {
+		case "crate", "self", "super":
+			return ImportLocal
+		case "std", "core", "alloc", "proc_macro":
+			return ImportStdlib
+		}
+
+	case LangJava, LangKotlin, LangScala, LangGroovy:
+		for _, prefix := range []string{"java.", "javax.", "kotlin.", "kotlinx.", "scala."} {
+			if strings.HasPrefix(target, prefix) {
+				return ImportStdlib
+			}
+		}
+
+	case LangC, LangCPP, LangObjectiveC:
+		if cStdHeaders[target] {
+			return ImportStdlib
+		}
+		if r.Roots[strings.SplitN(target, "/", 2)[0]] || r.Roots[strings.TrimSuffix(target, filepath.Ext(target))] {
+			return ImportLocal
+		}
+		// A bare, non-standard header is most likely one of the project's own
+		if !strings.Contains(target, "/") && filepath.Ext(target) != "" {
+			return ImportLocal
+		}
+		return ImportThirdParty
+
+	case LangNim:
+		if strings.HasPrefix(target, "std/") || strings.HasPrefix(target, "system/") || nimStdlib[target] {
+			return ImportStdlib
+		}
+		if strings.HasPrefix(target, "pkg/") {
+			return ImportThirdParty
+		}
+	}
+
+	if r.Roots[strings.SplitN(target, "/", 2)[0]] {
+		return ImportLocal
+	}
+	return ImportThirdParty
+}
+
+// pythonStdlib lists the top-level modules of the Python standard library.
+var pythonStdlib = toSet(
+	"__future__", "abc", "argparse", "array", "ast", "asyncio", "atexit", "base64", "bdb",
+	"binascii", "bisect", "builtins", "bz2", "calendar", "cmath", "cmd", "code", "codecs",
+	"collections", "colorsys", "compileall", "concurrent", "configparser", "contextlib",
+	"contextvars", "copy", "copyreg", "cProfile", "csv", "ctypes", "curses", "dataclasses",
+	"datetime", "dbm", "decimal", "difflib", "dis", "doctest", "email", "encodings", "enum",
+	"errno", "faulthandler", "fcntl", "filecmp", "fileinput", "fnmatch", "fractions",
+	"ftplib", "functools", "gc", "getopt", "getpass", "gettext", "glob", "graphlib", "grp",
+	"gzip", "hashlib", "heapq", "hmac", "html", "http", "imaplib", "importlib", "inspect",
+	"io", "ipaddress", "itertools", "json", "keyword", "linecache", "locale", "logging",
+	"lzma", "mailbox", "marshal", "math", "mimetypes", "mmap", "multiprocessing", "netrc",
+	"numbers", "operator", "optparse", "os", "pathlib", "pdb", "pickle", "pkgutil",
+	"platform", "plistlib", "poplib", "posix", "pprint", "profile", "pstats", "pty", "pwd",
+	"queue", "quopri", "random", "re", "readline", "reprlib", "resource", "rlcompleter",
+	"runpy", "sched", "secrets", "select", "selectors", "shelve", "shlex", "shutil",
+	"signal", "site", "smtplib", "socket", "socketserver", "sqlite3", "ssl", "stat",
+	"statistics", "string", "stringprep", "struct", "subprocess", "symtable", "sys",
+	"sysconfig", "syslog", "tabnanny", "tarfile", "tempfile", "termios", "textwrap",
+	"threading", "time", "timeit", "tkinter", "token", "tokenize", "tomllib", "trace",
+	"traceback", "tracemalloc", "tty", "turtle", "types", "typing", "unicodedata",
+	"unittest", "urllib", "uuid", "venv", "warnings", "wave", "weakref", "webbrowser",
+	"winreg", "wsgiref", "xml", "xmlrpc", "zipapp", "zipfile", "zipimport", "zlib", "zoneinfo",
+)
+
+// nodeBuiltins lists Node.js built-in modules importable without "node:".
+var nodeBuiltins = toSet(
+	"assert", "async_hooks", "buffer", "child_process", "cluster", "console", "constants",
+	"crypto", "dgram", "diagnostics_channel", "dns", "domain", "events", "fs", "http",
+	"http2", "https", "inspector", "module", "net", "os", "path", "perf_hooks", "process",
+	"punycode", "querystring", "readline", "repl", "stream", "string_decoder", "timers",
+	"tls", "trace_events", "tty", "url", "util", "v8", "vm", "wasi", "worker_threads", "zlib",
+)
+
+// cStdHeaders lists C and C++ standard library headers.
+var cStdHeaders = toSet(
+	"assert.h", "complex.h", "ctype.h", "errno.h", "fenv.h", "float.h", "inttypes.h",
+	"iso646.h", "limits.h", "locale.h", "math.h", "setjmp.h", "signal.h", "stdalign.h",
+	"stdarg.h", "stdatomic.h", "stdbool.h", "stddef.h", "stdint.h", "stdio.h", "stdlib.h",
+	"stdnoreturn.h", "string.h", "tgmath.h", "threads.h", "time.h", "uchar.h", "wchar.h",
+	"wctype.h",
+	"algorithm", "any", "array", "atomic", "bitset", "cassert", "cctype", "cerrno",
+	"chrono", "cmath", "condition_variable", "cstddef", "cstdint", "cstdio", "cstdlib",
+	"cstring", "ctime", "deque", "exception", "filesystem", "fstream", "functional",
+	"future", "initializer_list", "iomanip", "ios", "iosfwd", "iostream", "istream",
+	"iterator", "limits", "list", "map", "memory", "mutex", "new", "numeric", "optional",
+	"ostream", "queue", "random", "ranges", "regex", "set", "shared_mutex", "span",
+	"sstream", "stack", "stdexcept", "streambuf", "string", "string_view", "system_error",
+	"thread", "tuple", "type_traits", "typeinfo", "unordered_map", "unordered_set",
+	"utility", "variant", "vector",
+)
+
+// nimStdlib lists commonly imported Nim standard library modules, which may
+// be imported without their "std/" prefix.
+var nimStdlib = toSet(
+	"algorithm", "asyncdispatch", "asyncnet", "base64", "json", "macros", "math", "options",
+	"os", "osproc", "parseopt", "parseutils", "random", "re", "sequtils", "sets", "streams",
+	"strformat", "strutils", "tables", "terminal", "times", "typetraits", "unicode", "unittest",
+	"uri",
+)
+
+func toSet(items ...string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImportResolverPython(t *testing.T) {
+	src := "import os\nimport requests\nfrom myapp.models import User\nfrom .utils import helper\n"
+	fa, err := Analyze([]byte(src), "myapp/views.py")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	r := ImportResolver{Roots: map[string]bool{"myapp": true}}
+	r.ClassifyFile(fa)
+
+	want := map[string]ImportKind{
+		"os":           ImportStdlib,
+		"requests":     ImportThirdParty,
+		"myapp.models": ImportLocal,
+		".utils":       ImportLocal,
+	}
+	got := make(map[string]ImportKind)
+	for _, rel := range fa.Relationships {
+		if rel.Kind == RelImport {
+			got[rel.TargetFile] = rel.ImportKind
+		}
+	}
+	for target, kind := range want {
+		if got[target] != kind {
+			t.Errorf("import %q classified as %q, want %q (all: %v)", target, got[target], kind, got)
+		}
+	}
+}
+
+func TestImportResolverClassify(t *testing.T) {
+	r := ImportResolver{Modules: []string{"github.com/acme/app"}, Roots: map[string]bool{"lib": true}}
+	tests := []struct {
+		lang   Language
+		target string
+		want   ImportKind
+	}{
+		{LangGo, "fmt", ImportStdlib},
+		{LangGo, "net/http", ImportStdlib},
+		{LangGo, "github.com/acme/app/internal/db", ImportLocal},
+		{LangGo, "github.com/spf13/cobra", ImportThirdParty},
+		{LangTypeScript, "node:fs", ImportStdlib},
+		{LangTypeScript, "path", ImportStdlib},
+		{LangTypeScript, "./component", ImportLocal},
+		{LangTypeScript, "lib/util", ImportLocal},
+		{LangTypeScript, "@angular/core", ImportThirdParty},
+		{LangRust, "std::collections::HashMap", ImportStdlib},
+		{LangRust, "crate::config", ImportLocal},
+		{LangRust, "serde::Deserialize", ImportThirdParty},
+		{LangJava, "java.util.List", ImportStdlib},
+		{LangJava, "org.junit.Test", ImportThirdParty},
+		{LangC, "stdio.h", ImportStdlib},
+		{LangC, "parser.h", ImportLocal},
+		{LangCPP, "boost/asio.hpp", ImportThirdParty},
+		{LangNim, "std/strutils", ImportStdlib},
+		{LangNim, "tables", ImportStdlib},
+		{LangNim, "jester", ImportThirdParty},
+	}
+	for _, tt := range tests {
+		if got := r.Classify(tt.lang, tt.target); got != tt.want {
+			t.Errorf("Classify(%s, %q) = %q, want %q", tt.lang, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestNewImportResolverGoModules(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.work", "go 1.22\n\nuse (\n\t./apps/api\n\t../shared // outside the root\n)\n")
+	write("apps/api/go.mod", "module example.com/api\n\nrequire example.com/vendored v1.0.0\n\nreplace example.com/vendored => ./third_party/vendored\n")
+	write("tools/go.mod", "module example.com/tools\n\nreplace (\n\texample.com/fork v1.2.0 => ../forks/fork\n\texample.com/pinned => example.com/pinned v1.3.0\n)\n")
+	write("../shared/go.mod", "module example.com/shared\n")
+
+	r := NewImportResolver(root)
+	for _, mod := range []string{"example.com/api", "example.com/tools", "example.com/shared"} {
+		if !underModule(mod, r.Modules) {
+			t.Errorf("module %s not found, got %v", mod, r.Modules)
+		}
+	}
+
+	classify := func(path, target string) ImportKind {
+		fa := &FileAnalysis{Path: path, Language: string(LangGo), Relationships: []Relationship{{Kind: RelImport, TargetFile: target}}}
+		r.ClassifyFile(fa)
+		return fa.Relationships[0].ImportKind
+	}
+	tests := []struct {
+		path, target string
+		want         ImportKind
+	}{
+		{"apps/api/main.go", "example.com/tools/lint", ImportLocal},
+		{"apps/api/main.go", "example.com/shared/log", ImportLocal},
+		{"apps/api/main.go", "example.com/vendored/pkg", ImportLocal},
+		{"tools/lint/lint.go", "example.com/vendored/pkg", ImportThirdParty},
+		{"tools/lint/lint.go", "example.com/fork", ImportLocal},
+		{"tools/lint/lint.go", "example.com/pinned", ImportThirdParty},
+		{"apps/api/main.go", "fmt", ImportStdlib},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.path, tt.target); got != tt.want {
+			t.Errorf("import of %q from %s classified as %q, want %q", tt.target, tt.path, got, tt.want)
+		}
+	}
+}
@@ -38,6 +38,9 @@ var extensionToLanguage = map[string]Language{
 	".hpp": LangCPP,
 	".hxx": LangCPP,
 	".hh":  LangCPP,
+	// Objective-C (.m is shared with MATLAB; see DetectLanguageDetailed)
+	".m":  LangObjectiveC,
+	".mm": LangObjectiveC,
 	// C#
 	".cs": LangCSharp,
 	// Ruby
@@ -101,6 +104,9 @@ var extensionToLanguage = map[string]Language{
 	".elm": LangElm,
 	// CUE
 	".cue": LangCUE,
+	// Nim
+	".nim":  LangNim,
+	".nims": LangNim,
 }
 
 // filenameToLanguage maps specific filenames (without extensions) to languages
@@ -118,6 +124,8 @@ var filenameToLanguage = map[string]Language{
 }
 
 // DetectLanguage returns the programming language of a file based on its extension or filename.
+// Extensions several languages share map to the most common one; use
+// DetectLanguageDetailed to decide from the content.
 func DetectLanguage(filePath string) Language {
 	// First check extension
 	ext := strings.ToLower(filepath.Ext(filePath))
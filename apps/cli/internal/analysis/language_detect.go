@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LanguageDetection is the outcome of DetectLanguageDetailed.
+type LanguageDetection struct {
+	Language Language
+	// Confidence is 1 when the extension or filename names one language,
+	// 0.5 for a shared extension whose content gave no clue, and in between
+	// as the content favors one candidate over the others.
+	Confidence float64
+	// Ambiguous is set when the extension is shared by several languages.
+	Ambiguous bool
+	// Alternatives lists the other candidates, most likely first.
+	Alternatives []Language
+	// Evidence names the content signals that decided an ambiguous file.
+	Evidence []string
+}
+
+// languageSignal is a content pattern that points to one language.
+type languageSignal struct {
+	lang    Language
+	name    string
+	pattern *regexp.Regexp
+}
+
+// ambiguousExtension is an extension several languages use, with the
+// candidates in order of preference when the content gives no clue and the
+// signals that tell them apart.
+type ambiguousExtension struct {
+	candidates []Language
+	signals    []languageSignal
+}
+
+var ambiguousExtensions = map[string]ambiguousExtension{
+	".h": {
+		candidates: []Language{LangC, LangCPP, LangObjectiveC},
+		signals: []languageSignal{
+			{LangCPP, "class declaration", regexp.MustCompile(`(?m)^\s*(template\s*<[^>]*>\s*)?class\s+\w+\s*(final\s*)?[:{]`)},
+			{LangCPP, "template", regexp.MustCompile(`(?m)^\s*template\s*<`)},
+			{LangCPP, "namespace", regexp.MustCompile(`(?m)^\s*namespace\s+\w*\s*\{`)},
+			{LangCPP, "access specifier", regexp.MustCompile(`(?m)^\s*(public|private|protected)\s*:`)},
+			{LangCPP, "std:: qualifier", regexp.MustCompile(`\bstd::`)},
+			{LangCPP, "C++ standard header", regexp.MustCompile(`(?m)^\s*#\s*include\s*<(iostream|string|vector|map|memory|unordered_map|algorithm|functional|utility|optional)>`)},
+			{LangObjectiveC, "#import", regexp.MustCompile(`(?m)^\s*#\s*import\b`)},
+			{LangObjectiveC, "@interface", regexp.MustCompile(`(?m)^\s*@(interface|protocol)\b`)},
+			{LangObjectiveC, "@property", regexp.MustCompile(`(?m)^\s*@property\b`)},
+			{LangObjectiveC, "@end", regexp.MustCompile(`(?m)^\s*@end\b`)},
+			{LangC, "typedef struct", regexp.MustCompile(`(?m)^\s*typedef\s+struct\b`)},
+		},
+	},
+	".m": {
+		candidates: []Language{LangObjectiveC, LangMATLAB},
+		signals: []languageSignal{
+			{LangObjectiveC, "#import", regexp.MustCompile(`(?m)^\s*#\s*(import|include)\b`)},
+			{LangObjectiveC, "@implementation", regexp.MustCompile(`(?m)^\s*@(implementation|interface|protocol)\b`)},
+			{LangObjectiveC, "@end", regexp.MustCompile(`(?m)^\s*@end\b`)},
+			{LangObjectiveC, "message send", regexp.MustCompile(`\[\w+\s+(\w+:|(alloc|new|init)\])`)},
+			{LangMATLAB, "function definition", regexp.MustCompile(`(?m)^\s*function\s+(\[[^\]]*\]\s*=\s*|\w+\s*=\s*)?\w+`)},
+			{LangMATLAB, "% comment", regexp.MustCompile(`(?m)^\s*%`)},
+			{LangMATLAB, "end keyword", regexp.MustCompile(`(?m)^\s*end\s*$`)},
+			{LangMATLAB, "disp or fprintf call", regexp.MustCompile(`\b(disp|fprintf)\s*\(`)},
+		},
+	},
+}
+
+// detectionSampleSize is how much of a file the content signals look at.
+const detectionSampleSize = 64 * 1024
+
+// DetectLanguageDetailed returns the language of a file as DetectLanguage
+// does, but decides an ambiguous extension (.h for C, C++, or Objective-C;
+// .m for Objective-C or MATLAB) from the content: each candidate scores a
+// point for every distinct signal found, such as a template for C++ or
+// @interface for Objective-C, and the highest score wins. Ties go to the
+// candidate listed first.
+func DetectLanguageDetailed(filePath string, content []byte) LanguageDetection {
+	amb, ok := ambiguousExtensions[strings.ToLower(filepath.Ext(filePath))]
+	if !ok {
+		lang := DetectLanguage(filePath)
+		if lang == LangUnknown {
+			return LanguageDetection{Language: LangUnknown}
+		}
+		return LanguageDetection{Language: lang, Confidence: 1}
+	}
+
+	if len(content) > detectionSampleSize {
+		content = content[:detectionSampleSize]
+	}
+	scores := make(map[Language]int, len(amb.candidates))
+	evidence := make(map[Language][]string, len(amb.candidates))
+	total := 0
+	for _, s := range amb.signals {
+		if s.pattern.Match(content) {
+			scores[s.lang]++
+			evidence[s.lang] = append(evidence[s.lang], s.name)
+			total++
+		}
+	}
+
+	ranked := append([]Language(nil), amb.candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+	d := LanguageDetection{
+		Language:     ranked[0],
+		Confidence:   0.5,
+		Ambiguous:    true,
+		Alternatives: ranked[1:],
+		Evidence:     evidence[ranked[0]],
+	}
+	if total > 0 {
+		d.Confidence = 0.5 + 0.5*float64(scores[ranked[0]])/float64(total)
+	}
+	return d
+}
@@ -0,0 +1,114 @@
+package analysis
+
+import "testing"
+
+func TestDetectLanguageDetailed(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		want     Language
+		evidence string
+	}{
+		{
+			name: "C++ header with a class template",
+			path: "include/stack.h",
+			content: `#pragma once
+#include <vector>
+
+namespace util {
+template <typename T>
+class Stack {
+public:
+    void push(const T& v) { items_.push_back(v); }
+private:
+    std::vector<T> items_;
+};
+}
+`,
+			want:     LangCPP,
+			evidence: "template",
+		},
+		{
+			name: "C header",
+			path: "include/list.h",
+			content: `#ifndef LIST_H
+#define LIST_H
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+typedef struct list {
+    struct list *next;
+    void *value;
+} list_t;
+
+list_t *list_push(list_t *head, void *value);
+
+#ifdef __cplusplus
+}
+#endif
+#endif
+`,
+			want:     LangC,
+			evidence: "typedef struct",
+		},
+		{
+			name: "Objective-C header",
+			path: "Sources/Player.h",
+			content: `#import <Foundation/Foundation.h>
+
+@interface Player : NSObject
+@property (nonatomic, copy) NSString *name;
+@end
+`,
+			want:     LangObjectiveC,
+			evidence: "@interface",
+		},
+		{
+			name: "MATLAB script",
+			path: "scripts/fit.m",
+			content: `% Fit a line to the samples
+function [a, b] = fit(x, y)
+    p = polyfit(x, y, 1);
+    a = p(1); b = p(2);
+    disp([a b]);
+end
+`,
+			want:     LangMATLAB,
+			evidence: "function definition",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := DetectLanguageDetailed(tt.path, []byte(tt.content))
+			if d.Language != tt.want {
+				t.Fatalf("Language = %s, want %s (evidence %v, alternatives %v)", d.Language, tt.want, d.Evidence, d.Alternatives)
+			}
+			if !d.Ambiguous || len(d.Alternatives) == 0 {
+				t.Errorf("Ambiguous = %v, Alternatives = %v; want an ambiguous result with alternatives", d.Ambiguous, d.Alternatives)
+			}
+			if d.Confidence <= 0.5 || d.Confidence > 1 {
+				t.Errorf("Confidence = %v, want above 0.5 with content evidence", d.Confidence)
+			}
+			found := false
+			for _, e := range d.Evidence {
+				found = found || e == tt.evidence
+			}
+			if !found {
+				t.Errorf("Evidence = %v, want %q", d.Evidence, tt.evidence)
+			}
+		})
+	}
+
+	// A bare header falls back to C with no evidence.
+	if d := DetectLanguageDetailed("empty.h", nil); d.Language != LangC || d.Confidence != 0.5 {
+		t.Errorf("empty header = %+v, want C at 0.5 confidence", d)
+	}
+	// Unambiguous extensions are certain regardless of content.
+	if d := DetectLanguageDetailed("main.go", []byte("template <typename T>")); d.Language != LangGo || d.Confidence != 1 || d.Ambiguous {
+		t.Errorf("main.go = %+v, want Go at full confidence", d)
+	}
+}
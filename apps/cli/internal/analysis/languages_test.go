@@ -1,6 +1,8 @@
 package analysis
 
 import (
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -382,6 +384,202 @@ impl Point {
 	})
 }
 
+func TestRustParserCrate(t *testing.T) {
+	code := `use std::collections::{HashMap, hash_map::Entry};
+use std::fmt::{self, Display as Show};
+use crate::store::*;
+
+/// Largest number of entries kept.
+pub const MAX_ENTRIES: usize = 1024;
+static mut HITS: u32 = 0;
+
+/// A bounded cache.
+/// Evicts the oldest entry.
+///
+/// # Examples
+/// let c = Cache::new();
+#[derive(Debug, Clone)]
+pub struct Cache<K, V> where K: Eq {
+    /// The stored entries.
+    pub entries: HashMap<K, V>,
+    capacity: usize,
+}
+
+impl<K: Eq, V> Cache<K, V> {
+    /// Creates an empty cache.
+    pub fn new() -> Self {
+        Cache { entries: HashMap::new(), capacity: MAX_ENTRIES }
+    }
+
+    fn evict(&mut self) {
+        self.entries.clear();
+    }
+}
+
+impl<K, V> fmt::Display for Cache<K, V> {
+    fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+        write!(f, "cache")
+    }
+}
+
+impl Show for Remote {
+    fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+        Ok(())
+    }
+}
+
+pub enum Status {
+    Active,
+    Failed(String),
+}
+
+pub trait Store: Send + Sync {
+    const NAME: &'static str;
+    type Key;
+    fn get(&self, key: &Self::Key) -> Option<String>;
+}
+
+mod helpers {
+    pub fn normalize(s: &str) -> String {
+        s.trim().to_string()
+    }
+}
+
+pub async fn run<T>(x: T) -> Result<(), Error>
+where
+    T: Into<String>,
+{
+    fn inner() {}
+    let s = helpers::normalize(&x.into());
+    validate(&s);
+    Ok(())
+}
+`
+	result, err := NewRustParser().Parse([]byte(code), "src/cache.rs")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name      string
+		kind      SymbolKind
+		parent    string
+		signature string
+		doc       string
+		exported  bool
+	}{
+		{"MAX_ENTRIES", KindConstant, "", "pub const MAX_ENTRIES: usize", "Largest number of entries kept.", true},
+		{"HITS", KindConstant, "", "static mut HITS: u32", "", false},
+		{"Cache", KindClass, "", "pub struct Cache<K, V> where K: Eq", "A bounded cache. Evicts the oldest entry.", true},
+		{"entries", KindProperty, "Cache", "pub entries: HashMap<K, V>", "The stored entries.", true},
+		{"capacity", KindProperty, "Cache", "capacity: usize", "", false},
+		{"new", KindMethod, "Cache", "pub fn new() -> Self", "Creates an empty cache.", true},
+		{"evict", KindMethod, "Cache", "fn evict(&mut self)", "", false},
+		{"fmt", KindMethod, "Cache", "fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result", "", true},
+		{"Remote", KindClass, "", "", "", true},
+		{"fmt", KindMethod, "Remote", "fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result", "", true},
+		{"Status", KindEnum, "", "pub enum Status", "", true},
+		{"Store", KindInterface, "", "pub trait Store: Send + Sync", "", true},
+		{"NAME", KindConstant, "Store", "const NAME: &'static str", "", true},
+		{"Key", KindType, "Store", "type Key", "", true},
+		{"get", KindMethod, "Store", "fn get(&self, key: &Self::Key) -> Option<String>", "", true},
+		{"normalize", KindFunction, "", "pub fn normalize(s: &str) -> String", "", true},
+		{"run", KindFunction, "", "pub async fn run<T>(x: T) -> Result<(), Error> where T: Into<String>", "", true},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.parent+" "+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			var sym *Symbol
+			for i := range in {
+				if in[i].Name == tt.name {
+					sym = &in[i]
+				}
+			}
+			if sym == nil {
+				t.Fatalf("%s not found under %q", tt.name, tt.parent)
+			}
+			if sym.Kind != tt.kind {
+				t.Errorf("%s.Kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+			}
+			if sym.Signature != tt.signature {
+				t.Errorf("%s.Signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+			}
+			if sym.DocComment != tt.doc {
+				t.Errorf("%s.DocComment = %q, want %q", tt.name, sym.DocComment, tt.doc)
+			}
+			if sym.Exported != tt.exported {
+				t.Errorf("%s.Exported = %v, want %v", tt.name, sym.Exported, tt.exported)
+			}
+		})
+	}
+	// The two items, the four types, normalize, and run; impl methods and
+	// the nested inner function are not listed at the top level
+	if len(result.Symbols) != 8 {
+		t.Errorf("expected 8 top-level symbols, got %+v", result.Symbols)
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		source string
+		target string
+	}{
+		{RelImport, "", "std::collections::HashMap"},
+		{RelImport, "", "std::collections::hash_map::Entry"},
+		{RelImport, "", "std::fmt"},
+		{RelImport, "", "std::fmt::Display"},
+		{RelImport, "", "crate::store"},
+		{RelImplements, "Cache", "fmt::Display"},
+		{RelImplements, "Remote", "Show"},
+		{RelExtends, "Store", "Send"},
+		{RelExtends, "Store", "Sync"},
+		{RelCall, "", "HashMap::new"},
+		{RelCall, "", "helpers::normalize"},
+		{RelCall, "", "validate"},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				target := rel.TargetSymbol
+				if rel.Kind == RelImport && rel.TargetFile != "" {
+					target = rel.TargetFile
+				}
+				if rel.Kind == tt.kind && rel.SourceSymbol == tt.source && target == tt.target {
+					return
+				}
+			}
+			t.Errorf("no %s relationship from %q to %q in %+v", tt.kind, tt.source, tt.target, result.Relationships)
+		})
+	}
+}
+
+func TestRustUsePaths(t *testing.T) {
+	code := `use std::{io::{self, Read}, fmt::*};
+use crate::store::{*};
+use self::cache::Entry;
+`
+	result, err := NewRustParser().Parse([]byte(code), "lib.rs")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var got []string
+	for _, rel := range result.Relationships {
+		if rel.Kind == RelImport {
+			got = append(got, rel.TargetFile)
+		}
+	}
+	want := []string{"std::io", "std::io::Read", "std::fmt", "crate::store", "self::cache::Entry"}
+	if !slices.Equal(got, want) {
+		t.Errorf("imports = %q, want %q", got, want)
+	}
+}
+
 // TestJavaParser tests Java parsing
 func TestJavaParser(t *testing.T) {
 	parser := NewJavaParser()
@@ -450,6 +648,90 @@ func TestJavaParser(t *testing.T) {
 	})
 }
 
+func TestJavaParserNestedTypesAndCalls(t *testing.T) {
+	code := `package com.acme;
+
+/**
+ * A user account.
+ */
+public class User extends Entity {
+    /** Max logins. */
+    public static final int MAX = 3;
+
+    public User(String name) {}
+
+    public String greet(User other) {
+        helper(other);
+        return other.getName();
+    }
+
+    static class Builder {
+        Builder withName(String n) { return this; }
+    }
+
+    enum Role { ADMIN, USER }
+}
+
+record Point(int x, int y) {}
+`
+	result, err := NewJavaParser().Parse([]byte(code), "User.java")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name, parent string
+		kind         SymbolKind
+		signature    string
+		doc          string
+	}{
+		{"User", "", KindClass, "", "A user account."},
+		{"MAX", "User", KindConstant, "", "Max logins."},
+		{"User", "User", KindConstructor, "User(String name)", ""},
+		{"greet", "User", KindMethod, "String greet(User other)", ""},
+		{"Builder", "User", KindClass, "", ""},
+		{"withName", "Builder", KindMethod, "Builder withName(String n)", ""},
+		{"Role", "User", KindEnum, "", ""},
+		{"ADMIN", "Role", KindConstant, "", ""},
+		{"Point", "", KindClass, "record Point(int x, int y)", ""},
+		{"x", "Point", KindProperty, "", ""},
+	}
+	for _, tt := range symbols {
+		t.Run(tt.parent+"/"+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			for _, sym := range in {
+				if sym.Name == tt.name && sym.Kind == tt.kind {
+					if sym.Signature != tt.signature || sym.DocComment != tt.doc {
+						t.Errorf("%s: got signature %q and doc %q, want %q and %q", tt.name, sym.Signature, sym.DocComment, tt.signature, tt.doc)
+					}
+					return
+				}
+			}
+			t.Errorf("%s %s not found under %q", tt.kind, tt.name, tt.parent)
+		})
+	}
+	if len(result.Symbols) != 2 {
+		t.Errorf("expected only User and Point at the top level, got %d symbols", len(result.Symbols))
+	}
+
+	calls := map[string]int{}
+	for _, rel := range result.Relationships {
+		if rel.Kind == RelCall {
+			calls[rel.TargetSymbol] = rel.Line
+		}
+	}
+	if calls["helper"] != 13 || calls["other.getName"] != 14 {
+		t.Errorf("expected calls to helper on line 13 and other.getName on line 14, got %v", calls)
+	}
+}
+
 // TestJavaScriptParser tests JavaScript parsing
 func TestJavaScriptParser(t *testing.T) {
 	parser := NewJavaScriptParser()
@@ -544,6 +826,163 @@ void destroy_point(Point* p) {
 	})
 }
 
+func TestCParserFirmware(t *testing.T) {
+	code := `#include <stdint.h>
+#include "hal/gpio.h"
+
+#define MAX(a, b) ((a) > (b) ? (a) : (b))
+#define REG(addr) (*(volatile uint32_t *)(addr))
+
+/**
+ * A ring buffer.
+ * Not thread safe.
+ */
+typedef struct ring {
+    uint8_t *data;
+    size_t head, tail;
+} ring_t;
+
+typedef struct {
+    int x;
+    int y;
+} point_t;
+
+union value {
+    int i;
+    float f;
+};
+
+enum state { IDLE, RUNNING = 2 };
+
+typedef unsigned long tick_t;
+
+static int counter = 0, total;
+const char *version = "1.2";
+void (*on_tick)(tick_t now);
+
+// Duplicates a string.
+// The caller frees it.
+extern char *str_dup(const char *s);
+
+#ifdef DEBUG
+void debug_log(const char *fmt, ...);
+#endif
+
+static inline int add(int a, int b) {
+    int sum = MAX(a, b);
+    struct ring local;
+    return sum + gpio_read(a);
+}
+
+int main(void) {
+    ring_t *r = ring_new(16);
+    r->push(r, 1);
+    return add(1, 2);
+}
+`
+	result, err := NewCParser().Parse([]byte(code), "src/ring.c")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name      string
+		kind      SymbolKind
+		parent    string
+		signature string
+		doc       string
+		exported  bool
+	}{
+		{"ring", KindClass, "", "struct ring", "A ring buffer. Not thread safe.", true},
+		{"data", KindProperty, "ring", "uint8_t *data", "", true},
+		{"tail", KindProperty, "ring", "size_t tail", "", true},
+		{"ring_t", KindTypeAlias, "", "typedef struct ring ring_t", "", true},
+		{"point_t", KindClass, "", "typedef struct point_t", "", true},
+		{"y", KindProperty, "point_t", "int y", "", true},
+		{"value", KindClass, "", "union value", "", true},
+		{"state", KindEnum, "", "enum state", "", true},
+		{"RUNNING", KindConstant, "state", "", "", true},
+		{"tick_t", KindTypeAlias, "", "typedef unsigned long tick_t", "", true},
+		{"counter", KindVariable, "", "static int counter", "", false},
+		{"total", KindVariable, "", "static int total", "", false},
+		{"version", KindVariable, "", "const char *version", "", true},
+		{"on_tick", KindVariable, "", "void (*on_tick)(tick_t now)", "", true},
+		{"str_dup", KindFunction, "", "extern char *str_dup(const char *s)", "Duplicates a string. The caller frees it.", true},
+		{"debug_log", KindFunction, "", "void debug_log(const char *fmt, ...)", "", true},
+		{"add", KindFunction, "", "static inline int add(int a, int b)", "", false},
+		{"main", KindFunction, "", "int main(void)", "", true},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			var sym *Symbol
+			for i := range in {
+				if in[i].Name == tt.name {
+					sym = &in[i]
+				}
+			}
+			if sym == nil {
+				t.Fatalf("%s not found under %q", tt.name, tt.parent)
+			}
+			if sym.Kind != tt.kind {
+				t.Errorf("%s.Kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+			}
+			if sym.Signature != tt.signature {
+				t.Errorf("%s.Signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+			}
+			if sym.DocComment != tt.doc {
+				t.Errorf("%s.DocComment = %q, want %q", tt.name, sym.DocComment, tt.doc)
+			}
+			if sym.Exported != tt.exported {
+				t.Errorf("%s.Exported = %v, want %v", tt.name, sym.Exported, tt.exported)
+			}
+		})
+	}
+	// Macros are not functions, and locals and struct references in
+	// function bodies are not symbols
+	for _, name := range []string{"MAX", "REG", "sum", "local", "r"} {
+		if findSymbol(result.Symbols, name) != nil {
+			t.Errorf("unexpected symbol %s", name)
+		}
+	}
+	if len(result.Symbols) != 14 {
+		t.Errorf("expected 14 top-level symbols, got %+v", result.Symbols)
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		target string
+	}{
+		{RelImport, "stdint.h"},
+		{RelImport, "hal/gpio.h"},
+		{RelCall, "gpio_read"},
+		{RelCall, "ring_new"},
+		{RelCall, "r.push"},
+		{RelCall, "add"},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				target := rel.TargetSymbol
+				if rel.Kind == RelImport {
+					target = rel.TargetFile
+				}
+				if rel.Kind == tt.kind && target == tt.target {
+					return
+				}
+			}
+			t.Errorf("no %s relationship to %q in %+v", tt.kind, tt.target, result.Relationships)
+		})
+	}
+}
+
 // TestBashParser tests Bash script parsing
 func TestBashParser(t *testing.T) {
 	parser := NewBashParser()
@@ -577,6 +1016,124 @@ main "$@"
 	})
 }
 
+func TestBashParserScript(t *testing.T) {
+	code := `#!/usr/bin/env bash
+# Deploys the service.
+
+set -euo pipefail
+source ./lib/common.sh
+. "$HOME/.deployrc"
+
+# Where builds are kept.
+BUILD_DIR=/tmp/build
+readonly MAX_RETRIES=3
+export REGION=eu-west-1
+declare -r VERSION=1.2
+BUILD_DIR=/var/build
+
+# Builds one target.
+# Arguments: the target name.
+function build-target {
+  local target=$1
+  counter=0
+  LANG=C make "$target"
+}
+
+function cleanup() {
+  rm -rf "$BUILD_DIR"
+}
+
+# Not about deploy.
+
+deploy() {
+  # Nested helpers are global once deploy runs
+  notify-team() { echo "deployed"; }
+  build-target api
+  notify-team
+}
+
+if [ -n "${CI:-}" ]; then
+  CI_MODE=1
+fi
+
+trap cleanup EXIT
+deploy | tee deploy.log
+`
+	result, err := NewBashParser().Parse([]byte(code), "scripts/deploy.sh")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name      string
+		kind      SymbolKind
+		signature string
+		doc       string
+	}{
+		{"BUILD_DIR", KindVariable, "BUILD_DIR=/tmp/build", "Where builds are kept."},
+		{"MAX_RETRIES", KindConstant, "MAX_RETRIES=3", ""},
+		{"REGION", KindVariable, "REGION=eu-west-1", ""},
+		{"VERSION", KindConstant, "VERSION=1.2", ""},
+		{"build-target", KindFunction, "function build-target", "Builds one target. Arguments: the target name."},
+		{"cleanup", KindFunction, "function cleanup()", ""},
+		{"deploy", KindFunction, "deploy()", ""},
+		{"notify-team", KindFunction, "notify-team()", "Nested helpers are global once deploy runs"},
+		{"CI_MODE", KindVariable, "CI_MODE=1", ""},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.name, func(t *testing.T) {
+			sym := findSymbol(result.Symbols, tt.name)
+			if sym == nil {
+				t.Fatalf("%s not found in %+v", tt.name, result.Symbols)
+			}
+			if sym.Kind != tt.kind {
+				t.Errorf("%s.Kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+			}
+			if sym.Signature != tt.signature {
+				t.Errorf("%s.Signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+			}
+			if sym.DocComment != tt.doc {
+				t.Errorf("%s.DocComment = %q, want %q", tt.name, sym.DocComment, tt.doc)
+			}
+		})
+	}
+	// BUILD_DIR is listed once; local, function-level, and command-prefix
+	// assignments (target, counter, LANG) are not globals
+	if len(result.Symbols) != len(symbols) {
+		t.Errorf("expected %d symbols, got %+v", len(symbols), result.Symbols)
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		target string
+		line   int
+	}{
+		{RelImport, "./lib/common.sh", 5},
+		{RelImport, "$HOME/.deployrc", 6},
+		{RelCall, "build-target", 32},
+		{RelCall, "notify-team", 33},
+		{RelCall, "deploy", 41},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				if rel.Kind == tt.kind && (rel.TargetFile == tt.target || rel.TargetSymbol == tt.target) {
+					if rel.Line != tt.line {
+						t.Errorf("%s at line %d, want %d", tt.target, rel.Line, tt.line)
+					}
+					return
+				}
+			}
+			t.Errorf("no %s relationship to %q in %+v", tt.kind, tt.target, result.Relationships)
+		})
+	}
+	// Programs such as make and rm are not calls of the script's functions,
+	// nor is cleanup, passed to trap as an argument
+	if len(result.Relationships) != len(relationships) {
+		t.Errorf("expected %d relationships, got %+v", len(relationships), result.Relationships)
+	}
+}
+
 // TestDockerfileParser tests Dockerfile parsing
 func TestDockerfileParser(t *testing.T) {
 	parser := NewDockerfileParser()
@@ -924,6 +1481,135 @@ struct Point {
 	})
 }
 
+// TestSwiftParserIOS tests the declarations of an iOS source file: types,
+// extensions, protocols, computed properties, attributes, and doc comments.
+func TestSwiftParserIOS(t *testing.T) {
+	code := `import UIKit
+
+/// Shows a user's profile.
+/// Reloads when the user changes.
+@objc
+@available(iOS 15.0, *)
+public class ProfileViewController: UIViewController, UITableViewDelegate {
+    /// The user shown.
+    var user: User?
+    var title: String { get { user?.name ?? "" } set { } }
+    var isEmpty: Bool { user == nil }
+    init(user: User) { self.user = user }
+    @objc func reload(animated: Bool) -> Bool { return true }
+}
+
+struct Point: Equatable { let x: Int }
+
+enum Direction { case north, south }
+
+/**
+ * Loads users.
+ * - Parameter id: the user's ID
+ */
+protocol UserLoading: AnyObject {
+    var isLoading: Bool { get }
+    func load(id: Int) async throws -> User
+}
+
+extension User: Codable {
+    func greet() -> String { "hi" }
+}
+
+let maxCount = 10
+var current: Int { maxCount }
+`
+	result, err := NewSwiftParser().Parse([]byte(code), "App/ProfileViewController.swift")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name      string
+		kind      SymbolKind
+		parent    string
+		signature string
+		doc       string
+	}{
+		{"ProfileViewController", KindClass, "", "public class ProfileViewController: UIViewController, UITableViewDelegate", "Shows a user's profile. Reloads when the user changes."},
+		{"user", KindProperty, "ProfileViewController", "var user: User?", "The user shown."},
+		{"title", KindProperty, "ProfileViewController", "var title: String { get set }", ""},
+		{"isEmpty", KindProperty, "ProfileViewController", "var isEmpty: Bool { get }", ""},
+		{"init", KindConstructor, "ProfileViewController", "init(user: User)", ""},
+		{"reload", KindMethod, "ProfileViewController", "func reload(animated: Bool) -> Bool", ""},
+		{"Point", KindClass, "", "struct Point: Equatable", ""},
+		{"Direction", KindEnum, "", "enum Direction", ""},
+		{"south", KindConstant, "Direction", "", ""},
+		{"UserLoading", KindInterface, "", "protocol UserLoading: AnyObject", "Loads users."},
+		{"isLoading", KindProperty, "UserLoading", "var isLoading: Bool { get }", ""},
+		{"load", KindMethod, "UserLoading", "func load(id: Int) async throws -> User", ""},
+		{"User", KindClass, "", "extension User: Codable", ""},
+		{"greet", KindMethod, "User", "func greet() -> String", ""},
+		{"maxCount", KindConstant, "", "let maxCount", ""},
+		{"current", KindVariable, "", "var current: Int { get }", ""},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			var sym *Symbol
+			for i := range in {
+				if in[i].Name == tt.name {
+					sym = &in[i]
+				}
+			}
+			if sym == nil {
+				t.Fatalf("%s not found under %q", tt.name, tt.parent)
+			}
+			if sym.Kind != tt.kind {
+				t.Errorf("%s.Kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+			}
+			if sym.Signature != tt.signature {
+				t.Errorf("%s.Signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+			}
+			if sym.DocComment != tt.doc {
+				t.Errorf("%s.DocComment = %q, want %q", tt.name, sym.DocComment, tt.doc)
+			}
+		})
+	}
+	if len(result.Symbols) != 7 {
+		t.Errorf("expected 7 top-level symbols without members, got %+v", result.Symbols)
+	}
+	for _, sym := range result.Symbols {
+		if strings.Contains(sym.Signature, "@") {
+			t.Errorf("%s.Signature %q keeps its attributes", sym.Name, sym.Signature)
+		}
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		source string
+		target string
+	}{
+		{RelExtends, "ProfileViewController", "UIViewController"},
+		{RelExtends, "ProfileViewController", "UITableViewDelegate"},
+		{RelImplements, "Point", "Equatable"},
+		{RelExtends, "UserLoading", "AnyObject"},
+		{RelImplements, "User", "Codable"},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				if rel.Kind == tt.kind && rel.SourceSymbol == tt.source && rel.TargetSymbol == tt.target {
+					return
+				}
+			}
+			t.Errorf("no %s relationship from %q to %q in %+v", tt.kind, tt.source, tt.target, result.Relationships)
+		})
+	}
+}
+
 // TestRubyParser tests Ruby parsing
 func TestRubyParser(t *testing.T) {
 	parser := NewRubyParser()
@@ -977,6 +1663,106 @@ end
 	})
 }
 
+func TestRubyParserRails(t *testing.T) {
+	code := `require "json"
+
+module Admin
+  # A signed-in user.
+  class User < ApplicationRecord
+    include Comparable
+    extend Forwardable
+    attr_accessor :name, :email
+    MAX_LOGINS = 10
+
+    def greet(other)
+      puts format_name(other)
+      other.save!
+    end
+
+    def self.build
+      new
+    end
+  end
+end
+`
+	result, err := NewRubyParser().Parse([]byte(code), "app/models/admin/user.rb")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name   string
+		kind   SymbolKind
+		parent string
+	}{
+		{"Admin", KindInterface, ""},
+		{"User", KindClass, "Admin"},
+		{"name", KindProperty, "User"},
+		{"email", KindProperty, "User"},
+		{"MAX_LOGINS", KindConstant, "User"},
+		{"greet", KindMethod, "User"},
+		{"build", KindFunction, "User"},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			var sym *Symbol
+			for i := range in {
+				if in[i].Name == tt.name {
+					sym = &in[i]
+				}
+			}
+			if sym == nil {
+				t.Fatalf("%s not found under %q", tt.name, tt.parent)
+			}
+			if sym.Kind != tt.kind {
+				t.Errorf("%s.Kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+			}
+		})
+	}
+	if len(result.Symbols) != 1 {
+		t.Errorf("expected only Admin at the top level, got %d symbols", len(result.Symbols))
+	}
+	if user := findSymbol(result.Symbols, "User"); user == nil || user.DocComment != "A signed-in user." {
+		t.Errorf("expected User's doc comment, got %+v", user)
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		target string
+		line   int
+	}{
+		{RelImport, "Comparable", 6},
+		{RelImport, "Forwardable", 7},
+		{RelExtends, "ApplicationRecord", 5},
+		{RelCall, "puts", 12},
+		{RelCall, "format_name", 12},
+		{RelCall, "other.save!", 13},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				if rel.Kind == tt.kind && rel.TargetSymbol == tt.target && rel.Line == tt.line {
+					return
+				}
+			}
+			t.Errorf("no %s relationship to %s on line %d in %+v", tt.kind, tt.target, tt.line, result.Relationships)
+		})
+	}
+	for _, rel := range result.Relationships {
+		if rel.Kind == RelCall && (rel.TargetSymbol == "attr_accessor" || rel.TargetSymbol == "include") {
+			t.Errorf("declaration recorded as a call: %+v", rel)
+		}
+	}
+}
+
 // TestPHPParser tests PHP parsing
 func TestPHPParser(t *testing.T) {
 	parser := NewPHPParser()
@@ -1039,6 +1825,175 @@ class User implements Loggable {
 	})
 }
 
+func TestPHPParserLaravel(t *testing.T) {
+	code := `<?php
+
+declare(strict_types=1);
+
+namespace App\Http\Controllers;
+
+use App\Models\User;
+use Illuminate\Http\{Request, Response as Resp};
+
+const VERSION = '1.0';
+
+/**
+ * Handles users.
+ * Requires authentication.
+ *
+ * @package App
+ */
+#[Route('/users')]
+final class UserController extends Controller implements HasMiddleware, \JsonSerializable
+{
+    use Authorizes;
+
+    public const MAX = 10;
+
+    /** The table. */
+    protected string $table = 'users';
+
+    public function __construct(private readonly UserRepo $repo) {}
+
+    /**
+     * Shows a user.
+     *
+     * @param int $id
+     */
+    public static function show(int $id, ?string $name = null): ?User
+    {
+        $user = User::find($id);
+        $this->repo->save($user);
+        helper($id);
+        return $user;
+    }
+}
+
+trait Authorizes
+{
+    public function authorize(): bool { return true; }
+}
+
+enum Status: string implements HasLabel
+{
+    case Active = 'active';
+
+    public function label(): string { return ucfirst($this->value); }
+}
+
+interface HasLabel extends \Stringable
+{
+    public function label(): string;
+}
+
+if (!function_exists('format_name')) {
+    function format_name(string $first, string $last = ''): string
+    {
+        return trim("$first $last");
+    }
+}
+`
+	result, err := NewPHPParser().Parse([]byte(code), "app/Http/Controllers/UserController.php")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name      string
+		kind      SymbolKind
+		parent    string
+		signature string
+		doc       string
+		exported  bool
+	}{
+		{"VERSION", KindConstant, "", "const VERSION = '1.0'", "", true},
+		{"UserController", KindClass, "", `final class UserController extends Controller implements HasMiddleware, \JsonSerializable`, "Handles users. Requires authentication.", true},
+		{"MAX", KindConstant, "UserController", "public const MAX = 10", "", true},
+		{"table", KindProperty, "UserController", "protected string $table", "The table.", false},
+		{"repo", KindProperty, "UserController", "private readonly UserRepo $repo", "", false},
+		{"show", KindMethod, "UserController", "public static function show(int $id, ?string $name = null): ?User", "Shows a user.", true},
+		{"Authorizes", KindInterface, "", "trait Authorizes", "", true},
+		{"authorize", KindMethod, "Authorizes", "public function authorize(): bool", "", true},
+		{"Status", KindEnum, "", "enum Status: string implements HasLabel", "", true},
+		{"Active", KindConstant, "Status", "case Active = 'active'", "", true},
+		{"label", KindMethod, "Status", "public function label(): string", "", true},
+		{"HasLabel", KindInterface, "", `interface HasLabel extends \Stringable`, "", true},
+		{"format_name", KindFunction, "", "function format_name(string $first, string $last = ''): string", "", true},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			var sym *Symbol
+			for i := range in {
+				if in[i].Name == tt.name {
+					sym = &in[i]
+				}
+			}
+			if sym == nil {
+				t.Fatalf("%s not found under %q", tt.name, tt.parent)
+			}
+			if sym.Kind != tt.kind {
+				t.Errorf("%s.Kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+			}
+			if sym.Signature != tt.signature {
+				t.Errorf("%s.Signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+			}
+			if sym.DocComment != tt.doc {
+				t.Errorf("%s.DocComment = %q, want %q", tt.name, sym.DocComment, tt.doc)
+			}
+			if sym.Exported != tt.exported {
+				t.Errorf("%s.Exported = %v, want %v", tt.name, sym.Exported, tt.exported)
+			}
+		})
+	}
+	// The namespace, VERSION, and the five declarations; members are not
+	// repeated at the top level
+	if len(result.Symbols) != 7 {
+		t.Errorf("expected 7 top-level symbols without members, got %+v", result.Symbols)
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		source string
+		target string
+	}{
+		{RelImport, "", `App\Models\User`},
+		{RelImport, "", `Illuminate\Http\Request`},
+		{RelImport, "", `Illuminate\Http\Response`},
+		{RelImport, "UserController", "Authorizes"},
+		{RelExtends, "UserController", "Controller"},
+		{RelImplements, "UserController", "HasMiddleware"},
+		{RelImplements, "UserController", "JsonSerializable"},
+		{RelImplements, "Status", "HasLabel"},
+		{RelExtends, "HasLabel", "Stringable"},
+		{RelCall, "", "User::find"},
+		{RelCall, "", "$this->repo.save"},
+		{RelCall, "", "helper"},
+		{RelCall, "", "ucfirst"},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				target := rel.TargetSymbol
+				if rel.Kind == RelImport && rel.TargetFile != "" {
+					target = rel.TargetFile
+				}
+				if rel.Kind == tt.kind && rel.SourceSymbol == tt.source && target == tt.target {
+					return
+				}
+			}
+			t.Errorf("no %s relationship from %q to %q in %+v", tt.kind, tt.source, tt.target, result.Relationships)
+		})
+	}
+}
+
 // TestKotlinParser tests Kotlin parsing
 func TestKotlinParser(t *testing.T) {
 	parser := NewKotlinParser()
@@ -1078,6 +2033,139 @@ fun main() {
 	})
 }
 
+// TestKotlinParserAndroid tests the declarations of an Android source file:
+// data classes, objects, extension functions, properties, and supertypes.
+func TestKotlinParserAndroid(t *testing.T) {
+	code := `package com.example.app
+
+import android.os.Bundle
+import com.example.data.*
+
+/**
+ * A signed-in user.
+ *
+ * @property id the server ID
+ */
+data class User(val id: Int, var name: String) : Base(), Serializable {
+    /** Greets the user. */
+    fun greet(prefix: String): String = prefix + name
+
+    companion object {
+        const val TAG = "User"
+    }
+}
+
+interface Repo<T> : Closeable {
+    suspend fun load(id: Int): T?
+}
+
+object Registry : Repo<User> {
+    override suspend fun load(id: Int): User? = null
+}
+
+/** Shouts the string. */
+@JvmOverloads
+fun String.shout(times: Int = 1): String {
+    val local = uppercase()
+    return local.repeat(times)
+}
+
+val DEFAULT_NAME: String = "guest"
+private var counter = 0
+`
+	result, err := NewKotlinParser().Parse([]byte(code), "app/src/main/java/com/example/app/User.kt")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name      string
+		kind      SymbolKind
+		parent    string
+		signature string
+		doc       string
+		exported  bool
+	}{
+		{"User", KindClass, "", "data class User(val id: Int, var name: String) : Base(), Serializable", "A signed-in user.", true},
+		{"id", KindProperty, "User", "val id: Int", "", true},
+		{"greet", KindMethod, "User", "fun greet(prefix: String): String", "Greets the user.", true},
+		{"Companion", KindClass, "User", "companion object", "", true},
+		{"TAG", KindProperty, "Companion", "const val TAG", "", true},
+		{"Repo", KindInterface, "", "interface Repo<T> : Closeable", "", true},
+		{"load", KindMethod, "Repo", "suspend fun load(id: Int): T?", "", true},
+		{"Registry", KindClass, "", "object Registry : Repo<User>", "", true},
+		{"shout", KindFunction, "", "fun String.shout(times: Int = 1): String", "Shouts the string.", true},
+		{"DEFAULT_NAME", KindConstant, "", "val DEFAULT_NAME: String", "", true},
+		{"counter", KindVariable, "", "private var counter", "", false},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			var sym *Symbol
+			for i := range in {
+				if in[i].Name == tt.name {
+					sym = &in[i]
+				}
+			}
+			if sym == nil {
+				t.Fatalf("%s not found under %q", tt.name, tt.parent)
+			}
+			if sym.Kind != tt.kind {
+				t.Errorf("%s.Kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+			}
+			if sym.Signature != tt.signature {
+				t.Errorf("%s.Signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+			}
+			if sym.DocComment != tt.doc {
+				t.Errorf("%s.DocComment = %q, want %q", tt.name, sym.DocComment, tt.doc)
+			}
+			if sym.Exported != tt.exported {
+				t.Errorf("%s.Exported = %v, want %v", tt.name, sym.Exported, tt.exported)
+			}
+		})
+	}
+	if len(result.Symbols) != 6 {
+		t.Errorf("expected 6 top-level symbols without members or locals, got %+v", result.Symbols)
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		source string
+		target string
+	}{
+		{RelExtends, "User", "Base"},
+		{RelImplements, "User", "Serializable"},
+		{RelExtends, "Repo", "Closeable"},
+		{RelImplements, "Registry", "Repo"},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				if rel.Kind == tt.kind && rel.SourceSymbol == tt.source && rel.TargetSymbol == tt.target {
+					return
+				}
+			}
+			t.Errorf("no %s relationship from %q to %q in %+v", tt.kind, tt.source, tt.target, result.Relationships)
+		})
+	}
+	var imports []string
+	for _, rel := range result.Relationships {
+		if rel.Kind == RelImport {
+			imports = append(imports, rel.TargetFile)
+		}
+	}
+	if want := []string{"android.os.Bundle", "com.example.data"}; !slices.Equal(imports, want) {
+		t.Errorf("imports = %v, want %v", imports, want)
+	}
+}
+
 // TestScalaParser tests Scala parsing
 func TestScalaParser(t *testing.T) {
 	parser := NewScalaParser()
@@ -1325,6 +2413,70 @@ sqlite = "3.0"
 			t.Errorf("Language = %q, want %q", result.Language, "toml")
 		}
 	})
+
+	t.Run("nested tables and keys", func(t *testing.T) {
+		code := `[tool.poetry]
+name = "palace"
+version = "0.1.0"
+
+[[bin]]
+name = "palace"
+
+[[bin]]
+name = "palace-mcp"
+`
+		result, err := parser.Parse([]byte(code), "pyproject.toml")
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		if len(result.Symbols) != 3 {
+			t.Fatalf("expected 3 tables, got %+v", result.Symbols)
+		}
+		poetry := result.Symbols[0]
+		if poetry.Name != "tool.poetry" || poetry.Kind != KindClass {
+			t.Errorf("first table = %s (%s), want tool.poetry class", poetry.Name, poetry.Kind)
+		}
+		if len(poetry.Children) != 2 || poetry.Children[0].Name != "name" || poetry.Children[1].Name != "version" {
+			t.Errorf("tool.poetry children = %+v, want name and version", poetry.Children)
+		}
+		if result.Symbols[1].Name != "bin[0]" || result.Symbols[2].Name != "bin[1]" {
+			t.Errorf("array-of-tables = %s, %s, want bin[0], bin[1]", result.Symbols[1].Name, result.Symbols[2].Name)
+		}
+	})
+
+	t.Run("dependency references", func(t *testing.T) {
+		code := `[package]
+name = "palace"
+
+[dependencies]
+serde = { version = "1", features = ["derive"] }
+tokio.workspace = true
+
+[dependencies.rusqlite]
+version = "0.31"
+
+[project]
+dependencies = ["requests[socks]>=2.0", "click"]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+httpx = "^0.27"
+`
+		result, err := parser.Parse([]byte(code), "Cargo.toml")
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		var deps []string
+		for _, rel := range result.Relationships {
+			if rel.Kind == RelImport {
+				deps = append(deps, rel.TargetFile)
+			}
+		}
+		want := []string{"serde", "tokio", "rusqlite", "requests", "click", "httpx"}
+		if strings.Join(deps, ",") != strings.Join(want, ",") {
+			t.Errorf("dependencies = %v, want %v", deps, want)
+		}
+	})
 }
 
 // TestSvelteParser tests Svelte parsing
@@ -1512,6 +2664,7 @@ func TestParserLanguageMethod(t *testing.T) {
 		{NewProtobufParser(), LangProtobuf},
 		{NewDartParser(), LangDart},
 		{NewCUEParser(), LangCUE},
+		{NewNimParser(), LangNim},
 	}
 
 	for _, tt := range tests {
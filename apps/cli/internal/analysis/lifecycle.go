@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Go's "Deprecated:" paragraph, JSDoc/Javadoc/PHPDoc tags, and shouted
+	// markers such as "# DEPRECATED"
+	deprecatedDocRe   = regexp.MustCompile(`(?m)^\s*\*?\s*Deprecated:|@deprecated\b|\bDEPRECATED\b`)
+	experimentalDocRe = regexp.MustCompile(`(?m)^\s*\*?\s*Experimental:|@experimental\b|\bEXPERIMENTAL\b`)
+)
+
+// Annotation names, lowercased and without arguments or qualifiers, that
+// mark a symbol deprecated or experimental: Python's @deprecated, Java and
+// Kotlin's @Deprecated, C#'s [Obsolete], and Rust's #[deprecated] and
+// #[unstable].
+var (
+	deprecatedAnnotations   = map[string]bool{"deprecated": true, "obsolete": true}
+	experimentalAnnotations = map[string]bool{"experimental": true, "unstable": true}
+)
+
+// markLifecycle sets Deprecated and Experimental on the symbols of fa from
+// their annotations, their doc comments, and the comment lines directly
+// above them. Reading the source comments as well as DocComment catches
+// markers in multi-line doc comments that parsers only partly keep, such as
+// a Go "Deprecated:" paragraph.
+func markLifecycle(fa *FileAnalysis, lang Language, content []byte) {
+	if fa == nil || len(fa.Symbols) == 0 {
+		return
+	}
+	commentByLine := make(map[int]string)
+	for _, c := range Comments(lang, content) {
+		if c.OwnLine {
+			commentByLine[c.Line] += c.Text + "\n"
+		}
+	}
+
+	stack := []*[]Symbol{&fa.Symbols}
+	for len(stack) > 0 {
+		symbols := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for i := range *symbols {
+			sym := &(*symbols)[i]
+			if sym.Kind != KindFinding {
+				markSymbolLifecycle(sym, precedingComments(commentByLine, sym.LineStart))
+			}
+			if len(sym.Children) > 0 {
+				stack = append(stack, &sym.Children)
+			}
+		}
+	}
+}
+
+// markSymbolLifecycle sets the flags of one symbol given the comment block
+// above it.
+func markSymbolLifecycle(sym *Symbol, comments string) {
+	for _, ann := range sym.Annotations {
+		name := annotationName(ann)
+		sym.Deprecated = sym.Deprecated || deprecatedAnnotations[name]
+		sym.Experimental = sym.Experimental || experimentalAnnotations[name]
+	}
+	for _, text := range []string{sym.DocComment, comments} {
+		sym.Deprecated = sym.Deprecated || deprecatedDocRe.MatchString(text)
+		sym.Experimental = sym.Experimental || experimentalDocRe.MatchString(text)
+	}
+}
+
+// precedingComments returns the contiguous comment lines ending just above
+// line, in source order.
+func precedingComments(commentByLine map[int]string, line int) string {
+	start := line
+	for start > 1 && commentByLine[start-1] != "" {
+		start--
+	}
+	var sb strings.Builder
+	for l := start; l < line; l++ {
+		sb.WriteString(commentByLine[l])
+	}
+	return sb.String()
+}
+
+// annotationName reduces an annotation such as
+// "typing_extensions.deprecated('use g')" to "deprecated".
+func annotationName(ann string) string {
+	if i := strings.IndexAny(ann, "( "); i >= 0 {
+		ann = ann[:i]
+	}
+	if i := strings.LastIndexAny(ann, ".:"); i >= 0 {
+		ann = ann[i+1:]
+	}
+	return strings.ToLower(ann)
+}
@@ -0,0 +1,56 @@
+package analysis
+
+import "testing"
+
+func TestDeprecatedGoDocConvention(t *testing.T) {
+	src := `package thing
+
+// OldThing builds a thing the slow way.
+//
+// Deprecated: use NewThing instead.
+func OldThing() {}
+
+// NewThing builds a thing.
+func NewThing() {}
+`
+	fa, err := Analyze([]byte(src), "thing.go")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	old, current := findSymbol(fa.Symbols, "OldThing"), findSymbol(fa.Symbols, "NewThing")
+	if old == nil || current == nil {
+		t.Fatalf("expected OldThing and NewThing, got %+v", fa.Symbols)
+	}
+	if !old.Deprecated {
+		t.Error("OldThing should be deprecated")
+	}
+	if current.Deprecated || current.Experimental {
+		t.Errorf("NewThing should carry no lifecycle flags, got %+v", *current)
+	}
+}
+
+func TestDeprecatedPythonDecorator(t *testing.T) {
+	src := `from warnings import deprecated
+
+@deprecated("use fetch_all")
+def fetch():
+    pass
+
+def fetch_all():
+    pass
+`
+	fa, err := Analyze([]byte(src), "api.py")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	fetch, fetchAll := findSymbol(fa.Symbols, "fetch"), findSymbol(fa.Symbols, "fetch_all")
+	if fetch == nil || fetchAll == nil {
+		t.Fatalf("expected fetch and fetch_all, got %+v", fa.Symbols)
+	}
+	if !fetch.Deprecated {
+		t.Errorf("fetch should be deprecated, annotations %v", fetch.Annotations)
+	}
+	if fetchAll.Deprecated {
+		t.Error("fetch_all should not be deprecated")
+	}
+}
@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLiteralValueLen caps the length, in runes, of a Symbol's Value.
+const maxLiteralValueLen = 120
+
+// numericLiteral matches integer and floating-point literals, including hex,
+// octal, and binary forms, digit separators, and type suffixes ("10L",
+// "2.5f", "8u32").
+var numericLiteral = regexp.MustCompile(`^[-+]?(0[xX][0-9a-fA-F_]+|0[oObB][0-7_]+|(\d[\d_]*)?\.?\d[\d_]*([eE][-+]?\d+)?)([lLuUfFdDnj]|_?[uif](8|16|32|64|128|size))?$`)
+
+// literalValue returns the value of a simple literal written in source: a
+// string (without its quotes), a number, or a boolean. Anything else, such
+// as a call, an interpolated string, or arithmetic, yields "". Long values
+// are cut to maxLiteralValueLen runes.
+func literalValue(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return ""
+	}
+
+	switch expr {
+	case "true", "false", "True", "False":
+		return expr
+	}
+	if numericLiteral.MatchString(expr) {
+		return capLiteral(expr)
+	}
+
+	// Raw and unicode prefixes do not change what a Python string holds;
+	// f-strings and bytes are not plain text.
+	if len(expr) > 1 && strings.ContainsRune("rRuU", rune(expr[0])) && (expr[1] == '"' || expr[1] == '\'') {
+		expr = expr[1:]
+	}
+	if len(expr) < 2 {
+		return ""
+	}
+	quote := expr[0]
+	if quote != '"' && quote != '\'' && quote != '`' || expr[len(expr)-1] != quote {
+		return ""
+	}
+	for _, triple := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(expr, triple) {
+			return "" // Docstring-like blocks are not config values
+		}
+	}
+	body := expr[1 : len(expr)-1]
+	if strings.ContainsRune(body, rune(quote)) && !strings.Contains(body, `\`+string(quote)) {
+		return "" // Concatenation, as in "a" + "b"
+	}
+	if quote == '`' {
+		if strings.Contains(body, "${") {
+			return "" // Template literal with substitutions
+		}
+		return capLiteral(body)
+	}
+	if quote == '"' {
+		if s, err := strconv.Unquote(expr); err == nil {
+			return capLiteral(s)
+		}
+	}
+	return capLiteral(body)
+}
+
+func capLiteral(s string) string {
+	if utf8.RuneCountInString(s) <= maxLiteralValueLen {
+		return s
+	}
+	return string([]rune(s)[:maxLiteralValueLen]) + "…"
+}
@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLiteralValue(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`"1.0.0"`, "1.0.0"},
+		{`'utf-8'`, "utf-8"},
+		{`r"\d+"`, `\d+`},
+		{`"tab\there"`, "tab\there"},
+		{"`plain`", "plain"},
+		{"42", "42"},
+		{"-3.5e2", "-3.5e2"},
+		{"0xFF", "0xFF"},
+		{"1_000_000", "1_000_000"},
+		{"30u32", "30u32"},
+		{"True", "True"},
+		{"false", "false"},
+		{"None", ""},
+		{`f"v{major}"`, ""},
+		{`b"raw"`, ""},
+		{"`v${major}`", ""},
+		{`"a" + "b"`, ""},
+		{"60 * 60", ""},
+		{"os.getenv('HOME')", ""},
+		{"[1, 2]", ""},
+	}
+	for _, tt := range tests {
+		if got := literalValue(tt.expr); got != tt.want {
+			t.Errorf("literalValue(%s) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+
+	long := `"` + strings.Repeat("x", 200) + `"`
+	if got := literalValue(long); len([]rune(got)) != maxLiteralValueLen+1 {
+		t.Errorf("long literal kept %d runes, want %d and an ellipsis", len([]rune(got)), maxLiteralValueLen)
+	}
+}
+
+func TestParserConstantValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser Parser
+		path   string
+		code   string
+		want   map[string]string
+	}{
+		{
+			name:   "python",
+			parser: NewPythonParser(),
+			path:   "settings.py",
+			code:   "VERSION = \"1.0.0\"\nTIMEOUT = 60 * 60\nDEBUG = False\n",
+			want:   map[string]string{"VERSION": "1.0.0", "TIMEOUT": "", "DEBUG": "False"},
+		},
+		{
+			name:   "go",
+			parser: NewGoParser(),
+			path:   "config.go",
+			code:   "package config\n\nconst Version = \"2.1\"\n\nconst MaxRetries = 3\n\nvar Started = time.Now()\n",
+			want:   map[string]string{"Version": "2.1", "MaxRetries": "3", "Started": ""},
+		},
+		{
+			name:   "typescript",
+			parser: NewTypeScriptParser(),
+			path:   "config.ts",
+			code:   "export const API_URL = 'https://example.com';\nconst PORT = 8080;\nconst TAG = `v${PORT}`;\n",
+			want:   map[string]string{"API_URL": "https://example.com", "PORT": "8080", "TAG": ""},
+		},
+		{
+			name:   "java",
+			parser: NewJavaParser(),
+			path:   "Config.java",
+			code:   "public class Config {\n    public static final String NAME = \"a\";\n    static final long LIMIT = 10L;\n    private final List<String> names = new ArrayList<>();\n}\n",
+			want:   map[string]string{"NAME": "a", "LIMIT": "10L", "names": ""},
+		},
+		{
+			name:   "kotlin",
+			parser: NewKotlinParser(),
+			path:   "Config.kt",
+			code:   "val topLevel = 42\nval greeting = \"hi $topLevel\"\n\nobject Config {\n    const val NAME = \"a\"\n    val started = System.nanoTime()\n}\n",
+			want:   map[string]string{"topLevel": "42", "greeting": "", "NAME": "a", "started": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.parser.Parse([]byte(tt.code), tt.path)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			got := make(map[string]string)
+			var collect func(symbols []Symbol)
+			collect = func(symbols []Symbol) {
+				for _, sym := range symbols {
+					got[sym.Name] = sym.Value
+					collect(sym.Children)
+				}
+			}
+			collect(result.Symbols)
+			for name, want := range tt.want {
+				value, ok := got[name]
+				if !ok {
+					t.Errorf("symbol %s not found in %+v", name, result.Symbols)
+					continue
+				}
+				if value != want {
+					t.Errorf("%s Value = %q, want %q", name, value, want)
+				}
+			}
+		})
+	}
+}
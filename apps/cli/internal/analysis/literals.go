@@ -0,0 +1,190 @@
+package analysis
+
+import "strings"
+
+// StringLiteral is a quoted string found in source code, with its quotes
+// removed and escapes left as written.
+type StringLiteral struct {
+	Value  string
+	Line   int // 1-based
+	Column int // 0-based byte offset of the opening quote within its line
+}
+
+// Comment is one line of comment text found in source code, with the
+// comment markers removed. Block comments yield one Comment per line.
+type Comment struct {
+	Text    string
+	Line    int  // 1-based
+	OwnLine bool // Nothing but whitespace precedes the comment on its line
+}
+
+// lexSyntax describes how a language writes comments and strings, which is
+// all the lexer needs to tell code, comments, and strings apart.
+type lexSyntax struct {
+	lineComments []string
+	blockComment [2]string // Opening and closing delimiters; empty if none
+	quotes       string    // Characters that open a string
+	rawQuotes    string    // Quotes whose strings have no escapes
+	tripleQuotes bool      // Python-style """ and ''' strings
+}
+
+var (
+	cSyntax      = lexSyntax{lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`}
+	hashSyntax   = lexSyntax{lineComments: []string{"#"}, quotes: `"'`}
+	dashSyntax   = lexSyntax{lineComments: []string{"--"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`}
+	plainSyntax  = lexSyntax{quotes: `"`}
+	syntaxByLang = map[Language]lexSyntax{
+		LangGo:         {lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`", rawQuotes: "`"},
+		LangJavaScript: {lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`"},
+		LangTypeScript: {lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`"},
+		LangSvelte:     {lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`"},
+		LangPython:     {lineComments: []string{"#"}, quotes: `"'`, tripleQuotes: true},
+		// Single quotes in Rust are chars and lifetimes ('a), never secrets
+		LangRust:       {lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"`},
+		LangJava:       cSyntax,
+		LangDart:       cSyntax,
+		LangC:          cSyntax,
+		LangCPP:        cSyntax,
+		LangObjectiveC: cSyntax,
+		LangMATLAB:     {lineComments: []string{"%"}, blockComment: [2]string{"%{", "%}"}, quotes: `"'`},
+		LangCSharp:     cSyntax,
+		LangSwift:      cSyntax,
+		LangKotlin:     cSyntax,
+		LangScala:      cSyntax,
+		LangGroovy:     cSyntax,
+		LangCSS:        cSyntax,
+		LangProtobuf:   cSyntax,
+		LangCUE:        cSyntax,
+		LangHCL:        {lineComments: []string{"#", "//"}, blockComment: [2]string{"/*", "*/"}, quotes: `"`},
+		LangPHP:        {lineComments: []string{"//", "#"}, blockComment: [2]string{"/*", "*/"}, quotes: `"'`},
+		LangRuby:       hashSyntax,
+		LangBash:       {lineComments: []string{"#"}, quotes: `"'`, rawQuotes: "'"},
+		LangYAML:       hashSyntax,
+		LangTOML:       hashSyntax,
+		LangElixir:     hashSyntax,
+		LangDockerfile: hashSyntax,
+		LangSQL:        dashSyntax,
+		LangLua:        {lineComments: []string{"--"}, quotes: `"'`},
+		LangElm:        {lineComments: []string{"--"}, blockComment: [2]string{"{-", "-}"}, quotes: `"`},
+		LangOCaml:      {blockComment: [2]string{"(*", "*)"}, quotes: `"`},
+		LangNim:        {lineComments: []string{"#"}, blockComment: [2]string{"#[", "]#"}, quotes: `"`, tripleQuotes: true},
+		LangJSON:       plainSyntax,
+	}
+)
+
+// StringLiterals returns the string literals in content, skipping comments,
+// using the comment and quote syntax of lang. Languages without a known
+// syntax are read as double-quoted strings with no comments. The scan is
+// lexical, so constructs such as regex literals or heredocs may be misread;
+// it is meant for heuristics over string contents, not for parsing.
+func StringLiterals(lang Language, content []byte) []StringLiteral {
+	literals, _ := lexSource(lang, content)
+	return literals
+}
+
+// Comments returns the comments in content, one entry per comment line,
+// using the same syntax rules as StringLiterals. Comment markers inside
+// strings are not mistaken for comments.
+func Comments(lang Language, content []byte) []Comment {
+	_, comments := lexSource(lang, content)
+	return comments
+}
+
+// lexSource splits content into string literals and comments.
+func lexSource(lang Language, content []byte) ([]StringLiteral, []Comment) {
+	syntax, ok := syntaxByLang[lang]
+	if !ok {
+		syntax = plainSyntax
+	}
+	src := string(content)
+	var literals []StringLiteral
+	var comments []Comment
+	line, lineStart := 1, 0
+
+	// advance moves i to end, counting the newlines passed over
+	advance := func(i, end int) int {
+		for ; i < end && i < len(src); i++ {
+			if src[i] == '\n' {
+				line++
+				lineStart = i + 1
+			}
+		}
+		return i
+	}
+
+	for i := 0; i < len(src); {
+		rest := src[i:]
+
+		if marker := firstPrefix(rest, syntax.lineComments); marker != "" {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			comments = append(comments, Comment{
+				Text:    rest[len(marker):end],
+				Line:    line,
+				OwnLine: strings.TrimSpace(src[lineStart:i]) == "",
+			})
+			i += end
+			continue
+		}
+		if open := syntax.blockComment[0]; open != "" && strings.HasPrefix(rest, open) {
+			end := strings.Index(rest[len(open):], syntax.blockComment[1])
+			if end < 0 {
+				break
+			}
+			ownLine := strings.TrimSpace(src[lineStart:i]) == ""
+			for n, text := range strings.Split(rest[len(open):len(open)+end], "\n") {
+				comments = append(comments, Comment{Text: text, Line: line + n, OwnLine: ownLine || n > 0})
+			}
+			i = advance(i, i+len(open)+end+len(syntax.blockComment[1]))
+			continue
+		}
+
+		c := src[i]
+		if !strings.ContainsRune(syntax.quotes, rune(c)) {
+			i = advance(i, i+1)
+			continue
+		}
+
+		startLine, startCol := line, i-lineStart
+		if syntax.tripleQuotes && strings.HasPrefix(rest, strings.Repeat(string(c), 3)) {
+			delim := rest[:3]
+			end := strings.Index(rest[3:], delim)
+			if end < 0 {
+				break
+			}
+			literals = append(literals, StringLiteral{Value: rest[3 : 3+end], Line: startLine, Column: startCol})
+			i = advance(i, i+3+end+3)
+			continue
+		}
+
+		raw := strings.ContainsRune(syntax.rawQuotes, rune(c))
+		j := i + 1
+		for j < len(src) && src[j] != c {
+			if src[j] == '\\' && !raw {
+				j++
+			} else if src[j] == '\n' && c != '`' && !raw {
+				break // Unterminated on this line; not a string after all
+			}
+			j++
+		}
+		if j >= len(src) || src[j] != c {
+			i = advance(i, i+1)
+			continue
+		}
+		literals = append(literals, StringLiteral{Value: src[i+1 : j], Line: startLine, Column: startCol})
+		i = advance(i, j+1)
+	}
+	return literals, comments
+}
+
+// firstPrefix returns the first of prefixes that s starts with, or "".
+func firstPrefix(s string, prefixes []string) string {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return p
+		}
+	}
+	return ""
+}
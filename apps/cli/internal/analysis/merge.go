@@ -0,0 +1,108 @@
+package analysis
+
+import (
+	"slices"
+	"sort"
+)
+
+// Merge adds the symbols, relationships, and warnings of other, a partial
+// analysis of the same file, to fa. Parsers of files embedding several
+// languages, such as a .vue file's template and script, analyze each region
+// alone and merge the results.
+//
+// Line numbers are kept as they are, so a region parsed on its own should be
+// moved into place with ShiftLines first. A symbol found by both analyses,
+// with the same name and kind at the same position, is kept once, with its
+// children merged the same way; identical relationships and warnings are
+// kept once too. Symbols and relationships end up in line order.
+func (fa *FileAnalysis) Merge(other *FileAnalysis) {
+	if other == nil {
+		return
+	}
+	if fa.Path == "" {
+		fa.Path = other.Path
+	}
+	if fa.Language == "" {
+		fa.Language = other.Language
+	}
+	fa.IsTest = fa.IsTest || other.IsTest
+
+	fa.Symbols = mergeSymbols(fa.Symbols, other.Symbols)
+
+	for _, rel := range other.Relationships {
+		if !slices.Contains(fa.Relationships, rel) {
+			fa.Relationships = append(fa.Relationships, rel)
+		}
+	}
+	sort.SliceStable(fa.Relationships, func(i, j int) bool {
+		return fa.Relationships[i].Line < fa.Relationships[j].Line
+	})
+
+	for _, w := range other.Warnings {
+		if !slices.Contains(fa.Warnings, w) {
+			fa.Warnings = append(fa.Warnings, w)
+		}
+	}
+}
+
+// ShiftLines moves every symbol and relationship of fa down by offset
+// lines, placing an analysis of a region parsed on its own at the region's
+// line in the whole file.
+func (fa *FileAnalysis) ShiftLines(offset int) {
+	shiftSymbolLines(fa.Symbols, offset)
+	for i := range fa.Relationships {
+		if fa.Relationships[i].Line > 0 {
+			fa.Relationships[i].Line += offset
+		}
+	}
+}
+
+func shiftSymbolLines(symbols []Symbol, offset int) {
+	for i := range symbols {
+		symbols[i].LineStart += offset
+		symbols[i].LineEnd += offset
+		shiftSymbolLines(symbols[i].Children, offset)
+	}
+}
+
+// symbolKey identifies a symbol found by two analyses of the same file.
+type symbolKey struct {
+	name      string
+	kind      SymbolKind
+	lineStart int
+	colStart  int
+}
+
+// mergeSymbols returns a with the symbols of b it does not hold, in line
+// order. Symbols both hold have their children merged, and empty details
+// of a's are filled in from b's.
+func mergeSymbols(a, b []Symbol) []Symbol {
+	index := make(map[symbolKey]int, len(a))
+	for i, sym := range a {
+		index[symbolKey{sym.Name, sym.Kind, sym.LineStart, sym.ColStart}] = i
+	}
+	for _, sym := range b {
+		i, ok := index[symbolKey{sym.Name, sym.Kind, sym.LineStart, sym.ColStart}]
+		if !ok {
+			index[symbolKey{sym.Name, sym.Kind, sym.LineStart, sym.ColStart}] = len(a)
+			a = append(a, sym)
+			continue
+		}
+		existing := &a[i]
+		existing.Children = mergeSymbols(existing.Children, sym.Children)
+		if existing.Signature == "" {
+			existing.Signature = sym.Signature
+		}
+		if existing.DocComment == "" {
+			existing.DocComment = sym.DocComment
+		}
+		if existing.LineEnd < sym.LineEnd {
+			existing.LineEnd = sym.LineEnd
+		}
+		existing.Exported = existing.Exported || sym.Exported
+	}
+	sort.SliceStable(a, func(i, j int) bool {
+		return a[i].LineStart < a[j].LineStart
+	})
+	return a
+}
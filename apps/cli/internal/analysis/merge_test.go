@@ -0,0 +1,108 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+// A single-file Vue component: the template, then the script region, whose
+// first line is line 6 of the file.
+const vueTemplate = `<template>
+  <div id="app">
+    <user-card :user="user" />
+  </div>
+</template>
+`
+
+const vueScript = `import { defineComponent } from 'vue'
+import UserCard from './UserCard.vue'
+
+export function formatName(first: string, last: string): string {
+  return first + ' ' + last
+}
+`
+
+func TestMergeVueRegions(t *testing.T) {
+	template, err := NewHTMLParser().Parse([]byte(vueTemplate), "App.vue")
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	script, err := NewTypeScriptParser().Parse([]byte(vueScript), "App.vue")
+	if err != nil {
+		t.Fatalf("parse script: %v", err)
+	}
+	script.ShiftLines(6)
+	script.Warnings = []string{"region truncated"}
+
+	fa := &FileAnalysis{Path: "App.vue", Language: "vue"}
+	fa.Merge(template)
+	fa.Merge(script)
+	relationships := len(fa.Relationships)
+
+	// Merging the same regions again adds nothing
+	fa.Merge(template)
+	fa.Merge(script)
+	fa.Merge(nil)
+
+	if fa.Language != "vue" {
+		t.Errorf("Language = %q, want the merged analysis' own", fa.Language)
+	}
+	var names []string
+	for _, sym := range fa.Symbols {
+		names = append(names, sym.Name)
+	}
+	if got := strings.Join(names, ","); got != "template,#app,formatName" {
+		t.Errorf("symbols = %s, want template,#app,formatName in line order", got)
+	}
+	if len(fa.Relationships) != relationships {
+		t.Errorf("expected %d relationships, got %+v", relationships, fa.Relationships)
+	}
+	if len(fa.Warnings) != 1 {
+		t.Errorf("expected the warning once, got %q", fa.Warnings)
+	}
+
+	formatName := findSymbol(fa.Symbols, "formatName")
+	if formatName == nil {
+		t.Fatalf("formatName not found in %+v", fa.Symbols)
+	}
+	if formatName.LineStart != 10 || formatName.LineEnd != 12 {
+		t.Errorf("formatName at lines %d-%d, want 10-12", formatName.LineStart, formatName.LineEnd)
+	}
+	found := false
+	for _, rel := range fa.Relationships {
+		if rel.Kind == RelImport && strings.Contains(rel.TargetFile, "UserCard") {
+			found = true
+			if rel.Line != 8 {
+				t.Errorf("UserCard import at line %d, want 8", rel.Line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("UserCard import not found in %+v", fa.Relationships)
+	}
+}
+
+func TestMergeSymbolChildren(t *testing.T) {
+	fa := &FileAnalysis{Symbols: []Symbol{
+		{Name: "User", Kind: KindClass, LineStart: 1, LineEnd: 5, Children: []Symbol{
+			{Name: "name", Kind: KindProperty, LineStart: 2},
+		}},
+	}}
+	fa.Merge(&FileAnalysis{Symbols: []Symbol{
+		{Name: "User", Kind: KindClass, LineStart: 1, LineEnd: 9, DocComment: "A user.", Children: []Symbol{
+			{Name: "name", Kind: KindProperty, LineStart: 2},
+			{Name: "greet", Kind: KindMethod, LineStart: 6},
+		}},
+	}})
+
+	if len(fa.Symbols) != 1 {
+		t.Fatalf("expected User once, got %+v", fa.Symbols)
+	}
+	user := fa.Symbols[0]
+	if len(user.Children) != 2 || user.Children[1].Name != "greet" {
+		t.Errorf("expected name and greet as children, got %+v", user.Children)
+	}
+	if user.DocComment != "A user." || user.LineEnd != 9 {
+		t.Errorf("expected the doc comment and end line filled in, got %+v", user)
+	}
+}
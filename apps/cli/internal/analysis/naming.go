@@ -0,0 +1,182 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NamingRuleName names the finding emitted for a naming-convention
+// violation.
+const NamingRuleName = "naming-convention"
+
+// Naming styles a rule can require.
+const (
+	StyleSnakeCase  = "snake_case"
+	StyleCamelCase  = "camelCase"
+	StylePascalCase = "PascalCase"
+	StyleUpperCase  = "UPPER_CASE"
+	StyleMixedCaps  = "MixedCaps" // Go: letters and digits, no underscores
+)
+
+// namingStyles holds the pattern of each style. Leading underscores, which
+// mark privacy in several languages, are allowed by all of them, and
+// snake_case allows the ? and ! suffixes of Ruby methods and the trailing
+// underscores of Python's dunder methods.
+var namingStyles = map[string]*regexp.Regexp{
+	StyleSnakeCase:  regexp.MustCompile(`^_*[a-z][a-z0-9_]*[?!]?$`),
+	StyleCamelCase:  regexp.MustCompile(`^[_$]*[a-z][a-zA-Z0-9]*$`),
+	StylePascalCase: regexp.MustCompile(`^_*[A-Z][a-zA-Z0-9]*$`),
+	StyleUpperCase:  regexp.MustCompile(`^_*[A-Z][A-Z0-9_]*$`),
+	StyleMixedCaps:  regexp.MustCompile(`^_*[A-Za-z][A-Za-z0-9]*$`),
+}
+
+// NamingRule requires the names of one kind of symbol in one language to
+// follow a style, or to match Pattern when it is set.
+type NamingRule struct {
+	Language   Language
+	Kind       SymbolKind
+	Visibility string // "exported", "unexported", or empty for both
+	Style      string // A Style constant; describes Pattern when both are set
+	Pattern    *regexp.Regexp
+}
+
+// NewNamingRule returns a rule requiring style, or pattern when it is not
+// empty. Unknown styles and invalid patterns are errors.
+func NewNamingRule(lang Language, kind SymbolKind, visibility, style, pattern string) (NamingRule, error) {
+	rule := NamingRule{Language: lang, Kind: kind, Visibility: visibility, Style: style}
+	switch visibility {
+	case "", "exported", "unexported":
+	default:
+		return rule, fmt.Errorf("unknown visibility %q (use exported or unexported)", visibility)
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return rule, err
+		}
+		rule.Pattern = re
+		if rule.Style == "" {
+			rule.Style = pattern
+		}
+		return rule, nil
+	}
+	re, ok := namingStyles[style]
+	if !ok {
+		return rule, fmt.Errorf("unknown naming style %q", style)
+	}
+	rule.Pattern = re
+	return rule, nil
+}
+
+// DefaultNamingRules returns the community conventions of the languages
+// palace parses. Functions in JavaScript, TypeScript, and Kotlin are left
+// out, as components and composables are named in PascalCase there.
+func DefaultNamingRules() []NamingRule {
+	var rules []NamingRule
+	add := func(lang Language, style string, kinds ...SymbolKind) {
+		for _, kind := range kinds {
+			rules = append(rules, NamingRule{Language: lang, Kind: kind, Style: style, Pattern: namingStyles[style]})
+		}
+	}
+	add(LangGo, StyleMixedCaps, KindFunction, KindMethod, KindClass, KindInterface, KindType, KindTypeAlias, KindConstant, KindVariable)
+	add(LangPython, StyleSnakeCase, KindFunction, KindMethod)
+	add(LangPython, StylePascalCase, KindClass)
+	for _, lang := range []Language{LangJavaScript, LangTypeScript} {
+		add(lang, StyleCamelCase, KindMethod)
+		add(lang, StylePascalCase, KindClass, KindInterface)
+	}
+	add(LangJava, StyleCamelCase, KindMethod)
+	add(LangJava, StylePascalCase, KindClass, KindInterface, KindEnum)
+	add(LangJava, StyleUpperCase, KindConstant)
+	add(LangKotlin, StyleCamelCase, KindMethod)
+	add(LangKotlin, StylePascalCase, KindClass, KindInterface)
+	add(LangCSharp, StylePascalCase, KindClass, KindInterface, KindMethod)
+	add(LangRust, StyleSnakeCase, KindFunction, KindMethod)
+	add(LangRust, StylePascalCase, KindClass, KindInterface, KindEnum, KindType)
+	add(LangRust, StyleUpperCase, KindConstant)
+	add(LangRuby, StyleSnakeCase, KindFunction, KindMethod)
+	add(LangRuby, StylePascalCase, KindClass)
+	return rules
+}
+
+// OverrideNamingRules returns rules with each rule in overrides replacing
+// those of rules for the same language and kind. An override with a nil
+// Pattern only removes them, which turns the convention off.
+func OverrideNamingRules(rules, overrides []NamingRule) []NamingRule {
+	overridden := make(map[string]bool)
+	for _, o := range overrides {
+		overridden[string(o.Language)+"/"+string(o.Kind)] = true
+	}
+	var merged []NamingRule
+	for _, r := range rules {
+		if !overridden[string(r.Language)+"/"+string(r.Kind)] {
+			merged = append(merged, r)
+		}
+	}
+	for _, o := range overrides {
+		if o.Pattern != nil {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+// FindNamingViolations checks the symbols of fa, members included, against
+// rules and returns a finding for each name that breaks one. Tests,
+// findings, config keys, and the blank identifier are not checked, nor are
+// the test, benchmark, example, and fuzz functions of Go test files, whose
+// names the go tool dictates (Example_suffix, Test_helper).
+func FindNamingViolations(fa *FileAnalysis, rules []NamingRule) []Symbol {
+	if fa == nil {
+		return nil
+	}
+	lang := Language(fa.Language)
+	goTests := lang == LangGo && (fa.IsTest || IsTestFile(fa.Path, lang))
+	var findings []Symbol
+	var walk func(syms []Symbol)
+	walk = func(syms []Symbol) {
+		for i := range syms {
+			sym := &syms[i]
+			walk(sym.Children)
+			if sym.Test || IsSyntheticKind(sym.Kind) || sym.Name == "_" {
+				continue
+			}
+			if goTests && sym.Kind == KindFunction && isGoTestingFunc(sym.Name) {
+				continue
+			}
+			for _, r := range rules {
+				if r.Language != lang || r.Kind != sym.Kind || r.Pattern == nil {
+					continue
+				}
+				if (r.Visibility == "exported" && !sym.Exported) || (r.Visibility == "unexported" && sym.Exported) {
+					continue
+				}
+				if !r.Pattern.MatchString(sym.Name) {
+					findings = append(findings, Symbol{
+						Name:      NamingRuleName,
+						Kind:      KindFinding,
+						LineStart: sym.LineStart,
+						LineEnd:   sym.LineStart,
+						Signature: fmt.Sprintf("%s %q is not %s", sym.Kind, sym.Name, r.Style),
+					})
+					break
+				}
+			}
+		}
+	}
+	walk(fa.Symbols)
+	return findings
+}
+
+// isGoTestingFunc reports whether name is one the go tool runs from a test
+// file: TestXxx, BenchmarkXxx, ExampleXxx, or FuzzXxx, where Xxx does not
+// start with a lowercase letter.
+func isGoTestingFunc(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && (rest == "" || !isLowerASCII(rest[0])) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindNamingViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		code string
+		want []string // Descriptions of the expected findings
+	}{
+		{
+			name: "python function not snake_case",
+			path: "app/views.py",
+			code: "def MyFunc():\n    pass\n",
+			want: []string{`function "MyFunc" is not snake_case`},
+		},
+		{
+			name: "python snake_case function and dunder method",
+			path: "app/views.py",
+			code: "def my_func():\n    pass\n\nclass View:\n    def __init__(self):\n        pass\n",
+		},
+		{
+			name: "python method and class",
+			path: "app/views.py",
+			code: "class user_view:\n    def GetUser(self):\n        pass\n",
+			want: []string{`method "GetUser" is not snake_case`, `class "user_view" is not PascalCase`},
+		},
+		{
+			name: "go constant with underscores",
+			path: "pkg/limits.go",
+			code: "package pkg\n\nconst MAX_SIZE = 10\n\nconst MaxDepth = 3\n",
+			want: []string{`constant "MAX_SIZE" is not MixedCaps`},
+		},
+		{
+			name: "go test file functions named by the go tool",
+			path: "pkg/limits_test.go",
+			code: "package pkg\n\nfunc Example_limits() {}\n\nfunc Benchmark_size(b *testing.B) {}\n\nfunc Fuzz_parse(f *testing.F) {}\n\nfunc check_limits() {}\n",
+			want: []string{`function "check_limits" is not MixedCaps`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fa, err := Analyze([]byte(tt.code), tt.path)
+			if err != nil {
+				t.Fatalf("Analyze() error: %v", err)
+			}
+			var got []string
+			for _, f := range FindNamingViolations(fa, DefaultNamingRules()) {
+				if f.Kind != KindFinding || f.Name != NamingRuleName {
+					t.Errorf("expected a %s finding, got %+v", NamingRuleName, f)
+				}
+				got = append(got, f.Signature)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverrideNamingRules(t *testing.T) {
+	fa, err := Analyze([]byte("def fetchUser():\n    pass\n\ndef my_func():\n    pass\n"), "app/views.py")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	camel, err := NewNamingRule(LangPython, KindFunction, "", StyleCamelCase, "")
+	if err != nil {
+		t.Fatalf("NewNamingRule() error: %v", err)
+	}
+	findings := FindNamingViolations(fa, OverrideNamingRules(DefaultNamingRules(), []NamingRule{camel}))
+	if len(findings) != 1 || findings[0].Signature != `function "my_func" is not camelCase` {
+		t.Errorf("expected only my_func flagged under camelCase, got %+v", findings)
+	}
+
+	off := NamingRule{Language: LangPython, Kind: KindFunction}
+	if findings := FindNamingViolations(fa, OverrideNamingRules(DefaultNamingRules(), []NamingRule{off})); len(findings) != 0 {
+		t.Errorf("expected no findings with the convention off, got %+v", findings)
+	}
+	if _, err := NewNamingRule(LangPython, KindFunction, "", "kebab-case", ""); err == nil {
+		t.Error("expected an unknown style to be rejected")
+	}
+}
@@ -0,0 +1,137 @@
+package analysis
+
+import "fmt"
+
+// DefaultMaxNestingDepth is the deepest symbol nesting kept by default.
+// Real code rarely nests past a handful of levels; the limit exists to keep
+// pathological files (thousands of nested classes or functions) from blowing
+// up memory and every recursive walk over the symbol tree.
+const DefaultMaxNestingDepth = 64
+
+// SetMaxNestingDepth sets the nesting limit applied by Analyze.
+// A value of 0 or less restores DefaultMaxNestingDepth.
+func SetMaxNestingDepth(depth int) {
+	defaultRegistry.SetMaxNestingDepth(depth)
+}
+
+// SetMaxNestingDepth sets the deepest symbol nesting kept by Parse.
+// A value of 0 or less restores DefaultMaxNestingDepth.
+func (r *ParserRegistry) SetMaxNestingDepth(depth int) {
+	if depth <= 0 {
+		depth = DefaultMaxNestingDepth
+	}
+	r.mu.Lock()
+	r.maxDepth = depth
+	r.mu.Unlock()
+}
+
+// syntaxLevelsPerNesting is how many syntax tree levels the tree-sitter
+// walkers may descend per level of symbol nesting. One symbol sits inside
+// another through several nodes (declaration, body, block, statement), so
+// the walkers stop at this multiple of the nesting limit.
+const syntaxLevelsPerNesting = 8
+
+// nestingGuard bounds how deep a parser's recursive walkers descend into
+// the syntax tree, so a pathologically nested file cannot recurse without
+// limit. Tree-sitter parsers embed it; the registry sets the limit before
+// each Parse and records a warning when a walker was cut off.
+type nestingGuard struct {
+	maxLevels int
+	level     int
+	cutOff    bool
+}
+
+// nestingLimited is a parser whose walkers a nestingGuard bounds.
+type nestingLimited interface {
+	setNestingLimit(maxDepth int)
+	nestingCutOff() bool
+}
+
+// setNestingLimit allows the walkers syntaxLevelsPerNesting levels per
+// symbol level of maxDepth and resets the guard for the next Parse.
+func (g *nestingGuard) setNestingLimit(maxDepth int) {
+	g.maxLevels = maxDepth * syntaxLevelsPerNesting
+	g.level = 0
+	g.cutOff = false
+}
+
+// nestingCutOff reports whether the last Parse skipped a subtree.
+func (g *nestingGuard) nestingCutOff() bool {
+	return g.cutOff
+}
+
+// descend enters one syntax level, reporting false at the limit; the
+// subtree is then skipped. Every true must be paired with an ascend.
+func (g *nestingGuard) descend() bool {
+	if g.maxLevels > 0 && g.level >= g.maxLevels {
+		g.cutOff = true
+		return false
+	}
+	g.level++
+	return true
+}
+
+// ascend leaves the level entered by descend.
+func (g *nestingGuard) ascend() {
+	g.level--
+}
+
+// limitNesting caps the symbol tree of fa at maxDepth levels, top-level
+// symbols being level 1. Symbols nested deeper are not dropped: they are
+// lifted to the deepest allowed level, next to their ancestor there, and a
+// warning recording how many were lifted is added to fa.
+func limitNesting(fa *FileAnalysis, maxDepth int) {
+	if fa == nil || maxDepth <= 0 {
+		return
+	}
+	type level struct {
+		symbols *[]Symbol
+		depth   int
+	}
+	lifted := 0
+	stack := []level{{&fa.Symbols, 1}}
+	for len(stack) > 0 {
+		l := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if l.depth == maxDepth {
+			var n int
+			*l.symbols, n = flattenDescendants(*l.symbols)
+			lifted += n
+			continue
+		}
+		for i := range *l.symbols {
+			if sym := &(*l.symbols)[i]; len(sym.Children) > 0 {
+				stack = append(stack, level{&sym.Children, l.depth + 1})
+			}
+		}
+	}
+	if lifted > 0 {
+		fa.Warnings = append(fa.Warnings, fmt.Sprintf(
+			"symbol nesting exceeds %d levels; flattened %d deeper symbols", maxDepth, lifted))
+	}
+}
+
+// flattenDescendants returns symbols and all of their descendants as one
+// flat list in source order, along with how many symbols were lifted out of
+// deeper levels. It walks iteratively so depth cannot exhaust the stack.
+func flattenDescendants(symbols []Symbol) ([]Symbol, int) {
+	var flat []Symbol
+	lifted := 0
+	// Symbols are pushed in reverse so they pop in source order
+	stack := make([]Symbol, 0, len(symbols))
+	for i := len(symbols) - 1; i >= 0; i-- {
+		stack = append(stack, symbols[i])
+	}
+	for len(stack) > 0 {
+		sym := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nested := sym.Children
+		sym.Children = nil
+		flat = append(flat, sym)
+		lifted += len(nested)
+		for i := len(nested) - 1; i >= 0; i-- {
+			stack = append(stack, nested[i])
+		}
+	}
+	return flat, lifted
+}
@@ -0,0 +1,150 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deepParser returns a single chain of classes nested depth levels deep.
+type deepParser struct{ depth int }
+
+func (p deepParser) Language() Language { return LangPython }
+
+func (p deepParser) Parse(_ []byte, filePath string) (*FileAnalysis, error) {
+	var sym Symbol
+	for i := p.depth; i >= 1; i-- {
+		outer := Symbol{Name: fmt.Sprintf("C%d", i), Kind: KindClass, LineStart: i}
+		if i < p.depth {
+			outer.Children = []Symbol{sym}
+		}
+		sym = outer
+	}
+	return &FileAnalysis{Path: filePath, Language: string(LangPython), Symbols: []Symbol{sym}}, nil
+}
+
+func nestingDepth(symbols []Symbol) int {
+	depth := 0
+	for len(symbols) > 0 {
+		depth++
+		var next []Symbol
+		for _, s := range symbols {
+			next = append(next, s.Children...)
+		}
+		symbols = next
+	}
+	return depth
+}
+
+func countNested(symbols []Symbol) int {
+	n := len(symbols)
+	for _, s := range symbols {
+		n += countNested(s.Children)
+	}
+	return n
+}
+
+func TestParseLimitsNestingDepth(t *testing.T) {
+	reg := &ParserRegistry{parsers: make(map[Language][]parserEntry)}
+	reg.RegisterWithPriority(deepParser{depth: 5000}, PriorityTreeSitter)
+	reg.SetMaxNestingDepth(8)
+
+	done := make(chan *FileAnalysis, 1)
+	go func() {
+		fa, err := reg.Parse(nil, "deep.py")
+		if err != nil {
+			t.Errorf("Parse() error: %v", err)
+		}
+		done <- fa
+	}()
+
+	var fa *FileAnalysis
+	select {
+	case fa = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Parse() did not finish on a deeply nested file")
+	}
+	if fa == nil {
+		return
+	}
+
+	if got := nestingDepth(fa.Symbols); got != 8 {
+		t.Errorf("nesting depth = %d, want 8", got)
+	}
+	if got := countNested(fa.Symbols); got != 5000 {
+		t.Errorf("symbol count = %d, want all 5000 symbols kept", got)
+	}
+	if len(fa.Warnings) != 1 || !strings.Contains(fa.Warnings[0], "exceeds 8 levels") {
+		t.Errorf("expected a nesting warning, got %q", fa.Warnings)
+	}
+
+	// A file within the default limit is left untouched
+	reg = &ParserRegistry{parsers: make(map[Language][]parserEntry)}
+	reg.RegisterWithPriority(deepParser{depth: 10}, PriorityTreeSitter)
+	reg.SetMaxNestingDepth(0)
+	fa, err := reg.Parse(nil, "deep.py")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got := nestingDepth(fa.Symbols); got != 10 || len(fa.Warnings) != 0 {
+		t.Errorf("depth = %d, warnings = %q; want 10 levels and no warnings", got, fa.Warnings)
+	}
+}
+
+func TestParseStopsDescendingDeepSyntax(t *testing.T) {
+	var py strings.Builder
+	py.WriteString("top_call()\n")
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&py, "%sdef f%d():\n", strings.Repeat(" ", i), i)
+	}
+	fmt.Fprintf(&py, "%sdeep_call()\n", strings.Repeat(" ", 300))
+
+	var goSrc strings.Builder
+	goSrc.WriteString("package deep\n\nfunc Top() {\n\ttopCall()\n")
+	for i := 0; i < 2000; i++ {
+		goSrc.WriteString("func() {\n")
+	}
+	goSrc.WriteString("deepCall()\n")
+	for i := 0; i < 2000; i++ {
+		goSrc.WriteString("}()\n")
+	}
+	goSrc.WriteString("}\n")
+
+	tests := []struct {
+		name, path, src, top, deep string
+	}{
+		{"python", "deep.py", py.String(), "top_call", "deep_call"},
+		{"go", "deep.go", goSrc.String(), "topCall", "deepCall"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := NewParserRegistry()
+			reg.SetEnableLSP(false)
+			reg.SetMaxNestingDepth(8)
+			fa, err := reg.Parse([]byte(tt.src), tt.path)
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			calls := make(map[string]bool)
+			for _, r := range fa.Relationships {
+				if r.Kind == RelCall {
+					calls[r.TargetSymbol] = true
+				}
+			}
+			if !calls[tt.top] {
+				t.Errorf("expected the shallow call %s, got %v", tt.top, calls)
+			}
+			if calls[tt.deep] {
+				t.Errorf("expected the walk to stop before %s", tt.deep)
+			}
+			warned := false
+			for _, w := range fa.Warnings {
+				warned = warned || strings.Contains(w, "syntax nesting exceeds 64 levels")
+			}
+			if !warned {
+				t.Errorf("expected a syntax nesting warning, got %q", fa.Warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package analysis
+
+import "strings"
+
+// OverloadKey returns the name of a callable qualified by its parameter types,
+// e.g. "add(int,int)", so overloads that share a name get distinct keys.
+// The types are taken from the signature; parameter names, defaults,
+// annotations, and modifiers do not contribute, except const on pointer and
+// reference parameters, which selects a different overload.
+func OverloadKey(name, signature string) string {
+	return name + "(" + strings.Join(ParamTypes(signature), ",") + ")"
+}
+
+// SymbolKey returns the key a symbol is indexed under: the OverloadKey of
+// functions, methods, and constructors, and the name of anything else.
+func SymbolKey(sym Symbol) string {
+	if isCallable(sym.Kind) {
+		return OverloadKey(sym.Name, sym.Signature)
+	}
+	return sym.Name
+}
+
+// IsOverloadKey reports whether name is written as an overload key, with
+// its parameter types, rather than as a bare name.
+func IsOverloadKey(name string) bool {
+	return strings.HasSuffix(name, ")") && strings.Contains(name, "(")
+}
+
+// NormalizeOverloadKey rewrites a key typed by hand, such as
+// "add(int a, int b)", to the form OverloadKey produces.
+func NormalizeOverloadKey(key string) string {
+	open := strings.Index(key, "(")
+	if open < 0 {
+		return key
+	}
+	return OverloadKey(strings.TrimSpace(key[:open]), key[open:])
+}
+
+// ParamTypes extracts the parameter types from a signature. It understands
+// C-family declarations ("int count", "const char* s") as well as
+// name-colon-type parameters ("count: Int"). A signature without a
+// parameter list yields nil.
+func ParamTypes(signature string) []string {
+	open := strings.Index(signature, "(")
+	if open < 0 {
+		return nil
+	}
+	depth := 0
+	end := -1
+	for i := open; i < len(signature) && end < 0; i++ {
+		switch signature[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return nil
+	}
+
+	var types []string
+	for _, param := range splitTopLevel(signature[open+1 : end]) {
+		if t := paramType(param); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// paramModifiers are keywords that qualify a parameter without changing the
+// overload it selects. const is handled apart: it matters only through a
+// pointer or reference.
+var paramModifiers = map[string]bool{
+	"final": true, "ref": true, "out": true, "in": true,
+	"params": true, "this": true, "vararg": true, "inout": true, "var": true, "val": true,
+}
+
+func paramType(param string) string {
+	if idx := strings.Index(param, "="); idx >= 0 {
+		param = param[:idx]
+	}
+	param = strings.TrimSpace(param)
+	if param == "" || param == "void" {
+		return ""
+	}
+	if idx := strings.Index(param, ":"); idx >= 0 && !strings.Contains(param, "::") {
+		return strings.Join(strings.Fields(param[idx+1:]), "")
+	}
+
+	var fields []string
+	isConst := false
+	for _, f := range strings.Fields(param) {
+		if f == "const" {
+			isConst = true
+			continue
+		}
+		if strings.HasPrefix(f, "@") || (strings.HasPrefix(f, "[") && strings.HasSuffix(f, "]")) || paramModifiers[f] {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	// The trailing field is the parameter name unless the type stands alone,
+	// as in a C++ declaration without parameter names.
+	if len(fields) > 1 {
+		name := fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+		// Pointer and reference sigils bind to the name in "char *s"
+		fields[len(fields)-1] += strings.TrimRight(name, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_[]")
+	}
+	t := strings.Join(fields, " ")
+	// f(const T&) and f(T&) are distinct overloads; f(const T) and f(T) are not
+	if isConst && strings.ContainsAny(t, "*&") {
+		t = "const " + t
+	}
+	return t
+}
+
+// splitTopLevel splits a parameter list on commas that are not nested inside
+// generics, parentheses, or brackets.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '<', '(', '[', '{':
+			depth++
+		case '>':
+			if i == 0 || s[i-1] != '-' { // not an arrow in a function type
+				depth--
+			}
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
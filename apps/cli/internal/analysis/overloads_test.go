@@ -0,0 +1,39 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParamTypes(t *testing.T) {
+	tests := []struct {
+		sig  string
+		want []string
+	}{
+		{"int add(int a, int b)", []string{"int", "int"}},
+		{"void run()", nil},
+		{"void put(Map<String, Integer> m, final String... keys)", []string{"Map<String, Integer>", "String..."}},
+		{"int copy(const char *src, std::vector<int>& out) const", []string{"const char*", "std::vector<int>&"}},
+		{"void set(const int n, const Widget& w)", []string{"int", "const Widget&"}},
+		{"void Log([CallerMemberName] string caller = \"\", params object[] args)", []string{"string", "object[]"}},
+		{"fun apply(x: Int, f: (Int) -> Unit): Unit", []string{"Int", "(Int)->Unit"}},
+		{"int area(int, int)", []string{"int", "int"}},
+	}
+	for _, tt := range tests {
+		if got := ParamTypes(tt.sig); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParamTypes(%q) = %q, want %q", tt.sig, got, tt.want)
+		}
+	}
+}
+
+func TestOverloadKeyConstReference(t *testing.T) {
+	if OverloadKey("f", "void f(const T& x)") == OverloadKey("f", "void f(T& x)") {
+		t.Error("f(const T&) and f(T&) got the same overload key")
+	}
+	if OverloadKey("f", "void f(const int x)") != OverloadKey("f", "void f(int x)") {
+		t.Error("f(const int) and f(int) got different overload keys")
+	}
+	if got := NormalizeOverloadKey("add(int a, int b)"); got != "add(int,int)" {
+		t.Errorf("NormalizeOverloadKey() = %q, want add(int,int)", got)
+	}
+}
@@ -0,0 +1,323 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parameter is one parameter of a callable, read from its signature.
+type Parameter struct {
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Default  string `json:"default,omitempty"` // "?" for TypeScript optional parameters
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// String renders the parameter as "name type", adding " = default".
+func (p Parameter) String() string {
+	s := strings.TrimSpace(p.Name + " " + p.Type)
+	if p.Default != "" && p.Default != "?" {
+		s += " = " + p.Default
+	}
+	return s
+}
+
+// optional reports whether callers may leave the parameter out.
+func (p Parameter) optional() bool {
+	return p.Default != "" || p.Variadic
+}
+
+// ParseParameters reads the parameter list of a signature: the first
+// parenthesized group, or for Go methods the one after the receiver. Each
+// parameter is read in the style its text shows: "name: Type" (Python,
+// TypeScript, Rust, Kotlin, Swift), "name Type" (Go, where "a, b int" gives
+// both a and b the type int), or "Type name" (C-family, Java, C#, PHP). A
+// signature without a parameter list yields nil.
+func ParseParameters(signature string, lang Language) []Parameter {
+	if lang == LangGo && strings.HasPrefix(strings.TrimSpace(signature), "(") {
+		// Skip the receiver: "(s *Server) Fetch(id string)"
+		_, end := paramList(signature)
+		if end < 0 {
+			return nil
+		}
+		signature = signature[end+1:]
+	}
+	list, end := paramList(signature)
+	if end < 0 {
+		return nil
+	}
+
+	var params []Parameter
+	for _, raw := range splitTopLevel(list) {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || raw == "void" {
+			continue
+		}
+		params = append(params, parseParameter(raw, lang))
+	}
+	if lang == LangGo {
+		// In "a, b int" the type is written once, after the last name.
+		for i := len(params) - 2; i >= 0; i-- {
+			if params[i].Type == "" && params[i+1].Type != "" {
+				params[i].Type = params[i+1].Type
+				params[i].Variadic = params[i+1].Variadic
+			}
+		}
+	}
+	return params
+}
+
+// paramList returns the contents of the first balanced parenthesized group
+// and the index of its closing parenthesis, or -1 if there is none.
+func paramList(signature string) (string, int) {
+	open := strings.Index(signature, "(")
+	if open < 0 {
+		return "", -1
+	}
+	depth := 0
+	for i := open; i < len(signature); i++ {
+		switch signature[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return signature[open+1 : i], i
+			}
+		}
+	}
+	return "", -1
+}
+
+func parseParameter(raw string, lang Language) Parameter {
+	var p Parameter
+	if idx := defaultIndex(raw); idx >= 0 {
+		p.Default = strings.TrimSpace(raw[idx+1:])
+		raw = strings.TrimSpace(raw[:idx])
+	}
+
+	var fields []string
+	for _, f := range strings.Fields(raw) {
+		if strings.HasPrefix(f, "@") || (strings.HasPrefix(f, "[") && strings.HasSuffix(f, "]")) || paramModifiers[f] {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	joined := strings.Join(fields, " ")
+
+	switch {
+	case colonIndex(joined) >= 0:
+		idx := colonIndex(joined)
+		names := strings.Fields(joined[:idx])
+		if len(names) > 0 {
+			p.Name = names[len(names)-1] // Swift: "label name: Type"
+		}
+		p.Type = strings.TrimSpace(joined[idx+1:])
+	case len(fields) == 0:
+	case len(fields) == 1:
+		p.Name = fields[0]
+	case lang == LangGo:
+		p.Name = fields[0]
+		p.Type = strings.Join(fields[1:], " ")
+	default:
+		name := fields[len(fields)-1]
+		typ := strings.Join(fields[:len(fields)-1], " ")
+		// Pointer and reference sigils bind to the name in "char *s"
+		trimmed := strings.TrimLeft(name, "*&")
+		typ += name[:len(name)-len(trimmed)]
+		p.Name, p.Type = trimmed, typ
+	}
+
+	if strings.HasSuffix(p.Name, "?") {
+		p.Name = strings.TrimSuffix(p.Name, "?")
+		if p.Default == "" {
+			p.Default = "?"
+		}
+	}
+	for _, prefix := range []string{"...", "**", "*"} {
+		if strings.HasPrefix(p.Name, prefix) && len(p.Name) > len(prefix) {
+			p.Name = strings.TrimPrefix(p.Name, prefix)
+			p.Variadic = true
+			break
+		}
+	}
+	if strings.HasPrefix(p.Type, "...") || strings.HasSuffix(p.Type, "...") {
+		p.Type = strings.Trim(p.Type, ".")
+		p.Variadic = true
+	}
+	return p
+}
+
+// defaultIndex returns the index of the "=" starting a default value, or -1.
+// Comparison and arrow operators inside the default are not mistaken for it.
+func defaultIndex(param string) int {
+	for i := 0; i < len(param); i++ {
+		if param[i] != '=' {
+			continue
+		}
+		if i+1 < len(param) && (param[i+1] == '=' || param[i+1] == '>') {
+			return -1
+		}
+		if i > 0 && strings.ContainsRune("!<>:", rune(param[i-1])) {
+			return -1
+		}
+		return i
+	}
+	return -1
+}
+
+// colonIndex returns the index of the colon separating a name from its type,
+// or -1. C++ scope operators ("std::string") are not separators.
+func colonIndex(param string) int {
+	for i := 0; i < len(param); i++ {
+		if param[i] != ':' {
+			continue
+		}
+		if (i+1 < len(param) && param[i+1] == ':') || (i > 0 && param[i-1] == ':') {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// ParamChangeKind describes how one parameter differs between two versions
+// of a signature.
+type ParamChangeKind string
+
+const (
+	ParamAdded          ParamChangeKind = "added"
+	ParamRemoved        ParamChangeKind = "removed"
+	ParamRetyped        ParamChangeKind = "retyped"
+	ParamRenamed        ParamChangeKind = "renamed"
+	ParamMoved          ParamChangeKind = "moved"
+	ParamDefaultChanged ParamChangeKind = "default_changed"
+)
+
+// ParamChange is a parameter-level difference within a signature change.
+// Breaking is set when existing callers may no longer compile or may bind
+// their arguments differently.
+type ParamChange struct {
+	Kind     ParamChangeKind `json:"kind"`
+	Name     string          `json:"name"`
+	Old      *Parameter      `json:"old,omitempty"`
+	New      *Parameter      `json:"new,omitempty"`
+	Breaking bool            `json:"breaking"`
+}
+
+// String describes the change for a changelog, e.g. "added timeout int" or
+// "retyped id: string → int".
+func (c ParamChange) String() string {
+	switch c.Kind {
+	case ParamAdded:
+		return "added " + c.New.String()
+	case ParamRemoved:
+		return "removed " + c.Old.String()
+	case ParamRetyped:
+		return fmt.Sprintf("retyped %s: %s → %s", c.Name, orNone(c.Old.Type), orNone(c.New.Type))
+	case ParamRenamed:
+		return fmt.Sprintf("renamed %s → %s", c.Old.Name, c.New.Name)
+	case ParamMoved:
+		return "moved " + c.Name
+	default:
+		return fmt.Sprintf("default of %s: %s → %s", c.Name, orNone(c.Old.Default), orNone(c.New.Default))
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// namedArgLanguages let callers pass arguments by parameter name, which makes
+// renaming a parameter breaking.
+var namedArgLanguages = map[Language]bool{
+	LangPython: true, LangKotlin: true, LangSwift: true, LangScala: true, LangCSharp: true, LangPHP: true,
+}
+
+// DiffParameters compares the parameters of two versions of a signature.
+// Parameters are matched by name; when names are missing, or a parameter
+// was renamed in place, they are matched by position. The changes are in
+// the new signature's order, removals last.
+func DiffParameters(oldSig, newSig string, lang Language) []ParamChange {
+	oldParams := ParseParameters(oldSig, lang)
+	newParams := ParseParameters(newSig, lang)
+
+	oldIndex := make(map[string]int)
+	for i, p := range oldParams {
+		if p.Name != "" {
+			oldIndex[p.Name] = i
+		}
+	}
+	match := make([]int, len(newParams)) // new index -> old index, or -1
+	matchedOld := make([]bool, len(oldParams))
+	for i, p := range newParams {
+		match[i] = -1
+		if j, ok := oldIndex[p.Name]; ok && p.Name != "" && !matchedOld[j] {
+			match[i] = j
+			matchedOld[j] = true
+		}
+	}
+	// Unmatched parameters in the same position are the same parameter
+	// renamed (or, without names, the same parameter).
+	for i := range newParams {
+		if match[i] < 0 && i < len(oldParams) && !matchedOld[i] {
+			match[i] = i
+			matchedOld[i] = true
+		}
+	}
+
+	// A parameter matched by name has moved when its order relative to the
+	// other matched parameters changed; insertions alone do not move it.
+	moved := make([]bool, len(newParams))
+	var rank []int // new indexes of name-matched parameters, in new order
+	for i, j := range match {
+		if j >= 0 && oldParams[j].Name == newParams[i].Name {
+			rank = append(rank, i)
+		}
+	}
+	for a := range rank {
+		for b := a + 1; b < len(rank); b++ {
+			if match[rank[a]] > match[rank[b]] {
+				moved[rank[a]], moved[rank[b]] = true, true
+			}
+		}
+	}
+
+	var changes []ParamChange
+	for i := range newParams {
+		n := newParams[i]
+		j := match[i]
+		if j < 0 {
+			changes = append(changes, ParamChange{
+				Kind: ParamAdded, Name: n.Name, New: &n,
+				// An optional parameter at the end leaves existing calls valid
+				Breaking: !n.optional() || i < len(oldParams),
+			})
+			continue
+		}
+		o := oldParams[j]
+		if o.Name != n.Name {
+			changes = append(changes, ParamChange{Kind: ParamRenamed, Name: n.Name, Old: &o, New: &n, Breaking: namedArgLanguages[lang]})
+		} else if moved[i] {
+			changes = append(changes, ParamChange{Kind: ParamMoved, Name: n.Name, Old: &o, New: &n, Breaking: true})
+		}
+		if o.Type != n.Type || o.Variadic != n.Variadic {
+			changes = append(changes, ParamChange{Kind: ParamRetyped, Name: n.Name, Old: &o, New: &n, Breaking: true})
+		}
+		if o.Default != n.Default {
+			// Dropping a default makes the argument required; adding or
+			// changing one keeps existing calls valid.
+			changes = append(changes, ParamChange{Kind: ParamDefaultChanged, Name: n.Name, Old: &o, New: &n, Breaking: n.Default == "" && !n.Variadic})
+		}
+	}
+	for j := range oldParams {
+		if !matchedOld[j] {
+			o := oldParams[j]
+			changes = append(changes, ParamChange{Kind: ParamRemoved, Name: o.Name, Old: &o, Breaking: true})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSymbolsParameterDetail(t *testing.T) {
+	oldSrc := []byte(`package svc
+
+func Fetch(id string, retries int) error {
+	return nil
+}
+`)
+	newSrc := []byte(`package svc
+
+func Fetch(id int, retries int, timeout int) error {
+	return nil
+}
+`)
+	oldFA, _ := Analyze(oldSrc, "svc.go")
+	newFA, _ := Analyze(newSrc, "svc.go")
+	changes := DiffSymbols("svc.go", oldFA, oldSrc, newFA, newSrc)
+	if len(changes) != 1 || changes[0].Change != SymbolSignatureChanged {
+		t.Fatalf("changes = %+v, want one signature change", changes)
+	}
+
+	var got []string
+	for _, p := range changes[0].Params {
+		if !p.Breaking {
+			t.Errorf("%s should be breaking", p)
+		}
+		got = append(got, p.String())
+	}
+	want := []string{"retyped id: string → int", "added timeout int"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("params = %q, want %q", got, want)
+	}
+}
+
+func TestDiffParameters(t *testing.T) {
+	tests := []struct {
+		name           string
+		lang           Language
+		oldSig, newSig string
+		want           []string // String() plus "!" when breaking
+	}{
+		{"optional param appended", LangPython, "def get(url, retries=3)", "def get(url, retries=3, timeout: int = 30)",
+			[]string{"added timeout int = 30"}},
+		{"default changed and dropped", LangPython, "def get(url, retries=3, verbose=False)", "def get(url, retries=5, verbose)",
+			[]string{"default of retries: 3 → 5", "default of verbose: False → (none)!"}},
+		{"removed", LangTypeScript, "fetch(url: string, retries?: number)", "fetch(url: string)",
+			[]string{"removed retries number!"}},
+		{"renamed without named args", LangTypeScript, "fetch(url: string)", "fetch(href: string)",
+			[]string{"renamed url → href"}},
+		{"renamed with named args", LangKotlin, "fun fetch(url: String)", "fun fetch(href: String)",
+			[]string{"renamed url → href!"}},
+		{"reordered", LangJava, "void copy(String from, String to)", "void copy(String to, String from)",
+			[]string{"moved to!", "moved from!"}},
+		{"go grouped names and receiver", LangGo, "(s *Server) Move(x, y int)", "(s *Server) Move(x, y float64)",
+			[]string{"retyped x: int → float64!", "retyped y: int → float64!"}},
+		{"inserted before existing params", LangGo, "Run(name string)", "Run(ctx context.Context, name string)",
+			[]string{"added ctx context.Context!"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range DiffParameters(tt.oldSig, tt.newSig, tt.lang) {
+				s := c.String()
+				if c.Breaking {
+					s += "!"
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffParameters() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSymbolsTypeParams(t *testing.T) {
+	oldSrc := []byte("package p\n\ntype Cache struct {\n\tsize int\n}\n\nfunc Get[K comparable](k K) {}\n")
+	newSrc := []byte("package p\n\ntype Cache[V any] struct {\n\tsize int\n}\n\nfunc Get[K comparable](k K) {}\n")
+	oldFA, _ := Analyze(oldSrc, "p.go")
+	newFA, _ := Analyze(newSrc, "p.go")
+	changes := DiffSymbols("p.go", oldFA, oldSrc, newFA, newSrc)
+	if len(changes) != 1 || changes[0].Name != "Cache" || changes[0].Change != SymbolSignatureChanged {
+		t.Fatalf("changes = %+v, want one signature change to Cache", changes)
+	}
+	tps := changes[0].TypeParams
+	if len(tps) != 1 || tps[0].String() != "added V any" || !tps[0].Breaking {
+		t.Errorf("type params = %+v, want one breaking \"added V any\"", tps)
+	}
+}
+
+func TestDiffTypeParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new []TypeParam
+		want     []string
+	}{
+		{"unchanged", []TypeParam{{"T", "any"}}, []TypeParam{{"T", "any"}}, nil},
+		{"added in front", []TypeParam{{"T", "any"}}, []TypeParam{{"K", "comparable"}, {"T", "any"}}, []string{"added K comparable"}},
+		{"removed", []TypeParam{{"T", "any"}, {"U", ""}}, []TypeParam{{"T", "any"}}, []string{"removed U"}},
+		{"constraint tightened", []TypeParam{{"T", "any"}}, []TypeParam{{"T", "comparable"}}, []string{"retyped T: any → comparable"}},
+		{"swapped", []TypeParam{{"K", ""}, {"V", ""}}, []TypeParam{{"V", ""}, {"K", ""}}, []string{"moved V", "moved K"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range DiffTypeParams(tt.old, tt.new) {
+				if !c.Breaking {
+					t.Errorf("%s should be breaking", c)
+				}
+				got = append(got, c.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffTypeParams = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,10 @@ package analysis
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/logger"
 )
 
 // Parser Priority Strategy (IMPLEMENTED):
@@ -20,7 +24,7 @@ import (
 //    - Requires C compiler (gcc/MinGW on Windows)
 //
 // 3. Regex: Last resort for basic symbol extraction (works everywhere)
-//    - Currently: Dart, CUE
+//    - Currently: Dart, CUE, Nim
 //    - Good for simple languages or when no better option exists
 
 // Parser is the interface implemented by all language-specific parsers.
@@ -49,14 +53,80 @@ type LSPParser interface {
 type parserEntry struct {
 	parser   Parser
 	priority ParserPriority
+	pool     *parserPool
+}
+
+// parserPool lends instances of one parser to Parse calls. Parser instances
+// hold tree-sitter state that is not safe for concurrent use, so each call
+// takes one for itself. A pool with a constructor makes as many instances as
+// there are concurrent calls; one without, for a parser registered as an
+// instance, lends that instance to one call at a time.
+type parserPool struct {
+	mu        sync.Mutex
+	available *sync.Cond
+	idle      []Parser
+	newParser func() Parser
+}
+
+func newParserPool(p Parser, newParser func() Parser) *parserPool {
+	pool := &parserPool{idle: []Parser{p}, newParser: newParser}
+	pool.available = sync.NewCond(&pool.mu)
+	return pool
+}
+
+// get takes an idle instance, makes one, or waits for one to be put back.
+func (p *parserPool) get() Parser {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.idle) == 0 {
+		if p.newParser != nil {
+			return p.newParser()
+		}
+		p.available.Wait()
+	}
+	parser := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+	return parser
+}
+
+// put returns an instance taken with get.
+func (p *parserPool) put(parser Parser) {
+	p.mu.Lock()
+	p.idle = append(p.idle, parser)
+	p.mu.Unlock()
+	p.available.Signal()
+}
+
+// parserFactory adapts a parser constructor to the pool.
+func parserFactory[P Parser](newParser func() P) func() Parser {
+	return func() Parser { return newParser() }
 }
 
 // ParserRegistry manages all registered parsers and their priorities.
 type ParserRegistry struct {
-	parsers   map[Language][]parserEntry
-	rootPath  string
-	enableLSP bool
-	debugMode bool
+	parsers     map[Language][]parserEntry
+	rootPath    string
+	enableLSP   bool
+	debugMode   bool
+	maxDepth    int
+	findSecrets bool
+	// genericFallback parses text files in unknown languages with the
+	// GenericParser instead of skipping them.
+	genericFallback bool
+	// processors add framework relationships after parsing; see
+	// RelationshipProcessor.
+	processors []RelationshipProcessor
+	// callExclusions are project-specific call targets to drop on top of
+	// the builtins; see SetCallExclusions.
+	callExclusions map[string][]string
+	// strictness tunes the heuristic parsers per language; see
+	// SetStrictness.
+	strictness map[Language]Strictness
+
+	// mu guards the settings above: Parse reads them, so concurrent calls
+	// share it, while the setters change them. Parser instances are not
+	// shared between calls; see parserPool.
+	mu sync.RWMutex
 }
 
 // NewParserRegistry creates a new registry with default parsers.
@@ -65,6 +135,7 @@ func NewParserRegistry() *ParserRegistry {
 		parsers:   make(map[Language][]parserEntry),
 		enableLSP: true,
 		debugMode: false,
+		maxDepth:  DefaultMaxNestingDepth,
 	}
 	reg.registerDefaults()
 	return reg
@@ -77,6 +148,7 @@ func NewParserRegistryWithPath(rootPath string) *ParserRegistry {
 		rootPath:  rootPath,
 		enableLSP: true,
 		debugMode: false,
+		maxDepth:  DefaultMaxNestingDepth,
 	}
 	reg.registerDefaults()
 	return reg
@@ -87,61 +159,87 @@ func (r *ParserRegistry) SetDebugMode(enabled bool) {
 	r.debugMode = enabled
 }
 
+// SetFindSecrets enables or disables reporting likely hardcoded secrets
+// as KindFinding symbols (see FindSecrets).
+func (r *ParserRegistry) SetFindSecrets(enabled bool) {
+	r.mu.Lock()
+	r.findSecrets = enabled
+	r.mu.Unlock()
+}
+
+// SetGenericFallback enables or disables the generic fallback parser for
+// text files no language-specific parser handles.
+func (r *ParserRegistry) SetGenericFallback(enabled bool) {
+	r.mu.Lock()
+	r.genericFallback = enabled
+	r.mu.Unlock()
+}
+
 // SetEnableLSP enables or disables LSP parsers
 func (r *ParserRegistry) SetEnableLSP(enabled bool) {
 	r.enableLSP = enabled
 }
 
 func (r *ParserRegistry) registerDefaults() {
+	// Framework rules, run after whichever parser handles the file
+	r.processors = append(r.processors, FlaskRoutes{}, SpringInjection{})
+
 	// LSP parsers - Priority 1 (when available)
 	r.RegisterWithPriority(NewGoLSPParser(r.rootPath), PriorityLSP)
 
 	// Tree-sitter parsers - Priority 2
-	r.RegisterWithPriority(NewGoParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewJavaScriptParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewTypeScriptParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewPythonParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewRustParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewJavaParser(), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewGoParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewJavaScriptParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewTypeScriptParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewPythonParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewRustParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewJavaParser), PriorityTreeSitter)
 
 	// C family
-	r.RegisterWithPriority(NewCParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewCPPParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewCSharpParser(), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewCParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewCPPParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewCSharpParser), PriorityTreeSitter)
 
 	// Backend languages
-	r.RegisterWithPriority(NewRubyParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewPHPParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewKotlinParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewScalaParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewSwiftParser(), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewRubyParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewPHPParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewKotlinParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewScalaParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewSwiftParser), PriorityTreeSitter)
 
 	// Infrastructure/scripting
-	r.RegisterWithPriority(NewBashParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewSQLParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewDockerfileParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewHCLParser(), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewBashParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewSQLParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewDockerfileParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewHCLParser), PriorityTreeSitter)
 
 	// Config/web
-	r.RegisterWithPriority(NewHTMLParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewCSSParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewYAMLParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewTOMLParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewJSONParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewMarkdownParser(), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewHTMLParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewCSSParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewYAMLParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewTOMLParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewJSONParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewMarkdownParser), PriorityTreeSitter)
 
 	// Other languages
-	r.RegisterWithPriority(NewElixirParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewLuaParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewGroovyParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewSvelteParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewOCamlParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewElmParser(), PriorityTreeSitter)
-	r.RegisterWithPriority(NewProtobufParser(), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewElixirParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewLuaParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewGroovyParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewSvelteParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewOCamlParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewElmParser), PriorityTreeSitter)
+	r.registerFactory(parserFactory(NewProtobufParser), PriorityTreeSitter)
 
 	// Regex-based parsers - Priority 3
-	r.RegisterWithPriority(NewDartParser(), PriorityRegex)
-	r.RegisterWithPriority(NewCUEParser(), PriorityRegex)
+	r.registerFactory(parserFactory(NewDartParser), PriorityRegex)
+	r.registerFactory(parserFactory(NewCUEParser), PriorityRegex)
+	r.registerFactory(parserFactory(NewNimParser), PriorityRegex)
+}
+
+// registerFactory registers the parser newParser makes, making more of it
+// when Parse calls overlap.
+func (r *ParserRegistry) registerFactory(newParser func() Parser, priority ParserPriority) {
+	r.register(newParser(), newParser, priority)
 }
 
 // Register adds a parser to the registry with default Tree-sitter priority.
@@ -149,12 +247,18 @@ func (r *ParserRegistry) Register(p Parser) {
 	r.RegisterWithPriority(p, PriorityTreeSitter)
 }
 
-// RegisterWithPriority registers a parser with a specific priority
+// RegisterWithPriority registers a parser with a specific priority. Parse
+// uses the instance for one file at a time.
 func (r *ParserRegistry) RegisterWithPriority(p Parser, priority ParserPriority) {
+	r.register(p, nil, priority)
+}
+
+func (r *ParserRegistry) register(p Parser, newParser func() Parser, priority ParserPriority) {
 	lang := p.Language()
 	r.parsers[lang] = append(r.parsers[lang], parserEntry{
 		parser:   p,
 		priority: priority,
+		pool:     newParserPool(p, newParser),
 	})
 
 	// Sort by priority (lower number = higher priority)
@@ -171,13 +275,23 @@ func (r *ParserRegistry) RegisterWithPriority(p Parser, priority ParserPriority)
 
 // GetParser returns the highest priority parser available for the given language.
 func (r *ParserRegistry) GetParser(lang Language) (Parser, bool) {
+	i, ok := r.entryIndex(lang)
+	if !ok {
+		return nil, false
+	}
+	return r.parsers[lang][i].parser, true
+}
+
+// entryIndex returns the index in r.parsers[lang] of the highest priority
+// parser available for lang.
+func (r *ParserRegistry) entryIndex(lang Language) (int, bool) {
 	entries, ok := r.parsers[lang]
 	if !ok || len(entries) == 0 {
-		return nil, false
+		return 0, false
 	}
 
 	// Try parsers in priority order
-	for _, entry := range entries {
+	for i, entry := range entries {
 		// Skip LSP parsers if disabled
 		if entry.priority == PriorityLSP && !r.enableLSP {
 			continue
@@ -200,11 +314,11 @@ func (r *ParserRegistry) GetParser(lang Language) (Parser, bool) {
 				r.getPriorityName(entry.priority), entry.priority, lang)
 		}
 
-		return entry.parser, true
+		return i, true
 	}
 
 	// No available parser found
-	return nil, false
+	return 0, false
 }
 
 func (r *ParserRegistry) getPriorityName(priority ParserPriority) string {
@@ -221,8 +335,42 @@ func (r *ParserRegistry) getPriorityName(priority ParserPriority) string {
 }
 
 // Parse analyzes the content of a file and returns the symbol extraction results.
+// It is safe for concurrent use: calls run in parallel, each with its own
+// parser instance, while the registry's settings stay unchanged.
 func (r *ParserRegistry) Parse(content []byte, filePath string) (*FileAnalysis, error) {
-	lang := DetectLanguage(filePath)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	analysis, err := r.parse(content, filePath)
+	if analysis != nil {
+		analysis.IsTest = IsTestFile(filePath, Language(analysis.Language))
+		if analysis.IsTest && err == nil {
+			markTests(analysis, Language(analysis.Language), content)
+		}
+	}
+	return analysis, err
+}
+
+// parse does the work of Parse with r.mu read-locked.
+func (r *ParserRegistry) parse(content []byte, filePath string) (*FileAnalysis, error) {
+	detected := DetectLanguageDetailed(filePath, content)
+	lang := detected.Language
+	if detected.Ambiguous {
+		logger.Debug("%s: detected %s (%.0f%% confidence, evidence: %s; alternatives: %v)",
+			filePath, lang, detected.Confidence*100, strings.Join(detected.Evidence, ", "), detected.Alternatives)
+	}
+	if lang == LangUnknown && r.genericFallback && isText(content) {
+		generic := NewGenericParser()
+		r.tune(generic, LangGeneric)
+		analysis, err := generic.Parse(content, filePath)
+		if err == nil {
+			limitNesting(analysis, r.maxDepth)
+			markLifecycle(analysis, LangGeneric, content)
+			r.addFindings(analysis, LangGeneric, content)
+			addConfigKeys(analysis, LangGeneric, content)
+		}
+		return analysis, err
+	}
 	if lang == LangUnknown {
 		return &FileAnalysis{
 			Path:     filePath,
@@ -230,54 +378,102 @@ func (r *ParserRegistry) Parse(content []byte, filePath string) (*FileAnalysis,
 		}, nil
 	}
 
-	parser, ok := r.GetParser(lang)
+	i, ok := r.entryIndex(lang)
 	if !ok {
-		return &FileAnalysis{
+		fa := &FileAnalysis{
 			Path:     filePath,
 			Language: string(lang),
-		}, nil
+		}
+		r.addFindings(fa, lang, content)
+		addConfigKeys(fa, lang, content)
+		return fa, nil
 	}
 
 	// Try to parse with selected parser
-	analysis, err := parser.Parse(content, filePath)
+	entries := r.parsers[lang]
+	analysis, err := r.parseWith(entries[i], lang, content, filePath)
 
 	// If LSP parser failed, try fallback
 	if err != nil { //nolint:nestif // acceptable complexity for fallback logic
-		lspParser, ok := parser.(LSPParser)
+		lspParser, ok := entries[i].parser.(LSPParser)
 		if ok && lspParser.IsAvailable() {
 			if r.debugMode {
 				fmt.Printf("[DEBUG] LSP parser failed for %s: %v, trying fallback\n", lang, err)
 			}
 
 			// Try next parser in priority order
-			entries := r.parsers[lang]
-			for i, entry := range entries {
-				if entry.parser == parser && i+1 < len(entries) {
-					fallbackParser := entries[i+1].parser
-					if r.debugMode {
-						fmt.Printf("[DEBUG] Falling back to %s parser\n",
-							r.getPriorityName(entries[i+1].priority))
-					}
-					return fallbackParser.Parse(content, filePath)
+			if i+1 < len(entries) {
+				if r.debugMode {
+					fmt.Printf("[DEBUG] Falling back to %s parser\n",
+						r.getPriorityName(entries[i+1].priority))
 				}
+				analysis, err = r.parseWith(entries[i+1], lang, content, filePath)
 			}
 		}
 	}
 
+	if err == nil {
+		limitNesting(analysis, r.maxDepth)
+		excludeCalls(analysis, lang, r.callExclusions)
+		markLifecycle(analysis, lang, content)
+		r.applyProcessors(analysis, lang, content)
+		r.addFindings(analysis, lang, content)
+		addConfigKeys(analysis, lang, content)
+	}
 	return analysis, err
 }
 
+// parseWith parses content with an instance of entry's parser borrowed for
+// the call.
+func (r *ParserRegistry) parseWith(entry parserEntry, lang Language, content []byte, filePath string) (*FileAnalysis, error) {
+	parser := entry.pool.get()
+	defer entry.pool.put(parser)
+	r.tune(parser, lang)
+	analysis, err := parser.Parse(content, filePath)
+	if g, ok := parser.(nestingLimited); ok && analysis != nil && g.nestingCutOff() {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+			"syntax nesting exceeds %d levels; deeper code was not analyzed", r.maxDepth*syntaxLevelsPerNesting))
+	}
+	return analysis, err
+}
+
+// addFindings appends KindFinding symbols from the lexical passes: blocks of
+// commented-out code always, and likely secrets when enabled.
+func (r *ParserRegistry) addFindings(fa *FileAnalysis, lang Language, content []byte) {
+	if fa == nil {
+		return
+	}
+	fa.Symbols = append(fa.Symbols, FindCommentedCode(lang, content)...)
+	if r.findSecrets {
+		fa.Symbols = append(fa.Symbols, FindSecrets(lang, content)...)
+	}
+}
+
 var defaultRegistry *ParserRegistry
 
 func init() {
 	defaultRegistry = NewParserRegistryWithPath("")
 }
 
+// SetGenericFallback enables or disables the generic fallback parser in
+// Analyze.
+func SetGenericFallback(enabled bool) {
+	defaultRegistry.SetGenericFallback(enabled)
+}
+
 // Analyze is a convenience function that uses the default registry to analyze a file.
 func Analyze(content []byte, filePath string) (*FileAnalysis, error) {
-	// Update root path if analyzing a real file
-	if defaultRegistry.rootPath == "" && filePath != "" {
-		defaultRegistry.rootPath = filepath.Dir(filePath)
+	// Update root path if analyzing a real file. The write lock waits for
+	// every parse in flight, so it is only taken while the path is unset.
+	defaultRegistry.mu.RLock()
+	unset := defaultRegistry.rootPath == ""
+	defaultRegistry.mu.RUnlock()
+	if unset && filePath != "" {
+		defaultRegistry.mu.Lock()
+		if defaultRegistry.rootPath == "" {
+			defaultRegistry.rootPath = filepath.Dir(filePath)
+		}
+		defaultRegistry.mu.Unlock()
 	}
 	return defaultRegistry.Parse(content, filePath)
 }
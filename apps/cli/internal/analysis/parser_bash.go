@@ -9,6 +9,7 @@ import (
 )
 
 type BashParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -35,13 +36,28 @@ func (p *BashParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 	}
 
 	root := tree.RootNode()
-	p.extractSymbols(root, content, analysis)
-	p.extractRelationships(root, content, analysis)
+	p.extractSymbols(root, content, analysis, false)
+
+	functions := make(map[string]bool)
+	for _, sym := range analysis.Symbols {
+		if sym.Kind == KindFunction {
+			functions[sym.Name] = true
+		}
+	}
+	p.extractRelationships(root, content, analysis, functions)
 
 	return analysis, nil
 }
 
-func (p *BashParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// extractSymbols collects function definitions, nested ones included, and
+// the global variables assigned outside them. Assignments inside a function
+// and those prefixing a command, as in "LANG=C sort", are left out.
+func (p *BashParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis, inFunction bool) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -54,12 +70,25 @@ func (p *BashParser) extractSymbols(node *sitter.Node, content []byte, analysis
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
+			p.extractSymbols(child, content, analysis, true)
 
 		case "variable_assignment":
-			p.parseVariable(child, content, analysis)
-		}
+			if !inFunction {
+				p.parseVariable(child, content, KindVariable, analysis)
+			}
+
+		case "declaration_command":
+			if !inFunction {
+				p.parseDeclaration(child, content, analysis)
+			}
+
+		case "command":
+			// Assignments in a command, as in "LANG=C sort", set its
+			// environment only
 
-		p.extractSymbols(child, content, analysis)
+		default:
+			p.extractSymbols(child, content, analysis, inFunction)
+		}
 	}
 }
 
@@ -87,12 +116,38 @@ func (p *BashParser) parseFunction(node *sitter.Node, content []byte) *Symbol {
 		Kind:       KindFunction,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "", "compound_statement", "subshell", "test_command"),
 		DocComment: doc,
 		Exported:   true,
 	}
 }
 
-func (p *BashParser) parseVariable(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// parseDeclaration handles export, readonly, declare, and typeset. Names
+// declared read-only, with readonly or the -r flag, are constants; local
+// declarations outside a function declare nothing.
+func (p *BashParser) parseDeclaration(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	kind := KindVariable
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child == nil {
+			continue
+		}
+		switch child.Type() {
+		case "local":
+			return
+		case "readonly":
+			kind = KindConstant
+		case "word":
+			if flag := child.Content(content); strings.HasPrefix(flag, "-") && strings.Contains(flag, "r") {
+				kind = KindConstant
+			}
+		case "variable_assignment":
+			p.parseVariable(child, content, kind, analysis)
+		}
+	}
+}
+
+func (p *BashParser) parseVariable(node *sitter.Node, content []byte, kind SymbolKind, analysis *FileAnalysis) {
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
 		for i := 0; i < int(node.ChildCount()); i++ {
@@ -108,22 +163,34 @@ func (p *BashParser) parseVariable(node *sitter.Node, content []byte, analysis *
 		return
 	}
 
+	// A variable assigned again is the same variable
 	name := nameNode.Content(content)
-	kind := KindVariable
-	if strings.ToUpper(name) == name {
-		kind = KindConstant
+	for _, sym := range analysis.Symbols {
+		if sym.Name == name && (sym.Kind == KindVariable || sym.Kind == KindConstant) {
+			return
+		}
 	}
 
 	analysis.Symbols = append(analysis.Symbols, Symbol{
-		Name:      name,
-		Kind:      kind,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  true,
+		Name:       name,
+		Kind:       kind,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  strings.Join(strings.Fields(node.Content(content)), " "),
+		DocComment: p.extractPrecedingComment(node, content),
+		Exported:   true,
 	})
 }
 
-func (p *BashParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// extractRelationships records the scripts a script sources and its calls
+// of the functions it defines. Other commands run programs, which are not
+// symbols of the workspace.
+func (p *BashParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis, functions map[string]bool) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -132,12 +199,19 @@ func (p *BashParser) extractRelationships(node *sitter.Node, content []byte, ana
 
 		if child.Type() == "command" {
 			p.parseSourceCommand(child, content, analysis)
+			if name := child.ChildByFieldName("name"); name != nil && functions[name.Content(content)] {
+				analysis.Relationships = append(analysis.Relationships, Relationship{
+					TargetSymbol: name.Content(content),
+					Kind:         RelCall,
+					Line:         int(name.StartPoint().Row) + 1,
+					Column:       int(name.StartPoint().Column) + 1,
+				})
+			}
 		}
 
-		p.extractRelationships(child, content, analysis)
+		p.extractRelationships(child, content, analysis, functions)
 	}
 }
-
 func (p *BashParser) parseSourceCommand(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
@@ -175,15 +249,32 @@ func (p *BashParser) parseSourceCommand(node *sitter.Node, content []byte, analy
 	}
 }
 
+// extractPrecedingComment returns the block of # comment lines directly
+// above node, without a blank line between them, joined into one line.
+// The shebang line is not part of it.
 func (p *BashParser) extractPrecedingComment(node *sitter.Node, content []byte) string {
-	prev := node.PrevSibling()
-	if prev == nil {
-		return ""
+	if node.Parent() != nil && node.Parent().Type() == "declaration_command" {
+		node = node.Parent()
 	}
-
-	if prev.Type() == "comment" {
-		return strings.TrimPrefix(prev.Content(content), "# ")
+	var lines []string
+	row := node.StartPoint().Row
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevSibling() {
+		// A comment separated by a blank line, or trailing a command, belongs
+		// to something else
+		if prev.EndPoint().Row+1 < row {
+			break
+		}
+		if before := prev.PrevSibling(); before != nil && before.EndPoint().Row == prev.StartPoint().Row {
+			break
+		}
+		text := prev.Content(content)
+		if strings.HasPrefix(text, "#!") {
+			break
+		}
+		if line := strings.TrimSpace(strings.TrimLeft(text, "#")); line != "" {
+			lines = append([]string{line}, lines...)
+		}
+		row = prev.StartPoint().Row
 	}
-
-	return ""
+	return strings.Join(lines, " ")
 }
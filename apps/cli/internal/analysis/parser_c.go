@@ -9,6 +9,7 @@ import (
 )
 
 type CParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -41,7 +42,16 @@ func (p *CParser) Parse(content []byte, filePath string) (*FileAnalysis, error)
 	return analysis, nil
 }
 
+// extractSymbols collects the file-scope declarations of a translation
+// unit, including those inside #if blocks and extern "C" blocks. Function
+// bodies are not descended into, so their locals stay out. Macros are not
+// symbols: a function-like #define is not parsed as a function.
 func (p *CParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -56,31 +66,34 @@ func (p *CParser) extractSymbols(node *sitter.Node, content []byte, analysis *Fi
 			}
 
 		case "declaration":
-			p.parseDeclaration(child, content, analysis)
+			analysis.Symbols = append(analysis.Symbols, p.parseDeclaration(child, content)...)
 
-		case "struct_specifier":
-			sym := p.parseStruct(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
-
-		case "enum_specifier":
-			sym := p.parseEnum(child, content)
+		case "struct_specifier", "union_specifier", "enum_specifier":
+			sym := p.parseRecord(child, content, "")
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
 
 		case "type_definition":
-			sym := p.parseTypedef(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
+			analysis.Symbols = append(analysis.Symbols, p.parseTypedef(child, content)...)
+
+		case "preproc_if", "preproc_ifdef", "preproc_else", "preproc_elif", "declaration_list":
+			p.extractSymbols(child, content, analysis)
+
+		case "linkage_specification":
+			if body := child.ChildByFieldName("body"); body != nil {
+				if body.Type() == "declaration_list" {
+					p.extractSymbols(body, content, analysis)
+				} else {
+					p.extractSymbols(child, content, analysis)
+				}
 			}
 		}
-
-		p.extractSymbols(child, content, analysis)
 	}
 }
 
+// parseFunctionDef parses a function definition, with a signature such as
+// "static inline int add(int a, int b)". Static functions are not exported.
 func (p *CParser) parseFunctionDef(node *sitter.Node, content []byte) *Symbol {
 	declarator := node.ChildByFieldName("declarator")
 	if declarator == nil {
@@ -92,141 +105,120 @@ func (p *CParser) parseFunctionDef(node *sitter.Node, content []byte) *Symbol {
 		return nil
 	}
 
-	doc := p.extractPrecedingComment(node, content)
-	sig := p.extractFunctionSignature(node, content)
-
 	return &Symbol{
 		Name:       name,
 		Kind:       KindFunction,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
-		Signature:  sig,
-		DocComment: doc,
-		Exported:   true,
+		Signature:  declarationHeader(node, content, "", "compound_statement"),
+		DocComment: cDocComment(node, content),
+		Exported:   !cHasStorageClass(node, content, "static"),
 	}
 }
 
-func (p *CParser) parseDeclaration(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	declarator := node.ChildByFieldName("declarator")
-	if declarator == nil {
-		return
+// parseDeclaration parses a file-scope declaration: function prototypes
+// and variables, one symbol per declarator, and a struct, union, or enum
+// defined in the declaration's type.
+func (p *CParser) parseDeclaration(node *sitter.Node, content []byte) []Symbol {
+	var symbols []Symbol
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		if sym := p.parseRecord(typeNode, content, ""); sym != nil {
+			symbols = append(symbols, *sym)
+		}
 	}
 
-	if declarator.Type() == "function_declarator" {
+	doc := cDocComment(node, content)
+	exported := !cHasStorageClass(node, content, "static")
+	for _, declarator := range cDeclarators(node) {
 		name := p.extractDeclaratorName(declarator, content)
-		if name != "" {
-			analysis.Symbols = append(analysis.Symbols, Symbol{
-				Name:      name,
-				Kind:      KindFunction,
-				LineStart: int(node.StartPoint().Row) + 1,
-				LineEnd:   int(node.EndPoint().Row) + 1,
-				Exported:  true,
-			})
+		if name == "" {
+			continue
 		}
-	} else {
-		name := p.extractDeclaratorName(declarator, content)
-		if name != "" {
-			analysis.Symbols = append(analysis.Symbols, Symbol{
-				Name:      name,
-				Kind:      KindVariable,
-				LineStart: int(node.StartPoint().Row) + 1,
-				LineEnd:   int(node.EndPoint().Row) + 1,
-				Exported:  true,
-			})
+		sym := Symbol{
+			Name:       name,
+			Kind:       KindVariable,
+			LineStart:  int(node.StartPoint().Row) + 1,
+			LineEnd:    int(node.EndPoint().Row) + 1,
+			Signature:  cDeclaratorSignature(node, declarator, content),
+			DocComment: doc,
+			Exported:   exported,
+		}
+		if cIsFunctionDeclarator(declarator) {
+			sym.Kind = KindFunction
+		} else if declarator.Type() == "init_declarator" {
+			if value := declarator.ChildByFieldName("value"); value != nil {
+				sym.Value = literalValue(value.Content(content))
+			}
 		}
+		symbols = append(symbols, sym)
 	}
+	return symbols
 }
 
-func (p *CParser) parseStruct(node *sitter.Node, content []byte) *Symbol {
-	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
+// parseRecord parses a struct, union, or enum with a body; references to
+// one by tag, as in "struct point p;", are not symbols. An anonymous body
+// takes name, the typedef naming it.
+func (p *CParser) parseRecord(node *sitter.Node, content []byte, name string) *Symbol {
+	body := node.ChildByFieldName("body")
+	if body == nil {
 		return nil
 	}
-
-	name := nameNode.Content(content)
-	var children []Symbol
-
-	body := node.ChildByFieldName("body")
-	if body != nil {
-		children = p.extractStructFields(body, content)
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		name = nameNode.Content(content)
+	}
+	if name == "" {
+		return nil
 	}
 
-	return &Symbol{
-		Name:      name,
-		Kind:      KindClass,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  true,
-		Children:  children,
+	sym := &Symbol{
+		Name:       name,
+		Kind:       KindClass,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "", "field_declaration_list", "enumerator_list"),
+		DocComment: cDocComment(node, content),
+		Exported:   true,
+	}
+	if node.Type() == "enum_specifier" {
+		sym.Kind = KindEnum
+		sym.Children = cEnumerators(body, content)
+	} else {
+		sym.Children = p.extractStructFields(body, content)
 	}
+	return sym
 }
 
 func (p *CParser) extractStructFields(node *sitter.Node, content []byte) []Symbol {
 	var fields []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		if child == nil {
+		if child == nil || child.Type() != "field_declaration" {
 			continue
 		}
 
-		if child.Type() == "field_declaration" {
-			declarator := child.ChildByFieldName("declarator")
-			if declarator != nil {
-				name := p.extractDeclaratorName(declarator, content)
-				if name != "" {
-					fields = append(fields, Symbol{
-						Name:      name,
-						Kind:      KindProperty,
-						LineStart: int(child.StartPoint().Row) + 1,
-						LineEnd:   int(child.EndPoint().Row) + 1,
-						Exported:  true,
-					})
-				}
+		for _, declarator := range cDeclarators(child) {
+			name := p.extractDeclaratorName(declarator, content)
+			if name == "" {
+				continue
 			}
+			fields = append(fields, Symbol{
+				Name:       name,
+				Kind:       KindProperty,
+				LineStart:  int(child.StartPoint().Row) + 1,
+				LineEnd:    int(child.EndPoint().Row) + 1,
+				Signature:  cDeclaratorSignature(child, declarator, content),
+				DocComment: cDocComment(child, content),
+				Exported:   true,
+			})
 		}
 	}
 	return fields
 }
 
-func (p *CParser) parseEnum(node *sitter.Node, content []byte) *Symbol {
-	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		return nil
-	}
-
-	name := nameNode.Content(content)
-	var children []Symbol
-
-	body := node.ChildByFieldName("body")
-	if body != nil {
-		for i := 0; i < int(body.ChildCount()); i++ {
-			child := body.Child(i)
-			if child != nil && child.Type() == "enumerator" {
-				enumName := child.ChildByFieldName("name")
-				if enumName != nil {
-					children = append(children, Symbol{
-						Name:      enumName.Content(content),
-						Kind:      KindConstant,
-						LineStart: int(child.StartPoint().Row) + 1,
-						LineEnd:   int(child.EndPoint().Row) + 1,
-						Exported:  true,
-					})
-				}
-			}
-		}
-	}
-
-	return &Symbol{
-		Name:      name,
-		Kind:      KindEnum,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  true,
-		Children:  children,
-	}
-}
-
-func (p *CParser) parseTypedef(node *sitter.Node, content []byte) *Symbol {
+// parseTypedef parses a typedef into an alias, such as "typedef unsigned
+// long tick_t". A struct, union, or enum defined in it is a symbol of its
+// own, named by its tag or, when it has none, by the typedef.
+func (p *CParser) parseTypedef(node *sitter.Node, content []byte) []Symbol {
 	declarator := node.ChildByFieldName("declarator")
 	if declarator == nil {
 		return nil
@@ -237,20 +229,52 @@ func (p *CParser) parseTypedef(node *sitter.Node, content []byte) *Symbol {
 		return nil
 	}
 
-	return &Symbol{
-		Name:      name,
-		Kind:      KindType,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  true,
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil && typeNode.ChildByFieldName("body") != nil {
+		if typeNode.ChildByFieldName("name") == nil {
+			sym := p.parseRecord(typeNode, content, name)
+			if sym != nil {
+				sym.LineStart = int(node.StartPoint().Row) + 1
+				sym.LineEnd = int(node.EndPoint().Row) + 1
+				sym.Signature = "typedef " + sym.Signature + " " + name
+				sym.DocComment = cDocComment(node, content)
+				return []Symbol{*sym}
+			}
+			return nil
+		}
+		record := p.parseRecord(typeNode, content, "")
+		alias := Symbol{
+			Name:      name,
+			Kind:      KindTypeAlias,
+			LineStart: int(node.StartPoint().Row) + 1,
+			LineEnd:   int(node.EndPoint().Row) + 1,
+			Signature: "typedef " + record.Signature + " " + name,
+			Exported:  true,
+		}
+		record.DocComment = cDocComment(node, content)
+		return []Symbol{*record, alias}
 	}
+
+	return []Symbol{{
+		Name:       name,
+		Kind:       KindTypeAlias,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  strings.TrimSuffix(strings.Join(strings.Fields(node.Content(content)), " "), ";"),
+		DocComment: cDocComment(node, content),
+		Exported:   true,
+	}}
 }
 
 func (p *CParser) extractDeclaratorName(node *sitter.Node, content []byte) string {
+	if !p.descend() {
+		return ""
+	}
+	defer p.ascend()
+
 	switch node.Type() {
-	case "identifier":
+	case "identifier", "type_identifier", "field_identifier":
 		return node.Content(content)
-	case "pointer_declarator", "array_declarator", "function_declarator":
+	case "pointer_declarator", "array_declarator", "function_declarator", "init_declarator":
 		declarator := node.ChildByFieldName("declarator")
 		if declarator != nil {
 			return p.extractDeclaratorName(declarator, content)
@@ -259,8 +283,15 @@ func (p *CParser) extractDeclaratorName(node *sitter.Node, content []byte) strin
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		if child != nil && child.Type() == "identifier" {
+		if child == nil {
+			continue
+		}
+		switch child.Type() {
+		case "identifier", "type_identifier", "field_identifier":
 			return child.Content(content)
+		case "pointer_declarator":
+			// A function pointer: void (*handler)(int)
+			return p.extractDeclaratorName(child, content)
 		}
 	}
 
@@ -268,13 +299,22 @@ func (p *CParser) extractDeclaratorName(node *sitter.Node, content []byte) strin
 }
 
 func (p *CParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
 			continue
 		}
 
-		if child.Type() == "preproc_include" {
+		switch child.Type() {
+		case "type_definition":
+			p.parseTypedefReferences(child, content, analysis)
+
+		case "preproc_include":
 			pathNode := child.ChildByFieldName("path")
 			if pathNode != nil {
 				path := pathNode.Content(content)
@@ -285,41 +325,210 @@ func (p *CParser) extractRelationships(node *sitter.Node, content []byte, analys
 					Line:       int(child.StartPoint().Row) + 1,
 				})
 			}
+
+		case "call_expression":
+			if target := cCallTarget(child, content); target != "" {
+				analysis.Relationships = append(analysis.Relationships, Relationship{
+					TargetSymbol: target,
+					Kind:         RelCall,
+					Line:         int(child.StartPoint().Row) + 1,
+					Column:       int(child.StartPoint().Column),
+				})
+			}
+
+		case "preproc_function_def", "preproc_def":
+			// A macro body is not code until it is expanded
+			continue
 		}
 
 		p.extractRelationships(child, content, analysis)
 	}
 }
 
-func (p *CParser) extractFunctionSignature(node *sitter.Node, content []byte) string {
-	typeNode := node.ChildByFieldName("type")
+// parseTypedefReferences records what a typedef aliases. An anonymous struct
+// or enum body has no name to reference, so only named types are recorded.
+func (p *CParser) parseTypedefReferences(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	declarator := node.ChildByFieldName("declarator")
-
-	var sig strings.Builder
+	typeNode := node.ChildByFieldName("type")
+	if declarator == nil || typeNode == nil {
+		return
+	}
+	name := p.extractDeclaratorName(declarator, content)
+	if name == "" {
+		return
+	}
+	switch typeNode.Type() {
+	case "struct_specifier", "union_specifier", "enum_specifier":
+		// Only the tag name counts, not the fields of an inline body
+		typeNode = typeNode.ChildByFieldName("name")
+	}
 	if typeNode != nil {
-		sig.WriteString(typeNode.Content(content))
-		sig.WriteString(" ")
+		analysis.Relationships = append(analysis.Relationships, typeAliasReferences(name, typeNode, content)...)
 	}
-	if declarator != nil {
-		sig.WriteString(declarator.Content(content))
+}
+
+// cDeclarators returns the declarators of a declaration, one for each name
+// it declares: "int a = 1, *b;" has two.
+func cDeclarators(node *sitter.Node) []*sitter.Node {
+	var declarators []*sitter.Node
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.FieldNameForChild(i) == "declarator" {
+			declarators = append(declarators, node.Child(i))
+		}
 	}
+	return declarators
+}
 
-	return sig.String()
+// cIsFunctionDeclarator reports whether a declarator declares a function,
+// as in "char *strdup(const char *s)", rather than a variable, which may be
+// a function pointer such as "void (*handler)(int)" or an object
+// constructed with arguments such as "Widget w(1, 2)".
+func cIsFunctionDeclarator(node *sitter.Node) bool {
+	for {
+		switch node.Type() {
+		case "function_declarator":
+			inner := node.ChildByFieldName("declarator")
+			return inner == nil || inner.Type() != "parenthesized_declarator"
+		case "pointer_declarator", "reference_declarator":
+			if node = node.ChildByFieldName("declarator"); node == nil {
+				// A reference declarator has its declarator unnamed
+				return false
+			}
+		default:
+			return false
+		}
+	}
 }
 
-func (p *CParser) extractPrecedingComment(node *sitter.Node, content []byte) string {
-	prev := node.PrevSibling()
-	if prev == nil {
-		return ""
+// cDeclaratorSignature returns the declaration of one declarator, with the
+// specifiers and type shared by all of them but without its initializer:
+// "static int counter" for counter in "static int counter = 0, total;".
+func cDeclaratorSignature(decl, declarator *sitter.Node, content []byte) string {
+	if declarator.Type() == "init_declarator" {
+		if inner := declarator.ChildByFieldName("declarator"); inner != nil {
+			declarator = inner
+		}
 	}
+	var prefix string
+	for i := 0; i < int(decl.ChildCount()); i++ {
+		if decl.FieldNameForChild(i) == "declarator" {
+			prefix = string(content[decl.StartByte():decl.Child(i).StartByte()])
+			break
+		}
+	}
+	return strings.Join(strings.Fields(prefix+" "+declarator.Content(content)), " ")
+}
+
+// cHasStorageClass reports whether a declaration has the storage class,
+// such as static or extern.
+func cHasStorageClass(node *sitter.Node, content []byte, class string) bool {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() == "storage_class_specifier" && child.Content(content) == class {
+			return true
+		}
+	}
+	return false
+}
 
-	if prev.Type() == "comment" {
-		comment := prev.Content(content)
-		comment = strings.TrimPrefix(comment, "// ")
-		comment = strings.TrimPrefix(comment, "/* ")
-		comment = strings.TrimSuffix(comment, " */")
-		return comment
+// cEnumerators returns the constants of an enum body.
+func cEnumerators(body *sitter.Node, content []byte) []Symbol {
+	var children []Symbol
+	for i := 0; i < int(body.ChildCount()); i++ {
+		child := body.Child(i)
+		if child == nil || child.Type() != "enumerator" {
+			continue
+		}
+		enumName := child.ChildByFieldName("name")
+		if enumName == nil {
+			continue
+		}
+		sym := Symbol{
+			Name:      enumName.Content(content),
+			Kind:      KindConstant,
+			LineStart: int(child.StartPoint().Row) + 1,
+			LineEnd:   int(child.EndPoint().Row) + 1,
+			Exported:  true,
+		}
+		if value := child.ChildByFieldName("value"); value != nil {
+			sym.Value = literalValue(value.Content(content))
+		}
+		children = append(children, sym)
 	}
+	return children
+}
 
+// cCallTarget returns the callee of a call expression: "init" for
+// init(), "helper::zero" for helper::zero(), and "dev.read" for
+// dev.read() and dev->read(). Calls cannot be told from declarations by
+// their text alone; the grammar does, so this only sees real calls.
+func cCallTarget(node *sitter.Node, content []byte) string {
+	fn := node.ChildByFieldName("function")
+	if fn == nil {
+		return ""
+	}
+	switch fn.Type() {
+	case "identifier":
+		return fn.Content(content)
+	case "qualified_identifier":
+		// Without template arguments: std::make_unique<Point>()
+		target := fn.Content(content)
+		if idx := strings.Index(target, "<"); idx >= 0 {
+			target = target[:idx]
+		}
+		return target
+	case "template_function":
+		if name := fn.ChildByFieldName("name"); name != nil {
+			return name.Content(content)
+		}
+	case "field_expression":
+		field := fn.ChildByFieldName("field")
+		if field == nil {
+			return ""
+		}
+		if arg := fn.ChildByFieldName("argument"); arg != nil {
+			return arg.Content(content) + "." + field.Content(content)
+		}
+		return field.Content(content)
+	}
 	return ""
 }
+
+// cDocComment returns the comment lines directly above a declaration, with
+// their comment markers removed: a run of // or /// lines, or a /* */ or
+// /** */ block.
+func cDocComment(node *sitter.Node, content []byte) string {
+	var lines []string
+	row := node.StartPoint().Row
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevSibling() {
+		// A comment separated by a blank line, or trailing the previous
+		// declaration, belongs to something else
+		if prev.EndPoint().Row+1 < row {
+			break
+		}
+		if before := prev.PrevSibling(); before != nil && before.EndPoint().Row == prev.StartPoint().Row {
+			break
+		}
+		text := prev.Content(content)
+		if strings.HasPrefix(text, "/*") {
+			if len(lines) > 0 {
+				break
+			}
+			text = strings.TrimSuffix(strings.TrimLeft(text, "/*!"), "*/")
+			lines = strings.Split(text, "\n")
+			break
+		}
+		lines = append([]string{strings.TrimLeft(text, "/!")}, lines...)
+		row = prev.StartPoint().Row
+	}
+
+	var cleaned []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		if line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, " ")
+}
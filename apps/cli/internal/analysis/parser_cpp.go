@@ -9,6 +9,7 @@ import (
 )
 
 type CPPParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -41,7 +42,17 @@ func (p *CPPParser) Parse(content []byte, filePath string) (*FileAnalysis, error
 	return analysis, nil
 }
 
+// extractSymbols collects the declarations of a translation unit or
+// namespace body. Namespaces are listed with their contents at the top
+// level, as are declarations inside #if and extern "C" blocks; class
+// bodies give their symbol's children, and function bodies are not
+// descended into.
 func (p *CPPParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -49,45 +60,62 @@ func (p *CPPParser) extractSymbols(node *sitter.Node, content []byte, analysis *
 		}
 
 		switch child.Type() {
-		case "function_definition":
-			sym := p.parseFunctionDef(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
+		case "namespace_definition":
+			p.parseNamespace(child, content, analysis)
 
-		case "class_specifier":
-			sym := p.parseClass(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
+		case "preproc_if", "preproc_ifdef", "preproc_else", "preproc_elif", "declaration_list":
+			p.extractSymbols(child, content, analysis)
 
-		case "struct_specifier":
-			sym := p.parseStruct(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
+		case "linkage_specification":
+			if body := child.ChildByFieldName("body"); body != nil {
+				if body.Type() == "declaration_list" {
+					p.extractSymbols(body, content, analysis)
+				} else {
+					p.extractSymbols(child, content, analysis)
+				}
 			}
 
-		case "enum_specifier":
-			sym := p.parseEnum(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
+		default:
+			analysis.Symbols = append(analysis.Symbols, p.parseDeclarationNode(child, content, nil)...)
+		}
+	}
+}
 
-		case "namespace_definition":
-			p.parseNamespace(child, content, analysis)
+// parseDeclarationNode parses a declaration found at namespace scope, or in
+// a class body when class is set, into its symbols. Other nodes give none.
+func (p *CPPParser) parseDeclarationNode(node *sitter.Node, content []byte, class *Symbol) []Symbol {
+	switch node.Type() {
+	case "function_definition":
+		if sym := p.parseFunctionDef(node, content, class); sym != nil {
+			return []Symbol{*sym}
+		}
+
+	case "declaration", "field_declaration":
+		return p.parseDeclaration(node, content, class)
 
-		case "template_declaration":
-			p.parseTemplate(child, content, analysis)
+	case "class_specifier", "struct_specifier", "union_specifier":
+		if sym := p.parseClass(node, content); sym != nil {
+			return []Symbol{*sym}
+		}
 
-		case "declaration":
-			p.parseDeclaration(child, content, analysis)
+	case "enum_specifier":
+		if sym := p.parseEnum(node, content); sym != nil {
+			return []Symbol{*sym}
 		}
 
-		p.extractSymbols(child, content, analysis)
+	case "type_definition", "alias_declaration":
+		return p.parseTypeAlias(node, content)
+
+	case "template_declaration":
+		return p.parseTemplate(node, content, class)
 	}
+	return nil
 }
 
-func (p *CPPParser) parseFunctionDef(node *sitter.Node, content []byte) *Symbol {
+// parseFunctionDef parses a function or, inside a class, a method, with a
+// signature such as "T read() const override". A method named after its
+// class is a constructor.
+func (p *CPPParser) parseFunctionDef(node *sitter.Node, content []byte, class *Symbol) *Symbol {
 	declarator := node.ChildByFieldName("declarator")
 	if declarator == nil {
 		return nil
@@ -98,115 +126,122 @@ func (p *CPPParser) parseFunctionDef(node *sitter.Node, content []byte) *Symbol
 		return nil
 	}
 
-	doc := p.extractPrecedingComment(node, content)
-	sig := p.extractFunctionSignature(node, content)
-
-	return &Symbol{
+	sym := &Symbol{
 		Name:       name,
 		Kind:       KindFunction,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
-		Signature:  sig,
-		DocComment: doc,
-		Exported:   true,
+		Signature:  strings.TrimSuffix(declarationHeader(node, content, "", "compound_statement", "field_initializer_list", "default_method_clause", "delete_method_clause", "pure_virtual_clause"), ";"),
+		DocComment: cDocComment(node, content),
+		Exported:   !cHasStorageClass(node, content, "static"),
+	}
+	if class != nil {
+		// static in a class body makes a class member, not a private one
+		sym.Kind = p.memberKind(name, class)
+		sym.Exported = true
 	}
+	return sym
 }
 
-func (p *CPPParser) parseClass(node *sitter.Node, content []byte) *Symbol {
-	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		return nil
+func (p *CPPParser) memberKind(name string, class *Symbol) SymbolKind {
+	if name == class.Name {
+		return KindConstructor
+	}
+	return KindMethod
+}
+
+// parseDeclaration parses a declaration into one symbol per declarator:
+// function prototypes and variables at namespace scope, methods and fields
+// in a class. A class or enum defined in its type is a symbol too.
+func (p *CPPParser) parseDeclaration(node *sitter.Node, content []byte, class *Symbol) []Symbol {
+	var symbols []Symbol
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil && typeNode.ChildByFieldName("body") != nil {
+		symbols = append(symbols, p.parseDeclarationNode(typeNode, content, nil)...)
 	}
 
-	name := nameNode.Content(content)
-	doc := p.extractPrecedingComment(node, content)
-	var children []Symbol
+	doc := cDocComment(node, content)
+	exported := class != nil || !cHasStorageClass(node, content, "static")
+	for _, declarator := range cDeclarators(node) {
+		name := p.extractDeclaratorName(declarator, content)
+		if name == "" {
+			continue
+		}
+		sym := Symbol{
+			Name:       name,
+			Kind:       KindVariable,
+			LineStart:  int(node.StartPoint().Row) + 1,
+			LineEnd:    int(node.EndPoint().Row) + 1,
+			Signature:  cDeclaratorSignature(node, declarator, content),
+			DocComment: doc,
+			Exported:   exported,
+		}
+		switch {
+		case cIsFunctionDeclarator(declarator) && class != nil:
+			sym.Kind = p.memberKind(name, class)
+		case cIsFunctionDeclarator(declarator):
+			sym.Kind = KindFunction
+		case class != nil:
+			sym.Kind = KindProperty
+		}
+		if sym.Kind == KindVariable || sym.Kind == KindProperty {
+			value := declarator.ChildByFieldName("value")
+			if value == nil {
+				value = node.ChildByFieldName("default_value")
+			}
+			if value != nil {
+				sym.Value = literalValue(value.Content(content))
+			}
+		}
+		symbols = append(symbols, sym)
+	}
+	return symbols
+}
 
+// parseClass parses a class, struct, or union with a body, with its members
+// as children. Members are exported when public: by default in a struct or
+// union, after "public:" in a class.
+func (p *CPPParser) parseClass(node *sitter.Node, content []byte) *Symbol {
+	nameNode := node.ChildByFieldName("name")
 	body := node.ChildByFieldName("body")
-	if body != nil {
-		children = p.extractClassMembers(body, content)
+	if nameNode == nil || body == nil {
+		return nil
 	}
 
-	return &Symbol{
-		Name:       name,
+	sym := &Symbol{
+		Name:       nameNode.Content(content),
 		Kind:       KindClass,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
+		Signature:  declarationHeader(node, content, "", "field_declaration_list"),
+		DocComment: cDocComment(node, content),
 		Exported:   true,
-		Children:   children,
 	}
+	if qualified := nameNode.ChildByFieldName("name"); qualified != nil {
+		sym.Name = qualified.Content(content)
+	}
+	sym.Children = p.extractClassMembers(body, content, sym, node.Type() != "class_specifier")
+	return sym
 }
 
-func (p *CPPParser) parseStruct(node *sitter.Node, content []byte) *Symbol {
-	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
+func (p *CPPParser) extractClassMembers(node *sitter.Node, content []byte, class *Symbol, public bool) []Symbol {
+	if !p.descend() {
 		return nil
 	}
+	defer p.ascend()
 
-	name := nameNode.Content(content)
-	var children []Symbol
-
-	body := node.ChildByFieldName("body")
-	if body != nil {
-		children = p.extractClassMembers(body, content)
-	}
-
-	return &Symbol{
-		Name:      name,
-		Kind:      KindClass,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  true,
-		Children:  children,
-	}
-}
-
-func (p *CPPParser) extractClassMembers(node *sitter.Node, content []byte) []Symbol {
 	var members []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
 			continue
 		}
-
-		switch child.Type() {
-		case "field_declaration":
-			declarator := child.ChildByFieldName("declarator")
-			if declarator != nil {
-				name := p.extractDeclaratorName(declarator, content)
-				if name != "" {
-					members = append(members, Symbol{
-						Name:      name,
-						Kind:      KindProperty,
-						LineStart: int(child.StartPoint().Row) + 1,
-						LineEnd:   int(child.EndPoint().Row) + 1,
-						Exported:  true,
-					})
-				}
-			}
-
-		case "function_definition":
-			sym := p.parseFunctionDef(child, content)
-			if sym != nil {
-				sym.Kind = KindMethod
-				members = append(members, *sym)
-			}
-
-		case "declaration":
-			declarator := child.ChildByFieldName("declarator")
-			if declarator != nil && declarator.Type() == "function_declarator" {
-				name := p.extractDeclaratorName(declarator, content)
-				if name != "" {
-					members = append(members, Symbol{
-						Name:      name,
-						Kind:      KindMethod,
-						LineStart: int(child.StartPoint().Row) + 1,
-						LineEnd:   int(child.EndPoint().Row) + 1,
-						Exported:  true,
-					})
-				}
-			}
+		if child.Type() == "access_specifier" {
+			public = child.Content(content) == "public"
+			continue
+		}
+		for _, member := range p.parseDeclarationNode(child, content, class) {
+			member.Exported = member.Exported && public
+			members = append(members, member)
 		}
 	}
 	return members
@@ -214,110 +249,122 @@ func (p *CPPParser) extractClassMembers(node *sitter.Node, content []byte) []Sym
 
 func (p *CPPParser) parseEnum(node *sitter.Node, content []byte) *Symbol {
 	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		return nil
-	}
-
-	name := nameNode.Content(content)
-	var children []Symbol
-
 	body := node.ChildByFieldName("body")
-	if body != nil {
-		for i := 0; i < int(body.ChildCount()); i++ {
-			child := body.Child(i)
-			if child != nil && child.Type() == "enumerator" {
-				enumName := child.ChildByFieldName("name")
-				if enumName != nil {
-					children = append(children, Symbol{
-						Name:      enumName.Content(content),
-						Kind:      KindConstant,
-						LineStart: int(child.StartPoint().Row) + 1,
-						LineEnd:   int(child.EndPoint().Row) + 1,
-						Exported:  true,
-					})
-				}
-			}
-		}
+	if nameNode == nil || body == nil {
+		return nil
 	}
 
 	return &Symbol{
-		Name:      name,
-		Kind:      KindEnum,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  true,
-		Children:  children,
+		Name:       nameNode.Content(content),
+		Kind:       KindEnum,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "", "enumerator_list"),
+		DocComment: cDocComment(node, content),
+		Exported:   true,
+		Children:   cEnumerators(body, content),
 	}
 }
 
-func (p *CPPParser) parseNamespace(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// parseTypeAlias parses "typedef unsigned long tick_t;" and
+// "using Reading = std::pair<int, float>;" into type aliases.
+func (p *CPPParser) parseTypeAlias(node *sitter.Node, content []byte) []Symbol {
+	var symbols []Symbol
 	nameNode := node.ChildByFieldName("name")
+	if node.Type() == "type_definition" {
+		if typeNode := node.ChildByFieldName("type"); typeNode != nil && typeNode.ChildByFieldName("body") != nil {
+			symbols = append(symbols, p.parseDeclarationNode(typeNode, content, nil)...)
+		}
+		if declarator := node.ChildByFieldName("declarator"); declarator != nil {
+			nameNode = declarator
+		}
+	}
 	if nameNode == nil {
-		return
+		return symbols
+	}
+	name := p.extractDeclaratorName(nameNode, content)
+	if name == "" {
+		name = nameNode.Content(content)
 	}
 
-	name := nameNode.Content(content)
-	analysis.Symbols = append(analysis.Symbols, Symbol{
-		Name:      name,
-		Kind:      KindType,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  true,
+	return append(symbols, Symbol{
+		Name:       name,
+		Kind:       KindTypeAlias,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  strings.TrimSuffix(strings.Join(strings.Fields(node.Content(content)), " "), ";"),
+		DocComment: cDocComment(node, content),
+		Exported:   true,
 	})
 }
 
-func (p *CPPParser) parseTemplate(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child == nil {
-			continue
-		}
-
-		switch child.Type() {
-		case "function_definition":
-			sym := p.parseFunctionDef(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
-		case "class_specifier":
-			sym := p.parseClass(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
-		}
+// parseNamespace records a namespace and lists its declarations with the
+// file's.
+func (p *CPPParser) parseNamespace(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		analysis.Symbols = append(analysis.Symbols, Symbol{
+			Name:      nameNode.Content(content),
+			Kind:      KindType,
+			LineStart: int(node.StartPoint().Row) + 1,
+			LineEnd:   int(node.EndPoint().Row) + 1,
+			Exported:  true,
+		})
+	}
+	if body := node.ChildByFieldName("body"); body != nil {
+		p.extractSymbols(body, content, analysis)
 	}
 }
 
-func (p *CPPParser) parseDeclaration(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	declarator := node.ChildByFieldName("declarator")
-	if declarator == nil {
-		return
+// parseTemplate parses the declaration of a template, prefixing its
+// signature with the template parameters, as in
+// "template <typename T> T max(T a, T b)". Its doc comment precedes the
+// template keyword.
+func (p *CPPParser) parseTemplate(node *sitter.Node, content []byte, class *Symbol) []Symbol {
+	if !p.descend() {
+		return nil
 	}
+	defer p.ascend()
 
-	if declarator.Type() == "function_declarator" {
-		name := p.extractDeclaratorName(declarator, content)
-		if name != "" {
-			analysis.Symbols = append(analysis.Symbols, Symbol{
-				Name:      name,
-				Kind:      KindFunction,
-				LineStart: int(node.StartPoint().Row) + 1,
-				LineEnd:   int(node.EndPoint().Row) + 1,
-				Exported:  true,
-			})
+	var params string
+	if paramsNode := node.ChildByFieldName("parameters"); paramsNode != nil {
+		params = "template " + strings.Join(strings.Fields(paramsNode.Content(content)), " ") + " "
+	}
+	doc := cDocComment(node, content)
+
+	var symbols []Symbol
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		for _, sym := range p.parseDeclarationNode(node.NamedChild(i), content, class) {
+			sym.Signature = params + sym.Signature
+			if sym.DocComment == "" {
+				sym.DocComment = doc
+			}
+			sym.LineStart = int(node.StartPoint().Row) + 1
+			symbols = append(symbols, sym)
 		}
 	}
+	return symbols
 }
 
 func (p *CPPParser) extractDeclaratorName(node *sitter.Node, content []byte) string {
+	if !p.descend() {
+		return ""
+	}
+	defer p.ascend()
+
 	switch node.Type() {
-	case "identifier", "field_identifier":
+	case "identifier", "field_identifier", "type_identifier", "operator_name":
 		return node.Content(content)
 	case "qualified_identifier":
+		nameNode := node.ChildByFieldName("name")
+		if nameNode != nil {
+			return p.extractDeclaratorName(nameNode, content)
+		}
+	case "template_function", "template_type":
 		nameNode := node.ChildByFieldName("name")
 		if nameNode != nil {
 			return nameNode.Content(content)
 		}
-	case "pointer_declarator", "reference_declarator", "array_declarator", "function_declarator":
+	case "pointer_declarator", "reference_declarator", "array_declarator", "function_declarator", "init_declarator":
 		declarator := node.ChildByFieldName("declarator")
 		if declarator != nil {
 			return p.extractDeclaratorName(declarator, content)
@@ -328,8 +375,16 @@ func (p *CPPParser) extractDeclaratorName(node *sitter.Node, content []byte) str
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		if child != nil && (child.Type() == "identifier" || child.Type() == "field_identifier") {
+		if child == nil {
+			continue
+		}
+		switch child.Type() {
+		case "identifier", "field_identifier":
 			return child.Content(content)
+		case "pointer_declarator", "reference_declarator":
+			// A function pointer, void (*handler)(int), or a declarator of
+			// a reference, whose declarator is not a field
+			return p.extractDeclaratorName(child, content)
 		}
 	}
 
@@ -347,13 +402,19 @@ func (p *CPPParser) getChildIdentifier(node *sitter.Node, content []byte) string
 }
 
 func (p *CPPParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
 			continue
 		}
 
-		if child.Type() == "preproc_include" {
+		switch child.Type() {
+		case "preproc_include":
 			pathNode := child.ChildByFieldName("path")
 			if pathNode != nil {
 				path := pathNode.Content(content)
@@ -364,62 +425,63 @@ func (p *CPPParser) extractRelationships(node *sitter.Node, content []byte, anal
 					Line:       int(child.StartPoint().Row) + 1,
 				})
 			}
-		}
 
-		if child.Type() == "base_class_clause" {
+		case "base_class_clause":
 			p.parseBaseClasses(child, content, analysis)
+
+		case "call_expression":
+			if target := cCallTarget(child, content); target != "" {
+				analysis.Relationships = append(analysis.Relationships, Relationship{
+					TargetSymbol: target,
+					Kind:         RelCall,
+					Line:         int(child.StartPoint().Row) + 1,
+					Column:       int(child.StartPoint().Column),
+				})
+			}
+
+		case "preproc_function_def", "preproc_def":
+			// A macro body is not code until it is expanded
+			continue
 		}
 
 		p.extractRelationships(child, content, analysis)
 	}
 }
 
+// parseBaseClasses records the base classes of a class as extends
+// relationships, without their template arguments: "class Sensor : public
+// Device, private detail::Base<T>" extends Device and detail::Base.
 func (p *CPPParser) parseBaseClasses(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child == nil {
-			continue
+	var source string
+	if parent := node.Parent(); parent != nil {
+		if nameNode := parent.ChildByFieldName("name"); nameNode != nil {
+			source = p.extractDeclaratorName(nameNode, content)
 		}
+	}
 
-		if child.Type() == "type_identifier" || child.Type() == "qualified_identifier" {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		var target string
+		switch child.Type() {
+		case "type_identifier":
+			target = child.Content(content)
+		case "template_type":
+			if name := child.ChildByFieldName("name"); name != nil {
+				target = name.Content(content)
+			}
+		case "qualified_identifier":
+			target = child.Content(content)
+			if idx := strings.Index(target, "<"); idx >= 0 {
+				target = target[:idx]
+			}
+		}
+		if target != "" {
 			analysis.Relationships = append(analysis.Relationships, Relationship{
-				TargetSymbol: child.Content(content),
+				SourceSymbol: source,
+				TargetSymbol: target,
 				Kind:         RelExtends,
 				Line:         int(child.StartPoint().Row) + 1,
 			})
 		}
 	}
 }
-
-func (p *CPPParser) extractFunctionSignature(node *sitter.Node, content []byte) string {
-	typeNode := node.ChildByFieldName("type")
-	declarator := node.ChildByFieldName("declarator")
-
-	var sig strings.Builder
-	if typeNode != nil {
-		sig.WriteString(typeNode.Content(content))
-		sig.WriteString(" ")
-	}
-	if declarator != nil {
-		sig.WriteString(declarator.Content(content))
-	}
-
-	return sig.String()
-}
-
-func (p *CPPParser) extractPrecedingComment(node *sitter.Node, content []byte) string {
-	prev := node.PrevSibling()
-	if prev == nil {
-		return ""
-	}
-
-	if prev.Type() == "comment" {
-		comment := prev.Content(content)
-		comment = strings.TrimPrefix(comment, "// ")
-		comment = strings.TrimPrefix(comment, "/* ")
-		comment = strings.TrimSuffix(comment, " */")
-		return comment
-	}
-
-	return ""
-}
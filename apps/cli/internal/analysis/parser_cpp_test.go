@@ -198,3 +198,158 @@ T max(T a, T b) {
 		}
 	})
 }
+
+func TestCPPParserFirmware(t *testing.T) {
+	code := `#include <memory>
+#include "drivers/device.h"
+
+#define LOG(msg) log_line(__FILE__, msg)
+
+namespace fw {
+
+/// A sensor read over a pin.
+template <typename T>
+class Sensor : public Device, private detail::Base<T> {
+public:
+    explicit Sensor(int pin);
+    virtual ~Sensor() = default;
+    T read() const override { return convert(raw_); }
+    static constexpr int kMax = 8;
+
+    enum class Mode { Idle, Active };
+
+private:
+    void reset();
+    T raw_;
+};
+
+struct Point {
+    int x, y;
+};
+
+using Reading = std::pair<int, float>;
+typedef unsigned long tick_t;
+
+int global_count = 0;
+static Widget w(1, 2);
+int checksum(const uint8_t *data, size_t len);
+
+template <typename T>
+T clamp(T v, T lo, T hi) {
+    return std::max(lo, std::min(v, hi));
+}
+
+void Sensor::reset() {
+    raw_ = helper::zero();
+    this->notify();
+    auto p = std::make_unique<Point>();
+    bus.flush();
+}
+
+}
+`
+	result, err := NewCPPParser().Parse([]byte(code), "src/sensor.cpp")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	symbols := []struct {
+		name      string
+		kind      SymbolKind
+		parent    string
+		signature string
+		doc       string
+		exported  bool
+	}{
+		{"fw", KindType, "", "", "", true},
+		{"Sensor", KindClass, "", "template <typename T> class Sensor : public Device, private detail::Base<T>", "A sensor read over a pin.", true},
+		{"Sensor", KindConstructor, "Sensor", "explicit Sensor(int pin)", "", true},
+		{"~Sensor", KindMethod, "Sensor", "virtual ~Sensor()", "", true},
+		{"read", KindMethod, "Sensor", "T read() const override", "", true},
+		{"kMax", KindProperty, "Sensor", "static constexpr int kMax", "", true},
+		{"Mode", KindEnum, "Sensor", "enum class Mode", "", true},
+		{"reset", KindMethod, "Sensor", "void reset()", "", false},
+		{"raw_", KindProperty, "Sensor", "T raw_", "", false},
+		{"Point", KindClass, "", "struct Point", "", true},
+		{"y", KindProperty, "Point", "int y", "", true},
+		{"Reading", KindTypeAlias, "", "using Reading = std::pair<int, float>", "", true},
+		{"tick_t", KindTypeAlias, "", "typedef unsigned long tick_t", "", true},
+		{"global_count", KindVariable, "", "int global_count", "", true},
+		{"w", KindVariable, "", "static Widget w", "", false},
+		{"checksum", KindFunction, "", "int checksum(const uint8_t *data, size_t len)", "", true},
+		{"clamp", KindFunction, "", "template <typename T> T clamp(T v, T lo, T hi)", "", true},
+	}
+	for _, tt := range symbols {
+		t.Run("symbol "+tt.parent+" "+tt.name, func(t *testing.T) {
+			in := result.Symbols
+			if tt.parent != "" {
+				parent := findSymbol(result.Symbols, tt.parent)
+				if parent == nil {
+					t.Fatalf("parent %s not found", tt.parent)
+				}
+				in = parent.Children
+			}
+			var sym *Symbol
+			for i := range in {
+				if in[i].Name == tt.name && in[i].Kind == tt.kind {
+					sym = &in[i]
+				}
+			}
+			if sym == nil {
+				t.Fatalf("%s %s not found under %q", tt.kind, tt.name, tt.parent)
+			}
+			if sym.Signature != tt.signature {
+				t.Errorf("%s.Signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+			}
+			if sym.DocComment != tt.doc {
+				t.Errorf("%s.DocComment = %q, want %q", tt.name, sym.DocComment, tt.doc)
+			}
+			if sym.Exported != tt.exported {
+				t.Errorf("%s.Exported = %v, want %v", tt.name, sym.Exported, tt.exported)
+			}
+		})
+	}
+	// The namespace and its nine declarations; members and the locals of
+	// function bodies are not listed at the top level, nor is the macro
+	if len(result.Symbols) != 10 {
+		t.Errorf("expected 10 top-level symbols, got %+v", result.Symbols)
+	}
+	for _, name := range []string{"LOG", "raw_", "p"} {
+		for _, sym := range result.Symbols {
+			if sym.Name == name {
+				t.Errorf("unexpected top-level symbol %s", name)
+			}
+		}
+	}
+
+	relationships := []struct {
+		kind   RelationshipKind
+		source string
+		target string
+	}{
+		{RelImport, "", "memory"},
+		{RelImport, "", "drivers/device.h"},
+		{RelExtends, "Sensor", "Device"},
+		{RelExtends, "Sensor", "detail::Base"},
+		{RelCall, "", "convert"},
+		{RelCall, "", "std::max"},
+		{RelCall, "", "helper::zero"},
+		{RelCall, "", "this.notify"},
+		{RelCall, "", "std::make_unique"},
+		{RelCall, "", "bus.flush"},
+	}
+	for _, tt := range relationships {
+		t.Run(string(tt.kind)+" "+tt.target, func(t *testing.T) {
+			for _, rel := range result.Relationships {
+				target := rel.TargetSymbol
+				if rel.Kind == RelImport {
+					target = rel.TargetFile
+				}
+				if rel.Kind == tt.kind && rel.SourceSymbol == tt.source && target == tt.target {
+					return
+				}
+			}
+			t.Errorf("no %s relationship from %q to %q in %+v", tt.kind, tt.source, tt.target, result.Relationships)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 )
 
 type CSharpParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -43,6 +44,11 @@ func (p *CSharpParser) Parse(content []byte, filePath string) (*FileAnalysis, er
 }
 
 func (p *CSharpParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -108,13 +114,14 @@ func (p *CSharpParser) parseClass(node *sitter.Node, content []byte) *Symbol {
 	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindClass,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
-		Children:   children,
+		Name:        name,
+		Kind:        KindClass,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		DocComment:  doc,
+		Exported:    exported,
+		Children:    children,
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -135,13 +142,14 @@ func (p *CSharpParser) parseInterface(node *sitter.Node, content []byte) *Symbol
 	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindInterface,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
-		Children:   children,
+		Name:        name,
+		Kind:        KindInterface,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		DocComment:  doc,
+		Exported:    exported,
+		Children:    children,
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -162,13 +170,14 @@ func (p *CSharpParser) parseStruct(node *sitter.Node, content []byte) *Symbol {
 	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindClass,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
-		Children:   children,
+		Name:        name,
+		Kind:        KindClass,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		DocComment:  doc,
+		Exported:    exported,
+		Children:    children,
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -203,13 +212,14 @@ func (p *CSharpParser) parseEnum(node *sitter.Node, content []byte) *Symbol {
 	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindEnum,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
-		Children:   children,
+		Name:        name,
+		Kind:        KindEnum,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		DocComment:  doc,
+		Exported:    exported,
+		Children:    children,
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -225,13 +235,14 @@ func (p *CSharpParser) parseMethod(node *sitter.Node, content []byte) *Symbol {
 	exported := p.isPublic(node, content)
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindMethod,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		Signature:  sig,
-		DocComment: doc,
-		Exported:   exported,
+		Name:        name,
+		Kind:        KindMethod,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   sig,
+		DocComment:  doc,
+		Exported:    exported,
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -278,11 +289,12 @@ func (p *CSharpParser) parseFieldOrProperty(node *sitter.Node, content []byte, a
 	exported := p.isPublic(node, content)
 
 	analysis.Symbols = append(analysis.Symbols, Symbol{
-		Name:      name,
-		Kind:      KindProperty,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  exported,
+		Name:        name,
+		Kind:        KindProperty,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Exported:    exported,
+		Annotations: p.annotations(node, content),
 	})
 }
 
@@ -305,11 +317,12 @@ func (p *CSharpParser) extractClassMembers(node *sitter.Node, content []byte) []
 			nameNode := child.ChildByFieldName("name")
 			if nameNode != nil {
 				members = append(members, Symbol{
-					Name:      nameNode.Content(content),
-					Kind:      KindConstructor,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  p.isPublic(child, content),
+					Name:        nameNode.Content(content),
+					Kind:        KindConstructor,
+					LineStart:   int(child.StartPoint().Row) + 1,
+					LineEnd:     int(child.EndPoint().Row) + 1,
+					Exported:    p.isPublic(child, content),
+					Annotations: p.annotations(child, content),
 				})
 			}
 
@@ -317,11 +330,12 @@ func (p *CSharpParser) extractClassMembers(node *sitter.Node, content []byte) []
 			nameNode := child.ChildByFieldName("name")
 			if nameNode != nil {
 				members = append(members, Symbol{
-					Name:      nameNode.Content(content),
-					Kind:      KindProperty,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  p.isPublic(child, content),
+					Name:        nameNode.Content(content),
+					Kind:        KindProperty,
+					LineStart:   int(child.StartPoint().Row) + 1,
+					LineEnd:     int(child.EndPoint().Row) + 1,
+					Exported:    p.isPublic(child, content),
+					Annotations: p.annotations(child, content),
 				})
 			}
 
@@ -335,11 +349,12 @@ func (p *CSharpParser) extractClassMembers(node *sitter.Node, content []byte) []
 							nameNode := declarator.ChildByFieldName("name")
 							if nameNode != nil {
 								members = append(members, Symbol{
-									Name:      nameNode.Content(content),
-									Kind:      KindProperty,
-									LineStart: int(child.StartPoint().Row) + 1,
-									LineEnd:   int(child.EndPoint().Row) + 1,
-									Exported:  p.isPublic(child, content),
+									Name:        nameNode.Content(content),
+									Kind:        KindProperty,
+									LineStart:   int(child.StartPoint().Row) + 1,
+									LineEnd:     int(child.EndPoint().Row) + 1,
+									Exported:    p.isPublic(child, content),
+									Annotations: p.annotations(child, content),
 								})
 							}
 						}
@@ -364,11 +379,12 @@ func (p *CSharpParser) extractInterfaceMembers(node *sitter.Node, content []byte
 			nameNode := child.ChildByFieldName("name")
 			if nameNode != nil {
 				members = append(members, Symbol{
-					Name:      nameNode.Content(content),
-					Kind:      KindMethod,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  true,
+					Name:        nameNode.Content(content),
+					Kind:        KindMethod,
+					LineStart:   int(child.StartPoint().Row) + 1,
+					LineEnd:     int(child.EndPoint().Row) + 1,
+					Exported:    true,
+					Annotations: p.annotations(child, content),
 				})
 			}
 
@@ -376,11 +392,12 @@ func (p *CSharpParser) extractInterfaceMembers(node *sitter.Node, content []byte
 			nameNode := child.ChildByFieldName("name")
 			if nameNode != nil {
 				members = append(members, Symbol{
-					Name:      nameNode.Content(content),
-					Kind:      KindProperty,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  true,
+					Name:        nameNode.Content(content),
+					Kind:        KindProperty,
+					LineStart:   int(child.StartPoint().Row) + 1,
+					LineEnd:     int(child.EndPoint().Row) + 1,
+					Exported:    true,
+					Annotations: p.annotations(child, content),
 				})
 			}
 		}
@@ -389,6 +406,11 @@ func (p *CSharpParser) extractInterfaceMembers(node *sitter.Node, content []byte
 }
 
 func (p *CSharpParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -499,3 +521,20 @@ func (p *CSharpParser) extractXmlDoc(node *sitter.Node, content []byte) string {
 
 	return ""
 }
+
+// annotations returns the attributes on a C# declaration (e.g. "[ApiController]").
+func (p *CSharpParser) annotations(node *sitter.Node, content []byte) []string {
+	switch node.Type() {
+	case "class_declaration", "interface_declaration", "struct_declaration", "record_declaration", "enum_declaration",
+		"method_declaration", "constructor_declaration", "property_declaration", "field_declaration":
+		var result []string
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			list := node.NamedChild(i)
+			if list != nil && list.Type() == "attribute_list" {
+				result = append(result, childAnnotations(list, content, "", "attribute")...)
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type CSSParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *CSSParser) Parse(content []byte, filePath string) (*FileAnalysis, error
 }
 
 func (p *CSSParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -113,6 +119,11 @@ func (p *CSSParser) parseRuleSet(node *sitter.Node, content []byte, analysis *Fi
 }
 
 func (p *CSSParser) extractSelectorNames(node *sitter.Node, content []byte) []string {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var names []string
 
 	switch node.Type() {
@@ -215,6 +226,11 @@ func (p *CSSParser) parseCustomProperty(node *sitter.Node, content []byte, analy
 }
 
 func (p *CSSParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
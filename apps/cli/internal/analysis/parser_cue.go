@@ -5,7 +5,13 @@ import (
 	"strings"
 )
 
-type CUEParser struct{}
+// CUEParser extracts definitions, lets, and definition fields from CUE with
+// regular expressions. When strict, it only reports fields declared
+// directly in a definition, leaving out those of nested structs; when
+// loose, it also reports top-level fields that are not structs.
+type CUEParser struct {
+	strictness Strictness
+}
 
 func NewCUEParser() *CUEParser {
 	return &CUEParser{}
@@ -15,6 +21,11 @@ func (p *CUEParser) Language() Language {
 	return LangCUE
 }
 
+// SetStrictness implements StrictnessParser.
+func (p *CUEParser) SetStrictness(s Strictness) {
+	p.strictness = s
+}
+
 func (p *CUEParser) Parse(content []byte, filePath string) (*FileAnalysis, error) {
 	analysis := &FileAnalysis{
 		Path:     filePath,
@@ -33,6 +44,7 @@ var (
 	cueImportRe     = regexp.MustCompile(`^\s*"([^"]+)"`)
 	cueDefinitionRe = regexp.MustCompile(`^#?(\w+):\s*\{`)
 	cueFieldRe      = regexp.MustCompile(`^\s+(\w+):\s+`)
+	cueTopFieldRe   = regexp.MustCompile(`^(#?\w+):\s*[^\s{]`)
 	cueLetRe        = regexp.MustCompile(`^let\s+(\w+)\s*=`)
 	cueCommentRe    = regexp.MustCompile(`^//\s*(.*)`)
 )
@@ -124,8 +136,31 @@ func (p *CUEParser) extractSymbols(lines []string, analysis *FileAnalysis) {
 			continue
 		}
 
+		if currentDef == "" && p.strictness == StrictnessLoose {
+			if matches := cueTopFieldRe.FindStringSubmatch(line); len(matches) > 1 {
+				kind := KindVariable
+				if strings.HasPrefix(matches[1], "#") {
+					kind = KindType
+				}
+				analysis.Symbols = append(analysis.Symbols, Symbol{
+					Name:          matches[1],
+					Kind:          kind,
+					LineStart:     lineNum,
+					LineEnd:       lineNum,
+					DocComment:    pendingDoc,
+					Exported:      !strings.HasPrefix(matches[1], "_"),
+					LowConfidence: true,
+				})
+				pendingDoc = ""
+				continue
+			}
+		}
+
 		if currentDef != "" && braceCount > 0 {
-			if matches := cueFieldRe.FindStringSubmatch(line); len(matches) > 1 {
+			// A field inside a nested struct is not the definition's own;
+			// balanced attributes it to the definition anyway
+			nested := braceCount > 1 && p.strictness == StrictnessStrict
+			if matches := cueFieldRe.FindStringSubmatch(line); len(matches) > 1 && !nested {
 				fieldName := matches[1]
 				if !strings.HasPrefix(fieldName, "_") {
 					for j := range analysis.Symbols {
@@ -6,8 +6,14 @@ import (
 )
 
 // DartParser uses regex-based parsing since there are no stable
-// Tree-sitter Go bindings for Dart.
-type DartParser struct{}
+// Tree-sitter Go bindings for Dart. Its heuristics follow a Strictness:
+// loose also reports top-level functions declared without a return type,
+// as low-confidence guesses; strict drops declarations whose block never
+// closes, functions and methods whose return type does not read as a type,
+// and extends clauses naming a lowercase type.
+type DartParser struct {
+	strictness Strictness
+}
 
 func NewDartParser() *DartParser {
 	return &DartParser{}
@@ -17,6 +23,11 @@ func (p *DartParser) Language() Language {
 	return LangDart
 }
 
+// SetStrictness implements StrictnessParser.
+func (p *DartParser) SetStrictness(s Strictness) {
+	p.strictness = s
+}
+
 var (
 	dartClassRegex     = regexp.MustCompile(`(?m)^(\s*)(abstract\s+)?class\s+(\w+)(?:\s+extends\s+(\w+))?(?:\s+(?:with|implements)\s+[\w\s,]+)?\s*\{`)
 	dartMixinRegex     = regexp.MustCompile(`(?m)^(\s*)mixin\s+(\w+)(?:\s+on\s+[\w\s,]+)?\s*\{`)
@@ -33,7 +44,13 @@ var (
 	dartPartOfRegex    = regexp.MustCompile(`(?m)^part\s+of\s+['"]([^'"]+)['"]`)
 	dartExtensionRegex = regexp.MustCompile(`(?m)^(\s*)extension\s+(\w+)?\s+on\s+(\w+)`)
 	dartTypedefRegex   = regexp.MustCompile(`(?m)^(\s*)typedef\s+(\w+)`)
-	_                  = regexp.MustCompile(`(?m)^(\s*)///\s*(.*)$`)
+	// A top-level function without a return type, "main() {", reported
+	// only when loose
+	dartUntypedFunctionRegex = regexp.MustCompile(`(?m)^(\w+)\s*\(([^)]*)\)\s*(async\s*)?\{`)
+	// A return or field type as strict mode expects it: one name, maybe
+	// qualified, generic, or nullable
+	dartTypeRegex = regexp.MustCompile(`^[A-Za-z_$][\w$.]*(?:<[\w$.<>,\s?]*>)?\??$`)
+	_             = regexp.MustCompile(`(?m)^(\s*)///\s*(.*)$`)
 )
 
 func (p *DartParser) Parse(content []byte, filePath string) (*FileAnalysis, error) {
@@ -68,6 +85,9 @@ func (p *DartParser) extractSymbols(lines []string, analysis *FileAnalysis) {
 		name := fullContent[nameStart:nameEnd]
 		colStart := p.columnAt(fullContent, nameStart)
 
+		if !p.closes(lines, lineNum-1) {
+			continue
+		}
 		isAbstract := match[2] != -1 && match[3] != -1
 		doc := p.extractDocComment(lines, lineNum-1)
 
@@ -95,6 +115,9 @@ func (p *DartParser) extractSymbols(lines []string, analysis *FileAnalysis) {
 	// Extract mixins
 	for _, match := range dartMixinRegex.FindAllStringSubmatchIndex(fullContent, -1) {
 		lineNum := p.lineNumberAt(fullContent, match[0])
+		if !p.closes(lines, lineNum-1) {
+			continue
+		}
 		nameStart, nameEnd := match[4], match[5]
 		name := fullContent[nameStart:nameEnd]
 		colStart := p.columnAt(fullContent, nameStart)
@@ -120,6 +143,9 @@ func (p *DartParser) extractSymbols(lines []string, analysis *FileAnalysis) {
 	// Extract enums
 	for _, match := range dartEnumRegex.FindAllStringSubmatchIndex(fullContent, -1) {
 		lineNum := p.lineNumberAt(fullContent, match[0])
+		if !p.closes(lines, lineNum-1) {
+			continue
+		}
 		nameStart, nameEnd := match[4], match[5]
 		name := fullContent[nameStart:nameEnd]
 		colStart := p.columnAt(fullContent, nameStart)
@@ -204,6 +230,9 @@ func (p *DartParser) extractSymbols(lines []string, analysis *FileAnalysis) {
 		if returnType == "if" || returnType == "for" || returnType == "while" || returnType == "switch" || returnType == "return" {
 			continue
 		}
+		if !p.plausibleType(returnType) || !p.closes(lines, lineNum-1) {
+			continue
+		}
 
 		analysis.Symbols = append(analysis.Symbols, Symbol{
 			Name:       name,
@@ -217,6 +246,10 @@ func (p *DartParser) extractSymbols(lines []string, analysis *FileAnalysis) {
 		})
 	}
 
+	if p.strictness == StrictnessLoose {
+		p.extractUntypedFunctions(lines, fullContent, analysis)
+	}
+
 	// Extract top-level constants
 	for _, match := range dartConstRegex.FindAllStringSubmatchIndex(fullContent, -1) {
 		lineNum := p.lineNumberAt(fullContent, match[0])
@@ -277,6 +310,9 @@ func (p *DartParser) extractClassMembers(lines []string, startLine, endLine int)
 		if returnType == "if" || returnType == "for" || returnType == "while" || returnType == "switch" || returnType == "return" {
 			continue
 		}
+		if !p.plausibleType(returnType) {
+			continue
+		}
 
 		kind := KindMethod
 		if name == "constructor" || strings.Contains(name, ".") {
@@ -433,6 +469,9 @@ func (p *DartParser) extractRelationships(lines []string, analysis *FileAnalysis
 		// Check if extends is present
 		if match[8] != -1 && match[9] != -1 {
 			parentClass := fullContent[match[8]:match[9]]
+			if p.strictness == StrictnessStrict && !isUpperASCII(parentClass[0]) {
+				continue
+			}
 			analysis.Relationships = append(analysis.Relationships, Relationship{
 				TargetSymbol: parentClass,
 				Kind:         RelExtends,
@@ -442,6 +481,69 @@ func (p *DartParser) extractRelationships(lines []string, analysis *FileAnalysis
 	}
 }
 
+// extractUntypedFunctions adds the top-level functions declared without a
+// return type, as "main() {", marked LowConfidence: Dart allows them, but
+// the same shape is also a top-level call that opens a block by mistake.
+func (p *DartParser) extractUntypedFunctions(lines []string, fullContent string, analysis *FileAnalysis) {
+	declared := make(map[int]bool)
+	for _, sym := range analysis.Symbols {
+		for line := sym.LineStart; line <= sym.LineEnd; line++ {
+			declared[line] = true
+		}
+	}
+	for _, match := range dartUntypedFunctionRegex.FindAllStringSubmatchIndex(fullContent, -1) {
+		lineNum := p.lineNumberAt(fullContent, match[0])
+		name := fullContent[match[2]:match[3]]
+		if declared[lineNum] || genericControlWords[name] {
+			continue
+		}
+		analysis.Symbols = append(analysis.Symbols, Symbol{
+			Name:          name,
+			Kind:          KindFunction,
+			LineStart:     lineNum,
+			LineEnd:       p.findBlockEnd(lines, lineNum-1),
+			ColStart:      p.columnAt(fullContent, match[2]),
+			Signature:     name + "(" + fullContent[match[4]:match[5]] + ")",
+			DocComment:    p.extractDocComment(lines, lineNum-1),
+			Exported:      !strings.HasPrefix(name, "_"),
+			LowConfidence: true,
+		})
+	}
+}
+
+// plausibleType reports whether a matched return type may be kept: always,
+// unless strict, which wants a single type name such as "Future<int>?".
+func (p *DartParser) plausibleType(typ string) bool {
+	return p.strictness != StrictnessStrict || dartTypeRegex.MatchString(typ)
+}
+
+// closes reports whether the block opened on line startIdx may be kept:
+// always, unless strict, which wants its closing brace found.
+func (p *DartParser) closes(lines []string, startIdx int) bool {
+	if p.strictness != StrictnessStrict {
+		return true
+	}
+	depth := 0
+	for _, line := range lines[startIdx:] {
+		for _, ch := range line {
+			switch ch {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isUpperASCII(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
 func (p *DartParser) lineNumberAt(content string, pos int) int {
 	return strings.Count(content[:pos], "\n") + 1
 }
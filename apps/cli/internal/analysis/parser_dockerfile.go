@@ -9,6 +9,7 @@ import (
 )
 
 type DockerfileParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *DockerfileParser) Parse(content []byte, filePath string) (*FileAnalysis
 }
 
 func (p *DockerfileParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	stageCount := 0
 
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -238,6 +244,11 @@ func (p *DockerfileParser) parseEntrypoint(node *sitter.Node, _ []byte) *Symbol
 }
 
 func (p *DockerfileParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -9,6 +9,7 @@ import (
 )
 
 type ElixirParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *ElixirParser) Parse(content []byte, filePath string) (*FileAnalysis, er
 }
 
 func (p *ElixirParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -277,6 +283,11 @@ func (p *ElixirParser) extractFirstArg(args *sitter.Node, content []byte) string
 }
 
 func (p *ElixirParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
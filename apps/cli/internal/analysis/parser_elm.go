@@ -9,6 +9,7 @@ import (
 )
 
 type ElmParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *ElmParser) Parse(content []byte, filePath string) (*FileAnalysis, error
 }
 
 func (p *ElmParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -274,6 +280,11 @@ func (p *ElmParser) parsePort(node *sitter.Node, content []byte) *Symbol {
 }
 
 func (p *ElmParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
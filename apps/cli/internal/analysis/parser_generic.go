@@ -0,0 +1,203 @@
+package analysis
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// GenericParser is the fallback for text files in languages no other parser
+// handles. It knows no grammar: it looks for lines that read like
+// definitions in most languages ("def x", "fn x", "class X", "x(a) {") and
+// takes their extent from braces or indentation. Every symbol it reports is
+// marked LowConfidence. When strict, it keeps only definitions introduced
+// by a keyword, dropping the "x(a) {" shape that calls can take too.
+type GenericParser struct {
+	strictness Strictness
+}
+
+// NewGenericParser creates a generic fallback parser.
+func NewGenericParser() *GenericParser {
+	return &GenericParser{}
+}
+
+// Language returns LangGeneric.
+func (p *GenericParser) Language() Language {
+	return LangGeneric
+}
+
+// SetStrictness implements StrictnessParser.
+func (p *GenericParser) SetStrictness(s Strictness) {
+	p.strictness = s
+}
+
+var (
+	// A definition keyword, after optional modifiers, followed by a name
+	genericDefRe = regexp.MustCompile(`^\s*(?:(?:pub|public|private|protected|export|static|async|local|global|abstract|final|inline)\s+)*(def|defn|defun|fn|fun|func|function|proc|procedure|sub|method|macro|class|struct|object|record|impl|module|namespace|interface|trait|protocol|type|enum|let|var|val|const)\s+([A-Za-z_][\w.:!?-]*)`)
+	// A name with a parameter list that opens a block: "name(a, b) {"
+	genericCallDefRe = regexp.MustCompile(`^\s*([A-Za-z_][\w.]*)\s*\(([^)]*)\)\s*(?:\{|=>|=|:)\s*$`)
+	// Comment markers of common languages
+	genericCommentRe = regexp.MustCompile(`^\s*(?://+|#+|--+|;+|%+|/\*+|\*+/?|\(\*)\s?`)
+)
+
+// genericControlWords read like calls with a block but never define anything.
+var genericControlWords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"return": true, "elif": true, "until": true, "unless": true, "match": true,
+}
+
+// genericKinds maps definition keywords to symbol kinds; anything missing
+// is a function.
+var genericKinds = map[string]SymbolKind{
+	"class": KindClass, "struct": KindClass, "object": KindClass, "record": KindClass, "impl": KindClass,
+	"module": KindClass, "namespace": KindClass,
+	"interface": KindInterface, "trait": KindInterface, "protocol": KindInterface,
+	"type": KindType, "enum": KindEnum,
+	"let": KindVariable, "var": KindVariable, "val": KindVariable, "const": KindConstant,
+}
+
+// Parse extracts heuristic symbols. Definitions that fall inside another
+// definition's block become its children.
+func (p *GenericParser) Parse(content []byte, filePath string) (*FileAnalysis, error) {
+	analysis := &FileAnalysis{
+		Path:     filePath,
+		Language: string(LangGeneric),
+	}
+	lines := strings.Split(string(content), "\n")
+
+	var flat []Symbol
+	for i, line := range lines {
+		sym, ok := genericDefinition(line, p.strictness)
+		if !ok {
+			continue
+		}
+		sym.LineStart = i + 1
+		sym.LineEnd = genericBlockEnd(lines, i)
+		sym.DocComment = genericDocComment(lines, i)
+		flat = append(flat, sym)
+	}
+	analysis.Symbols = nestGenericSymbols(flat)
+	return analysis, nil
+}
+
+// genericDefinition recognizes a line that defines something.
+func genericDefinition(line string, strictness Strictness) (Symbol, bool) {
+	if genericCommentRe.MatchString(line) {
+		return Symbol{}, false
+	}
+	sig := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line), "{:=>"))
+	if m := genericDefRe.FindStringSubmatch(line); m != nil {
+		kind, ok := genericKinds[m[1]]
+		if !ok {
+			kind = KindFunction
+		}
+		// Variables only count at the top level; inside blocks they are locals
+		if (kind == KindVariable || kind == KindConstant) && leadingIndent(line) > 0 {
+			return Symbol{}, false
+		}
+		return Symbol{Name: m[2], Kind: kind, Signature: sig, ColStart: strings.Index(line, m[2]), LowConfidence: true}, true
+	}
+	if strictness == StrictnessStrict {
+		return Symbol{}, false
+	}
+	if m := genericCallDefRe.FindStringSubmatch(line); m != nil && !genericControlWords[strings.ToLower(m[1])] {
+		return Symbol{Name: m[1], Kind: KindFunction, Signature: sig, ColStart: strings.Index(line, m[1]), LowConfidence: true}, true
+	}
+	return Symbol{}, false
+}
+
+// genericBlockEnd returns the 1-based last line of the block opened on line
+// start: the matching close brace if the line opens one, otherwise the last
+// line indented deeper than it (plus a closing "end" at the same indent).
+func genericBlockEnd(lines []string, start int) int {
+	if depth := strings.Count(lines[start], "{") - strings.Count(lines[start], "}"); depth > 0 {
+		for i := start + 1; i < len(lines); i++ {
+			depth += strings.Count(lines[i], "{") - strings.Count(lines[i], "}")
+			if depth <= 0 {
+				return i + 1
+			}
+		}
+		return len(lines)
+	}
+
+	indent := leadingIndent(lines[start])
+	end := start
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if leadingIndent(lines[i]) <= indent {
+			if strings.TrimSpace(lines[i]) == "end" && end > start {
+				end = i
+			}
+			break
+		}
+		end = i
+	}
+	return end + 1
+}
+
+// genericDocComment joins the comment lines directly above line i.
+func genericDocComment(lines []string, i int) string {
+	start := i
+	for start > 0 && genericCommentRe.MatchString(lines[start-1]) && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	var doc []string
+	for _, line := range lines[start:i] {
+		if text := strings.TrimSpace(genericCommentRe.ReplaceAllString(line, "")); text != "" {
+			doc = append(doc, text)
+		}
+	}
+	return strings.Join(doc, "\n")
+}
+
+// nestGenericSymbols turns definitions listed in source order into a tree,
+// by line containment. Functions inside a class become methods.
+func nestGenericSymbols(flat []Symbol) []Symbol {
+	var build func(items []Symbol) []Symbol
+	build = func(items []Symbol) []Symbol {
+		var out []Symbol
+		for i := 0; i < len(items); {
+			sym := items[i]
+			j := i + 1
+			for j < len(items) && items[j].LineStart <= sym.LineEnd {
+				j++
+			}
+			children := build(items[i+1 : j])
+			for k := range children {
+				if sym.Kind == KindClass && children[k].Kind == KindFunction {
+					children[k].Kind = KindMethod
+				}
+			}
+			sym.Children = children
+			out = append(out, sym)
+			i = j
+		}
+		return out
+	}
+	return build(flat)
+}
+
+// leadingIndent counts leading whitespace, with a tab as four spaces.
+func leadingIndent(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// isText reports whether content looks like text rather than binary data:
+// valid UTF-8 with no NUL bytes.
+func isText(content []byte) bool {
+	return bytes.IndexByte(content, 0) < 0 && utf8.Valid(content)
+}
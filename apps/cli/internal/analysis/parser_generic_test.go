@@ -0,0 +1,61 @@
+package analysis
+
+import "testing"
+
+func TestGenericFallbackParser(t *testing.T) {
+	src := `-- Greets everyone on the list.
+proc greet(names) {
+  for name in names {
+    say("hello " + name)
+  }
+}
+
+class Counter
+  method increment
+    count = count + 1
+end
+`
+	reg := NewParserRegistry()
+	fa, err := reg.Parse([]byte(src), "hello.zork")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(fa.Symbols) != 0 {
+		t.Fatalf("expected no symbols without the fallback, got %+v", fa.Symbols)
+	}
+
+	reg.SetGenericFallback(true)
+	fa, err = reg.Parse([]byte(src), "hello.zork")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if fa.Language != string(LangGeneric) {
+		t.Errorf("expected language %q, got %q", LangGeneric, fa.Language)
+	}
+	greet := findSymbol(fa.Symbols, "greet")
+	if greet == nil {
+		t.Fatalf("expected a greet symbol, got %+v", fa.Symbols)
+	}
+	if !greet.LowConfidence || greet.Kind != KindFunction || greet.LineStart != 2 || greet.LineEnd != 6 {
+		t.Errorf("unexpected greet symbol: %+v", *greet)
+	}
+	if greet.DocComment != "Greets everyone on the list." {
+		t.Errorf("expected the comment above as doc, got %q", greet.DocComment)
+	}
+	increment := findSymbol(fa.Symbols, "increment")
+	if increment == nil || increment.Kind != KindMethod || !increment.LowConfidence {
+		t.Errorf("expected increment as a low-confidence method of Counter, got %+v", fa.Symbols)
+	}
+}
+
+func TestGenericFallbackSkipsBinary(t *testing.T) {
+	reg := NewParserRegistry()
+	reg.SetGenericFallback(true)
+	fa, err := reg.Parse([]byte("def x\x00\x01\x02"), "blob.bin")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(fa.Symbols) != 0 || fa.Language != string(LangUnknown) {
+		t.Errorf("expected binary content to be skipped, got %+v", fa)
+	}
+}
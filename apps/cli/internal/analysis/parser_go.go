@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"unicode"
 
@@ -10,6 +11,7 @@ import (
 )
 
 type GoParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -43,6 +45,11 @@ func (p *GoParser) Parse(content []byte, filePath string) (*FileAnalysis, error)
 }
 
 func (p *GoParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -91,6 +98,8 @@ func (p *GoParser) parseFunctionDecl(node *sitter.Node, content []byte) *Symbol
 		Signature:  sig,
 		DocComment: doc,
 		Exported:   isExported(name),
+		Children:   p.extractInlineTypes(node, content),
+		TypeParams: extractTypeParams(node, content),
 	}
 }
 
@@ -123,13 +132,24 @@ func (p *GoParser) parseMethodDecl(node *sitter.Node, content []byte) *Symbol {
 		Signature:  fullSig,
 		DocComment: doc,
 		Exported:   isExported(name),
+		Children:   p.extractInlineTypes(node, content),
+		TypeParams: extractTypeParams(node, content),
 	}
 }
 
 func (p *GoParser) parseTypeDecl(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	for i := 0; i < int(node.ChildCount()); i++ {
 		spec := node.Child(i)
-		if spec == nil || spec.Type() != "type_spec" {
+		if spec == nil {
+			continue
+		}
+		if spec.Type() == "type_alias" {
+			if sym := p.parseTypeAlias(node, spec, content); sym != nil {
+				analysis.Symbols = append(analysis.Symbols, *sym)
+			}
+			continue
+		}
+		if spec.Type() != "type_spec" {
 			continue
 		}
 
@@ -168,11 +188,41 @@ func (p *GoParser) parseTypeDecl(node *sitter.Node, content []byte, analysis *Fi
 			DocComment: doc,
 			Exported:   isExported(name),
 			Children:   children,
+			TypeParams: extractTypeParams(spec, content),
 		})
 	}
 }
 
+// parseTypeAlias handles "type UserID = int64". Unlike a defined type, an
+// alias is interchangeable with the type it names.
+func (p *GoParser) parseTypeAlias(decl, spec *sitter.Node, content []byte) *Symbol {
+	nameNode := spec.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(content)
+	sig := "type " + name
+	if typeNode := spec.ChildByFieldName("type"); typeNode != nil {
+		sig += " = " + typeNode.Content(content)
+	}
+	return &Symbol{
+		Name:       name,
+		Kind:       KindTypeAlias,
+		LineStart:  int(spec.StartPoint().Row) + 1,
+		LineEnd:    int(spec.EndPoint().Row) + 1,
+		Signature:  sig,
+		DocComment: p.extractPrecedingComment(decl, content),
+		Exported:   isExported(name),
+		TypeParams: extractTypeParams(spec, content),
+	}
+}
+
 func (p *GoParser) extractStructFields(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var fields []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -186,18 +236,23 @@ func (p *GoParser) extractStructFields(node *sitter.Node, content []byte) []Symb
 				continue
 			}
 
-			nameNode := field.ChildByFieldName("name")
-			if nameNode == nil {
-				continue
+			// A field of inline struct type gets that struct's fields
+			_, members := p.inlineTypeMembers(field.ChildByFieldName("type"), content)
+			// "X, Y int" declares a field per name
+			for k := 0; k < int(field.ChildCount()); k++ {
+				if field.FieldNameForChild(k) != "name" {
+					continue
+				}
+				name := field.Child(k).Content(content)
+				fields = append(fields, Symbol{
+					Name:      name,
+					Kind:      KindProperty,
+					LineStart: int(field.StartPoint().Row) + 1,
+					LineEnd:   int(field.EndPoint().Row) + 1,
+					Exported:  isExported(name),
+					Children:  members,
+				})
 			}
-
-			fields = append(fields, Symbol{
-				Name:      nameNode.Content(content),
-				Kind:      KindProperty,
-				LineStart: int(field.StartPoint().Row) + 1,
-				LineEnd:   int(field.EndPoint().Row) + 1,
-				Exported:  isExported(nameNode.Content(content)),
-			})
 		}
 	}
 	return fields
@@ -211,7 +266,8 @@ func (p *GoParser) extractInterfaceMethods(node *sitter.Node, content []byte) []
 			continue
 		}
 
-		if child.Type() == "method_spec" {
+		// Grammars before 0.20 call these method_spec
+		if child.Type() == "method_elem" || child.Type() == "method_spec" {
 			nameNode := child.ChildByFieldName("name")
 			if nameNode == nil {
 				continue
@@ -222,6 +278,7 @@ func (p *GoParser) extractInterfaceMethods(node *sitter.Node, content []byte) []
 				Kind:      KindMethod,
 				LineStart: int(child.StartPoint().Row) + 1,
 				LineEnd:   int(child.EndPoint().Row) + 1,
+				Signature: child.Content(content),
 				Exported:  isExported(nameNode.Content(content)),
 			})
 		}
@@ -229,6 +286,93 @@ func (p *GoParser) extractInterfaceMethods(node *sitter.Node, content []byte) []
 	return methods
 }
 
+// extractInlineTypes returns the struct and interface types written inline
+// in a function's parameters and results, such as "func load() struct{ ID
+// int }", each as a symbol holding its fields or methods. The symbol is
+// named after its parameter or result; an unnamed one is called "param" or
+// "result", numbered by position when the list has several. Empty types
+// like the struct{} of "chan struct{}" are left out.
+func (p *GoParser) extractInlineTypes(node *sitter.Node, content []byte) []Symbol {
+	var inline []Symbol
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		inline = append(inline, p.inlineParamTypes(params, "param", content)...)
+	}
+	if result := node.ChildByFieldName("result"); result != nil {
+		if result.Type() == "parameter_list" {
+			inline = append(inline, p.inlineParamTypes(result, "result", content)...)
+		} else if sym := p.inlineType(result, "result", content); sym != nil {
+			inline = append(inline, *sym)
+		}
+	}
+	return inline
+}
+
+func (p *GoParser) inlineParamTypes(list *sitter.Node, unnamed string, content []byte) []Symbol {
+	var decls []*sitter.Node
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		child := list.NamedChild(i)
+		if child.Type() == "parameter_declaration" || child.Type() == "variadic_parameter_declaration" {
+			decls = append(decls, child)
+		}
+	}
+	var inline []Symbol
+	for i, decl := range decls {
+		name := unnamed
+		if nameNode := decl.ChildByFieldName("name"); nameNode != nil {
+			name = nameNode.Content(content)
+		} else if len(decls) > 1 {
+			name = fmt.Sprintf("%s%d", unnamed, i+1)
+		}
+		if sym := p.inlineType(decl.ChildByFieldName("type"), name, content); sym != nil {
+			inline = append(inline, *sym)
+		}
+	}
+	return inline
+}
+
+// inlineType returns a symbol called name for the inline struct or
+// interface in typeNode, or nil if there is none or it is empty.
+func (p *GoParser) inlineType(typeNode *sitter.Node, name string, content []byte) *Symbol {
+	kind, members := p.inlineTypeMembers(typeNode, content)
+	if len(members) == 0 {
+		return nil
+	}
+	return &Symbol{
+		Name:      name,
+		Kind:      kind,
+		LineStart: int(typeNode.StartPoint().Row) + 1,
+		LineEnd:   int(typeNode.EndPoint().Row) + 1,
+		Children:  members,
+	}
+}
+
+// inlineTypeMembers finds an inline struct or interface in typeNode,
+// looking through pointers, slices, arrays, maps, and channels, and returns
+// its kind and its fields or methods.
+func (p *GoParser) inlineTypeMembers(typeNode *sitter.Node, content []byte) (SymbolKind, []Symbol) {
+	if !p.descend() {
+		return "", nil
+	}
+	defer p.ascend()
+
+	if typeNode == nil {
+		return "", nil
+	}
+	switch typeNode.Type() {
+	case "struct_type":
+		return KindClass, p.extractStructFields(typeNode, content)
+	case "interface_type":
+		return KindInterface, p.extractInterfaceMethods(typeNode, content)
+	case "pointer_type", "slice_type", "array_type", "map_type", "channel_type", "parenthesized_type":
+		for i := int(typeNode.NamedChildCount()) - 1; i >= 0; i-- {
+			if kind, members := p.inlineTypeMembers(typeNode.NamedChild(i), content); len(members) > 0 {
+				return kind, members
+			}
+		}
+	}
+	return "", nil
+}
+
 func (p *GoParser) parseVarDecl(node *sitter.Node, content []byte, analysis *FileAnalysis, isConst bool) {
 	for i := 0; i < int(node.ChildCount()); i++ {
 		spec := node.Child(i)
@@ -257,17 +401,31 @@ func (p *GoParser) parseVarDecl(node *sitter.Node, content []byte, analysis *Fil
 			kind = KindConstant
 		}
 
+		// Only "const Name = literal"; "A, B = 1, 2" lists are left out.
+		var value string
+		if valueNode := spec.ChildByFieldName("value"); valueNode != nil && valueNode.NamedChildCount() == 1 {
+			value = literalValue(valueNode.Content(content))
+		}
+		_, members := p.inlineTypeMembers(spec.ChildByFieldName("type"), content)
+
 		analysis.Symbols = append(analysis.Symbols, Symbol{
 			Name:      name,
 			Kind:      kind,
 			LineStart: int(spec.StartPoint().Row) + 1,
 			LineEnd:   int(spec.EndPoint().Row) + 1,
 			Exported:  isExported(name),
+			Value:     value,
+			Children:  members,
 		})
 	}
 }
 
 func (p *GoParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -280,6 +438,12 @@ func (p *GoParser) extractRelationships(node *sitter.Node, content []byte, analy
 
 		case "call_expression":
 			p.parseCallExpression(child, content, analysis)
+
+		case "type_alias":
+			if nameNode := child.ChildByFieldName("name"); nameNode != nil {
+				analysis.Relationships = append(analysis.Relationships,
+					typeAliasReferences(nameNode.Content(content), child.ChildByFieldName("type"), content)...)
+			}
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -324,6 +488,11 @@ func (p *GoParser) parseCallExpression(node *sitter.Node, content []byte, analys
 }
 
 func (p *GoParser) parseImports(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
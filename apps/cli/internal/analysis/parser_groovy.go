@@ -9,6 +9,7 @@ import (
 )
 
 type GroovyParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *GroovyParser) Parse(content []byte, filePath string) (*FileAnalysis, er
 }
 
 func (p *GroovyParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -319,6 +325,11 @@ func (p *GroovyParser) extractClassMembers(node *sitter.Node, content []byte) []
 }
 
 func (p *GroovyParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
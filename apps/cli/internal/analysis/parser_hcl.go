@@ -9,6 +9,7 @@ import (
 )
 
 type HCLParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *HCLParser) Parse(content []byte, filePath string) (*FileAnalysis, error
 }
 
 func (p *HCLParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -202,6 +208,11 @@ func (p *HCLParser) extractBlockAttributes(node *sitter.Node, content []byte) []
 }
 
 func (p *HCLParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -9,6 +9,7 @@ import (
 )
 
 type HTMLParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *HTMLParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 }
 
 func (p *HTMLParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -156,6 +162,11 @@ func (p *HTMLParser) parseAttribute(node *sitter.Node, content []byte) (string,
 }
 
 func (p *HTMLParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -9,6 +9,7 @@ import (
 )
 
 type JavaParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *JavaParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 }
 
 func (p *JavaParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -49,23 +55,13 @@ func (p *JavaParser) extractSymbols(node *sitter.Node, content []byte, analysis
 		}
 
 		switch child.Type() {
-		case "class_declaration":
-			sym := p.parseClassDecl(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
-
-		case "interface_declaration":
-			sym := p.parseInterfaceDecl(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
-
-		case "enum_declaration":
-			sym := p.parseEnumDecl(child, content)
+		case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+			// A type holds its members, nested types included, as children
+			sym := p.parseTypeDecl(child, content)
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
+			continue
 
 		case "method_declaration":
 			sym := p.parseMethodDecl(child, content)
@@ -77,12 +73,29 @@ func (p *JavaParser) extractSymbols(node *sitter.Node, content []byte, analysis
 			p.parseFieldDecl(child, content, analysis)
 		}
 
-		if child.Type() != "class_declaration" && child.Type() != "interface_declaration" {
-			p.extractSymbols(child, content, analysis)
-		}
+		p.extractSymbols(child, content, analysis)
 	}
 }
 
+// parseTypeDecl parses a class, interface, enum, or record declaration.
+func (p *JavaParser) parseTypeDecl(node *sitter.Node, content []byte) *Symbol {
+	var sym *Symbol
+	switch node.Type() {
+	case "class_declaration":
+		sym = p.parseClassDecl(node, content)
+	case "interface_declaration":
+		sym = p.parseInterfaceDecl(node, content)
+	case "enum_declaration":
+		sym = p.parseEnumDecl(node, content)
+	case "record_declaration":
+		sym = p.parseRecordDecl(node, content)
+	}
+	if sym != nil {
+		sym.Annotations = p.annotations(node, content)
+	}
+	return sym
+}
+
 func (p *JavaParser) parseClassDecl(node *sitter.Node, content []byte) *Symbol {
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
@@ -100,6 +113,7 @@ func (p *JavaParser) parseClassDecl(node *sitter.Node, content []byte) *Symbol {
 		LineEnd:    int(node.EndPoint().Row) + 1,
 		DocComment: doc,
 		Exported:   exported,
+		TypeParams: extractTypeParams(node, content),
 	}
 
 	bodyNode := node.ChildByFieldName("body")
@@ -125,6 +139,7 @@ func (p *JavaParser) parseInterfaceDecl(node *sitter.Node, content []byte) *Symb
 		LineEnd:    int(node.EndPoint().Row) + 1,
 		DocComment: p.extractJavadoc(node, content),
 		Exported:   p.isPublic(node, content),
+		TypeParams: extractTypeParams(node, content),
 	}
 
 	bodyNode := node.ChildByFieldName("body")
@@ -141,7 +156,7 @@ func (p *JavaParser) parseEnumDecl(node *sitter.Node, content []byte) *Symbol {
 		return nil
 	}
 
-	return &Symbol{
+	sym := &Symbol{
 		Name:       nameNode.Content(content),
 		Kind:       KindEnum,
 		LineStart:  int(node.StartPoint().Row) + 1,
@@ -149,6 +164,70 @@ func (p *JavaParser) parseEnumDecl(node *sitter.Node, content []byte) *Symbol {
 		DocComment: p.extractJavadoc(node, content),
 		Exported:   p.isPublic(node, content),
 	}
+
+	bodyNode := node.ChildByFieldName("body")
+	if bodyNode == nil {
+		return sym
+	}
+	for i := 0; i < int(bodyNode.NamedChildCount()); i++ {
+		child := bodyNode.NamedChild(i)
+		switch child.Type() {
+		case "enum_constant":
+			if constName := child.ChildByFieldName("name"); constName != nil {
+				sym.Children = append(sym.Children, Symbol{
+					Name:       constName.Content(content),
+					Kind:       KindConstant,
+					LineStart:  int(child.StartPoint().Row) + 1,
+					LineEnd:    int(child.EndPoint().Row) + 1,
+					DocComment: p.extractJavadoc(child, content),
+					Exported:   sym.Exported,
+				})
+			}
+		case "enum_body_declarations":
+			sym.Children = append(sym.Children, p.parseClassBody(child, content)...)
+		}
+	}
+	return sym
+}
+
+// parseRecordDecl parses "record Point(int x, int y) { ... }": a class
+// whose components are properties, followed by the members of its body.
+func (p *JavaParser) parseRecordDecl(node *sitter.Node, content []byte) *Symbol {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+
+	name := nameNode.Content(content)
+	sym := &Symbol{
+		Name:       name,
+		Kind:       KindClass,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		DocComment: p.extractJavadoc(node, content),
+		Exported:   p.isPublic(node, content),
+		TypeParams: extractTypeParams(node, content),
+	}
+
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		sym.Signature = "record " + name + params.Content(content)
+		for i := 0; i < int(params.NamedChildCount()); i++ {
+			param := params.NamedChild(i)
+			if compName := param.ChildByFieldName("name"); compName != nil {
+				sym.Children = append(sym.Children, Symbol{
+					Name:      compName.Content(content),
+					Kind:      KindProperty,
+					LineStart: int(param.StartPoint().Row) + 1,
+					LineEnd:   int(param.EndPoint().Row) + 1,
+					Exported:  sym.Exported,
+				})
+			}
+		}
+	}
+	if bodyNode := node.ChildByFieldName("body"); bodyNode != nil {
+		sym.Children = append(sym.Children, p.parseClassBody(bodyNode, content)...)
+	}
+	return sym
 }
 
 func (p *JavaParser) parseMethodDecl(node *sitter.Node, content []byte) *Symbol {
@@ -172,13 +251,15 @@ func (p *JavaParser) parseMethodDecl(node *sitter.Node, content []byte) *Symbol
 	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindMethod,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		Signature:  returnType + " " + name + params,
-		DocComment: p.extractJavadoc(node, content),
-		Exported:   p.isPublic(node, content),
+		Name:        name,
+		Kind:        KindMethod,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   returnType + " " + name + params,
+		DocComment:  p.extractJavadoc(node, content),
+		Exported:    p.isPublic(node, content),
+		TypeParams:  extractTypeParams(node, content),
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -201,17 +282,34 @@ func (p *JavaParser) parseFieldDecl(node *sitter.Node, content []byte, analysis
 			}
 
 			analysis.Symbols = append(analysis.Symbols, Symbol{
-				Name:      nameNode.Content(content),
-				Kind:      kind,
-				LineStart: int(node.StartPoint().Row) + 1,
-				LineEnd:   int(node.EndPoint().Row) + 1,
-				Exported:  p.isPublic(node, content),
+				Name:        nameNode.Content(content),
+				Kind:        kind,
+				LineStart:   int(node.StartPoint().Row) + 1,
+				LineEnd:     int(node.EndPoint().Row) + 1,
+				DocComment:  p.extractJavadoc(node, content),
+				Exported:    p.isPublic(node, content),
+				Annotations: p.annotations(node, content),
+				Value:       p.declaratorValue(child, content),
 			})
 		}
 	}
 }
 
+// declaratorValue returns the literal a variable declarator is initialized
+// with, such as "a" for NAME = "a", or "" for any other initializer.
+func (p *JavaParser) declaratorValue(decl *sitter.Node, content []byte) string {
+	if value := decl.ChildByFieldName("value"); value != nil {
+		return literalValue(value.Content(content))
+	}
+	return ""
+}
+
 func (p *JavaParser) parseClassBody(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var children []Symbol
 
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -227,15 +325,27 @@ func (p *JavaParser) parseClassBody(node *sitter.Node, content []byte) []Symbol
 				children = append(children, *sym)
 			}
 
+		case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+			if sym := p.parseTypeDecl(child, content); sym != nil {
+				children = append(children, *sym)
+			}
+
 		case "constructor_declaration":
 			nameNode := child.ChildByFieldName("name")
 			if nameNode != nil {
+				sig := nameNode.Content(content)
+				if paramsNode := child.ChildByFieldName("parameters"); paramsNode != nil {
+					sig += paramsNode.Content(content)
+				}
 				children = append(children, Symbol{
-					Name:      nameNode.Content(content),
-					Kind:      KindConstructor,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  p.isPublic(child, content),
+					Name:        nameNode.Content(content),
+					Kind:        KindConstructor,
+					LineStart:   int(child.StartPoint().Row) + 1,
+					LineEnd:     int(child.EndPoint().Row) + 1,
+					Signature:   sig,
+					DocComment:  p.extractJavadoc(child, content),
+					Exported:    p.isPublic(child, content),
+					Annotations: p.annotations(child, content),
 				})
 			}
 
@@ -250,11 +360,14 @@ func (p *JavaParser) parseClassBody(node *sitter.Node, content []byte) []Symbol
 							kind = KindConstant
 						}
 						children = append(children, Symbol{
-							Name:      nameNode.Content(content),
-							Kind:      kind,
-							LineStart: int(child.StartPoint().Row) + 1,
-							LineEnd:   int(child.EndPoint().Row) + 1,
-							Exported:  p.isPublic(child, content),
+							Name:        nameNode.Content(content),
+							Kind:        kind,
+							LineStart:   int(child.StartPoint().Row) + 1,
+							LineEnd:     int(child.EndPoint().Row) + 1,
+							DocComment:  p.extractJavadoc(child, content),
+							Exported:    p.isPublic(child, content),
+							Annotations: p.annotations(child, content),
+							Value:       p.declaratorValue(decl, content),
 						})
 					}
 				}
@@ -266,6 +379,11 @@ func (p *JavaParser) parseClassBody(node *sitter.Node, content []byte) []Symbol
 }
 
 func (p *JavaParser) parseInterfaceBody(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var children []Symbol
 
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -274,11 +392,17 @@ func (p *JavaParser) parseInterfaceBody(node *sitter.Node, content []byte) []Sym
 			continue
 		}
 
-		if child.Type() == "method_declaration" {
+		switch child.Type() {
+		case "method_declaration":
 			sym := p.parseMethodDecl(child, content)
 			if sym != nil {
 				children = append(children, *sym)
 			}
+
+		case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+			if sym := p.parseTypeDecl(child, content); sym != nil {
+				children = append(children, *sym)
+			}
 		}
 	}
 
@@ -347,20 +471,100 @@ func (p *JavaParser) extractJavadoc(node *sitter.Node, content []byte) string {
 }
 
 func (p *JavaParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
 			continue
 		}
 
-		if child.Type() == "import_declaration" {
+		switch child.Type() {
+		case "import_declaration":
 			p.parseImport(child, content, analysis)
+		case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration":
+			p.parseSupertypes(child, content, analysis)
+		case "method_invocation":
+			p.parseInvocation(child, content, analysis)
 		}
 
 		p.extractRelationships(child, content, analysis)
 	}
 }
 
+// parseInvocation records a method call: "helper(x)" calls helper and
+// "user.getName()" calls user.getName.
+func (p *JavaParser) parseInvocation(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+	target := nameNode.Content(content)
+	if object := node.ChildByFieldName("object"); object != nil {
+		target = object.Content(content) + "." + target
+	}
+	analysis.Relationships = append(analysis.Relationships, Relationship{
+		TargetSymbol: target,
+		Kind:         RelCall,
+		Line:         int(nameNode.StartPoint().Row) + 1,
+		Column:       int(nameNode.StartPoint().Column),
+	})
+}
+
+// parseSupertypes records what a type declaration extends and implements:
+// "class A extends B implements C, D" gives an extends relationship to B
+// and implements relationships to C and D. An interface's "extends" list is
+// recorded as extends.
+func (p *JavaParser) parseSupertypes(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+	name := nameNode.Content(content)
+	add := func(list *sitter.Node, kind RelationshipKind) {
+		var walk func(n *sitter.Node)
+		walk = func(n *sitter.Node) {
+			switch n.Type() {
+			case "type_identifier", "scoped_type_identifier":
+				analysis.Relationships = append(analysis.Relationships, Relationship{
+					SourceSymbol: name,
+					TargetSymbol: n.Content(content),
+					Kind:         kind,
+					Line:         int(n.StartPoint().Row) + 1,
+				})
+				return
+			case "generic_type":
+				// Only the generic type itself, not its type arguments
+				if n.NamedChildCount() > 0 {
+					walk(n.NamedChild(0))
+				}
+				return
+			}
+			for i := 0; i < int(n.NamedChildCount()); i++ {
+				if c := n.NamedChild(i); c != nil {
+					walk(c)
+				}
+			}
+		}
+		walk(list)
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child == nil {
+			continue
+		}
+		switch child.Type() {
+		case "superclass", "extends_interfaces":
+			add(child, RelExtends)
+		case "super_interfaces":
+			add(child, RelImplements)
+		}
+	}
+}
+
 func (p *JavaParser) parseImport(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -378,3 +582,13 @@ func (p *JavaParser) parseImport(node *sitter.Node, content []byte, analysis *Fi
 		}
 	}
 }
+
+// annotations returns the annotations on a Java declaration (e.g. "@Service").
+func (p *JavaParser) annotations(node *sitter.Node, content []byte) []string {
+	switch node.Type() {
+	case "class_declaration", "interface_declaration", "enum_declaration", "record_declaration",
+		"annotation_type_declaration", "method_declaration", "constructor_declaration", "field_declaration":
+		return childAnnotations(node, content, "modifiers", "marker_annotation", "annotation")
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type JavaScriptParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *JavaScriptParser) Parse(content []byte, filePath string) (*FileAnalysis
 }
 
 func (p *JavaScriptParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -191,12 +197,15 @@ func (p *JavaScriptParser) parseVariableDecl(node *sitter.Node, content []byte,
 			kind = KindConstant
 		}
 
+		var value string
 		valueNode := child.ChildByFieldName("value")
 		if valueNode != nil {
 			if valueNode.Type() == "arrow_function" || valueNode.Type() == "function" {
 				kind = KindFunction
 			} else if valueNode.Type() == "class" {
 				kind = KindClass
+			} else {
+				value = literalValue(valueNode.Content(content))
 			}
 		}
 
@@ -205,6 +214,7 @@ func (p *JavaScriptParser) parseVariableDecl(node *sitter.Node, content []byte,
 			Kind:      kind,
 			LineStart: int(child.StartPoint().Row) + 1,
 			LineEnd:   int(child.EndPoint().Row) + 1,
+			Value:     value,
 		})
 	}
 }
@@ -242,6 +252,11 @@ func (p *JavaScriptParser) parseExportStatement(node *sitter.Node, content []byt
 }
 
 func (p *JavaScriptParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
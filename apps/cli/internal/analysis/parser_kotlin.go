@@ -9,6 +9,7 @@ import (
 )
 
 type KotlinParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -48,6 +49,8 @@ func (p *KotlinParser) extractSymbols(node *sitter.Node, content []byte, analysi
 			continue
 		}
 
+		// Declarations hold their members as children, so only the top
+		// level is walked; locals inside function bodies are not symbols.
 		switch child.Type() {
 		case "class_declaration":
 			sym := p.parseClass(child, content)
@@ -73,11 +76,12 @@ func (p *KotlinParser) extractSymbols(node *sitter.Node, content []byte, analysi
 		case "type_alias":
 			p.parseTypeAlias(child, content, analysis)
 		}
-
-		p.extractSymbols(child, content, analysis)
 	}
 }
 
+// parseClass parses a class, data class, enum class, or interface with its
+// members. The signature is the declaration header, such as
+// "data class User(val id: Int) : Base()".
 func (p *KotlinParser) parseClass(node *sitter.Node, content []byte) *Symbol {
 	var name string
 	var nameNode *sitter.Node
@@ -99,19 +103,17 @@ func (p *KotlinParser) parseClass(node *sitter.Node, content []byte) *Symbol {
 	exported := p.isPublic(node, content)
 	var children []Symbol
 
-	body := node.ChildByFieldName("body")
-	if body == nil {
-		for i := 0; i < int(node.ChildCount()); i++ {
-			child := node.Child(i)
-			if child != nil && child.Type() == "class_body" {
-				body = child
-				break
-			}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child == nil {
+			continue
+		}
+		switch child.Type() {
+		case "primary_constructor":
+			children = append(children, p.parseConstructorProperties(child, content)...)
+		case "class_body", "enum_class_body":
+			children = append(children, p.extractClassMembers(child, content)...)
 		}
-	}
-
-	if body != nil {
-		children = p.extractClassMembers(body, content)
 	}
 
 	kind := KindClass
@@ -124,16 +126,21 @@ func (p *KotlinParser) parseClass(node *sitter.Node, content []byte) *Symbol {
 	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       kind,
-		LineStart:  int(nameNode.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
-		Children:   children,
+		Name:        name,
+		Kind:        kind,
+		LineStart:   int(nameNode.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   declarationHeader(node, content, "annotation", "class_body", "enum_class_body"),
+		DocComment:  doc,
+		Exported:    exported,
+		Children:    children,
+		TypeParams:  extractTypeParams(node, content),
+		Annotations: p.annotations(node, content),
 	}
 }
 
+// parseObject parses an object declaration, or a companion object, which
+// is named Companion unless it is given a name.
 func (p *KotlinParser) parseObject(node *sitter.Node, content []byte) *Symbol {
 	var name string
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -143,6 +150,9 @@ func (p *KotlinParser) parseObject(node *sitter.Node, content []byte) *Symbol {
 			break
 		}
 	}
+	if name == "" && node.Type() == "companion_object" {
+		name = "Companion"
+	}
 
 	if name == "" {
 		return nil
@@ -150,17 +160,29 @@ func (p *KotlinParser) parseObject(node *sitter.Node, content []byte) *Symbol {
 
 	doc := p.extractKDoc(node, content)
 	exported := p.isPublic(node, content)
+	var children []Symbol
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child != nil && child.Type() == "class_body" {
+			children = p.extractClassMembers(child, content)
+		}
+	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindClass,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
+		Name:        name,
+		Kind:        KindClass,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   declarationHeader(node, content, "annotation", "class_body"),
+		DocComment:  doc,
+		Exported:    exported,
+		Children:    children,
+		Annotations: p.annotations(node, content),
 	}
 }
 
+// parseFunction parses a function. The signature is its header, such as
+// "fun load(id: Int): User?"; for an extension function it includes the
+// receiver type, as in "fun String.shout(times: Int): String".
 func (p *KotlinParser) parseFunction(node *sitter.Node, content []byte) *Symbol {
 	var name string
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -176,36 +198,24 @@ func (p *KotlinParser) parseFunction(node *sitter.Node, content []byte) *Symbol
 	}
 
 	doc := p.extractKDoc(node, content)
-	sig := p.extractFunctionSignature(node, content)
+	sig := declarationHeader(node, content, "annotation", "function_body")
 	exported := p.isPublic(node, content)
 
 	return &Symbol{
-		Name:       name,
-		Kind:       KindFunction,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		Signature:  sig,
-		DocComment: doc,
-		Exported:   exported,
+		Name:        name,
+		Kind:        KindFunction,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   sig,
+		DocComment:  doc,
+		Exported:    exported,
+		TypeParams:  extractTypeParams(node, content),
+		Annotations: p.annotations(node, content),
 	}
 }
 
 func (p *KotlinParser) parseProperty(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	var name string
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && child.Type() == "variable_declaration" {
-			for j := 0; j < int(child.ChildCount()); j++ {
-				id := child.Child(j)
-				if id != nil && id.Type() == "simple_identifier" {
-					name = id.Content(content)
-					break
-				}
-			}
-			break
-		}
-	}
-
+	name := p.propertyName(node, content)
 	if name == "" {
 		return
 	}
@@ -220,14 +230,56 @@ func (p *KotlinParser) parseProperty(node *sitter.Node, content []byte, analysis
 	}
 
 	analysis.Symbols = append(analysis.Symbols, Symbol{
-		Name:      name,
-		Kind:      kind,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  p.isPublic(node, content),
+		Name:        name,
+		Kind:        kind,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   declarationHeader(node, content, "annotation", "=", "property_delegate", "getter", "setter"),
+		DocComment:  p.extractKDoc(node, content),
+		Exported:    p.isPublic(node, content),
+		Annotations: p.annotations(node, content),
+		Value:       p.propertyValue(node, content),
 	})
 }
 
+// propertyName returns the name a property declaration declares.
+func (p *KotlinParser) propertyName(node *sitter.Node, content []byte) string {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child != nil && child.Type() == "variable_declaration" {
+			for j := 0; j < int(child.ChildCount()); j++ {
+				id := child.Child(j)
+				if id != nil && id.Type() == "simple_identifier" {
+					return id.Content(content)
+				}
+			}
+			break
+		}
+	}
+	return ""
+}
+
+// propertyValue returns the literal a property is initialized with, such as
+// "42" for "val limit = 42"; string templates and other expressions yield "".
+func (p *KotlinParser) propertyValue(node *sitter.Node, content []byte) string {
+	for i := 0; i+1 < int(node.ChildCount()); i++ {
+		if eq := node.Child(i); eq == nil || eq.Type() != "=" {
+			continue
+		}
+		value := node.Child(i + 1)
+		if value == nil {
+			return ""
+		}
+		for j := 0; j < int(value.NamedChildCount()); j++ {
+			if part := value.NamedChild(j); part != nil && strings.HasPrefix(part.Type(), "interpolated_") {
+				return ""
+			}
+		}
+		return literalValue(value.Content(content))
+	}
+	return ""
+}
+
 func (p *KotlinParser) parseTypeAlias(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	var name string
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -251,7 +303,48 @@ func (p *KotlinParser) parseTypeAlias(node *sitter.Node, content []byte, analysi
 	})
 }
 
+// parseConstructorProperties returns the properties a primary constructor
+// declares: its val and var parameters.
+func (p *KotlinParser) parseConstructorProperties(node *sitter.Node, content []byte) []Symbol {
+	var props []Symbol
+	for i := 0; i < int(node.ChildCount()); i++ {
+		param := node.Child(i)
+		if param == nil || param.Type() != "class_parameter" {
+			continue
+		}
+		var name string
+		binding := false
+		for j := 0; j < int(param.ChildCount()); j++ {
+			switch c := param.Child(j); c.Type() {
+			case "binding_pattern_kind":
+				binding = true
+			case "simple_identifier":
+				if name == "" {
+					name = c.Content(content)
+				}
+			}
+		}
+		if !binding || name == "" {
+			continue
+		}
+		props = append(props, Symbol{
+			Name:      name,
+			Kind:      KindProperty,
+			LineStart: int(param.StartPoint().Row) + 1,
+			LineEnd:   int(param.EndPoint().Row) + 1,
+			Signature: strings.Join(strings.Fields(param.Content(content)), " "),
+			Exported:  p.isPublic(param, content),
+		})
+	}
+	return props
+}
+
 func (p *KotlinParser) extractClassMembers(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var members []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -268,36 +361,55 @@ func (p *KotlinParser) extractClassMembers(node *sitter.Node, content []byte) []
 			}
 
 		case "property_declaration":
-			var name string
+			if name := p.propertyName(child, content); name != "" {
+				members = append(members, Symbol{
+					Name:        name,
+					Kind:        KindProperty,
+					LineStart:   int(child.StartPoint().Row) + 1,
+					LineEnd:     int(child.EndPoint().Row) + 1,
+					Signature:   declarationHeader(child, content, "annotation", "=", "property_delegate", "getter", "setter"),
+					DocComment:  p.extractKDoc(child, content),
+					Exported:    p.isPublic(child, content),
+					Annotations: p.annotations(child, content),
+					Value:       p.propertyValue(child, content),
+				})
+			}
+
+		case "class_declaration":
+			if sym := p.parseClass(child, content); sym != nil {
+				members = append(members, *sym)
+			}
+
+		case "object_declaration", "companion_object":
+			if sym := p.parseObject(child, content); sym != nil {
+				members = append(members, *sym)
+			}
+
+		case "enum_entry":
 			for j := 0; j < int(child.ChildCount()); j++ {
-				varDecl := child.Child(j)
-				if varDecl != nil && varDecl.Type() == "variable_declaration" {
-					for k := 0; k < int(varDecl.ChildCount()); k++ {
-						id := varDecl.Child(k)
-						if id != nil && id.Type() == "simple_identifier" {
-							name = id.Content(content)
-							break
-						}
-					}
+				if id := child.Child(j); id != nil && id.Type() == "simple_identifier" {
+					members = append(members, Symbol{
+						Name:       id.Content(content),
+						Kind:       KindConstant,
+						LineStart:  int(child.StartPoint().Row) + 1,
+						LineEnd:    int(child.EndPoint().Row) + 1,
+						DocComment: p.extractKDoc(child, content),
+						Exported:   true,
+					})
 					break
 				}
 			}
-
-			if name != "" {
-				members = append(members, Symbol{
-					Name:      name,
-					Kind:      KindProperty,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  p.isPublic(child, content),
-				})
-			}
 		}
 	}
 	return members
 }
 
 func (p *KotlinParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -308,7 +420,7 @@ func (p *KotlinParser) extractRelationships(node *sitter.Node, content []byte, a
 		case "import_header":
 			p.parseImport(child, content, analysis)
 
-		case "class_declaration":
+		case "class_declaration", "object_declaration":
 			p.parseInheritance(child, content, analysis)
 		}
 
@@ -330,38 +442,59 @@ func (p *KotlinParser) parseImport(node *sitter.Node, content []byte, analysis *
 	}
 }
 
+// parseInheritance records the supertypes of a class or object. In
+// "class A : Base(), Listener" the constructor call marks Base as the
+// superclass, recorded as extends, and Listener as an implemented
+// interface. An interface's supertypes are all recorded as extends.
 func (p *KotlinParser) parseInheritance(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	var name string
+	isInterface := false
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
 			continue
 		}
-
-		if child.Type() == "delegation_specifiers" {
-			for j := 0; j < int(child.ChildCount()); j++ {
-				spec := child.Child(j)
-				if spec != nil {
-					var typeName string
-					for k := 0; k < int(spec.ChildCount()); k++ {
-						typeNode := spec.Child(k)
-						if typeNode != nil && (typeNode.Type() == "user_type" || typeNode.Type() == "type_identifier") {
-							typeName = typeNode.Content(content)
-							break
-						}
-					}
-
-					if typeName != "" {
-						kind := RelExtends
-						analysis.Relationships = append(analysis.Relationships, Relationship{
-							TargetSymbol: typeName,
-							Kind:         kind,
-							Line:         int(spec.StartPoint().Row) + 1,
-						})
-					}
-				}
+		switch child.Type() {
+		case "interface":
+			isInterface = true
+		case "type_identifier":
+			if name == "" {
+				name = child.Content(content)
 			}
 		}
 	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		spec := node.Child(i)
+		if spec == nil || spec.Type() != "delegation_specifier" {
+			continue
+		}
+		typeNode := spec.NamedChild(0)
+		if typeNode == nil {
+			continue
+		}
+		kind := RelImplements
+		if isInterface {
+			kind = RelExtends
+		}
+		if typeNode.Type() == "constructor_invocation" {
+			kind = RelExtends
+			typeNode = typeNode.NamedChild(0)
+		} else if typeNode.Type() == "explicit_delegation" {
+			typeNode = typeNode.NamedChild(0)
+		}
+		if typeNode == nil || typeNode.Type() != "user_type" {
+			continue
+		}
+		// Only the type itself, not its type arguments
+		typeName, _, _ := strings.Cut(typeNode.Content(content), "<")
+		analysis.Relationships = append(analysis.Relationships, Relationship{
+			SourceSymbol: name,
+			TargetSymbol: strings.TrimSpace(typeName),
+			Kind:         kind,
+			Line:         int(spec.StartPoint().Row) + 1,
+		})
+	}
 }
 
 func (p *KotlinParser) isPublic(node *sitter.Node, content []byte) bool {
@@ -381,51 +514,45 @@ func (p *KotlinParser) isPublic(node *sitter.Node, content []byte) bool {
 	return true
 }
 
-func (p *KotlinParser) extractFunctionSignature(node *sitter.Node, content []byte) string {
-	var sig strings.Builder
-	sig.WriteString("fun ")
-
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child == nil {
-			continue
-		}
-
-		switch child.Type() {
-		case "simple_identifier":
-			sig.WriteString(child.Content(content))
-		case "function_value_parameters":
-			sig.WriteString(child.Content(content))
-		case "type":
-			sig.WriteString(": ")
-			sig.WriteString(child.Content(content))
-		}
-	}
-
-	return sig.String()
-}
-
+// extractKDoc returns the KDoc comment before a declaration, up to its
+// first block tag such as @param. The grammar can attach a comment that
+// follows the imports to the last import, so a comment ending the previous
+// node counts too.
 func (p *KotlinParser) extractKDoc(node *sitter.Node, content []byte) string {
 	prev := node.PrevSibling()
-	if prev == nil {
+	for prev != nil && prev.Type() != "multiline_comment" && prev.ChildCount() > 0 {
+		prev = prev.Child(int(prev.ChildCount()) - 1)
+	}
+	if prev == nil || prev.Type() != "multiline_comment" {
 		return ""
 	}
 
-	if prev.Type() == "multiline_comment" {
-		comment := prev.Content(content)
-		if strings.HasPrefix(comment, "/**") {
-			comment = strings.TrimPrefix(comment, "/**")
-			comment = strings.TrimSuffix(comment, "*/")
-			lines := strings.Split(comment, "\n")
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				line = strings.TrimPrefix(line, "* ")
-				if line != "" && !strings.HasPrefix(line, "@") {
-					return line
-				}
-			}
+	comment := prev.Content(content)
+	if !strings.HasPrefix(comment, "/**") {
+		return ""
+	}
+	comment = strings.TrimPrefix(comment, "/**")
+	comment = strings.TrimSuffix(comment, "*/")
+	var cleaned []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			break
+		}
+		if line != "" {
+			cleaned = append(cleaned, line)
 		}
 	}
+	return strings.Join(cleaned, " ")
+}
 
-	return ""
+// annotations returns the annotations on a Kotlin declaration (e.g. "@Component").
+func (p *KotlinParser) annotations(node *sitter.Node, content []byte) []string {
+	switch node.Type() {
+	case "class_declaration", "object_declaration", "companion_object", "function_declaration", "property_declaration":
+		return childAnnotations(node, content, "modifiers", "annotation")
+	}
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 )
 
 type LuaParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *LuaParser) Parse(content []byte, filePath string) (*FileAnalysis, error
 }
 
 func (p *LuaParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -223,6 +229,11 @@ func (p *LuaParser) parseAssignment(node *sitter.Node, content []byte, analysis
 }
 
 func (p *LuaParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -9,6 +9,7 @@ import (
 )
 
 type MarkdownParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *MarkdownParser) Parse(content []byte, filePath string) (*FileAnalysis,
 }
 
 func (p *MarkdownParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -231,6 +237,11 @@ func (p *MarkdownParser) parseLinkDef(node *sitter.Node, content []byte) *Symbol
 }
 
 func (p *MarkdownParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -0,0 +1,419 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// NimParser extracts symbols from Nim with regular expressions and
+// indentation, as there is no tree-sitter grammar for it. When strict, it
+// leaves out forward declarations, routines declared without a body.
+type NimParser struct {
+	strictness Strictness
+}
+
+func NewNimParser() *NimParser {
+	return &NimParser{}
+}
+
+func (p *NimParser) Language() Language {
+	return LangNim
+}
+
+// SetStrictness implements StrictnessParser.
+func (p *NimParser) SetStrictness(s Strictness) {
+	p.strictness = s
+}
+
+func (p *NimParser) Parse(content []byte, filePath string) (*FileAnalysis, error) {
+	analysis := &FileAnalysis{
+		Path:     filePath,
+		Language: string(LangNim),
+	}
+
+	lines := strings.Split(string(content), "\n")
+	p.extractSymbols(lines, analysis)
+	p.extractRelationships(lines, analysis)
+
+	return analysis, nil
+}
+
+var (
+	nimRoutineRe   = regexp.MustCompile("^(proc|func|method|iterator|converter|template|macro)\\s+(`[^`]+`|\\w+)(\\*)?")
+	nimTypeRe      = regexp.MustCompile("^(`[^`]+`|\\w+)(\\*)?\\s*(?:\\[[^\\]]*\\])?\\s*(?:\\{\\.[^}]*\\.\\})?\\s*=\\s*(.*)$")
+	nimFieldRe     = regexp.MustCompile("^(`[^`]+`|\\w+)(\\*)?\\s*(?:\\{\\.[^}]*\\.\\})?\\s*[:,]")
+	nimEnumValueRe = regexp.MustCompile("^(\\w+)\\s*(?:=|,|$)")
+	nimCallRe      = regexp.MustCompile(`(?:\b(\w+)\.)?\b(\w+)\s*\(`)
+	nimImportRe    = regexp.MustCompile(`^(import|include|from)\s+(.+)$`)
+)
+
+// nimKeywords are words that may precede a parenthesis without being a call.
+var nimKeywords = toSet(
+	"if", "elif", "while", "for", "case", "of", "return", "and", "or", "not", "in", "notin",
+	"is", "isnot", "proc", "func", "method", "template", "macro", "iterator", "when", "cast",
+	"addr", "discard", "yield", "raise", "echo", "defined", "sizeof", "typeof", "type",
+)
+
+func (p *NimParser) extractSymbols(lines []string, analysis *FileAnalysis) {
+	var pendingDoc []string
+	inTypeSection := false
+	typeIndent := 0
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := nimIndent(line)
+
+		if strings.HasPrefix(trimmed, "##") {
+			pendingDoc = append(pendingDoc, strings.TrimSpace(strings.TrimPrefix(trimmed, "##")))
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if inTypeSection && indent <= typeIndent {
+			inTypeSection = false
+		}
+
+		if m := nimRoutineRe.FindStringSubmatch(trimmed); m != nil && indent == 0 {
+			end := nimBlockEnd(lines, i)
+			if p.strictness == StrictnessStrict && end == i && !strings.Contains(trimmed, "=") {
+				pendingDoc = nil
+				continue
+			}
+			if _, after, ok := strings.Cut(trimmed, "##"); ok {
+				pendingDoc = append(pendingDoc, strings.TrimSpace(after))
+			}
+			sym := Symbol{
+				Name:       strings.Trim(m[2], "`"),
+				Kind:       KindFunction,
+				LineStart:  i + 1,
+				LineEnd:    end + 1,
+				Signature:  nimSignature(lines, i),
+				DocComment: nimDoc(pendingDoc, lines, i, end),
+				Exported:   m[3] == "*",
+			}
+			if m[1] == "method" {
+				sym.Kind = KindMethod
+			}
+			analysis.Symbols = append(analysis.Symbols, sym)
+			pendingDoc = nil
+			i = end
+			continue
+		}
+
+		if trimmed == "type" && indent == 0 {
+			inTypeSection, typeIndent = true, indent
+			pendingDoc = nil
+			continue
+		}
+		decl := trimmed
+		if rest, ok := strings.CutPrefix(trimmed, "type "); ok && indent == 0 {
+			decl = strings.TrimSpace(rest)
+		} else if !inTypeSection {
+			pendingDoc = nil
+			continue
+		}
+
+		// A doc comment may trail the definition: "Person* = object ## A person"
+		decl, trailingDoc, _ := strings.Cut(decl, "##")
+		if trailingDoc = strings.TrimSpace(trailingDoc); trailingDoc != "" {
+			pendingDoc = append(pendingDoc, trailingDoc)
+		}
+		if m := nimTypeRe.FindStringSubmatch(strings.TrimSpace(decl)); m != nil {
+			end := nimBlockEnd(lines, i)
+			sym := p.parseTypeDecl(lines, i, end, m)
+			sym.DocComment = nimDoc(pendingDoc, lines, i, end)
+			analysis.Symbols = append(analysis.Symbols, sym)
+			i = end
+		}
+		pendingDoc = nil
+	}
+}
+
+// parseTypeDecl builds the symbol for a type definition spanning lines
+// start to end, with its object fields or enum values as children.
+func (p *NimParser) parseTypeDecl(lines []string, start, end int, m []string) Symbol {
+	sym := Symbol{
+		Name:      strings.Trim(m[1], "`"),
+		Kind:      KindType,
+		LineStart: start + 1,
+		LineEnd:   end + 1,
+		Exported:  m[2] == "*",
+	}
+	body := strings.TrimSpace(m[3])
+	sym.Signature = body
+
+	switch {
+	case strings.HasPrefix(body, "enum"):
+		sym.Kind = KindEnum
+		sym.Signature = "enum"
+		values := strings.TrimSpace(strings.TrimPrefix(body, "enum"))
+		for _, v := range strings.Split(values, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				sym.Children = append(sym.Children, nimEnumValue(v, start+1))
+			}
+		}
+		for j := start + 1; j <= end; j++ {
+			for _, v := range strings.Split(strings.TrimSpace(lines[j]), ",") {
+				v = strings.TrimSpace(v)
+				if v == "" || strings.HasPrefix(v, "#") || nimEnumValueRe.FindString(v) == "" {
+					continue
+				}
+				sym.Children = append(sym.Children, nimEnumValue(v, j+1))
+			}
+		}
+	case strings.HasPrefix(body, "concept"):
+		sym.Kind = KindInterface
+	case strings.Contains(" "+body+" ", " object "):
+		sym.Kind = KindClass
+		for j := start + 1; j <= end; j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "case ") || strings.HasPrefix(trimmed, "of ") {
+				continue
+			}
+			// "a, b*: int" declares several fields
+			names := trimmed
+			if idx := strings.Index(names, ":"); idx >= 0 {
+				names = names[:idx]
+			} else {
+				continue
+			}
+			for _, f := range strings.Split(names, ",") {
+				f = strings.TrimSpace(f)
+				if fm := nimFieldRe.FindStringSubmatch(f + ":"); fm != nil {
+					sym.Children = append(sym.Children, Symbol{
+						Name:      strings.Trim(fm[1], "`"),
+						Kind:      KindProperty,
+						LineStart: j + 1,
+						LineEnd:   j + 1,
+						Exported:  fm[2] == "*",
+					})
+				}
+			}
+		}
+	}
+	return sym
+}
+
+func nimEnumValue(v string, line int) Symbol {
+	name := strings.TrimSpace(strings.SplitN(v, "=", 2)[0])
+	return Symbol{Name: name, Kind: KindConstant, LineStart: line, LineEnd: line, Exported: true}
+}
+
+// nimIndent returns the number of leading spaces of a line.
+func nimIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// nimBlockEnd returns the index of the last line of the block opened at
+// start: the last non-blank line indented deeper than start.
+func nimBlockEnd(lines []string, start int) int {
+	indent := nimIndent(lines[start])
+	end := start
+	for j := start + 1; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed == "" {
+			continue
+		}
+		if nimIndent(lines[j]) <= indent {
+			break
+		}
+		end = j
+	}
+	return end
+}
+
+// nimSignature returns a routine's header, which may span lines, without
+// its pragmas and body.
+func nimSignature(lines []string, start int) string {
+	var sig strings.Builder
+	depth := 0
+	for j := start; j < len(lines); j++ {
+		for _, r := range strings.TrimSpace(lines[j]) {
+			switch r {
+			case '(', '[':
+				depth++
+			case ')', ']':
+				depth--
+			case '=':
+				if depth == 0 {
+					return nimStripPragmas(sig.String())
+				}
+			}
+			sig.WriteRune(r)
+		}
+		if depth == 0 {
+			break
+		}
+		sig.WriteRune(' ')
+	}
+	return nimStripPragmas(sig.String())
+}
+
+func nimStripPragmas(sig string) string {
+	if idx := strings.Index(sig, "{."); idx >= 0 {
+		sig = sig[:idx]
+	}
+	return strings.TrimSpace(sig)
+}
+
+// nimDoc returns a definition's doc comment: the "##" lines just before it
+// or, as Nim's documentation tools expect, the first ones inside its body.
+// Only the first paragraph is kept.
+func nimDoc(before []string, lines []string, start, end int) string {
+	doc := before
+	if len(doc) == 0 {
+		for j := start + 1; j <= end; j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if !strings.HasPrefix(trimmed, "##") {
+				if len(doc) > 0 || (trimmed != "" && !nimRoutineHeaderContinues(lines, start, j)) {
+					break
+				}
+				continue
+			}
+			doc = append(doc, strings.TrimSpace(strings.TrimPrefix(trimmed, "##")))
+		}
+	}
+	var kept []string
+	for _, l := range doc {
+		if l == "" {
+			if len(kept) > 0 {
+				break
+			}
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return strings.Join(kept, " ")
+}
+
+// nimRoutineHeaderContinues reports whether line j still belongs to the
+// multi-line header of the definition starting at start.
+func nimRoutineHeaderContinues(lines []string, start, j int) bool {
+	depth := 0
+	for k := start; k < j; k++ {
+		depth += strings.Count(lines[k], "(") - strings.Count(lines[k], ")")
+	}
+	return depth > 0
+}
+
+func (p *NimParser) extractRelationships(lines []string, analysis *FileAnalysis) {
+	inRoutine := false
+	routineIndent := 0
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := nimIndent(line)
+		if inRoutine && indent <= routineIndent {
+			inRoutine = false
+		}
+
+		if m := nimImportRe.FindStringSubmatch(trimmed); m != nil && indent == 0 {
+			for _, target := range nimImportTargets(m[1], m[2]) {
+				analysis.Relationships = append(analysis.Relationships, Relationship{
+					TargetFile: target,
+					Kind:       RelImport,
+					Line:       i + 1,
+				})
+			}
+			continue
+		}
+
+		if nimRoutineRe.MatchString(trimmed) && indent == 0 {
+			inRoutine, routineIndent = true, indent
+			// Calls in the header's default values are not calls made by the body
+			if idx := strings.Index(trimmed, "="); idx >= 0 && strings.Count(trimmed[:idx], "(") == strings.Count(trimmed[:idx], ")") {
+				trimmed = trimmed[idx+1:]
+			} else {
+				continue
+			}
+		}
+		if !inRoutine {
+			continue
+		}
+
+		code := stripNimStrings(trimmed)
+		for _, m := range nimCallRe.FindAllStringSubmatch(code, -1) {
+			if nimKeywords[m[2]] {
+				continue
+			}
+			target := m[2]
+			if m[1] != "" {
+				target = m[1] + "." + m[2]
+			}
+			analysis.Relationships = append(analysis.Relationships, Relationship{
+				TargetSymbol: target,
+				Kind:         RelCall,
+				Line:         i + 1,
+			})
+		}
+	}
+}
+
+// nimImportTargets lists the modules an import, include, or from statement
+// names: "import std/[os, strutils], json" gives std/os, std/strutils and
+// json; "from strutils import split" gives strutils.
+func nimImportTargets(keyword, rest string) []string {
+	if idx := strings.Index(rest, "#"); idx >= 0 {
+		rest = rest[:idx]
+	}
+	if keyword == "from" {
+		module, _, _ := strings.Cut(rest, " import ")
+		return []string{strings.Trim(strings.TrimSpace(module), `"`)}
+	}
+	if idx := strings.Index(rest, " except "); idx >= 0 {
+		rest = rest[:idx]
+	}
+
+	var targets []string
+	for _, part := range splitTopLevel(rest) {
+		part = strings.TrimSpace(part)
+		if idx := strings.Index(part, " as "); idx >= 0 {
+			part = strings.TrimSpace(part[:idx])
+		}
+		part = strings.Trim(part, `"`)
+		if open := strings.Index(part, "["); open >= 0 && strings.HasSuffix(part, "]") {
+			prefix := part[:open]
+			for _, sub := range strings.Split(part[open+1:len(part)-1], ",") {
+				if sub = strings.TrimSpace(sub); sub != "" {
+					targets = append(targets, prefix+sub)
+				}
+			}
+			continue
+		}
+		if part != "" {
+			targets = append(targets, part)
+		}
+	}
+	return targets
+}
+
+// stripNimStrings blanks out string literals and trailing comments so calls
+// are not read from them.
+func stripNimStrings(line string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			inString = !inString
+			b.WriteByte(' ')
+		case inString:
+			if c == '\\' && i+1 < len(line) {
+				i++
+			}
+			b.WriteByte(' ')
+		case c == '#':
+			return b.String()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
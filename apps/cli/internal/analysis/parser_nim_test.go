@@ -0,0 +1,89 @@
+package analysis
+
+import "testing"
+
+func TestNimParser(t *testing.T) {
+	code := `import std/[strutils, os], json
+include helpers
+
+type
+  Shape* = object ## A drawable shape
+    name*: string
+    sides: int
+  Color = enum
+    red, green
+    blue
+
+proc area*(s: Shape; scale: float = 1.0): float =
+  ## Returns the scaled area.
+  result = compute(s.sides, scale)
+
+proc compute(sides: int; scale: float): float =
+  float(sides) * scale
+
+method draw(s: Shape) {.base.} =
+  echo s.name.toUpperAscii()
+`
+	fa, err := NewNimParser().Parse([]byte(code), "shapes.nim")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if fa.Language != "nim" {
+		t.Errorf("Language = %q, want nim", fa.Language)
+	}
+
+	syms := make(map[string]Symbol)
+	for _, s := range fa.Symbols {
+		syms[s.Name] = s
+	}
+	area, ok := syms["area"]
+	if !ok || !area.Exported || area.Kind != KindFunction {
+		t.Fatalf("area = %+v, want an exported function", area)
+	}
+	if area.DocComment != "Returns the scaled area." || area.Signature != "proc area*(s: Shape; scale: float = 1.0): float" {
+		t.Errorf("area doc %q, signature %q", area.DocComment, area.Signature)
+	}
+	if area.LineStart != 12 || area.LineEnd != 14 {
+		t.Errorf("area spans %d-%d, want 12-14", area.LineStart, area.LineEnd)
+	}
+	if c := syms["compute"]; c.Exported {
+		t.Errorf("compute has no export marker but is exported")
+	}
+	if d := syms["draw"]; d.Kind != KindMethod || d.Signature != "method draw(s: Shape)" {
+		t.Errorf("draw = %+v, want a method without pragmas in its signature", d)
+	}
+
+	shape := syms["Shape"]
+	if shape.Kind != KindClass || !shape.Exported || shape.DocComment != "A drawable shape" || len(shape.Children) != 2 {
+		t.Fatalf("Shape = %+v", shape)
+	}
+	if !shape.Children[0].Exported || shape.Children[1].Exported {
+		t.Errorf("fields = %+v, want name exported and sides not", shape.Children)
+	}
+	if color := syms["Color"]; color.Kind != KindEnum || color.Exported || len(color.Children) != 3 {
+		t.Errorf("Color = %+v, want an unexported enum of three values", color)
+	}
+
+	var imports []string
+	calls := make(map[string]bool)
+	for _, r := range fa.Relationships {
+		switch r.Kind {
+		case RelImport:
+			imports = append(imports, r.TargetFile)
+		case RelCall:
+			calls[r.TargetSymbol] = true
+		}
+	}
+	want := []string{"std/strutils", "std/os", "json", "helpers"}
+	if len(imports) != len(want) {
+		t.Fatalf("imports = %v, want %v", imports, want)
+	}
+	for i := range want {
+		if imports[i] != want[i] {
+			t.Errorf("imports[%d] = %q, want %q", i, imports[i], want[i])
+		}
+	}
+	if !calls["compute"] || !calls["name.toUpperAscii"] || calls["echo"] {
+		t.Errorf("calls = %v, want compute and name.toUpperAscii", calls)
+	}
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type OCamlParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *OCamlParser) Parse(content []byte, filePath string) (*FileAnalysis, err
 }
 
 func (p *OCamlParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -323,6 +329,11 @@ func (p *OCamlParser) parseClassDef(node *sitter.Node, content []byte) *Symbol {
 }
 
 func (p *OCamlParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -9,6 +9,7 @@ import (
 )
 
 type PHPParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,39 +43,60 @@ func (p *PHPParser) Parse(content []byte, filePath string) (*FileAnalysis, error
 }
 
 func (p *PHPParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
 			continue
 		}
 
+		// Declarations are not descended into, as their members are
+		// children of their symbols; other statements are, which finds
+		// the declarations of braced namespaces and "if (!function_exists())"
+		// guards.
 		switch child.Type() {
 		case "class_declaration":
 			sym := p.parseClass(child, content)
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
+			continue
 
 		case "interface_declaration":
 			sym := p.parseInterface(child, content)
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
+			continue
 
 		case "trait_declaration":
 			sym := p.parseTrait(child, content)
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
+			continue
+
+		case "enum_declaration":
+			sym := p.parseEnum(child, content)
+			if sym != nil {
+				analysis.Symbols = append(analysis.Symbols, *sym)
+			}
+			continue
 
 		case "function_definition":
 			sym := p.parseFunction(child, content)
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
+			continue
 
 		case "const_declaration":
-			p.parseConstDecl(child, content, analysis)
+			analysis.Symbols = append(analysis.Symbols, p.parseConstDecl(child, content)...)
+			continue
 
 		case "namespace_definition":
 			p.parseNamespace(child, content, analysis)
@@ -104,6 +126,7 @@ func (p *PHPParser) parseClass(node *sitter.Node, content []byte) *Symbol {
 		Kind:       KindClass,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "attribute_list", "declaration_list"),
 		DocComment: doc,
 		Exported:   true,
 		Children:   children,
@@ -130,6 +153,7 @@ func (p *PHPParser) parseInterface(node *sitter.Node, content []byte) *Symbol {
 		Kind:       KindInterface,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "attribute_list", "declaration_list"),
 		DocComment: doc,
 		Exported:   true,
 		Children:   children,
@@ -144,14 +168,51 @@ func (p *PHPParser) parseTrait(node *sitter.Node, content []byte) *Symbol {
 
 	name := nameNode.Content(content)
 	doc := p.extractDocComment(node, content)
+	var children []Symbol
+
+	body := node.ChildByFieldName("body")
+	if body != nil {
+		children = p.extractClassMembers(body, content)
+	}
 
 	return &Symbol{
 		Name:       name,
 		Kind:       KindInterface,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "attribute_list", "declaration_list"),
+		DocComment: doc,
+		Exported:   true,
+		Children:   children,
+	}
+}
+
+// parseEnum parses a PHP 8.1 enum. Its cases are constants, listed with
+// its methods and constants.
+func (p *PHPParser) parseEnum(node *sitter.Node, content []byte) *Symbol {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+
+	name := nameNode.Content(content)
+	doc := p.extractDocComment(node, content)
+	var children []Symbol
+
+	body := node.ChildByFieldName("body")
+	if body != nil {
+		children = p.extractClassMembers(body, content)
+	}
+
+	return &Symbol{
+		Name:       name,
+		Kind:       KindEnum,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "attribute_list", "enum_declaration_list"),
 		DocComment: doc,
 		Exported:   true,
+		Children:   children,
 	}
 }
 
@@ -163,7 +224,7 @@ func (p *PHPParser) parseFunction(node *sitter.Node, content []byte) *Symbol {
 
 	name := nameNode.Content(content)
 	doc := p.extractDocComment(node, content)
-	sig := p.extractFunctionSignature(node, content)
+	sig := p.extractSignature(node, content)
 
 	return &Symbol{
 		Name:       name,
@@ -176,22 +237,34 @@ func (p *PHPParser) parseFunction(node *sitter.Node, content []byte) *Symbol {
 	}
 }
 
-func (p *PHPParser) parseConstDecl(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// parseConstDecl returns the constants of a const declaration, with
+// signatures such as "public const MAX = 10".
+func (p *PHPParser) parseConstDecl(node *sitter.Node, content []byte) []Symbol {
+	header := declarationHeader(node, content, "attribute_list", "const_element")
+	doc := p.extractDocComment(node, content)
+	exported := p.isPublic(node, content)
+
+	var consts []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		if child != nil && child.Type() == "const_element" {
-			nameNode := child.ChildByFieldName("name")
-			if nameNode != nil {
-				analysis.Symbols = append(analysis.Symbols, Symbol{
-					Name:      nameNode.Content(content),
-					Kind:      KindConstant,
-					LineStart: int(node.StartPoint().Row) + 1,
-					LineEnd:   int(node.EndPoint().Row) + 1,
-					Exported:  true,
-				})
-			}
+		if child == nil || child.Type() != "const_element" {
+			continue
 		}
-	}
+		nameNode := child.NamedChild(0)
+		if nameNode == nil || nameNode.Type() != "name" {
+			continue
+		}
+		consts = append(consts, Symbol{
+			Name:       nameNode.Content(content),
+			Kind:       KindConstant,
+			LineStart:  int(node.StartPoint().Row) + 1,
+			LineEnd:    int(node.EndPoint().Row) + 1,
+			Signature:  header + " " + strings.Join(strings.Fields(child.Content(content)), " "),
+			DocComment: doc,
+			Exported:   exported,
+		})
+	}
+	return consts
 }
 
 func (p *PHPParser) parseNamespace(node *sitter.Node, content []byte, analysis *FileAnalysis) {
@@ -209,6 +282,9 @@ func (p *PHPParser) parseNamespace(node *sitter.Node, content []byte, analysis *
 	})
 }
 
+// extractClassMembers returns the methods, properties, constants, and enum
+// cases of a class, trait, or enum body. Properties promoted from the
+// constructor's parameters are listed as properties too.
 func (p *PHPParser) extractClassMembers(node *sitter.Node, content []byte) []Symbol {
 	var members []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -222,26 +298,29 @@ func (p *PHPParser) extractClassMembers(node *sitter.Node, content []byte) []Sym
 			sym := p.parseMethod(child, content)
 			if sym != nil {
 				members = append(members, *sym)
+				if sym.Name == "__construct" {
+					members = append(members, p.parsePromotedProperties(child, content)...)
+				}
 			}
 
 		case "property_declaration":
 			p.parseProperties(child, content, &members)
 
 		case "const_declaration":
-			for j := 0; j < int(child.ChildCount()); j++ {
-				elem := child.Child(j)
-				if elem != nil && elem.Type() == "const_element" {
-					nameNode := elem.ChildByFieldName("name")
-					if nameNode != nil {
-						members = append(members, Symbol{
-							Name:      nameNode.Content(content),
-							Kind:      KindConstant,
-							LineStart: int(child.StartPoint().Row) + 1,
-							LineEnd:   int(child.EndPoint().Row) + 1,
-							Exported:  true,
-						})
-					}
-				}
+			members = append(members, p.parseConstDecl(child, content)...)
+
+		case "enum_case":
+			nameNode := child.ChildByFieldName("name")
+			if nameNode != nil {
+				members = append(members, Symbol{
+					Name:       nameNode.Content(content),
+					Kind:       KindConstant,
+					LineStart:  int(child.StartPoint().Row) + 1,
+					LineEnd:    int(child.EndPoint().Row) + 1,
+					Signature:  strings.TrimSuffix(strings.Join(strings.Fields(child.Content(content)), " "), ";"),
+					DocComment: p.extractDocComment(child, content),
+					Exported:   true,
+				})
 			}
 		}
 	}
@@ -256,7 +335,7 @@ func (p *PHPParser) parseMethod(node *sitter.Node, content []byte) *Symbol {
 
 	name := nameNode.Content(content)
 	doc := p.extractDocComment(node, content)
-	sig := p.extractMethodSignature(node, content)
+	sig := p.extractSignature(node, content)
 	exported := p.isPublic(node, content)
 
 	return &Symbol{
@@ -270,28 +349,65 @@ func (p *PHPParser) parseMethod(node *sitter.Node, content []byte) *Symbol {
 	}
 }
 
+// parseProperties adds the properties of a property declaration, with
+// signatures such as "protected string $table".
 func (p *PHPParser) parseProperties(node *sitter.Node, content []byte, members *[]Symbol) {
 	exported := p.isPublic(node, content)
+	header := declarationHeader(node, content, "attribute_list", "property_element")
+	doc := p.extractDocComment(node, content)
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child != nil && child.Type() == "property_element" {
-			nameNode := child.ChildByFieldName("name")
-			if nameNode != nil {
+			nameNode := child.NamedChild(0)
+			if nameNode != nil && nameNode.Type() == "variable_name" {
 				name := nameNode.Content(content)
-				name = strings.TrimPrefix(name, "$")
 				*members = append(*members, Symbol{
-					Name:      name,
-					Kind:      KindProperty,
-					LineStart: int(node.StartPoint().Row) + 1,
-					LineEnd:   int(node.EndPoint().Row) + 1,
-					Exported:  exported,
+					Name:       strings.TrimPrefix(name, "$"),
+					Kind:       KindProperty,
+					LineStart:  int(node.StartPoint().Row) + 1,
+					LineEnd:    int(node.EndPoint().Row) + 1,
+					Signature:  header + " " + name,
+					DocComment: doc,
+					Exported:   exported,
 				})
 			}
 		}
 	}
 }
 
+// parsePromotedProperties returns the properties declared by constructor
+// parameters with a visibility, as in
+// "__construct(private readonly UserRepo $repo)".
+func (p *PHPParser) parsePromotedProperties(node *sitter.Node, content []byte) []Symbol {
+	params := node.ChildByFieldName("parameters")
+	if params == nil {
+		return nil
+	}
+
+	var props []Symbol
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != "property_promotion_parameter" {
+			continue
+		}
+		nameNode := param.ChildByFieldName("name")
+		if nameNode == nil {
+			continue
+		}
+		visibility := param.ChildByFieldName("visibility")
+		props = append(props, Symbol{
+			Name:      strings.TrimPrefix(nameNode.Content(content), "$"),
+			Kind:      KindProperty,
+			LineStart: int(param.StartPoint().Row) + 1,
+			LineEnd:   int(param.EndPoint().Row) + 1,
+			Signature: declarationHeader(param, content, "attribute_list", "="),
+			Exported:  visibility == nil || visibility.Content(content) == "public",
+		})
+	}
+	return props
+}
+
 func (p *PHPParser) extractInterfaceMembers(node *sitter.Node, content []byte) []Symbol {
 	var members []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -300,23 +416,27 @@ func (p *PHPParser) extractInterfaceMembers(node *sitter.Node, content []byte) [
 			continue
 		}
 
-		if child.Type() == "method_declaration" {
-			nameNode := child.ChildByFieldName("name")
-			if nameNode != nil {
-				members = append(members, Symbol{
-					Name:      nameNode.Content(content),
-					Kind:      KindMethod,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  true,
-				})
+		switch child.Type() {
+		case "method_declaration":
+			sym := p.parseMethod(child, content)
+			if sym != nil {
+				sym.Exported = true
+				members = append(members, *sym)
 			}
+
+		case "const_declaration":
+			members = append(members, p.parseConstDecl(child, content)...)
 		}
 	}
 	return members
 }
 
 func (p *PHPParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -327,76 +447,157 @@ func (p *PHPParser) extractRelationships(node *sitter.Node, content []byte, anal
 		case "namespace_use_declaration":
 			p.parseUse(child, content, analysis)
 
-		case "class_declaration":
-			p.parseClassRelationships(child, content, analysis)
+		case "class_declaration", "interface_declaration", "enum_declaration":
+			p.parseSupertypes(child, content, analysis)
 
-		case "interface_declaration":
-			p.parseInterfaceExtends(child, content, analysis)
+		case "use_declaration":
+			p.parseTraitUse(child, content, analysis)
+
+		case "function_call_expression", "member_call_expression", "nullsafe_member_call_expression", "scoped_call_expression":
+			p.parseCall(child, content, analysis)
 		}
 
 		p.extractRelationships(child, content, analysis)
 	}
 }
 
+// parseUse records the imports of a use statement. A group use such as
+// "use App\Models\{User, Post}" imports App\Models\User and
+// App\Models\Post; aliases are dropped.
 func (p *PHPParser) parseUse(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	prefix := ""
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "namespace_name":
+			prefix = child.Content(content) + "\\"
+		case "namespace_use_clause":
+			p.addImport(prefix, child, content, analysis)
+		case "namespace_use_group":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				if clause := child.NamedChild(j); clause.Type() == "namespace_use_group_clause" {
+					p.addImport(prefix, clause, content, analysis)
+				}
+			}
+		}
+	}
+}
+
+func (p *PHPParser) addImport(prefix string, clause *sitter.Node, content []byte, analysis *FileAnalysis) {
+	name := clause.NamedChild(0)
+	if name == nil {
+		return
+	}
+	analysis.Relationships = append(analysis.Relationships, Relationship{
+		TargetFile: prefix + strings.TrimPrefix(name.Content(content), "\\"),
+		Kind:       RelImport,
+		Line:       int(clause.StartPoint().Row) + 1,
+	})
+}
+
+// parseSupertypes records what a class, interface, or enum declaration
+// extends and implements: "class A extends B implements C, D" gives an
+// extends relationship to B and implements relationships to C and D. An
+// interface's "extends" list is recorded as extends.
+func (p *PHPParser) parseSupertypes(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+
 	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && (child.Type() == "namespace_use_clause" || child.Type() == "qualified_name") {
+		clause := node.Child(i)
+		if clause == nil {
+			continue
+		}
+		var kind RelationshipKind
+		switch clause.Type() {
+		case "base_clause":
+			kind = RelExtends
+		case "class_interface_clause":
+			kind = RelImplements
+		default:
+			continue
+		}
+		for j := 0; j < int(clause.NamedChildCount()); j++ {
+			typeNode := clause.NamedChild(j)
+			if typeNode.Type() != "name" && typeNode.Type() != "qualified_name" {
+				continue
+			}
 			analysis.Relationships = append(analysis.Relationships, Relationship{
-				TargetFile: child.Content(content),
-				Kind:       RelImport,
-				Line:       int(node.StartPoint().Row) + 1,
+				SourceSymbol: nameNode.Content(content),
+				TargetSymbol: strings.TrimPrefix(typeNode.Content(content), "\\"),
+				Kind:         kind,
+				Line:         int(typeNode.StartPoint().Row) + 1,
 			})
 		}
 	}
 }
 
-func (p *PHPParser) parseClassRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	baseClause := node.ChildByFieldName("base_clause")
-	if baseClause != nil {
-		for i := 0; i < int(baseClause.ChildCount()); i++ {
-			child := baseClause.Child(i)
-			if child != nil && (child.Type() == "name" || child.Type() == "qualified_name") {
-				analysis.Relationships = append(analysis.Relationships, Relationship{
-					TargetSymbol: child.Content(content),
-					Kind:         RelExtends,
-					Line:         int(child.StartPoint().Row) + 1,
-				})
-			}
+// parseTraitUse records the traits a class uses ("use Authorizes;") as
+// imports of the trait.
+func (p *PHPParser) parseTraitUse(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	var owner string
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		if nameNode := n.ChildByFieldName("name"); nameNode != nil {
+			owner = nameNode.Content(content)
+			break
 		}
 	}
 
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && child.Type() == "class_interface_clause" {
-			for j := 0; j < int(child.ChildCount()); j++ {
-				iface := child.Child(j)
-				if iface != nil && (iface.Type() == "name" || iface.Type() == "qualified_name") {
-					analysis.Relationships = append(analysis.Relationships, Relationship{
-						TargetSymbol: iface.Content(content),
-						Kind:         RelImplements,
-						Line:         int(iface.StartPoint().Row) + 1,
-					})
-				}
-			}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		trait := node.NamedChild(i)
+		if trait.Type() != "name" && trait.Type() != "qualified_name" {
+			continue
 		}
+		analysis.Relationships = append(analysis.Relationships, Relationship{
+			SourceSymbol: owner,
+			TargetSymbol: strings.TrimPrefix(trait.Content(content), "\\"),
+			Kind:         RelImport,
+			Line:         int(trait.StartPoint().Row) + 1,
+		})
 	}
 }
 
-func (p *PHPParser) parseInterfaceExtends(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	baseClause := node.ChildByFieldName("base_clause")
-	if baseClause != nil {
-		for i := 0; i < int(baseClause.ChildCount()); i++ {
-			child := baseClause.Child(i)
-			if child != nil && (child.Type() == "name" || child.Type() == "qualified_name") {
-				analysis.Relationships = append(analysis.Relationships, Relationship{
-					TargetSymbol: child.Content(content),
-					Kind:         RelExtends,
-					Line:         int(child.StartPoint().Row) + 1,
-				})
-			}
+// parseCall records a call: "helper($x)" calls helper, "$this->save()"
+// calls this.save, "$repo->find()" calls $repo.find, and "User::find()"
+// calls User::find.
+func (p *PHPParser) parseCall(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	var nameNode *sitter.Node
+	target := ""
+	switch node.Type() {
+	case "function_call_expression":
+		nameNode = node.ChildByFieldName("function")
+		if nameNode == nil || (nameNode.Type() != "name" && nameNode.Type() != "qualified_name") {
+			return // A closure or callable in a variable
+		}
+		target = strings.TrimPrefix(nameNode.Content(content), "\\")
+	case "scoped_call_expression":
+		nameNode = node.ChildByFieldName("name")
+		scope := node.ChildByFieldName("scope")
+		if nameNode == nil || scope == nil {
+			return
+		}
+		target = scope.Content(content) + "::" + nameNode.Content(content)
+	default:
+		nameNode = node.ChildByFieldName("name")
+		object := node.ChildByFieldName("object")
+		if nameNode == nil || object == nil {
+			return
+		}
+		receiver := object.Content(content)
+		if receiver == "$this" {
+			receiver = "this"
 		}
+		target = receiver + "." + nameNode.Content(content)
 	}
+
+	analysis.Relationships = append(analysis.Relationships, Relationship{
+		TargetSymbol: target,
+		Kind:         RelCall,
+		Line:         int(nameNode.StartPoint().Row) + 1,
+		Column:       int(nameNode.StartPoint().Column),
+	})
 }
 
 func (p *PHPParser) isPublic(node *sitter.Node, content []byte) bool {
@@ -414,58 +615,43 @@ func (p *PHPParser) isPublic(node *sitter.Node, content []byte) bool {
 	return true
 }
 
-func (p *PHPParser) extractFunctionSignature(node *sitter.Node, content []byte) string {
-	nameNode := node.ChildByFieldName("name")
-	paramsNode := node.ChildByFieldName("parameters")
-
-	var sig strings.Builder
-	sig.WriteString("function ")
-	if nameNode != nil {
-		sig.WriteString(nameNode.Content(content))
-	}
-	if paramsNode != nil {
-		sig.WriteString(paramsNode.Content(content))
-	}
-
-	return sig.String()
-}
-
-func (p *PHPParser) extractMethodSignature(node *sitter.Node, content []byte) string {
-	nameNode := node.ChildByFieldName("name")
-	paramsNode := node.ChildByFieldName("parameters")
-
-	var sig strings.Builder
-	if nameNode != nil {
-		sig.WriteString(nameNode.Content(content))
-	}
-	if paramsNode != nil {
-		sig.WriteString(paramsNode.Content(content))
-	}
-
-	return sig.String()
+// extractSignature returns the header of a function or method with its
+// modifiers and type hints, such as
+// "public static function find(int $id): ?User".
+func (p *PHPParser) extractSignature(node *sitter.Node, content []byte) string {
+	sig := declarationHeader(node, content, "attribute_list", "compound_statement")
+	return strings.TrimSpace(strings.TrimSuffix(sig, ";"))
 }
 
+// extractDocComment returns the PHPDoc comment before a declaration, up to
+// its first tag such as @param, or a // or # comment on the line above.
 func (p *PHPParser) extractDocComment(node *sitter.Node, content []byte) string {
 	prev := node.PrevSibling()
-	if prev == nil {
+	if prev == nil || prev.Type() != "comment" {
 		return ""
 	}
 
-	if prev.Type() == "comment" {
-		comment := prev.Content(content)
-		if strings.HasPrefix(comment, "/**") {
-			comment = strings.TrimPrefix(comment, "/**")
-			comment = strings.TrimSuffix(comment, "*/")
-			comment = strings.TrimSpace(comment)
-			lines := strings.Split(comment, "\n")
-			if len(lines) > 0 {
-				first := strings.TrimSpace(lines[0])
-				first = strings.TrimPrefix(first, "* ")
-				return first
-			}
+	comment := prev.Content(content)
+	if !strings.HasPrefix(comment, "/**") {
+		if strings.HasPrefix(comment, "/*") {
+			return ""
+		}
+		comment = strings.TrimPrefix(comment, "#")
+		return strings.TrimSpace(strings.TrimPrefix(comment, "//"))
+	}
+	comment = strings.TrimPrefix(comment, "/**")
+	comment = strings.TrimSuffix(comment, "*/")
+	var cleaned []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			break
+		}
+		if line != "" {
+			cleaned = append(cleaned, line)
 		}
-		return strings.TrimPrefix(comment, "// ")
 	}
-
-	return ""
+	return strings.Join(cleaned, " ")
 }
@@ -9,6 +9,7 @@ import (
 )
 
 type ProtobufParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *ProtobufParser) Parse(content []byte, filePath string) (*FileAnalysis,
 }
 
 func (p *ProtobufParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -128,6 +134,11 @@ func (p *ProtobufParser) parseMessage(node *sitter.Node, content []byte) *Symbol
 }
 
 func (p *ProtobufParser) extractMessageFields(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var fields []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -364,6 +375,11 @@ func (p *ProtobufParser) parseOption(node *sitter.Node, content []byte, analysis
 }
 
 func (p *ProtobufParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
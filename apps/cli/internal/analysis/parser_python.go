@@ -9,6 +9,7 @@ import (
 )
 
 type PythonParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *PythonParser) Parse(content []byte, filePath string) (*FileAnalysis, er
 }
 
 func (p *PythonParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis, depth int) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -68,6 +74,18 @@ func (p *PythonParser) extractSymbols(node *sitter.Node, content []byte, analysi
 			if depth == 0 {
 				p.parseAssignment(child, content, analysis)
 			}
+
+		case "type_alias_statement":
+			if name, _ := pythonTypeAliasParts(child); name != nil && depth == 0 {
+				analysis.Symbols = append(analysis.Symbols, Symbol{
+					Name:      name.Content(content),
+					Kind:      KindTypeAlias,
+					LineStart: int(child.StartPoint().Row) + 1,
+					LineEnd:   int(child.EndPoint().Row) + 1,
+					Signature: child.Content(content),
+					Exported:  !strings.HasPrefix(name.Content(content), "_"),
+				})
+			}
 		}
 
 		if child.Type() != "class_definition" && child.Type() != "function_definition" {
@@ -103,13 +121,14 @@ func (p *PythonParser) parseFunctionDef(node *sitter.Node, content []byte, depth
 	}
 
 	return &Symbol{
-		Name:       name,
-		Kind:       kind,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		Signature:  "def " + name + params + returnType,
-		DocComment: doc,
-		Exported:   !strings.HasPrefix(name, "_"),
+		Name:        name,
+		Kind:        kind,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   "def " + name + params + returnType,
+		DocComment:  doc,
+		Exported:    !strings.HasPrefix(name, "_"),
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -123,12 +142,13 @@ func (p *PythonParser) parseClassDef(node *sitter.Node, content []byte) *Symbol
 	doc := p.extractDocstring(node, content)
 
 	sym := &Symbol{
-		Name:       name,
-		Kind:       KindClass,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   !strings.HasPrefix(name, "_"),
+		Name:        name,
+		Kind:        KindClass,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		DocComment:  doc,
+		Exported:    !strings.HasPrefix(name, "_"),
+		Annotations: p.annotations(node, content),
 	}
 
 	bodyNode := node.ChildByFieldName("body")
@@ -237,6 +257,14 @@ func (p *PythonParser) parseAssignment(node *sitter.Node, content []byte, analys
 		if strings.ToUpper(name) == name {
 			kind = KindConstant
 		}
+		if isTypeAliasAnnotation(node, content) {
+			kind = KindTypeAlias
+		}
+
+		var value string
+		if rightNode := node.ChildByFieldName("right"); rightNode != nil && kind != KindTypeAlias {
+			value = literalValue(rightNode.Content(content))
+		}
 
 		analysis.Symbols = append(analysis.Symbols, Symbol{
 			Name:      name,
@@ -244,10 +272,37 @@ func (p *PythonParser) parseAssignment(node *sitter.Node, content []byte, analys
 			LineStart: int(node.StartPoint().Row) + 1,
 			LineEnd:   int(node.EndPoint().Row) + 1,
 			Exported:  !strings.HasPrefix(name, "_"),
+			Value:     value,
 		})
 	}
 }
 
+// isTypeAliasAnnotation reports whether an assignment is annotated as an
+// explicit alias, as in "UserID: TypeAlias = int".
+func isTypeAliasAnnotation(node *sitter.Node, content []byte) bool {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return false
+	}
+	annotation := typeNode.Content(content)
+	return annotation == "TypeAlias" || strings.HasSuffix(annotation, ".TypeAlias")
+}
+
+// pythonTypeAliasParts returns the name and value of a Python 3.12
+// "type Name = value" statement, whose two type children are unlabeled.
+func pythonTypeAliasParts(node *sitter.Node) (name, value *sitter.Node) {
+	var types []*sitter.Node
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); child != nil && child.Type() == "type" {
+			types = append(types, child)
+		}
+	}
+	if len(types) < 2 {
+		return nil, nil
+	}
+	return types[0], types[1]
+}
+
 func (p *PythonParser) extractDocstring(node *sitter.Node, content []byte) string {
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode == nil {
@@ -281,6 +336,11 @@ func (p *PythonParser) extractDocstring(node *sitter.Node, content []byte) strin
 }
 
 func (p *PythonParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -296,12 +356,53 @@ func (p *PythonParser) extractRelationships(node *sitter.Node, content []byte, a
 
 		case "call":
 			p.parseCallExpression(child, content, analysis)
+
+		case "class_definition":
+			p.parseSuperclasses(child, content, analysis)
+
+		case "assignment":
+			if left := child.ChildByFieldName("left"); left != nil && isTypeAliasAnnotation(child, content) {
+				analysis.Relationships = append(analysis.Relationships,
+					typeAliasReferences(left.Content(content), child.ChildByFieldName("right"), content)...)
+			}
+
+		case "type_alias_statement":
+			if name, value := pythonTypeAliasParts(child); name != nil {
+				analysis.Relationships = append(analysis.Relationships,
+					typeAliasReferences(name.Content(content), value, content)...)
+			}
 		}
 
 		p.extractRelationships(child, content, analysis)
 	}
 }
 
+// parseSuperclasses emits one extends relationship per base class, in the
+// order declared. Keyword arguments such as metaclass= are not bases and are
+// skipped, as are *args-style splats.
+func (p *PythonParser) parseSuperclasses(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	bases := node.ChildByFieldName("superclasses")
+	if bases == nil {
+		return
+	}
+
+	for i := 0; i < int(bases.NamedChildCount()); i++ {
+		base := bases.NamedChild(i)
+		if base == nil {
+			continue
+		}
+
+		switch base.Type() {
+		case "identifier", "attribute", "subscript":
+			analysis.Relationships = append(analysis.Relationships, Relationship{
+				TargetSymbol: base.Content(content),
+				Kind:         RelExtends,
+				Line:         int(base.StartPoint().Row) + 1,
+			})
+		}
+	}
+}
+
 func (p *PythonParser) parseCallExpression(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	funcNode := node.ChildByFieldName("function")
 	if funcNode == nil {
@@ -379,3 +480,14 @@ func (p *PythonParser) parseImportFromStatement(node *sitter.Node, content []byt
 		})
 	}
 }
+
+// annotations returns the decorators on a Python function or class
+// definition (e.g. "app.route(\"/\")"), which tree-sitter holds in the
+// decorated_definition wrapping it.
+func (p *PythonParser) annotations(node *sitter.Node, content []byte) []string {
+	parent := node.Parent()
+	if parent == nil || parent.Type() != "decorated_definition" {
+		return nil
+	}
+	return childAnnotations(parent, content, "", "decorator")
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type RubyParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -41,7 +42,15 @@ func (p *RubyParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 	return analysis, nil
 }
 
+// extractSymbols collects the top-level definitions under node. Classes and
+// modules hold their members as children, so their bodies and method bodies
+// are not searched again here.
 func (p *RubyParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -74,10 +83,13 @@ func (p *RubyParser) extractSymbols(node *sitter.Node, content []byte, analysis
 			}
 
 		case "assignment":
-			p.parseAssignment(child, content, analysis)
-		}
+			if sym := p.parseAssignment(child, content); sym != nil {
+				analysis.Symbols = append(analysis.Symbols, *sym)
+			}
 
-		p.extractSymbols(child, content, analysis)
+		default:
+			p.extractSymbols(child, content, analysis)
+		}
 	}
 }
 
@@ -174,24 +186,30 @@ func (p *RubyParser) parseSingletonMethod(node *sitter.Node, content []byte) *Sy
 	}
 }
 
-func (p *RubyParser) parseAssignment(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// parseAssignment returns the constant node assigns, or nil if it assigns
+// a variable.
+func (p *RubyParser) parseAssignment(node *sitter.Node, content []byte) *Symbol {
 	left := node.ChildByFieldName("left")
-	if left == nil {
-		return
+	if left == nil || left.Type() != "constant" {
+		return nil
 	}
-
-	if left.Type() == "constant" {
-		analysis.Symbols = append(analysis.Symbols, Symbol{
-			Name:      left.Content(content),
-			Kind:      KindConstant,
-			LineStart: int(node.StartPoint().Row) + 1,
-			LineEnd:   int(node.EndPoint().Row) + 1,
-			Exported:  true,
-		})
+	return &Symbol{
+		Name:      left.Content(content),
+		Kind:      KindConstant,
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+		Exported:  true,
 	}
 }
 
+// extractClassMembers returns the members of a class or module body: its
+// methods, attributes, constants, and nested classes and modules.
 func (p *RubyParser) extractClassMembers(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var members []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -200,6 +218,21 @@ func (p *RubyParser) extractClassMembers(node *sitter.Node, content []byte) []Sy
 		}
 
 		switch child.Type() {
+		case "class":
+			if sym := p.parseClass(child, content); sym != nil {
+				members = append(members, *sym)
+			}
+
+		case "module":
+			if sym := p.parseModule(child, content); sym != nil {
+				members = append(members, *sym)
+			}
+
+		case "assignment":
+			if sym := p.parseAssignment(child, content); sym != nil {
+				members = append(members, *sym)
+			}
+
 		case "method":
 			sym := p.parseMethod(child, content)
 			if sym != nil {
@@ -251,6 +284,11 @@ func (p *RubyParser) parseAttrAccessor(node *sitter.Node, content []byte, member
 }
 
 func (p *RubyParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -259,7 +297,7 @@ func (p *RubyParser) extractRelationships(node *sitter.Node, content []byte, ana
 
 		switch child.Type() {
 		case "call":
-			p.parseRequire(child, content, analysis)
+			p.parseCall(child, content, analysis)
 
 		case "class":
 			p.parseInheritance(child, content, analysis)
@@ -269,17 +307,55 @@ func (p *RubyParser) extractRelationships(node *sitter.Node, content []byte, ana
 	}
 }
 
-func (p *RubyParser) parseRequire(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// parseCall records a call site: a require as an import, an include,
+// extend, or prepend as an import of the mixed-in module, and any other
+// method call as a call. Attribute macros declare members and are skipped.
+func (p *RubyParser) parseCall(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	methodNode := node.ChildByFieldName("method")
 	if methodNode == nil {
 		return
 	}
 
 	method := methodNode.Content(content)
-	if method != "require" && method != "require_relative" {
+	switch method {
+	case "require", "require_relative":
+		p.parseRequire(node, content, analysis)
+	case "include", "extend", "prepend":
+		p.parseMixin(node, content, analysis)
+	case "attr_reader", "attr_writer", "attr_accessor":
+	default:
+		target := method
+		if receiver := node.ChildByFieldName("receiver"); receiver != nil {
+			target = receiver.Content(content) + "." + method
+		}
+		analysis.Relationships = append(analysis.Relationships, Relationship{
+			TargetSymbol: target,
+			Kind:         RelCall,
+			Line:         int(node.StartPoint().Row) + 1,
+			Column:       int(methodNode.StartPoint().Column),
+		})
+	}
+}
+
+func (p *RubyParser) parseMixin(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	args := node.ChildByFieldName("arguments")
+	if args == nil {
 		return
 	}
 
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		if arg.Type() == "constant" || arg.Type() == "scope_resolution" {
+			analysis.Relationships = append(analysis.Relationships, Relationship{
+				TargetSymbol: arg.Content(content),
+				Kind:         RelImport,
+				Line:         int(node.StartPoint().Row) + 1,
+			})
+		}
+	}
+}
+
+func (p *RubyParser) parseRequire(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	args := node.ChildByFieldName("arguments")
 	if args == nil {
 		return
@@ -300,9 +376,11 @@ func (p *RubyParser) parseRequire(node *sitter.Node, content []byte, analysis *F
 
 func (p *RubyParser) parseInheritance(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	superclass := node.ChildByFieldName("superclass")
-	if superclass != nil {
+	if superclass != nil && superclass.NamedChildCount() > 0 {
+		// The superclass node spans "< Base"; its expression is the parent
+		parent := superclass.NamedChild(int(superclass.NamedChildCount()) - 1)
 		analysis.Relationships = append(analysis.Relationships, Relationship{
-			TargetSymbol: superclass.Content(content),
+			TargetSymbol: parent.Content(content),
 			Kind:         RelExtends,
 			Line:         int(node.StartPoint().Row) + 1,
 		})
@@ -327,6 +405,10 @@ func (p *RubyParser) extractMethodSignature(node *sitter.Node, content []byte) s
 
 func (p *RubyParser) extractPrecedingComment(node *sitter.Node, content []byte) string {
 	prev := node.PrevSibling()
+	if prev == nil && node.Parent() != nil && node.Parent().Type() == "body_statement" {
+		// A comment above the first statement of a body precedes the body
+		prev = node.Parent().PrevSibling()
+	}
 	if prev == nil {
 		return ""
 	}
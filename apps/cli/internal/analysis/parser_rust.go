@@ -9,6 +9,7 @@ import (
 )
 
 type RustParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -35,13 +36,24 @@ func (p *RustParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 	}
 
 	root := tree.RootNode()
-	p.extractSymbols(root, content, analysis)
+	var impls []*sitter.Node
+	p.extractSymbols(root, content, analysis, &impls)
+	p.attachImpls(impls, content, analysis)
 	p.extractRelationships(root, content, analysis)
 
 	return analysis, nil
 }
 
-func (p *RustParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// extractSymbols collects the items of a module. Function bodies are not
+// descended into, so nested functions stay out; inline modules are, and
+// their items are listed with the file's. impl blocks are collected for
+// attachImpls, as the type they implement may come later in the file.
+func (p *RustParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis, impls *[]*sitter.Node) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -74,7 +86,7 @@ func (p *RustParser) extractSymbols(node *sitter.Node, content []byte, analysis
 			}
 
 		case "impl_item":
-			p.parseImplItem(child, content, analysis)
+			*impls = append(*impls, child)
 
 		case "const_item", "static_item":
 			sym := p.parseConstItem(child, content)
@@ -87,43 +99,36 @@ func (p *RustParser) extractSymbols(node *sitter.Node, content []byte, analysis
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
-		}
 
-		p.extractSymbols(child, content, analysis)
+		case "mod_item":
+			if body := child.ChildByFieldName("body"); body != nil {
+				p.extractSymbols(body, content, analysis, impls)
+			}
+		}
 	}
 }
 
+// parseFunctionItem parses a function or a method, with a signature keeping
+// its generic parameters and where clause, such as
+// "pub async fn run<T>(x: T) -> Result<(), Error> where T: Into<String>".
 func (p *RustParser) parseFunctionItem(node *sitter.Node, content []byte) *Symbol {
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
 		return nil
 	}
 
-	name := nameNode.Content(content)
-	exported := p.hasVisibility(node, content)
-
-	params := ""
-	paramsNode := node.ChildByFieldName("parameters")
-	if paramsNode != nil {
-		params = paramsNode.Content(content)
-	}
-
-	returnType := ""
-	returnNode := node.ChildByFieldName("return_type")
-	if returnNode != nil {
-		returnType = " -> " + returnNode.Content(content)
-	}
-
-	doc := p.extractDocComment(node, content)
-
+	// A where clause may end in a comma; a trait method without a body ends
+	// in a semicolon
+	sig := strings.TrimRight(declarationHeader(node, content, "attribute_item", "block"), ",;")
 	return &Symbol{
-		Name:       name,
-		Kind:       KindFunction,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		Signature:  "fn " + name + params + returnType,
-		DocComment: doc,
-		Exported:   exported,
+		Name:        nameNode.Content(content),
+		Kind:        KindFunction,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   sig,
+		DocComment:  p.extractDocComment(node, content),
+		Exported:    p.hasVisibility(node, content),
+		Annotations: p.annotations(node, content),
 	}
 }
 
@@ -133,21 +138,20 @@ func (p *RustParser) parseStructItem(node *sitter.Node, content []byte) *Symbol
 		return nil
 	}
 
-	name := nameNode.Content(content)
-	exported := p.hasVisibility(node, content)
-	doc := p.extractDocComment(node, content)
-
+	sig := strings.TrimRight(declarationHeader(node, content, "attribute_item", "field_declaration_list"), ",;")
 	sym := &Symbol{
-		Name:       name,
-		Kind:       KindClass,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
+		Name:        nameNode.Content(content),
+		Kind:        KindClass,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   sig,
+		DocComment:  p.extractDocComment(node, content),
+		Exported:    p.hasVisibility(node, content),
+		Annotations: p.annotations(node, content),
 	}
 
 	bodyNode := node.ChildByFieldName("body")
-	if bodyNode != nil {
+	if bodyNode != nil && bodyNode.Type() == "field_declaration_list" {
 		sym.Children = p.parseStructFields(bodyNode, content)
 	}
 
@@ -169,11 +173,13 @@ func (p *RustParser) parseStructFields(node *sitter.Node, content []byte) []Symb
 		}
 
 		fields = append(fields, Symbol{
-			Name:      nameNode.Content(content),
-			Kind:      KindProperty,
-			LineStart: int(child.StartPoint().Row) + 1,
-			LineEnd:   int(child.EndPoint().Row) + 1,
-			Exported:  p.hasVisibility(child, content),
+			Name:       nameNode.Content(content),
+			Kind:       KindProperty,
+			LineStart:  int(child.StartPoint().Row) + 1,
+			LineEnd:    int(child.EndPoint().Row) + 1,
+			Signature:  strings.Join(strings.Fields(child.Content(content)), " "),
+			DocComment: p.extractDocComment(child, content),
+			Exported:   p.hasVisibility(child, content),
 		})
 	}
 
@@ -187,49 +193,106 @@ func (p *RustParser) parseEnumItem(node *sitter.Node, content []byte) *Symbol {
 	}
 
 	return &Symbol{
-		Name:       nameNode.Content(content),
-		Kind:       KindEnum,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: p.extractDocComment(node, content),
-		Exported:   p.hasVisibility(node, content),
+		Name:        nameNode.Content(content),
+		Kind:        KindEnum,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   declarationHeader(node, content, "attribute_item", "enum_variant_list"),
+		DocComment:  p.extractDocComment(node, content),
+		Exported:    p.hasVisibility(node, content),
+		Annotations: p.annotations(node, content),
 	}
 }
 
+// parseTraitItem parses a trait with its methods, associated constants,
+// and associated types as children. They are as visible as the trait.
 func (p *RustParser) parseTraitItem(node *sitter.Node, content []byte) *Symbol {
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
 		return nil
 	}
 
-	return &Symbol{
-		Name:       nameNode.Content(content),
-		Kind:       KindInterface,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: p.extractDocComment(node, content),
-		Exported:   p.hasVisibility(node, content),
+	sym := &Symbol{
+		Name:        nameNode.Content(content),
+		Kind:        KindInterface,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   strings.TrimSuffix(declarationHeader(node, content, "attribute_item", "declaration_list"), ","),
+		DocComment:  p.extractDocComment(node, content),
+		Exported:    p.hasVisibility(node, content),
+		Annotations: p.annotations(node, content),
 	}
+	if body := node.ChildByFieldName("body"); body != nil {
+		sym.Children = p.parseDeclarationList(body, content, sym.Exported)
+	}
+	return sym
 }
 
-func (p *RustParser) parseImplItem(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+// parseDeclarationList returns the members of a trait or impl body. With
+// public set, as in traits and trait impls, every member is exported;
+// otherwise members need their own pub.
+func (p *RustParser) parseDeclarationList(node *sitter.Node, content []byte, public bool) []Symbol {
+	var members []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child == nil {
+		item := node.Child(i)
+		if item == nil {
 			continue
 		}
 
-		if child.Type() == "declaration_list" {
-			for j := 0; j < int(child.ChildCount()); j++ {
-				item := child.Child(j)
-				if item != nil && item.Type() == "function_item" {
-					sym := p.parseFunctionItem(item, content)
-					if sym != nil {
-						sym.Kind = KindMethod
-						analysis.Symbols = append(analysis.Symbols, *sym)
-					}
-				}
+		var sym *Symbol
+		switch item.Type() {
+		case "function_item", "function_signature_item":
+			sym = p.parseFunctionItem(item, content)
+			if sym != nil {
+				sym.Kind = KindMethod
 			}
+		case "const_item":
+			sym = p.parseConstItem(item, content)
+		case "associated_type", "type_item":
+			sym = p.parseTypeItem(item, content)
+		}
+		if sym != nil {
+			sym.Exported = sym.Exported || public
+			members = append(members, *sym)
+		}
+	}
+	return members
+}
+
+// attachImpls adds the members of impl blocks to the type they implement,
+// found by name among the file's symbols. A type defined in another file
+// gets a symbol spanning its first impl block here, so its methods still
+// have a parent.
+func (p *RustParser) attachImpls(impls []*sitter.Node, content []byte, analysis *FileAnalysis) {
+	for _, impl := range impls {
+		typeNode := impl.ChildByFieldName("type")
+		body := impl.ChildByFieldName("body")
+		if typeNode == nil || body == nil {
+			continue
+		}
+		name := rustTypeName(typeNode, content)
+		members := p.parseDeclarationList(body, content, impl.ChildByFieldName("trait") != nil)
+
+		var owner *Symbol
+		for i := range analysis.Symbols {
+			sym := &analysis.Symbols[i]
+			if sym.Name == name && (sym.Kind == KindClass || sym.Kind == KindEnum || sym.Kind == KindType) {
+				owner = sym
+				break
+			}
+		}
+		if owner == nil {
+			analysis.Symbols = append(analysis.Symbols, Symbol{
+				Name:      name,
+				Kind:      KindClass,
+				LineStart: int(impl.StartPoint().Row) + 1,
+				LineEnd:   int(impl.EndPoint().Row) + 1,
+			})
+			owner = &analysis.Symbols[len(analysis.Symbols)-1]
+		}
+		owner.Children = append(owner.Children, members...)
+		for _, m := range members {
+			owner.Exported = owner.Exported || m.Exported
 		}
 	}
 }
@@ -240,12 +303,22 @@ func (p *RustParser) parseConstItem(node *sitter.Node, content []byte) *Symbol {
 		return nil
 	}
 
+	var value string
+	if valueNode := node.ChildByFieldName("value"); valueNode != nil {
+		value = literalValue(valueNode.Content(content))
+	}
+
+	sig := declarationHeader(node, content, "attribute_item", "=")
 	return &Symbol{
-		Name:      nameNode.Content(content),
-		Kind:      KindConstant,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  p.hasVisibility(node, content),
+		Name:        nameNode.Content(content),
+		Kind:        KindConstant,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   strings.TrimSuffix(sig, ";"),
+		DocComment:  p.extractDocComment(node, content),
+		Exported:    p.hasVisibility(node, content),
+		Annotations: p.annotations(node, content),
+		Value:       value,
 	}
 }
 
@@ -256,12 +329,27 @@ func (p *RustParser) parseTypeItem(node *sitter.Node, content []byte) *Symbol {
 	}
 
 	return &Symbol{
-		Name:      nameNode.Content(content),
-		Kind:      KindType,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  p.hasVisibility(node, content),
+		Name:        nameNode.Content(content),
+		Kind:        KindType,
+		LineStart:   int(node.StartPoint().Row) + 1,
+		LineEnd:     int(node.EndPoint().Row) + 1,
+		Signature:   strings.TrimSuffix(strings.Join(strings.Fields(node.Content(content)), " "), ";"),
+		DocComment:  p.extractDocComment(node, content),
+		Exported:    p.hasVisibility(node, content),
+		Annotations: p.annotations(node, content),
+	}
+}
+
+// rustTypeName returns the name of a type without its type arguments or
+// reference: "Cache" for "Cache<K, V>" and "&mut Cache<K, V>".
+func rustTypeName(node *sitter.Node, content []byte) string {
+	switch node.Type() {
+	case "generic_type", "reference_type", "pointer_type":
+		if inner := node.ChildByFieldName("type"); inner != nil {
+			return rustTypeName(inner, content)
+		}
 	}
+	return node.Content(content)
 }
 
 func (p *RustParser) hasVisibility(node *sitter.Node, _ []byte) bool {
@@ -274,23 +362,50 @@ func (p *RustParser) hasVisibility(node *sitter.Node, _ []byte) bool {
 	return false
 }
 
+// extractDocComment returns the /// or /** */ comment before an item,
+// across any attributes between them, up to its first heading such as
+// "# Examples".
 func (p *RustParser) extractDocComment(node *sitter.Node, content []byte) string {
 	prev := node.PrevSibling()
-	if prev == nil {
-		return ""
+	for prev != nil && prev.Type() == "attribute_item" {
+		prev = prev.PrevSibling()
 	}
 
-	if prev.Type() == "line_comment" {
+	var lines []string
+	for ; prev != nil; prev = prev.PrevSibling() {
 		text := prev.Content(content)
-		if strings.HasPrefix(text, "///") {
-			return strings.TrimPrefix(text, "/// ")
+		if prev.Type() == "line_comment" && strings.HasPrefix(text, "///") {
+			lines = append([]string{strings.TrimPrefix(text, "///")}, lines...)
+			continue
+		}
+		if prev.Type() == "block_comment" && strings.HasPrefix(text, "/**") && len(lines) == 0 {
+			text = strings.TrimSuffix(strings.TrimPrefix(text, "/**"), "*/")
+			lines = strings.Split(text, "\n")
 		}
+		break
 	}
 
-	return ""
+	var cleaned []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			break
+		}
+		if line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, " ")
 }
 
 func (p *RustParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -301,6 +416,12 @@ func (p *RustParser) extractRelationships(node *sitter.Node, content []byte, ana
 		case "use_declaration":
 			p.parseUseDecl(child, content, analysis)
 
+		case "impl_item":
+			p.parseImplTrait(child, content, analysis)
+
+		case "trait_item":
+			p.parseSupertraits(child, content, analysis)
+
 		case "call_expression":
 			p.parseCallExpression(child, content, analysis)
 		}
@@ -353,21 +474,126 @@ func (p *RustParser) parseCallExpression(node *sitter.Node, content []byte, anal
 	}
 }
 
+// parseUseDecl records an import for each path a use declaration brings
+// in: "use std::{io, fmt::Write as W};" imports std::io and
+// std::fmt::Write. "self" in a list imports the module the list is in, and
+// a glob such as "use std::io::*;" imports the module it expands, as Java
+// wildcard imports do.
 func (p *RustParser) parseUseDecl(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child == nil {
+	arg := node.ChildByFieldName("argument")
+	if arg == nil {
+		return
+	}
+	for _, path := range rustUsePaths(arg, content, "") {
+		analysis.Relationships = append(analysis.Relationships, Relationship{
+			TargetFile: path,
+			Kind:       RelImport,
+			Line:       int(node.StartPoint().Row) + 1,
+		})
+	}
+}
+
+// rustUsePaths expands the argument of a use declaration into full paths.
+func rustUsePaths(node *sitter.Node, content []byte, prefix string) []string {
+	join := func(path string) string {
+		if prefix == "" {
+			return path
+		}
+		return prefix + "::" + path
+	}
+	switch node.Type() {
+	case "use_as_clause":
+		if path := node.ChildByFieldName("path"); path != nil {
+			return []string{join(path.Content(content))}
+		}
+	case "scoped_use_list":
+		list := node.ChildByFieldName("list")
+		if list == nil {
+			return nil
+		}
+		if path := node.ChildByFieldName("path"); path != nil {
+			prefix = join(path.Content(content))
+		}
+		return rustUsePaths(list, content, prefix)
+	case "use_list":
+		var paths []string
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			paths = append(paths, rustUsePaths(node.NamedChild(i), content, prefix)...)
+		}
+		return paths
+	case "self":
+		if prefix != "" {
+			return []string{prefix}
+		}
+	case "use_wildcard":
+		if path := strings.TrimSuffix(node.Content(content), "*"); path != "" {
+			return []string{join(strings.TrimSuffix(path, "::"))}
+		}
+		if prefix != "" {
+			return []string{prefix}
+		}
+		return nil
+	case "line_comment", "block_comment":
+		return nil
+	}
+	return []string{join(node.Content(content))}
+}
+
+// parseImplTrait records "impl Display for User" as User implementing
+// Display.
+func (p *RustParser) parseImplTrait(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	traitNode := node.ChildByFieldName("trait")
+	typeNode := node.ChildByFieldName("type")
+	if traitNode == nil || typeNode == nil {
+		return
+	}
+	analysis.Relationships = append(analysis.Relationships, Relationship{
+		SourceSymbol: rustTypeName(typeNode, content),
+		TargetSymbol: rustTypeName(traitNode, content),
+		Kind:         RelImplements,
+		Line:         int(traitNode.StartPoint().Row) + 1,
+	})
+}
+
+// parseSupertraits records the supertraits of a trait, as in
+// "trait Store: Send + Sync", as extends relationships.
+func (p *RustParser) parseSupertraits(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	nameNode := node.ChildByFieldName("name")
+	bounds := node.ChildByFieldName("bounds")
+	if nameNode == nil || bounds == nil {
+		return
+	}
+	for i := 0; i < int(bounds.NamedChildCount()); i++ {
+		bound := bounds.NamedChild(i)
+		if bound.Type() == "lifetime" {
 			continue
 		}
+		analysis.Relationships = append(analysis.Relationships, Relationship{
+			SourceSymbol: nameNode.Content(content),
+			TargetSymbol: rustTypeName(bound, content),
+			Kind:         RelExtends,
+			Line:         int(bound.StartPoint().Row) + 1,
+		})
+	}
+}
 
-		switch child.Type() {
-		case "scoped_identifier", "identifier", "use_wildcard", "use_list", "scoped_use_list":
-			path := child.Content(content)
-			analysis.Relationships = append(analysis.Relationships, Relationship{
-				TargetFile: path,
-				Kind:       RelImport,
-				Line:       int(node.StartPoint().Row) + 1,
-			})
+// annotations returns the outer attributes preceding a Rust item (e.g. "#[derive(Debug)]").
+func (p *RustParser) annotations(node *sitter.Node, content []byte) []string {
+	switch node.Type() {
+	case "function_item", "struct_item", "enum_item", "trait_item", "impl_item", "mod_item",
+		"type_item", "const_item", "static_item", "union_item", "macro_definition":
+	default:
+		return nil
+	}
+	var result []string
+	for prev := node.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if prev.Type() == "line_comment" || prev.Type() == "block_comment" {
+			continue
+		}
+		if prev.Type() != "attribute_item" {
+			break
 		}
+		result = append([]string{NormalizeAnnotation(prev.Content(content))}, result...)
 	}
+	return result
 }
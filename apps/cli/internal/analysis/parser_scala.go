@@ -9,6 +9,7 @@ import (
 )
 
 type ScalaParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *ScalaParser) Parse(content []byte, filePath string) (*FileAnalysis, err
 }
 
 func (p *ScalaParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -322,6 +328,11 @@ func (p *ScalaParser) extractClassMembers(node *sitter.Node, content []byte) []S
 }
 
 func (p *ScalaParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -9,6 +9,7 @@ import (
 )
 
 type SQLParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *SQLParser) Parse(content []byte, filePath string) (*FileAnalysis, error
 }
 
 func (p *SQLParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -280,6 +286,11 @@ func (p *SQLParser) extractColumns(node *sitter.Node, content []byte) []Symbol {
 }
 
 func (p *SQLParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -9,6 +9,7 @@ import (
 )
 
 type SvelteParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *SvelteParser) Parse(content []byte, filePath string) (*FileAnalysis, er
 }
 
 func (p *SvelteParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -242,6 +248,11 @@ func (p *SvelteParser) parseIfStatement(node *sitter.Node, _ []byte, analysis *F
 }
 
 func (p *SvelteParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
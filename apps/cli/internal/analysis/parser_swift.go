@@ -9,6 +9,7 @@ import (
 )
 
 type SwiftParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -48,6 +49,8 @@ func (p *SwiftParser) extractSymbols(node *sitter.Node, content []byte, analysis
 			continue
 		}
 
+		// Declarations hold their members as children, so only the top
+		// level is walked; locals inside function bodies are not symbols.
 		switch child.Type() {
 		case "class_declaration":
 			sym := p.parseClass(child, content)
@@ -55,24 +58,12 @@ func (p *SwiftParser) extractSymbols(node *sitter.Node, content []byte, analysis
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
 
-		case "struct_declaration":
-			sym := p.parseStruct(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
-
 		case "protocol_declaration":
 			sym := p.parseProtocol(child, content)
 			if sym != nil {
 				analysis.Symbols = append(analysis.Symbols, *sym)
 			}
 
-		case "enum_declaration":
-			sym := p.parseEnum(child, content)
-			if sym != nil {
-				analysis.Symbols = append(analysis.Symbols, *sym)
-			}
-
 		case "function_declaration":
 			sym := p.parseFunction(child, content)
 			if sym != nil {
@@ -85,23 +76,15 @@ func (p *SwiftParser) extractSymbols(node *sitter.Node, content []byte, analysis
 		case "typealias_declaration":
 			p.parseTypealias(child, content, analysis)
 		}
-
-		p.extractSymbols(child, content, analysis)
 	}
 }
 
+// parseClass parses a class, struct, enum, or extension, which the grammar
+// all parses as class declarations told apart by their keyword. Structs and
+// extensions are classes, as in the other parsers; an extension is named
+// after the type it extends and its signature starts with "extension".
 func (p *SwiftParser) parseClass(node *sitter.Node, content []byte) *Symbol {
 	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		for i := 0; i < int(node.ChildCount()); i++ {
-			child := node.Child(i)
-			if child != nil && child.Type() == "type_identifier" {
-				nameNode = child
-				break
-			}
-		}
-	}
-
 	if nameNode == nil {
 		return nil
 	}
@@ -109,46 +92,32 @@ func (p *SwiftParser) parseClass(node *sitter.Node, content []byte) *Symbol {
 	name := nameNode.Content(content)
 	doc := p.extractDocComment(node, content)
 	exported := p.isPublic(node, content)
-	var children []Symbol
 
-	body := node.ChildByFieldName("body")
-	if body == nil {
-		for i := 0; i < int(node.ChildCount()); i++ {
-			child := node.Child(i)
-			if child != nil && child.Type() == "class_body" {
-				body = child
-				break
-			}
-		}
+	kind := KindClass
+	if declKind := node.ChildByFieldName("declaration_kind"); declKind != nil && declKind.Type() == "enum" {
+		kind = KindEnum
 	}
 
-	if body != nil {
+	var children []Symbol
+	if body := node.ChildByFieldName("body"); body != nil {
 		children = p.extractClassMembers(body, content)
 	}
 
 	return &Symbol{
 		Name:       name,
-		Kind:       KindClass,
+		Kind:       kind,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "attribute", "class_body", "enum_class_body"),
 		DocComment: doc,
 		Exported:   exported,
 		Children:   children,
+		TypeParams: extractTypeParams(node, content),
 	}
 }
 
-func (p *SwiftParser) parseStruct(node *sitter.Node, content []byte) *Symbol {
+func (p *SwiftParser) parseProtocol(node *sitter.Node, content []byte) *Symbol {
 	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		for i := 0; i < int(node.ChildCount()); i++ {
-			child := node.Child(i)
-			if child != nil && child.Type() == "type_identifier" {
-				nameNode = child
-				break
-			}
-		}
-	}
-
 	if nameNode == nil {
 		return nil
 	}
@@ -156,167 +125,104 @@ func (p *SwiftParser) parseStruct(node *sitter.Node, content []byte) *Symbol {
 	name := nameNode.Content(content)
 	doc := p.extractDocComment(node, content)
 	exported := p.isPublic(node, content)
-	var children []Symbol
 
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && (child.Type() == "class_body" || child.Type() == "struct_body") {
-			children = p.extractClassMembers(child, content)
-			break
-		}
+	var children []Symbol
+	if body := node.ChildByFieldName("body"); body != nil {
+		children = p.extractClassMembers(body, content)
 	}
 
 	return &Symbol{
 		Name:       name,
-		Kind:       KindClass,
+		Kind:       KindInterface,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  declarationHeader(node, content, "attribute", "protocol_body"),
 		DocComment: doc,
 		Exported:   exported,
 		Children:   children,
 	}
 }
 
-func (p *SwiftParser) parseProtocol(node *sitter.Node, content []byte) *Symbol {
+// parseFunction parses a function or a protocol's function requirement.
+// The signature is its header without attributes, such as
+// "func load(id: Int) async throws -> User".
+func (p *SwiftParser) parseFunction(node *sitter.Node, content []byte) *Symbol {
 	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		for i := 0; i < int(node.ChildCount()); i++ {
-			child := node.Child(i)
-			if child != nil && child.Type() == "type_identifier" {
-				nameNode = child
-				break
-			}
-		}
-	}
-
 	if nameNode == nil {
 		return nil
 	}
 
 	name := nameNode.Content(content)
 	doc := p.extractDocComment(node, content)
+	sig := declarationHeader(node, content, "attribute", "function_body")
 	exported := p.isPublic(node, content)
 
 	return &Symbol{
 		Name:       name,
-		Kind:       KindInterface,
+		Kind:       KindFunction,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  sig,
 		DocComment: doc,
 		Exported:   exported,
+		TypeParams: extractTypeParams(node, content),
 	}
 }
 
-func (p *SwiftParser) parseEnum(node *sitter.Node, content []byte) *Symbol {
-	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		for i := 0; i < int(node.ChildCount()); i++ {
-			child := node.Child(i)
-			if child != nil && child.Type() == "type_identifier" {
-				nameNode = child
-				break
-			}
-		}
+// parseProperty parses a top-level property. Stored "let" properties are
+// constants; "var" and computed properties are variables.
+func (p *SwiftParser) parseProperty(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	sym := p.propertySymbol(node, content)
+	if sym == nil {
+		return
+	}
+	sym.Kind = KindVariable
+	if node.ChildByFieldName("computed_value") == nil && strings.HasPrefix(sym.Signature, "let ") {
+		sym.Kind = KindConstant
 	}
+	analysis.Symbols = append(analysis.Symbols, *sym)
+}
 
-	if nameNode == nil {
+// propertySymbol returns a stored or computed property, or a protocol's
+// property requirement, as a property. The signature is its declaration
+// without the initial value or accessor bodies; computed properties end
+// in "{ get }" or "{ get set }", as requirements do.
+func (p *SwiftParser) propertySymbol(node *sitter.Node, content []byte) *Symbol {
+	pattern := node.ChildByFieldName("name")
+	if pattern == nil {
 		return nil
 	}
-
-	name := nameNode.Content(content)
-	doc := p.extractDocComment(node, content)
-	exported := p.isPublic(node, content)
-	var children []Symbol
-
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && child.Type() == "enum_class_body" {
-			children = p.extractEnumCases(child, content)
+	var name string
+	for i := 0; i < int(pattern.ChildCount()); i++ {
+		if id := pattern.Child(i); id != nil && id.Type() == "simple_identifier" {
+			name = id.Content(content)
 			break
 		}
 	}
-
-	return &Symbol{
-		Name:       name,
-		Kind:       KindEnum,
-		LineStart:  int(node.StartPoint().Row) + 1,
-		LineEnd:    int(node.EndPoint().Row) + 1,
-		DocComment: doc,
-		Exported:   exported,
-		Children:   children,
+	if name == "" {
+		return nil
 	}
-}
 
-func (p *SwiftParser) parseFunction(node *sitter.Node, content []byte) *Symbol {
-	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
-		for i := 0; i < int(node.ChildCount()); i++ {
-			child := node.Child(i)
-			if child != nil && child.Type() == "simple_identifier" {
-				nameNode = child
-				break
+	sig := declarationHeader(node, content, "attribute", "=", "computed_property")
+	if computed := node.ChildByFieldName("computed_value"); computed != nil {
+		accessors := " { get }"
+		for i := 0; i < int(computed.ChildCount()); i++ {
+			if c := computed.Child(i); c != nil && c.Type() == "computed_setter" {
+				accessors = " { get set }"
 			}
 		}
+		sig += accessors
 	}
 
-	if nameNode == nil {
-		return nil
-	}
-
-	name := nameNode.Content(content)
-	doc := p.extractDocComment(node, content)
-	sig := p.extractFunctionSignature(node, content)
-	exported := p.isPublic(node, content)
-
 	return &Symbol{
 		Name:       name,
-		Kind:       KindFunction,
+		Kind:       KindProperty,
 		LineStart:  int(node.StartPoint().Row) + 1,
 		LineEnd:    int(node.EndPoint().Row) + 1,
 		Signature:  sig,
-		DocComment: doc,
-		Exported:   exported,
-	}
-}
-
-func (p *SwiftParser) parseProperty(node *sitter.Node, content []byte, analysis *FileAnalysis) {
-	var name string
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && child.Type() == "pattern" {
-			for j := 0; j < int(child.ChildCount()); j++ {
-				patternChild := child.Child(j)
-				if patternChild != nil && patternChild.Type() == "simple_identifier" {
-					name = patternChild.Content(content)
-					break
-				}
-			}
-			if name != "" {
-				break
-			}
-		}
-	}
-
-	if name == "" {
-		return
-	}
-
-	kind := KindVariable
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && child.Content(content) == "let" {
-			kind = KindConstant
-			break
-		}
+		DocComment: p.extractDocComment(node, content),
+		Exported:   p.isPublic(node, content),
 	}
-
-	analysis.Symbols = append(analysis.Symbols, Symbol{
-		Name:      name,
-		Kind:      kind,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  p.isPublic(node, content),
-	})
 }
 
 func (p *SwiftParser) parseTypealias(node *sitter.Node, content []byte, analysis *FileAnalysis) {
@@ -336,15 +242,23 @@ func (p *SwiftParser) parseTypealias(node *sitter.Node, content []byte, analysis
 	}
 
 	analysis.Symbols = append(analysis.Symbols, Symbol{
-		Name:      nameNode.Content(content),
-		Kind:      KindType,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Exported:  p.isPublic(node, content),
+		Name:       nameNode.Content(content),
+		Kind:       KindType,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		DocComment: p.extractDocComment(node, content),
+		Exported:   p.isPublic(node, content),
 	})
 }
 
+// extractClassMembers returns the members of a type, enum, or protocol
+// body, nested types included.
 func (p *SwiftParser) extractClassMembers(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var members []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -353,76 +267,73 @@ func (p *SwiftParser) extractClassMembers(node *sitter.Node, content []byte) []S
 		}
 
 		switch child.Type() {
-		case "function_declaration":
+		case "function_declaration", "protocol_function_declaration":
 			sym := p.parseFunction(child, content)
 			if sym != nil {
 				sym.Kind = KindMethod
 				members = append(members, *sym)
 			}
 
-		case "property_declaration":
-			var name string
-			for j := 0; j < int(child.ChildCount()); j++ {
-				pattern := child.Child(j)
-				if pattern != nil && pattern.Type() == "pattern" {
-					for k := 0; k < int(pattern.ChildCount()); k++ {
-						id := pattern.Child(k)
-						if id != nil && id.Type() == "simple_identifier" {
-							name = id.Content(content)
-							break
-						}
-					}
-					break
-				}
-			}
-
-			if name != "" {
-				members = append(members, Symbol{
-					Name:      name,
-					Kind:      KindProperty,
-					LineStart: int(child.StartPoint().Row) + 1,
-					LineEnd:   int(child.EndPoint().Row) + 1,
-					Exported:  p.isPublic(child, content),
-				})
+		case "property_declaration", "protocol_property_declaration":
+			if sym := p.propertySymbol(child, content); sym != nil {
+				members = append(members, *sym)
 			}
 
 		case "init_declaration":
 			members = append(members, Symbol{
-				Name:      "init",
-				Kind:      KindConstructor,
-				LineStart: int(child.StartPoint().Row) + 1,
-				LineEnd:   int(child.EndPoint().Row) + 1,
-				Exported:  p.isPublic(child, content),
+				Name:       "init",
+				Kind:       KindConstructor,
+				LineStart:  int(child.StartPoint().Row) + 1,
+				LineEnd:    int(child.EndPoint().Row) + 1,
+				Signature:  declarationHeader(child, content, "attribute", "function_body"),
+				DocComment: p.extractDocComment(child, content),
+				Exported:   p.isPublic(child, content),
 			})
+
+		case "class_declaration":
+			if sym := p.parseClass(child, content); sym != nil {
+				members = append(members, *sym)
+			}
+
+		case "protocol_declaration":
+			if sym := p.parseProtocol(child, content); sym != nil {
+				members = append(members, *sym)
+			}
+
+		case "enum_entry":
+			members = append(members, p.extractEnumCases(child, content)...)
 		}
 	}
 	return members
 }
 
+// extractEnumCases returns the cases an enum entry declares; "case north,
+// south" declares two.
 func (p *SwiftParser) extractEnumCases(node *sitter.Node, content []byte) []Symbol {
 	var cases []Symbol
+	doc := p.extractDocComment(node, content)
 	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child != nil && child.Type() == "enum_entry" {
-			for j := 0; j < int(child.ChildCount()); j++ {
-				nameNode := child.Child(j)
-				if nameNode != nil && nameNode.Type() == "simple_identifier" {
-					cases = append(cases, Symbol{
-						Name:      nameNode.Content(content),
-						Kind:      KindConstant,
-						LineStart: int(child.StartPoint().Row) + 1,
-						LineEnd:   int(child.EndPoint().Row) + 1,
-						Exported:  true,
-					})
-					break
-				}
-			}
+		nameNode := node.Child(i)
+		if nameNode != nil && nameNode.Type() == "simple_identifier" {
+			cases = append(cases, Symbol{
+				Name:       nameNode.Content(content),
+				Kind:       KindConstant,
+				LineStart:  int(node.StartPoint().Row) + 1,
+				LineEnd:    int(node.EndPoint().Row) + 1,
+				DocComment: doc,
+				Exported:   true,
+			})
 		}
 	}
 	return cases
 }
 
 func (p *SwiftParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -433,7 +344,7 @@ func (p *SwiftParser) extractRelationships(node *sitter.Node, content []byte, an
 		case "import_declaration":
 			p.parseImport(child, content, analysis)
 
-		case "class_declaration", "struct_declaration":
+		case "class_declaration", "protocol_declaration":
 			p.parseInheritance(child, content, analysis)
 		}
 
@@ -455,25 +366,40 @@ func (p *SwiftParser) parseImport(node *sitter.Node, content []byte, analysis *F
 	}
 }
 
+// parseInheritance records the types after a declaration's colon. Swift
+// writes a superclass and protocol conformances alike, so a class's list
+// and a protocol's inherited protocols are recorded as extends, while
+// structs, enums, and extensions, which can only conform, implement theirs.
 func (p *SwiftParser) parseInheritance(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+	kind := RelExtends
+	if declKind := node.ChildByFieldName("declaration_kind"); declKind != nil {
+		switch declKind.Type() {
+		case "struct", "enum", "extension":
+			kind = RelImplements
+		}
+	}
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		if child == nil {
+		if child == nil || child.Type() != "inheritance_specifier" {
 			continue
 		}
-
-		if child.Type() == "inheritance_specifier" || child.Type() == "type_inheritance_clause" {
-			for j := 0; j < int(child.ChildCount()); j++ {
-				typeNode := child.Child(j)
-				if typeNode != nil && typeNode.Type() == "type_identifier" {
-					analysis.Relationships = append(analysis.Relationships, Relationship{
-						TargetSymbol: typeNode.Content(content),
-						Kind:         RelExtends,
-						Line:         int(typeNode.StartPoint().Row) + 1,
-					})
-				}
-			}
+		typeNode := child.ChildByFieldName("inherits_from")
+		if typeNode == nil {
+			continue
 		}
+		// Only the type itself, not its generic arguments
+		typeName, _, _ := strings.Cut(typeNode.Content(content), "<")
+		analysis.Relationships = append(analysis.Relationships, Relationship{
+			SourceSymbol: nameNode.Content(content),
+			TargetSymbol: strings.TrimSpace(typeName),
+			Kind:         kind,
+			Line:         int(typeNode.StartPoint().Row) + 1,
+		})
 	}
 }
 
@@ -497,54 +423,47 @@ func (p *SwiftParser) isPublic(node *sitter.Node, content []byte) bool {
 	return true
 }
 
-func (p *SwiftParser) extractFunctionSignature(node *sitter.Node, content []byte) string {
-	var sig strings.Builder
-	sig.WriteString("func ")
-
-	for i := 0; i < int(node.ChildCount()); i++ {
-		child := node.Child(i)
-		if child == nil {
-			continue
-		}
-
-		switch child.Type() {
-		case "simple_identifier":
-			sig.WriteString(child.Content(content))
-		case "function_value_parameters":
-			sig.WriteString(child.Content(content))
-		case "type_annotation":
-			sig.WriteString(" -> ")
-			sig.WriteString(child.Content(content))
-		}
-	}
-
-	return sig.String()
-}
-
+// extractDocComment returns the doc comment before a declaration: a run of
+// "///" lines or a "/** */" block, up to the first callout such as
+// "- Parameter id:". Attributes such as @objc belong to the declaration, so
+// they do not separate it from its comment.
 func (p *SwiftParser) extractDocComment(node *sitter.Node, content []byte) string {
 	prev := node.PrevSibling()
 	if prev == nil {
 		return ""
 	}
 
-	if prev.Type() == "comment" || prev.Type() == "multiline_comment" {
-		comment := prev.Content(content)
-		if strings.HasPrefix(comment, "///") {
-			return strings.TrimPrefix(comment, "/// ")
-		}
-		if strings.HasPrefix(comment, "/**") {
-			comment = strings.TrimPrefix(comment, "/**")
-			comment = strings.TrimSuffix(comment, "*/")
-			lines := strings.Split(comment, "\n")
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				line = strings.TrimPrefix(line, "* ")
-				if line != "" && !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "@") {
-					return line
-				}
+	var lines []string
+	switch {
+	case prev.Type() == "multiline_comment" && strings.HasPrefix(prev.Content(content), "/**"):
+		comment := strings.TrimSuffix(strings.TrimPrefix(prev.Content(content), "/**"), "*/")
+		lines = strings.Split(comment, "\n")
+	case prev.Type() == "comment" && strings.HasPrefix(prev.Content(content), "///"):
+		// Collect the run of /// lines ending right above the declaration
+		line := int(prev.StartPoint().Row)
+		for c := prev; c != nil && c.Type() == "comment" && int(c.StartPoint().Row) == line; c = c.PrevSibling() {
+			text := c.Content(content)
+			if !strings.HasPrefix(text, "///") {
+				break
 			}
+			lines = append([]string{strings.TrimPrefix(text, "///")}, lines...)
+			line--
 		}
+	default:
+		return ""
 	}
 
-	return ""
+	var cleaned []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "@") {
+			break
+		}
+		if line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, " ")
 }
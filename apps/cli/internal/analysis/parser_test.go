@@ -1,8 +1,12 @@
 package analysis
 
 import (
+	"fmt"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestDetectLanguage(t *testing.T) {
@@ -54,6 +58,10 @@ func TestDetectLanguage(t *testing.T) {
 		{"hxx header", "header.hxx", LangCPP},
 		{"hh header", "header.hh", LangCPP},
 
+		// Objective-C
+		{"objc file", "main.m", LangObjectiveC},
+		{"objc++ file", "main.mm", LangObjectiveC},
+
 		// C#
 		{"csharp file", "Program.cs", LangCSharp},
 
@@ -140,6 +148,9 @@ func TestDetectLanguage(t *testing.T) {
 		// CUE
 		{"cue file", "schema.cue", LangCUE},
 
+		// Nim
+		{"nim file", "main.nim", LangNim},
+
 		// Special filenames
 		{"Dockerfile", "Dockerfile", LangDockerfile},
 		{"dockerfile lowercase", "dockerfile", LangDockerfile},
@@ -333,7 +344,7 @@ func TestParserRegistry(t *testing.T) {
 			LangDockerfile, LangHCL, LangHTML, LangCSS, LangYAML,
 			LangTOML, LangJSON, LangMarkdown, LangElixir, LangLua,
 			LangGroovy, LangSvelte, LangOCaml, LangElm, LangProtobuf,
-			LangDart, LangCUE,
+			LangDart, LangCUE, LangNim,
 		}
 
 		for _, lang := range expectedLanguages {
@@ -601,6 +612,87 @@ func main() {
 	}
 }
 
+func TestAnalyzePythonBaseClasses(t *testing.T) {
+	code := `class A: pass
+class B: pass
+
+class D(A, B):
+    pass
+
+class Model(
+    base.Entity,
+    Generic[T],
+    metaclass=ABCMeta,
+):
+    pass
+`
+	result, err := Analyze([]byte(code), "models.py")
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+
+	var bases []string
+	for _, rel := range result.Relationships {
+		if rel.Kind == RelExtends {
+			bases = append(bases, fmt.Sprintf("%s@%d", rel.TargetSymbol, rel.Line))
+		}
+	}
+
+	want := []string{"A@4", "B@4", "base.Entity@8", "Generic[T]@9"}
+	if strings.Join(bases, ",") != strings.Join(want, ",") {
+		t.Errorf("extends relationships = %v, want %v", bases, want)
+	}
+}
+
+func TestAnalyzeTypeAliases(t *testing.T) {
+	tests := []struct {
+		name, path, code string
+		alias            string
+		refs             []string
+	}{
+		{"go alias", "ids.go", "package ids\n\ntype UserID = int64\n\ntype Count int\n", "UserID", []string{"int64"}},
+		{"typescript", "ids.ts", "type ID = string;\ntype Result = Ok<Value> | Err;\n", "Result", []string{"Ok", "Value", "Err"}},
+		{"c typedef", "node.h", "typedef struct node Node;\n", "Node", []string{"node"}},
+		{"python TypeAlias", "ids.py", "from typing import TypeAlias\n\nUserID: TypeAlias = int\n", "UserID", []string{"int"}},
+		{"python type statement", "pt.py", "type Point = tuple[float, float]\n", "Point", []string{"tuple", "float"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze([]byte(tt.code), tt.path)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+
+			found := false
+			for _, sym := range result.Symbols {
+				if sym.Name == tt.alias {
+					found = true
+					if sym.Kind != KindTypeAlias {
+						t.Errorf("%s kind = %s, want %s", tt.alias, sym.Kind, KindTypeAlias)
+					}
+				}
+				if sym.Name == "Count" && sym.Kind != KindType {
+					t.Errorf("defined type Count should stay %s, got %s", KindType, sym.Kind)
+				}
+			}
+			if !found {
+				t.Fatalf("expected type alias %s in %+v", tt.alias, result.Symbols)
+			}
+
+			var refs []string
+			for _, rel := range result.Relationships {
+				if rel.Kind == RelReference && rel.SourceSymbol == tt.alias {
+					refs = append(refs, rel.TargetSymbol)
+				}
+			}
+			if strings.Join(refs, ",") != strings.Join(tt.refs, ",") {
+				t.Errorf("references from %s = %v, want %v", tt.alias, refs, tt.refs)
+			}
+		})
+	}
+}
+
 func TestAnalyzeExportedSymbols(t *testing.T) {
 	code := `package example
 
@@ -667,3 +759,217 @@ func (m *mockParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 func (m *mockParser) Language() Language {
 	return m.lang
 }
+
+func TestAnalyzeInlineTypes(t *testing.T) {
+	tests := []struct {
+		name, path, code string
+		owner            string   // Top-level symbol holding the inline type
+		inline           string   // Nested symbol for the inline type
+		fields           []string // Its members
+	}{
+		{
+			"go result struct", "load.go",
+			"package load\n\nfunc Load(done chan struct{}) (struct {\n\tID   int\n\tName string\n}, error) {\n\treturn struct {\n\t\tID   int\n\t\tName string\n\t}{}, nil\n}\n",
+			"Load", "result1", []string{"ID", "Name"},
+		},
+		{
+			"go parameter struct", "save.go",
+			"package save\n\nfunc Save(opts *struct{ Dir, Mode string }) {}\n",
+			"Save", "opts", []string{"Dir", "Mode"},
+		},
+		{
+			"go struct field", "config.go",
+			"package config\n\ntype Config struct {\n\tDB struct {\n\t\tHost string\n\t\tPort int\n\t}\n}\n",
+			"Config", "DB", []string{"Host", "Port"},
+		},
+		{
+			"typescript return type", "load.ts",
+			"function load(): Promise<{ id: number; name: string }> { return fetch(); }\n",
+			"load", "result", []string{"id", "name"},
+		},
+		{
+			"typescript property", "user.ts",
+			"interface User {\n  address: { street: string; city: string };\n}\n",
+			"User", "address", []string{"street", "city"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze([]byte(tt.code), tt.path)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+
+			var inline *Symbol
+			for i := range result.Symbols {
+				if result.Symbols[i].Name != tt.owner {
+					continue
+				}
+				for j := range result.Symbols[i].Children {
+					if result.Symbols[i].Children[j].Name == tt.inline {
+						inline = &result.Symbols[i].Children[j]
+					}
+				}
+				// The empty struct{} of "chan struct{}" is not a data shape.
+				for _, c := range result.Symbols[i].Children {
+					if len(c.Children) == 0 && c.Kind != KindProperty {
+						t.Errorf("%s should not hold the empty inline type %s", tt.owner, c.Name)
+					}
+				}
+			}
+			if inline == nil {
+				t.Fatalf("expected inline type %s under %s in %+v", tt.inline, tt.owner, result.Symbols)
+			}
+
+			var fields []string
+			for _, c := range inline.Children {
+				fields = append(fields, c.Name)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("members of %s = %v, want %v", tt.inline, fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTypeParams(t *testing.T) {
+	tests := []struct {
+		name, path, code string
+		symbol           string   // Symbol, or Parent.Child, holding the type parameters
+		want             []string // "Name Constraint"
+	}{
+		{
+			"go generic function", "maps.go",
+			"package maps\n\nfunc Map[T any, U comparable](in []T, f func(T) U) []U { return nil }\n",
+			"Map", []string{"T any", "U comparable"},
+		},
+		{
+			"go shared constraint and union", "set.go",
+			"package set\n\ntype Pair[K, V comparable] struct{ Key K }\n\nfunc Sum[N ~int | ~float64](ns ...N) N { return 0 }\n",
+			"Pair", []string{"K comparable", "V comparable"},
+		},
+		{
+			"typescript function", "pick.ts",
+			"function pick<T extends object, K extends keyof T>(o: T, k: K): T[K] { return o[k]; }\n",
+			"pick", []string{"T object", "K keyof T"},
+		},
+		{
+			"typescript class method", "box.ts",
+			"class Box<T> {\n  map<U>(f: (v: T) => U): Box<U> { return new Box(); }\n}\n",
+			"Box.map", []string{"U"},
+		},
+		{
+			"java class", "Repo.java",
+			"public class Repo<T extends Comparable<T>> {}\n",
+			"Repo", []string{"T Comparable<T>"},
+		},
+		{
+			"kotlin function", "util.kt",
+			"fun <T : Any, R> convert(x: T): R { TODO() }\n",
+			"convert", []string{"T Any", "R"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze([]byte(tt.code), tt.path)
+			if err != nil {
+				t.Fatalf("Analyze returned error: %v", err)
+			}
+			var sym *Symbol
+			parent, child, nested := strings.Cut(tt.symbol, ".")
+			for i := range result.Symbols {
+				if result.Symbols[i].Name != parent {
+					continue
+				}
+				sym = &result.Symbols[i]
+				if nested {
+					sym = nil
+					for j := range result.Symbols[i].Children {
+						if result.Symbols[i].Children[j].Name == child {
+							sym = &result.Symbols[i].Children[j]
+						}
+					}
+				}
+			}
+			if sym == nil {
+				t.Fatalf("symbol %s not found in %+v", tt.symbol, result.Symbols)
+			}
+
+			var got []string
+			for _, tp := range sym.TypeParams {
+				got = append(got, tp.String())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("type params of %s = %q, want %q", tt.symbol, got, tt.want)
+			}
+		})
+	}
+}
+
+// barrierParser parses only once `parties` calls are parsing at the same
+// time, so a registry that serialized Parse would never get past it.
+type barrierParser struct {
+	arrived *sync.WaitGroup
+}
+
+func (p *barrierParser) Parse(content []byte, filePath string) (*FileAnalysis, error) {
+	p.arrived.Done()
+	done := make(chan struct{})
+	go func() {
+		p.arrived.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return &FileAnalysis{Path: filePath, Language: string(LangGo)}, nil
+	case <-time.After(5 * time.Second):
+		return nil, fmt.Errorf("%s: parsed alone", filePath)
+	}
+}
+
+func (p *barrierParser) Language() Language { return LangGo }
+
+func TestParserRegistryParsesConcurrently(t *testing.T) {
+	const parties = 4
+	var arrived sync.WaitGroup
+	arrived.Add(parties)
+	reg := &ParserRegistry{parsers: make(map[Language][]parserEntry)}
+	reg.registerFactory(func() Parser { return &barrierParser{arrived: &arrived} }, PriorityTreeSitter)
+
+	errs := make(chan error, parties)
+	for i := 0; i < parties; i++ {
+		go func() {
+			_, err := reg.Parse([]byte("package main\n"), fmt.Sprintf("f%d.go", i))
+			errs <- err
+		}()
+	}
+	for i := 0; i < parties; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Parse: %v", err)
+		}
+	}
+}
+
+func TestParserRegistryConcurrentResults(t *testing.T) {
+	reg := NewParserRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("Func%d", i)
+			src := fmt.Sprintf("package main\n\nfunc %s() {}\n", name)
+			fa, err := reg.Parse([]byte(src), name+".go")
+			if err != nil {
+				t.Errorf("Parse %s: %v", name, err)
+				return
+			}
+			if findSymbol(fa.Symbols, name) == nil {
+				t.Errorf("%s not found in %+v", name, fa.Symbols)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -9,6 +10,7 @@ import (
 )
 
 type TOMLParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -35,12 +37,17 @@ func (p *TOMLParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 	}
 
 	root := tree.RootNode()
-	p.extractSymbols(root, content, analysis, "")
+	p.extractSymbols(root, content, analysis)
 
 	return analysis, nil
 }
 
-func (p *TOMLParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis, currentSection string) {
+// extractSymbols emits each table as a symbol named by its dotted path with
+// its keys as children. Array-of-tables entries are numbered in order of
+// appearance ([[bin]] becomes bin[0], bin[1], ...).
+func (p *TOMLParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	arrayIndex := make(map[string]int)
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -48,71 +55,82 @@ func (p *TOMLParser) extractSymbols(node *sitter.Node, content []byte, analysis
 		}
 
 		switch child.Type() {
+		case "pair":
+			if sym := p.parsePair(child, content, analysis, ""); sym != nil {
+				analysis.Symbols = append(analysis.Symbols, *sym)
+			}
+
 		case "table":
-			section := p.parseTable(child, content, analysis)
-			currentSection = section
+			section := p.tableName(child, content)
+			if section == "" {
+				continue
+			}
+			// [dependencies.serde] declares a dependency in table form
+			if idx := strings.LastIndex(section, "."); idx >= 0 && isDependencyTable(section[:idx]) {
+				p.addDependency(child, section[idx+1:], analysis)
+			}
+			analysis.Symbols = append(analysis.Symbols, Symbol{
+				Name:      section,
+				Kind:      KindClass,
+				LineStart: int(child.StartPoint().Row) + 1,
+				LineEnd:   int(child.EndPoint().Row) + 1,
+				Exported:  true,
+				Children:  p.parsePairs(child, content, analysis, section),
+			})
 
 		case "table_array_element":
-			section := p.parseTableArray(child, content, analysis)
-			currentSection = section
-
-		case "pair":
-			p.parsePair(child, content, analysis, currentSection)
+			section := p.tableName(child, content)
+			if section == "" {
+				continue
+			}
+			n := arrayIndex[section]
+			arrayIndex[section]++
+			analysis.Symbols = append(analysis.Symbols, Symbol{
+				Name:      fmt.Sprintf("%s[%d]", section, n),
+				Kind:      KindVariable,
+				LineStart: int(child.StartPoint().Row) + 1,
+				LineEnd:   int(child.EndPoint().Row) + 1,
+				Signature: "[[" + section + "]]",
+				Exported:  true,
+				Children:  p.parsePairs(child, content, analysis, section),
+			})
 		}
-
-		p.extractSymbols(child, content, analysis, currentSection)
 	}
 }
 
-func (p *TOMLParser) parseTable(node *sitter.Node, content []byte, analysis *FileAnalysis) string {
+// tableName returns the dotted header of a table or array-of-tables element.
+func (p *TOMLParser) tableName(node *sitter.Node, content []byte) string {
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
 			continue
 		}
-
 		if child.Type() == "dotted_key" || child.Type() == "bare_key" || child.Type() == "quoted_key" {
-			section := p.extractKeyName(child, content)
-			analysis.Symbols = append(analysis.Symbols, Symbol{
-				Name:      section,
-				Kind:      KindClass,
-				LineStart: int(node.StartPoint().Row) + 1,
-				LineEnd:   int(node.EndPoint().Row) + 1,
-				Exported:  true,
-			})
-			return section
+			return p.extractKeyName(child, content)
 		}
 	}
-
 	return ""
 }
 
-func (p *TOMLParser) parseTableArray(node *sitter.Node, content []byte, analysis *FileAnalysis) string {
+func (p *TOMLParser) parsePairs(node *sitter.Node, content []byte, analysis *FileAnalysis, section string) []Symbol {
+	var children []Symbol
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		if child == nil {
+		if child == nil || child.Type() != "pair" {
 			continue
 		}
-
-		if child.Type() == "dotted_key" || child.Type() == "bare_key" || child.Type() == "quoted_key" {
-			section := p.extractKeyName(child, content)
-			analysis.Symbols = append(analysis.Symbols, Symbol{
-				Name:      section,
-				Kind:      KindVariable,
-				LineStart: int(node.StartPoint().Row) + 1,
-				LineEnd:   int(node.EndPoint().Row) + 1,
-				Signature: "[[" + section + "]]",
-				Exported:  true,
-			})
-			return section
+		if sym := p.parsePair(child, content, analysis, section); sym != nil {
+			children = append(children, *sym)
 		}
 	}
-
-	return ""
+	return children
 }
 
-func (p *TOMLParser) parsePair(node *sitter.Node, content []byte, analysis *FileAnalysis, section string) {
+// parsePair returns the symbol for a key and records the dependencies it
+// declares when it sits in a dependency table.
+func (p *TOMLParser) parsePair(node *sitter.Node, content []byte, analysis *FileAnalysis, section string) *Symbol {
 	var key string
+	var value *sitter.Node
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -123,28 +141,94 @@ func (p *TOMLParser) parsePair(node *sitter.Node, content []byte, analysis *File
 		switch child.Type() {
 		case "dotted_key", "bare_key", "quoted_key":
 			key = p.extractKeyName(child, content)
+		case "=":
+		default:
+			if child.IsNamed() && child.Type() != "comment" {
+				value = child
+			}
 		}
 	}
 
 	if key == "" {
-		return
+		return nil
 	}
 
-	fullKey := key
-	if section != "" {
-		fullKey = section + "." + key
+	switch {
+	case isDependencyTable(section):
+		// serde.workspace = true names the dependency by its first segment
+		name, _, _ := strings.Cut(key, ".")
+		if name != "python" {
+			p.addDependency(node, name, analysis)
+		}
+	case (section == "project" && key == "dependencies") || section == "project.optional-dependencies":
+		// PEP 621 lists requirement strings: dependencies = ["requests>=2"]
+		if value != nil && value.Type() == "array" {
+			for j := 0; j < int(value.NamedChildCount()); j++ {
+				item := value.NamedChild(j)
+				if item != nil && item.Type() == "string" {
+					if name := requirementName(strings.Trim(item.Content(content), "\"'")); name != "" {
+						p.addDependency(item, name, analysis)
+					}
+				}
+			}
+		}
 	}
 
-	analysis.Symbols = append(analysis.Symbols, Symbol{
-		Name:      fullKey,
+	sym := &Symbol{
+		Name:      key,
 		Kind:      KindProperty,
 		LineStart: int(node.StartPoint().Row) + 1,
 		LineEnd:   int(node.EndPoint().Row) + 1,
 		Exported:  true,
+	}
+	if value != nil && value.Type() != "inline_table" && value.Type() != "array" {
+		sym.Signature = key + " = " + value.Content(content)
+	}
+	return sym
+}
+
+func (p *TOMLParser) addDependency(node *sitter.Node, name string, analysis *FileAnalysis) {
+	analysis.Relationships = append(analysis.Relationships, Relationship{
+		TargetFile: name,
+		Kind:       RelImport,
+		Line:       int(node.StartPoint().Row) + 1,
+	})
+}
+
+// isDependencyTable reports whether a table lists package dependencies, as in
+// Cargo ([dependencies], [dev-dependencies], [target.x.dependencies],
+// [workspace.dependencies]) and Poetry ([tool.poetry.group.dev.dependencies]).
+func isDependencyTable(section string) bool {
+	last := section
+	if idx := strings.LastIndex(section, "."); idx >= 0 {
+		last = section[idx+1:]
+	}
+	switch last {
+	case "dependencies", "dev-dependencies", "build-dependencies", "dev_dependencies", "build_dependencies":
+		return section != "project"
+	}
+	return false
+}
+
+// requirementName returns the package name at the start of a PEP 508
+// requirement such as "requests[socks]>=2.0; python_version>'3.8'".
+func requirementName(req string) string {
+	req = strings.TrimSpace(req)
+	end := strings.IndexFunc(req, func(r rune) bool {
+		return !(r == '-' || r == '_' || r == '.' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'))
 	})
+	if end < 0 {
+		return req
+	}
+	return req[:end]
 }
 
 func (p *TOMLParser) extractKeyName(node *sitter.Node, content []byte) string {
+	if !p.descend() {
+		return ""
+	}
+	defer p.ascend()
+
 	switch node.Type() {
 	case "bare_key":
 		return node.Content(content)
@@ -9,6 +9,7 @@ import (
 )
 
 type TypeScriptParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -42,6 +43,11 @@ func (p *TypeScriptParser) Parse(content []byte, filePath string) (*FileAnalysis
 }
 
 func (p *TypeScriptParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -100,11 +106,13 @@ func (p *TypeScriptParser) parseFunctionDecl(node *sitter.Node, content []byte)
 	sig := p.buildSignature(node, content)
 
 	return &Symbol{
-		Name:      name,
-		Kind:      KindFunction,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
-		Signature: sig,
+		Name:       name,
+		Kind:       KindFunction,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Signature:  sig,
+		Children:   p.extractInlineTypes(node, content),
+		TypeParams: extractTypeParams(node, content),
 	}
 }
 
@@ -116,10 +124,11 @@ func (p *TypeScriptParser) parseClassDecl(node *sitter.Node, content []byte) *Sy
 
 	name := nameNode.Content(content)
 	sym := &Symbol{
-		Name:      name,
-		Kind:      KindClass,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
+		Name:       name,
+		Kind:       KindClass,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		TypeParams: extractTypeParams(node, content),
 	}
 
 	bodyNode := node.ChildByFieldName("body")
@@ -138,10 +147,11 @@ func (p *TypeScriptParser) parseInterfaceDecl(node *sitter.Node, content []byte)
 
 	name := nameNode.Content(content)
 	sym := &Symbol{
-		Name:      name,
-		Kind:      KindInterface,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
+		Name:       name,
+		Kind:       KindInterface,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		TypeParams: extractTypeParams(node, content),
 	}
 
 	bodyNode := node.ChildByFieldName("body")
@@ -158,11 +168,14 @@ func (p *TypeScriptParser) parseTypeAlias(node *sitter.Node, content []byte) *Sy
 		return nil
 	}
 
+	_, members := p.inlineTypeMembers(node.ChildByFieldName("value"), content)
 	return &Symbol{
-		Name:      nameNode.Content(content),
-		Kind:      KindType,
-		LineStart: int(node.StartPoint().Row) + 1,
-		LineEnd:   int(node.EndPoint().Row) + 1,
+		Name:       nameNode.Content(content),
+		Kind:       KindTypeAlias,
+		LineStart:  int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Children:   members,
+		TypeParams: extractTypeParams(node, content),
 	}
 }
 
@@ -203,10 +216,12 @@ func (p *TypeScriptParser) parseClassBody(node *sitter.Node, content []byte) []S
 			}
 
 			children = append(children, Symbol{
-				Name:      name,
-				Kind:      kind,
-				LineStart: int(child.StartPoint().Row) + 1,
-				LineEnd:   int(child.EndPoint().Row) + 1,
+				Name:       name,
+				Kind:       kind,
+				LineStart:  int(child.StartPoint().Row) + 1,
+				LineEnd:    int(child.EndPoint().Row) + 1,
+				Children:   p.extractInlineTypes(child, content),
+				TypeParams: extractTypeParams(child, content),
 			})
 
 		case "public_field_definition", "property_signature":
@@ -215,11 +230,13 @@ func (p *TypeScriptParser) parseClassBody(node *sitter.Node, content []byte) []S
 				continue
 			}
 
+			_, members := p.inlineTypeMembers(child.ChildByFieldName("type"), content)
 			children = append(children, Symbol{
 				Name:      nameNode.Content(content),
 				Kind:      KindProperty,
 				LineStart: int(child.StartPoint().Row) + 1,
 				LineEnd:   int(child.EndPoint().Row) + 1,
+				Children:  members,
 			})
 		}
 	}
@@ -228,6 +245,11 @@ func (p *TypeScriptParser) parseClassBody(node *sitter.Node, content []byte) []S
 }
 
 func (p *TypeScriptParser) parseInterfaceBody(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
 	var children []Symbol
 
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -244,10 +266,12 @@ func (p *TypeScriptParser) parseInterfaceBody(node *sitter.Node, content []byte)
 			}
 
 			children = append(children, Symbol{
-				Name:      nameNode.Content(content),
-				Kind:      KindMethod,
-				LineStart: int(child.StartPoint().Row) + 1,
-				LineEnd:   int(child.EndPoint().Row) + 1,
+				Name:       nameNode.Content(content),
+				Kind:       KindMethod,
+				LineStart:  int(child.StartPoint().Row) + 1,
+				LineEnd:    int(child.EndPoint().Row) + 1,
+				Children:   p.extractInlineTypes(child, content),
+				TypeParams: extractTypeParams(child, content),
 			})
 
 		case "property_signature":
@@ -256,11 +280,13 @@ func (p *TypeScriptParser) parseInterfaceBody(node *sitter.Node, content []byte)
 				continue
 			}
 
+			_, members := p.inlineTypeMembers(child.ChildByFieldName("type"), content)
 			children = append(children, Symbol{
 				Name:      nameNode.Content(content),
 				Kind:      KindProperty,
 				LineStart: int(child.StartPoint().Row) + 1,
 				LineEnd:   int(child.EndPoint().Row) + 1,
+				Children:  members,
 			})
 		}
 	}
@@ -268,6 +294,79 @@ func (p *TypeScriptParser) parseInterfaceBody(node *sitter.Node, content []byte)
 	return children
 }
 
+// extractInlineTypes returns the object types written inline in a
+// function's parameters and return type, such as "function load(): { id:
+// number }", each as a symbol holding its properties and methods. The symbol
+// is named after its parameter, or "result" for the return type.
+func (p *TypeScriptParser) extractInlineTypes(node *sitter.Node, content []byte) []Symbol {
+	if !p.descend() {
+		return nil
+	}
+	defer p.ascend()
+
+	var inline []Symbol
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		for i := 0; i < int(params.NamedChildCount()); i++ {
+			param := params.NamedChild(i)
+			if param.Type() != "required_parameter" && param.Type() != "optional_parameter" {
+				continue
+			}
+			name := "param"
+			if pattern := param.ChildByFieldName("pattern"); pattern != nil {
+				name = pattern.Content(content)
+			}
+			if sym := p.inlineType(param.ChildByFieldName("type"), name, content); sym != nil {
+				inline = append(inline, *sym)
+			}
+		}
+	}
+	if sym := p.inlineType(node.ChildByFieldName("return_type"), "result", content); sym != nil {
+		inline = append(inline, *sym)
+	}
+	return inline
+}
+
+// inlineType returns a symbol called name for the inline object type in
+// typeNode, or nil if there is none or it is empty.
+func (p *TypeScriptParser) inlineType(typeNode *sitter.Node, name string, content []byte) *Symbol {
+	kind, members := p.inlineTypeMembers(typeNode, content)
+	if len(members) == 0 {
+		return nil
+	}
+	return &Symbol{
+		Name:      name,
+		Kind:      kind,
+		LineStart: int(typeNode.StartPoint().Row) + 1,
+		LineEnd:   int(typeNode.EndPoint().Row) + 1,
+		Children:  members,
+	}
+}
+
+// inlineTypeMembers finds an inline object type in typeNode, looking through
+// type annotations, arrays, Promise<...>-style type arguments, and optional
+// or readonly wrappers, and returns its kind and members.
+func (p *TypeScriptParser) inlineTypeMembers(typeNode *sitter.Node, content []byte) (SymbolKind, []Symbol) {
+	if !p.descend() {
+		return "", nil
+	}
+	defer p.ascend()
+
+	if typeNode == nil {
+		return "", nil
+	}
+	switch typeNode.Type() {
+	case "object_type":
+		return KindInterface, p.parseInterfaceBody(typeNode, content)
+	case "type_annotation", "array_type", "generic_type", "type_arguments", "parenthesized_type", "readonly_type":
+		for i := 0; i < int(typeNode.NamedChildCount()); i++ {
+			if kind, members := p.inlineTypeMembers(typeNode.NamedChild(i), content); len(members) > 0 {
+				return kind, members
+			}
+		}
+	}
+	return "", nil
+}
+
 func (p *TypeScriptParser) parseVariableDecl(node *sitter.Node, content []byte, analysis *FileAnalysis) {
 	isConst := false
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -294,13 +393,18 @@ func (p *TypeScriptParser) parseVariableDecl(node *sitter.Node, content []byte,
 			kind = KindConstant
 		}
 
+		var value string
+		var children []Symbol
 		valueNode := child.ChildByFieldName("value")
 		if valueNode != nil {
 			switch valueNode.Type() {
 			case "arrow_function", "function":
 				kind = KindFunction
+				children = p.extractInlineTypes(valueNode, content)
 			case "class":
 				kind = KindClass
+			default:
+				value = literalValue(valueNode.Content(content))
 			}
 		}
 
@@ -309,6 +413,8 @@ func (p *TypeScriptParser) parseVariableDecl(node *sitter.Node, content []byte,
 			Kind:      kind,
 			LineStart: int(child.StartPoint().Row) + 1,
 			LineEnd:   int(child.EndPoint().Row) + 1,
+			Value:     value,
+			Children:  children,
 		})
 	}
 }
@@ -367,6 +473,11 @@ func (p *TypeScriptParser) parseExportStatement(node *sitter.Node, content []byt
 }
 
 func (p *TypeScriptParser) extractRelationships(node *sitter.Node, content []byte, analysis *FileAnalysis) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -379,6 +490,12 @@ func (p *TypeScriptParser) extractRelationships(node *sitter.Node, content []byt
 
 		case "call_expression":
 			p.parseCallExpression(child, content, analysis)
+
+		case "type_alias_declaration":
+			if nameNode := child.ChildByFieldName("name"); nameNode != nil {
+				analysis.Relationships = append(analysis.Relationships,
+					typeAliasReferences(nameNode.Content(content), child.ChildByFieldName("value"), content)...)
+			}
 		}
 
 		p.extractRelationships(child, content, analysis)
@@ -9,6 +9,7 @@ import (
 )
 
 type YAMLParser struct {
+	nestingGuard
 	parser *sitter.Parser
 }
 
@@ -41,6 +42,11 @@ func (p *YAMLParser) Parse(content []byte, filePath string) (*FileAnalysis, erro
 }
 
 func (p *YAMLParser) extractSymbols(node *sitter.Node, content []byte, analysis *FileAnalysis, prefix string) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -61,6 +67,11 @@ func (p *YAMLParser) extractSymbols(node *sitter.Node, content []byte, analysis
 }
 
 func (p *YAMLParser) parseBlockMapping(node *sitter.Node, content []byte, analysis *FileAnalysis, prefix string) {
+	if !p.descend() {
+		return
+	}
+	defer p.ascend()
+
 	var key string
 	var valueNode *sitter.Node
 
@@ -147,6 +158,11 @@ func (p *YAMLParser) parseFlowPair(node *sitter.Node, content []byte, analysis *
 }
 
 func (p *YAMLParser) extractScalarValue(node *sitter.Node, content []byte) string {
+	if !p.descend() {
+		return ""
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -167,6 +183,11 @@ func (p *YAMLParser) extractScalarValue(node *sitter.Node, content []byte) strin
 }
 
 func (p *YAMLParser) getValueType(node *sitter.Node) string {
+	if !p.descend() {
+		return ""
+	}
+	defer p.ascend()
+
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child == nil {
@@ -0,0 +1,161 @@
+package analysis
+
+import "strings"
+
+// returnTypeFirst are the languages that write a callable's return type
+// before its name rather than after its parameter list.
+var returnTypeFirst = map[Language]bool{
+	LangC: true, LangCPP: true, LangJava: true, LangCSharp: true, LangDart: true, LangGroovy: true,
+}
+
+// declModifiers are keywords that may precede a return type without being
+// part of it.
+var declModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "internal": true, "static": true,
+	"final": true, "abstract": true, "synchronized": true, "native": true, "virtual": true,
+	"inline": true, "extern": true, "override": true, "async": true, "sealed": true, "default": true,
+}
+
+// noReturn are the return types that mean a callable returns nothing.
+var noReturn = map[string]bool{"": true, "void": true, "None": true, "()": true, "Unit": true}
+
+// ParseReturns reads the return types of a signature: for Go, the result
+// after the parameter list, split when it is a list ("(int, error)"); for
+// languages that annotate it ("-> T", ": T"), the annotation; for C-family
+// languages, Java, and C#, the type before the name. A callable returning
+// nothing yields nil.
+func ParseReturns(signature string, lang Language) []string {
+	sig := strings.TrimSpace(signature)
+	if lang == LangGo && strings.HasPrefix(sig, "(") {
+		_, end := paramList(sig)
+		if end < 0 {
+			return nil
+		}
+		sig = sig[end+1:]
+	}
+	open := strings.Index(sig, "(")
+	_, end := paramList(sig)
+	if end < 0 {
+		return nil
+	}
+
+	if returnTypeFirst[lang] {
+		fields := strings.Fields(sig[:open])
+		if len(fields) < 2 {
+			return nil // A constructor, or no return type written
+		}
+		var kept []string
+		for _, f := range fields[:len(fields)-1] {
+			if declModifiers[f] || strings.HasPrefix(f, "@") || (strings.HasPrefix(f, "<") && strings.HasSuffix(f, ">")) {
+				continue
+			}
+			kept = append(kept, f)
+		}
+		// Pointer and reference sigils bind to the name in "char *name()"
+		name := fields[len(fields)-1]
+		typ := strings.Join(kept, " ") + name[:len(name)-len(strings.TrimLeft(name, "*&"))]
+		if noReturn[typ] {
+			return nil
+		}
+		return []string{typ}
+	}
+
+	rest := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(sig[end+1:]), "{"))
+	if idx := strings.Index(rest, " where "); idx >= 0 {
+		rest = rest[:idx] // Rust trait bounds
+	}
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, word := range []string{"async", "throws", "rethrows"} {
+			if after, ok := strings.CutPrefix(rest, word); ok && (after == "" || after[0] == ' ' || after[0] == '-') {
+				rest, trimmed = strings.TrimSpace(after), true
+			}
+		}
+	}
+	rest = strings.TrimPrefix(rest, "->")
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, ":"))
+	if noReturn[rest] {
+		return nil
+	}
+
+	if lang == LangGo && strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")") {
+		var types []string
+		for _, part := range splitTopLevel(rest[1 : len(rest)-1]) {
+			// Named results: "(n int, err error)"
+			if fields := strings.Fields(part); len(fields) > 1 {
+				types = append(types, strings.Join(fields[1:], " "))
+			} else if part = strings.TrimSpace(part); part != "" {
+				types = append(types, part)
+			}
+		}
+		return types
+	}
+	return []string{rest}
+}
+
+// SignatureQuery describes callables by shape: the types of their
+// parameters and of what they return. A nil Params or Returns leaves that
+// side unconstrained.
+type SignatureQuery struct {
+	Params  []string // Parameter types, in order
+	Returns []string // Return types, in order
+	Fuzzy   bool     // Match types by case-insensitive substring, parameters in any order, extra ones allowed
+}
+
+// Match reports whether the signature has the queried shape. Without Fuzzy,
+// the parameter and return types must be exactly those queried, ignoring
+// whitespace. Untyped parameters, as in plain Python or JavaScript, match
+// no type.
+func (q SignatureQuery) Match(signature string, lang Language) bool {
+	params := ParseParameters(signature, lang)
+	types := make([]string, 0, len(params))
+	for _, p := range params {
+		t := p.Type
+		if t == "" && lang == LangGo {
+			t = p.Name // Unnamed parameters: "func(context.Context, string)"
+		}
+		types = append(types, t)
+	}
+	returns := ParseReturns(signature, lang)
+
+	if q.Fuzzy {
+		return containsTypes(types, q.Params) && containsTypes(returns, q.Returns)
+	}
+	return (q.Params == nil || sameTypes(types, q.Params)) && (q.Returns == nil || sameTypes(returns, q.Returns))
+}
+
+func sameTypes(have, want []string) bool {
+	if len(have) != len(want) {
+		return false
+	}
+	for i := range have {
+		if normalizeType(have[i]) != normalizeType(want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsTypes reports whether each wanted type is part of a different one
+// of the types had.
+func containsTypes(have, want []string) bool {
+	used := make([]bool, len(have))
+	for _, w := range want {
+		w = strings.ToLower(normalizeType(w))
+		found := false
+		for i, h := range have {
+			if !used[i] && strings.Contains(strings.ToLower(normalizeType(h)), w) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeType(t string) string {
+	return strings.Join(strings.Fields(t), "")
+}
@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSignatureQueryMatchGo(t *testing.T) {
+	src := []byte(`package svc
+
+import "context"
+
+func Sync(ctx context.Context, s string) error {
+	return nil
+}
+`)
+	fa, err := Analyze(src, "svc.go")
+	if err != nil || len(fa.Symbols) == 0 {
+		t.Fatalf("Analyze() = %+v, %v", fa, err)
+	}
+	var sig string
+	for _, s := range fa.Symbols {
+		if s.Name == "Sync" {
+			sig = s.Signature
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query SignatureQuery
+		want  bool
+	}{
+		{"exact", SignatureQuery{Params: []string{"context.Context", "string"}, Returns: []string{"error"}}, true},
+		{"returns open", SignatureQuery{Params: []string{"context.Context", "string"}}, true},
+		{"wrong order", SignatureQuery{Params: []string{"string", "context.Context"}, Returns: []string{"error"}}, false},
+		{"missing param", SignatureQuery{Params: []string{"context.Context"}, Returns: []string{"error"}}, false},
+		{"wrong return", SignatureQuery{Params: []string{"context.Context", "string"}, Returns: []string{"bool"}}, false},
+		{"fuzzy partial", SignatureQuery{Params: []string{"Context"}, Returns: []string{"err"}, Fuzzy: true}, true},
+		{"fuzzy any order", SignatureQuery{Params: []string{"string", "context"}, Fuzzy: true}, true},
+		{"fuzzy absent", SignatureQuery{Params: []string{"int"}, Fuzzy: true}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.query.Match(sig, LangGo); got != tt.want {
+			t.Errorf("%s: Match(%q) = %v, want %v", tt.name, sig, got, tt.want)
+		}
+	}
+}
+
+func TestParseReturns(t *testing.T) {
+	tests := []struct {
+		sig  string
+		lang Language
+		want []string
+	}{
+		{"Load(path string) (*Config, error)", LangGo, []string{"*Config", "error"}},
+		{"(s *Server) Count() (n int, err error)", LangGo, []string{"int", "error"}},
+		{"Close()", LangGo, nil},
+		{"def load(path: str) -> Config", LangPython, []string{"Config"}},
+		{"fetch(id: string): Promise<User>", LangTypeScript, []string{"Promise<User>"}},
+		{"func read() async throws -> Data", LangSwift, []string{"Data"}},
+		{"public static List<String> names(int n)", LangJava, []string{"List<String>"}},
+		{"char *dup(const char *s)", LangC, []string{"char*"}},
+		{"void reset()", LangC, nil},
+	}
+	for _, tt := range tests {
+		if got := ParseReturns(tt.sig, tt.lang); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseReturns(%q) = %q, want %q", tt.sig, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strictness trades recall for precision in the heuristic parsers: the
+// regex parsers (Dart, Nim, CUE) and the generic fallback.
+type Strictness string
+
+// Strictness levels.
+const (
+	// StrictnessLoose also emits speculative symbols, marked LowConfidence.
+	StrictnessLoose Strictness = "loose"
+	// StrictnessBalanced is the default: what the parsers emit unless tuned.
+	StrictnessBalanced Strictness = "balanced"
+	// StrictnessStrict requires stronger evidence before emitting a symbol
+	// or relationship, dropping constructs the parser is unsure of.
+	StrictnessStrict Strictness = "strict"
+)
+
+// ParseStrictness returns the strictness named s; "" is balanced.
+func ParseStrictness(s string) (Strictness, error) {
+	switch Strictness(strings.ToLower(strings.TrimSpace(s))) {
+	case "", StrictnessBalanced:
+		return StrictnessBalanced, nil
+	case StrictnessLoose:
+		return StrictnessLoose, nil
+	case StrictnessStrict:
+		return StrictnessStrict, nil
+	default:
+		return "", fmt.Errorf("unknown strictness %q (use %s, %s, or %s)", s, StrictnessLoose, StrictnessBalanced, StrictnessStrict)
+	}
+}
+
+// StrictnessParser is a parser whose heuristics follow a Strictness. The
+// registry sets it before each Parse, from the level configured for the
+// parser's language.
+type StrictnessParser interface {
+	Parser
+	SetStrictness(s Strictness)
+}
+
+// SetStrictness sets the strictness of the heuristic parsers used by
+// Analyze, keyed by language name, e.g. {"dart": "strict"}. Languages not
+// listed are balanced; passing nil resets every language.
+func SetStrictness(levels map[string]string) error {
+	return defaultRegistry.SetStrictness(levels)
+}
+
+// SetStrictness sets the strictness of the heuristic parsers used by Parse.
+// See the package-level SetStrictness. On an unknown level, or a language
+// whose parsers do not follow one, nothing changes.
+func (r *ParserRegistry) SetStrictness(levels map[string]string) error {
+	parsed := make(map[Language]Strictness, len(levels))
+	for lang, level := range levels {
+		s, err := ParseStrictness(level)
+		if err != nil {
+			return fmt.Errorf("%s: %w", lang, err)
+		}
+		l := Language(strings.ToLower(lang))
+		if !r.followsStrictness(l) {
+			return fmt.Errorf("%s: strictness is not supported for this language", lang)
+		}
+		parsed[l] = s
+	}
+	r.mu.Lock()
+	r.strictness = parsed
+	r.mu.Unlock()
+	return nil
+}
+
+// followsStrictness reports whether a parser registered for lang, or the
+// generic fallback, is a StrictnessParser.
+func (r *ParserRegistry) followsStrictness(lang Language) bool {
+	if lang == LangGeneric {
+		return true
+	}
+	for _, entry := range r.parsers[lang] {
+		if _, ok := entry.parser.(StrictnessParser); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tune sets the strictness configured for lang on p, if p follows one, and
+// the nesting limit on parsers whose walkers follow it.
+func (r *ParserRegistry) tune(p Parser, lang Language) {
+	if g, ok := p.(nestingLimited); ok {
+		g.setNestingLimit(r.maxDepth)
+	}
+	if sp, ok := p.(StrictnessParser); ok {
+		s, ok := r.strictness[lang]
+		if !ok {
+			s = StrictnessBalanced
+		}
+		sp.SetStrictness(s)
+	}
+}
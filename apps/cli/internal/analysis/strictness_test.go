@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+)
+
+func symbolNamed(fa *FileAnalysis, name string) *Symbol {
+	for i := range fa.Symbols {
+		if fa.Symbols[i].Name == name {
+			return &fa.Symbols[i]
+		}
+	}
+	return nil
+}
+
+func TestStrictness(t *testing.T) {
+	// A top-level function without a return type: valid Dart, but the
+	// balanced regexes only know typed declarations
+	src := `import 'package:flutter/material.dart';
+
+main() {
+  runApp(const App());
+}
+
+class App {
+  void build() {}
+}
+`
+	parse := func(level string) *FileAnalysis {
+		t.Helper()
+		reg := NewParserRegistry()
+		if err := reg.SetStrictness(map[string]string{"dart": level}); err != nil {
+			t.Fatalf("SetStrictness(%q) error: %v", level, err)
+		}
+		fa, err := reg.Parse([]byte(src), "main.dart")
+		if err != nil {
+			t.Fatalf("Parse() error: %v", err)
+		}
+		return fa
+	}
+
+	loose := parse("loose")
+	if sym := symbolNamed(loose, "main"); sym == nil {
+		t.Errorf("loose: main() { should be a symbol, got %+v", loose.Symbols)
+	} else if !sym.LowConfidence || sym.LineStart != 3 || sym.LineEnd != 5 {
+		t.Errorf("loose: main = %+v, want a low-confidence function on lines 3-5", *sym)
+	}
+	for _, level := range []string{"balanced", "strict"} {
+		fa := parse(level)
+		if sym := symbolNamed(fa, "main"); sym != nil {
+			t.Errorf("%s: main() { should not be a symbol, got %+v", level, *sym)
+		}
+		if symbolNamed(fa, "App") == nil {
+			t.Errorf("%s: class App should be kept, got %+v", level, fa.Symbols)
+		}
+	}
+
+	reg := NewParserRegistry()
+	if err := reg.SetStrictness(map[string]string{"dart": "pedantic"}); err == nil {
+		t.Error("SetStrictness with an unknown level should fail")
+	}
+}
+
+func TestStrictnessCUE(t *testing.T) {
+	src := `package config
+
+version: "1.2"
+
+#Service: {
+	name: string
+	ports: {
+		http: int
+	}
+}
+`
+	parse := func(level string) *FileAnalysis {
+		t.Helper()
+		reg := NewParserRegistry()
+		if err := reg.SetStrictness(map[string]string{"cue": level}); err != nil {
+			t.Fatalf("SetStrictness(%q) error: %v", level, err)
+		}
+		fa, err := reg.Parse([]byte(src), "config.cue")
+		if err != nil {
+			t.Fatalf("Parse() error: %v", err)
+		}
+		return fa
+	}
+	fields := func(fa *FileAnalysis) []string {
+		t.Helper()
+		sym := symbolNamed(fa, "#Service")
+		if sym == nil {
+			t.Fatalf("#Service should be a symbol, got %+v", fa.Symbols)
+		}
+		var names []string
+		for _, child := range sym.Children {
+			names = append(names, child.Name)
+		}
+		return names
+	}
+
+	loose := parse("loose")
+	if sym := symbolNamed(loose, "version"); sym == nil || !sym.LowConfidence {
+		t.Errorf("loose: version should be a low-confidence symbol, got %+v", loose.Symbols)
+	}
+	balanced := parse("balanced")
+	if sym := symbolNamed(balanced, "version"); sym != nil {
+		t.Errorf("balanced: version should not be a symbol, got %+v", *sym)
+	}
+	if got := fmt.Sprint(fields(balanced)); got != "[name ports http]" {
+		t.Errorf("balanced: #Service fields = %s, want [name ports http]", got)
+	}
+	if got := fmt.Sprint(fields(parse("strict"))); got != "[name ports]" {
+		t.Errorf("strict: #Service fields = %s, want [name ports]", got)
+	}
+}
+
+func TestSetStrictnessUnsupportedLanguage(t *testing.T) {
+	reg := NewParserRegistry()
+	for _, lang := range []string{"dart", "Nim", "cue", "generic"} {
+		if err := reg.SetStrictness(map[string]string{lang: "strict"}); err != nil {
+			t.Errorf("SetStrictness(%s) error: %v", lang, err)
+		}
+	}
+	for _, lang := range []string{"go", "python", "cobol"} {
+		if err := reg.SetStrictness(map[string]string{lang: "strict"}); err == nil {
+			t.Errorf("SetStrictness(%s) should fail: its parsers ignore strictness", lang)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SymbolChangeKind describes how a symbol differs between two versions of a file.
+type SymbolChangeKind string
+
+const (
+	SymbolAdded            SymbolChangeKind = "added"
+	SymbolRemoved          SymbolChangeKind = "removed"
+	SymbolSignatureChanged SymbolChangeKind = "signature_changed"
+	SymbolBodyChanged      SymbolChangeKind = "body_changed"
+)
+
+// SymbolChange is a single symbol-level difference between two file versions.
+type SymbolChange struct {
+	ID           string           `json:"id"` // Stable ID: "<path>#<Qualified.Name>", plus "(<param types>)" for overloads
+	File         string           `json:"file"`
+	Name         string           `json:"name"` // Qualified name (Parent.Child)
+	SymbolKind   SymbolKind       `json:"symbolKind"`
+	Change       SymbolChangeKind `json:"change"`
+	OldSignature string           `json:"oldSignature,omitempty"`
+	NewSignature string           `json:"newSignature,omitempty"`
+	LineStart    int              `json:"lineStart,omitempty"`  // Line in the new version (old version for removals)
+	Params       []ParamChange    `json:"params,omitempty"`     // Parameter-level detail of a callable's signature change
+	TypeParams   []ParamChange    `json:"typeParams,omitempty"` // Type parameters added, removed, moved, or reconstrained
+}
+
+// symbolSnapshot captures what is compared for a symbol across versions.
+type symbolSnapshot struct {
+	name       string
+	kind       SymbolKind
+	signature  string
+	typeParams []TypeParam
+	bodyHash   string
+	lineStart  int
+}
+
+// SymbolID returns the stable identifier used to match a symbol across versions.
+func SymbolID(path, qualifiedName string) string {
+	return path + "#" + qualifiedName
+}
+
+// DiffSymbols compares two analyses of the same file and reports per-symbol changes.
+// Either analysis may be nil (file added or deleted). The contents are used to
+// detect body changes for symbols whose signature is unchanged.
+func DiffSymbols(path string, oldFA *FileAnalysis, oldContent []byte, newFA *FileAnalysis, newContent []byte) []SymbolChange {
+	// A name overloaded in either version is keyed by its parameter types in
+	// both, so adding an overload does not change the ID of the existing one
+	// into an unrelated-looking removal.
+	overloaded := overloadedNames(oldFA)
+	for name := range overloadedNames(newFA) {
+		overloaded[name] = true
+	}
+	oldSyms := snapshotSymbols(oldFA, oldContent, overloaded)
+	newSyms := snapshotSymbols(newFA, newContent, overloaded)
+
+	var changes []SymbolChange
+	for key, n := range newSyms {
+		o, ok := oldSyms[key]
+		if !ok {
+			changes = append(changes, SymbolChange{
+				ID: SymbolID(path, key), File: path, Name: n.name, SymbolKind: n.kind,
+				Change: SymbolAdded, NewSignature: n.signature, LineStart: n.lineStart,
+			})
+			continue
+		}
+		switch {
+		case o.signature != n.signature || o.kind != n.kind || typeParamsKey(o.typeParams) != typeParamsKey(n.typeParams):
+			change := SymbolChange{
+				ID: SymbolID(path, key), File: path, Name: n.name, SymbolKind: n.kind,
+				Change: SymbolSignatureChanged, OldSignature: o.signature, NewSignature: n.signature, LineStart: n.lineStart,
+			}
+			if isCallable(o.kind) && isCallable(n.kind) {
+				change.Params = DiffParameters(o.signature, n.signature, DetectLanguage(path))
+			}
+			change.TypeParams = DiffTypeParams(o.typeParams, n.typeParams)
+			changes = append(changes, change)
+		case o.bodyHash != n.bodyHash:
+			changes = append(changes, SymbolChange{
+				ID: SymbolID(path, key), File: path, Name: n.name, SymbolKind: n.kind,
+				Change: SymbolBodyChanged, OldSignature: o.signature, NewSignature: n.signature, LineStart: n.lineStart,
+			})
+		}
+	}
+	for key, o := range oldSyms {
+		if _, ok := newSyms[key]; !ok {
+			changes = append(changes, SymbolChange{
+				ID: SymbolID(path, key), File: path, Name: o.name, SymbolKind: o.kind,
+				Change: SymbolRemoved, OldSignature: o.signature, LineStart: o.lineStart,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return changes[i].File < changes[j].File
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	return changes
+}
+
+// snapshotSymbols flattens an analysis into qualified-name keyed snapshots.
+// Overloaded callables are keyed by name and parameter types; any remaining
+// duplicate names are disambiguated by their order of appearance. Synthetic
+// kinds such as findings are skipped: fixing one is not an API change.
+func snapshotSymbols(fa *FileAnalysis, content []byte, overloaded map[string]bool) map[string]symbolSnapshot {
+	result := make(map[string]symbolSnapshot)
+	if fa == nil {
+		return result
+	}
+	lines := strings.Split(string(content), "\n")
+	seen := make(map[string]int)
+
+	var walk func(syms []Symbol, prefix string)
+	walk = func(syms []Symbol, prefix string) {
+		for i := range syms {
+			sym := &syms[i]
+			if IsSyntheticKind(sym.Kind) {
+				continue
+			}
+			name := sym.Name
+			if prefix != "" {
+				name = prefix + "." + sym.Name
+			}
+			key := name
+			if overloaded[name] && isCallable(sym.Kind) {
+				key = OverloadKey(name, sym.Signature)
+			}
+			if n := seen[key]; n > 0 {
+				key = fmt.Sprintf("%s~%d", key, n)
+			}
+			seen[key]++
+
+			result[key] = symbolSnapshot{
+				name:       name,
+				kind:       sym.Kind,
+				signature:  sym.Signature,
+				typeParams: sym.TypeParams,
+				bodyHash:   hashLines(lines, sym.LineStart, sym.LineEnd),
+				lineStart:  sym.LineStart,
+			}
+			walk(sym.Children, name)
+		}
+	}
+	walk(fa.Symbols, "")
+	return result
+}
+
+// overloadedNames returns the qualified names shared by more than one
+// callable in the analysis.
+func overloadedNames(fa *FileAnalysis) map[string]bool {
+	result := make(map[string]bool)
+	if fa == nil {
+		return result
+	}
+	counts := make(map[string]int)
+	var walk func(syms []Symbol, prefix string)
+	walk = func(syms []Symbol, prefix string) {
+		for i := range syms {
+			name := syms[i].Name
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			if isCallable(syms[i].Kind) {
+				counts[name]++
+				if counts[name] > 1 {
+					result[name] = true
+				}
+			}
+			walk(syms[i].Children, name)
+		}
+	}
+	walk(fa.Symbols, "")
+	return result
+}
+
+func isCallable(kind SymbolKind) bool {
+	return kind == KindFunction || kind == KindMethod || kind == KindConstructor
+}
+
+// hashLines hashes the 1-based inclusive line range, ignoring leading and
+// trailing whitespace so re-indentation alone is not reported as a change.
+func hashLines(lines []string, start, end int) string {
+	if start < 1 || end < start || start > len(lines) {
+		return ""
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+	h := sha256.New()
+	for _, line := range lines[start-1 : end] {
+		h.Write([]byte(strings.TrimSpace(line)))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
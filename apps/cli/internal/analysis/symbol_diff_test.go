@@ -0,0 +1,113 @@
+package analysis
+
+import "testing"
+
+func TestDiffSymbols(t *testing.T) {
+	oldSrc := []byte(`package svc
+
+func Load(id string) error {
+	return nil
+}
+
+func Drop() {}
+`)
+	newSrc := []byte(`package svc
+
+func Load(id string, force bool) error {
+	return nil
+}
+`)
+	oldFA, err := Analyze(oldSrc, "svc.go")
+	if err != nil {
+		t.Fatalf("Analyze(old) error: %v", err)
+	}
+	newFA, err := Analyze(newSrc, "svc.go")
+	if err != nil {
+		t.Fatalf("Analyze(new) error: %v", err)
+	}
+
+	got := make(map[string]SymbolChange)
+	for _, c := range DiffSymbols("svc.go", oldFA, oldSrc, newFA, newSrc) {
+		got[c.Name] = c
+	}
+
+	if c := got["Load"]; c.Change != SymbolSignatureChanged || c.OldSignature == c.NewSignature {
+		t.Errorf("Load = %+v, want signature change", c)
+	}
+	if c := got["Drop"]; c.Change != SymbolRemoved {
+		t.Errorf("Drop change = %q, want %q", c.Change, SymbolRemoved)
+	}
+}
+
+func TestDiffSymbolsNewFile(t *testing.T) {
+	src := []byte("package svc\n\nfunc New() {}\n")
+	fa, err := Analyze(src, "new.go")
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+	changes := DiffSymbols("new.go", nil, nil, fa, src)
+	if len(changes) != 1 || changes[0].Change != SymbolAdded || changes[0].ID != "new.go#New" {
+		t.Errorf("unexpected changes: %+v", changes)
+	}
+}
+
+func TestJavaOverloadsAreDistinctSymbols(t *testing.T) {
+	src := []byte(`public class Calc {
+    public int add(int a) { return a; }
+    public int add(int a, int b) { return a + b; }
+}
+`)
+	fa, err := Analyze(src, "Calc.java")
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+	if len(fa.Symbols) != 1 {
+		t.Fatalf("expected one class, got %+v", fa.Symbols)
+	}
+
+	keys := make(map[string]bool)
+	for _, m := range fa.Symbols[0].Children {
+		if m.Name == "add" {
+			keys[OverloadKey(m.Name, m.Signature)] = true
+		}
+	}
+	if len(keys) != 2 || !keys["add(int)"] || !keys["add(int,int)"] {
+		t.Errorf("expected distinct add(int) and add(int,int), got %v", keys)
+	}
+}
+
+func TestDiffSymbolsOverloadAdded(t *testing.T) {
+	oldSrc := []byte(`public class Calc {
+    public int add(int a, int b) { return a + b; }
+}
+`)
+	newSrc := []byte(`public class Calc {
+    public int add(int a, int b) { return a + b; }
+    public long add(long a, long b) { return a + b; }
+}
+`)
+	oldFA, err := Analyze(oldSrc, "Calc.java")
+	if err != nil {
+		t.Fatalf("Analyze(old) error: %v", err)
+	}
+	newFA, err := Analyze(newSrc, "Calc.java")
+	if err != nil {
+		t.Fatalf("Analyze(new) error: %v", err)
+	}
+
+	got := make(map[string]SymbolChange)
+	for _, c := range DiffSymbols("Calc.java", oldFA, oldSrc, newFA, newSrc) {
+		got[c.ID] = c
+	}
+	if c, ok := got["Calc.java#Calc.add(long,long)"]; !ok || c.Change != SymbolAdded {
+		t.Errorf("expected add(long,long) to be added, got %+v", got)
+	}
+	if c, ok := got["Calc.java#Calc.add(int,int)"]; ok {
+		t.Errorf("existing overload should be unchanged, got %+v", c)
+	}
+	for id, c := range got {
+		if c.Change == SymbolRemoved {
+			t.Errorf("unexpected removal %s", id)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// assertionPatterns match, per language, the calls and statements counted
+// as assertions in a test body. Languages without their own pattern use
+// defaultAssertionPattern, which covers xUnit-style assert*/Assert.* calls,
+// expect(...), XCTAssert*, and verify(...).
+var assertionPatterns = map[Language]*regexp.Regexp{
+	LangGo:         regexp.MustCompile(`\b(?:t|tb|b|f)\.(?:Errorf?|Fatalf?|Fail|FailNow)\(|\b(?:assert|require)\.\w+\(`),
+	LangPython:     regexp.MustCompile(`(?m)^\s*assert\b|\bself\.assert\w+\(|\bpytest\.(?:raises|fail)\(`),
+	LangJavaScript: jsAssertionPattern,
+	LangTypeScript: jsAssertionPattern,
+	LangSvelte:     jsAssertionPattern,
+	LangRust:       regexp.MustCompile(`\b(?:debug_)?assert(?:_eq|_ne)?!`),
+}
+
+var (
+	jsAssertionPattern      = regexp.MustCompile(`\bexpect\s*\(|\bassert(?:\.\w+)?\s*\(`)
+	defaultAssertionPattern = regexp.MustCompile(`\b(?:assert\w*|Assert(?:ions)?\.\w+|expect\w*|XCTAssert\w*|verify)\s*\(`)
+)
+
+// testAnnotations are the annotation names, as annotationName gives them,
+// that mark a method as a test: JUnit, xUnit, NUnit, MSTest, and Rust.
+var testAnnotations = map[string]bool{
+	"test": true, "parameterizedtest": true, "repeatedtest": true, "testfactory": true,
+	"fact": true, "theory": true, "testmethod": true, "testcase": true,
+}
+
+var (
+	// Go's t.Run("name", func(t *testing.T) {...}); the name may be any
+	// expression, such as tc.name in a table-driven test. Requiring the
+	// name and a comma leaves out calls like cmd.Run().
+	goSubtestRe = regexp.MustCompile(`\b\w+\.Run(\()\s*("(?:[^"\\]|\\.)*"|[^,()"]+),`)
+	// Python's unittest "with self.subTest(...):" blocks.
+	pySubtestRe = regexp.MustCompile(`^(\s*)with\s+self\.subTest\((.*)\)\s*:`)
+	// Jest, Mocha, and Vitest blocks: describe("name", ...), it("name",
+	// ...), and test("name", ...), also with .only, .skip, and the like.
+	jsTestBlockRe = regexp.MustCompile(`^\s*(?:describe|context|it|test)(?:\.(?:only|skip|todo|concurrent))?\s*(\()\s*('(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `)\s*,`)
+	// pytest's pytest.param(..., id="name").
+	pytestParamIDRe = regexp.MustCompile(`^pytest\.param\(.*\bid\s*=\s*['"]([^'"]*)['"]`)
+)
+
+// markTests finds the test functions of a test file, counts the assertions
+// in each, and adds their subtests as KindTest children: Go's t.Run calls,
+// Python's self.subTest blocks, and the cases of pytest.mark.parametrize. A
+// table-driven test yields one subtest named by its name expression, e.g.
+// "tc.name", standing for all its cases. In JavaScript and TypeScript,
+// whose tests are describe, it, and test blocks rather than functions, the
+// blocks become KindTest symbols nested as they are written.
+func markTests(fa *FileAnalysis, lang Language, content []byte) {
+	if fa == nil {
+		return
+	}
+	lines := strings.Split(string(content), "\n")
+	var walk func(symbols []Symbol)
+	walk = func(symbols []Symbol) {
+		for i := range symbols {
+			sym := &symbols[i]
+			if isTestSymbol(sym, lang) {
+				sym.Test = true
+				sym.AssertionCount = countAssertions(lang, lines, sym.LineStart, sym.LineEnd)
+				sym.Children = append(sym.Children, findSubtests(lang, lines, sym.LineStart, sym.LineEnd)...)
+				if lang == LangPython {
+					sym.Children = append(sym.Children, parametrizedCases(sym)...)
+				}
+				continue
+			}
+			walk(sym.Children)
+		}
+	}
+	walk(fa.Symbols)
+	if lang == LangJavaScript || lang == LangTypeScript {
+		fa.Symbols = append(fa.Symbols, findSubtests(lang, lines, 0, len(lines))...)
+	}
+}
+
+// isTestSymbol reports whether sym is a test function by the conventions of
+// lang: Go's TestXxx, a test annotation such as @Test or [Fact], or a name
+// starting with "test" as in pytest, JUnit 3, XCTest, and PHPUnit.
+func isTestSymbol(sym *Symbol, lang Language) bool {
+	if sym.Kind != KindFunction && sym.Kind != KindMethod {
+		return false
+	}
+	if lang == LangGo {
+		rest, ok := strings.CutPrefix(sym.Name, "Test")
+		return ok && (rest == "" || !isLowerASCII(rest[0]))
+	}
+	for _, ann := range sym.Annotations {
+		if testAnnotations[annotationName(ann)] {
+			return true
+		}
+	}
+	return strings.HasPrefix(strings.ToLower(sym.Name), "test")
+}
+
+func isLowerASCII(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// countAssertions counts the assertions on lines start to end (1-based,
+// inclusive), skipping whole-line comments.
+func countAssertions(lang Language, lines []string, start, end int) int {
+	pattern, ok := assertionPatterns[lang]
+	if !ok {
+		pattern = defaultAssertionPattern
+	}
+	count := 0
+	for _, line := range lineRange(lines, start, end) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		count += len(pattern.FindAllStringIndex(line, -1))
+	}
+	return count
+}
+
+// lineRange returns lines start to end, 1-based and inclusive, clamped to
+// the lines there are.
+func lineRange(lines []string, start, end int) []string {
+	start = max(start, 1)
+	end = min(end, len(lines))
+	if start > end {
+		return nil
+	}
+	return lines[start-1 : end]
+}
+
+// findSubtests returns the subtests directly inside lines start to end of a
+// test, each with its own assertions counted and its own subtests nested.
+func findSubtests(lang Language, lines []string, start, end int) []Symbol {
+	var subtests []Symbol
+	// Start after the test's own first line so a subtest is never its parent
+	for line := start + 1; line <= min(end, len(lines)); line++ {
+		var sub Symbol
+		var ok bool
+		switch lang {
+		case LangGo:
+			sub, ok = goSubtest(lines, line, end)
+		case LangPython:
+			sub, ok = pySubtest(lines, line, end)
+		case LangJavaScript, LangTypeScript:
+			sub, ok = jsTestBlock(lines, line, end)
+		}
+		if !ok {
+			continue
+		}
+		sub.AssertionCount = countAssertions(lang, lines, sub.LineStart, sub.LineEnd)
+		sub.Children = findSubtests(lang, lines, sub.LineStart, sub.LineEnd)
+		subtests = append(subtests, sub)
+		line = sub.LineEnd
+	}
+	return subtests
+}
+
+// goSubtest reads a t.Run call starting on line, ending where its
+// parentheses balance, but no later than limit.
+func goSubtest(lines []string, line, limit int) (Symbol, bool) {
+	m := goSubtestRe.FindStringSubmatchIndex(lines[line-1])
+	if m == nil {
+		return Symbol{}, false
+	}
+	name := unquoteName(lines[line-1][m[4]:m[5]])
+
+	depth := 0
+	end := line
+	for l, text := line, lines[line-1][m[2]:]; l <= limit && l <= len(lines); l++ {
+		if l > line {
+			text = lines[l-1]
+		}
+		depth += parenBalance(text)
+		end = l
+		if depth <= 0 {
+			break
+		}
+	}
+	return Symbol{Name: name, Kind: KindTest, LineStart: line, LineEnd: end, Test: true}, true
+}
+
+// jsTestBlock reads a describe, it, or test block starting on line, ending
+// where its parentheses balance, but no later than limit.
+func jsTestBlock(lines []string, line, limit int) (Symbol, bool) {
+	m := jsTestBlockRe.FindStringSubmatchIndex(lines[line-1])
+	if m == nil {
+		return Symbol{}, false
+	}
+	name := lines[line-1][m[4]+1 : m[5]-1]
+
+	depth := 0
+	end := line
+	for l, text := line, lines[line-1][m[2]:]; l <= limit && l <= len(lines); l++ {
+		if l > line {
+			text = lines[l-1]
+		}
+		depth += parenBalance(text)
+		end = l
+		if depth <= 0 {
+			break
+		}
+	}
+	return Symbol{Name: name, Kind: KindTest, LineStart: line, LineEnd: end, Test: true}, true
+}
+
+// parenBalance returns the opening minus the closing parentheses in text,
+// ignoring those in string literals and after a line comment.
+func parenBalance(text string) int {
+	balance := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(text) && text[i+1] == '/':
+			return balance
+		case c == '(':
+			balance++
+		case c == ')':
+			balance--
+		}
+	}
+	return balance
+}
+
+// pySubtest reads a "with self.subTest(...):" block starting on line,
+// ending before the next line indented no deeper than the with, but no
+// later than limit.
+func pySubtest(lines []string, line, limit int) (Symbol, bool) {
+	m := pySubtestRe.FindStringSubmatch(lines[line-1])
+	if m == nil {
+		return Symbol{}, false
+	}
+	name := unquoteName(m[2])
+	indent := len(m[1])
+	end := line
+	for l := line + 1; l <= limit && l <= len(lines); l++ {
+		text := lines[l-1]
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if len(text)-len(strings.TrimLeft(text, " \t")) <= indent {
+			break
+		}
+		end = l
+	}
+	return Symbol{Name: name, Kind: KindTest, LineStart: line, LineEnd: end, Test: true}, true
+}
+
+// unquoteName returns a subtest name expression without its quotes when it
+// is a single string literal, and as written otherwise.
+func unquoteName(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if n := len(expr); n >= 2 && expr[0] == '\'' && expr[n-1] == '\'' && !strings.ContainsAny(expr[1:n-1], "'\\") {
+		return expr[1 : n-1]
+	}
+	if unquoted, err := strconv.Unquote(expr); err == nil {
+		return unquoted
+	}
+	return expr
+}
+
+// parametrizedCases returns a subtest for each case of the
+// pytest.mark.parametrize decorators of a test, named as pytest names them:
+// by its id= if it has one, else by its values joined with "-" when they
+// are plain literals, else by argument name and index. Stacked decorators
+// multiply. A case list pytest would build at run time, such as a variable,
+// yields one subtest named by the expression, standing for all its cases.
+// Every case runs the body of the test, so each has its assertions.
+func parametrizedCases(test *Symbol) []Symbol {
+	var ids []string
+	for _, ann := range test.Annotations {
+		if annotationName(ann) != "parametrize" {
+			continue
+		}
+		open := strings.Index(ann, "(")
+		if open < 0 || !strings.HasSuffix(ann, ")") {
+			continue
+		}
+		args := splitPythonArgs(ann[open+1 : len(ann)-1])
+		if len(args) < 2 {
+			continue
+		}
+		names := strings.Split(unquoteName(args[0]), ",")
+		var decorated []string
+		if list := args[1]; len(list) >= 2 && (list[0] == '[' || list[0] == '(') {
+			for i, c := range splitPythonArgs(list[1 : len(list)-1]) {
+				decorated = append(decorated, parametrizeID(c, names, i))
+			}
+		} else {
+			decorated = []string{list}
+		}
+		if ids == nil {
+			ids = decorated
+			continue
+		}
+		var product []string
+		for _, id := range ids {
+			for _, d := range decorated {
+				product = append(product, id+"-"+d)
+			}
+		}
+		ids = product
+	}
+
+	subtests := make([]Symbol, 0, len(ids))
+	for _, id := range ids {
+		subtests = append(subtests, Symbol{
+			Name: id, Kind: KindTest, LineStart: test.LineStart, LineEnd: test.LineEnd,
+			Test: true, AssertionCount: test.AssertionCount,
+		})
+	}
+	return subtests
+}
+
+// parametrizeID names the i-th case of a parametrize decorator over names.
+func parametrizeID(c string, names []string, i int) string {
+	if m := pytestParamIDRe.FindStringSubmatch(c); m != nil {
+		return m[1]
+	}
+	values := []string{c}
+	if len(names) > 1 && len(c) >= 2 && (c[0] == '(' || c[0] == '[') {
+		values = splitPythonArgs(c[1 : len(c)-1])
+	}
+	var parts []string
+	for j, v := range values {
+		v = unquoteName(v)
+		if v == "" || strings.ContainsAny(v, " ()[]{},=") {
+			name := fmt.Sprint(j)
+			if j < len(names) {
+				name = strings.TrimSpace(names[j])
+			}
+			v = name + strconv.Itoa(i)
+		}
+		parts = append(parts, v)
+	}
+	return strings.Join(parts, "-")
+}
+
+// splitPythonArgs splits a Python argument or element list on the commas
+// outside brackets and string literals, dropping a trailing empty element.
+func splitPythonArgs(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(s[start:]); rest != "" {
+		parts = append(parts, rest)
+	}
+	return parts
+}
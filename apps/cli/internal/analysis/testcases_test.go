@@ -0,0 +1,186 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkTestsGoSubtests(t *testing.T) {
+	src := `package thing
+
+import "testing"
+
+func TestThing(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		if got := Thing(""); got != "" {
+			t.Errorf("Thing(\"\") = %q", got)
+		}
+	})
+	t.Run("upper", func(t *testing.T) {
+		got := Thing("a")
+		if got == "" {
+			t.Fatal("empty result")
+		}
+		if got != "A" {
+			t.Errorf("Thing(\"a\") = %q", got)
+		}
+	})
+}
+
+func TestNothing(t *testing.T) {
+	Thing("x")
+}
+
+func helper() {}
+`
+	fa, err := Analyze([]byte(src), "thing_test.go")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	thing := findSymbol(fa.Symbols, "TestThing")
+	if thing == nil {
+		t.Fatalf("expected TestThing, got %+v", fa.Symbols)
+	}
+	if !thing.Test || thing.AssertionCount != 3 {
+		t.Errorf("TestThing: Test=%v, AssertionCount=%d, want a test with 3 assertions", thing.Test, thing.AssertionCount)
+	}
+	var subtests []Symbol
+	for _, c := range thing.Children {
+		if c.Kind == KindTest {
+			subtests = append(subtests, c)
+		}
+	}
+	if len(subtests) != 2 {
+		t.Fatalf("expected 2 subtests, got %+v", thing.Children)
+	}
+	if subtests[0].Name != "empty" || subtests[0].AssertionCount != 1 || subtests[0].LineStart != 6 || subtests[0].LineEnd != 10 {
+		t.Errorf("unexpected first subtest: %+v", subtests[0])
+	}
+	if subtests[1].Name != "upper" || subtests[1].AssertionCount != 2 || subtests[1].LineStart != 11 || subtests[1].LineEnd != 19 {
+		t.Errorf("unexpected second subtest: %+v", subtests[1])
+	}
+
+	if nothing := findSymbol(fa.Symbols, "TestNothing"); nothing == nil || !nothing.Test || nothing.AssertionCount != 0 {
+		t.Errorf("TestNothing should be a test with no assertions, got %+v", nothing)
+	}
+	if h := findSymbol(fa.Symbols, "helper"); h == nil || h.Test {
+		t.Errorf("helper should not be a test, got %+v", h)
+	}
+}
+
+func TestMarkTestsOnlyInTestFiles(t *testing.T) {
+	src := `package thing
+
+func TestLooksLikeATest() {
+	check("x")
+}
+`
+	fa, err := Analyze([]byte(src), "thing.go")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if sym := findSymbol(fa.Symbols, "TestLooksLikeATest"); sym == nil || sym.Test {
+		t.Errorf("functions outside test files should not be tests, got %+v", sym)
+	}
+}
+
+func TestMarkTestsPythonSubTest(t *testing.T) {
+	src := `import unittest
+
+class TestParse(unittest.TestCase):
+    def test_numbers(self):
+        for n in [1, 2]:
+            with self.subTest(n=n):
+                self.assertEqual(parse(str(n)), n)
+        assert parse("") is None
+
+    def test_todo(self):
+        pass
+`
+	fa, err := Analyze([]byte(src), "test_parse.py")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	numbers := findSymbol(fa.Symbols, "test_numbers")
+	if numbers == nil || !numbers.Test || numbers.AssertionCount != 2 {
+		t.Fatalf("test_numbers should be a test with 2 assertions, got %+v", numbers)
+	}
+	if len(numbers.Children) != 1 || numbers.Children[0].Name != "n=n" || numbers.Children[0].AssertionCount != 1 {
+		t.Errorf("expected one subtest n=n with 1 assertion, got %+v", numbers.Children)
+	}
+	if todo := findSymbol(fa.Symbols, "test_todo"); todo == nil || !todo.Test || todo.AssertionCount != 0 {
+		t.Errorf("test_todo should be a test with no assertions, got %+v", todo)
+	}
+}
+
+func TestMarkTestsPytestParametrize(t *testing.T) {
+	src := `import pytest
+
+@pytest.mark.parametrize("a,b", [
+    (1, 2),
+    ("x y", 3),
+    pytest.param(5, 6, id="big"),
+])
+@pytest.mark.parametrize("mode", ["fast", "slow"])
+def test_add(a, b, mode):
+    assert a + 1 == b
+
+@pytest.mark.parametrize("n", CASES)
+def test_table(n):
+    pass
+`
+	fa, err := Analyze([]byte(src), "test_add.py")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	add := findSymbol(fa.Symbols, "test_add")
+	if add == nil || !add.Test {
+		t.Fatalf("expected test_add to be a test, got %+v", fa.Symbols)
+	}
+	var names []string
+	for _, c := range add.Children {
+		if c.Kind != KindTest || c.AssertionCount != 1 {
+			t.Errorf("case %q should be a subtest with the test's assertion, got %+v", c.Name, c)
+		}
+		names = append(names, c.Name)
+	}
+	want := "1-2-fast,1-2-slow,a1-3-fast,a1-3-slow,big-fast,big-slow"
+	if strings.Join(names, ",") != want {
+		t.Errorf("cases = %v, want %s", names, want)
+	}
+	if table := findSymbol(fa.Symbols, "test_table"); table == nil || len(table.Children) != 1 || table.Children[0].Name != "CASES" {
+		t.Errorf("a case list built at run time should be one subtest, got %+v", table)
+	}
+}
+
+func TestMarkTestsJSBlocks(t *testing.T) {
+	src := `import { add } from './add';
+
+describe('add', () => {
+  it('adds', () => {
+    expect(add(1, 2)).toBe(3);
+  });
+  describe("edge cases", () => {
+    test.skip(` + "`zero`" + `, () => {});
+  });
+});
+`
+	fa, err := Analyze([]byte(src), "add.test.ts")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if len(fa.Symbols) != 1 {
+		t.Fatalf("expected one top-level describe block, got %+v", fa.Symbols)
+	}
+	add := fa.Symbols[0]
+	if add.Name != "add" || add.Kind != KindTest || add.AssertionCount != 1 || add.LineStart != 3 || add.LineEnd != 10 {
+		t.Errorf("unexpected describe block %+v", add)
+	}
+	if len(add.Children) != 2 || add.Children[0].Name != "adds" || add.Children[0].AssertionCount != 1 {
+		t.Fatalf("expected the adds and edge cases blocks, got %+v", add.Children)
+	}
+	edge := add.Children[1]
+	if edge.Name != "edge cases" || len(edge.Children) != 1 || edge.Children[0].Name != "zero" || edge.Children[0].AssertionCount != 0 {
+		t.Errorf("expected edge cases with the zero test nested, got %+v", edge)
+	}
+}
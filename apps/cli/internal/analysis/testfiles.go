@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// testNameRule matches a test file by its base name: a prefix, a suffix
+// (which includes the extension), or both.
+type testNameRule struct {
+	prefix, suffix string
+}
+
+// testNameRules are the per-language naming conventions for test files.
+var testNameRules = map[Language][]testNameRule{
+	LangGo:         {{suffix: "_test.go"}},
+	LangPython:     {{prefix: "test_", suffix: ".py"}, {suffix: "_test.py"}, {prefix: "conftest", suffix: ".py"}},
+	LangJavaScript: jsTestRules(".js", ".jsx", ".mjs", ".cjs"),
+	LangTypeScript: jsTestRules(".ts", ".tsx", ".mts", ".cts"),
+	LangSvelte:     jsTestRules(".svelte"),
+	LangJava:       {{suffix: "Test.java"}, {suffix: "Tests.java"}},
+	LangKotlin:     {{suffix: "Test.kt"}, {suffix: "Tests.kt"}},
+	LangScala:      {{suffix: "Test.scala"}, {suffix: "Spec.scala"}, {suffix: "Suite.scala"}},
+	LangGroovy:     {{suffix: "Test.groovy"}, {suffix: "Spec.groovy"}},
+	LangCSharp:     {{suffix: "Test.cs"}, {suffix: "Tests.cs"}},
+	LangSwift:      {{suffix: "Test.swift"}, {suffix: "Tests.swift"}},
+	LangPHP:        {{suffix: "Test.php"}},
+	LangRuby:       {{suffix: "_spec.rb"}, {suffix: "_test.rb"}, {prefix: "test_", suffix: ".rb"}},
+	LangDart:       {{suffix: "_test.dart"}},
+	LangElixir:     {{suffix: "_test.exs"}},
+	LangRust:       {{suffix: "_test.rs"}, {suffix: "_tests.rs"}},
+	LangC:          {{suffix: "_test.c"}, {prefix: "test_", suffix: ".c"}},
+	LangCPP: {
+		{suffix: "_test.cc"}, {suffix: "_test.cpp"}, {suffix: "_unittest.cc"}, {suffix: "_unittest.cpp"},
+		{prefix: "test_", suffix: ".cc"}, {prefix: "test_", suffix: ".cpp"},
+	},
+	LangLua:   {{suffix: "_spec.lua"}, {suffix: "_test.lua"}},
+	LangOCaml: {{prefix: "test_", suffix: ".ml"}},
+	LangBash:  {{suffix: ".bats"}},
+}
+
+// jsTestRules covers "x.test.js" and "x.spec.js" for each extension.
+func jsTestRules(exts ...string) []testNameRule {
+	var rules []testNameRule
+	for _, ext := range exts {
+		rules = append(rules, testNameRule{suffix: ".test" + ext}, testNameRule{suffix: ".spec" + ext})
+	}
+	return rules
+}
+
+// testDirRules are directories whose source files are all tests, per
+// language: "src/test" is Maven and Gradle's test source root, "tests" is a
+// Rust crate's integration tests and a Python package's test suite.
+var testDirRules = map[Language][]string{
+	LangPython:     {"tests", "test"},
+	LangJavaScript: {"__tests__", "__mocks__"},
+	LangTypeScript: {"__tests__", "__mocks__"},
+	LangJava:       {"src/test"},
+	LangKotlin:     {"src/test", "src/androidTest"},
+	LangScala:      {"src/test"},
+	LangGroovy:     {"src/test"},
+	LangRust:       {"tests"},
+	LangRuby:       {"spec", "test"},
+	LangDart:       {"test", "integration_test"},
+	LangElixir:     {"test"},
+	LangPHP:        {"tests"},
+	LangSwift:      {"Tests"},
+}
+
+// IsTestFile reports whether the file at path holds tests, by the naming
+// and layout conventions of lang. Languages without a rule of their own,
+// including LangUnknown, are judged by the conventions common to all:
+// "_test" and "test_" names and "test"/"tests" directories.
+func IsTestFile(filePath string, lang Language) bool {
+	p := path.Clean(filepath.ToSlash(filePath))
+	base := path.Base(p)
+
+	nameRules, ok := testNameRules[lang]
+	dirRules := testDirRules[lang]
+	if !ok {
+		stem := strings.TrimSuffix(base, path.Ext(base))
+		return strings.HasSuffix(stem, "_test") || strings.HasPrefix(stem, "test_") ||
+			inDir(p, "test") || inDir(p, "tests")
+	}
+	for _, r := range nameRules {
+		if strings.HasPrefix(base, r.prefix) && strings.HasSuffix(base, r.suffix) && len(base) >= len(r.prefix)+len(r.suffix) {
+			return true
+		}
+	}
+	for _, dir := range dirRules {
+		if inDir(p, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// inDir reports whether the slash-separated path p lies under a directory
+// named dir (which may itself have several segments) at any depth.
+func inDir(p, dir string) bool {
+	dir = "/" + dir + "/"
+	return strings.HasPrefix(p, dir[1:]) || strings.Contains(p, dir)
+}
@@ -0,0 +1,62 @@
+package analysis
+
+import "testing"
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		lang Language
+		want bool
+	}{
+		{"pkg/server_test.go", LangGo, true},
+		{"pkg/server.go", LangGo, false},
+		{"analysis/test_data.go", LangGo, false},
+		{"tests/test_api.py", LangPython, true},
+		{"app/test_api.py", LangPython, true},
+		{"app/api_test.py", LangPython, true},
+		{"conftest.py", LangPython, true},
+		{"app/tests/helpers.py", LangPython, true},
+		{"app/testing.py", LangPython, false},
+		{"src/app.spec.ts", LangTypeScript, true},
+		{"src/app.test.tsx", LangTypeScript, true},
+		{"src/__tests__/app.ts", LangTypeScript, true},
+		{"src/app.ts", LangTypeScript, false},
+		{"src/util.test.js", LangJavaScript, true},
+		{"src/main/java/com/x/UserService.java", LangJava, false},
+		{"src/main/java/com/x/UserServiceTest.java", LangJava, true},
+		{"src/test/java/com/x/Fixtures.java", LangJava, true},
+		{"Tests/AppTests.swift", LangSwift, true},
+		{"spec/models/user_spec.rb", LangRuby, true},
+		{"lib/user.rb", LangRuby, false},
+		{"tests/integration.rs", LangRust, true},
+		{"src/lib.rs", LangRust, false},
+		{"UserTests.cs", LangCSharp, true},
+		{`src\widget_test.dart`, LangDart, true},
+		// Languages without rules use the common conventions
+		{"scripts/build_test.sh2", LangUnknown, true},
+		{"test/fixtures.xyz", LangUnknown, true},
+		{"contest/entry.xyz", LangUnknown, false},
+	}
+	for _, tt := range tests {
+		if got := IsTestFile(tt.path, tt.lang); got != tt.want {
+			t.Errorf("IsTestFile(%q, %s) = %v, want %v", tt.path, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestParseSetsIsTest(t *testing.T) {
+	fa, err := Analyze([]byte("package x\n\nfunc TestX(t *testing.T) {}\n"), "x_test.go")
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if !fa.IsTest {
+		t.Error("x_test.go should be marked IsTest")
+	}
+	fa, err = Analyze([]byte("package x\n"), "x.go")
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if fa.IsTest {
+		t.Error("x.go should not be marked IsTest")
+	}
+}
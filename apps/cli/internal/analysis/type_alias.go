@@ -0,0 +1,50 @@
+package analysis
+
+import sitter "github.com/smacker/go-tree-sitter"
+
+// typeNameNodes are the node types that name a type outright, across the
+// grammars whose parsers emit type aliases. Qualified names are taken whole.
+var typeNameNodes = map[string]bool{
+	"type_identifier":        true, // Go, TypeScript, C
+	"qualified_type":         true, // Go: pkg.Type
+	"nested_type_identifier": true, // TypeScript: ns.Type
+	"predefined_type":        true, // TypeScript: string, number
+	"primitive_type":         true, // C: int, char
+	"sized_type_specifier":   true, // C: unsigned long
+	"identifier":             true, // Python
+	"attribute":              true, // Python: typing.Optional
+}
+
+// typeAliasReferences returns a reference relationship from an alias to each
+// type named in its aliased type expression, e.g. Foo, Bar, and Baz for
+// "Foo | Bar<Baz>". Each type is referenced once, in order of appearance.
+func typeAliasReferences(alias string, typeNode *sitter.Node, content []byte) []Relationship {
+	var rels []Relationship
+	seen := make(map[string]bool)
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if typeNameNodes[n.Type()] {
+			name := n.Content(content)
+			if !seen[name] {
+				seen[name] = true
+				rels = append(rels, Relationship{
+					SourceSymbol: alias,
+					TargetSymbol: name,
+					Kind:         RelReference,
+					Line:         int(n.StartPoint().Row) + 1,
+					Column:       int(n.StartPoint().Column),
+				})
+			}
+			return
+		}
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			if child := n.NamedChild(i); child != nil {
+				walk(child)
+			}
+		}
+	}
+	if typeNode != nil {
+		walk(typeNode)
+	}
+	return rels
+}
@@ -0,0 +1,151 @@
+package analysis
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// TypeParam is one generic type parameter of a symbol, such as T in
+// "func Map[T any]" or "class Box<T extends Item>".
+type TypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint,omitempty"` // "any", "comparable", "Item"; empty when unconstrained
+}
+
+// String renders the type parameter as "name constraint".
+func (tp TypeParam) String() string {
+	return strings.TrimSpace(tp.Name + " " + tp.Constraint)
+}
+
+// extractTypeParams reads the type parameter list of a declaration: the
+// type_parameters field (Go, TypeScript, Java) or child (Kotlin). Go's
+// "[K, V comparable]" gives both K and V the constraint comparable; the
+// "extends" of TypeScript and Java and the ":" of Kotlin are left out of
+// the constraint.
+func extractTypeParams(node *sitter.Node, content []byte) []TypeParam {
+	list := node.ChildByFieldName("type_parameters")
+	if list == nil {
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if child := node.NamedChild(i); child.Type() == "type_parameters" {
+				list = child
+				break
+			}
+		}
+	}
+	if list == nil {
+		return nil
+	}
+
+	var params []TypeParam
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		decl := list.NamedChild(i)
+		switch decl.Type() {
+		case "type_parameter_declaration": // Go
+			constraint := ""
+			if typeNode := decl.ChildByFieldName("type"); typeNode != nil {
+				constraint = typeNode.Content(content)
+			}
+			for j := 0; j < int(decl.ChildCount()); j++ {
+				if decl.FieldNameForChild(j) == "name" {
+					params = append(params, TypeParam{Name: decl.Child(j).Content(content), Constraint: constraint})
+				}
+			}
+		case "type_parameter": // TypeScript, Java, Kotlin
+			if tp, ok := typeParam(decl, content); ok {
+				params = append(params, tp)
+			}
+		}
+	}
+	return params
+}
+
+// typeParam reads one TypeScript, Java, or Kotlin type_parameter.
+func typeParam(decl *sitter.Node, content []byte) (TypeParam, bool) {
+	var tp TypeParam
+	if nameNode := decl.ChildByFieldName("name"); nameNode != nil {
+		tp.Name = nameNode.Content(content)
+	}
+	if c := decl.ChildByFieldName("constraint"); c != nil {
+		tp.Constraint = c.Content(content)
+	}
+	afterColon := false
+	for i := 0; i < int(decl.ChildCount()); i++ {
+		child := decl.Child(i)
+		switch {
+		case child.Type() == "type_identifier" && tp.Name == "":
+			tp.Name = child.Content(content)
+		case child.Type() == "type_bound": // Java
+			tp.Constraint = child.Content(content)
+		case child.Type() == ":": // Kotlin
+			afterColon = true
+		case afterColon && child.IsNamed() && tp.Constraint == "":
+			tp.Constraint = child.Content(content)
+		}
+	}
+	tp.Constraint = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tp.Constraint), "extends"))
+	return tp, tp.Name != ""
+}
+
+// DiffTypeParams compares the type parameters of two versions of a symbol,
+// matching them by name. Every change is breaking: code that names the
+// type arguments explicitly no longer fits the new list, and a changed
+// constraint may reject type arguments the old one accepted.
+func DiffTypeParams(oldParams, newParams []TypeParam) []ParamChange {
+	oldIndex := make(map[string]int, len(oldParams))
+	for i, tp := range oldParams {
+		oldIndex[tp.Name] = i
+	}
+	matched := make([]bool, len(oldParams))
+	for _, tp := range newParams {
+		if j, ok := oldIndex[tp.Name]; ok {
+			matched[j] = true
+		}
+	}
+	// A type parameter has moved when its order relative to the other kept
+	// ones changed; insertions and removals alone do not move it.
+	moved := make(map[string]bool)
+	for a := range newParams {
+		for b := a + 1; b < len(newParams); b++ {
+			ja, okA := oldIndex[newParams[a].Name]
+			jb, okB := oldIndex[newParams[b].Name]
+			if okA && okB && ja > jb {
+				moved[newParams[a].Name], moved[newParams[b].Name] = true, true
+			}
+		}
+	}
+
+	var changes []ParamChange
+	for _, tp := range newParams {
+		n := Parameter{Name: tp.Name, Type: tp.Constraint}
+		j, ok := oldIndex[tp.Name]
+		if !ok {
+			changes = append(changes, ParamChange{Kind: ParamAdded, Name: n.Name, New: &n, Breaking: true})
+			continue
+		}
+		o := Parameter{Name: oldParams[j].Name, Type: oldParams[j].Constraint}
+		if moved[tp.Name] {
+			changes = append(changes, ParamChange{Kind: ParamMoved, Name: n.Name, Old: &o, New: &n, Breaking: true})
+		}
+		if o.Type != n.Type {
+			changes = append(changes, ParamChange{Kind: ParamRetyped, Name: n.Name, Old: &o, New: &n, Breaking: true})
+		}
+	}
+	for j, tp := range oldParams {
+		if !matched[j] {
+			o := Parameter{Name: tp.Name, Type: tp.Constraint}
+			changes = append(changes, ParamChange{Kind: ParamRemoved, Name: o.Name, Old: &o, Breaking: true})
+		}
+	}
+	return changes
+}
+
+// typeParamsKey renders type parameters for comparing two versions of a
+// symbol.
+func typeParamsKey(params []TypeParam) string {
+	parts := make([]string, len(params))
+	for i, tp := range params {
+		parts[i] = tp.String()
+	}
+	return strings.Join(parts, ", ")
+}
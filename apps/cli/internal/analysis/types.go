@@ -15,8 +15,22 @@ const (
 	KindEnum        SymbolKind = "enum"
 	KindProperty    SymbolKind = "property"
 	KindConstructor SymbolKind = "constructor"
+	KindTypeAlias   SymbolKind = "type_alias"
+	KindFinding     SymbolKind = "finding"    // A problem spotted in the source, e.g. a hardcoded secret
+	KindTest        SymbolKind = "test"       // A subtest inside a test function, e.g. Go's t.Run
+	KindConfigKey   SymbolKind = "config_key" // A configuration or environment key read by the code, e.g. DB_HOST
 )
 
+// SyntheticKinds are the kinds derived by a pass over the source rather
+// than declared in it. They are not part of a file's API and are left out
+// of diffs, API surfaces, graphs, and lint.
+var SyntheticKinds = []SymbolKind{KindFinding, KindTest, KindConfigKey}
+
+// IsSyntheticKind reports whether kind is one of SyntheticKinds.
+func IsSyntheticKind(kind SymbolKind) bool {
+	return kind == KindFinding || kind == KindTest || kind == KindConfigKey
+}
+
 // RelationshipKind represents the type of relationship between symbols.
 type RelationshipKind string
 
@@ -28,6 +42,12 @@ const (
 	RelExtends    RelationshipKind = "extends"
 	RelImplements RelationshipKind = "implements"
 	RelUses       RelationshipKind = "uses"
+
+	// Framework relationships, added by a RelationshipProcessor rather than
+	// read from the source.
+	RelRoute      RelationshipKind = "route"      // A router dispatches to a handler
+	RelInjects    RelationshipKind = "injects"    // A dependency is injected into a consumer
+	RelSubscribes RelationshipKind = "subscribes" // A listener receives an event type
 )
 
 // Symbol represents a programming construct found in a file.
@@ -41,6 +61,34 @@ type Symbol struct {
 	DocComment string
 	Exported   bool
 	Children   []Symbol
+	// Annotations holds decorators/attributes without their sigils,
+	// e.g. "Service", "derive(Debug, Clone)", "app.route('/')".
+	Annotations []string
+	// Deprecated and Experimental are set from markers such as Go's
+	// "Deprecated:" paragraph, @deprecated tags and decorators, or
+	// [Obsolete]; see markLifecycle.
+	Deprecated   bool
+	Experimental bool
+	// LowConfidence marks symbols guessed by the generic fallback parser
+	// rather than parsed from a known grammar.
+	LowConfidence bool
+	// Owner and LastCommit attribute the symbol to whoever wrote most of its
+	// lines and to the latest commit among them. They are set only by scans
+	// run with git blame enrichment.
+	Owner      string
+	LastCommit string
+	// Value is the literal a constant or variable is assigned, such as
+	// "1.0.0" for VERSION = "1.0.0", with string quotes removed. It is empty
+	// when the assignment is not a simple string, number, or boolean.
+	Value string
+	// TypeParams are the generic type parameters of a function, method, or
+	// type, in declaration order; see extractTypeParams.
+	TypeParams []TypeParam
+	// Test marks test functions and their subtests in test files, and
+	// AssertionCount is the number of assertions in their bodies, subtests
+	// included; see markTests.
+	Test           bool
+	AssertionCount int
 }
 
 // Relationship represents a semantic link between symbols.
@@ -51,6 +99,7 @@ type Relationship struct {
 	Kind         RelationshipKind
 	Line         int
 	Column       int
+	ImportKind   ImportKind // For imports: local, stdlib, or thirdparty
 }
 
 // FileAnalysis stores the results of analyzing a single file.
@@ -59,6 +108,8 @@ type FileAnalysis struct {
 	Language      string
 	Symbols       []Symbol
 	Relationships []Relationship
+	Warnings      []string // Problems that degraded the analysis, e.g. nesting past the depth limit
+	IsTest        bool     // The file holds tests, by its language's conventions (see IsTestFile)
 }
 
 // Language represents a programming or markup language.
@@ -99,5 +150,9 @@ const (
 	LangOCaml      Language = "ocaml"
 	LangElm        Language = "elm"
 	LangCUE        Language = "cue"
+	LangNim        Language = "nim"
+	LangObjectiveC Language = "objc"
+	LangMATLAB     Language = "matlab"
+	LangGeneric    Language = "generic" // Unknown text parsed by the generic fallback
 	LangUnknown    Language = "unknown"
 )
@@ -52,6 +52,9 @@ func New(db *sql.DB, root string) (*Butler, error) {
 	if err == nil {
 		b.memory = mem
 
+		rc := b.recallConfig()
+		mem.SetQueryExpansion(memory.QueryExpansion{Stemming: rc.StemmingEnabled(), Synonyms: rc.Synonyms})
+
 		// Initialize embedding pipeline if configured
 		if embedder := b.GetEmbedder(); embedder != nil {
 			pipeline := memory.NewEmbeddingPipeline(mem, embedder, 2) // 2 workers
@@ -259,6 +259,8 @@ func (b *Butler) resolveRoom(filePath string) string {
 func (b *Butler) gatherPrioritizedLearnings(filePath, room string, cfg *config.AutoInjectionConfig) []PrioritizedLearning {
 	var all []PrioritizedLearning
 	seen := make(map[string]bool)
+	now := time.Now()
+	halfLife := b.recallConfig().HalfLife()
 
 	// Helper to add learning with deduplication
 	addLearning := func(l memory.Learning, scopeType string, priority float64) {
@@ -275,12 +277,7 @@ func (b *Butler) gatherPrioritizedLearnings(filePath, room string, cfg *config.A
 		finalPriority += l.Confidence * 0.3 // Higher confidence = higher priority
 
 		if cfg.PrioritizeRecent {
-			daysSinceUsed := time.Since(l.LastUsed).Hours() / 24
-			if daysSinceUsed < 7 {
-				finalPriority += 0.2
-			} else if daysSinceUsed < 30 {
-				finalPriority += 0.1
-			}
+			finalPriority += recencyBoost(l.LastUsed, now, halfLife)
 		}
 
 		reason := fmt.Sprintf("Relevant to %s scope", scopeType)
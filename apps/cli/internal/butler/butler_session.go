@@ -119,6 +119,14 @@ func (b *Butler) SearchLearnings(query string, limit int) ([]memory.Learning, er
 	return b.memory.SearchLearnings(query, limit)
 }
 
+// SearchLearningsBool searches learnings with a boolean query.
+func (b *Butler) SearchLearningsBool(q *memory.BoolQuery, limit int) ([]memory.Learning, error) {
+	if b.memory == nil {
+		return nil, fmt.Errorf("session memory not available")
+	}
+	return b.memory.SearchLearningsBool(q, limit)
+}
+
 // ReinforceLearning increases learning confidence.
 func (b *Butler) ReinforceLearning(id string) error {
 	if b.memory == nil {
@@ -514,7 +514,7 @@ func TestButlerIntegrated(t *testing.T) {
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
 		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
-		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
+		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER, low_confidence INTEGER DEFAULT 0);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
 		`CREATE TABLE scans (id INTEGER PRIMARY KEY, root TEXT, scan_hash TEXT, started_at TEXT, completed_at TEXT);`,
 		`CREATE TABLE rooms (name TEXT PRIMARY KEY, summary TEXT, entry_points TEXT, file_patterns TEXT, updated_at TEXT);`,
@@ -530,7 +530,7 @@ func TestButlerIntegrated(t *testing.T) {
 	db.ExecContext(context.Background(), `INSERT INTO files VALUES (?, ?, ?, ?, ?, ?);`, "auth.go", "h1", 100, now, now, "go")
 	db.ExecContext(context.Background(), `INSERT INTO chunks VALUES (?, ?, ?, ?, ?, ?);`, 1, "auth.go", 0, 1, 10, "func HandleAuth() {}")
 	db.ExecContext(context.Background(), `INSERT INTO chunks_fts VALUES (?, ?, ?);`, "auth.go", "func HandleAuth() {}", 0)
-	db.ExecContext(context.Background(), `INSERT INTO symbols VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, 1, "auth.go", "HandleAuth", "function", 1, 10, "()", "Auth handler", nil, 1)
+	db.ExecContext(context.Background(), `INSERT INTO symbols(id, file_path, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, 1, "auth.go", "HandleAuth", "function", 1, 10, "()", "Auth handler", nil, 1)
 	db.ExecContext(context.Background(), `INSERT INTO symbols_fts VALUES (?, ?, ?, ?);`, "HandleAuth", "auth.go", "function", "Auth handler")
 	db.ExecContext(context.Background(), `INSERT INTO rooms VALUES (?, ?, ?, ?, ?);`, "auth", "Auth module", `["auth.go"]`, `[]`, now)
 
@@ -1003,7 +1003,7 @@ func TestGetContextForTask(t *testing.T) {
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
 		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
-		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
+		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER, low_confidence INTEGER DEFAULT 0);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
 	}
 	for _, s := range stmts {
@@ -1046,7 +1046,7 @@ func TestGetContextForTaskWithOptions(t *testing.T) {
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
 		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
-		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
+		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER, low_confidence INTEGER DEFAULT 0);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
 	}
 	for _, s := range stmts {
@@ -1082,7 +1082,7 @@ func TestGetEnhancedContext(t *testing.T) {
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
 		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index, tokenize="unicode61 tokenchars '_.:@#$-'");`,
-		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
+		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER, low_confidence INTEGER DEFAULT 0);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment, tokenize="unicode61 tokenchars '_'");`,
 	}
 	for _, s := range stmts {
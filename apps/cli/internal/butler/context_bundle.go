@@ -0,0 +1,347 @@
+package butler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// DefaultContextBudget is the token budget of a context bundle when none is
+// given.
+const DefaultContextBudget = 4000
+
+// contextKeptDecisions is how many of the highest-ranked decisions a bundle
+// keeps ahead of everything else, as it keeps pinned records.
+const contextKeptDecisions = 2
+
+// contextSummaryChars is the longest line kept when an entry is summarized.
+const contextSummaryChars = 80
+
+// ContextItem is one ranked entry of a context bundle.
+type ContextItem struct {
+	Kind       string  `json:"kind"` // symbol, snippet, decision, learning, or idea
+	Ref        string  `json:"ref"`  // file:line for code, record ID for memory
+	Text       string  `json:"text"`
+	Tokens     int     `json:"tokens"`
+	Score      float64 `json:"score,omitempty"` // Boosted rank score of memory records
+	Pinned     bool    `json:"pinned,omitempty"`
+	Summarized bool    `json:"summarized,omitempty"` // Text cut to its heading and first line
+
+	kept bool // Pinned or a top decision, fitted before all other entries
+}
+
+// ContextOmission is an entry left out of a bundle to fit its budget.
+type ContextOmission struct {
+	Kind   string `json:"kind"`
+	Ref    string `json:"ref"`
+	Tokens int    `json:"tokens"` // What the entry would have used in full
+}
+
+// ContextBundle is code and memory context for a topic, trimmed to a budget.
+type ContextBundle struct {
+	Topic     string            `json:"topic"`
+	Budget    int               `json:"budget"`
+	Tokens    int               `json:"tokens"`
+	Truncated bool              `json:"truncated,omitempty"`
+	Code      []ContextItem     `json:"code"`
+	Memory    []ContextItem     `json:"memory"`
+	Omitted   []ContextOmission `json:"omitted,omitempty"`
+}
+
+// ContextBundleOptions configures BuildContextBundle.
+type ContextBundleOptions struct {
+	Topic        string
+	Budget       int // Token budget for the whole bundle
+	Limit        int // Maximum candidates fetched from each source
+	IncludeTests bool
+	// SkipKinds are memory kinds (decision, learning, idea) left out.
+	SkipKinds []string
+}
+
+// BuildContextBundle gathers code context from the index and memory context
+// from the palace's records, ranks each, and trims both to the token budget.
+// Pinned records and the top decisions are fitted first, so they are only
+// dropped when the budget cannot hold them. The remaining entries share the
+// rest: each section is given half of the budget, and whatever one section
+// leaves unused is handed to the other. Within a section entries are taken
+// greedily in rank order; one that does not fit is summarized if its summary
+// does, and omitted otherwise.
+func (b *Butler) BuildContextBundle(opts ContextBundleOptions) (*ContextBundle, error) {
+	if strings.TrimSpace(opts.Topic) == "" {
+		return nil, errors.New("topic is required")
+	}
+	if opts.Budget <= 0 {
+		opts.Budget = DefaultContextBudget
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+
+	code, err := b.codeContextItems(opts)
+	if err != nil {
+		return nil, err
+	}
+	mem, err := b.memoryContextItems(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept, rest []ContextItem
+	for _, item := range mem {
+		if item.kept {
+			kept = append(kept, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	keptFit, keptOmitted, keptUsed := fitContextItems(kept, opts.Budget)
+	budget := opts.Budget - keptUsed
+
+	codeFit, codeOmitted, codeUsed := fitContextItems(code, min(opts.Budget/2, budget))
+	memFit, memOmitted, memUsed := fitContextItems(rest, budget-codeUsed)
+	if len(codeOmitted) > 0 {
+		codeFit, codeOmitted, codeUsed = fitContextItems(code, budget-memUsed)
+	}
+
+	bundle := &ContextBundle{
+		Topic:  opts.Topic,
+		Budget: opts.Budget,
+		Tokens: keptUsed + codeUsed + memUsed,
+		Code:   codeFit,
+		Memory: rankedContextItems(mem, append(keptFit, memFit...)),
+	}
+	for _, omitted := range [][]ContextItem{codeOmitted, keptOmitted, memOmitted} {
+		for _, item := range omitted {
+			bundle.Omitted = append(bundle.Omitted, ContextOmission{Kind: item.Kind, Ref: item.Ref, Tokens: item.Tokens})
+		}
+	}
+	bundle.Truncated = len(bundle.Omitted) > 0
+	for _, item := range append(bundle.Code, bundle.Memory...) {
+		bundle.Truncated = bundle.Truncated || item.Summarized
+	}
+	return bundle, nil
+}
+
+// rankedContextItems returns the fitted items in their order in ranked.
+func rankedContextItems(ranked, fitted []ContextItem) []ContextItem {
+	byRef := make(map[string]ContextItem, len(fitted))
+	for _, item := range fitted {
+		byRef[item.Ref] = item
+	}
+	items := make([]ContextItem, 0, len(fitted))
+	for _, item := range ranked {
+		if fit, ok := byRef[item.Ref]; ok {
+			items = append(items, fit)
+		}
+	}
+	return items
+}
+
+// Markdown renders the bundle for pasting into a prompt.
+func (cb *ContextBundle) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Context: %s\n\n", cb.Topic)
+	sb.WriteString("## Code context\n\n")
+	if len(cb.Code) == 0 {
+		sb.WriteString("_No matching code._\n\n")
+	}
+	for _, item := range cb.Code {
+		sb.WriteString(item.Text)
+	}
+	sb.WriteString("## Memory context\n\n")
+	if len(cb.Memory) == 0 {
+		sb.WriteString("_No matching records._\n\n")
+	}
+	for _, item := range cb.Memory {
+		sb.WriteString(item.Text)
+	}
+	if len(cb.Omitted) > 0 {
+		refs := make([]string, len(cb.Omitted))
+		for i, o := range cb.Omitted {
+			refs[i] = fmt.Sprintf("%s `%s`", o.Kind, o.Ref)
+		}
+		fmt.Fprintf(&sb, "_Omitted to fit a budget of %d tokens: %s._\n", cb.Budget, strings.Join(refs, ", "))
+	} else if cb.Truncated {
+		fmt.Fprintf(&sb, "_Summarized to fit a budget of %d tokens._\n", cb.Budget)
+	}
+	return sb.String()
+}
+
+// codeContextItems returns matching symbols, then matching snippets, in the
+// order the oracle ranks them.
+func (b *Butler) codeContextItems(opts ContextBundleOptions) ([]ContextItem, error) {
+	result, err := index.GetContextForTaskWithOptions(b.db, opts.Topic, opts.Limit, &index.ContextOptions{IncludeTests: opts.IncludeTests})
+	if err != nil {
+		return nil, fmt.Errorf("search code: %w", err)
+	}
+
+	var items []ContextItem
+	for _, sym := range result.Symbols {
+		ref := fmt.Sprintf("%s:%d", sym.FilePath, sym.LineStart)
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "### `%s` (%s) — %s\n", sym.Name, sym.Kind, ref)
+		if sym.Signature != "" {
+			fmt.Fprintf(&sb, "```\n%s\n```\n", sym.Signature)
+		}
+		if sym.DocComment != "" {
+			fmt.Fprintf(&sb, "%s\n", strings.TrimSpace(sym.DocComment))
+		}
+		if callers, err := index.GetIncomingCalls(b.db, sym.Name); err == nil && len(callers) > 0 {
+			fmt.Fprintf(&sb, "Called from: %s\n", callSiteList(callers, 5))
+		}
+		sb.WriteString("\n")
+		items = append(items, newContextItem("symbol", ref, sb.String()))
+	}
+	for _, f := range result.Files {
+		if f.Snippet == "" {
+			continue
+		}
+		ref := fmt.Sprintf("%s:%d-%d", f.Path, f.ChunkStart, f.ChunkEnd)
+		text := fmt.Sprintf("### %s\n```%s\n%s\n```\n\n", ref, f.Language, strings.TrimRight(f.Snippet, "\n"))
+		items = append(items, newContextItem("snippet", ref, text))
+	}
+	return items, nil
+}
+
+// memoryContextItems returns the decisions, learnings, and ideas matching
+// the topic, ranked by term match with the configured boosts for decisions
+// and pinned records, which bind future work most strongly. Equal scores
+// keep decisions ahead of learnings and learnings ahead of ideas. Pinned
+// records and the top contextKeptDecisions decisions are marked to be kept.
+func (b *Butler) memoryContextItems(opts ContextBundleOptions) ([]ContextItem, error) {
+	if b.memory == nil {
+		return nil, nil
+	}
+	skip := make(map[string]bool, len(opts.SkipKinds))
+	for _, kind := range opts.SkipKinds {
+		skip[kind] = true
+	}
+
+	var records []memory.RankedRecord
+	texts := make(map[string]string)
+	if !skip["decision"] {
+		decisions, err := b.memory.SearchDecisions(opts.Topic, opts.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("search decisions: %w", err)
+		}
+		for _, d := range decisions {
+			text := fmt.Sprintf("### Decision `%s` (%s)\n%s\n", d.ID, d.Status, d.Content)
+			if d.Rationale != "" {
+				text += fmt.Sprintf("Rationale: %s\n", d.Rationale)
+			}
+			records = append(records, memory.RankedRecord{ID: d.ID, Kind: "decision", Content: d.Content})
+			texts[d.ID] = text + "\n"
+		}
+	}
+
+	if !skip["learning"] {
+		learnings, err := b.memory.SearchLearnings(opts.Topic, opts.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("search learnings: %w", err)
+		}
+		for _, l := range learnings {
+			records = append(records, memory.RankedRecord{ID: l.ID, Kind: "learning", Content: l.Content})
+			texts[l.ID] = fmt.Sprintf("### Learning `%s` (%.0f%% confidence)\n%s\n\n", l.ID, l.Confidence*100, l.Content)
+		}
+	}
+
+	if !skip["idea"] {
+		ideas, err := b.memory.SearchIdeas(opts.Topic, opts.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("search ideas: %w", err)
+		}
+		for _, i := range ideas {
+			records = append(records, memory.RankedRecord{ID: i.ID, Kind: "idea", Content: i.Content})
+			texts[i.ID] = fmt.Sprintf("### Idea `%s` (%s)\n%s\n\n", i.ID, i.Status, i.Content)
+		}
+	}
+
+	rc := b.recallConfig()
+	boost := memory.RelevanceBoost{Decision: rc.DecisionBoost, Pinned: rc.PinnedBoost}
+	if err := b.memory.RankRecords(opts.Topic, records, boost); err != nil {
+		return nil, fmt.Errorf("rank records: %w", err)
+	}
+	items := make([]ContextItem, 0, len(records))
+	topDecisions := 0
+	for _, r := range records {
+		item := newContextItem(r.Kind, r.ID, texts[r.ID])
+		item.Score, item.Pinned = r.Score, r.Pinned
+		item.kept = r.Pinned
+		if r.Kind == "decision" && topDecisions < contextKeptDecisions {
+			item.kept = true
+			topDecisions++
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func newContextItem(kind, ref, text string) ContextItem {
+	return ContextItem{Kind: kind, Ref: ref, Text: text, Tokens: index.EstimateTokens(text)}
+}
+
+// fitContextItems greedily takes items in order while they fit within
+// budget, summarizing an item that does not fit when its summary does, and
+// returns the items taken, the items omitted, and the tokens used. Omitted
+// items are returned in full, not summarized. Unlike recall, nothing is
+// forced in: a bundle is pasted into a prompt, so the budget is a hard
+// limit.
+func fitContextItems(items []ContextItem, budget int) ([]ContextItem, []ContextItem, int) {
+	var fitted, omitted []ContextItem
+	used := 0
+	for _, item := range items {
+		fit := item
+		if used+fit.Tokens > budget {
+			fit = summarizeContextItem(item)
+		}
+		if used+fit.Tokens > budget {
+			omitted = append(omitted, item)
+			continue
+		}
+		fitted = append(fitted, fit)
+		used += fit.Tokens
+	}
+	return fitted, omitted, used
+}
+
+// summarizeContextItem cuts an item to its heading and the first line after
+// it, such as a symbol's signature or a record's opening sentence. Items
+// that would not get shorter are returned as they are.
+func summarizeContextItem(item ContextItem) ContextItem {
+	lines := strings.Split(item.Text, "\n")
+	text := lines[0] + "\n"
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "```") {
+			continue
+		}
+		if runes := []rune(line); len(runes) > contextSummaryChars {
+			line = string(runes[:contextSummaryChars]) + "…"
+		}
+		text += line + "\n"
+		break
+	}
+	text += "\n"
+	tokens := index.EstimateTokens(text)
+	if tokens >= item.Tokens {
+		return item
+	}
+	item.Text, item.Tokens, item.Summarized = text, tokens, true
+	return item
+}
+
+// callSiteList formats up to limit call sites as file:line references.
+func callSiteList(calls []index.CallSite, limit int) string {
+	refs := make([]string, 0, limit)
+	for i, c := range calls {
+		if i == limit {
+			refs = append(refs, fmt.Sprintf("and %d more", len(calls)-limit))
+			break
+		}
+		refs = append(refs, fmt.Sprintf("%s:%d", c.FilePath, c.Line))
+	}
+	return strings.Join(refs, ", ")
+}
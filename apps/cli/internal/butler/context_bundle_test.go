@@ -0,0 +1,27 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFitContextItemsOmitsAtFullSize(t *testing.T) {
+	item := newContextItem("idea", "i1", "### Idea `i1`\n"+strings.Repeat("benchmark the cache first ", 20)+"\n\n")
+	summary := summarizeContextItem(item)
+	if !summary.Summarized || summary.Tokens >= item.Tokens {
+		t.Fatalf("expected a shorter summary, got %+v", summary)
+	}
+
+	fitted, omitted, used := fitContextItems([]ContextItem{item}, summary.Tokens-1)
+	if len(fitted) != 0 || used != 0 || len(omitted) != 1 {
+		t.Fatalf("expected the item to be omitted, got fitted %+v, omitted %+v", fitted, omitted)
+	}
+	if omitted[0].Tokens != item.Tokens || omitted[0].Summarized {
+		t.Errorf("omitted item = %d tokens (summarized %v), want its full %d", omitted[0].Tokens, omitted[0].Summarized, item.Tokens)
+	}
+
+	fitted, _, used = fitContextItems([]ContextItem{item}, summary.Tokens)
+	if len(fitted) != 1 || !fitted[0].Summarized || used != summary.Tokens {
+		t.Errorf("expected the summary to fit, got %+v", fitted)
+	}
+}
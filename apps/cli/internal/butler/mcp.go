@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 )
 
 // MCPMode represents the operational mode of the MCP server.
@@ -48,6 +49,7 @@ var adminOnlyTools = map[string]bool{
 	"recall_outcome":  true, // Marks decisions with outcomes
 	"recall_link":     true, // Links ideas/decisions/learnings
 	"recall_unlink":   true, // Removes links
+	"forget":          true, // Deletes records
 	"recall_obsolete": true, // Marks learnings obsolete
 	"recall_archive":  true, // Archives learnings
 }
@@ -72,6 +74,12 @@ type MCPServer struct {
 	reader *bufio.Reader
 	writer io.Writer
 	mode   MCPMode // Operational mode (agent or human)
+
+	writeMu       sync.Mutex // serializes responses and notifications
+	notifyChanges bool       // advertise and emit resource change notifications
+
+	subMu         sync.Mutex      // guards subscriptions
+	subscriptions map[string]bool // resource URIs the client subscribed to
 }
 
 // JSON-RPC types
@@ -240,6 +248,10 @@ func (s *MCPServer) handleRequest(req jsonRPCRequest) jsonRPCResponse {
 		return s.handleResourcesList(req)
 	case "resources/read":
 		return s.handleResourcesRead(req)
+	case "resources/subscribe":
+		return s.handleResourcesSubscribe(req, true)
+	case "resources/unsubscribe":
+		return s.handleResourcesSubscribe(req, false)
 	case "ping":
 		return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]string{}}
 	default:
@@ -257,7 +269,7 @@ func (s *MCPServer) handleInitialize(req jsonRPCRequest) jsonRPCResponse {
 		ProtocolVersion: "2024-11-05",
 		Capabilities: mcpCapabilities{
 			Tools:     &mcpToolsCap{},
-			Resources: &mcpResourcesCap{},
+			Resources: &mcpResourcesCap{Subscribe: s.notifyChanges, ListChanged: s.notifyChanges},
 		},
 		ServerInfo: mcpServerInfo{
 			Name:    "mind-palace",
@@ -350,6 +362,10 @@ func (s *MCPServer) handleToolsCall(req jsonRPCRequest) jsonRPCResponse {
 	// Recall tools - retrieve knowledge and manage relationships
 	case "recall":
 		return s.toolRecall(req.ID, params.Arguments)
+	case "memory_stats":
+		return s.toolMemoryStats(req.ID, params.Arguments)
+	case "reflect":
+		return s.toolReflect(req.ID, params.Arguments)
 	case "recall_decisions":
 		return s.toolRecallDecisions(req.ID, params.Arguments)
 	case "recall_ideas":
@@ -362,6 +378,14 @@ func (s *MCPServer) handleToolsCall(req jsonRPCRequest) jsonRPCResponse {
 		return s.toolRecallLinks(req.ID, params.Arguments)
 	case "recall_unlink":
 		return s.toolRecallUnlink(req.ID, params.Arguments)
+	case "append":
+		return s.toolAppend(req.ID, params.Arguments)
+	case "update":
+		return s.toolUpdate(req.ID, params.Arguments)
+	case "forget":
+		return s.toolForget(req.ID, params.Arguments)
+	case "review":
+		return s.toolReview(req.ID, params.Arguments)
 
 	// Brief tools - get briefings and file intel
 	case "brief":
@@ -595,7 +619,15 @@ func (s *MCPServer) writeResponse(resp jsonRPCResponse) error {
 	if err != nil {
 		return err
 	}
-	_, err = fmt.Fprintf(s.writer, "%s\n", data)
+	return s.writeLine(data)
+}
+
+// writeLine writes a single JSON-RPC message, guarding against interleaving
+// with notifications sent from other goroutines.
+func (s *MCPServer) writeLine(data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, err := fmt.Fprintf(s.writer, "%s\n", data)
 	return err
 }
 
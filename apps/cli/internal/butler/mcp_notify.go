@@ -0,0 +1,100 @@
+package butler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// jsonRPCNotification is a server-initiated message that expects no response.
+type jsonRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// EnableChangeNotifications makes the server advertise resource change
+// notifications. Used when the server runs alongside a live index watcher.
+func (s *MCPServer) EnableChangeNotifications() {
+	s.notifyChanges = true
+}
+
+// NotifyResourcesChanged tells the client that indexed files changed.
+// It emits notifications/resources/updated for each changed file resource
+// the client subscribed to, and a single notifications/resources/list_changed
+// when files were added or removed.
+func (s *MCPServer) NotifyResourcesChanged(updated []string, listChanged bool) error {
+	if !s.notifyChanges {
+		return nil
+	}
+
+	paths := append([]string(nil), updated...)
+	sort.Strings(paths)
+	for _, p := range paths {
+		uri := "palace://files/" + p
+		if !s.subscribed(uri) {
+			continue
+		}
+		if err := s.writeNotification("notifications/resources/updated", map[string]string{
+			"uri": uri,
+		}); err != nil {
+			return err
+		}
+	}
+	if listChanged {
+		return s.writeNotification("notifications/resources/list_changed", nil)
+	}
+	return nil
+}
+
+// handleResourcesSubscribe adds or removes the resource named by the
+// request's uri from the subscriptions. Subscriptions are only offered while
+// change notifications are enabled.
+func (s *MCPServer) handleResourcesSubscribe(req jsonRPCRequest, subscribe bool) jsonRPCResponse {
+	if !s.notifyChanges {
+		return jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &rpcError{Code: -32601, Message: fmt.Sprintf("Method not found: %s", req.Method)},
+		}
+	}
+	var params mcpResourceReadParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		data := "uri is required"
+		if err != nil {
+			data = err.Error()
+		}
+		return jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &rpcError{Code: -32602, Message: "Invalid params", Data: data},
+		}
+	}
+
+	s.subMu.Lock()
+	if subscribe {
+		if s.subscriptions == nil {
+			s.subscriptions = make(map[string]bool)
+		}
+		s.subscriptions[params.URI] = true
+	} else {
+		delete(s.subscriptions, params.URI)
+	}
+	s.subMu.Unlock()
+	return jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]string{}}
+}
+
+// subscribed reports whether the client subscribed to uri.
+func (s *MCPServer) subscribed(uri string) bool {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	return s.subscriptions[uri]
+}
+
+func (s *MCPServer) writeNotification(method string, params interface{}) error {
+	data, err := json.Marshal(jsonRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	return s.writeLine(data)
+}
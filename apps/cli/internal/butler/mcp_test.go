@@ -14,6 +14,7 @@ import (
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/jsonc"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
 )
 
 func setupMCPServer(t *testing.T) (*MCPServer, *Butler) {
@@ -203,6 +204,45 @@ func TestMCPResourcesRead(t *testing.T) {
 	}
 }
 
+func TestMCPResourcesSubscribe(t *testing.T) {
+	server, _ := setupMCPServer(t)
+	out := server.writer.(*bytes.Buffer)
+	subscribe := func(method, uri string) *rpcError {
+		return server.handleRequest(jsonRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  method,
+			Params:  mustMarshal(t, mcpResourceReadParams{URI: uri}),
+		}).Error
+	}
+
+	if err := subscribe("resources/subscribe", "palace://files/main.go"); err == nil || err.Code != -32601 {
+		t.Fatalf("expected method not found without change notifications, got %+v", err)
+	}
+
+	server.EnableChangeNotifications()
+	if err := subscribe("resources/subscribe", "palace://files/main.go"); err != nil {
+		t.Fatalf("subscribe error = %v", err)
+	}
+	if err := server.NotifyResourcesChanged([]string{"main.go", "other.go"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.Contains(got, "palace://files/main.go") || strings.Contains(got, "palace://files/other.go") {
+		t.Errorf("expected an update for the subscribed file only, got %q", got)
+	}
+
+	out.Reset()
+	if err := subscribe("resources/unsubscribe", "palace://files/main.go"); err != nil {
+		t.Fatalf("unsubscribe error = %v", err)
+	}
+	if err := server.NotifyResourcesChanged([]string{"main.go"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no updates after unsubscribing, got %q", out.String())
+	}
+}
+
 func TestMCPToolHandlersIndex(t *testing.T) {
 	server, _ := setupMCPServer(t)
 
@@ -492,6 +532,36 @@ func TestMCPToolExploreContext(t *testing.T) {
 	}
 }
 
+func TestMCPToolExploreContextBudget(t *testing.T) {
+	server, b := setupMCPServer(t)
+	pinnedID, err := b.memory.AddLearning(memory.Learning{Content: "DoWork retries twice before failing", Scope: "palace", Source: "cli", Confidence: 0.9, Authority: string(memory.AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddLearning() error = %v", err)
+	}
+	if err := b.memory.AddTag(pinnedID, "learning", memory.PinnedTag); err != nil {
+		t.Fatalf("AddTag() error = %v", err)
+	}
+	ideaID, err := b.memory.AddIdea(memory.Idea{
+		Content: "Make DoWork concurrent. " + strings.Repeat("It needs a benchmark against production traffic before anyone commits to it. ", 6),
+		Scope:   "palace",
+		Source:  "cli",
+	})
+	if err != nil {
+		t.Fatalf("AddIdea() error = %v", err)
+	}
+
+	text := toolText(t, server.toolExploreContext(1, map[string]interface{}{
+		"task":      "DoWork",
+		"maxTokens": float64(40),
+	}))
+	if !strings.Contains(text, pinnedID) {
+		t.Errorf("expected the pinned learning %s in the bundle, got:\n%s", pinnedID, text)
+	}
+	if !strings.Contains(text, "_Omitted to fit a budget of 40 tokens") || !strings.Contains(text, "idea `"+ideaID+"`") {
+		t.Errorf("expected idea %s to be listed as omitted, got:\n%s", ideaID, text)
+	}
+}
+
 func TestMCPToolExploreRooms(t *testing.T) {
 	server, _ := setupMCPServer(t)
 
@@ -985,3 +1055,157 @@ func TestMCPDefaultModeIsAgent(t *testing.T) {
 		t.Errorf("NewMCPServer() should default to agent mode, got %q", server.Mode())
 	}
 }
+
+func TestMCPToolStoreIdempotencyKey(t *testing.T) {
+	server, b := setupMCPServer(t)
+
+	args := map[string]interface{}{
+		"content":        "Retry-safe idea for caching",
+		"as":             "idea",
+		"idempotencyKey": "task-42-store-1",
+	}
+	first := extractBetween(toolText(t, server.toolStore(1, args)), "**ID:** `", "`")
+	second := extractBetween(toolText(t, server.toolStore(2, args)), "**ID:** `", "`")
+	if first == "" || first != second {
+		t.Fatalf("expected the same ID for both stores, got %q and %q", first, second)
+	}
+	count, err := b.Memory().CountIdeas("")
+	if err != nil {
+		t.Fatalf("CountIdeas() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 idea, got %d", count)
+	}
+
+	// Proposals: a retry returns the pending proposal instead of a duplicate error
+	args = map[string]interface{}{
+		"content":        "Use connection pooling for the database",
+		"as":             "decision",
+		"idempotencyKey": "task-42-store-2",
+	}
+	first = extractBetween(toolText(t, server.toolStore(3, args)), "**ID:** `", "`")
+	resp := server.toolStore(4, args)
+	if result := resp.Result.(mcpToolResult); result.IsError {
+		t.Fatalf("retry should not fail: %s", toolText(t, resp))
+	}
+	if second = extractBetween(toolText(t, resp), "**ID:** `", "`"); first == "" || first != second {
+		t.Fatalf("expected the same proposal ID, got %q and %q", first, second)
+	}
+	proposals, err := b.Memory().GetProposals("", "", 10)
+	if err != nil {
+		t.Fatalf("GetProposals() error = %v", err)
+	}
+	if len(proposals) != 1 {
+		t.Errorf("expected 1 proposal, got %d", len(proposals))
+	}
+}
+
+func TestMCPToolForgetAndUndo(t *testing.T) {
+	server, b := setupMCPServerWithMode(t, MCPModeHuman)
+	mem := b.Memory()
+
+	// Undoing a store removes the record
+	text := toolText(t, server.toolStore(1, map[string]interface{}{"content": "Try a bloom filter for dedupe", "as": "idea"}))
+	storedID := extractBetween(text, "**ID:** `", "`")
+	if _, err := mem.Undo(1); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if _, err := mem.GetIdea(storedID); err == nil {
+		t.Errorf("undoing store should remove idea %s", storedID)
+	}
+
+	// Undoing a forget restores the record
+	ideaID, _ := mem.AddIdea(memory.Idea{Content: "Shard the sessions table"})
+	resp := server.toolForget(2, map[string]interface{}{"id": ideaID})
+	if text := toolText(t, resp); !strings.Contains(text, "Record Forgotten") {
+		t.Fatalf("toolForget output unexpected: %s", text)
+	}
+	if _, err := mem.GetIdea(ideaID); err == nil {
+		t.Fatal("idea should be deleted after forget")
+	}
+	if _, err := mem.Undo(1); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	idea, err := mem.GetIdea(ideaID)
+	if err != nil || idea.Content != "Shard the sessions table" {
+		t.Errorf("undoing forget should restore the idea, got %v, %v", idea, err)
+	}
+
+	if resp := server.toolForget(3, map[string]interface{}{"id": "i_missing"}); !resp.Result.(mcpToolResult).IsError {
+		t.Error("expected error forgetting a missing record")
+	}
+}
+
+func TestMCPToolAppend(t *testing.T) {
+	server, b := setupMCPServerWithMode(t, MCPModeAgent)
+	mem := b.Memory()
+
+	ideaID, _ := mem.AddIdea(memory.Idea{Content: "Scratchpad"})
+	if text := toolText(t, server.toolAppend(1, map[string]interface{}{"id": ideaID, "content": "Check the retry budget"})); !strings.Contains(text, "Appended to idea") {
+		t.Fatalf("toolAppend output unexpected: %s", text)
+	}
+	if idea, _ := mem.GetIdea(ideaID); !strings.HasSuffix(idea.Content, "Check the retry budget") {
+		t.Errorf("content should end with the addition, got %q", idea.Content)
+	}
+
+	decisionID, _ := mem.AddDecision(memory.Decision{Content: "Use JWT", Authority: string(memory.AuthorityApproved)})
+	if resp := server.toolAppend(2, map[string]interface{}{"id": decisionID, "content": "and rotate keys"}); !resp.Result.(mcpToolResult).IsError {
+		t.Error("agents should not append to decisions")
+	}
+	resp := server.toolAppend(3, map[string]interface{}{"id": "i_missing", "content": "text"})
+	if result := resp.Result.(mcpToolResult); !result.IsError || !strings.Contains(result.Content[0].Text, "not found") {
+		t.Errorf("expected not found appending to a missing record, got %+v", result)
+	}
+}
+
+func TestMCPToolUpdate(t *testing.T) {
+	server, b := setupMCPServerWithMode(t, MCPModeAgent)
+	mem := b.Memory()
+
+	ideaID, _ := mem.AddIdea(memory.Idea{Content: "Cache sessions"})
+	text := toolText(t, server.toolUpdate(1, map[string]interface{}{"id": ideaID, "content": "Cache sessions in Redis", "tags": []interface{}{"cache"}}))
+	if !strings.Contains(text, "previous version is kept") {
+		t.Fatalf("toolUpdate output unexpected: %s", text)
+	}
+	if idea, _ := mem.GetIdea(ideaID); idea.Content != "Cache sessions in Redis" {
+		t.Errorf("content should be replaced, got %q", idea.Content)
+	}
+	if _, versions, _ := mem.RecordHistory(ideaID); len(versions) != 1 || versions[0].Content != "Cache sessions" {
+		t.Errorf("the original should be kept in the history, got %+v", versions)
+	}
+
+	if resp := server.toolUpdate(2, map[string]interface{}{"id": ideaID, "kind": "decision"}); !resp.Result.(mcpToolResult).IsError {
+		t.Error("agents should not change the kind of a record")
+	}
+	decisionID, _ := mem.AddDecision(memory.Decision{Content: "Use JWT", Authority: string(memory.AuthorityApproved)})
+	if resp := server.toolUpdate(3, map[string]interface{}{"id": decisionID, "content": "Use sessions"}); !resp.Result.(mcpToolResult).IsError {
+		t.Error("agents should not edit decisions")
+	}
+}
+
+func TestMCPToolReview(t *testing.T) {
+	server, b := setupMCPServerWithMode(t, MCPModeAgent)
+
+	text := toolText(t, server.toolStore(1, map[string]interface{}{"content": "Cache keys include the tenant"}))
+	if !strings.Contains(text, "queued for review") {
+		t.Fatalf("uncertain store should be queued for review: %s", text)
+	}
+	storedID := extractBetween(text, "**ID:** `", "`")
+
+	text = toolText(t, server.toolReview(2, map[string]interface{}{}))
+	if !strings.Contains(text, storedID) {
+		t.Fatalf("review queue should list %s: %s", storedID, text)
+	}
+
+	text = toolText(t, server.toolReview(3, map[string]interface{}{"id": storedID, "kind": "learning"}))
+	if !strings.Contains(text, "Re-stored") {
+		t.Fatalf("unexpected review output: %s", text)
+	}
+	if flagged, _ := b.Memory().NeedsReview(storedID); flagged {
+		t.Error("review should clear the flag")
+	}
+
+	if resp := server.toolReview(4, map[string]interface{}{"id": storedID}); !resp.Result.(mcpToolResult).IsError {
+		t.Error("expected error reviewing without a kind")
+	}
+}
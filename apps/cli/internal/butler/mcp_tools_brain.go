@@ -3,12 +3,44 @@ package butler
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
 )
 
+// alreadyStored answers a store whose idempotency key already resolves to
+// a record.
+func alreadyStored(id any, key string, existing *memory.IdempotentRecord) jsonRPCResponse {
+	var output strings.Builder
+	output.WriteString("# Already Stored\n\n")
+	fmt.Fprintf(&output, "**ID:** `%s`\n", existing.RecordID)
+	fmt.Fprintf(&output, "**Type:** %s\n", existing.RecordKind)
+	fmt.Fprintf(&output, "\nIdempotency key `%s` was already used on %s; no new record was created.\n",
+		key, existing.CreatedAt.Format("2006-01-02 15:04:05"))
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+// createdOrClaimed splits the result of an idempotent store into the ID of
+// the record it created or, when another store claimed the key first, the
+// record the key resolves to.
+func createdOrClaimed(rec *memory.IdempotentRecord, created bool, err error) (string, *memory.IdempotentRecord, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	if !created {
+		return "", rec, nil
+	}
+	return rec.RecordID, nil, nil
+}
+
 // toolStore stores a thought with auto-classification.
 // Phase 2: Creates a proposal instead of direct record for decisions/learnings.
 // Ideas are still stored directly (no governance for ideas).
@@ -43,6 +75,11 @@ func (s *MCPServer) toolStore(id any, args map[string]interface{}) jsonRPCRespon
 		}
 	}
 
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not initialized")
+	}
+
 	// Determine kind
 	var kind memory.RecordKind
 	var classification memory.Classification
@@ -51,8 +88,8 @@ func (s *MCPServer) toolStore(id any, args map[string]interface{}) jsonRPCRespon
 		kind = memory.RecordKind(kindStr)
 		classification = memory.Classification{Kind: kind, Confidence: 1.0, Signals: []string{"explicit"}}
 	} else {
-		// Auto-classify
-		classification = memory.Classify(content)
+		// Auto-classify, consulting rules learned from past reviews
+		classification = mem.Classify(content)
 		kind = classification.Kind
 	}
 
@@ -60,14 +97,31 @@ func (s *MCPServer) toolStore(id any, args map[string]interface{}) jsonRPCRespon
 	extractedTags := memory.ExtractTags(content)
 	tags = append(tags, extractedTags...)
 
-	mem := s.butler.Memory()
-	if mem == nil {
-		return s.toolError(id, "memory not initialized")
+	lintViolations, rejected, err := s.storeLint(kind, content, rationale, tags)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("memory lint: %v", err))
+	}
+	if rejected {
+		return s.toolError(id, fmt.Sprintf("%s breaks the team's memory conventions and was not stored:\n%s", kind, formatLintViolations(lintViolations)))
+	}
+
+	// A retried store with a known idempotency key returns the original record
+	idempotencyKey, _ := args["idempotencyKey"].(string)
+	if idempotencyKey != "" {
+		existing, err := mem.GetIdempotencyKey(idempotencyKey)
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("check idempotency key failed: %v", err))
+		}
+		if existing != nil {
+			return alreadyStored(id, idempotencyKey, existing)
+		}
 	}
 
 	var recordID string
-	var err error
 	var isProposal bool
+	// Set when a concurrent store with the same key claimed it after the
+	// check above
+	var claimed *memory.IdempotentRecord
 
 	// Phase 2: Decisions and learnings go through proposal workflow
 	// Ideas are stored directly (no governance requirement)
@@ -81,7 +135,11 @@ func (s *MCPServer) toolStore(id any, args map[string]interface{}) jsonRPCRespon
 			ScopePath: scopePath,
 			Source:    "agent",
 		}
-		recordID, err = s.butler.AddIdea(idea)
+		if idempotencyKey == "" {
+			recordID, err = s.butler.AddIdea(idea)
+		} else {
+			recordID, claimed, err = createdOrClaimed(mem.AddIdeaIdempotent(idea, idempotencyKey))
+		}
 
 	case memory.RecordKindDecision, memory.RecordKindLearning:
 		// Decisions and learnings go through proposal workflow
@@ -119,18 +177,32 @@ func (s *MCPServer) toolStore(id any, args map[string]interface{}) jsonRPCRespon
 		}
 		proposal.DedupeKey = dedupeKey
 
-		recordID, err = mem.AddProposal(proposal)
+		if idempotencyKey == "" {
+			recordID, err = mem.AddProposal(proposal)
+		} else {
+			recordID, claimed, err = createdOrClaimed(mem.AddProposalIdempotent(proposal, idempotencyKey))
+		}
 	}
 
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("store %s failed: %v", kind, err))
 	}
+	if claimed != nil {
+		return alreadyStored(id, idempotencyKey, claimed)
+	}
 
 	// Set tags if any (only for ideas, proposals don't have tags yet)
 	if len(tags) > 0 && !isProposal {
 		s.butler.SetTags(recordID, string(kind), tags)
 	}
 
+	// Uncertain auto-classifications of direct records are queued for review;
+	// proposals are reviewed through approval anyway
+	needsReview := false
+	if kindStr == "" && !isProposal {
+		needsReview, _ = mem.FlagForReview(recordID, classification)
+	}
+
 	var output strings.Builder
 	if isProposal {
 		output.WriteString("# Proposal Created\n\n")
@@ -168,6 +240,15 @@ func (s *MCPServer) toolStore(id any, args map[string]interface{}) jsonRPCRespon
 			fmt.Fprintf(&output, "**Tags:** %s\n", strings.Join(tags, ", "))
 		}
 		fmt.Fprintf(&output, "\n**Content:** %s\n", content)
+		if needsReview {
+			output.WriteString("\n_Classification is uncertain; queued for review. Use `review` to confirm or correct the kind._\n")
+		}
+	}
+
+	if len(lintViolations) > 0 {
+		output.WriteString("\n---\n\n## Convention Warnings\n\n")
+		output.WriteString(formatLintViolations(lintViolations))
+		output.WriteString("\nSee memoryLint in palace.jsonc; run `palace lint-memory` to check existing records.\n")
 	}
 
 	// Auto-check for contradictions if enabled (only for non-proposals)
@@ -274,20 +355,39 @@ func (s *MCPServer) toolRecallDecisions(id any, args map[string]interface{}) jso
 	if l, ok := args["limit"].(float64); ok {
 		limit = int(l)
 	}
+	consensus, _ := args["consensus"].(bool)
+
+	// Consensus groups every matching decision by topic; the limit then
+	// applies to topics.
+	fetch := limit
+	if consensus {
+		fetch = 0
+	}
 
 	var decisions []memory.Decision
 	var err error
 
 	if query != "" {
-		decisions, err = s.butler.SearchDecisions(query, limit)
+		decisions, err = s.butler.SearchDecisions(query, fetch)
 	} else {
-		decisions, err = s.butler.GetDecisions(status, scope, scopePath, limit)
+		decisions, err = s.butler.GetDecisions(status, scope, scopePath, fetch)
 	}
 
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("get decisions failed: %v", err))
 	}
 
+	if consensus {
+		return s.recallDecisionConsensus(id, decisions, limit)
+	}
+
+	var notes []string
+	if flag, _ := args["flagContradictions"].(bool); flag {
+		if notes, err = s.contradictionNotes(decisions); err != nil {
+			return s.toolError(id, fmt.Sprintf("check contradictions failed: %v", err))
+		}
+	}
+
 	var output strings.Builder
 	output.WriteString("# Decisions\n\n")
 
@@ -295,37 +395,54 @@ func (s *MCPServer) toolRecallDecisions(id any, args map[string]interface{}) jso
 		output.WriteString("No decisions found.\n")
 	} else {
 		for i := range decisions {
-			d := &decisions[i]
-			statusIcon := "🔵"
-			switch d.Status {
-			case memory.DecisionStatusSuperseded:
-				statusIcon = "🔄"
-			case memory.DecisionStatusReversed:
-				statusIcon = "↩️"
+			note := ""
+			if notes != nil {
+				note = notes[i]
 			}
+			output.WriteString(formatDecisionEntry(&decisions[i], note))
+		}
+	}
 
-			outcomeIcon := "❓"
-			switch d.Outcome {
-			case memory.DecisionOutcomeSuccessful:
-				outcomeIcon = "✅"
-			case memory.DecisionOutcomeFailed:
-				outcomeIcon = "❌"
-			case memory.DecisionOutcomeMixed:
-				outcomeIcon = "⚖️"
-			}
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
 
-			fmt.Fprintf(&output, "## %s `%s` %s\n\n", statusIcon, d.ID, outcomeIcon)
-			fmt.Fprintf(&output, "**Status:** %s | **Outcome:** %s\n", d.Status, d.Outcome)
-			scopeInfo := d.Scope
-			if d.ScopePath != "" {
-				scopeInfo = fmt.Sprintf("%s:%s", d.Scope, d.ScopePath)
-			}
-			fmt.Fprintf(&output, "**Scope:** %s\n", scopeInfo)
-			fmt.Fprintf(&output, "**Content:** %s\n", d.Content)
-			if d.Rationale != "" {
-				fmt.Fprintf(&output, "**Rationale:** %s\n", d.Rationale)
+// recallDecisionConsensus renders the current decision of each topic, with
+// the earlier decisions on it collapsed behind a count.
+func (s *MCPServer) recallDecisionConsensus(id any, decisions []memory.Decision, limit int) jsonRPCResponse {
+	groups, err := s.butler.memory.DecisionConsensus(decisions)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("group decisions failed: %v", err))
+	}
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+
+	var output strings.Builder
+	output.WriteString("# Decisions (consensus)\n\n")
+	if len(groups) == 0 {
+		output.WriteString("No decisions found.\n")
+	}
+	for i := range groups {
+		g := &groups[i]
+		marker := ""
+		if len(g.Earlier) > 0 {
+			marker = fmt.Sprintf("(%d earlier)", len(g.Earlier))
+		}
+		entry := formatDecisionEntry(&g.Current, marker)
+		entry = strings.Replace(entry, "**Status:**", "**Topic:** "+g.Topic+"\n**Status:**", 1)
+		output.WriteString(entry)
+		if len(g.Earlier) > 0 {
+			ids := make([]string, len(g.Earlier))
+			for j := range g.Earlier {
+				ids[j] = fmt.Sprintf("`%s` (%s)", g.Earlier[j].ID, g.Earlier[j].CreatedAt.Format("2006-01-02"))
 			}
-			fmt.Fprintf(&output, "**Created:** %s\n\n", d.CreatedAt.Format(time.RFC3339))
+			fmt.Fprintf(&output, "_Earlier:_ %s\n\n", strings.Join(ids, ", "))
 		}
 	}
 
@@ -338,6 +455,81 @@ func (s *MCPServer) toolRecallDecisions(id any, args map[string]interface{}) jso
 	}
 }
 
+// contradictionNotes returns, for each of decisions, a note naming the other
+// results it conflicts with, as reflect finds conflicts: linked as
+// contradicting, or active on the same topic. Results are numbered from 1
+// in the order given; a decision without conflicts gets "".
+func (s *MCPServer) contradictionNotes(decisions []memory.Decision) ([]string, error) {
+	pairs, err := s.butler.memory.DecisionConflictPairs(decisions)
+	if err != nil {
+		return nil, err
+	}
+	position := make(map[string]int, len(decisions))
+	for i := range decisions {
+		position[decisions[i].ID] = i
+	}
+	conflicts := make([][]memory.DecisionConflictPair, len(decisions))
+	for _, p := range pairs {
+		a, b := position[p.A.ID], position[p.B.ID]
+		conflicts[a] = append(conflicts[a], p)
+		conflicts[b] = append(conflicts[b], memory.DecisionConflictPair{A: p.B, B: p.A, Reason: p.Reason})
+	}
+	notes := make([]string, len(decisions))
+	for i, c := range conflicts {
+		if len(c) == 0 {
+			continue
+		}
+		sort.Slice(c, func(x, y int) bool { return position[c[x].B.ID] < position[c[y].B.ID] })
+		others := make([]string, len(c))
+		for j, p := range c {
+			others[j] = fmt.Sprintf("#%d `%s` (%s)", position[p.B.ID]+1, p.B.ID, p.Reason)
+		}
+		notes[i] = "⚠ conflicts with result " + strings.Join(others, ", ")
+	}
+	return notes, nil
+}
+
+// formatDecisionEntry renders a decision as a recall_decisions entry, with
+// note appended to its heading when set.
+func formatDecisionEntry(d *memory.Decision, note string) string {
+	statusIcon := "🔵"
+	switch d.Status {
+	case memory.DecisionStatusSuperseded:
+		statusIcon = "🔄"
+	case memory.DecisionStatusReversed:
+		statusIcon = "↩️"
+	}
+
+	outcomeIcon := "❓"
+	switch d.Outcome {
+	case memory.DecisionOutcomeSuccessful:
+		outcomeIcon = "✅"
+	case memory.DecisionOutcomeFailed:
+		outcomeIcon = "❌"
+	case memory.DecisionOutcomeMixed:
+		outcomeIcon = "⚖️"
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "## %s `%s` %s", statusIcon, d.ID, outcomeIcon)
+	if note != "" {
+		output.WriteString(" " + note)
+	}
+	output.WriteString("\n\n")
+	fmt.Fprintf(&output, "**Status:** %s | **Outcome:** %s\n", d.Status, d.Outcome)
+	scopeInfo := d.Scope
+	if d.ScopePath != "" {
+		scopeInfo = fmt.Sprintf("%s:%s", d.Scope, d.ScopePath)
+	}
+	fmt.Fprintf(&output, "**Scope:** %s\n", scopeInfo)
+	fmt.Fprintf(&output, "**Content:** %s\n", d.Content)
+	if d.Rationale != "" {
+		fmt.Fprintf(&output, "**Rationale:** %s\n", d.Rationale)
+	}
+	fmt.Fprintf(&output, "**Created:** %s\n\n", d.CreatedAt.Format(time.RFC3339))
+	return output.String()
+}
+
 // toolRecallIdeas retrieves ideas from the brain.
 func (s *MCPServer) toolRecallIdeas(id any, args map[string]interface{}) jsonRPCResponse {
 	query, _ := args["query"].(string)
@@ -362,6 +554,11 @@ func (s *MCPServer) toolRecallIdeas(id any, args map[string]interface{}) jsonRPC
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("get ideas failed: %v", err))
 	}
+	ids := make([]string, len(ideas))
+	for i := range ideas {
+		ids[i] = ideas[i].ID
+	}
+	_ = s.butler.memory.RecordAccess("idea", ids...)
 
 	var output strings.Builder
 	output.WriteString("# Ideas\n\n")
@@ -608,6 +805,187 @@ func (s *MCPServer) toolRecallUnlink(id any, args map[string]interface{}) jsonRP
 	}
 }
 
+// toolForget deletes an idea, decision, learning, or proposal by ID.
+func (s *MCPServer) toolForget(id any, args map[string]interface{}) jsonRPCResponse {
+	recordID, _ := args["id"].(string)
+	if recordID == "" {
+		return s.toolError(id, "id is required")
+	}
+
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not initialized")
+	}
+	kind, err := mem.Forget(recordID)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("forget failed: %v", err))
+	}
+
+	var output strings.Builder
+	output.WriteString("# Record Forgotten\n\n")
+	fmt.Fprintf(&output, "Deleted %s `%s` with its links and tags.\n", kind, recordID)
+	output.WriteString("Run `palace memory undo` to restore it.\n")
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+// toolAppend appends content to a record in place. Agents may only grow
+// ideas; decisions and learnings change through proposals.
+func (s *MCPServer) toolAppend(id any, args map[string]interface{}) jsonRPCResponse {
+	recordID, _ := args["id"].(string)
+	if recordID == "" {
+		return s.toolError(id, "id is required")
+	}
+	content, _ := args["content"].(string)
+	if strings.TrimSpace(content) == "" {
+		return s.toolError(id, "content is required")
+	}
+
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not initialized")
+	}
+	var kinds []string
+	if s.mode == MCPModeAgent {
+		kinds = []string{"idea"}
+	}
+	kind, err := mem.AppendContent(recordID, content, kinds...)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("append failed: %v", err))
+	}
+
+	var output strings.Builder
+	output.WriteString("# Appended\n\n")
+	fmt.Fprintf(&output, "Appended to %s `%s`; its ID, tags, and links are unchanged.\n", kind, recordID)
+	output.WriteString("Run `palace memory undo` to take the addition back.\n")
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+// toolUpdate edits a record's content, kind, or tags, keeping its prior
+// state in the record's history. Agents may only edit ideas and may not
+// change their kind; decisions and learnings change through proposals.
+func (s *MCPServer) toolUpdate(id any, args map[string]interface{}) jsonRPCResponse {
+	recordID, _ := args["id"].(string)
+	if recordID == "" {
+		return s.toolError(id, "id is required")
+	}
+	var edit memory.RecordEdit
+	edit.Content, _ = args["content"].(string)
+	if kind, _ := args["kind"].(string); kind != "" {
+		edit.Kind = memory.RecordKind(kind)
+	}
+	if tagsRaw, ok := args["tags"].([]interface{}); ok {
+		edit.Tags = []string{}
+		for _, t := range tagsRaw {
+			if tag, ok := t.(string); ok && tag != "" {
+				edit.Tags = append(edit.Tags, tag)
+			}
+		}
+	}
+
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not initialized")
+	}
+	var kinds []string
+	if s.mode == MCPModeAgent {
+		if edit.Kind != "" && edit.Kind != memory.RecordKindIdea {
+			return s.toolError(id, "agents cannot change the kind of a record; store a proposal instead")
+		}
+		kinds = []string{"idea"}
+	}
+	newID, err := mem.EditRecord(recordID, edit, kinds...)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("update failed: %v", err))
+	}
+
+	var output strings.Builder
+	output.WriteString("# Updated\n\n")
+	if newID != recordID {
+		fmt.Fprintf(&output, "Re-stored `%s` as %s `%s`; its tags, links, and history moved with it.\n", recordID, edit.Kind, newID)
+	} else {
+		fmt.Fprintf(&output, "Updated `%s`; the previous version is kept in its history.\n", recordID)
+	}
+	fmt.Fprintf(&output, "Run `palace memory history %s` to list past versions or restore one.\n", newID)
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+// toolReview lists records whose auto-classification was uncertain, or
+// confirms/corrects the kind of one of them.
+func (s *MCPServer) toolReview(id any, args map[string]interface{}) jsonRPCResponse {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not initialized")
+	}
+
+	var output strings.Builder
+	if recordID, _ := args["id"].(string); recordID != "" {
+		kind, _ := args["kind"].(string)
+		if kind == "" {
+			return s.toolError(id, "kind is required when reviewing a record")
+		}
+		newID, err := mem.ResolveReview(recordID, memory.RecordKind(kind))
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("review failed: %v", err))
+		}
+		output.WriteString("# Classification Reviewed\n\n")
+		if newID == recordID {
+			fmt.Fprintf(&output, "Confirmed `%s` as %s.\n", recordID, kind)
+		} else {
+			fmt.Fprintf(&output, "Re-stored `%s` as %s `%s`, keeping its tags and links.\n", recordID, kind, newID)
+		}
+		output.WriteString("Future records phrased the same way will be classified accordingly.\n")
+	} else {
+		limit := 20
+		if l, ok := args["limit"].(float64); ok && l > 0 {
+			limit = int(l)
+		}
+		items, err := mem.GetReviewQueue(limit)
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("get review queue failed: %v", err))
+		}
+		output.WriteString("# Review Queue\n\n")
+		if len(items) == 0 {
+			output.WriteString("No records need review.\n")
+		}
+		for _, item := range items {
+			fmt.Fprintf(&output, "- `%s` classified as **%s** (%.0f%% confidence): %s\n",
+				item.RecordID, item.RecordKind, item.Confidence*100, truncateSnippet(item.Content, 100))
+		}
+		if len(items) > 0 {
+			output.WriteString("\nCall `review` with `id` and `kind` to confirm or correct a classification.\n")
+		}
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
 // ============================================================================
 // Learning Lifecycle Tools
 // ============================================================================
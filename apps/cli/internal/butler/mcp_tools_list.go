@@ -102,7 +102,7 @@ Assembling complete context for a specific goal. This is more comprehensive than
 					},
 					"maxTokens": map[string]interface{}{
 						"type":        "integer",
-						"description": "Token budget for the response. Context will be truncated to fit within this budget.",
+						"description": "Token budget for the response. With a budget the result is a ranked bundle: pinned records and the top decisions are kept first, other entries are summarized or omitted to fit, and the omitted entries are listed.",
 					},
 					"includeTests": map[string]interface{}{
 						"type":        "boolean",
@@ -180,7 +180,7 @@ Getting a catalog of symbols by type. Useful for overview and discovery of avail
 					"kind": map[string]interface{}{
 						"type":        "string",
 						"description": "Symbol kind: 'class', 'interface', 'function', 'method', 'constant', 'type', 'enum', 'property', 'constructor'.",
-						"enum":        []string{"class", "interface", "function", "method", "constant", "type", "enum", "property", "constructor"},
+						"enum":        []string{"class", "interface", "function", "method", "constant", "type", "type_alias", "enum", "property", "constructor"},
 					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
@@ -211,7 +211,7 @@ Getting full details about a specific named entity. Returns signature, documenta
 				"properties": map[string]interface{}{
 					"name": map[string]interface{}{
 						"type":        "string",
-						"description": "Name of the symbol to look up.",
+						"description": "Name of the symbol to look up. Add parameter types to pick one overload, e.g. 'add(int, int)'.",
 					},
 					"file": map[string]interface{}{
 						"type":        "string",
@@ -446,6 +446,10 @@ Agents should call this automatically after completing tasks, solving problems,
 						"description": "For learnings: confidence level 0.0-1.0 (default: 0.5).",
 						"default":     0.5,
 					},
+					"idempotencyKey": map[string]interface{}{
+						"type":        "string",
+						"description": "Optional client-supplied key that makes retries safe: storing again with a key already used in this workspace returns the existing record and ID instead of creating a new one.",
+					},
 				},
 				"required": []string{"content"},
 			},
@@ -599,7 +603,18 @@ Agents should proactively search learnings when working in areas that might have
 
 **EXAMPLES:**
 - recall({query: 'authentication'}) - Find auth-related learnings
-- recall({scope: 'file', scopePath: 'auth/jwt.go'}) - File-specific learnings`,
+- recall({query: '(redis OR memcached) AND caching'}) - Boolean query over learning content
+- recall({scope: 'file', scopePath: 'auth/jwt.go'}) - File-specific learnings
+- recall({query: 'database', countOnly: true}) - How many learnings mention the database
+- recall({tag: 'perf', tagMode: 'prefix'}) - Learnings tagged performance, perf-regression, ...
+- recall({tag: {all: ['db', 'perf'], none: ['obsolete']}}) - Tagged db and perf but not obsolete
+- recall({ids: ['lrn_abc', 'dec_xyz']}) - Fetch records captured from an earlier store or recall
+- recall({query: 'cache', template: 'table'}) - Results as a markdown table
+- recall({template: '{{.ID}} {{.Kind}}'}) - One line per record with just its ID and kind
+- recall({anchorStatus: 'stale'}) - Learnings whose anchored code is gone from the index
+- recall({evolution: 'caching'}) - How thinking about caching changed over time
+- recall({narrative: true, since: '7d'}) - What was decided, explored, and learned this week, as standup notes
+- recall({query: 'cache', minScore: 0.2}) - Only learnings that are mostly about caching`,
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -607,9 +622,14 @@ Agents should proactively search learnings when working in areas that might have
 						"type":        "string",
 						"description": "ID of a specific learning to retrieve. If provided, returns only that record.",
 					},
+					"ids": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "IDs of learnings, decisions, or ideas to retrieve, returned in the order given. IDs that don't exist are listed as not found. Other filters are ignored.",
+					},
 					"query": map[string]interface{}{
 						"type":        "string",
-						"description": "Optional search query to filter learnings.",
+						"description": "Optional search query to filter learnings. Capitalized AND/OR/NOT, a -term, or a balanced \"quoted phrase\" make it a boolean query: bare terms are ANDed, quoted phrases match the exact word sequence, parentheses group, and NOT (or -) binds tighter than AND, AND tighter than OR. A query that is not valid boolean syntax is searched as plain text. A term written term^factor, like redis^2, is boosted: it ranks records mentioning it higher without being required.",
 					},
 					"scope": map[string]interface{}{
 						"type":        "string",
@@ -620,10 +640,178 @@ Agents should proactively search learnings when working in areas that might have
 						"type":        "string",
 						"description": "Filter by scope path.",
 					},
+					"tag": map[string]interface{}{
+						"type":        []string{"string", "array", "object"},
+						"description": "Only return learnings carrying this tag, matched according to tagMode. A list of tags requires all of them. An object combines conditions: {\"all\": [...]} requires every tag, {\"any\": [...]} at least one, and {\"none\": [...]} excludes learnings carrying any of them, e.g. {\"all\": [\"db\"], \"none\": [\"obsolete\"]}.",
+						"items":       map[string]interface{}{"type": "string"},
+						"properties": map[string]interface{}{
+							"all":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+							"any":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+							"none": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						},
+					},
+					"tagMode": map[string]interface{}{
+						"type":        "string",
+						"description": "How 'tag' is matched: 'exact' (default), 'prefix' ('perf' matches 'performance'), or 'fuzzy' (also tolerates typos such as 'databse').",
+						"enum":        []string{"exact", "prefix", "fuzzy"},
+						"default":     "exact",
+					},
+					"asQuestions": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Spaced review: return learnings framed as review questions ('Do you remember: ...'), least recently reviewed first, and record them as reviewed. Honors scope, scopePath, and limit.",
+						"default":     false,
+					},
+					"sinceLastSession": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Catch up: return the decisions, learnings, and ideas created or modified since the last catch-up, newest first, then advance the marker. limit caps each kind.",
+						"default":     false,
+					},
+					"narrative": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Timeline summary: narrate the decisions, ideas, and learnings created between since and until, oldest first, grouped by day or week ('This week you decided ..., explored ..., learned ...'). Rewritten as prose by the configured LLM, templated otherwise. limit caps each kind, keeping the most recent (default 100); other filters are ignored.",
+						"default":     false,
+					},
+					"since": map[string]interface{}{
+						"type":        "string",
+						"description": "With narrative: start of the range, as a date (2026-01-31), an RFC 3339 time, or a duration back from now (24h, 7d, 2w). Default: 7d.",
+					},
+					"until": map[string]interface{}{
+						"type":        "string",
+						"description": "With narrative: end of the range, in the same forms as since; a date includes that day. Default: now.",
+					},
+					"period": map[string]interface{}{
+						"type":        "string",
+						"description": "With narrative: group records by 'day' or 'week'. Default: by day for ranges up to two weeks, by week beyond.",
+						"enum":        []string{"day", "week"},
+					},
+					"evolution": map[string]interface{}{
+						"type":        "string",
+						"description": "Topic to trace: returns the decisions, learnings, and ideas about it oldest first, pulling in decisions linked by 'supersedes', each with what changed since the one before (superseded records, words added and dropped, similarity). limit caps each kind.",
+					},
+					"workspaces": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Recall across workspaces: this one (by directory name) and those declared under relatedWorkspaces in palace.jsonc. Returns learnings and active decisions labeled by origin workspace; query, scope, and scopePath apply in each.",
+					},
+					"related": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Recall across this workspace and every related workspace declared in palace.jsonc. Same as listing them all in workspaces.",
+						"default":     false,
+					},
+					"pinnedOnly": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Pinboard: return only records tagged 'pinned', in the order they were pinned. query, scope, and scopePath narrow the list when given; other filters are ignored.",
+						"default":     false,
+					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
-						"description": "Maximum learnings to return (default: 10).",
-						"default":     10,
+						"description": "Maximum learnings to return. If omitted, results are capped by the configured token budget (recall.defaultTokenBudget, default ~2000 tokens).",
+						"minimum":     1,
+					},
+					"cursor": map[string]interface{}{
+						"type":        "integer",
+						"description": "Offset to continue from, as reported in the 'more available' note of a previous call.",
+					},
+					"anchorStatus": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter code-anchored learnings (scoped to a file or linked to code) against the current index: 'live' keeps those whose files and line ranges still exist, 'stale' those with an anchor that no longer does, 'any' (default) applies no filter. 'live' and 'stale' drop learnings with no code anchor.",
+						"enum":        []string{"live", "stale", "any"},
+						"default":     "any",
+					},
+					"minScore": map[string]interface{}{
+						"type":        "number",
+						"description": "Relevance threshold from 0 to 1: rank learnings by how closely they match the query (the share of their words that are query terms, after stemming and synonyms) and drop those scoring below it. When nothing scores high enough the result says so instead of returning weak matches. Requires a plain query.",
+						"minimum":     0,
+						"maximum":     1,
+					},
+					"collapseByAnchor": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return only the most recent learning per anchor (the file or room it is scoped to), noting how many were collapsed as '(+N more)'.",
+						"default":     false,
+					},
+					"countOnly": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return only the number of matching learnings, not their content. All other filters still apply; limit and cursor are ignored.",
+						"default":     false,
+					},
+					"breakdown": map[string]interface{}{
+						"type":        "boolean",
+						"description": "With countOnly, also break the count down by scope and by tag.",
+						"default":     false,
+					},
+					"template": map[string]interface{}{
+						"type":        "string",
+						"description": "Output format: 'markdown' (default), 'list', 'table', 'json', or a Go text/template executed once per record, with fields .ID, .Kind, .Content, .Scope, .ScopePath, .Confidence, .Source, .Authority, .UseCount, .CreatedAt, and .LastUsed. Invalid templates fail with an error.",
+					},
+				},
+			},
+		},
+		{
+			Name: "memory_stats",
+			Description: `🟢 **RECOMMENDED** Summarize the knowledge base: records by kind and scope, tag frequencies, records created per week, and average content length.
+
+**WHEN TO USE:**
+- When user asks how much has been recorded, or about what
+- To find the most used tags before filtering recall by tag
+- To see whether the knowledge base is still growing
+
+**AUTONOMOUS BEHAVIOR:**
+Optional. Use when user asks about the shape or growth of the knowledge base.
+
+**BEST FOR:**
+A one-call overview of the store's contents and growth.`,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"topTags": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of most used tags to list (default: 20, 0 for all).",
+						"default":     20,
+					},
+				},
+			},
+		},
+		{
+			Name: "reflect",
+			Description: `🟢 **RECOMMENDED** Look over a slice of the knowledge base and get concrete suggestions: conflicting active decisions to resolve, tagged ideas that never became decisions, decisions with no recorded outcome, and weak learnings.
+
+**WHEN TO USE:**
+- Before planning work in a room, to clear up what it already knows
+- When user asks what needs attention in the knowledge base
+- As periodic maintenance, scoped to the area being worked on
+
+**AUTONOMOUS BEHAVIOR:**
+Optional. Each suggestion names its records and the tool to act with, so it can be worked through directly.
+
+**BEST FOR:**
+Turning an unwieldy store into a short to-do list for one room, tag, or kind of record.`,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"scope": map[string]interface{}{
+						"type":        "string",
+						"description": "Only look at records of this scope.",
+						"enum":        []string{"file", "room", "palace"},
+					},
+					"scopePath": map[string]interface{}{
+						"type":        "string",
+						"description": "Only look at records with this scope path (room name or file path).",
+					},
+					"tags": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only look at records carrying at least one of these tags.",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Only look at one kind of record.",
+						"enum":        []string{"idea", "decision", "learning"},
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Output format: 'markdown' (default) or 'json' (the suggestions with their type, message, recordIds, and action).",
+						"enum":        []string{"markdown", "json"},
+						"default":     "markdown",
 					},
 				},
 			},
@@ -643,7 +831,9 @@ Agents should check for relevant decisions before making new ones. This prevents
 
 **EXAMPLES:**
 - recall_decisions({query: 'database'}) - Find DB-related decisions
-- recall_decisions({status: 'active', scope: 'room', scopePath: 'api'}) - Active API decisions`,
+- recall_decisions({status: 'active', scope: 'room', scopePath: 'api'}) - Active API decisions
+- recall_decisions({query: 'database', consensus: true}) - Current database decision per topic
+- recall_decisions({query: 'database', flagContradictions: true}) - Flag results that conflict with each other`,
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -671,9 +861,19 @@ Agents should check for relevant decisions before making new ones. This prevents
 					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
-						"description": "Maximum decisions to return (default: 10).",
+						"description": "Maximum decisions to return (default: 10); with consensus, maximum topics.",
 						"default":     10,
 					},
+					"consensus": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Group decisions by topic and return only the current one per topic (active over superseded, then newest), with earlier ones collapsed behind a count.",
+						"default":     false,
+					},
+					"flagContradictions": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Mark each active decision that conflicts with another returned one, by a 'contradicts' link or by sharing its topic, e.g. '⚠ conflicts with result #2'.",
+						"default":     false,
+					},
 				},
 			},
 		},
@@ -702,8 +902,8 @@ Agents can check ideas when relevant, but it's not critical for most workflows.
 					},
 					"status": map[string]interface{}{
 						"type":        "string",
-						"description": "Filter by status: 'active', 'exploring', 'implemented', 'dropped'.",
-						"enum":        []string{"active", "exploring", "implemented", "dropped"},
+						"description": "Filter by status: 'active', 'exploring', 'implemented', 'dropped', or 'archived' (hidden unless asked for).",
+						"enum":        []string{"active", "exploring", "implemented", "dropped", "archived"},
 					},
 					"scope": map[string]interface{}{
 						"type":        "string",
@@ -850,6 +1050,116 @@ Maintains knowledge graph accuracy by removing incorrect relationships.`,
 				"required": []string{"linkId"},
 			},
 		},
+		{
+			Name: "append",
+			Description: `🟡 Append to an existing record's content in place, after a timestamped separator.
+
+**WHEN TO USE:**
+- To grow a running note, such as a room's scratchpad, without recalling and re-storing it
+- To add a follow-up to an idea as it develops
+
+**WHY IT MATTERS:**
+The record keeps its ID, tags, and links, so everything pointing at the note still finds it. In agent mode only ideas can be appended to; decisions and learnings change through proposals. Appends are journaled and can be undone with 'palace memory undo'.`,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the record to append to.",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "Text to append.",
+					},
+				},
+				"required": []string{"id", "content"},
+			},
+		},
+		{
+			Name: "update",
+			Description: `🟡 Edit an existing record's content, kind, or tags, keeping the previous version.
+
+**WHEN TO USE:**
+- To correct the wording of a record instead of forgetting and re-storing it
+- To retag a record, or fix a record stored as the wrong kind
+
+**WHY IT MATTERS:**
+Each edit pushes the record's prior state onto its history, so the original wording is never lost; 'palace memory history <id>' lists past versions and restores one. Content and tag edits keep the record's ID, tags, and links; a kind change re-stores it under a new ID. In agent mode only ideas can be edited, and their kind cannot change.`,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the record to edit.",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "New content (omit to keep the current content).",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"idea", "decision", "learning"},
+						"description": "New kind (omit to keep the current kind).",
+					},
+					"tags": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Tags replacing all current tags (omit to keep them; an empty list clears them).",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+		{
+			Name: "forget",
+			Description: `⚪ [HUMAN MODE ONLY] Delete an idea, decision, learning, or proposal by ID, together with its links and tags.
+
+**WHEN TO USE:**
+- When a record is wrong, duplicated, or no longer relevant
+- When user says 'forget that'
+
+**WHY IT MATTERS:**
+Every deletion is journaled; 'palace memory undo' restores the record with its links and tags.`,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the record to delete.",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+		{
+			Name: "review",
+			Description: `🟡 List records whose auto-classification was uncertain, or confirm/correct the kind of one.
+
+**WHEN TO USE:**
+- When a store reports that its classification was queued for review
+- To clear the review queue with the user
+
+**WHY IT MATTERS:**
+Corrections become classification rules for records phrased the same way. A rule is tentative until a second review agrees with it; until then the records it files are still queued for review.`,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of a queued record to resolve. Omit to list the queue.",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "The correct kind for the record; the same kind confirms it, a different one re-stores it.",
+						"enum":        []string{"idea", "decision", "learning"},
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum queued records to list (default 20).",
+					},
+				},
+			},
+		},
 
 		// ============================================================
 		// BRIEF TOOLS - Get briefings and file intelligence
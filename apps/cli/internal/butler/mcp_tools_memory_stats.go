@@ -0,0 +1,76 @@
+package butler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// toolMemoryStats summarizes the knowledge store's shape and growth.
+func (s *MCPServer) toolMemoryStats(id any, args map[string]interface{}) jsonRPCResponse {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not available")
+	}
+	topTags := 20
+	if n, ok := args["topTags"].(float64); ok && n >= 0 {
+		topTags = int(n)
+	}
+
+	stats, err := mem.Stats()
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("memory stats: %v", err))
+	}
+
+	var output strings.Builder
+	output.WriteString("# Memory Statistics\n\n")
+	fmt.Fprintf(&output, "- **Records:** %d\n", stats.Total)
+	fmt.Fprintf(&output, "- **Average length:** %.0f characters\n", stats.AvgContentLength)
+
+	output.WriteString("\n## By Kind\n\n")
+	for _, kind := range []string{"decision", "idea", "learning"} {
+		fmt.Fprintf(&output, "- %s: %d\n", kind, stats.ByKind[kind])
+	}
+	output.WriteString("\n## By Scope\n\n")
+	scopes := make([]string, 0, len(stats.ByScope))
+	for scope := range stats.ByScope {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		fmt.Fprintf(&output, "- %s: %d\n", scope, stats.ByScope[scope])
+	}
+
+	output.WriteString("\n## Tags\n\n")
+	tags := stats.Tags
+	if topTags > 0 && len(tags) > topTags {
+		tags = tags[:topTags]
+	}
+	if len(tags) == 0 {
+		output.WriteString("No tags.\n")
+	}
+	for _, tc := range tags {
+		fmt.Fprintf(&output, "- `%s`: %d\n", tc.Tag, tc.Count)
+	}
+	if len(tags) < len(stats.Tags) {
+		fmt.Fprintf(&output, "- _... %d more_\n", len(stats.Tags)-len(tags))
+	}
+
+	output.WriteString("\n## Records per Week\n\n")
+	if len(stats.Weekly) == 0 {
+		output.WriteString("No records yet.\n")
+	} else {
+		output.WriteString("| Week of | Records |\n|---|---|\n")
+		for _, w := range stats.Weekly {
+			fmt.Fprintf(&output, "| %s | %d |\n", w.Week, w.Count)
+		}
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
@@ -50,11 +50,39 @@ func (s *MCPServer) toolExploreContext(id any, args map[string]interface{}) json
 		includeDecisions = id
 	}
 
+	// A token budget gets the same bundle as palace context: pinned records
+	// and the top decisions first, other entries summarized or omitted to
+	// fit, and the omissions listed
+	if maxTokens > 0 {
+		var skip []string
+		for kind, include := range map[string]bool{"learning": includeLearnings, "idea": includeIdeas, "decision": includeDecisions} {
+			if !include {
+				skip = append(skip, kind)
+			}
+		}
+		bundle, err := s.butler.BuildContextBundle(ContextBundleOptions{
+			Topic:        task,
+			Budget:       maxTokens,
+			Limit:        limit,
+			IncludeTests: includeTests,
+			SkipKinds:    skip,
+		})
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("get context failed: %v", err))
+		}
+		return jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      id,
+			Result: mcpToolResult{
+				Content: []mcpContent{{Type: "text", Text: bundle.Markdown()}},
+			},
+		}
+	}
+
 	// Use enhanced context with memory data
 	opts := EnhancedContextOptions{
 		Query:            task,
 		Limit:            limit,
-		MaxTokens:        maxTokens,
 		IncludeTests:     includeTests,
 		IncludeLearnings: includeLearnings,
 		IncludeFileIntel: includeFileIntel,
@@ -415,6 +443,9 @@ func (s *MCPServer) toolExploreSymbol(id any, args map[string]interface{}) jsonR
 	fmt.Fprintf(&output, "**File:** `%s`\n", sym.FilePath)
 	fmt.Fprintf(&output, "**Lines:** %d-%d\n", sym.LineStart, sym.LineEnd)
 	fmt.Fprintf(&output, "**Exported:** %v\n", sym.Exported)
+	if sym.LowConfidence {
+		output.WriteString("**Confidence:** low, guessed by a heuristic parser\n")
+	}
 	if sym.Signature != "" {
 		fmt.Fprintf(&output, "\n**Signature:**\n```\n%s\n```\n", sym.Signature)
 	}
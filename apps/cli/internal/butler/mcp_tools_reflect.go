@@ -0,0 +1,77 @@
+package butler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// toolReflect suggests what to do about a scoped slice of the knowledge base.
+func (s *MCPServer) toolReflect(id any, args map[string]interface{}) jsonRPCResponse {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not available")
+	}
+
+	filter := memory.ReflectFilter{}
+	filter.Scope, _ = args["scope"].(string)
+	filter.ScopePath, _ = args["scopePath"].(string)
+	if tagsRaw, ok := args["tags"].([]interface{}); ok {
+		for _, t := range tagsRaw {
+			if tag, ok := t.(string); ok && tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+	if kind, _ := args["kind"].(string); kind != "" {
+		if kind != "idea" && kind != "decision" && kind != "learning" {
+			return s.toolError(id, fmt.Sprintf("invalid kind %q: must be idea, decision, or learning", kind))
+		}
+		filter.Kinds = []string{kind}
+	}
+	format, _ := args["format"].(string)
+	if format != "" && format != "markdown" && format != "json" {
+		return s.toolError(id, fmt.Sprintf("invalid format %q: must be markdown or json", format))
+	}
+
+	r, err := mem.Reflect(filter)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("reflect: %v", err))
+	}
+
+	var output strings.Builder
+	if format == "json" {
+		data, _ := json.MarshalIndent(r, "", "  ")
+		output.Write(data)
+	} else {
+		output.WriteString("# Reflection\n\n")
+		fmt.Fprintf(&output, "Looked at %d decisions, %d ideas, and %d learnings", r.Decisions, r.Ideas, r.Learnings)
+		if filter.Scope != "" || filter.ScopePath != "" {
+			fmt.Fprintf(&output, " in %s", strings.Trim(filter.Scope+":"+filter.ScopePath, ":"))
+		}
+		if len(filter.Tags) > 0 {
+			fmt.Fprintf(&output, " tagged %s", strings.Join(filter.Tags, ", "))
+		}
+		output.WriteString(".\n\n")
+
+		if len(r.Suggestions) == 0 {
+			output.WriteString("Nothing needs attention.\n")
+		}
+		for i, sg := range r.Suggestions {
+			fmt.Fprintf(&output, "## %d. %s\n\n", i+1, sg.Message)
+			fmt.Fprintf(&output, "- **Type:** %s\n", sg.Type)
+			fmt.Fprintf(&output, "- **Records:** `%s`\n", strings.Join(sg.RecordIDs, "`, `"))
+			fmt.Fprintf(&output, "- **Action:** %s\n\n", sg.Action)
+		}
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package butler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolReflectJSON(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	for _, content := range []string{"Use MySQL for the user database", "Use PostgreSQL for the user database"} {
+		if _, err := b.memory.AddDecision(memory.Decision{
+			Content: content, Scope: "room", ScopePath: "storage",
+			Authority: string(memory.AuthorityApproved),
+		}); err != nil {
+			t.Fatalf("AddDecision failed: %v", err)
+		}
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolReflect(1, map[string]interface{}{"scopePath": "storage", "format": "json"}))
+
+	var r memory.Reflection
+	if err := json.Unmarshal([]byte(text), &r); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, text)
+	}
+	if len(r.Suggestions) != 1 || r.Suggestions[0].Type != memory.SuggestConflict || len(r.Suggestions[0].RecordIDs) != 2 {
+		t.Errorf("suggestions = %+v, want one conflict between the two decisions", r.Suggestions)
+	}
+}
@@ -150,45 +150,230 @@ func (s *MCPServer) toolRecall(id any, args map[string]interface{}) jsonRPCRespo
 		}
 	}
 
+	if rawIDs, ok := args["ids"].([]interface{}); ok && len(rawIDs) > 0 {
+		ids := make([]string, 0, len(rawIDs))
+		for _, raw := range rawIDs {
+			if recordID, ok := raw.(string); ok && recordID != "" {
+				ids = append(ids, recordID)
+			}
+		}
+		return s.recallByIDs(id, ids)
+	}
+
 	query, _ := args["query"].(string)
 	scope, _ := args["scope"].(string)
 	scopePath, _ := args["scopePath"].(string)
 
-	limit := 10
+	// Without an explicit limit, results are capped by a token budget so
+	// agents don't receive a wall of text they never asked for.
+	cfg := s.butler.recallConfig()
+	limit := cfg.MaxResults
+	budgeted := true
 	if l, ok := args["limit"].(float64); ok {
+		if l < 1 {
+			return s.toolError(id, "limit must be at least 1")
+		}
 		limit = int(l)
+		budgeted = false
+	}
+	cursor := 0
+	if c, ok := args["cursor"].(float64); ok && c > 0 {
+		cursor = int(c)
+	}
+
+	if asQuestions, _ := args["asQuestions"].(bool); asQuestions {
+		return s.recallAsQuestions(id, scope, scopePath, limit)
+	}
+	if topic, _ := args["evolution"].(string); topic != "" {
+		return s.recallEvolution(id, topic, limit)
+	}
+	if catchUp, _ := args["sinceLastSession"].(bool); catchUp {
+		return s.recallSinceLastSession(id, limit)
+	}
+	if narrative, _ := args["narrative"].(bool); narrative {
+		since, _ := args["since"].(string)
+		until, _ := args["until"].(string)
+		period, _ := args["period"].(string)
+		narrativeLimit := 0
+		if !budgeted {
+			narrativeLimit = limit
+		}
+		return s.recallNarrative(id, since, until, period, narrativeLimit)
+	}
+	if rawNames, ok := args["workspaces"].([]interface{}); ok && len(rawNames) > 0 {
+		names := make([]string, 0, len(rawNames))
+		for _, raw := range rawNames {
+			if name, ok := raw.(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+		return s.recallRelated(id, names, query, scope, scopePath, limit)
+	}
+	if related, _ := args["related"].(bool); related {
+		return s.recallRelated(id, nil, query, scope, scopePath, limit)
+	}
+	if pinnedOnly, _ := args["pinnedOnly"].(bool); pinnedOnly {
+		return s.recallPinned(id, memory.PinFilter{Query: query, Scope: scope, ScopePath: scopePath}, limit)
+	}
+
+	templateSpec, _ := args["template"].(string)
+	tmpl, err := parseRecallTemplate(templateSpec)
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+
+	collapse, _ := args["collapseByAnchor"].(bool)
+	countOnly, _ := args["countOnly"].(bool)
+	tags, err := parseTagFilter(args["tag"])
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+	tagMode, _ := args["tagMode"].(string)
+	anchorStatus, _ := args["anchorStatus"].(string)
+	minScore, _ := args["minScore"].(float64)
+	if minScore < 0 || minScore > 1 {
+		return s.toolError(id, "minScore must be between 0 and 1")
+	}
+	if minScore > 0 && (query == "" || memory.IsBoolQuery(query)) {
+		return s.toolError(id, "minScore requires a plain (non-boolean) query to score against")
+	}
+	// Boosted terms (redis^2) only rank the records the rest of the query
+	// finds; a query of boosted terms alone finds records with any of them
+	search, boosts, err := memory.SplitTermBoosts(query)
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+
+	// Fetch one extra record to detect whether more are available.
+	// Collapsing, counting, ranking, and filtering by tag or anchor status
+	// need every match, since a page's worth of results may span any number
+	// of records.
+	fetch := cursor + limit + 1
+	if collapse || countOnly || minScore > 0 || len(boosts) > 0 || !tags.empty() || (anchorStatus != "" && anchorStatus != anchorStatusAny) {
+		fetch = 0
 	}
 
 	var learnings []memory.Learning
-	var err error
 
-	if query != "" {
-		learnings, err = s.butler.SearchLearnings(query, limit)
+	if search == "" && len(boosts) > 0 {
+		q, perr := memory.BoostedTermsQuery(boosts)
+		if perr != nil {
+			return s.toolError(id, fmt.Sprintf("invalid query %q: %v", query, perr))
+		}
+		learnings, err = s.butler.SearchLearningsBool(q, fetch)
+	} else if memory.IsBoolQuery(search) {
+		// A query that only looks boolean, such as "redis OR", is searched
+		// as plain text
+		if q, perr := memory.ParseBoolQuery(search); perr == nil {
+			learnings, err = s.butler.SearchLearningsBool(q, fetch)
+		} else {
+			learnings, err = s.butler.SearchLearnings(search, fetch)
+		}
+	} else if search != "" {
+		learnings, err = s.butler.SearchLearnings(search, fetch)
 	} else {
-		learnings, err = s.butler.GetLearnings(scope, scopePath, limit)
+		learnings, err = s.butler.GetLearnings(scope, scopePath, fetch)
 	}
 
 	if err != nil {
 		return s.toolError(id, fmt.Sprintf("get learnings failed: %v", err))
 	}
 
+	if !tags.empty() {
+		learnings, err = s.filterLearningsByTags(learnings, tags, tagMode)
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("filter by tag failed: %v", err))
+		}
+	}
+
+	learnings, err = s.filterLearningsByAnchorStatus(learnings, anchorStatus)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("filter by anchor status failed: %v", err))
+	}
+
+	if minScore > 0 || len(boosts) > 0 {
+		learnings = s.filterLearningsByScore(learnings, query, minScore)
+	}
+
+	var collapsed map[string]int
+	if collapse {
+		learnings, collapsed = collapseByAnchor(learnings)
+	}
+
+	if countOnly {
+		breakdown, _ := args["breakdown"].(bool)
+		return jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      id,
+			Result: mcpToolResult{
+				Content: []mcpContent{{Type: "text", Text: s.formatRecallCount(learnings, breakdown)}},
+			},
+		}
+	}
+
+	if cursor >= len(learnings) {
+		learnings = nil
+	} else {
+		learnings = learnings[cursor:]
+	}
+	hasMore := len(learnings) > limit
+	if hasMore {
+		learnings = learnings[:limit]
+	}
+
+	entries := make([]string, len(learnings))
+	for i := range learnings {
+		if tmpl == nil {
+			entries[i] = formatLearningEntry(&learnings[i], collapsed[learnings[i].ID])
+			continue
+		}
+		if entries[i], err = tmpl.entry(learningRecord(&learnings[i])); err != nil {
+			return s.toolError(id, fmt.Sprintf("template failed: %v", err))
+		}
+	}
+	if budgeted {
+		if n := fitToTokenBudget(entries, cfg.DefaultTokenBudget); n < len(entries) {
+			entries = entries[:n]
+			hasMore = true
+		}
+	}
+	ids := make([]string, len(entries))
+	for i := range entries {
+		ids[i] = learnings[i].ID
+	}
+	_ = s.butler.memory.RecordAccess("learning", ids...)
+
+	if tmpl != nil {
+		return jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      id,
+			Result: mcpToolResult{
+				Content: []mcpContent{{Type: "text", Text: tmpl.render(entries, hasMore, cursor+len(entries))}},
+			},
+		}
+	}
+
 	var output strings.Builder
 	output.WriteString("# Learnings\n\n")
 
-	if len(learnings) == 0 {
+	if len(entries) == 0 && minScore > 0 && cursor == 0 {
+		fmt.Fprintf(&output, "Nothing sufficiently relevant to %q (minScore %.2f).\n", query, minScore)
+	} else if len(entries) == 0 {
 		output.WriteString("No learnings found.\n")
 	} else {
-		for i := range learnings {
-			l := &learnings[i]
-			scopeInfo := l.Scope
-			if l.ScopePath != "" {
-				scopeInfo = fmt.Sprintf("%s:%s", l.Scope, l.ScopePath)
-			}
-			fmt.Fprintf(&output, "## `%s` (%.0f%% confidence)\n", l.ID, l.Confidence*100)
-			fmt.Fprintf(&output, "- **Scope:** %s\n", scopeInfo)
-			fmt.Fprintf(&output, "- **Source:** %s | Used: %d times\n", l.Source, l.UseCount)
-			fmt.Fprintf(&output, "- **Content:** %s\n\n", l.Content)
+		for _, entry := range entries {
+			output.WriteString(entry)
+		}
+	}
+
+	if hasMore {
+		nextCursor := cursor + len(entries)
+		if budgeted {
+			fmt.Fprintf(&output, "---\n_More learnings available (output capped at ~%d tokens). ", cfg.DefaultTokenBudget)
+		} else {
+			output.WriteString("---\n_More learnings available. ")
 		}
+		fmt.Fprintf(&output, "Call recall again with `cursor: %d` for the next page, or set an explicit `limit`._\n", nextCursor)
 	}
 
 	return jsonRPCResponse{
@@ -0,0 +1,103 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// Anchor status filters for recall.
+const (
+	anchorStatusAny   = "any"
+	anchorStatusLive  = "live"
+	anchorStatusStale = "stale"
+)
+
+// filterLearningsByAnchorStatus keeps the code-anchored learnings whose
+// anchors all still exist in the index ("live"), or those with at least one
+// anchor that no longer does ("stale"). A learning is anchored by its file
+// scope and by its links to code; learnings with neither are dropped by both
+// filters, having no code to be live or stale against. "any" or an empty
+// status returns learnings unchanged.
+func (s *MCPServer) filterLearningsByAnchorStatus(learnings []memory.Learning, status string) ([]memory.Learning, error) {
+	switch status {
+	case "", anchorStatusAny:
+		return learnings, nil
+	case anchorStatusLive, anchorStatusStale:
+	default:
+		return nil, fmt.Errorf("anchorStatus must be %q, %q, or %q", anchorStatusLive, anchorStatusStale, anchorStatusAny)
+	}
+	if s.butler.db == nil {
+		return nil, errors.New("anchorStatus needs the code index; run 'palace scan' first")
+	}
+
+	extents := make(map[string]int) // file path -> last indexed line, -1 if not indexed
+	var filtered []memory.Learning
+	for i := range learnings {
+		anchors, err := s.learningCodeAnchors(&learnings[i])
+		if err != nil {
+			return nil, err
+		}
+		if len(anchors) == 0 {
+			continue
+		}
+		live := true
+		for _, anchor := range anchors {
+			ok, err := s.anchorLive(anchor, extents)
+			if err != nil {
+				return nil, err
+			}
+			live = live && ok
+		}
+		if live == (status == anchorStatusLive) {
+			filtered = append(filtered, learnings[i])
+		}
+	}
+	return filtered, nil
+}
+
+// learningCodeAnchors returns the code locations a learning is attached to:
+// the file it is scoped to and the targets of its code links.
+func (s *MCPServer) learningCodeAnchors(l *memory.Learning) ([]*memory.CodeTarget, error) {
+	var anchors []*memory.CodeTarget
+	if l.Scope == string(memory.ScopeFile) && l.ScopePath != "" {
+		target, _ := memory.ParseCodeTarget(l.ScopePath)
+		anchors = append(anchors, target)
+	}
+	links, err := s.butler.memory.GetLinksForSource(l.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get links for %s: %w", l.ID, err)
+	}
+	for _, link := range links {
+		if link.TargetKind != memory.TargetKindCode {
+			continue
+		}
+		if target, err := memory.ParseCodeTarget(link.TargetID); err == nil {
+			anchors = append(anchors, target)
+		}
+	}
+	return anchors, nil
+}
+
+// anchorLive reports whether the anchored file is in the index and, for a
+// line range, still reaches its last line. extents caches lookups by file.
+func (s *MCPServer) anchorLive(anchor *memory.CodeTarget, extents map[string]int) (bool, error) {
+	lastLine, ok := extents[anchor.FilePath]
+	if !ok {
+		var indexed int
+		err := s.butler.db.QueryRowContext(context.Background(), `
+			SELECT (SELECT COUNT(*) FROM files WHERE path = ?),
+			       (SELECT COALESCE(MAX(end_line), 0) FROM chunks WHERE path = ?);`,
+			anchor.FilePath, anchor.FilePath).Scan(&indexed, &lastLine)
+		if err != nil {
+			return false, fmt.Errorf("look up %s in index: %w", anchor.FilePath, err)
+		}
+		if indexed == 0 {
+			lastLine = -1
+		}
+		extents[anchor.FilePath] = lastLine
+	}
+	return lastLine >= 0 && anchor.EndLine <= lastLine, nil
+}
@@ -0,0 +1,53 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallAnchorStatus(t *testing.T) {
+	server, b := setupMCPServer(t)
+	if b.memory == nil {
+		t.Fatal("expected memory to be available")
+	}
+
+	add := func(content, scope, scopePath string) string {
+		id, err := b.memory.AddLearning(memory.Learning{
+			Content: content, Scope: scope, ScopePath: scopePath, Confidence: 0.8,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		return id
+	}
+	liveID := add("DoWork must stay idempotent", "file", "main.go")
+	staleID := add("The legacy parser leaks buffers", "file", "legacy/parser.go")
+	unanchoredID := add("Prefer table-driven tests", "palace", "")
+
+	recall := func(status string) string {
+		return toolText(t, server.toolRecall(1, map[string]interface{}{"anchorStatus": status, "limit": float64(10)}))
+	}
+
+	stale := recall("stale")
+	if !strings.Contains(stale, staleID) || strings.Contains(stale, liveID) || strings.Contains(stale, unanchoredID) {
+		t.Errorf("anchorStatus stale should return only %s, got:\n%s", staleID, stale)
+	}
+	live := recall("live")
+	if !strings.Contains(live, liveID) || strings.Contains(live, staleID) || strings.Contains(live, unanchoredID) {
+		t.Errorf("anchorStatus live should return only %s, got:\n%s", liveID, live)
+	}
+	all := recall("any")
+	for _, id := range []string{liveID, staleID, unanchoredID} {
+		if !strings.Contains(all, id) {
+			t.Errorf("anchorStatus any should include %s, got:\n%s", id, all)
+		}
+	}
+
+	resp := server.toolRecall(1, map[string]interface{}{"anchorStatus": "dead"})
+	if result, ok := resp.Result.(mcpToolResult); !ok || !result.IsError {
+		t.Errorf("expected an error for an unknown anchorStatus, got %+v", resp.Result)
+	}
+}
@@ -0,0 +1,68 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallBoostedTerms(t *testing.T) {
+	server, b := setupMCPServer(t)
+	add := func(content string, confidence float64) string {
+		id, err := b.memory.AddLearning(memory.Learning{
+			Content: content, Scope: "palace", Confidence: confidence,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		return id
+	}
+	memcachedID := add("memcached caching, caching everywhere", 0.9)
+	redisID := add("caching layer backed by redis", 0.5)
+	otherID := add("postgres tuning notes", 0.9)
+
+	recall := func(query string) string {
+		return toolText(t, server.toolRecall(1, map[string]interface{}{"query": query, "limit": float64(10)}))
+	}
+	first := func(out string, ids ...string) string {
+		best, bestAt := "", len(out)
+		for _, id := range ids {
+			if at := strings.Index(out, id); at >= 0 && at < bestAt {
+				best, bestAt = id, at
+			}
+		}
+		return best
+	}
+
+	out := recall("caching")
+	if first(out, memcachedID, redisID) != memcachedID {
+		t.Fatalf("without a boost the more confident record should come first:\n%s", out)
+	}
+
+	// The memcached record is the closer match to caching alone; boosting
+	// redis puts the record mentioning it first without dropping the other
+	out = recall("caching redis^5")
+	if first(out, memcachedID, redisID) != redisID {
+		t.Errorf("the boosted term's record should rank first:\n%s", out)
+	}
+	if !strings.Contains(out, memcachedID) || strings.Contains(out, otherID) {
+		t.Errorf("a boosted term should not filter, and the rest of the query should:\n%s", out)
+	}
+	out = recall("caching redis^1")
+	if first(out, memcachedID, redisID) != memcachedID {
+		t.Errorf("a factor of 1 should leave the closer match first:\n%s", out)
+	}
+
+	out = recall("redis^2")
+	if !strings.Contains(out, redisID) || strings.Contains(out, memcachedID) || strings.Contains(out, otherID) {
+		t.Errorf("boosted terms alone should find the records mentioning them:\n%s", out)
+	}
+
+	resp := server.toolRecall(1, map[string]interface{}{"query": "caching redis^x"})
+	result, ok := resp.Result.(mcpToolResult)
+	if !ok || !result.IsError || !strings.Contains(result.Content[0].Text, "invalid boost") {
+		t.Errorf("expected an error for a malformed boost, got %+v", resp.Result)
+	}
+}
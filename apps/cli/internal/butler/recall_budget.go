@@ -0,0 +1,49 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// recallConfig returns the configured recall settings, falling back to defaults.
+func (b *Butler) recallConfig() *config.RecallConfig {
+	return b.config.RecallSettings()
+}
+
+// formatLearningEntry renders a single learning as a markdown section.
+// collapsed is the number of other learnings on the same anchor folded into it.
+func formatLearningEntry(l *memory.Learning, collapsed int) string {
+	scopeInfo := l.Scope
+	if l.ScopePath != "" {
+		scopeInfo = fmt.Sprintf("%s:%s", l.Scope, l.ScopePath)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## `%s` (%.0f%% confidence)", l.ID, l.Confidence*100)
+	if collapsed > 0 {
+		fmt.Fprintf(&sb, " (+%d more)", collapsed)
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "- **Scope:** %s\n", scopeInfo)
+	fmt.Fprintf(&sb, "- **Source:** %s | Used: %d times\n", l.Source, l.UseCount)
+	fmt.Fprintf(&sb, "- **Content:** %s\n\n", l.Content)
+	return sb.String()
+}
+
+// fitToTokenBudget returns how many of the rendered entries fit within the
+// token budget. At least one entry is always included so a single oversized
+// record is still reachable.
+func fitToTokenBudget(entries []string, budget int) int {
+	used := 0
+	for i, entry := range entries {
+		cost := index.EstimateTokens(entry)
+		if i > 0 && used+cost > budget {
+			return i
+		}
+		used += cost
+	}
+	return len(entries)
+}
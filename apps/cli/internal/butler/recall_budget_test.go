@@ -0,0 +1,94 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func seedLargeLearnings(t *testing.T, b *Butler, n int) {
+	t.Helper()
+	body := strings.Repeat("connection pooling detail ", 60)
+	for i := 0; i < n; i++ {
+		_, err := b.memory.AddLearning(memory.Learning{
+			Content:    fmt.Sprintf("Learning %d: %s", i, body),
+			Authority:  string(memory.AuthorityApproved),
+			Scope:      "palace",
+			Confidence: 0.8,
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+	}
+}
+
+func TestToolRecallDefaultBudgetCapsResults(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	seedLargeLearnings(t, b, 30)
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{}))
+
+	count := strings.Count(text, "## `")
+	if count == 0 || count >= 30 {
+		t.Fatalf("expected budget to cap results below 30, got %d", count)
+	}
+	if !strings.Contains(text, "More learnings available") {
+		t.Errorf("expected 'more available' note, got:\n%s", text)
+	}
+	if !strings.Contains(text, fmt.Sprintf("cursor: %d", count)) {
+		t.Errorf("expected next cursor %d in note", count)
+	}
+
+	// Following the cursor returns the next page without repeating records
+	next := toolText(t, server.toolRecall(2, map[string]interface{}{"cursor": float64(count)}))
+	firstID := extractBetween(next, "## `", "`")
+	if firstID == "" || strings.Contains(text, firstID) {
+		t.Errorf("expected cursor page to start with an unseen record, got %q", firstID)
+	}
+}
+
+func TestToolRecallExplicitLimitBypassesBudget(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	seedLargeLearnings(t, b, 30)
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"limit": float64(25)}))
+
+	if count := strings.Count(text, "## `"); count != 25 {
+		t.Errorf("expected 25 results with explicit limit, got %d", count)
+	}
+	if !strings.Contains(text, "cursor: 25") {
+		t.Errorf("expected cursor note for remaining records")
+	}
+}
+
+func TestToolRecallSmallResultHasNoNote(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	seedLargeLearnings(t, b, 1)
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{}))
+	if strings.Contains(text, "More learnings available") {
+		t.Errorf("did not expect 'more available' note for a single record")
+	}
+}
+
+func TestToolRecallRejectsNonPositiveLimit(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	seedLargeLearnings(t, b, 3)
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	for _, limit := range []float64{0, -1} {
+		resp := server.toolRecall(1, map[string]interface{}{"limit": limit})
+		if result, ok := resp.Result.(mcpToolResult); !ok || !result.IsError {
+			t.Errorf("limit %v: expected a tool error, got %+v", limit, resp.Result)
+		}
+	}
+}
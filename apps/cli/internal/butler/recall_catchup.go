@@ -0,0 +1,76 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// recallSinceLastSession lists the records created or changed since the
+// previous catch-up and advances the marker. Each kind is capped at limit
+// entries, with a count of the rest, since the marker moves on regardless.
+func (s *MCPServer) recallSinceLastSession(id any, limit int) jsonRPCResponse {
+	c, err := s.butler.memory.CatchUp()
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("catch up failed: %v", err))
+	}
+
+	var output strings.Builder
+	if c.Since.IsZero() {
+		output.WriteString("# Since last session (first session)\n\n")
+	} else {
+		fmt.Fprintf(&output, "# Since last session (%s)\n\n", c.Since.Local().Format("2006-01-02 15:04"))
+	}
+	if c.Total() == 0 {
+		output.WriteString("Nothing new since the last session.\n")
+	}
+
+	if len(c.Decisions) > 0 {
+		output.WriteString("# Decisions\n\n")
+		for i := range c.Decisions {
+			if limit > 0 && i == limit {
+				fmt.Fprintf(&output, "_…and %d more decisions._\n\n", len(c.Decisions)-limit)
+				break
+			}
+			output.WriteString(formatDecisionEntry(&c.Decisions[i], changeNote(c.Decisions[i].CreatedAt, c.Since)))
+		}
+	}
+	if len(c.Learnings) > 0 {
+		output.WriteString("# Learnings\n\n")
+		for i := range c.Learnings {
+			if limit > 0 && i == limit {
+				fmt.Fprintf(&output, "_…and %d more learnings._\n\n", len(c.Learnings)-limit)
+				break
+			}
+			output.WriteString(formatLearningEntry(&c.Learnings[i], 0))
+		}
+	}
+	if len(c.Ideas) > 0 {
+		output.WriteString("# Ideas\n\n")
+		for i := range c.Ideas {
+			if limit > 0 && i == limit {
+				fmt.Fprintf(&output, "_…and %d more ideas._\n", len(c.Ideas)-limit)
+				break
+			}
+			idea := &c.Ideas[i]
+			fmt.Fprintf(&output, "- `%s` (%s, %s) %s\n", idea.ID, idea.Status, strings.Trim(changeNote(idea.CreatedAt, c.Since), "()"), idea.Content)
+		}
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+// changeNote tells a record created since the marker from one only updated
+// since.
+func changeNote(createdAt, since time.Time) string {
+	if createdAt.Before(since.Truncate(time.Second)) {
+		return "(updated)"
+	}
+	return "(new)"
+}
@@ -0,0 +1,42 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallSinceLastSession(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	old := time.Now().Add(-time.Hour)
+	if _, err := b.memory.AddLearning(memory.Learning{
+		Content: "Retries use exponential backoff", Scope: "palace", Confidence: 0.8,
+		Authority: string(memory.AuthorityApproved), CreatedAt: old,
+	}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	if err := b.memory.MarkSession(time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("MarkSession failed: %v", err)
+	}
+	if _, err := b.memory.AddDecision(memory.Decision{
+		Content: "Use SQLite for the index", Scope: "palace",
+		Authority: string(memory.AuthorityApproved),
+	}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"sinceLastSession": true}))
+	if !strings.Contains(text, "Use SQLite for the index") || !strings.Contains(text, "(new)") {
+		t.Errorf("expected the new decision:\n%s", text)
+	}
+	if strings.Contains(text, "exponential backoff") {
+		t.Errorf("learning from before the last session should not be listed:\n%s", text)
+	}
+
+}
@@ -0,0 +1,45 @@
+package butler
+
+import (
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// learningAnchor returns the code location a learning is attached to, or ""
+// for workspace-wide learnings that have no anchor.
+func learningAnchor(l *memory.Learning) string {
+	if l.ScopePath == "" {
+		return ""
+	}
+	return l.Scope + ":" + l.ScopePath
+}
+
+// collapseByAnchor keeps one learning per anchor: the most recent, or the
+// higher-ranked one when they were created at the same time. The kept
+// learning takes the position of the group's best-ranked member. The returned
+// map counts how many learnings were folded into each kept ID.
+func collapseByAnchor(learnings []memory.Learning) ([]memory.Learning, map[string]int) {
+	collapsed := map[string]int{}
+	groupIndex := map[string]int{}
+	var result []memory.Learning
+	for _, l := range learnings {
+		anchor := learningAnchor(&l)
+		if anchor == "" {
+			result = append(result, l)
+			continue
+		}
+		i, seen := groupIndex[anchor]
+		if !seen {
+			groupIndex[anchor] = len(result)
+			result = append(result, l)
+			continue
+		}
+		kept := result[i]
+		count := collapsed[kept.ID] + 1
+		delete(collapsed, kept.ID)
+		if l.CreatedAt.After(kept.CreatedAt) {
+			result[i] = l
+		}
+		collapsed[result[i].ID] = count
+	}
+	return result, collapsed
+}
@@ -0,0 +1,57 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallCollapseByAnchor(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	base := time.Now().Add(-time.Hour)
+	var latestID string
+	for i, content := range []string{"auth.go: tokens expire after 1h", "auth.go: tokens expire after 30m", "auth.go: tokens expire after 15m"} {
+		id, err := b.memory.AddLearning(memory.Learning{
+			Content:    content,
+			Authority:  string(memory.AuthorityApproved),
+			Scope:      "file",
+			ScopePath:  "auth.go",
+			Confidence: 0.8,
+			CreatedAt:  base.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		latestID = id
+	}
+	if _, err := b.memory.AddLearning(memory.Learning{
+		Content:    "Prefer table-driven tests",
+		Authority:  string(memory.AuthorityApproved),
+		Scope:      "palace",
+		Confidence: 0.8,
+	}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{}))
+	if count := strings.Count(text, "## `"); count != 4 {
+		t.Fatalf("expected 4 learnings without collapsing, got %d", count)
+	}
+
+	text = toolText(t, server.toolRecall(2, map[string]interface{}{"collapseByAnchor": true}))
+	if count := strings.Count(text, "## `"); count != 2 {
+		t.Fatalf("expected 2 learnings after collapsing, got %d:\n%s", count, text)
+	}
+	if !strings.Contains(text, "## `"+latestID+"`") {
+		t.Errorf("expected the most recent learning %s to be kept:\n%s", latestID, text)
+	}
+	if !strings.Contains(text, "(+2 more)") {
+		t.Errorf("expected '(+2 more)' note:\n%s", text)
+	}
+}
@@ -0,0 +1,46 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallDecisionsConsensus(t *testing.T) {
+	server, b := setupMCPServer(t)
+	add := func(content string, created time.Time) string {
+		id, err := b.memory.AddDecision(memory.Decision{
+			Content: content, Scope: "palace", CreatedAt: created,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddDecision failed: %v", err)
+		}
+		return id
+	}
+	now := time.Now().UTC()
+	oldID := add("Store user sessions in Redis", now.Add(-48*time.Hour))
+	newID := add("Store user sessions in Postgres instead", now.Add(-time.Hour))
+	otherID := add("Deploy the frontend with Netlify", now.Add(-24*time.Hour))
+
+	out := toolText(t, server.toolRecallDecisions(1, map[string]interface{}{"consensus": true}))
+	if !strings.Contains(out, "`"+newID+"` ❓ (1 earlier)") {
+		t.Errorf("expected %s current with a (1 earlier) marker, got:\n%s", newID, out)
+	}
+	if !strings.Contains(out, "_Earlier:_ `"+oldID+"`") || strings.Contains(out, "## 🔵 `"+oldID+"`") {
+		t.Errorf("expected %s collapsed behind the current decision, got:\n%s", oldID, out)
+	}
+	if !strings.Contains(out, "## 🔵 `"+otherID+"` ❓\n") {
+		t.Errorf("expected unrelated %s as its own topic, got:\n%s", otherID, out)
+	}
+	if strings.Index(out, newID) > strings.Index(out, otherID) {
+		t.Errorf("expected topics newest first, got:\n%s", out)
+	}
+
+	out = toolText(t, server.toolRecallDecisions(1, map[string]interface{}{}))
+	if !strings.Contains(out, "## 🔵 `"+oldID+"`") {
+		t.Errorf("without consensus every decision should be listed, got:\n%s", out)
+	}
+}
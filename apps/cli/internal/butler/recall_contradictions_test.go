@@ -0,0 +1,58 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallDecisionsFlagContradictions(t *testing.T) {
+	server, b := setupMCPServer(t)
+	add := func(content string, created time.Time) string {
+		id, err := b.memory.AddDecision(memory.Decision{
+			Content: content, Scope: "palace", CreatedAt: created,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddDecision failed: %v", err)
+		}
+		return id
+	}
+	now := time.Now().UTC()
+	sqliteID := add("Use SQLite as the embedded database", now.Add(-3*time.Hour))
+	postgresID := add("Keep all persistent data in Postgres", now.Add(-2*time.Hour))
+	otherID := add("Deploy the frontend with Netlify", now.Add(-time.Hour))
+	if _, err := b.AddLink(memory.Link{
+		SourceID: postgresID, SourceKind: "decision",
+		TargetID: sqliteID, TargetKind: "decision",
+		Relation: memory.RelationContradicts,
+	}); err != nil {
+		t.Fatalf("AddLink failed: %v", err)
+	}
+
+	out := toolText(t, server.toolRecallDecisions(1, map[string]interface{}{"flagContradictions": true}))
+	// Newest first: Netlify is #1, Postgres #2, SQLite #3
+	entry := func(id string) string {
+		start := strings.Index(out, "## 🔵 `"+id+"`")
+		if start < 0 {
+			t.Fatalf("expected %s in the results, got:\n%s", id, out)
+		}
+		return out[start : start+strings.Index(out[start:], "\n")]
+	}
+	if want := "⚠ conflicts with result #3 `" + sqliteID + "` (linked as contradicting)"; !strings.Contains(entry(postgresID), want) {
+		t.Errorf("expected %s flagged with %q, got:\n%s", postgresID, want, out)
+	}
+	if want := "⚠ conflicts with result #2 `" + postgresID + "`"; !strings.Contains(entry(sqliteID), want) {
+		t.Errorf("expected %s flagged with %q, got:\n%s", sqliteID, want, out)
+	}
+	if strings.Contains(entry(otherID), "⚠") {
+		t.Errorf("expected %s not flagged, got:\n%s", otherID, out)
+	}
+
+	out = toolText(t, server.toolRecallDecisions(1, map[string]interface{}{}))
+	if strings.Contains(out, "⚠") {
+		t.Errorf("without flagContradictions nothing should be flagged, got:\n%s", out)
+	}
+}
@@ -0,0 +1,56 @@
+package butler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// formatRecallCount renders the number of matching learnings without their
+// content. With breakdown, counts per scope and per tag follow; a learning
+// with several tags counts once under each.
+func (s *MCPServer) formatRecallCount(learnings []memory.Learning, breakdown bool) string {
+	var output strings.Builder
+	output.WriteString("# Learnings\n\n")
+	fmt.Fprintf(&output, "**Count:** %d\n", len(learnings))
+	if !breakdown || len(learnings) == 0 {
+		return output.String()
+	}
+
+	byScope := make(map[string]int)
+	byTag := make(map[string]int)
+	for i := range learnings {
+		byScope[learnings[i].Scope]++
+		tags, _ := s.butler.memory.GetTags(learnings[i].ID, "learning")
+		for _, tag := range tags {
+			byTag[tag]++
+		}
+	}
+
+	output.WriteString("\n## By Scope\n\n")
+	writeCounts(&output, byScope)
+	if len(byTag) > 0 {
+		output.WriteString("\n## By Tag\n\n")
+		writeCounts(&output, byTag)
+	}
+	return output.String()
+}
+
+// writeCounts lists counts in descending order, ties broken by name.
+func writeCounts(sb *strings.Builder, counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		fmt.Fprintf(sb, "- %s: %d\n", k, counts[k])
+	}
+}
@@ -0,0 +1,49 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallCountOnly(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	for _, l := range []memory.Learning{
+		{Content: "Database migrations run in a transaction", Scope: "palace"},
+		{Content: "Database connections are pooled per request", Scope: "file", ScopePath: "db/pool.go"},
+		{Content: "Prefer table-driven tests", Scope: "palace"},
+	} {
+		l.Authority = string(memory.AuthorityApproved)
+		l.Confidence = 0.8
+		id, err := b.memory.AddLearning(l)
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		if strings.Contains(l.Content, "Database") {
+			if err := b.memory.SetTags(id, "learning", []string{"database"}); err != nil {
+				t.Fatalf("SetTags failed: %v", err)
+			}
+		}
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"query": "database", "countOnly": true}))
+	if !strings.Contains(text, "**Count:** 2\n") {
+		t.Errorf("expected a count of 2:\n%s", text)
+	}
+	if strings.Contains(text, "migrations") || strings.Contains(text, "## `") {
+		t.Errorf("countOnly should not include record content:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(2, map[string]interface{}{"scope": "palace", "countOnly": true, "breakdown": true}))
+	if !strings.Contains(text, "**Count:** 2\n") {
+		t.Errorf("expected the scope filter to apply:\n%s", text)
+	}
+	if !strings.Contains(text, "- palace: 2") || !strings.Contains(text, "- database: 1") {
+		t.Errorf("expected scope and tag breakdown:\n%s", text)
+	}
+}
@@ -0,0 +1,65 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxEvolutionWords is how many gained or lost words a step names.
+const maxEvolutionWords = 6
+
+// recallEvolution tells the story of a topic: the records about it oldest
+// first, each after the first with what changed since the one before it.
+func (s *MCPServer) recallEvolution(id any, topic string, limit int) jsonRPCResponse {
+	steps, err := s.butler.memory.TopicEvolution(topic, limit)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("recall evolution failed: %v", err))
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "# Evolution of %q\n\n", topic)
+	if len(steps) == 0 {
+		output.WriteString("No records found on this topic.\n")
+	}
+	for i := range steps {
+		st := &steps[i]
+		status := st.Kind
+		if st.Status != "" {
+			status += ", " + st.Status
+		}
+		fmt.Fprintf(&output, "%d. **%s** `%s` (%s) %s\n", i+1, st.CreatedAt.Local().Format("2006-01-02"), st.ID, status, st.Content)
+		if i == 0 {
+			continue
+		}
+
+		var notes []string
+		if len(st.Supersedes) > 0 {
+			notes = append(notes, "supersedes `"+strings.Join(st.Supersedes, "`, `")+"`")
+		}
+		if added := limitWords(st.Added); added != "" {
+			notes = append(notes, "adds: "+added)
+		}
+		if dropped := limitWords(st.Dropped); dropped != "" {
+			notes = append(notes, "drops: "+dropped)
+		}
+		notes = append(notes, fmt.Sprintf("%.0f%% similar", st.Similarity*100))
+		prev := &steps[i-1]
+		fmt.Fprintf(&output, "   - Changed from `%s` → `%s`: %s\n", prev.ID, st.ID, strings.Join(notes, "; "))
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
+
+// limitWords joins words, naming at most maxEvolutionWords of them.
+func limitWords(words []string) string {
+	if len(words) > maxEvolutionWords {
+		return strings.Join(words[:maxEvolutionWords], ", ") + fmt.Sprintf(" (+%d more)", len(words)-maxEvolutionWords)
+	}
+	return strings.Join(words, ", ")
+}
@@ -0,0 +1,48 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallEvolution(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	oldID, err := b.memory.AddDecision(memory.Decision{
+		Content: "Cache sessions in memcached", Scope: "palace", Status: memory.DecisionStatusSuperseded,
+		Authority: string(memory.AuthorityApproved), CreatedAt: time.Now().Add(-48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	// The replacement never says "memcached"; the supersedes link ties it in.
+	newID, err := b.memory.AddDecision(memory.Decision{
+		Content: "Cache sessions in redis with expiry", Scope: "palace",
+		Authority: string(memory.AuthorityApproved), CreatedAt: time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := b.memory.AddLink(memory.Link{SourceID: newID, SourceKind: "decision", TargetID: oldID, TargetKind: "decision", Relation: memory.RelationSupersedes}); err != nil {
+		t.Fatalf("AddLink failed: %v", err)
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"evolution": "memcached"}))
+
+	first, second := strings.Index(text, "1. "), strings.Index(text, "2. ")
+	if first < 0 || second < 0 || !strings.Contains(text[first:second], oldID) || !strings.Contains(text[second:], newID) {
+		t.Fatalf("expected %s then %s, oldest first:\n%s", oldID, newID, text)
+	}
+	change := "Changed from `" + oldID + "` → `" + newID + "`: supersedes `" + oldID + "`"
+	if !strings.Contains(text, change) {
+		t.Errorf("expected the change %q:\n%s", change, text)
+	}
+	if !strings.Contains(text, "adds: redis, with, expiry") || !strings.Contains(text, "drops: memcached") {
+		t.Errorf("expected the words gained and lost:\n%s", text)
+	}
+}
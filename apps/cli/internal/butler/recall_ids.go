@@ -0,0 +1,56 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// recallByIDs returns the requested records in the order given. Learnings are
+// rendered like any recall result; decisions and ideas, which agents also
+// capture IDs for, are shown with their content. IDs that match no record are
+// reported at the end rather than failing the call.
+func (s *MCPServer) recallByIDs(id any, ids []string) jsonRPCResponse {
+	mem := s.butler.Memory()
+	if mem == nil {
+		return s.toolError(id, "memory not initialized")
+	}
+
+	var output strings.Builder
+	output.WriteString("# Records\n\n")
+	var missing []string
+	for _, recordID := range ids {
+		if l, err := mem.GetLearning(recordID); err == nil {
+			output.WriteString(formatLearningEntry(l, 0))
+			continue
+		}
+		found := false
+		for _, kind := range []string{"decision", "idea"} {
+			content, _, err := mem.GetRecordContent(recordID, kind)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(&output, "## `%s` (%s)\n", recordID, kind)
+			fmt.Fprintf(&output, "- **Content:** %s\n\n", content)
+			found = true
+			break
+		}
+		if !found {
+			missing = append(missing, recordID)
+		}
+	}
+
+	if len(missing) == len(ids) {
+		output.WriteString("No records found.\n")
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(&output, "---\n_Not found: `%s`_\n", strings.Join(missing, "`, `"))
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallByIDs(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	learningID, err := b.memory.AddLearning(memory.Learning{
+		Content: "Retries use exponential backoff", Scope: "palace",
+		Authority: string(memory.AuthorityApproved),
+	})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	decisionID, err := b.memory.AddDecision(memory.Decision{Content: "Use SQLite for the index", Scope: "palace"})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{
+		"ids": []interface{}{decisionID, "lrn_missing", learningID},
+	}))
+
+	decisionAt := strings.Index(text, "Use SQLite for the index")
+	learningAt := strings.Index(text, "Retries use exponential backoff")
+	if decisionAt < 0 || learningAt < 0 {
+		t.Fatalf("expected both records:\n%s", text)
+	}
+	if decisionAt > learningAt {
+		t.Errorf("records should follow the requested order:\n%s", text)
+	}
+	if !strings.Contains(text, "Not found: `lrn_missing`") {
+		t.Errorf("expected the missing ID to be reported:\n%s", text)
+	}
+}
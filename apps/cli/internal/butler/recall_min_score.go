@@ -0,0 +1,26 @@
+package butler
+
+import (
+	"sort"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// filterLearningsByScore ranks learnings by how closely they match query,
+// boosted terms included, and drops those scoring below minScore, so weak
+// matches never reach an agent. Learnings with equal scores keep their order.
+func (s *MCPServer) filterLearningsByScore(learnings []memory.Learning, query string, minScore float64) []memory.Learning {
+	scores := make(map[string]float64, len(learnings))
+	var kept []memory.Learning
+	for i := range learnings {
+		score := s.butler.memory.MatchScore(learnings[i].Content, query)
+		if score >= minScore {
+			scores[learnings[i].ID] = score
+			kept = append(kept, learnings[i])
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return scores[kept[i].ID] > scores[kept[j].ID]
+	})
+	return kept
+}
@@ -0,0 +1,52 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallMinScore(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	for _, content := range []string{
+		"The deploy script restarts the workers and clears the cache before running database migrations",
+		"Cache keys are versioned",
+		"Prefer table-driven tests",
+	} {
+		if _, err := b.memory.AddLearning(memory.Learning{Content: content, Scope: "palace", Authority: string(memory.AuthorityApproved), Confidence: 0.8}); err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+	}
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"query": "cache"}))
+	if !strings.Contains(text, "deploy script") {
+		t.Fatalf("without minScore the passing mention should match:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(2, map[string]interface{}{"query": "cache", "minScore": 0.2}))
+	if !strings.Contains(text, "Cache keys are versioned") {
+		t.Errorf("the strong match should be kept:\n%s", text)
+	}
+	if strings.Contains(text, "deploy script") || strings.Contains(text, "table-driven") {
+		t.Errorf("weak and unrelated records should be dropped:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(3, map[string]interface{}{"query": "migrations", "minScore": 0.5}))
+	if !strings.Contains(text, `Nothing sufficiently relevant to "migrations"`) {
+		t.Errorf("expected an explicit nothing-relevant message:\n%s", text)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"minScore": 0.2},
+		{"query": "cache OR redis", "minScore": 0.2},
+		{"query": "cache", "minScore": 1.5},
+	} {
+		if resp := server.toolRecall(4, args); !resp.Result.(mcpToolResult).IsError {
+			t.Errorf("recall(%v) should fail", args)
+		}
+	}
+}
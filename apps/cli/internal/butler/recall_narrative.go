@@ -0,0 +1,171 @@
+package butler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/llm"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// defaultNarrativeWindow is how far back a narrative goes without "since".
+const defaultNarrativeWindow = 7 * 24 * time.Hour
+
+// defaultNarrativeLimit caps each kind of record in a narrative without an
+// explicit limit.
+const defaultNarrativeLimit = 100
+
+// recallNarrative narrates the decisions, learnings, and ideas created
+// between since and until, oldest period first, keeping the limit most
+// recent of each kind (defaultNarrativeLimit when limit is 0). With an LLM
+// configured the templated narrative is rewritten as prose; without one, or
+// when the LLM fails, the template is returned as is.
+func (s *MCPServer) recallNarrative(id any, since, until, period string, limit int) jsonRPCResponse {
+	now := time.Now()
+	from, err := parseNarrativeTime(since, now, now.Add(-defaultNarrativeWindow))
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("invalid since: %v", err))
+	}
+	to, err := parseNarrativeTime(until, now, now)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("invalid until: %v", err))
+	}
+	if _, err := time.Parse("2006-01-02", strings.TrimSpace(until)); err == nil {
+		to = to.AddDate(0, 0, 1) // Until a date includes that day
+	}
+	var weekly bool
+	switch period {
+	case "":
+		weekly = to.Sub(from) > 14*24*time.Hour
+	case "day":
+	case "week":
+		weekly = true
+	default:
+		return s.toolError(id, fmt.Sprintf("invalid period %q (use day or week)", period))
+	}
+
+	if limit <= 0 {
+		limit = defaultNarrativeLimit
+	}
+	timeline, err := s.butler.memory.Timeline(from, to, weekly, limit)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("timeline failed: %v", err))
+	}
+	text := formatNarrative(timeline)
+
+	if timeline.Total() > 0 {
+		if client, err := s.butler.GetLLMClient(); err == nil && client != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if prose, err := client.Complete(ctx, buildNarrativePrompt(text), llm.CompletionOptions{}); err == nil && strings.TrimSpace(prose) != "" {
+				text = prose
+			}
+		}
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: text}},
+		},
+	}
+}
+
+// formatNarrative renders a timeline as a templated narrative, one
+// paragraph per period: what was decided, learned, and explored.
+func formatNarrative(t *memory.Timeline) string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "# Timeline %s to %s\n\n", t.From.Local().Format("2006-01-02"), t.To.Local().Format("2006-01-02"))
+	if t.Total() == 0 {
+		output.WriteString("Nothing was decided, learned, or explored in this period.\n")
+		return output.String()
+	}
+
+	for i := range t.Periods {
+		p := &t.Periods[i]
+		if t.Weekly {
+			fmt.Fprintf(&output, "## Week of %s\n\n", p.Start.Format("2006-01-02"))
+		} else {
+			fmt.Fprintf(&output, "## %s\n\n", p.Start.Format("Monday 2006-01-02"))
+		}
+
+		var sentences []string
+		if len(p.Decisions) > 0 {
+			items := make([]string, len(p.Decisions))
+			for j := range p.Decisions {
+				items[j] = narrativeItem(p.Decisions[j].Content, p.Decisions[j].ID)
+			}
+			sentences = append(sentences, "You decided: "+strings.Join(items, "; ")+".")
+		}
+		if len(p.Ideas) > 0 {
+			items := make([]string, len(p.Ideas))
+			for j := range p.Ideas {
+				items[j] = narrativeItem(p.Ideas[j].Content, p.Ideas[j].ID)
+			}
+			sentences = append(sentences, "You explored: "+strings.Join(items, "; ")+".")
+		}
+		if len(p.Learnings) > 0 {
+			items := make([]string, len(p.Learnings))
+			for j := range p.Learnings {
+				items[j] = narrativeItem(p.Learnings[j].Content, p.Learnings[j].ID)
+			}
+			sentences = append(sentences, "You learned: "+strings.Join(items, "; ")+".")
+		}
+		output.WriteString(strings.Join(sentences, " "))
+		output.WriteString("\n\n")
+	}
+	if t.Truncated {
+		output.WriteString("_Older records in this range were left out; narrow the range or raise the limit to include them._\n")
+	}
+	return output.String()
+}
+
+// narrativeItem is one record in a narrative sentence.
+func narrativeItem(content, id string) string {
+	return fmt.Sprintf("%s (`%s`)", strings.TrimRight(strings.TrimSpace(content), ".;"), id)
+}
+
+// buildNarrativePrompt asks the LLM to retell a templated narrative.
+func buildNarrativePrompt(narrative string) string {
+	var prompt strings.Builder
+	prompt.WriteString("You are a knowledge assistant for a software project. ")
+	prompt.WriteString("Rewrite the following timeline as a short chronological narrative for standup notes, ")
+	prompt.WriteString("addressed to the developer (\"This week you decided ..., explored ..., learned ...\"). ")
+	prompt.WriteString("Keep one markdown section per period, keep every record ID in backticks, and do not add facts.\n\n")
+	prompt.WriteString(narrative)
+	return prompt.String()
+}
+
+// parseNarrativeTime parses a narrative bound: a date (2006-01-02), an
+// RFC 3339 time, or a duration back from now such as 24h, 7d, or 2w. An
+// empty value is def.
+func parseNarrativeTime(value string, now, def time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return def, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return time.Time{}, fmt.Errorf("%q is not a date or duration", value)
+			}
+			return now.Add(-time.Duration(count) * unit), nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("%q is not a date or duration", value)
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,69 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallNarrative(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	now := time.Now()
+	if _, err := b.memory.AddDecision(memory.Decision{
+		Content: "Use SQLite for the index", Scope: "palace",
+		Authority: string(memory.AuthorityApproved), CreatedAt: now.Add(-2 * 24 * time.Hour),
+	}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := b.memory.AddLearning(memory.Learning{
+		Content: "Retries use exponential backoff.", Scope: "palace", Confidence: 0.8,
+		Authority: string(memory.AuthorityApproved), CreatedAt: now.Add(-time.Hour),
+	}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	if _, err := b.memory.AddLearning(memory.Learning{
+		Content: "The old cache was flaky", Scope: "palace", Confidence: 0.8,
+		Authority: string(memory.AuthorityApproved), CreatedAt: now.Add(-30 * 24 * time.Hour),
+	}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"narrative": true, "since": "7d"}))
+	decided := strings.Index(text, "You decided: Use SQLite for the index")
+	learned := strings.Index(text, "You learned: Retries use exponential backoff (`")
+	if decided < 0 || learned < 0 {
+		t.Fatalf("expected the decision and learning in the narrative:\n%s", text)
+	}
+	if decided > learned {
+		t.Errorf("expected the narrative in chronological order:\n%s", text)
+	}
+	if strings.Contains(text, "old cache") {
+		t.Errorf("learning from before the range should not be narrated:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(1, map[string]interface{}{"narrative": true, "since": "60d", "period": "week"}))
+	if !strings.Contains(text, "## Week of ") || !strings.Contains(text, "old cache") {
+		t.Errorf("expected a weekly narrative including the older learning:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(1, map[string]interface{}{"narrative": true, "since": "60d", "limit": float64(1)}))
+	if !strings.Contains(text, "Retries use exponential backoff") || strings.Contains(text, "old cache") || !strings.Contains(text, "were left out") {
+		t.Errorf("expected limit 1 to keep only the most recent learning, noting the rest:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(1, map[string]interface{}{"narrative": true, "since": "90d", "until": "45d"}))
+	if !strings.Contains(text, "Nothing was decided") {
+		t.Errorf("expected an empty narrative for a range without records:\n%s", text)
+	}
+
+	resp := server.toolRecall(1, map[string]interface{}{"narrative": true, "since": "last tuesday"})
+	if result, ok := resp.Result.(mcpToolResult); !ok || !result.IsError {
+		t.Errorf("expected an error for an invalid since, got %+v", resp.Result)
+	}
+}
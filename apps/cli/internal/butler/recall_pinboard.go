@@ -0,0 +1,42 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// recallPinned lists the pinned records in pin order. The query and scope
+// narrow the list only when the caller gave them.
+func (s *MCPServer) recallPinned(id any, filter memory.PinFilter, limit int) jsonRPCResponse {
+	pinned, err := s.butler.memory.Pinned(filter)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("list pinned records failed: %v", err))
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "# Pinned (%d)\n\n", len(pinned))
+	if len(pinned) == 0 {
+		output.WriteString("Nothing pinned. Tag a record \"pinned\" to keep it here.\n")
+	}
+	for i, p := range pinned {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(&output, "\n_…and %d more pinned records._\n", len(pinned)-limit)
+			break
+		}
+		scopeInfo := p.Scope
+		if p.ScopePath != "" {
+			scopeInfo = fmt.Sprintf("%s:%s", p.Scope, p.ScopePath)
+		}
+		fmt.Fprintf(&output, "- `%s` (%s, %s) %s\n", p.ID, p.Kind, scopeInfo, p.Content)
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
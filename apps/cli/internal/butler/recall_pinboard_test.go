@@ -0,0 +1,46 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallPinnedOnly(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	pinnedID, err := b.memory.AddLearning(memory.Learning{
+		Content: "Retries use exponential backoff with jitter", Scope: "palace", Confidence: 0.8,
+		Authority: string(memory.AuthorityApproved),
+	})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	if err := b.memory.AddTag(pinnedID, "learning", memory.PinnedTag); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+	unpinnedID, err := b.memory.AddLearning(memory.Learning{
+		Content: "Retries use exponential backoff without jitter", Scope: "palace", Confidence: 0.8,
+		Authority: string(memory.AuthorityApproved),
+	})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	for _, args := range []map[string]interface{}{
+		{"pinnedOnly": true},
+		{"pinnedOnly": true, "query": "exponential backoff"},
+	} {
+		text := toolText(t, server.toolRecall(1, args))
+		if !strings.Contains(text, pinnedID) || !strings.Contains(text, "Pinned (1)") {
+			t.Errorf("recall %v should list the pinned learning:\n%s", args, text)
+		}
+		if strings.Contains(text, unpinnedID) {
+			t.Errorf("recall %v should leave out the unpinned learning:\n%s", args, text)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallBooleanQuery(t *testing.T) {
+	server, b := setupMCPServer(t)
+	add := func(content string) string {
+		id, err := b.memory.AddLearning(memory.Learning{
+			Content: content, Scope: "palace", Confidence: 0.8,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		return id
+	}
+	redisID := add("redis backs the caching layer")
+	memcachedID := add("memcached caching for rendered pages")
+	otherID := add("postgres caching is not worth it")
+	phraseID := add("Retry with exponential backoff")
+	scrambledID := add("Backoff exponential, then retry")
+
+	recall := func(query string) string {
+		return toolText(t, server.toolRecall(1, map[string]interface{}{"query": query, "limit": float64(10)}))
+	}
+
+	out := recall("(redis OR memcached) AND caching")
+	if !strings.Contains(out, redisID) || !strings.Contains(out, memcachedID) || strings.Contains(out, otherID) {
+		t.Errorf("OR grouping should return %s and %s only, got:\n%s", redisID, memcachedID, out)
+	}
+	out = recall(`"exponential backoff"`)
+	if !strings.Contains(out, phraseID) || strings.Contains(out, scrambledID) {
+		t.Errorf("phrase should return only %s, got:\n%s", phraseID, out)
+	}
+
+	// Queries that are not valid boolean syntax are plain searches
+	resp := server.toolRecall(1, map[string]interface{}{"query": "(redis OR"})
+	if result, ok := resp.Result.(mcpToolResult); !ok || result.IsError {
+		t.Errorf("expected a plain search for a malformed query, got %+v", resp.Result)
+	}
+	initID := add("Call init() before the first request")
+	if out := recall("init()"); !strings.Contains(out, initID) {
+		t.Errorf("init() should be a plain search returning %s, got:\n%s", initID, out)
+	}
+}
@@ -0,0 +1,45 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// recallAsQuestions serves recall's spaced-review mode: learnings come back
+// as review questions, least recently reviewed first, and are marked reviewed
+// so the next call moves on to others.
+func (s *MCPServer) recallAsQuestions(id any, scope, scopePath string, limit int) jsonRPCResponse {
+	prompts, err := s.butler.memory.GetReviewPrompts(scope, scopePath, limit)
+	if err != nil {
+		return s.toolError(id, fmt.Sprintf("get review prompts failed: %v", err))
+	}
+
+	var output strings.Builder
+	output.WriteString("# Review\n\n")
+	if len(prompts) == 0 {
+		output.WriteString("No learnings to review.\n")
+	}
+	ids := make([]string, len(prompts))
+	for i, p := range prompts {
+		ids[i] = p.Learning.ID
+		fmt.Fprintf(&output, "%d. %s\n", i+1, p.Question)
+		if p.LastReviewedAt.IsZero() {
+			fmt.Fprintf(&output, "   _`%s` · never reviewed_\n", p.Learning.ID)
+		} else {
+			fmt.Fprintf(&output, "   _`%s` · last reviewed %s (%d times)_\n",
+				p.Learning.ID, p.LastReviewedAt.Format("2006-01-02"), p.ReviewCount)
+		}
+	}
+
+	if err := s.butler.memory.MarkReviewed(ids...); err != nil {
+		return s.toolError(id, fmt.Sprintf("record review failed: %v", err))
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallAsQuestions(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	for _, content := range []string{"Retries use exponential backoff", "Migrations run in a transaction"} {
+		if _, err := b.memory.AddLearning(memory.Learning{
+			Content: content, Scope: "palace", Confidence: 0.8,
+			Authority: string(memory.AuthorityApproved),
+		}); err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"asQuestions": true, "limit": float64(1)}))
+	if !strings.Contains(text, "Do you remember:") || !strings.Contains(text, "never reviewed") {
+		t.Fatalf("expected an unreviewed review question:\n%s", text)
+	}
+	first := "Retries use exponential backoff"
+	if !strings.Contains(text, first) {
+		first = "Migrations run in a transaction"
+	}
+
+	// The question just asked was recorded, so the other learning comes next
+	text = toolText(t, server.toolRecall(2, map[string]interface{}{"asQuestions": true, "limit": float64(1)}))
+	if strings.Contains(text, first) || !strings.Contains(text, "never reviewed") {
+		t.Errorf("expected the other, never-reviewed learning:\n%s", text)
+	}
+}
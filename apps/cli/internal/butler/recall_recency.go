@@ -0,0 +1,24 @@
+package butler
+
+import (
+	"math"
+	"time"
+)
+
+// maxRecencyBoost is the priority added for a learning used just now. The
+// boost halves every half-life after that.
+const maxRecencyBoost = 0.2
+
+// recencyBoost returns the priority boost for a learning last used at
+// lastUsed. A zero half-life disables the boost, so recency does not affect
+// ranking.
+func recencyBoost(lastUsed, now time.Time, halfLife time.Duration) float64 {
+	if halfLife <= 0 || lastUsed.IsZero() {
+		return 0
+	}
+	age := now.Sub(lastUsed)
+	if age < 0 {
+		age = 0
+	}
+	return maxRecencyBoost * math.Exp2(-float64(age)/float64(halfLife))
+}
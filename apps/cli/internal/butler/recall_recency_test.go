@@ -0,0 +1,40 @@
+package butler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecencyBoostHalfLife(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-60 * 24 * time.Hour)
+
+	// With equal confidence, the gap between a recent and an old learning
+	// comes entirely from the recency boost.
+	gap := func(halfLife time.Duration) float64 {
+		return recencyBoost(recent, now, halfLife) - recencyBoost(old, now, halfLife)
+	}
+
+	short, long := gap(7*24*time.Hour), gap(90*24*time.Hour)
+	if short <= 0 || long <= 0 {
+		t.Fatalf("recent learning should rank ahead: short gap %.4f, long gap %.4f", short, long)
+	}
+	if short <= long {
+		t.Errorf("shorter half-life should widen the gap: short %.4f, long %.4f", short, long)
+	}
+
+	if got := recencyBoost(now, now, 30*24*time.Hour); got != maxRecencyBoost {
+		t.Errorf("boost at age 0 = %.4f, want %.4f", got, maxRecencyBoost)
+	}
+	if got := recencyBoost(now.Add(-30*24*time.Hour), now, 30*24*time.Hour); got < maxRecencyBoost/2-1e-9 || got > maxRecencyBoost/2+1e-9 {
+		t.Errorf("boost at one half-life = %.4f, want %.4f", got, maxRecencyBoost/2)
+	}
+}
+
+func TestRecencyBoostDisabled(t *testing.T) {
+	now := time.Now()
+	if gap := recencyBoost(now, now, 0) - recencyBoost(now.Add(-365*24*time.Hour), now, 0); gap != 0 {
+		t.Errorf("zero half-life should not affect ranking, gap %.4f", gap)
+	}
+}
@@ -0,0 +1,148 @@
+package butler
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// workspaceMemory is the memory store of one workspace in a related recall.
+type workspaceMemory struct {
+	name string
+	mem  *memory.Memory
+}
+
+// relatedMemories opens the stores of the named workspaces: this one, by
+// its directory name, and those declared under relatedWorkspaces. With no
+// names it opens this workspace and every declared one. Declared
+// workspaces without a store are skipped and returned by name. The
+// returned function closes the stores it opened.
+func (b *Butler) relatedMemories(names []string) ([]workspaceMemory, []string, func(), error) {
+	self := b.GetWorkspaceName()
+	roots := b.config.RelatedWorkspaceRoots(b.root)
+	if len(names) == 0 {
+		names = append(names, self)
+		declared := make([]string, 0, len(roots))
+		for name := range roots {
+			if name != self {
+				declared = append(declared, name)
+			}
+		}
+		sort.Strings(declared)
+		names = append(names, declared...)
+	}
+
+	var opened []*memory.Memory
+	closeAll := func() {
+		for _, m := range opened {
+			m.Close()
+		}
+	}
+	var stores []workspaceMemory
+	var missing []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if name == self {
+			stores = append(stores, workspaceMemory{name: name, mem: b.memory})
+			continue
+		}
+		root, ok := roots[name]
+		if !ok {
+			closeAll()
+			return nil, nil, nil, fmt.Errorf("unknown workspace %q: declare it under relatedWorkspaces in palace.jsonc", name)
+		}
+		if _, err := os.Stat(config.MemoryDBPath(root)); err != nil {
+			missing = append(missing, name)
+			continue
+		}
+		mem, err := memory.Open(root)
+		if err != nil {
+			closeAll()
+			return nil, nil, nil, fmt.Errorf("open memory of workspace %q: %w", name, err)
+		}
+		opened = append(opened, mem)
+		stores = append(stores, workspaceMemory{name: name, mem: mem})
+	}
+	return stores, missing, closeAll, nil
+}
+
+// recallRelated recalls the learnings and active decisions of several
+// workspaces, each labeled with the workspace it came from. The query, or
+// else the scope, applies in every workspace, and limit caps each kind per
+// workspace.
+func (s *MCPServer) recallRelated(id any, names []string, query, scope, scopePath string, limit int) jsonRPCResponse {
+	stores, missing, closeAll, err := s.butler.relatedMemories(names)
+	if err != nil {
+		return s.toolError(id, err.Error())
+	}
+	defer closeAll()
+
+	var output strings.Builder
+	workspaces := make([]string, len(stores))
+	for i, ws := range stores {
+		workspaces[i] = ws.name
+	}
+	fmt.Fprintf(&output, "# Related recall (%s)\n\n", strings.Join(workspaces, ", "))
+	if len(missing) > 0 {
+		fmt.Fprintf(&output, "_No memory store in: %s._\n\n", strings.Join(missing, ", "))
+	}
+
+	var decisions, learnings []string
+	for _, ws := range stores {
+		var ds []memory.Decision
+		var ls []memory.Learning
+		if query != "" {
+			ds, err = ws.mem.SearchDecisionsWithAuthority(query, limit, true)
+			if err == nil {
+				ls, err = ws.mem.SearchLearningsWithAuthority(query, limit, true)
+			}
+		} else {
+			ds, err = ws.mem.GetDecisionsWithAuthority(memory.DecisionStatusActive, "", scope, scopePath, limit, true)
+			if err == nil {
+				ls, err = ws.mem.GetLearningsWithAuthority(scope, scopePath, limit, true)
+			}
+		}
+		if err != nil {
+			return s.toolError(id, fmt.Sprintf("recall from workspace %q failed: %v", ws.name, err))
+		}
+		for i := range ds {
+			decisions = append(decisions, fmt.Sprintf("- **[%s]** `%s` %s\n", ws.name, ds[i].ID, ds[i].Content))
+		}
+		for i := range ls {
+			learnings = append(learnings, fmt.Sprintf("- **[%s]** `%s` (%.0f%% confidence) %s\n", ws.name, ls[i].ID, ls[i].Confidence*100, ls[i].Content))
+		}
+	}
+
+	if len(decisions)+len(learnings) == 0 {
+		output.WriteString("Nothing found in these workspaces.\n")
+	}
+	if len(decisions) > 0 {
+		output.WriteString("## Decisions\n\n")
+		for _, d := range decisions {
+			output.WriteString(d)
+		}
+		output.WriteString("\n")
+	}
+	if len(learnings) > 0 {
+		output.WriteString("## Learnings\n\n")
+		for _, l := range learnings {
+			output.WriteString(l)
+		}
+	}
+
+	return jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: mcpToolResult{
+			Content: []mcpContent{{Type: "text", Text: output.String()}},
+		},
+	}
+}
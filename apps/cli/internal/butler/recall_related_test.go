@@ -0,0 +1,61 @@
+package butler
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestToolRecallRelatedWorkspaces(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	self := b.GetWorkspaceName()
+
+	mobileRoot := filepath.Join(t.TempDir(), "mobile")
+	mobile, err := memory.Open(mobileRoot)
+	if err != nil {
+		t.Fatalf("open mobile memory: %v", err)
+	}
+	if _, err := mobile.AddDecision(memory.Decision{
+		Content: "Auth tokens expire after 15 minutes", Authority: string(memory.AuthorityApproved),
+	}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	mobile.Close()
+
+	if _, err := b.memory.AddLearning(memory.Learning{
+		Content: "Refresh auth tokens before they expire", Scope: "palace", Confidence: 0.8,
+		Authority: string(memory.AuthorityApproved),
+	}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	b.config = &config.PalaceConfig{RelatedWorkspaces: map[string]string{"mobile": mobileRoot, "web": "../web"}}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	for _, args := range []map[string]interface{}{
+		{"workspaces": []interface{}{self, "mobile"}, "query": "auth tokens"},
+		{"related": true, "query": "auth tokens"},
+	} {
+		text := toolText(t, server.toolRecall(1, args))
+		if !strings.Contains(text, "**[mobile]**") || !strings.Contains(text, "expire after 15 minutes") {
+			t.Errorf("recall %v should list the mobile decision labeled by workspace:\n%s", args, text)
+		}
+		if !strings.Contains(text, "**["+self+"]**") || !strings.Contains(text, "Refresh auth tokens") {
+			t.Errorf("recall %v should list this workspace's learning labeled by workspace:\n%s", args, text)
+		}
+	}
+
+	// "web" is declared but has no store yet.
+	if text := toolText(t, server.toolRecall(1, map[string]interface{}{"related": true})); !strings.Contains(text, "No memory store in: web") {
+		t.Errorf("related recall should note the workspace without a store:\n%s", text)
+	}
+
+	resp := server.toolRecall(1, map[string]interface{}{"workspaces": []interface{}{"billing"}})
+	if result, ok := resp.Result.(mcpToolResult); !ok || !result.IsError {
+		t.Errorf("recall from an undeclared workspace should fail, got %+v", resp.Result)
+	}
+}
@@ -0,0 +1,201 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// Tag matching modes for recall's tag filter.
+const (
+	tagModeExact  = "exact"
+	tagModePrefix = "prefix"
+	tagModeFuzzy  = "fuzzy"
+)
+
+// matchTags returns the tags in candidates that the queried tag selects.
+// Exact requires equality, prefix accepts any tag the query starts
+// ("perf" selects "performance"), and fuzzy accepts prefixes plus tags
+// within a small edit distance, so typos like "databse" still match.
+func matchTags(query, mode string, candidates []string) ([]string, error) {
+	switch mode {
+	case "", tagModeExact, tagModePrefix, tagModeFuzzy:
+	default:
+		return nil, fmt.Errorf("unknown tagMode %q (use exact, prefix, or fuzzy)", mode)
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matches []string
+	for _, tag := range candidates {
+		var ok bool
+		switch mode {
+		case tagModePrefix:
+			ok = strings.HasPrefix(tag, query)
+		case tagModeFuzzy:
+			ok = strings.HasPrefix(tag, query) || LevenshteinDistance(query, tag) <= fuzzyTagDistance(query)
+		default:
+			ok = tag == query
+		}
+		if ok {
+			matches = append(matches, tag)
+		}
+	}
+	return matches, nil
+}
+
+// fuzzyTagDistance is the edit distance tolerated for a tag of this length.
+// Short tags allow a single edit so that unrelated short tags don't match.
+func fuzzyTagDistance(tag string) int {
+	if len([]rune(tag)) <= 4 {
+		return 1
+	}
+	return 2
+}
+
+// tagFilter selects records by their tags: those carrying every tag in All,
+// at least one in Any (when Any is set), and none in None.
+type tagFilter struct {
+	All, Any, None []string
+}
+
+func (f tagFilter) empty() bool {
+	return len(f.All) == 0 && len(f.Any) == 0 && len(f.None) == 0
+}
+
+// parseTagFilter reads recall's tag argument: a single tag, a list of tags
+// that must all be present, or an object of "all", "any", and "none" lists.
+func parseTagFilter(raw interface{}) (tagFilter, error) {
+	var f tagFilter
+	switch v := raw.(type) {
+	case nil:
+	case string:
+		if v != "" {
+			f.All = []string{v}
+		}
+	case []interface{}:
+		tags, err := tagList("tag", v)
+		if err != nil {
+			return f, err
+		}
+		f.All = tags
+	case map[string]interface{}:
+		for key, val := range v {
+			list, ok := val.([]interface{})
+			if !ok {
+				return f, fmt.Errorf("tag.%s must be a list of tags", key)
+			}
+			tags, err := tagList("tag."+key, list)
+			if err != nil {
+				return f, err
+			}
+			switch key {
+			case "all":
+				f.All = tags
+			case "any":
+				f.Any = tags
+			case "none":
+				f.None = tags
+			default:
+				return f, fmt.Errorf("unknown tag filter %q (use all, any, or none)", key)
+			}
+		}
+	default:
+		return f, fmt.Errorf("tag must be a tag, a list of tags, or an object with all, any, and none")
+	}
+	return f, nil
+}
+
+func tagList(name string, raw []interface{}) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	for _, item := range raw {
+		tag, ok := item.(string)
+		if !ok || strings.TrimSpace(tag) == "" {
+			return nil, fmt.Errorf("%s must hold non-empty strings", name)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// filterLearningsByTags keeps the learnings the filter selects, each of its
+// tags matched under mode, preserving their order.
+func (s *MCPServer) filterLearningsByTags(learnings []memory.Learning, filter tagFilter, mode string) ([]memory.Learning, error) {
+	allTags, err := s.butler.memory.GetAllTags("learning")
+	if err != nil {
+		return nil, err
+	}
+	tagged := func(tag string) (map[string]bool, error) {
+		matched, err := matchTags(tag, mode, allTags)
+		if err != nil {
+			return nil, err
+		}
+		ids := make(map[string]bool)
+		for _, t := range matched {
+			recordIDs, err := s.butler.memory.GetRecordsByTag(t, "learning")
+			if err != nil {
+				return nil, err
+			}
+			for _, recordID := range recordIDs {
+				ids[recordID] = true
+			}
+		}
+		return ids, nil
+	}
+
+	var all, none []map[string]bool
+	for _, tag := range filter.All {
+		ids, err := tagged(tag)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, ids)
+	}
+	for _, tag := range filter.None {
+		ids, err := tagged(tag)
+		if err != nil {
+			return nil, err
+		}
+		none = append(none, ids)
+	}
+	var anyIDs map[string]bool
+	if len(filter.Any) > 0 {
+		anyIDs = make(map[string]bool)
+		for _, tag := range filter.Any {
+			ids, err := tagged(tag)
+			if err != nil {
+				return nil, err
+			}
+			for recordID := range ids {
+				anyIDs[recordID] = true
+			}
+		}
+	}
+
+	var filtered []memory.Learning
+	for i := range learnings {
+		if selectedByTags(learnings[i].ID, all, anyIDs, none) {
+			filtered = append(filtered, learnings[i])
+		}
+	}
+	return filtered, nil
+}
+
+// selectedByTags reports whether the record is in every set of all, in
+// anyOf when it is non-nil, and in no set of none.
+func selectedByTags(recordID string, all []map[string]bool, anyOf map[string]bool, none []map[string]bool) bool {
+	for _, ids := range all {
+		if !ids[recordID] {
+			return false
+		}
+	}
+	if anyOf != nil && !anyOf[recordID] {
+		return false
+	}
+	for _, ids := range none {
+		if ids[recordID] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestMatchTags(t *testing.T) {
+	candidates := []string{"database", "perf", "performance", "security"}
+
+	tests := []struct {
+		query, mode string
+		want        []string
+	}{
+		{"perf", "", []string{"perf"}},
+		{"perf", tagModeExact, []string{"perf"}},
+		{"perf", tagModePrefix, []string{"perf", "performance"}},
+		{"databse", tagModeExact, nil},
+		{"databse", tagModePrefix, nil},
+		{"databse", tagModeFuzzy, []string{"database"}},
+		{"PERF", tagModePrefix, []string{"perf", "performance"}},
+		{"xyz", tagModeFuzzy, nil},
+	}
+	for _, tt := range tests {
+		got, err := matchTags(tt.query, tt.mode, candidates)
+		if err != nil {
+			t.Fatalf("matchTags(%q, %q) error: %v", tt.query, tt.mode, err)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("matchTags(%q, %q) = %v, want %v", tt.query, tt.mode, got, tt.want)
+		}
+	}
+
+	if _, err := matchTags("perf", "regex", candidates); err == nil {
+		t.Error("expected error for unknown tagMode")
+	}
+}
+
+func TestToolRecallTagMode(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	for content, tag := range map[string]string{
+		"Cache compiled templates":        "performance",
+		"Migrations run in a transaction": "database",
+		"Escape user input in templates":  "security",
+	} {
+		id, err := b.memory.AddLearning(memory.Learning{
+			Content: content, Scope: "palace", Confidence: 0.8,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		if err := b.memory.SetTags(id, "learning", []string{tag}); err != nil {
+			t.Fatalf("SetTags failed: %v", err)
+		}
+	}
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"tag": "perf"}))
+	if !strings.Contains(text, "No learnings found") {
+		t.Errorf("exact mode should not match perf to performance:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(2, map[string]interface{}{"tag": "perf", "tagMode": "prefix"}))
+	if !strings.Contains(text, "Cache compiled templates") || strings.Contains(text, "Escape user input") {
+		t.Errorf("prefix mode should match only performance:\n%s", text)
+	}
+
+	text = toolText(t, server.toolRecall(3, map[string]interface{}{"tag": "databse", "tagMode": "fuzzy"}))
+	if !strings.Contains(text, "Migrations run in a transaction") || strings.Contains(text, "Cache compiled") {
+		t.Errorf("fuzzy mode should tolerate databse -> database:\n%s", text)
+	}
+
+	resp := server.toolRecall(4, map[string]interface{}{"tag": "perf", "tagMode": "regex"})
+	if result, ok := resp.Result.(mcpToolResult); !ok || !result.IsError {
+		t.Errorf("expected an error for unknown tagMode, got %+v", resp)
+	}
+}
+
+func TestToolRecallTagFilter(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+
+	for content, tags := range map[string][]string{
+		"Index queries by tenant":         {"db", "perf"},
+		"Old sharding plan":               {"db", "perf", "obsolete"},
+		"Migrations run in a transaction": {"db"},
+		"Escape user input in templates":  {"security"},
+		"Cache compiled templates":        {"perf"},
+	} {
+		id, err := b.memory.AddLearning(memory.Learning{
+			Content: content, Scope: "palace", Confidence: 0.8,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		if err := b.memory.SetTags(id, "learning", tags); err != nil {
+			t.Fatalf("SetTags failed: %v", err)
+		}
+	}
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	recall := func(tag interface{}) string {
+		return toolText(t, server.toolRecall(1, map[string]interface{}{"tag": tag, "limit": float64(10)}))
+	}
+	assertContents := func(name, text string, want ...string) {
+		t.Helper()
+		for _, content := range []string{"Index queries by tenant", "Old sharding plan", "Migrations run in a transaction", "Escape user input in templates", "Cache compiled templates"} {
+			wanted := false
+			for _, w := range want {
+				wanted = wanted || w == content
+			}
+			if strings.Contains(text, content) != wanted {
+				t.Errorf("%s: %q included = %v, want %v:\n%s", name, content, !wanted, wanted, text)
+			}
+		}
+	}
+
+	assertContents("all+none", recall(map[string]interface{}{
+		"all":  []interface{}{"db", "perf"},
+		"none": []interface{}{"obsolete"},
+	}), "Index queries by tenant")
+
+	assertContents("any", recall(map[string]interface{}{
+		"any": []interface{}{"security", "obsolete"},
+	}), "Old sharding plan", "Escape user input in templates")
+
+	assertContents("any+none", recall(map[string]interface{}{
+		"any":  []interface{}{"db", "security"},
+		"none": []interface{}{"perf"},
+	}), "Migrations run in a transaction", "Escape user input in templates")
+
+	// A plain list keeps meaning "all".
+	assertContents("list", recall([]interface{}{"db", "perf"}), "Index queries by tenant", "Old sharding plan")
+
+	for _, bad := range []interface{}{
+		map[string]interface{}{"some": []interface{}{"db"}},
+		map[string]interface{}{"all": "db"},
+		[]interface{}{"db", 3.0},
+		42.0,
+	} {
+		if resp := server.toolRecall(2, map[string]interface{}{"tag": bad}); !resp.Result.(mcpToolResult).IsError {
+			t.Errorf("tag %v should be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package butler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// recallRecord is the view of a record that recall templates render.
+type recallRecord struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	Content    string    `json:"content"`
+	Scope      string    `json:"scope"`
+	ScopePath  string    `json:"scopePath,omitempty"`
+	Confidence float64   `json:"confidence"`
+	Source     string    `json:"source"`
+	Authority  string    `json:"authority"`
+	UseCount   int       `json:"useCount"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsed   time.Time `json:"lastUsed"`
+}
+
+func learningRecord(l *memory.Learning) recallRecord {
+	return recallRecord{
+		ID:         l.ID,
+		Kind:       "learning",
+		Content:    l.Content,
+		Scope:      l.Scope,
+		ScopePath:  l.ScopePath,
+		Confidence: l.Confidence,
+		Source:     l.Source,
+		Authority:  l.Authority,
+		UseCount:   l.UseCount,
+		CreatedAt:  l.CreatedAt,
+		LastUsed:   l.LastUsed,
+	}
+}
+
+// recallTemplate renders recall results in a named preset or with a custom
+// text/template executed once per record.
+type recallTemplate struct {
+	preset string
+	custom *template.Template
+}
+
+// parseRecallTemplate resolves the recall "template" argument. An empty
+// value or "markdown" selects the default format and returns nil. Custom
+// templates are checked against a sample record up front, so a reference to
+// a field that does not exist fails even when nothing matches.
+func parseRecallTemplate(spec string) (*recallTemplate, error) {
+	switch spec {
+	case "", "markdown":
+		return nil, nil
+	case "list", "table", "json":
+		return &recallTemplate{preset: spec}, nil
+	}
+	tmpl, err := template.New("recall").Option("missingkey=error").Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, recallRecord{}); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return &recallTemplate{custom: tmpl}, nil
+}
+
+// entry renders a single record.
+func (t *recallTemplate) entry(r recallRecord) (string, error) {
+	switch t.preset {
+	case "list":
+		return fmt.Sprintf("- `%s` %s\n", r.ID, r.Content), nil
+	case "table":
+		scope := r.Scope
+		if r.ScopePath != "" {
+			scope += ":" + r.ScopePath
+		}
+		return fmt.Sprintf("| `%s` | %s | %s | %.0f%% | %s |\n",
+			r.ID, r.Kind, tableCell(scope), r.Confidence*100, tableCell(r.Content)), nil
+	case "json":
+		data, err := json.Marshal(r)
+		return string(data), err
+	}
+	var sb strings.Builder
+	if err := t.custom.Execute(&sb, r); err != nil {
+		return "", fmt.Errorf("render %s: %w", r.ID, err)
+	}
+	if !strings.HasSuffix(sb.String(), "\n") {
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// render assembles rendered entries into the final output. JSON output is a
+// single object so scripts can parse it; other formats end with the usual
+// note when more records are available.
+func (t *recallTemplate) render(entries []string, hasMore bool, nextCursor int) string {
+	var sb strings.Builder
+	switch t.preset {
+	case "json":
+		fmt.Fprintf(&sb, "{\"records\": [%s], \"hasMore\": %t", strings.Join(entries, ", "), hasMore)
+		if hasMore {
+			fmt.Fprintf(&sb, ", \"nextCursor\": %d", nextCursor)
+		}
+		sb.WriteString("}\n")
+		return sb.String()
+	case "table":
+		sb.WriteString("| ID | Kind | Scope | Confidence | Content |\n|---|---|---|---|---|\n")
+	}
+	for _, entry := range entries {
+		sb.WriteString(entry)
+	}
+	if hasMore {
+		fmt.Fprintf(&sb, "_More available: call recall again with `cursor: %d`._\n", nextCursor)
+	}
+	return sb.String()
+}
+
+// tableCell keeps a value on one line and from breaking the table.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// FormatLearnings renders learnings the way recall does for the template
+// spec: "" or "markdown" for the default format, "list", "table", or "json"
+// for a preset, or a custom text/template executed once per learning.
+// Callers outside the MCP server use it to export recall results.
+func FormatLearnings(learnings []memory.Learning, spec string) (string, error) {
+	tmpl, err := parseRecallTemplate(spec)
+	if err != nil {
+		return "", err
+	}
+	entries := make([]string, len(learnings))
+	for i := range learnings {
+		if tmpl == nil {
+			entries[i] = formatLearningEntry(&learnings[i], 0)
+			continue
+		}
+		if entries[i], err = tmpl.entry(learningRecord(&learnings[i])); err != nil {
+			return "", fmt.Errorf("template failed: %w", err)
+		}
+	}
+	if tmpl != nil {
+		return tmpl.render(entries, false, 0), nil
+	}
+	if len(entries) == 0 {
+		return "# Learnings\n\nNo learnings found.\n", nil
+	}
+	return "# Learnings\n\n" + strings.Join(entries, ""), nil
+}
@@ -0,0 +1,74 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func addTemplateLearnings(t *testing.T, b *Butler) []string {
+	t.Helper()
+	var ids []string
+	for _, content := range []string{"Cache keys include the tenant", "Cache | entries expire hourly"} {
+		learningID, err := b.memory.AddLearning(memory.Learning{
+			Content: content, Scope: "palace", Confidence: 0.8,
+			Authority: string(memory.AuthorityApproved),
+		})
+		if err != nil {
+			t.Fatalf("AddLearning failed: %v", err)
+		}
+		ids = append(ids, learningID)
+	}
+	return ids
+}
+
+func TestToolRecallTemplateTable(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	ids := addTemplateLearnings(t, b)
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"query": "Cache", "template": "table"}))
+
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) != 4 || lines[0] != "| ID | Kind | Scope | Confidence | Content |" {
+		t.Fatalf("expected a header, separator, and two rows:\n%s", text)
+	}
+	for _, learningID := range ids {
+		if !strings.Contains(text, "| `"+learningID+"` | learning | palace | 80% |") {
+			t.Errorf("missing row for %s:\n%s", learningID, text)
+		}
+	}
+	if !strings.Contains(text, `Cache \| entries expire hourly`) {
+		t.Errorf("pipes in content should be escaped:\n%s", text)
+	}
+}
+
+func TestToolRecallTemplateCustom(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	ids := addTemplateLearnings(t, b)
+
+	server := NewMCPServerWithMode(b, MCPModeAgent)
+	text := toolText(t, server.toolRecall(1, map[string]interface{}{"query": "Cache", "template": "{{.ID}} {{.Kind}}"}))
+
+	got := strings.Split(strings.TrimSpace(text), "\n")
+	if len(got) != 2 {
+		t.Fatalf("expected one line per record:\n%s", text)
+	}
+	for _, learningID := range ids {
+		if !strings.Contains(text, learningID+" learning\n") {
+			t.Errorf("missing line for %s:\n%s", learningID, text)
+		}
+	}
+	if strings.Contains(text, "Cache") {
+		t.Errorf("custom template should render only IDs and kinds:\n%s", text)
+	}
+
+	resp := server.toolRecall(1, map[string]interface{}{"template": "{{.Nope}}"})
+	result, ok := resp.Result.(mcpToolResult)
+	if !ok || !result.IsError || !strings.Contains(result.Content[0].Text, "invalid template") {
+		t.Errorf("expected an invalid template error, got %+v", resp.Result)
+	}
+}
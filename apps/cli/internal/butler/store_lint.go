@@ -0,0 +1,75 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// Modes for memoryLint.onStore in palace.jsonc.
+const (
+	storeLintOff    = "off"
+	storeLintWarn   = "warn"
+	storeLintReject = "reject"
+)
+
+// storeLint checks a record about to be stored against the memoryLint
+// rules. It returns the violations and whether they should block the store;
+// with onStore unset or "off" nothing is checked.
+func (s *MCPServer) storeLint(kind memory.RecordKind, content, rationale string, tags []string) ([]memory.LintViolation, bool, error) {
+	cfg := s.butler.Config()
+	if cfg == nil || cfg.MemoryLint == nil {
+		return nil, false, nil
+	}
+	mode := cfg.MemoryLint.OnStore
+	switch mode {
+	case "", storeLintOff:
+		return nil, false, nil
+	case storeLintWarn, storeLintReject:
+	default:
+		return nil, false, fmt.Errorf("memoryLint.onStore must be %q, %q, or %q, not %q", storeLintOff, storeLintWarn, storeLintReject, mode)
+	}
+	rules, err := MemoryLintRules(cfg.MemoryLint)
+	if err != nil {
+		return nil, false, err
+	}
+	violations := memory.LintCheck(rules, memory.LintRecord{Kind: string(kind), Content: content, Rationale: rationale, Tags: tags})
+	return violations, mode == storeLintReject && len(violations) > 0, nil
+}
+
+// MemoryLintRules compiles the memoryLint rules set in palace.jsonc.
+// Unnamed rules are named by their position, as rule-1, rule-2, and so on.
+func MemoryLintRules(cfg *config.MemoryLintConfig) ([]memory.LintRule, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	rules := make([]memory.LintRule, 0, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i+1)
+		}
+		rule, err := memory.NewLintRule(name, r.Kind, r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("memory lint rule %q: %w", name, err)
+		}
+		rule.MinLength = r.MinLength
+		rule.MaxLength = r.MaxLength
+		rule.RequiredTags = r.RequiredTags
+		rule.RequireRationale = r.RequireRationale
+		rule.Message = r.Message
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// formatLintViolations lists violations as markdown bullets.
+func formatLintViolations(violations []memory.LintViolation) string {
+	var sb strings.Builder
+	for _, v := range violations {
+		fmt.Fprintf(&sb, "- **%s**: %s\n", v.Rule, strings.Join(v.Problems, "; "))
+	}
+	return sb.String()
+}
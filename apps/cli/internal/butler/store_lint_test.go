@@ -0,0 +1,52 @@
+package butler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+)
+
+func TestToolStoreMemoryLint(t *testing.T) {
+	b, cleanup := setupButlerWithMemory(t)
+	defer cleanup()
+	lint := &config.MemoryLintConfig{Rules: []config.MemoryLintRule{
+		{Name: "decision-prefix", Kind: "decision", Pattern: "^Decided to "},
+	}}
+	b.config = &config.PalaceConfig{MemoryLint: lint}
+	server := NewMCPServerWithMode(b, MCPModeHuman)
+
+	lint.OnStore = "reject"
+	resp := server.toolStore(1, map[string]interface{}{"content": "Use SQLite for the index", "as": "decision"})
+	if result, ok := resp.Result.(mcpToolResult); !ok || !result.IsError {
+		t.Fatalf("a decision breaking a rule should be rejected, got %+v", resp.Result)
+	}
+	if n, _ := b.memory.CountDecisions("", ""); n != 0 {
+		t.Errorf("a rejected decision should not be stored, have %d", n)
+	}
+	if text := toolText(t, server.toolStore(1, map[string]interface{}{"content": "Decided to use SQLite for the index", "as": "decision"})); strings.Contains(text, "Convention Warnings") {
+		t.Errorf("a conforming decision should pass:\n%s", text)
+	}
+
+	lint.OnStore = "warn"
+	text := toolText(t, server.toolStore(1, map[string]interface{}{"content": "Use WAL mode", "as": "decision"}))
+	if !strings.Contains(text, "Convention Warnings") || !strings.Contains(text, "decision-prefix") {
+		t.Errorf("a decision breaking a rule should be stored with a warning:\n%s", text)
+	}
+}
+
+func TestMemoryLintRules(t *testing.T) {
+	rules, err := MemoryLintRules(&config.MemoryLintConfig{Rules: []config.MemoryLintRule{
+		{Name: "decision-prefix", Kind: "decision", Pattern: "^Decided to "},
+		{Kind: "learning", MinLength: 20, RequiredTags: []string{"db"}},
+	}})
+	if err != nil {
+		t.Fatalf("MemoryLintRules() error: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Pattern == nil || rules[1].Name != "rule-2" || rules[1].MinLength != 20 || len(rules[1].RequiredTags) != 1 {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+	if _, err := MemoryLintRules(&config.MemoryLintConfig{Rules: []config.MemoryLintRule{{Kind: "note"}}}); err == nil || !strings.Contains(err.Error(), `"rule-1"`) {
+		t.Errorf("a rule for an unknown kind should be rejected by name, got %v", err)
+	}
+}
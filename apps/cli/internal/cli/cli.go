@@ -10,6 +10,7 @@ import (
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/commands"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/util"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/jsonc"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/model"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/update"
@@ -30,6 +31,13 @@ func validatePort(v int) error           { return flags.ValidatePort(v) }
 
 // Run executes the application given the command-line arguments.
 func Run(args []string) error {
+	args, dataDir, err := extractDataDir(args)
+	if err != nil {
+		return err
+	}
+	if dataDir != "" {
+		config.SetDataDir(dataDir)
+	}
 	if len(args) == 0 {
 		return usage()
 	}
@@ -45,6 +53,8 @@ func Run(args []string) error {
 		return cmdRecall(args[1:])
 	case "brief":
 		return cmdBrief(args[1:])
+	case "context":
+		return cmdContext(args[1:])
 
 	// Setup & Index
 	case "init":
@@ -55,16 +65,38 @@ func Run(args []string) error {
 		return cmdCheck(args[1:])
 	case "stats":
 		return cmdStats(args[1:])
+	case "diff":
+		return cmdDiff(args[1:])
+	case "api-export":
+		return cmdAPIExport(args[1:])
+	case "api-diff":
+		return cmdAPIDiff(args[1:])
+	case "graph":
+		return cmdGraph(args[1:])
+	case "query":
+		return cmdQuery(args[1:])
+	case "export":
+		return cmdExport(args[1:])
+	case "report":
+		return cmdReport(args[1:])
+	case "lint":
+		return cmdLint(args[1:])
+	case "lint-memory":
+		return cmdLintMemory(args[1:])
 
 	// Services
 	case "serve":
 		return cmdServe(args[1:])
 	case "dashboard":
 		return cmdDashboard(args[1:])
+	case "watch":
+		return cmdWatch(args[1:])
 
 	// Agents & Sessions
 	case "session":
 		return cmdSession(args[1:])
+	case "memory":
+		return cmdMemory(args[1:])
 
 	// Cross-workspace
 	case "corridor":
@@ -99,9 +131,9 @@ func Run(args []string) error {
 			return cmdStore(args)
 		}
 		if len(args) > 0 {
-			return fmt.Errorf("unknown command: %s\nRun 'palace help' for usage", args[0])
+			return commands.UsageError(fmt.Errorf("unknown command: %s\nRun 'palace help' for usage", args[0]))
 		}
-		return fmt.Errorf("unknown command\nRun 'palace help' for usage")
+		return commands.UsageError(fmt.Errorf("unknown command\nRun 'palace help' for usage"))
 	}
 }
 
@@ -109,6 +141,12 @@ func usage() error {
 	return commands.ShowUsage()
 }
 
+// ExitCode returns the process exit code for the error Run returned; see
+// the Exit constants in the commands package.
+func ExitCode(err error) int {
+	return commands.ExitCode(err)
+}
+
 // ============================================================================
 // Core Commands - delegating to commands package
 // ============================================================================
@@ -133,6 +171,11 @@ func cmdBrief(args []string) error {
 	return commands.RunBrief(args)
 }
 
+// cmdContext delegates to commands.RunContext
+func cmdContext(args []string) error {
+	return commands.RunContext(args)
+}
+
 // ============================================================================
 // Setup & Index Commands - delegating to commands package
 // ============================================================================
@@ -157,6 +200,51 @@ func cmdStats(args []string) error {
 	return commands.RunStats(args)
 }
 
+// cmdDiff delegates to commands.RunDiff
+func cmdDiff(args []string) error {
+	return commands.RunDiff(args)
+}
+
+// cmdAPIExport delegates to commands.RunAPIExport
+func cmdAPIExport(args []string) error {
+	return commands.RunAPIExport(args)
+}
+
+// cmdAPIDiff delegates to commands.RunAPIDiff
+func cmdAPIDiff(args []string) error {
+	return commands.RunAPIDiff(args)
+}
+
+// cmdGraph delegates to commands.RunGraph
+func cmdGraph(args []string) error {
+	return commands.RunGraph(args)
+}
+
+// cmdQuery delegates to commands.RunQuery
+func cmdQuery(args []string) error {
+	return commands.RunQuery(args)
+}
+
+// cmdExport delegates to commands.RunExport
+func cmdExport(args []string) error {
+	return commands.RunExport(args)
+}
+
+// cmdReport delegates to commands.RunReport
+func cmdReport(args []string) error {
+	return commands.RunReport(args)
+}
+
+// cmdLint delegates to commands.RunLint
+func cmdLint(args []string) error {
+	return commands.RunLint(args)
+}
+
+// cmdLintMemory delegates to commands.RunLintMemory
+func cmdLintMemory(args []string) error {
+	return commands.RunLintMemory(args)
+}
+
 // ============================================================================
 // Service Commands - delegating to commands package
 // ============================================================================
@@ -171,6 +259,11 @@ func cmdDashboard(args []string) error {
 	return commands.RunDashboard(args)
 }
 
+// cmdWatch delegates to commands.RunWatch
+func cmdWatch(args []string) error {
+	return commands.RunWatch(args)
+}
+
 // ============================================================================
 // Session & Corridor Commands - delegating to commands package
 // ============================================================================
@@ -180,6 +273,11 @@ func cmdSession(args []string) error {
 	return commands.RunSession(args)
 }
 
+// cmdMemory delegates to commands.RunMemory
+func cmdMemory(args []string) error {
+	return commands.RunMemory(args)
+}
+
 // cmdCorridor delegates to commands.RunCorridor
 func cmdCorridor(args []string) error {
 	return commands.RunCorridor(args)
@@ -257,6 +355,32 @@ func looksLikeContent(s string) bool {
 	return false
 }
 
+// extractDataDir removes the global --data-dir option from args, wherever it
+// appears, and returns its value. Every command opens its workspace's stores
+// under that directory.
+func extractDataDir(args []string) ([]string, string, error) {
+	var rest []string
+	var dataDir string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--data-dir" || arg == "-data-dir":
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("flag needs an argument: %s", arg)
+			}
+			i++
+			dataDir = args[i]
+		case strings.HasPrefix(arg, "--data-dir=") || strings.HasPrefix(arg, "-data-dir="):
+			dataDir = arg[strings.Index(arg, "=")+1:]
+		case arg == "--":
+			return append(rest, args[i:]...), dataDir, nil
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, dataDir, nil
+}
+
 func checkForUpdates(args []string) {
 	if len(args) == 0 {
 		return
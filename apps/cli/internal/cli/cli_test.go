@@ -260,6 +260,31 @@ func TestRunUnknownCommand(t *testing.T) {
 	}
 }
 
+func TestRunContextDispatched(t *testing.T) {
+	// Without a topic context fails on usage, not as an unknown command
+	err := Run([]string{"context"})
+	if err == nil {
+		t.Fatal("expected usage error for context without a topic")
+	}
+	if strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("context should be dispatched, got: %v", err)
+	}
+}
+
+func TestExtractDataDir(t *testing.T) {
+	rest, dir, err := extractDataDir([]string{"serve", "--data-dir", "/data", "--root", "x"})
+	if err != nil || dir != "/data" || strings.Join(rest, " ") != "serve --root x" {
+		t.Errorf("got %v %q %v", rest, dir, err)
+	}
+	rest, dir, _ = extractDataDir([]string{"--data-dir=/data", "scan"})
+	if dir != "/data" || strings.Join(rest, " ") != "scan" {
+		t.Errorf("got %v %q", rest, dir)
+	}
+	if _, _, err := extractDataDir([]string{"scan", "--data-dir"}); err == nil {
+		t.Error("expected error for --data-dir without a value")
+	}
+}
+
 func TestRunNoArgs(t *testing.T) {
 	// No args should show usage (not error)
 	err := Run([]string{})
@@ -468,7 +493,7 @@ func TestCmdCleanInvalidFlag(t *testing.T) {
 func TestCmdHelpKnownCommands(t *testing.T) {
 	// Only test commands that have help topics defined in cmdHelp
 	commandNames := []string{"init", "scan", "check", "explore", "store", "recall",
-		"brief", "serve", "session", "corridor", "dashboard", "clean"}
+		"brief", "context", "serve", "session", "corridor", "dashboard", "clean"}
 
 	for _, cmd := range commandNames {
 		t.Run(cmd, func(t *testing.T) {
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "api-diff",
+		Description: "Compare an exported API surface with another or the workspace",
+		Run:         RunAPIDiff,
+	})
+}
+
+// APIDiffOptions contains the configuration for the api-diff command.
+type APIDiffOptions struct {
+	Root string
+	Old  string // API surface written by api-export
+	New  string // API surface to compare with; empty for the workspace
+}
+
+// RunAPIDiff executes the api-diff command with parsed arguments.
+func RunAPIDiff(args []string) error {
+	fs := flag.NewFlagSet("api-diff", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	failOnBreaking := fs.Bool("fail-on-breaking", false, "exit with code 3 if any change is breaking")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		return UsageError(errors.New("usage: palace api-diff [options] <old.json> [new.json]"))
+	}
+
+	result, err := ExecuteAPIDiff(APIDiffOptions{Root: *root, Old: fs.Arg(0), New: fs.Arg(1)})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printDiffResult(result)
+	}
+	if *failOnBreaking {
+		if n := result.Breaking(); n > 0 {
+			return findingsError("%d breaking API changes since %s", n, result.Ref)
+		}
+	}
+	return nil
+}
+
+// ExecuteAPIDiff compares the API surface in opts.Old with the one in
+// opts.New, or with the current API of the workspace when New is empty.
+func ExecuteAPIDiff(opts APIDiffOptions) (*DiffResult, error) {
+	old, err := readAPISurfaceFile(opts.Old)
+	if err != nil {
+		return nil, err
+	}
+	var cur *analysis.APISurface
+	if opts.New != "" {
+		cur, err = readAPISurfaceFile(opts.New)
+	} else {
+		cur, err = ExecuteAPIExport(APIExportOptions{Root: opts.Root})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changes := analysis.DiffAPISurfaces(old, cur)
+	files := make(map[string]bool)
+	for _, s := range []*analysis.APISurface{old, cur} {
+		for _, pkg := range s.Packages {
+			for _, sym := range pkg.Symbols {
+				files[sym.File] = true
+			}
+		}
+	}
+	return &DiffResult{Ref: opts.Old, Files: len(files), Changes: changes}, nil
+}
+
+func readAPISurfaceFile(path string) (*analysis.APISurface, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open API surface: %w", err)
+	}
+	defer f.Close()
+	surface, err := analysis.ReadAPISurface(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return surface, nil
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+func TestExecuteAPIDiff(t *testing.T) {
+	root := t.TempDir()
+	write := func(src string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, "api.go"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("package api\n\nfunc Greet(name string) {}\n\nfunc Old() {}\n\nfunc helper() {}\n")
+
+	surface, err := ExecuteAPIExport(APIExportOptions{Root: root})
+	if err != nil {
+		t.Fatalf("ExecuteAPIExport() error: %v", err)
+	}
+	old := filepath.Join(t.TempDir(), "api.json")
+	f, err := os.Create(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := analysis.WriteAPISurface(f, surface); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	write("package api\n\nfunc Greet(name string, loud bool) {}\n\nfunc New() {}\n\nfunc helper(n int) {}\n")
+	result, err := ExecuteAPIDiff(APIDiffOptions{Root: root, Old: old})
+	if err != nil {
+		t.Fatalf("ExecuteAPIDiff() error: %v", err)
+	}
+	got := make(map[string]analysis.SymbolChangeKind)
+	for _, c := range result.Changes {
+		got[c.ID] = c.Change
+	}
+	want := map[string]analysis.SymbolChangeKind{
+		"api.go#Greet": analysis.SymbolSignatureChanged,
+		"api.go#Old":   analysis.SymbolRemoved,
+		"api.go#New":   analysis.SymbolAdded,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %v, want %v (unexported helper must not show up)", got, want)
+	}
+	if result.Breaking() != 2 {
+		t.Errorf("Breaking() = %d, want 2 for the removal and the added parameter", result.Breaking())
+	}
+
+	// Comparing a surface with itself reports nothing.
+	result, err = ExecuteAPIDiff(APIDiffOptions{Root: root, Old: old, New: old})
+	if err != nil {
+		t.Fatalf("ExecuteAPIDiff() error: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("expected no changes, got %+v", result.Changes)
+	}
+}
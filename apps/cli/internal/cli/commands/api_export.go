@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "api-export",
+		Description: "Export the exported API surface of the workspace as JSON",
+		Run:         RunAPIExport,
+	})
+}
+
+// APIExportOptions contains the configuration for the api-export command.
+type APIExportOptions struct {
+	Root string
+}
+
+// RunAPIExport executes the api-export command with parsed arguments.
+func RunAPIExport(args []string) error {
+	fs := flag.NewFlagSet("api-export", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	out := fs.String("out", "", "write the API surface to this file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	surface, err := ExecuteAPIExport(APIExportOptions{Root: *root})
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		return analysis.WriteAPISurface(os.Stdout, surface)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("create output: %w", err)
+	}
+	if err := analysis.WriteAPISurface(f, surface); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("exported %d symbols in %d packages to %s\n", surface.Symbols(), len(surface.Packages), *out)
+	return nil
+}
+
+// ExecuteAPIExport parses the source files of the workspace, as scan would
+// find them, and collects their exported API. The files are read directly
+// rather than from the index, which keeps no type parameters.
+func ExecuteAPIExport(opts APIExportOptions) (*analysis.APISurface, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if err := scan.ApplyParserLimits(rootPath); err != nil {
+		return nil, err
+	}
+	files, err := fsutil.ListFiles(rootPath, config.LoadGuardrails(rootPath))
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+
+	surface := analysis.NewAPISurface()
+	for _, rel := range files {
+		if !analysis.IsAnalyzable(rel) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(rootPath, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		fa, err := analysis.Analyze(content, rel)
+		if err != nil {
+			continue // Skipped, as scan skips files it cannot parse
+		}
+		surface.Add(fa)
+	}
+	surface.Sort()
+	return surface, nil
+}
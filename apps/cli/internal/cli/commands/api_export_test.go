@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+func TestExecuteAPIExport(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"auth/token.go":      "package auth\n\n// Sign signs the claims with key.\nfunc Sign[T any](claims T, key []byte) (string, error) {\n\treturn \"\", nil\n}\n\nfunc verify(token string) bool {\n\treturn false\n}\n",
+		"auth/token_test.go": "package auth\n\nimport \"testing\"\n\nfunc TestSign(t *testing.T) {}\n",
+		"config.json":        "{\"Name\": \"demo\"}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	surface, err := ExecuteAPIExport(APIExportOptions{Root: root})
+	if err != nil {
+		t.Fatalf("ExecuteAPIExport() error: %v", err)
+	}
+	if len(surface.Packages) != 1 || surface.Packages[0].Path != "auth" || surface.Packages[0].Language != "go" {
+		t.Fatalf("expected the auth Go package only, got %+v", surface.Packages)
+	}
+	symbols := surface.Packages[0].Symbols
+	if len(symbols) != 1 {
+		t.Fatalf("expected only Sign, without verify or TestSign, got %+v", symbols)
+	}
+	sign := symbols[0]
+	if sign.ID != "auth/token.go#Sign" || sign.Kind != analysis.KindFunction || sign.Doc != "Sign signs the claims with key." {
+		t.Errorf("unexpected symbol %+v", sign)
+	}
+	if sign.Signature == "" || len(sign.Params) != 2 || sign.Params[1].Name != "key" || sign.Params[1].Type != "[]byte" {
+		t.Errorf("expected Sign's signature and parameters, got %+v", sign)
+	}
+	if len(sign.Returns) != 2 || sign.Returns[1] != "error" {
+		t.Errorf("expected returns string, error, got %v", sign.Returns)
+	}
+	if len(sign.TypeParams) != 1 || sign.TypeParams[0].Name != "T" {
+		t.Errorf("expected type parameter T, got %v", sign.TypeParams)
+	}
+
+	var buf bytes.Buffer
+	if err := analysis.WriteAPISurface(&buf, surface); err != nil {
+		t.Fatalf("WriteAPISurface() error: %v", err)
+	}
+	read, err := analysis.ReadAPISurface(&buf)
+	if err != nil {
+		t.Fatalf("ReadAPISurface() error: %v", err)
+	}
+	if read.Symbols() != 1 || read.Packages[0].Symbols[0].ID != sign.ID {
+		t.Errorf("round trip lost symbols: %+v", read)
+	}
+}
+
+func TestExecuteAPIExportAppliesScanSettings(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".palace"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".palace", "palace.jsonc"), []byte(`{"scan": {"strictness": {"go": "bogus"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExecuteAPIExport(APIExportOptions{Root: root}); err == nil {
+		t.Error("expected the invalid scan.strictness to be reported")
+	}
+}
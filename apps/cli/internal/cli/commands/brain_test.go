@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
@@ -164,6 +167,53 @@ func TestRunRecallPendingNoDecisions(t *testing.T) {
 	}
 }
 
+func TestRunRecallOut(t *testing.T) {
+	root := t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	id, err := mem.AddLearning(memory.Learning{
+		Content:    "Retry webhooks with backoff",
+		Scope:      "palace",
+		Confidence: 0.8,
+		Source:     "cli",
+		Authority:  string(memory.AuthorityApproved),
+	})
+	mem.Close()
+	if err != nil {
+		t.Fatalf("AddLearning() error: %v", err)
+	}
+
+	out := filepath.Join(root, "recall.md")
+	if err := RunRecall([]string{"--root", root, "--out", out, "webhooks"}); err != nil {
+		t.Fatalf("RunRecall(--out) error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read --out file: %v", err)
+	}
+	want := "# Learnings\n\n## `" + id + "` (80% confidence)\n" +
+		"- **Scope:** palace\n" +
+		"- **Source:** cli | Used: 0 times\n" +
+		"- **Content:** Retry webhooks with backoff\n\n"
+	if string(data) != want {
+		t.Errorf("--out wrote:\n%s\nwant:\n%s", data, want)
+	}
+
+	if err := RunRecall([]string{"--root", root, "--out", out, "--template", "list", "webhooks"}); err != nil {
+		t.Fatalf("RunRecall(--template list) error: %v", err)
+	}
+	data, _ = os.ReadFile(out)
+	if want := "- `" + id + "` Retry webhooks with backoff\n"; string(data) != want {
+		t.Errorf("--template list wrote %q, want %q", data, want)
+	}
+
+	if err := RunRecall([]string{"--root", root, "--out", out, "--type", "idea"}); err == nil || !strings.Contains(err.Error(), "export learnings") {
+		t.Errorf("expected --type idea to be rejected with --out, got %v", err)
+	}
+}
+
 func TestInferTargetKind(t *testing.T) {
 	tests := []struct {
 		id   string
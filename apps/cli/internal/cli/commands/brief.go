@@ -11,6 +11,7 @@ import (
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/util"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
 )
 
@@ -43,7 +44,7 @@ func RunBrief(args []string) error {
 	root := flags.AddRootFlag(fs)
 	sessions := fs.Bool("sessions", false, "show detailed session information")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	remaining := fs.Args()
@@ -80,7 +81,7 @@ func ExecuteBrief(opts BriefOptions) error {
 	fmt.Println(strings.Repeat("═", 60))
 
 	// Show index statistics from scan.json
-	scanPath := filepath.Join(rootPath, ".palace", "index", "scan.json")
+	scanPath := filepath.Join(config.ResolveStore(rootPath).IndexDir, "scan.json")
 	if scanData, err := os.ReadFile(scanPath); err == nil {
 		var scanInfo ScanInfo
 		if json.Unmarshal(scanData, &scanInfo) == nil && scanInfo.FileCount > 0 {
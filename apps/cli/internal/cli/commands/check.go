@@ -11,6 +11,7 @@ import (
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/util"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/collect"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/lint"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/signal"
@@ -46,7 +47,7 @@ func RunCheck(args []string) error {
 	signalFlag := fs.Bool("signal", false, "also generate change signal from diff")
 	allowStale := fs.Bool("allow-stale", false, "for --collect: allow even if index is stale")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	return ExecuteCheck(CheckOptions{
@@ -68,10 +69,10 @@ func ExecuteCheck(opts CheckOptions) error {
 
 	// Run lint first
 	if err := lint.Run(rootPath); err != nil {
-		return fmt.Errorf("config validation failed: %w", err)
+		return findingsError("config validation failed: %w", err)
 	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); err != nil {
 		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
 	}
@@ -113,7 +114,7 @@ func ExecuteCheck(opts CheckOptions) error {
 		if len(staleList) > len(preview) {
 			fmt.Printf("... and %d more\n", len(staleList)-len(preview))
 		}
-		return errors.New("index is stale; run 'palace scan'")
+		return verifyError("index is stale; run 'palace scan'")
 	}
 
 	fmt.Printf("check ok; latest scan %s at %s\n", summary.ScanHash, summary.CompletedAt.Format(time.RFC3339))
@@ -134,7 +135,7 @@ func ExecuteCheck(opts CheckOptions) error {
 	// Generate change signal if requested
 	if opts.Signal {
 		if opts.DiffRange == "" {
-			return errors.New("--signal requires --diff range")
+			return UsageError(errors.New("--signal requires --diff range"))
 		}
 		if _, err := signal.Generate(opts.Root, opts.DiffRange); err != nil {
 			return fmt.Errorf("signal generation failed: %w", err)
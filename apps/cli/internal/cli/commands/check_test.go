@@ -3,6 +3,7 @@ package commands
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -75,3 +76,90 @@ func TestExecuteCheckStrictMode(t *testing.T) {
 		t.Fatalf("ExecuteCheck(Strict) error: %v", err)
 	}
 }
+
+func TestExecuteCheckConfigBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   string
+		wantErr bool
+	}{
+		{
+			name:  "recall",
+			block: `"recall": {"defaultTokenBudget": 1500, "maxResults": 20, "stemming": true, "synonyms": {"db": ["database"]}, "recencyHalfLife": "30d", "decisionBoost": 1.2, "pinnedBoost": 3}`,
+		},
+		{
+			name:  "scan",
+			block: `"scan": {"processors": ["language-stats"], "maxNestingDepth": 32, "snapshotFormat": "binary", "callExclusions": {"*": ["trace.*"]}, "strictness": {"dart": "strict"}}`,
+		},
+		{
+			name:  "forget",
+			block: `"forget": {"onStartup": true, "minAgeDays": 30, "maxAccessCount": 1, "maxConfidence": 0.4, "excludeKinds": ["idea"]}`,
+		},
+		{
+			name:  "relink",
+			block: `"relink": {"minSharedTags": 3, "minTagOverlap": 0.4, "minSimilarity": 0.7}`,
+		},
+		{
+			name:  "related workspaces",
+			block: `"relatedWorkspaces": {"billing": "../billing", "shared": "/srv/shared"}`,
+		},
+		{
+			name:  "memory lint",
+			block: `"memoryLint": {"onStore": "warn", "rules": [{"name": "decision-rationale", "kind": "decision", "requireRationale": true, "minLength": 20, "maxLength": 2000, "pattern": "^[A-Z]", "requiredTags": ["area"], "message": "Explain the decision"}]}`,
+		},
+		{
+			name:  "hotspots",
+			block: `"hotspots": {"complexityWeight": 2, "sizeWeight": 1, "churnWeight": 0.5, "churnSince": "6 months ago"}`,
+		},
+		{
+			name:  "naming lint",
+			block: `"namingLint": {"noDefaults": true, "rules": [{"language": "python", "kinds": ["function"], "style": "snake_case", "visibility": "exported"}, {"language": "go", "kinds": ["constant"], "pattern": "^[A-Z]"}]}`,
+		},
+		{
+			name:    "recall with a mistyped field",
+			block:   `"recall": {"stemming": "yes"}`,
+			wantErr: true,
+		},
+		{
+			name:    "recall with a mistyped half-life",
+			block:   `"recall": {"recencyHalfLife": "30days"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := ExecuteInit(InitOptions{Root: root, NoScan: true}); err != nil {
+				t.Fatalf("ExecuteInit() error: %v", err)
+			}
+
+			// Add the block to the generated config, after defaultRoom
+			path := filepath.Join(root, ".palace", "palace.jsonc")
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			const anchor = `"defaultRoom": "project-overview",`
+			if !strings.Contains(string(data), anchor) {
+				t.Fatalf("no %s in the generated config:\n%s", anchor, data)
+			}
+			config := strings.Replace(string(data), anchor, anchor+"\n  "+tt.block+",", 1)
+			if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\nfunc main() {}\n"), 0o644)
+			if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+				t.Fatalf("ExecuteScan() error: %v", err)
+			}
+
+			err = ExecuteCheck(CheckOptions{Root: root})
+			if tt.wantErr && err == nil {
+				t.Error("expected the config to fail validation")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ExecuteCheck() error: %v", err)
+			}
+		})
+	}
+}
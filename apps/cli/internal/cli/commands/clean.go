@@ -34,7 +34,7 @@ func RunClean(args []string) error {
 	root := flags.AddRootFlag(fs)
 	dryRun := fs.Bool("dry-run", false, "show what would be done without making changes")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	return ExecuteClean(CleanOptions{
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/butler"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "context",
+		Description: "Build a token-budgeted context bundle for a topic",
+		Run:         RunContext,
+	})
+}
+
+// ContextOptions contains the configuration for the context command.
+type ContextOptions struct {
+	Root   string
+	Topic  string
+	Budget int // Token budget for the whole bundle
+	Limit  int // Maximum candidates fetched from each source
+}
+
+// RunContext executes the context command.
+func RunContext(args []string) error {
+	fs := flag.NewFlagSet("context", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	budget := fs.Int("budget", butler.DefaultContextBudget, "maximum tokens in the bundle")
+	limit := flags.AddLimitFlag(fs, 20)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if err := flags.ValidateLimit(*limit); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New(`usage: palace context <topic> [--budget N] [--json]
+
+Examples:
+  palace context authentication
+  palace context "session storage" --budget 2000`)
+	}
+
+	bundle, err := ExecuteContext(ContextOptions{
+		Root:   *root,
+		Topic:  strings.Join(fs.Args(), " "),
+		Budget: *budget,
+		Limit:  *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(bundle)
+	}
+	fmt.Print(bundle.Markdown())
+	return nil
+}
+
+// ExecuteContext builds the context bundle for a topic from the workspace's
+// index and memory; see butler.BuildContextBundle, which the explore_context
+// MCP tool also uses when given a token budget.
+func ExecuteContext(opts ContextOptions) (*butler.ContextBundle, error) {
+	if strings.TrimSpace(opts.Topic) == "" {
+		return nil, errors.New("topic is required")
+	}
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openQueryIndex(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	b, err := butler.New(db, rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("initialize butler: %w", err)
+	}
+	defer b.Close()
+
+	return b.BuildContextBundle(butler.ContextBundleOptions{
+		Topic:  opts.Topic,
+		Budget: opts.Budget,
+		Limit:  opts.Limit,
+	})
+}
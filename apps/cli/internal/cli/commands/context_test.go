@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func TestExecuteContext(t *testing.T) {
+	root := t.TempDir()
+	src := "package store\n\n// SessionStore keeps user sessions.\ntype SessionStore struct{}\n\nfunc helper() {}\n"
+	if err := os.WriteFile(filepath.Join(root, "store.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := scan.Run(root); err != nil {
+		t.Fatalf("scan.Run() error: %v", err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	decisionID, err := mem.AddDecision(memory.Decision{
+		Content:   "Keep SessionStore in Redis so sessions survive restarts",
+		Scope:     "palace",
+		Source:    "cli",
+		Authority: string(memory.AuthorityApproved),
+	})
+	mem.Close()
+	if err != nil {
+		t.Fatalf("AddDecision() error: %v", err)
+	}
+
+	bundle, err := ExecuteContext(ContextOptions{Root: root, Topic: "SessionStore", Budget: 1000})
+	if err != nil {
+		t.Fatalf("ExecuteContext() error: %v", err)
+	}
+	if len(bundle.Code) == 0 || bundle.Code[0].Kind != "symbol" || !strings.Contains(bundle.Code[0].Text, "SessionStore") {
+		t.Errorf("expected SessionStore in the code section, got %+v", bundle.Code)
+	}
+	if len(bundle.Memory) != 1 || bundle.Memory[0].Ref != decisionID {
+		t.Errorf("expected decision %s in the memory section, got %+v", decisionID, bundle.Memory)
+	}
+	if bundle.Tokens > bundle.Budget {
+		t.Errorf("bundle uses %d tokens, over its budget of %d", bundle.Tokens, bundle.Budget)
+	}
+	md := bundle.Markdown()
+	codeAt, memoryAt := strings.Index(md, "## Code context"), strings.Index(md, "## Memory context")
+	if codeAt < 0 || memoryAt < codeAt {
+		t.Errorf("expected code then memory sections, got:\n%s", md)
+	}
+
+	small, err := ExecuteContext(ContextOptions{Root: root, Topic: "SessionStore", Budget: 10})
+	if err != nil {
+		t.Fatalf("ExecuteContext() error: %v", err)
+	}
+	if small.Tokens > 10 || !small.Truncated {
+		t.Errorf("expected a truncated bundle within 10 tokens, got %d tokens (truncated=%v)", small.Tokens, small.Truncated)
+	}
+}
+
+func TestExecuteContextKeepsPinnedRecords(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := scan.Run(root); err != nil {
+		t.Fatalf("scan.Run() error: %v", err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	pinnedID, err := mem.AddLearning(memory.Learning{Content: "Tenant IDs prefix every cache key", Scope: "palace", Source: "cli", Confidence: 0.9, Authority: string(memory.AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddLearning() error: %v", err)
+	}
+	if err := mem.AddTag(pinnedID, "learning", memory.PinnedTag); err != nil {
+		t.Fatalf("AddTag() error: %v", err)
+	}
+	var ideaIDs []string
+	for _, topic := range []string{"eviction metrics", "warmup on deploy", "a second tier"} {
+		id, err := mem.AddIdea(memory.Idea{
+			Content: "Explore " + topic + " for the cache. " + strings.Repeat("This needs a benchmark against production traffic before anyone commits to it. ", 4),
+			Scope:   "palace",
+			Source:  "cli",
+		})
+		if err != nil {
+			t.Fatalf("AddIdea() error: %v", err)
+		}
+		ideaIDs = append(ideaIDs, id)
+	}
+	mem.Close()
+
+	bundle, err := ExecuteContext(ContextOptions{Root: root, Topic: "cache", Budget: 60})
+	if err != nil {
+		t.Fatalf("ExecuteContext() error: %v", err)
+	}
+	if bundle.Tokens > bundle.Budget {
+		t.Errorf("bundle uses %d tokens, over its budget of %d", bundle.Tokens, bundle.Budget)
+	}
+	if len(bundle.Memory) == 0 || bundle.Memory[0].Ref != pinnedID || !bundle.Memory[0].Pinned || bundle.Memory[0].Summarized {
+		t.Errorf("expected pinned learning %s first and in full, got %+v", pinnedID, bundle.Memory)
+	}
+	if !bundle.Truncated || len(bundle.Omitted) == 0 {
+		t.Fatalf("expected omitted ideas, got %+v", bundle)
+	}
+	for _, o := range bundle.Omitted {
+		if o.Kind != "idea" || !slices.Contains(ideaIDs, o.Ref) {
+			t.Errorf("expected only ideas to be omitted, got %+v", o)
+		}
+	}
+	if md := bundle.Markdown(); !strings.Contains(md, "_Omitted to fit a budget of 60 tokens: idea `"+bundle.Omitted[0].Ref+"`") {
+		t.Errorf("expected the omissions in the markdown, got:\n%s", md)
+	}
+}
@@ -24,7 +24,7 @@ func init() {
 // RunCorridor is the main entry point for the corridor command
 func RunCorridor(args []string) error {
 	if len(args) == 0 {
-		return errors.New("usage: palace corridor <subcommand> [options]\n\n" +
+		return UsageError(errors.New("usage: palace corridor <subcommand> [options]\n\n" +
 			"Subcommands:\n" +
 			"  list      List linked workspaces\n" +
 			"  link      Link another workspace\n" +
@@ -32,7 +32,7 @@ func RunCorridor(args []string) error {
 			"  personal  Show personal corridor learnings\n" +
 			"  promote   Promote a learning to personal corridor\n" +
 			"  search    Search across all corridors\n\n" +
-			"run 'palace corridor <subcommand> --help' for subcommand help")
+			"run 'palace corridor <subcommand> --help' for subcommand help"))
 	}
 
 	switch args[0] {
@@ -49,7 +49,7 @@ func RunCorridor(args []string) error {
 	case "search":
 		return ExecuteCorridorSearch(args[1:])
 	default:
-		return fmt.Errorf("unknown corridor command: %s\nRun 'palace help corridor' for usage", args[0])
+		return UsageError(fmt.Errorf("unknown corridor command: %s\nRun 'palace help corridor' for usage", args[0]))
 	}
 }
 
@@ -167,7 +167,7 @@ func ExecuteCorridorPersonal(args []string) error {
 	query := fs.String("query", "", "search query")
 	limit := flags.AddLimitFlag(fs, 10)
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	gc, err := corridor.OpenGlobal()
@@ -211,7 +211,7 @@ func ExecuteCorridorPromote(args []string) error {
 	fs := flag.NewFlagSet("promote", flag.ContinueOnError)
 	root := flags.AddRootFlag(fs)
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	remaining := fs.Args()
@@ -280,7 +280,7 @@ func ExecuteCorridorSearch(args []string) error {
 	all := fs.Bool("all", false, "search all linked workspaces too")
 	limit := flags.AddLimitFlag(fs, 10)
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Validate inputs
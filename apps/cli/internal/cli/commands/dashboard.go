@@ -37,7 +37,7 @@ func RunDashboard(args []string) error {
 	port := flags.AddPortFlag(fs, 3001)
 	noBrowser := fs.Bool("no-browser", false, "don't open browser automatically")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Validate inputs
@@ -79,7 +79,7 @@ func ExecuteDashboard(opts DashboardOptions) error {
 	}
 
 	// Open butler for code search
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	var b *butler.Butler
 	if _, err := os.Stat(dbPath); err == nil {
 		db, err := index.Open(dbPath)
@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/gitutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "diff",
+		Description: "Show symbol-level changes between a git ref and the working tree",
+		Run:         RunDiff,
+	})
+}
+
+// DiffOptions contains the configuration for the diff command.
+type DiffOptions struct {
+	Root     string
+	Ref      string
+	Snapshot string // Index snapshot to compare the current index against, instead of a ref
+}
+
+// DiffResult holds the symbol-level changes between a ref and the working tree.
+type DiffResult struct {
+	Ref     string                  `json:"ref"`
+	Files   int                     `json:"files"`
+	Changes []analysis.SymbolChange `json:"changes"`
+}
+
+// RunDiff executes the diff command with parsed arguments.
+func RunDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	ref := fs.String("git", "", "git ref to compare the working tree against")
+	snapshot := fs.String("snapshot", "", "index snapshot (palace export --what index) to compare the current index against")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	failOnBreaking := fs.Bool("fail-on-breaking", false, "exit with code 3 if any change is breaking")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if (*ref == "") == (*snapshot == "") {
+		return UsageError(errors.New("usage: palace diff --git <ref> | --snapshot <file>"))
+	}
+
+	result, err := ExecuteDiff(DiffOptions{Root: *root, Ref: *ref, Snapshot: *snapshot})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printDiffResult(result)
+	}
+	if *failOnBreaking {
+		if n := result.Breaking(); n > 0 {
+			return findingsError("%d breaking changes since %s", n, result.Ref)
+		}
+	}
+	return nil
+}
+
+// Breaking counts the changes that may break existing callers: removed
+// symbols, and signature changes other than ones whose parameter detail
+// shows only compatible edits, such as an added optional parameter.
+func (r *DiffResult) Breaking() int {
+	n := 0
+	for _, c := range r.Changes {
+		if isBreakingChange(c) {
+			n++
+		}
+	}
+	return n
+}
+
+func isBreakingChange(c analysis.SymbolChange) bool {
+	switch c.Change {
+	case analysis.SymbolRemoved:
+		return true
+	case analysis.SymbolSignatureChanged:
+		if len(c.Params) == 0 && len(c.TypeParams) == 0 {
+			return true
+		}
+		for _, p := range c.Params {
+			if p.Breaking {
+				return true
+			}
+		}
+		for _, p := range c.TypeParams {
+			if p.Breaking {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExecuteDiff compares every changed, analyzable file between the ref and the
+// working tree and returns per-symbol changes. With a snapshot it compares
+// the snapshot with the current index instead.
+func ExecuteDiff(opts DiffOptions) (*DiffResult, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Snapshot != "" {
+		return diffSnapshot(rootPath, opts.Snapshot)
+	}
+	if !gitutil.IsGitRepo(rootPath) {
+		return nil, fmt.Errorf("%s is not a git repository", rootPath)
+	}
+	repoRoot, err := gitutil.GetRepoRoot(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve repo root: %w", err)
+	}
+	if !gitutil.IsValidCommit(repoRoot, opts.Ref) {
+		return nil, fmt.Errorf("unknown git ref: %s", opts.Ref)
+	}
+
+	added, modified, deleted, err := gitutil.GetChangedFilesSinceCommit(repoRoot, opts.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("list changed files: %w", err)
+	}
+
+	guardrails := config.LoadGuardrails(rootPath)
+	result := &DiffResult{Ref: opts.Ref}
+
+	type pending struct {
+		path     string
+		old, new bool
+	}
+	var files []pending
+	for _, p := range added {
+		files = append(files, pending{path: p, new: true})
+	}
+	for _, p := range modified {
+		files = append(files, pending{path: p, old: true, new: true})
+	}
+	for _, p := range deleted {
+		files = append(files, pending{path: p, old: true})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	for _, f := range files {
+		if !analysis.IsAnalyzable(f.path) || fsutil.MatchesGuardrail(f.path, guardrails) {
+			continue
+		}
+
+		var oldFA, newFA *analysis.FileAnalysis
+		var oldContent, newContent []byte
+		if f.old {
+			// A file reported as modified may be new relative to the ref
+			if content, err := gitutil.ShowFileAtRef(repoRoot, opts.Ref, f.path); err == nil {
+				oldContent = content
+				oldFA, _ = analysis.Analyze(content, f.path)
+			}
+		}
+		if f.new {
+			if content, err := os.ReadFile(filepath.Join(repoRoot, f.path)); err == nil {
+				newContent = content
+				newFA, _ = analysis.Analyze(content, f.path)
+			}
+		}
+		if oldFA == nil && newFA == nil {
+			continue
+		}
+
+		result.Files++
+		result.Changes = append(result.Changes, analysis.DiffSymbols(f.path, oldFA, oldContent, newFA, newContent)...)
+	}
+
+	return result, nil
+}
+
+// diffSnapshot compares a saved index snapshot with the current index.
+func diffSnapshot(rootPath, path string) (*DiffResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot: %w", err)
+	}
+	defer f.Close()
+	old, err := index.ReadSnapshot(f)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openQueryIndex(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	cur, err := index.LoadSnapshot(db)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, files := index.CompareSnapshots(old, cur)
+	return &DiffResult{Ref: path, Files: files, Changes: changes}, nil
+}
+
+func printDiffResult(result *DiffResult) {
+	fmt.Printf("\nSymbol changes since %s (%d files analyzed)\n\n", result.Ref, result.Files)
+	if len(result.Changes) == 0 {
+		fmt.Println("  No symbol-level changes.")
+		return
+	}
+
+	markers := map[analysis.SymbolChangeKind]string{
+		analysis.SymbolAdded:            "+",
+		analysis.SymbolRemoved:          "-",
+		analysis.SymbolSignatureChanged: "~",
+		analysis.SymbolBodyChanged:      "*",
+	}
+	currentFile := ""
+	for _, c := range result.Changes {
+		if c.File != currentFile {
+			currentFile = c.File
+			fmt.Printf("%s\n", currentFile)
+		}
+		fmt.Printf("  %s %-18s %s (%s)\n", markers[c.Change], c.Change, c.Name, c.SymbolKind)
+		if c.Change == analysis.SymbolSignatureChanged {
+			fmt.Printf("      - %s\n      + %s\n", c.OldSignature, c.NewSignature)
+			for _, p := range c.Params {
+				label := ""
+				if p.Breaking {
+					label = " (breaking)"
+				}
+				fmt.Printf("        %s%s\n", p, label)
+			}
+			for _, p := range c.TypeParams {
+				label := ""
+				if p.Breaking {
+					label = " (breaking)"
+				}
+				fmt.Printf("        type parameter %s%s\n", p, label)
+			}
+		}
+	}
+	fmt.Println()
+}
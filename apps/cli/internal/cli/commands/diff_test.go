@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func TestRunDiffRequiresRef(t *testing.T) {
+	if err := RunDiff([]string{}); err == nil {
+		t.Error("expected error when --git is missing")
+	}
+}
+
+func TestExecuteDiffNotGitRepo(t *testing.T) {
+	if _, err := ExecuteDiff(DiffOptions{Root: t.TempDir(), Ref: "HEAD"}); err == nil {
+		t.Error("expected error for non-git directory")
+	}
+}
+
+func TestExecuteDiffSnapshot(t *testing.T) {
+	root := t.TempDir()
+	write := func(src string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := scan.Run(root); err != nil {
+			t.Fatalf("scan.Run() error: %v", err)
+		}
+	}
+	write("package main\n\nfunc Greet(name string) {}\n\nfunc Old() {}\n")
+
+	snapshot := filepath.Join(t.TempDir(), "index.snapshot")
+	f, err := os.Create(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ExecuteExport(ExportOptions{Root: root, Format: "binary", What: "index"}, f); err != nil {
+		t.Fatalf("ExecuteExport(index) error: %v", err)
+	}
+	f.Close()
+
+	write("package main\n\nfunc Greet(name string, loud bool) {}\n\nfunc New() {}\n")
+	result, err := ExecuteDiff(DiffOptions{Root: root, Snapshot: snapshot})
+	if err != nil {
+		t.Fatalf("ExecuteDiff() error: %v", err)
+	}
+	got := make(map[string]analysis.SymbolChangeKind)
+	for _, c := range result.Changes {
+		got[c.ID] = c.Change
+	}
+	want := map[string]analysis.SymbolChangeKind{
+		"main.go#Greet": analysis.SymbolSignatureChanged,
+		"main.go#Old":   analysis.SymbolRemoved,
+		"main.go#New":   analysis.SymbolAdded,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+}
+
+func TestExecuteDiffSymbolChanges(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.CommandContext(context.Background(), "git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@test.com")
+	git("config", "user.name", "Test")
+
+	original := `package main
+
+func Greet(name string) string {
+	return "hello " + name
+}
+
+func Unchanged() int {
+	return 1
+}
+`
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-m", "initial")
+
+	updated := `package main
+
+func Greet(name string) string {
+	return "hi " + name
+}
+
+func Unchanged() int {
+	return 1
+}
+
+func Farewell(name string) string {
+	return "bye " + name
+}
+`
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ExecuteDiff(DiffOptions{Root: dir, Ref: "HEAD"})
+	if err != nil {
+		t.Fatalf("ExecuteDiff() error: %v", err)
+	}
+
+	got := make(map[string]analysis.SymbolChangeKind)
+	for _, c := range result.Changes {
+		got[c.Name] = c.Change
+	}
+	if got["Greet"] != analysis.SymbolBodyChanged {
+		t.Errorf("Greet change = %q, want %q", got["Greet"], analysis.SymbolBodyChanged)
+	}
+	if got["Farewell"] != analysis.SymbolAdded {
+		t.Errorf("Farewell change = %q, want %q", got["Farewell"], analysis.SymbolAdded)
+	}
+	if _, ok := got["Unchanged"]; ok {
+		t.Errorf("Unchanged should not be reported, got %q", got["Unchanged"])
+	}
+	for _, c := range result.Changes {
+		if c.ID != analysis.SymbolID("main.go", c.Name) {
+			t.Errorf("unexpected stable ID %q", c.ID)
+		}
+	}
+}
+
+func TestRemovedFindingIsNotBreaking(t *testing.T) {
+	oldSrc := []byte(`package auth
+
+func Login() {}
+
+// func legacyLogin(user string) error {
+//     token := issueToken(user)
+//     store.Save(token);
+//     return nil
+// }
+`)
+	newSrc := []byte("package auth\n\nfunc Login() {}\n")
+	oldFA, err := analysis.Analyze(oldSrc, "auth.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFA, err := analysis.Analyze(newSrc, "auth.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range analysis.DiffSymbols("auth.go", oldFA, oldSrc, newFA, newSrc) {
+		if isBreakingChange(c) {
+			t.Errorf("removing the commented-out block should not be breaking, got %+v", c)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// Exit codes every command follows, so CI pipelines can gate on the
+// condition that failed rather than on failure alone.
+const (
+	ExitOK           = 0 // Success
+	ExitFailure      = 1 // Any other error
+	ExitUsage        = 2 // Unknown command, bad flag, or missing argument
+	ExitFindings     = 3 // The command ran and found problems: rule violations, warnings, breaking changes
+	ExitVerifyFailed = 4 // The index does not match the workspace
+)
+
+// ExitError is an error that ends the process with a specific exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// UsageError marks err as a usage error, so that it exits with ExitUsage.
+// Commands wrap the errors of parsing their flags with it. A nil err and
+// flag.ErrHelp, a request for help rather than a mistake, are returned as
+// they are.
+func UsageError(err error) error {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return &ExitError{Code: ExitUsage, Err: err}
+}
+
+// findingsError reports that a command found problems.
+func findingsError(format string, args ...any) error {
+	return &ExitError{Code: ExitFindings, Err: fmt.Errorf(format, args...)}
+}
+
+// verifyError reports that the index failed verification.
+func verifyError(format string, args ...any) error {
+	return &ExitError{Code: ExitVerifyFailed, Err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode returns the exit code for the error a command returned: ExitOK
+// for nil or a help request, the code of an ExitError, and ExitFailure
+// otherwise.
+func ExitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitFailure
+}
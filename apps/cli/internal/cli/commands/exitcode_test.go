@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"help", flag.ErrHelp, ExitOK},
+		{"plain error", errors.New("boom"), ExitFailure},
+		{"usage", UsageError(errors.New("bad flag")), ExitUsage},
+		{"wrapped findings", errors.Join(errors.New("context"), findingsError("%d problems", 2)), ExitFindings},
+		{"verification", verifyError("stale"), ExitVerifyFailed},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("%s: ExitCode = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCommandExitCodes(t *testing.T) {
+	t.Run("bad flag is a usage error", func(t *testing.T) {
+		if got := ExitCode(RunScan([]string{"--no-such-flag"})); got != ExitUsage {
+			t.Errorf("exit code = %d, want %d", got, ExitUsage)
+		}
+		if got := ExitCode(RunDiff(nil)); got != ExitUsage {
+			t.Errorf("diff without --git: exit code = %d, want %d", got, ExitUsage)
+		}
+	})
+
+	t.Run("invalid scan options are usage errors", func(t *testing.T) {
+		root := t.TempDir()
+		for name, opts := range map[string]ScanOptions{
+			"--tests-only with --no-tests": {Root: root, TestsOnly: true, NoTests: true},
+			"--repair-index with --strict": {Root: root, RepairIndex: true, Strict: true},
+			"negative --jobs":              {Root: root, Jobs: -1},
+			"negative --lsp-concurrency":   {Root: root, LSPConcurrency: -2},
+			"negative --max-depth":         {Root: root, LimitDepth: true, MaxDepth: -1},
+		} {
+			if got := ExitCode(ExecuteScan(opts)); got != ExitUsage {
+				t.Errorf("%s: exit code = %d, want %d", name, got, ExitUsage)
+			}
+		}
+	})
+
+	t.Run("lint-memory --strict with violations", func(t *testing.T) {
+		root := t.TempDir()
+		if err := ExecuteInit(InitOptions{Root: root, NoScan: true}); err != nil {
+			t.Fatalf("ExecuteInit() error: %v", err)
+		}
+		cfg := `{"memoryLint": {"rules": [{"kind": "idea", "minLength": 40}]}}`
+		if err := os.WriteFile(filepath.Join(root, ".palace", "palace.jsonc"), []byte(cfg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mem, err := memory.Open(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = mem.AddIdea(memory.Idea{Content: "Too short", Scope: "palace"})
+		mem.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := ExitCode(RunLintMemory([]string{"--root", root})); got != ExitOK {
+			t.Errorf("without --strict: exit code = %d, want %d", got, ExitOK)
+		}
+		if got := ExitCode(RunLintMemory([]string{"--root", root, "--strict"})); got != ExitFindings {
+			t.Errorf("exit code = %d, want %d", got, ExitFindings)
+		}
+	})
+
+	t.Run("check on a stale index", func(t *testing.T) {
+		root := t.TempDir()
+		if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+			t.Fatalf("ExecuteInit() error: %v", err)
+		}
+		goFile := filepath.Join(root, "main.go")
+		if err := os.WriteFile(goFile, []byte("package main\nfunc main() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+			t.Fatalf("ExecuteScan() error: %v", err)
+		}
+		if err := os.WriteFile(goFile, []byte("package main\nfunc main() { println() }\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := ExitCode(ExecuteCheck(CheckOptions{Root: root, Strict: true})); got != ExitVerifyFailed {
+			t.Errorf("exit code = %d, want %d", got, ExitVerifyFailed)
+		}
+	})
+
+	t.Run("scan --strict with analysis warnings", func(t *testing.T) {
+		root := t.TempDir()
+		if err := ExecuteInit(InitOptions{Root: root, NoScan: true}); err != nil {
+			t.Fatalf("ExecuteInit() error: %v", err)
+		}
+		src := "package main\n\ntype Config struct {\n\tName string\n}\n"
+		if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := ExitCode(ExecuteScan(ScanOptions{Root: root, Strict: true})); got != ExitOK {
+			t.Errorf("without warnings: exit code = %d, want %d", got, ExitOK)
+		}
+		// A nesting limit of one level flattens the struct's field, which
+		// the analysis reports as a warning.
+		cfg := `{"scan": {"maxNestingDepth": 1}}`
+		if err := os.WriteFile(filepath.Join(root, ".palace", "palace.jsonc"), []byte(cfg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		defer analysis.SetMaxNestingDepth(0)
+		if got := ExitCode(ExecuteScan(ScanOptions{Root: root, Strict: true})); got != ExitFindings {
+			t.Errorf("exit code = %d, want %d", got, ExitFindings)
+		}
+	})
+
+	t.Run("diff --fail-on-breaking", func(t *testing.T) {
+		dir := t.TempDir()
+		git := func(args ...string) {
+			t.Helper()
+			cmd := exec.CommandContext(context.Background(), "git", append([]string{"-C", dir}, args...)...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Skipf("git %v failed: %v\n%s", args, err, out)
+			}
+		}
+		git("init")
+		git("config", "user.email", "test@test.com")
+		git("config", "user.name", "Test")
+		path := filepath.Join(dir, "main.go")
+		if err := os.WriteFile(path, []byte("package main\n\nfunc Keep() {}\n\nfunc Drop() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".")
+		git("commit", "-m", "initial")
+
+		// Adding a function is compatible
+		if err := os.WriteFile(path, []byte("package main\n\nfunc Keep() {}\n\nfunc Drop() {}\n\nfunc Add() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := ExitCode(RunDiff([]string{"--root", dir, "--git", "HEAD", "--json", "--fail-on-breaking"})); got != ExitOK {
+			t.Errorf("compatible change: exit code = %d, want %d", got, ExitOK)
+		}
+
+		// Removing one is not
+		if err := os.WriteFile(path, []byte("package main\n\nfunc Keep() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := ExitCode(RunDiff([]string{"--root", dir, "--git", "HEAD", "--json"})); got != ExitOK {
+			t.Errorf("without --fail-on-breaking: exit code = %d, want %d", got, ExitOK)
+		}
+		if got := ExitCode(RunDiff([]string{"--root", dir, "--git", "HEAD", "--json", "--fail-on-breaking"})); got != ExitFindings {
+			t.Errorf("exit code = %d, want %d", got, ExitFindings)
+		}
+	})
+}
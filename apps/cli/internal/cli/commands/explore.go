@@ -60,7 +60,7 @@ func RunExplore(args []string) error {
 	direction := fs.String("direction", "up", "trace direction: up (callers), down (callees), or both")
 	listRooms := fs.Bool("rooms", false, "list all configured rooms")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Validate inputs
@@ -135,7 +135,7 @@ func runExploreSearch(opts ExploreOptions) error {
 		return err
 	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); err != nil {
 		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
 	}
@@ -228,7 +228,7 @@ func runExploreFull(root, goal string) error {
 		cp.Provenance.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if db, err := index.Open(dbPath); err == nil {
 		if summary, err := index.LatestScan(db); err == nil && summary.ID != 0 {
 			cp.ScanHash = summary.ScanHash
@@ -256,7 +256,7 @@ func runExploreMap(root, symbol, filePath string) error {
 		return err
 	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); err != nil {
 		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
 	}
@@ -281,7 +281,7 @@ func runExploreMap(root, symbol, filePath string) error {
 		return runExploreMapCallers(db, symbol)
 	}
 
-	return errors.New("usage: palace explore --map <symbol> or palace explore --map --file <file>")
+	return UsageError(errors.New("usage: palace explore --map <symbol> or palace explore --map --file <file>"))
 }
 
 // runExploreMapCallers shows who calls a symbol.
@@ -384,7 +384,7 @@ func runExploreCallChain(root, symbol, filePath string, depth int, direction str
 		return err
 	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); err != nil {
 		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
 	}
@@ -482,7 +482,7 @@ func runExploreListRooms(root string) error {
 		return err
 	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); err != nil {
 		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
 	}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "export",
+		Description: "Export index data for spreadsheets and other tools",
+		Run:         RunExport,
+	})
+}
+
+// Column headers for the CSV export. Keep them stable: spreadsheets and
+// scripts address columns by name.
+var (
+	symbolCSVHeader       = []string{"path", "name", "kind", "visibility", "start_line", "complexity", "line_count"}
+	relationshipCSVHeader = []string{"from", "to", "kind", "confidence", "file", "line"}
+)
+
+// ExportOptions contains the configuration for the export command.
+type ExportOptions struct {
+	Root   string
+	Format string
+	What   string
+}
+
+// RunExport executes the export command with parsed arguments.
+func RunExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	format := fs.String("format", "", "output format: csv for symbols and relationships; json or binary for index (default from palace.jsonc, else json)")
+	what := fs.String("what", "symbols", "data to export: symbols, relationships, or index")
+	out := fs.String("out", "", "write to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("create output: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return ExecuteExport(ExportOptions{Root: *root, Format: *format, What: *what}, w)
+}
+
+// ExecuteExport writes the requested index data to w.
+func ExecuteExport(opts ExportOptions, w io.Writer) error {
+	if opts.What == "index" {
+		return exportSnapshot(opts, w)
+	}
+	if opts.Format != "" && opts.Format != "csv" {
+		return fmt.Errorf("unsupported format %q (supported: csv)", opts.Format)
+	}
+	if opts.What != "symbols" && opts.What != "relationships" {
+		return fmt.Errorf("unknown export target %q (use symbols, relationships, or index)", opts.What)
+	}
+
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if opts.What == "relationships" {
+		rels, err := index.GetExportRelationships(db)
+		if err != nil {
+			return err
+		}
+		return WriteRelationshipsCSV(w, rels)
+	}
+	symbols, err := index.GetExportSymbols(db, opts.Root)
+	if err != nil {
+		return err
+	}
+	return WriteSymbolsCSV(w, symbols)
+}
+
+// exportSnapshot writes a snapshot of every symbol and relationship, in the
+// format asked for or else the one configured as scan.snapshotFormat.
+func exportSnapshot(opts ExportOptions, w io.Writer) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+	format := opts.Format
+	if format == "" {
+		format = index.SnapshotJSON
+		if cfg, err := config.LoadPalaceConfig(rootPath); err == nil && cfg.Scan != nil && cfg.Scan.SnapshotFormat != "" {
+			format = cfg.Scan.SnapshotFormat
+		}
+	}
+	if format != index.SnapshotJSON && format != index.SnapshotBinary {
+		return fmt.Errorf("unsupported format %q for index (supported: json, binary)", format)
+	}
+
+	db, err := openQueryIndex(rootPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	snap, err := index.LoadSnapshot(db)
+	if err != nil {
+		return err
+	}
+	return index.WriteSnapshot(w, snap, format)
+}
+
+// WriteSymbolsCSV writes symbols as CSV with a header row. Complexity is
+// left empty for symbols that have none.
+func WriteSymbolsCSV(w io.Writer, symbols []index.ExportSymbol) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(symbolCSVHeader); err != nil {
+		return err
+	}
+	for _, s := range symbols {
+		visibility := "private"
+		if s.Exported {
+			visibility = "public"
+		}
+		complexity := ""
+		if s.Complexity > 0 {
+			complexity = strconv.Itoa(s.Complexity)
+		}
+		if err := cw.Write([]string{
+			s.FilePath, s.Name, s.Kind, visibility,
+			strconv.Itoa(s.LineStart), complexity, strconv.Itoa(s.LineCount),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteRelationshipsCSV writes relationships as CSV with a header row.
+func WriteRelationshipsCSV(w io.Writer, rels []index.ExportRelationship) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(relationshipCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range rels {
+		confidence := strconv.FormatFloat(r.Confidence, 'g', 4, 64)
+		if err := cw.Write([]string{r.From, r.To, r.Kind, confidence, r.File, strconv.Itoa(r.Line)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
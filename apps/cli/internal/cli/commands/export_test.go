@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func TestWriteSymbolsCSVRoundTrip(t *testing.T) {
+	symbols := []index.ExportSymbol{
+		{FilePath: "pkg/a.go", Name: `Parse, "strict"`, Kind: "function", Exported: true, LineStart: 10, LineCount: 5, Complexity: 3},
+		{FilePath: "pkg/b.go", Name: "helper", Kind: "function", LineStart: 3, LineCount: 1},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSymbolsCSV(&buf, symbols); err != nil {
+		t.Fatalf("WriteSymbolsCSV() error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read back CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d: %v", len(records), records)
+	}
+	if !reflect.DeepEqual(records[0], []string{"path", "name", "kind", "visibility", "start_line", "complexity", "line_count"}) {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	want := []string{"pkg/a.go", `Parse, "strict"`, "function", "public", "10", "3", "5"}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("row = %v, want %v", records[1], want)
+	}
+	if records[2][5] != "" {
+		t.Errorf("symbol without complexity exported %q, want an empty cell", records[2][5])
+	}
+}
+
+func TestWriteRelationshipsCSVRoundTrip(t *testing.T) {
+	rels := []index.ExportRelationship{{From: "main", To: "fmt.Println", Kind: "call", Confidence: 0.5, File: "main.go", Line: 7}}
+
+	var buf bytes.Buffer
+	if err := WriteRelationshipsCSV(&buf, rels); err != nil {
+		t.Fatalf("WriteRelationshipsCSV() error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read back CSV: %v", err)
+	}
+	if len(records) != 2 || !reflect.DeepEqual(records[0], []string{"from", "to", "kind", "confidence", "file", "line"}) {
+		t.Fatalf("unexpected records: %v", records)
+	}
+	if !reflect.DeepEqual(records[1], []string{"main", "fmt.Println", "call", "0.5", "main.go", "7"}) {
+		t.Errorf("unexpected row: %v", records[1])
+	}
+}
+
+func TestExecuteExport(t *testing.T) {
+	root := t.TempDir()
+	src := "package main\n\nfunc helper() {}\n\nfunc main() {\n\thelper()\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := scan.Run(root); err != nil {
+		t.Fatalf("scan.Run() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExecuteExport(ExportOptions{Root: root, Format: "csv", What: "relationships"}, &buf); err != nil {
+		t.Fatalf("ExecuteExport() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "main,helper,call,1,main.go,6") {
+		t.Errorf("expected main -> helper call row, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := ExecuteExport(ExportOptions{Root: root, Format: "csv", What: "symbols"}, &buf); err != nil {
+		t.Fatalf("ExecuteExport(symbols) error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "main.go,helper,function,private,3,1,1") {
+		t.Errorf("expected helper with complexity 1, got:\n%s", buf.String())
+	}
+
+	if err := ExecuteExport(ExportOptions{Root: root, Format: "xlsx", What: "symbols"}, &buf); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+
+	// Index snapshots default to the format configured for the workspace.
+	if err := os.WriteFile(filepath.Join(root, ".palace", "palace.jsonc"), []byte(`{"scan": {"snapshotFormat": "binary"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := ExecuteExport(ExportOptions{Root: root, What: "index"}, &buf); err != nil {
+		t.Fatalf("ExecuteExport(index) error: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("PALIDX")) {
+		t.Errorf("expected a binary snapshot, got %q", buf.String())
+	}
+	snap, err := index.ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error: %v", err)
+	}
+	if len(snap.Symbols) != 2 || len(snap.Relationships) != 1 {
+		t.Errorf("expected 2 symbols and 1 relationship, got %+v", snap)
+	}
+}
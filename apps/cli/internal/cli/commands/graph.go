@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "graph",
+		Description: "Export the code graph of the workspace for graph tools",
+		Run:         RunGraph,
+	})
+}
+
+// GraphOptions contains the configuration for the graph command.
+type GraphOptions struct {
+	Root   string
+	Format string
+}
+
+// RunGraph executes the graph command with parsed arguments.
+func RunGraph(args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	format := fs.String("format", "graphml", "output format: graphml")
+	out := fs.String("out", "", "write the graph to this file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	opts := GraphOptions{Root: *root, Format: *format}
+	if opts.Format != "graphml" {
+		return UsageError(fmt.Errorf("unsupported format %q (supported: graphml)", opts.Format))
+	}
+
+	if *out == "" {
+		_, _, err := ExecuteGraph(opts, os.Stdout)
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("create output: %w", err)
+	}
+	nodes, edges, err := ExecuteGraph(opts, f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("exported %d nodes and %d edges to %s\n", nodes, edges, *out)
+	return nil
+}
+
+// ExecuteGraph parses the source files of the workspace, as scan would find
+// them and with its parser settings, and writes their code graph to w,
+// returning the number of nodes and edges written. Each file's nodes are
+// written as it is parsed, so the workspace is never held in memory whole.
+func ExecuteGraph(opts GraphOptions, w io.Writer) (nodes, edges int, err error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := scan.ApplyParserLimits(rootPath); err != nil {
+		return 0, 0, err
+	}
+	files, err := fsutil.ListFiles(rootPath, config.LoadGuardrails(rootPath))
+	if err != nil {
+		return 0, 0, fmt.Errorf("list files: %w", err)
+	}
+
+	imports := analysis.NewImportResolver(rootPath)
+	exporter := analysis.NewGraphMLExporter(w)
+	for _, rel := range files {
+		if !analysis.IsAnalyzable(rel) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(rootPath, filepath.FromSlash(rel)))
+		if err != nil {
+			return 0, 0, err
+		}
+		fa, err := analysis.Analyze(content, rel)
+		if err != nil {
+			continue // Skipped, as scan skips files it cannot parse
+		}
+		imports.ClassifyFile(fa)
+		if err := exporter.Add(fa); err != nil {
+			return 0, 0, fmt.Errorf("write graph: %w", err)
+		}
+	}
+	if err := exporter.Close(); err != nil {
+		return 0, 0, fmt.Errorf("write graph: %w", err)
+	}
+	return exporter.Nodes(), exporter.Edges(), nil
+}
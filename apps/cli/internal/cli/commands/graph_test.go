@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+func TestExecuteGraph(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.24\n",
+		"store/store.go": "package store\n\n// Load reads a value.\nfunc Load(key string) string {\n\treturn key\n}\n",
+		"main.go":        "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/app/store\"\n)\n\nfunc main() {\n\tfmt.Println(store.Load(\"a & b\"))\n}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	nodes, edges, err := ExecuteGraph(GraphOptions{Root: root, Format: "graphml"}, &buf)
+	if err != nil {
+		t.Fatalf("ExecuteGraph() error: %v", err)
+	}
+	if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if nodes != 4 {
+		t.Errorf("expected 4 nodes (two files, main, Load), got %d", nodes)
+	}
+	// Two contains edges, the import of store, and the call of Load
+	if edges != 4 {
+		t.Errorf("expected 4 edges, got %d:\n%s", edges, buf.String())
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<data key="edge_kind">import</data>`,
+		`<data key="edge_kind">call</data>`,
+		`<data key="name">Load</data>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in the graph:\n%s", want, out)
+		}
+	}
+}
+
+func TestExecuteGraphCallExclusions(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":               "module example.com/app\n\ngo 1.24\n",
+		".palace/palace.jsonc": `{"scan": {"callExclusions": {"go": ["store.*"]}}}`,
+		"store/store.go":       "package store\n\nfunc Load(key string) string {\n\treturn key\n}\n",
+		"main.go":              "package main\n\nimport \"example.com/app/store\"\n\nfunc main() {\n\tstore.Load(\"a\")\n}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The settings are global to the parsers; leave none for other tests
+	t.Cleanup(func() { analysis.SetCallExclusions(nil) })
+
+	var buf bytes.Buffer
+	if _, _, err := ExecuteGraph(GraphOptions{Root: root, Format: "graphml"}, &buf); err != nil {
+		t.Fatalf("ExecuteGraph() error: %v", err)
+	}
+	if strings.Contains(buf.String(), `<data key="edge_kind">call</data>`) {
+		t.Errorf("the excluded call to store.Load should have no edge:\n%s", buf.String())
+	}
+}
+
+func TestRunGraphUnsupportedFormat(t *testing.T) {
+	err := RunGraph([]string{"--root", t.TempDir(), "--format", "dot"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported format") {
+		t.Errorf("expected an unsupported format error, got %v", err)
+	}
+}
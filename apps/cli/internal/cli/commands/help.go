@@ -30,6 +30,7 @@ func ShowUsage() error {
 
 CORE COMMANDS
   explore   Search code, get context, or trace call relationships
+  context   Build a token-budgeted code and memory bundle for a topic
   store     Store knowledge in the palace (idea, decision, or learning)
   recall    Retrieve knowledge from the palace
   brief     Get briefing on workspace or file
@@ -39,13 +40,24 @@ SETUP & INDEX
   scan      Build/refresh the code index
   check     Verify index freshness and optionally generate CI outputs
   stats     Show index and knowledge statistics
+  diff      Show symbol-level changes since a git ref
+  api-export Export the exported API surface as JSON
+  api-diff  Compare an exported API surface with another or the workspace
+  graph     Export the code graph as GraphML for graph tools
+  query     Run structured queries against the code index
+  export    Export symbols or relationships as CSV
+  report    Report quality metrics such as documentation coverage
+  lint      Check code against the team's conventions (naming)
 
 SERVICES
   serve     Start MCP server for AI agents
   dashboard Start web dashboard for visualization
+  watch     Keep the index fresh as files change (--mcp to also serve MCP)
 
 AGENTS & SESSIONS
   session   Manage agent sessions
+  memory    Inspect the memory journal and undo recent changes
+  lint-memory Check stored knowledge against the team's conventions
 
 CROSS-WORKSPACE
   corridor  Cross-workspace knowledge sharing
@@ -71,6 +83,20 @@ BRIEF EXAMPLES
   palace brief                             # Workspace briefing
   palace brief src/auth.go                 # File-specific briefing
 
+GLOBAL OPTIONS
+  --data-dir <dir>  Keep each workspace's index and memory under
+                    <dir>/<workspace-id>/{index,memory} instead of .palace
+                    (also PALACE_DATA_DIR, or "dataDir" in palace.jsonc)
+
+EXIT CODES
+  0  Success
+  1  Error
+  2  Usage error: unknown command, bad flag, or missing argument
+  3  Findings: lint and lint-memory --strict violations, scan --strict warnings,
+     diff --fail-on-breaking breaking changes, report --min-coverage
+     shortfalls, check config problems
+  4  Verification failed: check found the index stale
+
 Run 'palace help <command>' for detailed help on a command.
 `)
 	return nil
@@ -90,6 +116,12 @@ Options:
   --detect          Auto-detect project type and generate profile
   --with-outputs    Also create generated outputs
 
+Writes .palace/palace.jsonc with doNotTouch and readOnly guardrail globs
+pre-tuned for the detected languages (from manifests such as go.mod or
+pyproject.toml, or from the source files themselves): their caches and
+environments, lockfiles, and generated sources. An existing palace.jsonc is
+kept unless --force is given.
+
 Examples:
   palace init
   palace init --detect
@@ -105,7 +137,24 @@ Options:
   --incremental    Force git-based incremental scan
   --deep           Enable LSP-based deep analysis for call tracking
   --verbose, -v    Show detailed progress information
-  --debug          Show debug information (LSP communication, etc.)
+  --debug          Show debug information (LSP communication, how ambiguous
+                   extensions like .h and .m were resolved, etc.)
+  --timeout <dur>  Cancel the scan after this long, e.g. 5m
+  --scan-secrets   Record likely hardcoded secrets (see 'palace query secrets')
+  --profile        Print wall time per phase and parse time per language
+  --profile-out <file>  Also write the timing breakdown as JSON
+  --generic-fallback    Extract symbols from files in unsupported languages
+  --tests-only     Index only test files
+  --no-tests       Leave test files out of the index
+  --max-depth <n>  Index only files at most n directories below the root (root = 0)
+  --blame          Attribute symbols to owners with git blame (see 'palace query owned-by')
+  --strict         Rescan every file and exit with code 3 if any gave analysis warnings
+  --repair-index   Discard the index, rebuild it from scratch, and swap it in once verified
+  --jobs <n>       Parse at most n files at once (default: number of CPUs)
+  --lsp-concurrency <n>  Send at most n requests at once to language servers
+                   during deep analysis (default: 1)
+  --max-memory <size>    Pause new work while the process uses more than this
+                   much memory, e.g. 512MB or 2G (default: no limit)
 
 The scan command parses your codebase using Tree-sitter and builds a structural index.
 By default, it auto-detects: if in a git repo with a previous scan, uses git diff
@@ -113,12 +162,69 @@ to find changed files (faster). Otherwise, uses hash-based change detection.
 
 For Dart/Flutter projects, deep analysis runs automatically to extract accurate call graphs.
 
+Ctrl-C or --timeout stops the scan between files. The files processed so far
+are written as a consistent index marked partial; the next scan finishes it.
+
+--profile times each phase: walk (listing files or detecting changes),
+resolve, parse, persist, processors, and deep-analysis. Parse time per
+language is summed across parallel workers, so it can exceed the parse phase.
+
+Files in languages palace has no parser for are indexed as text only. With
+--generic-fallback, text files among them are also searched for lines that
+look like definitions (def, fn, class, "name(args) {", ...), with extents
+taken from braces or indentation and comments above as docs. These symbols
+are guesses: they are marked low-confidence and the file's language is
+recorded as "generic".
+
+--tests-only and --no-tests select files by their language's test
+conventions (_test.go, test_*.py, *.spec.ts, *Test.java, src/test/, ...),
+after the guardrails. --max-depth keeps files near the root: with 0 only
+the root's own files, with 1 also those one directory down, and so on; the
+files it skips are counted after the scan. With any of these filters the
+scan compares the whole tree against the index, so files indexed earlier
+that the filters exclude are removed; run 'palace scan --full' to index
+everything again.
+
+--blame runs git blame on each file it indexes and records, for every
+symbol, the author of most of its lines and the latest commit among them.
+It is slow on large histories, so blames are cached by file content: later
+--blame scans only blame files that changed. Incremental scans attribute
+just the files they re-index; use 'palace scan --full --blame' for all.
+
+--repair-index is for an index that is corrupt or that scans no longer keep
+in step with the sources. It never reads the old index: every file is
+scanned into a new database beside it, which is checked for integrity and
+completeness before it replaces the old one. If the rebuild fails or is
+interrupted, the old index is left as it was. Memory (decisions, learnings,
+ideas) lives in its own database and is not touched.
+
+--jobs and --lsp-concurrency cap two separate pools: parsing, which is
+CPU-bound, and requests to language servers in deep analysis, which is
+bound by what the server can handle. One never waits on the other. Each
+parse gets its own parser, so files of one language parse side by side,
+except with a parser that has a single instance, such as the Go language
+server parser, which takes one file at a time. With --max-memory, new work
+of either kind waits while the resident memory of the process is over the
+limit, until running work finishes and frees some; work always proceeds
+when nothing else is running, so a limit set too low slows the scan down
+rather than stopping it.
+
 Examples:
   palace scan                  # Auto-detect: git-based if possible
   palace scan --full           # Force full rescan
   palace scan --incremental    # Force git-based incremental
   palace scan -v               # Show progress details
   palace scan --debug          # Debug mode for troubleshooting
+  palace scan --full --timeout 5m
+  palace scan --full --scan-secrets
+  palace scan --full --profile --profile-out scan-profile.json
+  palace scan --full --generic-fallback
+  palace scan --no-tests
+  palace scan --full --jobs 4 --lsp-concurrency 2 --max-memory 2G
+  palace scan --full --max-depth 2
+  palace scan --full --blame
+  palace scan --strict         # CI: fail on analysis warnings
+  palace scan --repair-index   # Rebuild a corrupt index
 `)
 	case "check":
 		fmt.Print(`palace check - Verify index freshness
@@ -133,6 +239,37 @@ Options:
   --signal            Also generate change signal from diff
 
 The check command ensures the index is up-to-date and validates configuration.
+It exits with code 3 when the configuration is invalid and 4 when the index is
+stale, so CI can tell the two apart.
+`)
+	case "context":
+		fmt.Print(`palace context - Build a context bundle for a topic
+
+Usage: palace context <topic> [options]
+
+Options:
+  --root <path>    Workspace root (default: current directory)
+  --budget <n>     Maximum tokens in the bundle (default: 4000)
+  --limit <n>      Maximum candidates from each source (default: 20)
+  --json           Output as JSON
+
+Combines the code index (matching symbols with their callers, and matching
+snippets) with the palace's memory (decisions, learnings, and ideas) into one
+markdown bundle for pasting into a prompt. Code and memory each get half the
+budget, and either may use what the other leaves. Entries are added in rank
+order; one that would exceed the budget is cut to its heading and first line
+if that fits, and left out otherwise. Pinned records and the two top-ranked
+decisions are added before anything else, so they are only left out when the
+budget cannot hold them. The bundle lists what it left out.
+
+Memory records are ranked by how closely they match the topic, multiplied by
+recall.decisionBoost for decisions (default 1.5) and recall.pinnedBoost for
+records tagged "pinned" (default 2.0) in palace.jsonc, so binding decisions
+come first. A boost of 1.0 has no effect.
+
+Examples:
+  palace context authentication
+  palace context "session storage" --budget 2000
 `)
 	case "explore":
 		fmt.Print(`palace explore - Search code, get context, or trace calls
@@ -182,15 +319,46 @@ Options:
   --root <path>       Workspace root (default: current directory)
   --type <type>       Filter by type: decision, idea, learning
   --pending           Show decisions awaiting outcome
+  --since-last-session
+                      Show records created or changed since the last
+                      catch-up, then advance it to now
+  --out <file>        Write the learnings found to a file
+  --copy              Copy the learnings found to the clipboard
+  --template <t>      Format for --out/--copy: markdown (default), list,
+                      table, json, or a Go template such as '{{.Content}}'
 
 Subcommands:
   update    Record decision outcome
   link      Create relationship between records
+
+--out and --copy export learnings in the formats of the recall MCP tool's
+template argument. Where no clipboard tool is available (pbcopy, clip,
+wl-copy, xclip, or xsel), --copy prints a warning instead of failing.
+
+--since-last-session shares its marker with the recall MCP tool's
+sinceLastSession argument, so a catch-up from either moves it for both.
+
+Examples:
+  palace recall --since-last-session
+  palace recall "auth" --out auth-notes.md
+  palace recall "auth" --copy --template list
 `)
 	case "serve":
 		fmt.Print(`palace serve - Start MCP server for AI agents
 
 Starts a Model Context Protocol server on stdio.
+
+Usage: palace serve [options]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --mode <mode>     'agent' (restricted, default) or 'human' (full access)
+  --data-dir <dir>  Data directory holding the workspace stores
+
+The server opens the index and memory of the workspace at --root. With a
+data directory configured they live under <dir>/<workspace-id>, where the
+workspace id is the root's directory name and a hash of its absolute path,
+so one install serves many repositories without mixing their records.
 `)
 	case "session":
 		fmt.Print(`palace session - Manage agent sessions
@@ -199,6 +367,144 @@ Usage: palace session <command> [options]
 
 Commands:
   start, end, list, show
+`)
+	case "memory":
+		fmt.Print(`palace memory - Inspect and maintain the knowledge store
+
+Usage: palace memory <command> [options]
+
+Commands:
+  log               Show the journal of memory mutations (newest first)
+  undo [n]          Revert the n most recent mutations (default: 1)
+  compact           Drop old journal entries
+  review [id]       List uncertain classifications, or resolve one with --as
+  promote <id>      Move a record to another scope in place
+  history <id>      List the past versions of an edited record
+  relink            Link related records (shared anchor, tags, or content)
+  stats             Counts by kind and scope, tag histogram, weekly growth
+  gc                Archive low-value records per the forget policy
+  import <workspace>
+                    Copy another workspace's records into this store
+  import --in <file.jsonl> --stream
+                    Import records from a JSON-lines file, one at a time
+  pinned            List pinned records, oldest pin first
+  rooms             Map records onto the directory tree next to its code
+  bundle --tag <tag>
+                    Package one investigation: the records with a tag, their
+                    links, and the code they are anchored to
+  bundle-open <file>
+                    Read a bundle made by 'palace memory bundle'
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --limit <n>       log, review: maximum number of entries (default: 20)
+  --keep <n>        compact: number of newest entries to keep (default: 100)
+  --as <kind>       review: confirm or correct the kind (idea, decision, learning)
+  --to <scope>      promote: target scope (palace, room, file)
+  --restore <n>     history: make version n current again
+  --path <path>     promote: room name or file path (required for room and file);
+                    pinned: only records with this room name or file path
+  --scope <scope>   pinned: only records in this scope
+  --query <text>    pinned: only records matching the query
+  --dry-run         relink, gc, import: list what would change without changing it
+  --min-shared-tags <n>    relink: tags two records must share (default: 2)
+  --min-tag-overlap <f>    relink: Jaccard overlap of tag sets (default: 0.5)
+  --min-similarity <f>     relink: Jaccard overlap of content words (default: 0.6)
+  --tags            stats: list every tag, not just the 10 most used
+  --min-age <days>  gc: days since a record was created and last recalled (default: 90)
+  --max-access <n>  gc: recalls a record may have had (default: 0)
+  --max-confidence <f>     gc: confidence a learning may have (default: 0.3)
+  --merge           import: also skip records whose content is already held
+  --replace         import --in: overwrite records held under the same ID
+  --strict          import --in: stop at the first malformed line
+  --depth <n>       rooms: directory levels each area spans (default: 2)
+  --dark            rooms: only list areas with code but no records
+  --tag <tag>       bundle: bundle the records carrying this tag (required)
+  --out <file>      bundle: write the bundle to this file (default: stdout)
+  --json            history, stats, gc, import, pinned, rooms, bundle-open: output as JSON
+
+Every store, forget, link, unlink, append, edit, promote, and gc archive is journaled with
+before/after snapshots. Undo restores forgotten records with their links and tags,
+removes stored ones, moves promoted ones back, and unarchives what gc archived. The journal keeps the
+newest 1000 entries.
+
+History lists the versions a record had before it was edited through the
+MCP update tool, newest first, each with its kind and tags. The record
+itself always holds the current version. Restoring a version is itself an
+edit, so the version it replaces joins the history. The newest 20 past
+versions of each record are kept.
+
+Relink proposes a "related" link between two records anchored to the same
+file (by file scope or a code link), sharing enough tags, or with closely
+matching content. Records already linked to each other by any relation are
+left alone. Thresholds can also be set in palace.jsonc under "relink".
+
+Gc archives (soft-deletes) ideas and learnings that are old, rarely or
+never recalled, low in confidence (learnings), and unlinked. Decisions and
+records tagged "pinned" are never archived. Archived ideas are hidden from
+recall unless asked for by status. Set the policy in palace.jsonc under
+"forget" (minAgeDays, maxAccessCount, maxConfidence, excludeKinds); with
+"onStartup": true it is also applied whenever 'palace serve' starts.
+
+Import syncs stores kept on different machines. Records keep their IDs, so
+importing again only adds what is new. With --merge, records are also
+matched by a hash of their kind, scope, and normalized content, so the same
+knowledge stored separately on each machine is not duplicated. A record
+held under the same ID but edited differently on each side is reported as
+a conflict and left unchanged. Tags are imported; links are not.
+
+Import --in --stream migrates large exports. Each line holds one record, in
+its JSON form with a "kind" of decision, learning, or idea and optional
+"tags", e.g.
+  {"kind": "learning", "id": "lrn_1", "content": "...", "tags": ["db"]}
+Lines are read and stored one at a time, so memory use stays flat however
+large the file, and progress is printed to stderr every 1000 lines.
+Malformed lines are skipped and listed at the end; with --strict the first
+one stops the import, keeping the records before it. With --replace, a
+record held under the same ID with different content is overwritten
+instead of reported as a conflict.
+
+Pinned lists the records tagged "pinned", the working reference that
+stays relevant whatever the task, in the order they were pinned. Other
+filters apply only when given. Archived records are left out.
+
+Rooms shows where thinking is concentrated. Each area is a directory cut
+to --depth levels, with its indexed files and symbols beside the decisions,
+ideas, and learnings about it. File-scoped records count toward their
+file's area. Room-scoped records count toward the area of the room's first
+entry point (.palace/rooms/<room>.jsonc), else the directory the room is
+named after; rooms that match no directory are listed separately. Areas
+with code but no records are marked dark.
+
+Bundle packages one investigation so it can be shared or archived: every
+record tagged --tag, with all of its tags, the links among those records
+and from them to code and URLs, and a snapshot of the code they are
+anchored to (code links and file scopes). For each anchor the bundle keeps
+the lines it points at, up to 80, and the signatures of the symbols defined
+there, read from the index. Bundle-open prints a bundle on any machine,
+with or without the workspace it came from.
+
+Records auto-classified below 70% confidence are queued for review.
+Resolving one with a different kind re-stores it under that kind; either
+way its opening words become a rule for classifying future records. A rule
+stays tentative, still queueing the records it files, until a second review
+agrees with it.
+
+Examples:
+  palace memory log
+  palace memory undo 3
+  palace memory review i_abc123 --as decision
+  palace memory promote lrn_abc123 --to palace
+  palace memory history d_abc123 --restore 2
+  palace memory relink --dry-run
+  palace memory stats --tags
+  palace memory gc --dry-run
+  palace memory import ../laptop-checkout --merge
+  palace memory import --in brain.jsonl --stream --replace
+  palace memory pinned --json
+  palace memory rooms --depth 3 --dark
+  palace memory bundle --tag incident-123 --out bundle.json
+  palace memory bundle-open bundle.json
 `)
 	case "brief":
 		fmt.Print(`palace brief - Get briefing on workspace or file
@@ -282,11 +588,492 @@ Usage: palace stats [options]
 
 Options:
   --root <path>     Workspace root (default: current directory)
+  --recompute       Recount the index from scratch, report whether the
+                    running counts had drifted, and repair them
 
 Displays statistics about:
 - Index: files, symbols (by kind), relationships, last scan
 - Knowledge: ideas, decisions, learnings
 - Sessions: total and active count
+
+Index counts are kept as files are indexed and removed, so reading them
+takes the same time however large the index is.
+`)
+	case "lint":
+		fmt.Print(`palace lint - Check code against the team's conventions
+
+Usage: palace lint naming [options]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --lang <lang>     Only check files in this language (e.g. python)
+  --strict          Exit with code 3 if any name breaks a convention
+  --json            Output as JSON
+
+naming parses the workspace's source files and flags each symbol whose name
+breaks its language's convention, such as a Python function that is not
+snake_case or a Go constant with underscores. The built-in conventions:
+
+  go          functions, methods, types, constants, variables: MixedCaps
+  python      functions, methods: snake_case; classes: PascalCase
+  javascript, typescript
+              methods: camelCase; classes, interfaces: PascalCase
+  java        methods: camelCase; types: PascalCase; constants: UPPER_CASE
+  kotlin      methods: camelCase; classes, interfaces: PascalCase
+  csharp      classes, interfaces, methods: PascalCase
+  rust        functions, methods: snake_case; types: PascalCase;
+              constants: UPPER_CASE
+  ruby        functions, methods: snake_case; classes: PascalCase
+
+Rules under namingLint in palace.jsonc replace the built-in convention for
+their language and kinds. A rule sets a style (snake_case, camelCase,
+PascalCase, UPPER_CASE, or MixedCaps), a pattern names must match, or "off";
+visibility limits it to exported or unexported symbols. noDefaults drops the
+built-in conventions.
+
+  "namingLint": {
+    "rules": [
+      { "language": "kotlin", "kinds": ["function"], "style": "camelCase", "visibility": "exported" },
+      { "language": "go", "kinds": ["variable"], "style": "off" },
+      { "language": "typescript", "kinds": ["function"], "pattern": "^(use)?[a-z][A-Za-z0-9]*$" }
+    ]
+  }
+
+Examples:
+  palace lint naming
+  palace lint naming --lang python --strict
+  palace lint naming --json
+`)
+	case "lint-memory":
+		fmt.Print(`palace lint-memory - Check stored knowledge against the team's conventions
+
+Usage: palace lint-memory [options]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --kind <kind>     Only check ideas, decisions, or learnings
+  --strict          Exit with code 3 if any record breaks a rule
+  --json            Output as JSON
+
+Rules are set under memoryLint in palace.jsonc. Each rule applies to one
+kind (or every kind when kind is omitted) and checks any of: a regex the
+content must match, a minimum and maximum length, tags the record must
+carry, and, for decisions, that a rationale was recorded.
+
+  "memoryLint": {
+    "onStore": "warn",
+    "rules": [
+      {
+        "name": "decision-prefix",
+        "kind": "decision",
+        "pattern": "^Decided to ",
+        "requireRationale": true,
+        "message": "start decisions with \"Decided to\" and say why"
+      },
+      { "kind": "learning", "minLength": 20, "maxLength": 500 }
+    ]
+  }
+
+onStore applies the rules when agents store through MCP: "warn" (the
+default) stores the record and lists what it breaks, "reject" refuses it,
+and "off" skips the check.
+
+Examples:
+  palace lint-memory
+  palace lint-memory --kind decision --strict
+`)
+	case "watch":
+		fmt.Print(`palace watch - Keep the index fresh as files change
+
+Usage: palace watch [options]
+
+Options:
+  --root <path>       Workspace root (default: current directory)
+  --mcp               Also run the MCP server on stdin/stdout
+  --mode <mode>       MCP mode: agent (default) or human
+  --interval <dur>    Polling interval (default: 2s)
+
+With --mcp, the server answers from the live-updated index and emits
+notifications/resources/updated (and list_changed for added or removed
+files) whenever a reindex happens. Ctrl+C stops both the watcher and server.
+
+Examples:
+  palace watch
+  palace watch --mcp
+`)
+	case "diff":
+		fmt.Print(`palace diff - Show symbol-level changes since a git ref
+
+Usage: palace diff --git <ref> [options]
+       palace diff --snapshot <file> [options]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --git <ref>       Git ref to compare the working tree against
+  --snapshot <file> Index snapshot to compare the current index against
+  --json            Output as JSON
+  --fail-on-breaking  Exit with code 3 if any change is breaking
+
+Reports functions, types, and other symbols that were added, removed, or had
+their signature or body changed. Each change carries a stable ID
+(<path>#<Qualified.Name>) suitable for review tooling and test selection.
+Overloaded methods add their parameter types (Calc.java#Calc.add(int,int)) so
+each overload is tracked separately.
+
+Signature changes to functions and methods list what happened to each
+parameter: added, removed, retyped, renamed, moved, or a default changed.
+Changes that can break existing callers are marked (breaking): a required or
+mid-list parameter added, a parameter removed, retyped, or reordered, a
+default dropped, and renames in languages with named arguments. In JSON each
+signature change carries these as "params", and type parameter changes to
+generic symbols as "typeParams".
+
+With --fail-on-breaking, removed symbols and breaking signature changes make
+the command exit with code 3 after printing the report, for gating pull
+requests. A signature change without parameter detail, such as a new return
+type, counts as breaking.
+
+With --snapshot, the symbols of an index snapshot saved by 'palace export
+--what index' are compared with the current index, without git. Snapshots
+hold no source, so only added, removed, and signature changes are reported.
+
+Examples:
+  palace diff --git main
+  palace diff --git HEAD~3 --json
+  palace diff --git origin/main --fail-on-breaking
+  palace diff --snapshot release.snapshot --fail-on-breaking
+`)
+	case "api-export":
+		fmt.Print(`palace api-export - Export the exported API surface as JSON
+
+Usage: palace api-export [options]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --out <file>      Write the API surface to this file (default: stdout)
+
+Lists the exported symbols of the workspace by package (the source files of
+a directory in one language), with their signatures, parameters, return
+types, type parameters, and doc comments. Test files and test functions,
+unexported symbols and the members of unexported types, and the symbols of
+data and markup files are left out.
+
+The output carries no line numbers and is sorted, so it changes only when
+the API does: commit it, or keep one per release, to track the public API
+over time. Each symbol has the same stable ID as in 'palace diff'
+(<path>#<Qualified.Name>), and 'palace api-diff' compares two surfaces. Files
+are parsed directly, so no scan is needed.
+
+Examples:
+  palace api-export --out api.json
+  palace api-export | jq '.packages[].symbols[].id'
+`)
+	case "api-diff":
+		fmt.Print(`palace api-diff - Compare an exported API surface with another or the workspace
+
+Usage: palace api-diff [options] <old.json> [new.json]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --json            Output as JSON
+  --fail-on-breaking  Exit with code 3 if any change is breaking
+
+Compares an API surface written by 'palace api-export' with a second one, or
+with the current API of the workspace when only one is given. Exported
+symbols that were added, removed, or had their signature changed are
+reported as in 'palace diff', with the same parameter detail and (breaking)
+marks. Only the exported API is compared, so changes to internal code do not
+show up.
+
+Examples:
+  palace api-diff api.json
+  palace api-diff --json v1-api.json v2-api.json
+  palace api-diff --fail-on-breaking api.json
+`)
+	case "graph":
+		fmt.Print(`palace graph - Export the code graph as GraphML for graph tools
+
+Usage: palace graph [options]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --format <name>   Output format: graphml (default: graphml)
+  --out <file>      Write the graph to this file (default: stdout)
+
+Writes a node for each source file and each of its symbols, with name, kind,
+file, language, and line attributes, and a directed edge for each
+relationship: contains (a file's symbols, a type's members), import, call,
+reference, extends, implements, and the rest of the kinds scan records.
+
+Relationships name their targets, so edges are resolved by name across the
+workspace: a target matching several symbols gets an edge to each, with a
+confidence of 1/n, and symbols of the calling file win over others. Targets
+outside the workspace, such as the standard library, and names matching more
+than 10 symbols get no edge. Files are
+parsed directly and nodes streamed out as they are, so no scan is needed.
+
+The output loads into Gephi, yEd, Cytoscape, or networkx.
+
+Examples:
+  palace graph --out code.graphml
+  palace graph --root ./services/api --format graphml > api.graphml
+`)
+	case "query":
+		fmt.Print(`palace query - Run structured queries against the code index
+
+Usage: palace query <command> [options]
+
+Commands:
+  annotated <name>  List symbols carrying an annotation, decorator, or attribute
+  unresolved        List the most common call targets that resolve to no
+                    indexed symbol, grouped by likely cause
+  recent-changes    List symbols in files modified within a time window
+  imports           List imports classified as local, stdlib, or thirdparty
+  secrets           List likely hardcoded secrets recorded by a scan
+  commented-code    List blocks of commented-out code with line ranges
+  deprecated        List symbols marked deprecated
+  callgraph <name>  Render the calls around a symbol as Mermaid or DOT
+  owned-by <author> List symbols attributed to an author by git blame
+  signature-search  Find functions by parameter and return types
+  constants         List constants with their literal values
+  impls-of-method <Interface>.<method>
+                    List the types implementing an interface method, with
+                    file:line of each version
+  hotspots          Rank functions by complexity, size, and git churn
+  weak-tests        List tests and subtests that make no assertions
+  neighbors         List the files a file depends on (upstream) and the files
+                    depending on it (downstream), with edge counts
+  config-usage <key>
+                    List the code reading a configuration or environment key,
+                    with the enclosing function
+  calls-into <dir>  List the symbols of a directory called or referenced from
+                    files outside it, by number of calling files: the API the
+                    rest of the code actually uses
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --limit <n>       annotated, owned-by, signature-search: maximum number of
+                    results (default: no limit)
+  --top <n>         unresolved: number of callee names to list (default: 50);
+                    hotspots: number of functions to list (default: 20)
+  --within <dur>    recent-changes: time window, e.g. 30m, 24h, 7d (default: 24h)
+  --lang <lang>     recent-changes: only files in this language
+  --kind <kind>     recent-changes: only symbols of this kind;
+                    imports: local, stdlib, or thirdparty
+  --module <name>   imports: only this module or its submodules
+  --experimental    deprecated: list experimental symbols instead
+  --depth <n>       callgraph: levels of calls to follow, 1-10 (default: 2);
+                    neighbors: levels of files to follow, 1-10 (default: 1)
+  --incoming        callgraph: follow callers instead of callees
+  --file <path>     callgraph: defining file when the name is ambiguous
+  --format <fmt>    callgraph: mermaid (default), dot, or json
+  --param <type>    signature-search: a parameter type, in order (repeatable)
+  --returns <type>  signature-search: a return type, in order (repeatable)
+  --fuzzy           signature-search: match partial types in any order
+  --path <prefix>   constants: only files under this path
+  --variables       constants: include variables assigned a literal
+  --compact         One line per result: location, kind, and name
+  --rich            Each result with its signature, doc comment, calls, and
+                    number of callers
+  --json            Output as JSON
+
+Listing commands (all but unresolved, callgraph, and neighbors) print rich output on a
+terminal and compact output when piped, so grep and wc see one line per
+result; --compact and --rich override the choice.
+
+Annotations are indexed uniformly across languages: Java/Kotlin @Annotations,
+C# [Attributes], Rust #[attributes], and Python @decorators. The name matches
+with or without arguments and sigils, and a dotted decorator also matches its
+last segment (app.route matches "route").
+
+Unresolved calls are classified as external (qualified call into code that
+was not scanned), untracked (builtins, import aliases, dynamic calls), or
+ambiguous (the name is defined in several files). A high external count
+usually means more directories should be scanned; a high untracked count
+points at calls the parser cannot follow.
+
+Recent changes are judged from file modification times, so no git history is
+needed. Symbols come from the last scan; files edited since then are marked
+"changed since last scan" and may list outdated symbols.
+
+Imports are classified during the scan. Relative imports and imports of the
+project's own top-level packages (or its Go module path) are local; imports
+from the language's standard library are stdlib; everything else is
+thirdparty. Use --kind thirdparty --module <name> to find every file that
+depends on an external package.
+
+Secrets are only recorded by 'palace scan --scan-secrets'. String literals are
+checked for known key formats (AWS, GitHub, Slack, Stripe, Google, PEM private
+keys) and for long high-entropy tokens; comments are ignored. Findings give
+the rule and location but never the secret itself.
+
+Commented-out code is detected on every scan: runs of three or more comment
+lines that mostly read as code (calls, assignments, declarations) and include
+braces, semicolons, or a block-opening colon. Prose comments are not flagged.
+
+Deprecated symbols are recognized from Go "// Deprecated:" doc paragraphs,
+@deprecated doc tags and decorators, Java/Kotlin @Deprecated, C# [Obsolete],
+Rust #[deprecated], and "DEPRECATED" comment markers. Experimental symbols are
+marked the same way (@experimental, "Experimental:", EXPERIMENTAL).
+
+Callgraphs follow calls breadth-first from the symbol. A call resolves by
+name to the caller's own file or to the only file defining it; calls that
+don't (library calls, ambiguous names) are drawn as dashed ghost nodes and
+not followed.
+
+Owners are recorded by 'palace scan --blame'. The author matches any part of
+an owner's name or email, case-insensitively.
+
+Signature search reads parameter and return types from indexed signatures.
+By default the parameters must be exactly the types given, in order, and
+the return types likewise; leaving out --param or --returns leaves that side
+open. With --fuzzy each type only has to appear in some parameter (or return
+type), case-insensitively, so "Context" matches context.Context and extra
+parameters are allowed. Untyped parameters, as in plain Python or
+JavaScript, match no type.
+
+Constant values are recorded for simple literal assignments: strings (shown
+without quotes), numbers, and booleans, cut to 120 characters. Computed
+values, calls, and interpolated strings are listed by name only elsewhere and
+left out here. Values are read by the Python, Go, JavaScript, TypeScript,
+and Rust parsers.
+
+Method implementations are found through implements and extends
+relationships, followed transitively so a subclass is found through its
+base class, and for Go by method set: a type implements an interface when it
+has methods named like all of the interface's, so a type that merely shares
+one method name is not listed. Only types that declare the method
+themselves are listed.
+
+Hotspots score every indexed function and method from 0 to 1 on three
+metrics: estimated cyclomatic complexity (one plus its branches and boolean
+operators), length in lines, and churn, the number of commits touching its
+file. Each metric is scaled against its largest value in the workspace and
+the score is their weighted average. Outside a git repository churn is left
+out. Weights are set in palace.jsonc, e.g.
+  "hotspots": {"complexityWeight": 2, "sizeWeight": 1, "churnWeight": 1,
+               "churnSince": "1 year ago"}
+
+Weak tests are found in test files (see 'palace scan --tests-only'). Test
+functions are recognized by name (Go's TestXxx, test_* and testXxx
+elsewhere) or by annotations such as @Test, [Fact], and #[test]; Go t.Run
+calls, Python self.subTest blocks, and the cases of pytest.mark.parametrize
+within them are indexed as subtests of kind "test". In JavaScript and
+TypeScript the describe, it, and test blocks are the tests, nested as
+written. Subtests are listed by their full path, such as
+TestParse/numbers/negative. Assertions are counted from the source: t.Error/t.Fatal and
+assert/require calls in Go, assert statements and self.assert* in Python,
+expect() and assert() in JavaScript, assert! macros in Rust, and assert*,
+Assert.*, expect, and verify calls elsewhere. A test counts the assertions
+of its subtests, so only a test none of whose subtests assert is weak. A
+table-driven subtest is one subtest named by its name expression.
+
+Neighbors aggregate the index to files: a file depends on another when it
+imports it, calls a function defined there, or references a type defined
+there. Calls and references resolve as in callgraphs; local imports resolve
+by matching the module path against indexed file paths, so
+"myapp/internal/auth" finds internal/auth/*.go and "pkg.util" finds
+pkg/util.py. Edges that resolve to no single file are left out. Beyond
+depth 1 each file is reached through a nearer one (via), and its edge count
+is the number of edges linking it to that level.
+
+Config keys are indexed from string literals passed to the common accessors
+of each language: os.Getenv in Go, os.environ[...] and os.getenv in Python,
+process.env in JavaScript, ENV[...] in Ruby, System.getenv and @Value in
+Java and Kotlin, env::var in Rust, getenv in PHP and C, and
+Environment.GetEnvironmentVariable in C#, as well as config.get(...) and
+settings.get(...) calls anywhere. Keys built at run time are not seen.
+
+Examples:
+  palace query annotated Deprecated
+  palace query annotated app.route --json
+  palace query unresolved --top 50
+  palace query recent-changes --within 24h --kind function
+  palace query imports --kind thirdparty --module requests
+  palace query secrets
+  palace query commented-code
+  palace query deprecated --json
+  palace query callgraph ExecuteScan --depth 1
+  palace query callgraph openQueryIndex --incoming --format dot
+  palace query owned-by alice@example.com
+  palace query signature-search --param context.Context --param string --returns error
+  palace query signature-search --param Context --returns error --fuzzy
+  palace query constants --path config/
+  palace query impls-of-method Server.Serve
+  palace query hotspots --top 20
+  palace query weak-tests --json
+  palace query neighbors auth/jwt.go --depth 2
+  palace query config-usage DB_HOST
+  palace query calls-into internal/auth --json
+  palace query deprecated --compact | wc -l
+`)
+	case "export":
+		fmt.Print(`palace export - Export index data for spreadsheets and other tools
+
+Usage: palace export [options]
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --format <fmt>    Output format: csv for symbols and relationships; json or
+                    binary for index (default: scan.snapshotFormat in
+                    palace.jsonc, else json)
+  --what <data>     symbols, relationships, or index (default: symbols)
+  --out <file>      Write to a file instead of stdout
+
+Columns:
+  symbols:        path, name, kind, visibility, start_line, complexity,
+                  line_count
+  relationships:  from, to, kind, confidence, file, line
+
+The column set is stable so spreadsheets and scripts can rely on it. "from" is
+the enclosing symbol of a relationship (empty at file level); "to" is the
+target symbol, or the imported file for imports. complexity is the estimated
+cyclomatic complexity of functions and methods (empty for other symbols).
+confidence is how surely "to" resolves to one definition: 1 for imports and
+targets in the same file, 1/n for targets defined in n other files, and 0 for
+targets the index does not define.
+
+An index export is a snapshot of every symbol and relationship with all their
+fields, for loading into other tools. JSON snapshots are readable and easy to
+diff; binary snapshots are smaller and load several times faster, which
+matters on very large repositories. Both record a layout version (binary ones
+in a "PALIDX" magic header), so readers can tell the formats apart and
+upgrade snapshots written by older versions. 'palace diff --snapshot' compares
+a saved snapshot with the current index.
+
+Examples:
+  palace export --format csv --what symbols > symbols.csv
+  palace export --what relationships --out relationships.csv
+  palace export --what index --format binary --out index.snapshot
+`)
+	case "report":
+		fmt.Print(`palace report - Report quality metrics of the indexed code
+
+Usage: palace report <command> [options]
+
+Commands:
+  doc-coverage      Share of exported symbols with a doc comment, overall
+                    and by language
+
+Options:
+  --root <path>     Workspace root (default: current directory)
+  --list            doc-coverage: list the undocumented exported symbols
+  --min-coverage <pct>
+                    doc-coverage: exit with code 3 when overall coverage is
+                    below this percentage, for CI
+  --json            Output as JSON
+
+Documentation coverage counts the symbols the parsers mark exported, such
+as Go capitalized names or Python names without a leading underscore, and
+those among them with a doc comment. Data, markup, and configuration files
+(JSON, YAML, Markdown, CSS, SQL, ...) are left out. A workspace with no
+exported symbols counts as fully covered. Run 'palace scan' first so the
+numbers reflect the current code.
+
+Examples:
+  palace report doc-coverage
+  palace report doc-coverage --list
+  palace report doc-coverage --min-coverage 80 --json
 `)
 	case "artifacts":
 		fmt.Print(`Mind Palace Artifacts
@@ -297,7 +1084,7 @@ Generated: .palace/index/*, .palace/outputs/*
 	case "all":
 		fmt.Println(ExplainAll())
 	default:
-		return fmt.Errorf("unknown help topic: %s\n\nAvailable topics: explore, store, recall, brief, init, scan, check, stats, serve, session, corridor, dashboard, clean, mcp-config, artifacts", topic)
+		return fmt.Errorf("unknown help topic: %s\n\nAvailable topics: explore, store, recall, brief, init, scan, check, stats, diff, api-export, api-diff, query, export, report, lint, serve, watch, session, memory, corridor, dashboard, clean, mcp-config, artifacts", topic)
 	}
 	return nil
 }
@@ -41,7 +41,7 @@ func RunInit(args []string) error {
 	skipDetect := fs.Bool("skip-detect", false, "skip auto-detection of project type")
 	noScan := fs.Bool("no-scan", false, "skip automatic scan after init (not recommended)")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	opts := InitOptions{
@@ -71,6 +71,7 @@ func ExecuteInit(opts InitOptions) error {
 
 	// Auto-detect project type by default
 	language := "unknown"
+	languages := []string{language}
 	var monorepoRooms []MonorepoRoom
 	if !opts.SkipDetect {
 		profile := project.BuildProfile(rootPath)
@@ -80,6 +81,7 @@ func ExecuteInit(opts InitOptions) error {
 		}
 		if len(profile.Languages) > 0 {
 			language = profile.Languages[0]
+			languages = profile.Languages
 		}
 		fmt.Printf("detected project type: %s\n", language)
 
@@ -91,10 +93,17 @@ func ExecuteInit(opts InitOptions) error {
 	}
 
 	replacements := map[string]string{
-		"projectName": filepath.Base(rootPath),
-		"language":    language,
+		"projectName":     filepath.Base(rootPath),
+		"language":        language,
+		"languages":       strings.Join(languages, ", "),
+		"doNotTouchGlobs": jsonListItems(project.IgnoreGlobs(languages)),
+		"readOnlyGlobs":   jsonListItems(project.ReadOnlyGlobs(languages)),
+	}
+	configPath := filepath.Join(rootPath, ".palace", "palace.jsonc")
+	if _, err := os.Stat(configPath); err == nil && !opts.Force {
+		fmt.Printf("keeping existing %s (use --force to overwrite)\n", configPath)
 	}
-	if err := config.WriteTemplate(filepath.Join(rootPath, ".palace", "palace.jsonc"), "palace.jsonc", replacements, opts.Force); err != nil {
+	if err := config.WriteTemplate(configPath, "palace.jsonc", replacements, opts.Force); err != nil {
 		return err
 	}
 	if err := config.WriteTemplate(filepath.Join(rootPath, ".palace", "rooms", "project-overview.jsonc"), "rooms/project-overview.jsonc", map[string]string{}, opts.Force); err != nil {
@@ -378,3 +387,16 @@ func writeMonorepoRoom(path string, room MonorepoRoom) error {
 
 	return os.WriteFile(path, data, 0o600)
 }
+
+// jsonListItems renders items as the inside of a JSON string array, without
+// the outer quotes, so that it fills a ["{{placeholder}}"] in a template
+// that stays valid JSONC before rendering.
+func jsonListItems(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		data, _ := json.Marshal(item)
+		quoted[i] = string(data)
+	}
+	list := strings.Join(quoted, ", ")
+	return strings.TrimSuffix(strings.TrimPrefix(list, `"`), `"`)
+}
@@ -1,9 +1,15 @@
 package commands
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/jsonc"
+	"github.com/koksalmehmet/mind-palace/apps/cli/starter"
 )
 
 func TestRunInitInvalidFlag(t *testing.T) {
@@ -96,3 +102,53 @@ func TestExecuteInitForceOverwrite(t *testing.T) {
 		t.Fatalf("Second ExecuteInit() error: %v", err)
 	}
 }
+
+func TestExecuteInitTunesConfigForPython(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"app.py", "models.py"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("def main():\n    pass\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ExecuteInit(InitOptions{Root: root, NoScan: true}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+
+	cfg, err := config.LoadPalaceConfig(root)
+	if err != nil {
+		t.Fatalf("generated palace.jsonc does not load: %v", err)
+	}
+	if cfg.Project.Language != "python" {
+		t.Errorf("project.language = %q, want python", cfg.Project.Language)
+	}
+	if !slices.Contains(cfg.Guardrails.DoNotTouchGlobs, "**/__pycache__/**") {
+		t.Errorf("expected Python caches in doNotTouchGlobs, got %v", cfg.Guardrails.DoNotTouchGlobs)
+	}
+	if !slices.Contains(cfg.Guardrails.ReadOnlyGlobs, "**/*_pb2.py") {
+		t.Errorf("expected generated Python sources in readOnlyGlobs, got %v", cfg.Guardrails.ReadOnlyGlobs)
+	}
+
+	// A second init without --force keeps the existing config
+	configPath := filepath.Join(root, ".palace", "palace.jsonc")
+	if err := os.WriteFile(configPath, []byte(`{"project": {"language": "custom"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExecuteInit(InitOptions{Root: root, NoScan: true}); err != nil {
+		t.Fatalf("second ExecuteInit() error: %v", err)
+	}
+	if cfg, _ := config.LoadPalaceConfig(root); cfg == nil || cfg.Project.Language != "custom" {
+		t.Errorf("init without --force overwrote the existing config")
+	}
+}
+
+func TestPalaceTemplateIsValidJSONC(t *testing.T) {
+	tpl, err := starter.Get("palace.jsonc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg config.PalaceConfig
+	if err := json.Unmarshal(jsonc.Clean([]byte(tpl)), &cfg); err != nil {
+		t.Errorf("unrendered palace.jsonc template is not valid JSONC: %v", err)
+	}
+}
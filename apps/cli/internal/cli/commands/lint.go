@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "lint",
+		Description: "Check code against the team's conventions",
+		Run:         RunLint,
+	})
+}
+
+// RunLint dispatches the lint subcommands.
+func RunLint(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace lint <command>
+
+Commands:
+  naming  Flag symbols whose names break the naming conventions
+
+Examples:
+  palace lint naming
+  palace lint naming --lang python --strict`)
+	}
+
+	switch args[0] {
+	case "naming":
+		return RunLintNaming(args[1:])
+	default:
+		return UsageError(fmt.Errorf("unknown lint command: %s\nRun 'palace help lint' for usage", args[0]))
+	}
+}
+
+// LintNamingOptions contains the configuration for the lint naming command.
+type LintNamingOptions struct {
+	Root     string
+	Language string // Only check files in this language
+}
+
+// NamingFinding is a symbol whose name breaks a naming convention.
+type NamingFinding struct {
+	File        string `json:"file"`
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Line        int    `json:"line"`
+}
+
+// RunLintNaming executes the lint naming command with parsed arguments.
+func RunLintNaming(args []string) error {
+	fs := flag.NewFlagSet("lint naming", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	lang := fs.String("lang", "", "only check files in this language (e.g. python)")
+	strict := fs.Bool("strict", false, "exit with code 3 if any name breaks a convention")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	findings, err := ExecuteLintNaming(LintNamingOptions{Root: *root, Language: *lang})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	} else if len(findings) == 0 {
+		fmt.Println("All names follow the naming conventions.")
+	} else {
+		for _, f := range findings {
+			fmt.Printf("%s:%d  %s\n", f.File, f.Line, f.Description)
+		}
+		fmt.Printf("\n%d violations\n", len(findings))
+	}
+	if *strict && len(findings) > 0 {
+		return findingsError("%d names break the naming conventions", len(findings))
+	}
+	return nil
+}
+
+// ExecuteLintNaming parses the source files of the workspace, as scan would
+// find them, and checks their symbols against the built-in naming
+// conventions as overridden by namingLint in palace.jsonc.
+func ExecuteLintNaming(opts LintNamingOptions) ([]NamingFinding, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	var naming *config.NamingLintConfig
+	if cfg, err := config.LoadPalaceConfig(rootPath); err == nil {
+		naming = cfg.NamingLint
+	}
+	rules, err := namingRulesFromConfig(naming)
+	if err != nil {
+		return nil, err
+	}
+	if err := scan.ApplyParserLimits(rootPath); err != nil {
+		return nil, err
+	}
+	files, err := fsutil.ListFiles(rootPath, config.LoadGuardrails(rootPath))
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+
+	findings := []NamingFinding{}
+	for _, rel := range files {
+		if !analysis.IsAnalyzable(rel) {
+			continue
+		}
+		if opts.Language != "" && string(analysis.DetectLanguage(rel)) != opts.Language {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(rootPath, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		fa, err := analysis.Analyze(content, rel)
+		if err != nil {
+			continue // Skipped, as scan skips files it cannot parse
+		}
+		for _, f := range analysis.FindNamingViolations(fa, rules) {
+			findings = append(findings, NamingFinding{File: rel, Rule: f.Name, Description: f.Signature, Line: f.LineStart})
+		}
+	}
+	return findings, nil
+}
+
+// namingRulesFromConfig compiles the namingLint rules set in palace.jsonc
+// over the built-in conventions.
+func namingRulesFromConfig(cfg *config.NamingLintConfig) ([]analysis.NamingRule, error) {
+	rules := analysis.DefaultNamingRules()
+	if cfg == nil {
+		return rules, nil
+	}
+	if cfg.NoDefaults {
+		rules = nil
+	}
+	var overrides []analysis.NamingRule
+	for i, r := range cfg.Rules {
+		if r.Language == "" || len(r.Kinds) == 0 {
+			return nil, fmt.Errorf("naming rule %d: language and kinds are required", i+1)
+		}
+		for _, kind := range r.Kinds {
+			if r.Style == "off" {
+				overrides = append(overrides, analysis.NamingRule{Language: analysis.Language(r.Language), Kind: analysis.SymbolKind(kind)})
+				continue
+			}
+			rule, err := analysis.NewNamingRule(analysis.Language(r.Language), analysis.SymbolKind(kind), r.Visibility, r.Style, r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("naming rule %d: %w", i+1, err)
+			}
+			overrides = append(overrides, rule)
+		}
+	}
+	return analysis.OverrideNamingRules(rules, overrides), nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/butler"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/util"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "lint-memory",
+		Description: "Check stored knowledge against the team's content conventions",
+		Run:         RunLintMemory,
+	})
+}
+
+// LintMemoryOptions contains the configuration for the lint-memory command.
+type LintMemoryOptions struct {
+	Root string
+	Kind string // Only check records of this kind
+}
+
+// RunLintMemory executes the lint-memory command with parsed arguments.
+func RunLintMemory(args []string) error {
+	fs := flag.NewFlagSet("lint-memory", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	kind := fs.String("kind", "", "only check records of this kind (idea, decision, learning)")
+	strict := fs.Bool("strict", false, "exit with code 3 if any record violates a rule")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	violations, err := ExecuteLintMemory(LintMemoryOptions{Root: *root, Kind: *kind})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(violations); err != nil {
+			return err
+		}
+	} else if len(violations) == 0 {
+		fmt.Println("All records follow the memory conventions.")
+	} else {
+		for _, v := range violations {
+			fmt.Printf("%s %s  [%s] %s\n", v.Kind, v.RecordID, v.Rule, strings.Join(v.Problems, "; "))
+			fmt.Printf("    %s\n", util.TruncateLine(v.Content, 100))
+		}
+		fmt.Printf("\n%d violations\n", len(violations))
+	}
+	if *strict && len(violations) > 0 {
+		return findingsError("%d records violate the memory conventions", len(violations))
+	}
+	return nil
+}
+
+// ExecuteLintMemory checks the stored records against the memoryLint rules
+// in palace.jsonc.
+func ExecuteLintMemory(opts LintMemoryOptions) ([]memory.LintViolation, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadPalaceConfig(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MemoryLint == nil || len(cfg.MemoryLint.Rules) == 0 {
+		return nil, errors.New("no memory conventions configured: add memoryLint.rules to palace.jsonc")
+	}
+	rules, err := butler.MemoryLintRules(cfg.MemoryLint)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Kind != "" {
+		kept := rules[:0]
+		for _, r := range rules {
+			if r.Kind == "" || r.Kind == opts.Kind {
+				r.Kind = opts.Kind
+				kept = append(kept, r)
+			}
+		}
+		rules = kept
+	}
+
+	mem, err := openMemory(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+	return mem.Lint(rules)
+}
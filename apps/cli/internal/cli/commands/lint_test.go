@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecuteLintNaming(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"views.py":  "def MyFunc():\n    pass\n\ndef my_func():\n    pass\n",
+		"limits.go": "package limits\n\nvar max_depth = 3\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	findings, err := ExecuteLintNaming(LintNamingOptions{Root: root, Language: "python"})
+	if err != nil {
+		t.Fatalf("ExecuteLintNaming() error: %v", err)
+	}
+	if len(findings) != 1 || findings[0].File != "views.py" || findings[0].Line != 1 || findings[0].Description != `function "MyFunc" is not snake_case` {
+		t.Errorf("expected only MyFunc flagged, got %+v", findings)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, ".palace"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"namingLint": {"rules": [{"language": "go", "kinds": ["variable"], "style": "off"}]}}`
+	if err := os.WriteFile(filepath.Join(root, ".palace", "palace.jsonc"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	findings, err = ExecuteLintNaming(LintNamingOptions{Root: root})
+	if err != nil {
+		t.Fatalf("ExecuteLintNaming() error: %v", err)
+	}
+	if len(findings) != 1 || findings[0].File != "views.py" {
+		t.Errorf("expected the Go variable rule to be off, got %+v", findings)
+	}
+}
+
+func TestExecuteLintNamingAppliesScanSettings(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".palace"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".palace", "palace.jsonc"), []byte(`{"scan": {"strictness": {"go": "bogus"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExecuteLintNaming(LintNamingOptions{Root: root}); err == nil {
+		t.Error("expected the invalid scan.strictness to be reported")
+	}
+}
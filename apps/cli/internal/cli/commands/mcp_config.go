@@ -130,7 +130,7 @@ func RunMCPConfig(args []string) error {
 	list := fs.Bool("list", false, "list all supported tools")
 
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	if *list {
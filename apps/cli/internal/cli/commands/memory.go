@@ -0,0 +1,1036 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/util"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "memory",
+		Description: "Inspect and maintain the knowledge store",
+		Run:         RunMemory,
+	})
+}
+
+// RunMemory dispatches to the appropriate memory subcommand.
+func RunMemory(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace memory <command>
+
+Commands:
+  log      Show the journal of memory mutations
+  undo     Revert the most recent mutations
+  compact  Drop old journal entries
+  review   List uncertain classifications, or confirm/correct one
+  promote  Move a record to another scope, keeping its ID and history
+  history  List the past versions of an edited record, or restore one
+  relink   Link related records that share an anchor, tags, or content
+  stats    Show counts by kind and scope, tag frequencies, and weekly growth
+  gc       Archive old, never-recalled, unlinked records per the forget policy
+  import   Copy the records of another workspace's store into this one
+  pinned   List pinned records in the order they were pinned
+  rooms    Map records onto the directory tree next to its code
+  bundle   Package the records with a tag, their links, and their code
+  bundle-open  Read a bundle made by 'palace memory bundle'
+
+Examples:
+  palace memory log --limit 50
+  palace memory undo
+  palace memory undo 3
+  palace memory compact --keep 100
+  palace memory review
+  palace memory review i_abc123 --as decision
+  palace memory promote lrn_abc123 --to palace
+  palace memory promote d_abc123 --to file --path auth/jwt.go
+  palace memory history d_abc123
+  palace memory history d_abc123 --restore 1
+  palace memory relink --dry-run
+  palace memory stats --tags
+  palace memory gc --dry-run
+  palace memory import ~/desktop/project --merge --dry-run
+  palace memory import --in brain.jsonl --stream --merge
+  palace memory pinned --scope room --path auth
+  palace memory rooms --dark
+  palace memory bundle --tag incident-123 --out bundle.json
+  palace memory bundle-open bundle.json`)
+	}
+
+	switch args[0] {
+	case "log":
+		return RunMemoryLog(args[1:])
+	case "undo":
+		return RunMemoryUndo(args[1:])
+	case "compact":
+		return RunMemoryCompact(args[1:])
+	case "review":
+		return RunMemoryReview(args[1:])
+	case "promote":
+		return RunMemoryPromote(args[1:])
+	case "history":
+		return RunMemoryHistory(args[1:])
+	case "relink":
+		return RunMemoryRelink(args[1:])
+	case "stats":
+		return RunMemoryStats(args[1:])
+	case "gc":
+		return RunMemoryGC(args[1:])
+	case "import":
+		return RunMemoryImport(args[1:])
+	case "pinned":
+		return RunMemoryPinned(args[1:])
+	case "rooms":
+		return RunMemoryRooms(args[1:])
+	case "bundle":
+		return RunMemoryBundle(args[1:])
+	case "bundle-open":
+		return RunMemoryBundleOpen(args[1:])
+	default:
+		return UsageError(fmt.Errorf("unknown memory command: %s\nRun 'palace help memory' for usage", args[0]))
+	}
+}
+
+// openMemory opens the workspace memory database.
+func openMemory(root string) (*memory.Memory, error) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	mem, err := memory.Open(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("open memory: %w", err)
+	}
+	return mem, nil
+}
+
+// MemoryLogOptions contains the configuration for memory log.
+type MemoryLogOptions struct {
+	Root  string
+	Limit int
+}
+
+// RunMemoryLog executes the memory log subcommand.
+func RunMemoryLog(args []string) error {
+	fs := flag.NewFlagSet("memory log", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	limit := fs.Int("limit", 20, "maximum number of entries to show (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	entries, err := ExecuteMemoryLog(MemoryLogOptions{Root: *root, Limit: *limit})
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("The memory journal is empty.")
+		return nil
+	}
+	for _, e := range entries {
+		status := ""
+		if e.Undone() {
+			status = "  (undone)"
+		}
+		fmt.Printf("#%-5d %s  %-7s %-9s %s%s\n", e.Seq, e.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			e.Op, e.RecordKind, e.RecordID, status)
+		if summary := journalSummary(e); summary != "" {
+			fmt.Printf("       %s\n", summary)
+		}
+	}
+	return nil
+}
+
+// ExecuteMemoryLog returns the most recent journal entries, newest first.
+func ExecuteMemoryLog(opts MemoryLogOptions) ([]memory.JournalEntry, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+	return mem.GetJournal(opts.Limit)
+}
+
+// MemoryUndoOptions contains the configuration for memory undo.
+type MemoryUndoOptions struct {
+	Root  string
+	Count int
+}
+
+// RunMemoryUndo executes the memory undo subcommand.
+func RunMemoryUndo(args []string) error {
+	fs := flag.NewFlagSet("memory undo", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	count := 1
+	if fs.NArg() > 0 {
+		n, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid count %q: must be a positive number", fs.Arg(0))
+		}
+		count = n
+	}
+
+	undone, err := ExecuteMemoryUndo(MemoryUndoOptions{Root: *root, Count: count})
+	for _, e := range undone {
+		fmt.Printf("Undid #%d: %s %s %s\n", e.Seq, e.Op, e.RecordKind, e.RecordID)
+	}
+	if err != nil {
+		return err
+	}
+	if len(undone) == 0 {
+		fmt.Println("Nothing to undo.")
+	}
+	return nil
+}
+
+// ExecuteMemoryUndo reverts the most recent mutations and returns them.
+func ExecuteMemoryUndo(opts MemoryUndoOptions) ([]memory.JournalEntry, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+	return mem.Undo(opts.Count)
+}
+
+// MemoryCompactOptions contains the configuration for memory compact.
+type MemoryCompactOptions struct {
+	Root string
+	Keep int
+}
+
+// RunMemoryCompact executes the memory compact subcommand.
+func RunMemoryCompact(args []string) error {
+	fs := flag.NewFlagSet("memory compact", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	keep := fs.Int("keep", 100, "number of most recent journal entries to keep")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	removed, err := ExecuteMemoryCompact(MemoryCompactOptions{Root: *root, Keep: *keep})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d journal entries (kept the newest %d).\n", removed, *keep)
+	return nil
+}
+
+// ExecuteMemoryCompact drops all but the newest journal entries.
+func ExecuteMemoryCompact(opts MemoryCompactOptions) (int64, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return 0, err
+	}
+	defer mem.Close()
+	return mem.CompactJournal(opts.Keep)
+}
+
+// MemoryReviewOptions contains the configuration for memory review.
+type MemoryReviewOptions struct {
+	Root  string
+	ID    string // Record to resolve; empty lists the queue
+	As    string // Confirmed or corrected kind for ID
+	Limit int
+}
+
+// MemoryReviewResult is the outcome of memory review: the queue when
+// listing, or the record's (possibly new) ID when resolving.
+type MemoryReviewResult struct {
+	Queue []memory.ReviewItem
+	ID    string
+}
+
+// RunMemoryReview executes the memory review subcommand.
+func RunMemoryReview(args []string) error {
+	fs := flag.NewFlagSet("memory review", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	as := fs.String("as", "", "confirm or correct the record's kind: idea, decision, or learning")
+	limit := fs.Int("limit", 20, "maximum number of queued records to show (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	var id string
+	if fs.NArg() > 0 {
+		// Allow flags after the record ID as well as before it
+		id = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return UsageError(err)
+		}
+	}
+	opts := MemoryReviewOptions{Root: *root, ID: id, As: *as, Limit: *limit}
+	if opts.ID != "" {
+		if opts.As == "" {
+			return errors.New("--as is required when reviewing a record")
+		}
+	}
+
+	result, err := ExecuteMemoryReview(opts)
+	if err != nil {
+		return err
+	}
+	if opts.ID != "" {
+		if result.ID == opts.ID {
+			fmt.Printf("Confirmed %s as %s.\n", opts.ID, opts.As)
+		} else {
+			fmt.Printf("Re-stored %s as %s %s.\n", opts.ID, opts.As, result.ID)
+		}
+		return nil
+	}
+	if len(result.Queue) == 0 {
+		fmt.Println("No records need review.")
+		return nil
+	}
+	for _, item := range result.Queue {
+		fmt.Printf("%-22s %-9s %3.0f%%  %s\n", item.RecordID, item.RecordKind, item.Confidence*100,
+			util.TruncateLine(item.Content, 60))
+	}
+	fmt.Println("\nRun 'palace memory review <id> --as <kind>' to confirm or correct a classification.")
+	return nil
+}
+
+// ExecuteMemoryReview lists the review queue, or resolves opts.ID as opts.As.
+func ExecuteMemoryReview(opts MemoryReviewOptions) (*MemoryReviewResult, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+
+	if opts.ID != "" {
+		id, err := mem.ResolveReview(opts.ID, memory.RecordKind(opts.As))
+		if err != nil {
+			return nil, err
+		}
+		return &MemoryReviewResult{ID: id}, nil
+	}
+	queue, err := mem.GetReviewQueue(opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryReviewResult{Queue: queue}, nil
+}
+
+// MemoryPromoteOptions contains the configuration for memory promote.
+type MemoryPromoteOptions struct {
+	Root string
+	ID   string
+	To   string
+	Path string
+}
+
+// RunMemoryPromote executes the memory promote subcommand.
+func RunMemoryPromote(args []string) error {
+	fs := flag.NewFlagSet("memory promote", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	to := fs.String("to", "", "target scope: palace, room, or file")
+	path := fs.String("path", "", "room name or file path for room and file scopes")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace memory promote <id> --to <palace|room|file> [--path <path>]"))
+	}
+	// Allow flags after the record ID as well as before it
+	id := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return UsageError(err)
+	}
+	if *to == "" {
+		return errors.New("--to is required")
+	}
+
+	kind, err := ExecuteMemoryPromote(MemoryPromoteOptions{Root: *root, ID: id, To: *to, Path: *path})
+	if err != nil {
+		return err
+	}
+	target := *to
+	if *path != "" {
+		target += ":" + *path
+	}
+	fmt.Printf("Moved %s %s to %s scope.\n", kind, id, target)
+	return nil
+}
+
+// ExecuteMemoryPromote moves a record to another scope and returns its kind.
+func ExecuteMemoryPromote(opts MemoryPromoteOptions) (string, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return "", err
+	}
+	defer mem.Close()
+	return mem.ChangeScope(opts.ID, memory.Scope(opts.To), opts.Path)
+}
+
+// MemoryHistoryOptions contains the configuration for memory history.
+type MemoryHistoryOptions struct {
+	Root    string
+	ID      string
+	Restore int // Version to make current again; 0 only lists
+}
+
+// MemoryHistoryResult is a record's current state and its past versions.
+type MemoryHistoryResult struct {
+	ID       string                 `json:"id"`
+	Kind     string                 `json:"kind"`
+	Content  string                 `json:"content"`
+	Tags     []string               `json:"tags,omitempty"`
+	Versions []memory.RecordVersion `json:"versions"`           // Newest first
+	Restored int                    `json:"restored,omitempty"` // The version restored, if any
+}
+
+// RunMemoryHistory executes the memory history subcommand.
+func RunMemoryHistory(args []string) error {
+	fs := flag.NewFlagSet("memory history", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	restore := fs.Int("restore", 0, "make this past version current again")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace memory history <id> [--restore <version>]"))
+	}
+	// Allow flags after the record ID as well as before it
+	id := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return UsageError(err)
+	}
+	if *restore < 0 {
+		return fmt.Errorf("invalid version %d: must be a positive number", *restore)
+	}
+
+	result, err := ExecuteMemoryHistory(MemoryHistoryOptions{Root: *root, ID: id, Restore: *restore})
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	if result.Restored > 0 {
+		if result.ID != id {
+			fmt.Printf("Restored version %d of %s as %s %s.\n\n", result.Restored, id, result.Kind, result.ID)
+		} else {
+			fmt.Printf("Restored version %d of %s %s.\n\n", result.Restored, result.Kind, result.ID)
+		}
+	}
+	fmt.Printf("%s %s\n\n", result.Kind, result.ID)
+	printVersion := func(label string, content string, tags []string) {
+		fmt.Println(label)
+		for _, line := range strings.Split(content, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+		if len(tags) > 0 {
+			fmt.Printf("    [tags: %s]\n", strings.Join(tags, ", "))
+		}
+	}
+	printVersion("current", result.Content, result.Tags)
+	if len(result.Versions) == 0 {
+		fmt.Println("\nNo past versions; the record has not been edited.")
+		return nil
+	}
+	for _, v := range result.Versions {
+		fmt.Println()
+		printVersion(fmt.Sprintf("v%d  %s, replaced %s", v.Version, v.Kind, v.ReplacedAt.Local().Format("2006-01-02 15:04")), v.Content, v.Tags)
+	}
+	return nil
+}
+
+// ExecuteMemoryHistory returns a record's past versions, after making the
+// version opts.Restore current again when it is set.
+func ExecuteMemoryHistory(opts MemoryHistoryOptions) (*MemoryHistoryResult, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+
+	id := opts.ID
+	if opts.Restore > 0 {
+		if id, err = mem.RestoreVersion(id, opts.Restore); err != nil {
+			return nil, err
+		}
+	}
+	kind, versions, err := mem.RecordHistory(id)
+	if err != nil {
+		return nil, err
+	}
+	content, _, err := mem.GetRecordContent(id, kind)
+	if err != nil {
+		return nil, fmt.Errorf("get record: %w", err)
+	}
+	tags, err := mem.GetTags(id, kind)
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil {
+		versions = []memory.RecordVersion{}
+	}
+	return &MemoryHistoryResult{ID: id, Kind: kind, Content: content, Tags: tags, Versions: versions, Restored: opts.Restore}, nil
+}
+
+// MemoryRelinkOptions contains the configuration for memory relink.
+type MemoryRelinkOptions struct {
+	Root   string
+	DryRun bool
+	// Thresholds; zero values use palace.jsonc's "relink" settings, then the
+	// memory package defaults.
+	MinSharedTags int
+	MinTagOverlap float64
+	MinSimilarity float64
+}
+
+// RunMemoryRelink executes the memory relink subcommand.
+func RunMemoryRelink(args []string) error {
+	fs := flag.NewFlagSet("memory relink", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "list proposed links without creating them")
+	minShared := fs.Int("min-shared-tags", 0, "tags two records must share (default 2)")
+	minOverlap := fs.Float64("min-tag-overlap", 0, "Jaccard overlap of tag sets, 0-1 (default 0.5)")
+	minSimilarity := fs.Float64("min-similarity", 0, "Jaccard overlap of content words, 0-1 (default 0.6)")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	proposals, created, err := ExecuteMemoryRelink(MemoryRelinkOptions{
+		Root:          *root,
+		DryRun:        *dryRun,
+		MinSharedTags: *minShared,
+		MinTagOverlap: *minOverlap,
+		MinSimilarity: *minSimilarity,
+	})
+	if err != nil {
+		return err
+	}
+	if len(proposals) == 0 {
+		fmt.Println("No new relations found.")
+		return nil
+	}
+	for _, p := range proposals {
+		fmt.Printf("%s --related--> %s  (%s: %s)\n", p.SourceID, p.TargetID, p.Reason, p.Detail)
+	}
+	if *dryRun {
+		fmt.Printf("\n%d links proposed. Run without --dry-run to create them.\n", len(proposals))
+	} else {
+		fmt.Printf("\nCreated %d links. Undo with 'palace memory undo %d'.\n", created, created)
+	}
+	return nil
+}
+
+// ExecuteMemoryRelink proposes "related" links and, unless DryRun is set,
+// creates them. It returns the proposals and how many links were created.
+func ExecuteMemoryRelink(opts MemoryRelinkOptions) ([]memory.RelinkProposal, int, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, 0, err
+	}
+	relinkOpts := memory.RelinkOptions{
+		MinSharedTags: opts.MinSharedTags,
+		MinTagOverlap: opts.MinTagOverlap,
+		MinSimilarity: opts.MinSimilarity,
+	}
+	if cfg, err := config.LoadPalaceConfig(rootPath); err == nil && cfg.Relink != nil {
+		if relinkOpts.MinSharedTags == 0 {
+			relinkOpts.MinSharedTags = cfg.Relink.MinSharedTags
+		}
+		if relinkOpts.MinTagOverlap == 0 {
+			relinkOpts.MinTagOverlap = cfg.Relink.MinTagOverlap
+		}
+		if relinkOpts.MinSimilarity == 0 {
+			relinkOpts.MinSimilarity = cfg.Relink.MinSimilarity
+		}
+	}
+
+	mem, err := openMemory(rootPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer mem.Close()
+
+	proposals, err := mem.ProposeRelinks(relinkOpts)
+	if err != nil || opts.DryRun {
+		return proposals, 0, err
+	}
+	created, err := mem.ApplyRelinks(proposals)
+	return proposals, created, err
+}
+
+// MemoryGCOptions contains the configuration for memory gc. Zero values
+// use the forget policy in palace.jsonc, then the memory package defaults.
+type MemoryGCOptions struct {
+	Root           string
+	DryRun         bool
+	MinAgeDays     int
+	MaxAccessCount int
+	MaxConfidence  float64
+}
+
+// RunMemoryGC executes the memory gc subcommand.
+func RunMemoryGC(args []string) error {
+	fs := flag.NewFlagSet("memory gc", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "list the records that would be archived without archiving them")
+	minAge := fs.Int("min-age", 0, "days since a record was created and last recalled (default 90)")
+	maxAccess := fs.Int("max-access", 0, "recalls a record may have had (default 0)")
+	maxConfidence := fs.Float64("max-confidence", 0, "confidence a learning may have, 0-1 (default 0.3)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	candidates, err := ExecuteMemoryGC(MemoryGCOptions{
+		Root:           *root,
+		DryRun:         *dryRun,
+		MinAgeDays:     *minAge,
+		MaxAccessCount: *maxAccess,
+		MaxConfidence:  *maxConfidence,
+	})
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		if candidates == nil {
+			candidates = []memory.ForgetCandidate{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(candidates)
+	}
+	if len(candidates) == 0 {
+		fmt.Println("Nothing to archive.")
+		return nil
+	}
+	for _, c := range candidates {
+		detail := fmt.Sprintf("created %s, recalled %d times", c.CreatedAt.Local().Format("2006-01-02"), c.AccessCount)
+		if c.Kind == "learning" {
+			detail += fmt.Sprintf(", %.0f%% confidence", c.Confidence*100)
+		}
+		fmt.Printf("%-9s %s  %s\n          (%s)\n", c.Kind, c.ID, util.TruncateLine(c.Content, 60), detail)
+	}
+	if *dryRun {
+		fmt.Printf("\n%d records would be archived. Run without --dry-run to archive them.\n", len(candidates))
+	} else {
+		fmt.Printf("\nArchived %d records.\n", len(candidates))
+	}
+	return nil
+}
+
+// forgetPolicyFromConfig returns the default forget policy with the values
+// cfg sets applied; cfg may be nil.
+func forgetPolicyFromConfig(cfg *config.ForgetConfig) memory.ForgetPolicy {
+	p := memory.DefaultForgetPolicy()
+	if cfg == nil {
+		return p
+	}
+	if cfg.MinAgeDays > 0 {
+		p.MinAge = time.Duration(cfg.MinAgeDays) * 24 * time.Hour
+	}
+	if cfg.MaxAccessCount > 0 {
+		p.MaxAccessCount = cfg.MaxAccessCount
+	}
+	if cfg.MaxConfidence > 0 {
+		p.MaxConfidence = cfg.MaxConfidence
+	}
+	p.ExcludeKinds = cfg.ExcludeKinds
+	return p
+}
+
+// ExecuteMemoryGC lists the records the forget policy selects and, unless
+// DryRun is set, archives them. Decisions and records tagged "pinned" are
+// never selected.
+func ExecuteMemoryGC(opts MemoryGCOptions) ([]memory.ForgetCandidate, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	var forgetCfg *config.ForgetConfig
+	if cfg, err := config.LoadPalaceConfig(rootPath); err == nil {
+		forgetCfg = cfg.Forget
+	}
+	policy := forgetPolicyFromConfig(forgetCfg)
+	if opts.MinAgeDays > 0 {
+		policy.MinAge = time.Duration(opts.MinAgeDays) * 24 * time.Hour
+	}
+	if opts.MaxAccessCount > 0 {
+		policy.MaxAccessCount = opts.MaxAccessCount
+	}
+	if opts.MaxConfidence > 0 {
+		policy.MaxConfidence = opts.MaxConfidence
+	}
+
+	mem, err := openMemory(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+
+	if opts.DryRun {
+		return mem.ForgetCandidates(policy)
+	}
+	return mem.ApplyForgetPolicy(policy)
+}
+
+// MemoryStatsOptions contains the configuration for memory stats.
+type MemoryStatsOptions struct {
+	Root string
+}
+
+// memoryStatsTopTags is how many tags memory stats lists without --tags.
+const memoryStatsTopTags = 10
+
+// RunMemoryStats executes the memory stats subcommand.
+func RunMemoryStats(args []string) error {
+	fs := flag.NewFlagSet("memory stats", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	allTags := fs.Bool("tags", false, "list every tag in the histogram, not just the most used")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	stats, err := ExecuteMemoryStats(MemoryStatsOptions{Root: *root})
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	fmt.Printf("Records: %d  (average %.0f characters)\n", stats.Total, stats.AvgContentLength)
+	fmt.Println("\nBy kind:")
+	for _, kind := range []string{"decision", "idea", "learning"} {
+		fmt.Printf("  %-10s %d\n", kind, stats.ByKind[kind])
+	}
+	fmt.Println("\nBy scope:")
+	for _, scope := range sortedKeys(stats.ByScope) {
+		fmt.Printf("  %-10s %d\n", scope, stats.ByScope[scope])
+	}
+
+	fmt.Println("\nTags:")
+	tags := stats.Tags
+	if !*allTags && len(tags) > memoryStatsTopTags {
+		tags = tags[:memoryStatsTopTags]
+	}
+	if len(tags) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, tc := range tags {
+		fmt.Printf("  %-20s %4d  %s\n", tc.Tag, tc.Count, histogramBar(tc.Count, stats.Tags[0].Count))
+	}
+	if len(tags) < len(stats.Tags) {
+		fmt.Printf("  ... %d more (use --tags to list all)\n", len(stats.Tags)-len(tags))
+	}
+
+	fmt.Println("\nRecords per week:")
+	if len(stats.Weekly) == 0 {
+		fmt.Println("  (none)")
+	}
+	peak := 0
+	for _, w := range stats.Weekly {
+		peak = max(peak, w.Count)
+	}
+	for _, w := range stats.Weekly {
+		fmt.Printf("  %s  %4d  %s\n", w.Week, w.Count, histogramBar(w.Count, peak))
+	}
+	return nil
+}
+
+// ExecuteMemoryStats aggregates the knowledge store.
+func ExecuteMemoryStats(opts MemoryStatsOptions) (*memory.Stats, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+	return mem.Stats()
+}
+
+// histogramBar draws n against peak as a bar of up to 30 blocks.
+func histogramBar(n, peak int) string {
+	if n <= 0 || peak <= 0 {
+		return ""
+	}
+	return strings.Repeat("█", max(1, n*30/peak))
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// journalSummary returns a short description of the record a journal entry touched.
+func journalSummary(e memory.JournalEntry) string {
+	data := e.After
+	if data == "" {
+		data = e.Before
+	}
+	var snap struct {
+		Record struct {
+			Content  string `json:"content"`
+			SourceID string `json:"sourceId"`
+			TargetID string `json:"targetId"`
+			Relation string `json:"relation"`
+		} `json:"record"`
+	}
+	if data == "" || json.Unmarshal([]byte(data), &snap) != nil {
+		return ""
+	}
+	if snap.Record.Relation != "" {
+		return fmt.Sprintf("%s --%s--> %s", snap.Record.SourceID, snap.Record.Relation, snap.Record.TargetID)
+	}
+	return util.TruncateLine(snap.Record.Content, 80)
+}
+
+// MemoryImportOptions contains the configuration for memory import.
+type MemoryImportOptions struct {
+	Root   string
+	Source string // Workspace root whose store is imported
+	Merge  bool
+	DryRun bool
+}
+
+// RunMemoryImport executes the memory import subcommand.
+func RunMemoryImport(args []string) error {
+	fs := flag.NewFlagSet("memory import", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	merge := fs.Bool("merge", false, "skip records whose content this store already holds under another ID")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without importing it")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	in := fs.String("in", "", "JSON-lines file to import instead of a workspace (with --stream)")
+	stream := fs.Bool("stream", false, "read --in one record at a time")
+	replace := fs.Bool("replace", false, "with --in, overwrite records held under the same ID")
+	strict := fs.Bool("strict", false, "with --in, stop at the first malformed line")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	// Allow flags after the workspace as well as before it
+	source := fs.Arg(0)
+	if fs.NArg() > 0 {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return UsageError(err)
+		}
+	}
+
+	if *in != "" {
+		if !*stream {
+			return UsageError(errors.New("--in reads a JSON-lines file; pass --stream"))
+		}
+		if source != "" {
+			return UsageError(errors.New("import either a workspace or --in, not both"))
+		}
+		return runMemoryImportStream(MemoryImportStreamOptions{
+			Root: *root, In: *in, Merge: *merge, Replace: *replace, Strict: *strict, DryRun: *dryRun, Progress: os.Stderr,
+		}, *jsonOut)
+	}
+	if source == "" || *stream || *replace || *strict {
+		return UsageError(errors.New("usage: palace memory import <workspace> [--merge] [--dry-run] [--json]\n       palace memory import --in <file.jsonl> --stream [--merge|--replace] [--strict] [--dry-run] [--json]"))
+	}
+
+	report, err := ExecuteMemoryImport(MemoryImportOptions{Root: *root, Source: source, Merge: *merge, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d records; %d already present.\n", verb, len(report.Imported), len(report.Duplicates))
+	if len(report.Conflicts) > 0 {
+		fmt.Printf("\n%d records differ between the stores and were left alone:\n", len(report.Conflicts))
+		for _, c := range report.Conflicts {
+			fmt.Printf("\n%-9s %s\n  here:     %s\n  incoming: %s\n", c.Kind, c.ID, util.TruncateLine(c.Local, 60), util.TruncateLine(c.Incoming, 60))
+		}
+		fmt.Println("\nResolve them by editing the record in one store to match the other, then import again.")
+	}
+	return nil
+}
+
+// ExecuteMemoryImport copies the records of the store at opts.Source that
+// the workspace's store lacks into it. With Merge, records whose content
+// hash matches one already held are skipped even under a different ID.
+func ExecuteMemoryImport(opts MemoryImportOptions) (*memory.MergeReport, error) {
+	sourcePath, err := filepath.Abs(opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(config.MemoryDBPath(sourcePath)); err != nil {
+		return nil, fmt.Errorf("no memory store in %s: %w", opts.Source, err)
+	}
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if rootPath == sourcePath {
+		return nil, errors.New("cannot import a store into itself")
+	}
+
+	src, err := openMemory(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	mem, err := openMemory(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+
+	return mem.MergeFrom(src, memory.MergeOptions{ByContent: opts.Merge, DryRun: opts.DryRun})
+}
+
+// MemoryImportStreamOptions contains the configuration for memory import
+// from a JSON-lines file.
+type MemoryImportStreamOptions struct {
+	Root     string
+	In       string // JSON-lines file, one record per line
+	Merge    bool
+	Replace  bool
+	Strict   bool
+	DryRun   bool
+	Progress io.Writer // Receives a progress line every importProgressEvery lines; nil for none
+}
+
+// importProgressEvery is how many lines pass between progress reports.
+const importProgressEvery = 1000
+
+func runMemoryImportStream(opts MemoryImportStreamOptions, jsonOut bool) error {
+	report, err := ExecuteMemoryImportStream(opts)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	verb := "Imported"
+	if opts.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d records from %d lines; %d replaced, %d already present.\n", verb, report.Imported, report.Lines, report.Replaced, report.Duplicates)
+	if len(report.Malformed) > 0 {
+		fmt.Printf("\nSkipped %d malformed lines:\n", len(report.Malformed))
+		for _, bad := range report.Malformed {
+			fmt.Printf("  line %d: %s\n", bad.Line, bad.Error)
+		}
+	}
+	if len(report.Conflicts) > 0 {
+		fmt.Printf("\n%d records differ from the ones held under their IDs and were left alone (use --replace to overwrite):\n", len(report.Conflicts))
+		for _, c := range report.Conflicts {
+			fmt.Printf("\n%-9s %s\n  here:     %s\n  incoming: %s\n", c.Kind, c.ID, util.TruncateLine(c.Local, 60), util.TruncateLine(c.Incoming, 60))
+		}
+	}
+	return nil
+}
+
+// ExecuteMemoryImportStream imports the records of a JSON-lines file into
+// the workspace's store one line at a time, so files of any size import in
+// bounded memory. Malformed lines are skipped and reported unless Strict.
+func ExecuteMemoryImportStream(opts MemoryImportStreamOptions) (*memory.StreamImportReport, error) {
+	f, err := os.Open(opts.In)
+	if err != nil {
+		return nil, fmt.Errorf("open import file: %w", err)
+	}
+	defer f.Close()
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+
+	importOpts := memory.StreamImportOptions{ByContent: opts.Merge, Replace: opts.Replace, DryRun: opts.DryRun, Strict: opts.Strict}
+	if opts.Progress != nil {
+		importOpts.Progress = func(r *memory.StreamImportReport) {
+			if r.Lines%importProgressEvery == 0 {
+				fmt.Fprintf(opts.Progress, "%d lines read, %d imported, %d malformed\n", r.Lines, r.Imported+r.Replaced, len(r.Malformed))
+			}
+		}
+	}
+	return mem.ImportStream(f, importOpts)
+}
+
+// MemoryPinnedOptions contains the configuration for memory pinned.
+type MemoryPinnedOptions struct {
+	Root   string
+	Filter memory.PinFilter
+}
+
+// RunMemoryPinned executes the memory pinned subcommand.
+func RunMemoryPinned(args []string) error {
+	fs := flag.NewFlagSet("memory pinned", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	scope := fs.String("scope", "", "only records in this scope (palace, room, file)")
+	scopePath := fs.String("path", "", "only records with this room name or file path")
+	query := fs.String("query", "", "only records matching this query")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	pinned, err := ExecuteMemoryPinned(MemoryPinnedOptions{
+		Root:   *root,
+		Filter: memory.PinFilter{Scope: *scope, ScopePath: *scopePath, Query: *query},
+	})
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pinned)
+	}
+	if len(pinned) == 0 {
+		fmt.Println("Nothing pinned. Pin a record by tagging it \"pinned\".")
+		return nil
+	}
+	for _, p := range pinned {
+		scopeInfo := p.Scope
+		if p.ScopePath != "" {
+			scopeInfo += ":" + p.ScopePath
+		}
+		fmt.Printf("%-9s %-14s %-20s %s\n", p.Kind, p.ID, scopeInfo, p.Content)
+	}
+	return nil
+}
+
+// ExecuteMemoryPinned lists the pinned records, oldest pin first.
+func ExecuteMemoryPinned(opts MemoryPinnedOptions) ([]memory.PinnedRecord, error) {
+	mem, err := openMemory(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+	return mem.Pinned(opts.Filter)
+}
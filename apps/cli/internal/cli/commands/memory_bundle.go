@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// maxBundleExcerptLines caps the lines of code a bundle keeps per anchor.
+const maxBundleExcerptLines = 80
+
+// MemoryBundleOptions contains the configuration for memory bundle.
+type MemoryBundleOptions struct {
+	Root string
+	Tag  string
+}
+
+// RunMemoryBundle executes the memory bundle subcommand.
+func RunMemoryBundle(args []string) error {
+	fs := flag.NewFlagSet("memory bundle", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	tag := fs.String("tag", "", "bundle the records carrying this tag (required)")
+	out := fs.String("out", "", "write the bundle to this file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if *tag == "" {
+		return UsageError(errors.New("--tag is required"))
+	}
+
+	b, err := ExecuteMemoryBundle(MemoryBundleOptions{Root: *root, Tag: *tag})
+	if err != nil {
+		return err
+	}
+	if b.Records() == 0 {
+		return fmt.Errorf("no records tagged %q", b.Tag)
+	}
+	if *out == "" {
+		return memory.WriteBundle(os.Stdout, b)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := memory.WriteBundle(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("bundled %d records, %d links, and %d code anchors tagged %q into %s\n",
+		b.Records(), len(b.Links), len(b.Code), b.Tag, *out)
+	return nil
+}
+
+// ExecuteMemoryBundle collects the records tagged opts.Tag into a bundle
+// with the current code behind their anchors. Symbols are read from the
+// index when there is one.
+func ExecuteMemoryBundle(opts MemoryBundleOptions) (*memory.Bundle, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	mem, err := openMemory(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer mem.Close()
+	b, err := mem.Bundle(opts.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var db *sql.DB
+	if _, err := os.Stat(config.IndexDBPath(rootPath)); err == nil {
+		if db, err = index.Open(config.IndexDBPath(rootPath)); err == nil {
+			defer db.Close()
+		}
+	}
+	for _, anchor := range b.Anchors() {
+		code, err := bundleCode(rootPath, db, anchor)
+		if err != nil {
+			return nil, err
+		}
+		b.Code = append(b.Code, code)
+	}
+	return b, nil
+}
+
+// bundleCode snapshots the code behind one anchor: the lines of a ranged
+// anchor, and the symbols defined there (or at the top level of the file,
+// for a whole-file anchor) when db is not nil.
+func bundleCode(rootPath string, db *sql.DB, anchor string) (memory.BundleCode, error) {
+	target, err := memory.ParseCodeTarget(anchor)
+	if err != nil {
+		return memory.BundleCode{}, err
+	}
+	code := memory.BundleCode{Anchor: anchor, File: target.FilePath, StartLine: target.StartLine, EndLine: target.EndLine}
+	data, err := os.ReadFile(filepath.Join(rootPath, filepath.FromSlash(target.FilePath)))
+	if err != nil {
+		code.Missing = true
+		return code, nil
+	}
+	if target.StartLine > 0 {
+		lines := strings.Split(string(bytes.TrimRight(data, "\n")), "\n")
+		end := min(target.EndLine, len(lines), target.StartLine+maxBundleExcerptLines-1)
+		if target.StartLine <= end {
+			code.Excerpt = strings.Join(lines[target.StartLine-1:end], "\n")
+		}
+	}
+	if db != nil {
+		symbols, err := index.GetSymbolsInRange(db, target.FilePath, target.StartLine, target.EndLine)
+		if err != nil {
+			return memory.BundleCode{}, fmt.Errorf("read symbols of %s: %w", target.FilePath, err)
+		}
+		for _, s := range symbols {
+			code.Symbols = append(code.Symbols, memory.BundleSymbol{Name: s.Name, Kind: s.Kind, Line: s.LineStart, Signature: s.Signature})
+		}
+	}
+	return code, nil
+}
+
+// RunMemoryBundleOpen executes the memory bundle-open subcommand.
+func RunMemoryBundleOpen(args []string) error {
+	fs := flag.NewFlagSet("memory bundle-open", flag.ContinueOnError)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() != 1 {
+		return UsageError(errors.New("usage: palace memory bundle-open <bundle.json>"))
+	}
+
+	b, err := ExecuteMemoryBundleOpen(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		return memory.WriteBundle(os.Stdout, b)
+	}
+	printBundle(os.Stdout, b)
+	return nil
+}
+
+// ExecuteMemoryBundleOpen reads the bundle at path.
+func ExecuteMemoryBundleOpen(path string) (*memory.Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return memory.ReadBundle(f)
+}
+
+// printBundle prints a bundle for reading: its records, their links, and
+// the code they are anchored to.
+func printBundle(w io.Writer, b *memory.Bundle) {
+	fmt.Fprintf(w, "Bundle %q, made %s: %d records, %d links, %d code anchors\n",
+		b.Tag, b.CreatedAt.Format("2006-01-02 15:04"), b.Records(), len(b.Links), len(b.Code))
+
+	record := func(kind, id, content string) {
+		fmt.Fprintf(w, "  %-9s %-14s %s\n", kind, id, content)
+		if tags := b.Tags[id]; len(tags) > 0 {
+			fmt.Fprintf(w, "  %-9s %-14s tags: %s\n", "", "", strings.Join(tags, ", "))
+		}
+	}
+	fmt.Fprintln(w, "\nRecords:")
+	for _, d := range b.Decisions {
+		record("decision", d.ID, d.Content)
+	}
+	for _, l := range b.Learnings {
+		record("learning", l.ID, l.Content)
+	}
+	for _, i := range b.Ideas {
+		record("idea", i.ID, i.Content)
+	}
+
+	if len(b.Links) > 0 {
+		fmt.Fprintln(w, "\nLinks:")
+		for _, l := range b.Links {
+			stale := ""
+			if l.IsStale {
+				stale = " (stale)"
+			}
+			fmt.Fprintf(w, "  %s --%s--> %s%s\n", l.SourceID, l.Relation, l.TargetID, stale)
+		}
+	}
+
+	for _, c := range b.Code {
+		fmt.Fprintf(w, "\nCode %s", c.Anchor)
+		if c.Missing {
+			fmt.Fprintln(w, " (file missing when bundled)")
+			continue
+		}
+		fmt.Fprintln(w)
+		for _, s := range c.Symbols {
+			sig := s.Signature
+			if sig == "" {
+				sig = s.Name
+			}
+			fmt.Fprintf(w, "  %s:%d  %s %s\n", c.File, s.Line, s.Kind, sig)
+		}
+		if c.Excerpt != "" {
+			for i, line := range strings.Split(c.Excerpt, "\n") {
+				fmt.Fprintf(w, "  %5d | %s\n", c.StartLine+i, line)
+			}
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/jsonc"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/model"
+)
+
+// MemoryRoomsOptions contains the configuration for memory rooms.
+type MemoryRoomsOptions struct {
+	Root  string
+	Depth int // Directory levels an area spans (default 2)
+}
+
+// RoomArea is one directory of the repository with its code and the
+// records about it.
+type RoomArea struct {
+	Path    string         `json:"path"` // "." for files at the root
+	Files   int            `json:"files"`
+	Symbols int            `json:"symbols"`
+	Records map[string]int `json:"records"` // By kind
+	Total   int            `json:"total"`
+	Rooms   []string       `json:"rooms,omitempty"` // Rooms placed here
+}
+
+// Dark reports whether an area has code but no records.
+func (a RoomArea) Dark() bool {
+	return a.Total == 0 && a.Files > 0
+}
+
+// RoomMap is where the records of a workspace sit in its directory tree.
+type RoomMap struct {
+	Areas    []RoomArea          `json:"areas"` // Ordered by path
+	Rooms    []memory.ScopeCount `json:"rooms"` // Room-scoped records by room
+	Unplaced []string            `json:"unplaced,omitempty"`
+	Palace   int                 `json:"palace"`  // Palace-scoped records
+	Indexed  bool                `json:"indexed"` // False when no index was found
+}
+
+// defaultRoomMapDepth is how many directory levels an area spans by default.
+const defaultRoomMapDepth = 2
+
+// RunMemoryRooms executes the memory rooms subcommand.
+func RunMemoryRooms(args []string) error {
+	fs := flag.NewFlagSet("memory rooms", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	depth := fs.Int("depth", defaultRoomMapDepth, "directory levels each area spans")
+	darkOnly := fs.Bool("dark", false, "only list areas with code but no records")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	m, err := ExecuteMemoryRooms(MemoryRoomsOptions{Root: *root, Depth: *depth})
+	if err != nil {
+		return err
+	}
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	}
+
+	if !m.Indexed {
+		fmt.Println("No index found; run 'palace scan' to see files, symbols, and dark areas.")
+		fmt.Println()
+	}
+	fmt.Printf("%-32s %6s %8s %8s  %-9s %s\n", "AREA", "FILES", "SYMBOLS", "RECORDS", "D/I/L", "ROOMS")
+	dark := 0
+	for _, a := range m.Areas {
+		if a.Dark() {
+			dark++
+		} else if *darkOnly {
+			continue
+		}
+		kinds := fmt.Sprintf("%d/%d/%d", a.Records["decision"], a.Records["idea"], a.Records["learning"])
+		if a.Dark() {
+			kinds = "dark"
+		}
+		fmt.Printf("%-32s %6d %8d %8d  %-9s %s\n", a.Path, a.Files, a.Symbols, a.Total, kinds, strings.Join(a.Rooms, ", "))
+	}
+	fmt.Printf("\n%d areas, %d dark; %d palace-wide records\n", len(m.Areas), dark, m.Palace)
+	if len(m.Unplaced) > 0 {
+		fmt.Printf("Rooms matching no directory: %s\n", strings.Join(m.Unplaced, ", "))
+	}
+	return nil
+}
+
+// ExecuteMemoryRooms maps the records of the workspace onto its directory
+// tree, next to the file and symbol counts of the code index.
+func ExecuteMemoryRooms(opts MemoryRoomsOptions) (*RoomMap, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	depth := opts.Depth
+	if depth <= 0 {
+		depth = defaultRoomMapDepth
+	}
+
+	mem, err := openMemory(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := mem.CountByScopePath()
+	mem.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols map[string]int
+	if _, err := os.Stat(config.IndexDBPath(rootPath)); err == nil {
+		db, err := index.Open(config.IndexDBPath(rootPath))
+		if err != nil {
+			return nil, err
+		}
+		symbols, err = index.SymbolsPerFile(db)
+		db.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entryPoints := make(map[string][]string)
+	for _, c := range counts {
+		if c.Scope == string(memory.ScopeRoom) {
+			entryPoints[c.Path] = roomEntryPoints(rootPath, c.Path)
+		}
+	}
+	m := buildRoomMap(symbols, counts, entryPoints, depth)
+	m.Indexed = symbols != nil
+	return m, nil
+}
+
+// buildRoomMap groups the indexed files (with their symbol counts) and the
+// records into areas of depth directory levels. A file-scoped record counts
+// toward its file's area. A room-scoped record counts toward the area of
+// the room's first entry point, else the area of a directory the room is
+// named after (by path, or by last path element when that names a single
+// area); rooms placed nowhere are listed as unplaced.
+func buildRoomMap(symbols map[string]int, counts []memory.ScopeCount, entryPoints map[string][]string, depth int) *RoomMap {
+	m := &RoomMap{Areas: []RoomArea{}, Rooms: []memory.ScopeCount{}}
+	areas := make(map[string]*RoomArea)
+	area := func(p string) *RoomArea {
+		if a, ok := areas[p]; ok {
+			return a
+		}
+		a := &RoomArea{Path: p, Records: map[string]int{}}
+		areas[p] = a
+		return a
+	}
+	dirAreas := make(map[string]map[string]bool) // Last path element of a directory -> areas
+	for file, n := range symbols {
+		a := area(roomMapArea(file, depth))
+		a.Files++
+		a.Symbols += n
+		for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+			base := path.Base(dir)
+			if dirAreas[base] == nil {
+				dirAreas[base] = make(map[string]bool)
+			}
+			dirAreas[base][a.Path] = true
+		}
+	}
+	place := func(room string) string {
+		if eps := entryPoints[room]; len(eps) > 0 {
+			return roomMapArea(filepath.ToSlash(eps[0]), depth)
+		}
+		if p := dirArea(strings.Trim(room, "/"), depth); areas[p] != nil && p != "." {
+			return p
+		}
+		if candidates := dirAreas[room]; len(candidates) == 1 {
+			for p := range candidates {
+				return p
+			}
+		}
+		return ""
+	}
+
+	addRecords := func(a *RoomArea, c memory.ScopeCount) {
+		for kind, n := range c.ByKind {
+			a.Records[kind] += n
+		}
+		a.Total += c.Total
+	}
+	for _, c := range counts {
+		switch memory.Scope(c.Scope) {
+		case memory.ScopePalace:
+			m.Palace += c.Total
+		case memory.ScopeFile:
+			addRecords(area(roomMapArea(filepath.ToSlash(c.Path), depth)), c)
+		case memory.ScopeRoom:
+			m.Rooms = append(m.Rooms, c)
+			p := place(c.Path)
+			if p == "" {
+				m.Unplaced = append(m.Unplaced, c.Path)
+				continue
+			}
+			a := area(p)
+			addRecords(a, c)
+			a.Rooms = append(a.Rooms, c.Path)
+		}
+	}
+
+	for _, a := range areas {
+		m.Areas = append(m.Areas, *a)
+	}
+	sort.Slice(m.Areas, func(i, j int) bool { return m.Areas[i].Path < m.Areas[j].Path })
+	return m
+}
+
+// roomMapArea returns the directory, at most depth levels deep, holding
+// file; "." for files at the root.
+func roomMapArea(file string, depth int) string {
+	return dirArea(path.Dir(file), depth)
+}
+
+// dirArea cuts dir to at most depth levels.
+func dirArea(dir string, depth int) string {
+	if dir == "" || dir == "." {
+		return "."
+	}
+	dirs := strings.Split(dir, "/")
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/")
+}
+
+// roomEntryPoints reads the entry points of a room manifest, if the room
+// has one.
+func roomEntryPoints(rootPath, room string) []string {
+	var r model.Room
+	if err := jsonc.DecodeFile(filepath.Join(rootPath, ".palace", "rooms", room+".jsonc"), &r); err != nil {
+		return nil
+	}
+	return r.EntryPoints
+}
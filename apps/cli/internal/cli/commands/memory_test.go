@@ -0,0 +1,409 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func TestRunMemoryUnknownCommand(t *testing.T) {
+	if err := RunMemory([]string{"bogus"}); err == nil || !strings.Contains(err.Error(), "unknown memory command") {
+		t.Errorf("expected unknown command error, got %v", err)
+	}
+}
+
+func TestRunMemoryUndoInvalidCount(t *testing.T) {
+	if err := RunMemoryUndo([]string{"--root", t.TempDir(), "zero"}); err == nil {
+		t.Error("expected error for non-numeric count")
+	}
+}
+
+func TestExecuteMemoryUndoAndLog(t *testing.T) {
+	root := t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	id, _ := mem.AddIdea(memory.Idea{Content: "Journal me"})
+	mem.Close()
+
+	undone, err := ExecuteMemoryUndo(MemoryUndoOptions{Root: root, Count: 1})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryUndo() error: %v", err)
+	}
+	if len(undone) != 1 || undone[0].RecordID != id {
+		t.Fatalf("expected idea %s undone, got %+v", id, undone)
+	}
+
+	entries, err := ExecuteMemoryLog(MemoryLogOptions{Root: root, Limit: 10})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryLog() error: %v", err)
+	}
+	if len(entries) != 1 || !entries[0].Undone() || journalSummary(entries[0]) != "Journal me" {
+		t.Errorf("unexpected log %+v", entries)
+	}
+}
+
+func TestExecuteMemoryReview(t *testing.T) {
+	root := t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	id, _, _, err := mem.ClassifyAndStore("Retries use exponential backoff", "cli", "")
+	mem.Close()
+	if err != nil {
+		t.Fatalf("ClassifyAndStore() error: %v", err)
+	}
+
+	result, err := ExecuteMemoryReview(MemoryReviewOptions{Root: root})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryReview() error: %v", err)
+	}
+	if len(result.Queue) != 1 || result.Queue[0].RecordID != id {
+		t.Fatalf("expected %s queued, got %+v", id, result.Queue)
+	}
+
+	if err := RunMemoryReview([]string{"--root", root, id}); err == nil {
+		t.Error("expected error reviewing a record without --as")
+	}
+	if err := RunMemoryReview([]string{"--root", root, id, "--as", "idea"}); err != nil {
+		t.Fatalf("RunMemoryReview() error: %v", err)
+	}
+
+	result, err = ExecuteMemoryReview(MemoryReviewOptions{Root: root})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryReview() error: %v", err)
+	}
+	if len(result.Queue) != 0 {
+		t.Errorf("confirming should empty the queue, got %+v", result.Queue)
+	}
+}
+
+func TestRunMemoryPromote(t *testing.T) {
+	root := t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	id, _ := mem.AddIdea(memory.Idea{Content: "Batch the audit writes", Scope: "file", ScopePath: "audit/log.go"})
+	mem.Close()
+
+	if err := RunMemoryPromote([]string{"--root", root, id, "--to", "file"}); err == nil {
+		t.Error("expected error demoting to file without --path")
+	}
+	if err := RunMemoryPromote([]string{"--root", root, id, "--to", "palace"}); err != nil {
+		t.Fatalf("RunMemoryPromote() error: %v", err)
+	}
+
+	mem, _ = memory.Open(root)
+	defer mem.Close()
+	if idea, _ := mem.GetIdea(id); idea.Scope != "palace" || idea.ScopePath != "" {
+		t.Errorf("expected palace scope, got %s:%s", idea.Scope, idea.ScopePath)
+	}
+}
+
+func TestExecuteMemoryGCDryRun(t *testing.T) {
+	root := t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	id, _ := mem.AddIdea(memory.Idea{Content: "Forgotten idea", CreatedAt: time.Now().AddDate(-1, 0, 0)})
+	mem.Close()
+
+	candidates, err := ExecuteMemoryGC(MemoryGCOptions{Root: root, DryRun: true})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryGC() error: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != id {
+		t.Fatalf("expected idea %s listed, got %+v", id, candidates)
+	}
+	if candidates, _ = ExecuteMemoryGC(MemoryGCOptions{Root: root, DryRun: true, MinAgeDays: 400}); len(candidates) != 0 {
+		t.Errorf("--min-age 400 should spare a year-old idea, got %+v", candidates)
+	}
+
+	if _, err := ExecuteMemoryGC(MemoryGCOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteMemoryGC() error: %v", err)
+	}
+	candidates, _ = ExecuteMemoryGC(MemoryGCOptions{Root: root, DryRun: true})
+	if len(candidates) != 0 {
+		t.Errorf("expected nothing left to archive, got %+v", candidates)
+	}
+}
+
+func TestExecuteMemoryImportMerge(t *testing.T) {
+	root, source := t.TempDir(), t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	_, _ = mem.AddLearning(memory.Learning{Content: "Tests need Docker running"})
+	mem.Close()
+	src, err := memory.Open(source)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	_, _ = src.AddLearning(memory.Learning{Content: "tests need docker running"})
+	src.Close()
+
+	report, err := ExecuteMemoryImport(MemoryImportOptions{Root: root, Source: source})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryImport() error: %v", err)
+	}
+	if len(report.Imported) != 1 {
+		t.Errorf("without --merge a differently-identified copy is imported, got %+v", report)
+	}
+
+	root = t.TempDir()
+	mem, _ = memory.Open(root)
+	_, _ = mem.AddLearning(memory.Learning{Content: "Tests need Docker running"})
+	mem.Close()
+	report, err = ExecuteMemoryImport(MemoryImportOptions{Root: root, Source: source, Merge: true})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryImport() error: %v", err)
+	}
+	if len(report.Imported) != 0 || len(report.Duplicates) != 1 {
+		t.Errorf("with --merge the copy should be skipped, got %+v", report)
+	}
+}
+
+func TestExecuteMemoryImportStream(t *testing.T) {
+	root := t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	_, _ = mem.AddLearning(memory.Learning{ID: "lrn_held", Content: "Builds run on CI only"})
+	mem.Close()
+
+	in := filepath.Join(t.TempDir(), "brain.jsonl")
+	lines := `{"kind": "learning", "id": "lrn_new", "content": "Tests need Docker running", "tags": ["testing"]}
+{"kind": "decision", "content": "Use Postgres for storage"
+{"kind": "idea", "id": "i_cache", "content": "Cache the index in memory"}
+
+{"kind": "learning", "id": "lrn_held", "content": "Builds run locally too"}
+`
+	if err := os.WriteFile(in, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress strings.Builder
+	report, err := ExecuteMemoryImportStream(MemoryImportStreamOptions{Root: root, In: in, Progress: &progress})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryImportStream() error: %v", err)
+	}
+	if report.Lines != 5 || report.Imported != 2 || len(report.Conflicts) != 1 {
+		t.Errorf("expected 2 records imported and 1 conflict from 5 lines, got %+v", report)
+	}
+	if len(report.Malformed) != 1 || report.Malformed[0].Line != 2 {
+		t.Errorf("expected line 2 reported as malformed, got %+v", report.Malformed)
+	}
+
+	mem, _ = memory.Open(root)
+	if l, err := mem.GetLearning("lrn_new"); err != nil || l.Content != "Tests need Docker running" {
+		t.Errorf("lrn_new should be imported, got %+v, %v", l, err)
+	}
+	if tags, _ := mem.GetTags("lrn_new", "learning"); len(tags) != 1 || tags[0] != "testing" {
+		t.Errorf("tags should be imported, got %v", tags)
+	}
+	if _, err := mem.GetIdea("i_cache"); err != nil {
+		t.Errorf("the idea after the malformed line should be imported: %v", err)
+	}
+	mem.Close()
+
+	report, err = ExecuteMemoryImportStream(MemoryImportStreamOptions{Root: root, In: in, Replace: true})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryImportStream(replace) error: %v", err)
+	}
+	if report.Replaced != 1 || report.Duplicates != 2 {
+		t.Errorf("with --replace the conflicting record should be overwritten, got %+v", report)
+	}
+	mem, _ = memory.Open(root)
+	if l, _ := mem.GetLearning("lrn_held"); l == nil || l.Content != "Builds run locally too" {
+		t.Errorf("lrn_held should be replaced, got %+v", l)
+	}
+	mem.Close()
+
+	if _, err := ExecuteMemoryImportStream(MemoryImportStreamOptions{Root: root, In: in, Strict: true}); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("--strict should stop at the malformed line, got %v", err)
+	}
+}
+
+func TestExecuteMemoryRooms(t *testing.T) {
+	root := t.TempDir()
+	for file, src := range map[string]string{
+		"services/auth/jwt.go":       "package auth\n\nfunc Sign() {}\n\nfunc Verify() {}\n",
+		"services/billing/stripe.go": "package billing\n\nfunc Charge() {}\n",
+		"main.go":                    "package main\n\nfunc main() {}\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, file)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, file), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := scan.Run(root); err != nil {
+		t.Fatalf("scan.Run() error: %v", err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	mem.AddDecision(memory.Decision{Content: "Tokens expire after 15 minutes", Scope: "room", ScopePath: "auth"})
+	mem.AddIdea(memory.Idea{Content: "Rotate signing keys", Scope: "room", ScopePath: "auth"})
+	mem.AddLearning(memory.Learning{Content: "Verify checks the audience", Scope: "file", ScopePath: "services/auth/jwt.go", Confidence: 0.8})
+	mem.AddIdea(memory.Idea{Content: "Move to a queue", Scope: "room", ScopePath: "notifications"})
+	mem.AddIdea(memory.Idea{Content: "Keep the CLI small"})
+	mem.Close()
+
+	m, err := ExecuteMemoryRooms(MemoryRoomsOptions{Root: root, Depth: 2})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryRooms() error: %v", err)
+	}
+	areas := make(map[string]RoomArea)
+	for _, a := range m.Areas {
+		areas[a.Path] = a
+	}
+
+	auth := areas["services/auth"]
+	if auth.Files != 1 || auth.Symbols != 2 || auth.Total != 3 ||
+		auth.Records["decision"] != 1 || auth.Records["idea"] != 1 || auth.Records["learning"] != 1 {
+		t.Errorf("services/auth should hold its file, 2 symbols, and 3 records, got %+v", auth)
+	}
+	if len(auth.Rooms) != 1 || auth.Rooms[0] != "auth" {
+		t.Errorf("room auth should be placed in services/auth, got %v", auth.Rooms)
+	}
+	if billing := areas["services/billing"]; !billing.Dark() || billing.Symbols != 1 {
+		t.Errorf("services/billing has code but no records and should be dark, got %+v", billing)
+	}
+	if len(m.Unplaced) != 1 || m.Unplaced[0] != "notifications" {
+		t.Errorf("room notifications matches no directory, got unplaced %v", m.Unplaced)
+	}
+	if m.Palace != 1 || !m.Indexed {
+		t.Errorf("expected 1 palace-wide record with the index read, got %+v", m)
+	}
+}
+
+func TestExecuteMemoryBundle(t *testing.T) {
+	root := t.TempDir()
+	src := "package auth\n\n// Verify checks a token.\nfunc Verify(token string) error {\n\treturn nil\n}\n"
+	if err := os.MkdirAll(filepath.Join(root, "auth"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "auth", "jwt.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := scan.Run(root); err != nil {
+		t.Fatalf("scan.Run() error: %v", err)
+	}
+
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	decision, _ := mem.AddDecision(memory.Decision{Content: "Reject tokens without an expiry"})
+	learning, _ := mem.AddLearning(memory.Learning{Content: "Expired tokens were accepted by Verify"})
+	unrelated, _ := mem.AddLearning(memory.Learning{Content: "The cache warms on startup"})
+	if err := mem.AddTag(decision, "decision", "incident-123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.AddTag(learning, "learning", "incident-123"); err != nil {
+		t.Fatal(err)
+	}
+	links := []memory.Link{
+		{SourceID: learning, SourceKind: "learning", TargetID: decision, TargetKind: "decision", Relation: memory.RelationSupports},
+		{SourceID: learning, SourceKind: "learning", TargetID: "auth/jwt.go:4-6", TargetKind: memory.TargetKindCode, Relation: memory.RelationRelated},
+		{SourceID: decision, SourceKind: "decision", TargetID: unrelated, TargetKind: "learning", Relation: memory.RelationRelated},
+	}
+	for _, l := range links {
+		if _, err := mem.AddLink(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mem.Close()
+
+	b, err := ExecuteMemoryBundle(MemoryBundleOptions{Root: root, Tag: "incident-123"})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryBundle() error: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "bundle.json")
+	f, err := os.Create(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := memory.WriteBundle(f, b); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	opened, err := ExecuteMemoryBundleOpen(out)
+	if err != nil {
+		t.Fatalf("ExecuteMemoryBundleOpen() error: %v", err)
+	}
+	if len(opened.Decisions) != 1 || opened.Decisions[0].ID != decision || len(opened.Learnings) != 1 || opened.Learnings[0].ID != learning {
+		t.Fatalf("expected the tagged decision and learning, got %+v", opened)
+	}
+	if len(opened.Links) != 2 {
+		t.Fatalf("expected the inter-link and the code link, not the link leaving the bundle; got %+v", opened.Links)
+	}
+	var interLinked bool
+	for _, l := range opened.Links {
+		interLinked = interLinked || (l.SourceID == learning && l.TargetID == decision && l.Relation == memory.RelationSupports)
+	}
+	if !interLinked {
+		t.Errorf("expected learning --supports--> decision, got %+v", opened.Links)
+	}
+	if len(opened.Code) != 1 {
+		t.Fatalf("expected one code anchor, got %+v", opened.Code)
+	}
+	code := opened.Code[0]
+	if code.Anchor != "auth/jwt.go:4-6" || !strings.HasPrefix(code.Excerpt, "func Verify(token string) error {") {
+		t.Errorf("unexpected code snapshot: %+v", code)
+	}
+	if len(code.Symbols) != 1 || code.Symbols[0].Name != "Verify" || !strings.Contains(code.Symbols[0].Signature, "token string") {
+		t.Errorf("expected Verify with its signature, got %+v", code.Symbols)
+	}
+}
+
+func TestExecuteMemoryHistory(t *testing.T) {
+	root := t.TempDir()
+	mem, err := memory.Open(root)
+	if err != nil {
+		t.Fatalf("memory.Open() error: %v", err)
+	}
+	id, _ := mem.AddIdea(memory.Idea{Content: "Shard by tenant"})
+	if _, err := mem.EditRecord(id, memory.RecordEdit{Content: "Shard by region"}); err != nil {
+		t.Fatalf("EditRecord() error: %v", err)
+	}
+	mem.Close()
+
+	result, err := ExecuteMemoryHistory(MemoryHistoryOptions{Root: root, ID: id})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryHistory() error: %v", err)
+	}
+	if result.Content != "Shard by region" || len(result.Versions) != 1 || result.Versions[0].Content != "Shard by tenant" {
+		t.Fatalf("unexpected history %+v", result)
+	}
+
+	if err := RunMemoryHistory([]string{"--root", root, id, "--restore", "1"}); err != nil {
+		t.Fatalf("RunMemoryHistory() error: %v", err)
+	}
+	result, err = ExecuteMemoryHistory(MemoryHistoryOptions{Root: root, ID: id})
+	if err != nil {
+		t.Fatalf("ExecuteMemoryHistory() error: %v", err)
+	}
+	if result.Content != "Shard by tenant" || len(result.Versions) != 2 {
+		t.Errorf("restoring should bring back version 1 and keep the replaced one, got %+v", result)
+	}
+	if err := RunMemoryHistory([]string{"--root", root}); err == nil {
+		t.Error("expected usage error without a record ID")
+	}
+}
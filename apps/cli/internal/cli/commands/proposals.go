@@ -49,7 +49,7 @@ func RunProposals(args []string) error {
 	proposedAs := fs.String("type", "", "filter by type: decision, learning")
 	limit := fs.Int("limit", 20, "maximum number of proposals to show")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Handle "all" status
@@ -161,7 +161,7 @@ func RunApprove(args []string) error {
 	reviewedBy := fs.String("by", "cli", "reviewer identifier")
 	reviewNote := fs.String("note", "", "optional review note")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	remaining := fs.Args()
@@ -256,7 +256,7 @@ func RunReject(args []string) error {
 	reviewedBy := fs.String("by", "cli", "reviewer identifier")
 	reviewNote := fs.String("note", "", "reason for rejection (recommended)")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	remaining := fs.Args()
@@ -0,0 +1,1268 @@
+package commands
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/gitutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "query",
+		Description: "Run structured queries against the code index",
+		Run:         RunQuery,
+	})
+}
+
+// RunQuery dispatches to the appropriate query subcommand.
+func RunQuery(args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: palace query <command>
+
+Commands:
+  annotated       List symbols carrying an annotation, decorator, or attribute
+  unresolved      List the most common call targets that resolve to no indexed symbol
+  recent-changes  List symbols in files modified within a time window
+  imports         List imports, classified as local, stdlib, or thirdparty
+  secrets         List likely hardcoded secrets found by 'palace scan --scan-secrets'
+  commented-code  List blocks of commented-out code with their line ranges
+  deprecated      List symbols marked deprecated (or --experimental)
+  callgraph       Render the call neighborhood of a symbol as Mermaid or DOT
+  owned-by        List symbols attributed to an author by 'palace scan --blame'
+  signature-search  Find functions by parameter and return types
+  constants       List constants with their literal values
+  impls-of-method List the types implementing an interface method
+  hotspots        Rank functions by complexity, size, and git churn
+  weak-tests      List tests and subtests that make no assertions
+  neighbors       List the files a file depends on and the files depending on it
+  config-usage    List the code reading a configuration or environment key
+  calls-into      List the symbols of a directory that code outside it calls
+
+Examples:
+  palace query annotated Deprecated
+  palace query annotated app.route --json
+  palace query unresolved --top 50
+  palace query recent-changes --within 24h --lang go
+  palace query imports --kind thirdparty --module requests
+  palace query secrets --json
+  palace query commented-code
+  palace query deprecated --experimental
+  palace query callgraph Run --depth 2 --format dot
+  palace query owned-by alice@example.com
+  palace query signature-search --param context.Context --param string --returns error
+  palace query constants --path config/
+  palace query impls-of-method Server.Serve
+  palace query hotspots --top 20
+  palace query weak-tests
+  palace query neighbors auth/jwt.go --depth 2
+  palace query config-usage DB_HOST
+  palace query calls-into internal/auth`)
+	}
+
+	switch args[0] {
+	case "annotated":
+		return RunQueryAnnotated(args[1:])
+	case "unresolved":
+		return RunQueryUnresolved(args[1:])
+	case "recent-changes":
+		return RunQueryRecentChanges(args[1:])
+	case "imports":
+		return RunQueryImports(args[1:])
+	case "secrets":
+		return RunQuerySecrets(args[1:])
+	case "commented-code":
+		return RunQueryCommentedCode(args[1:])
+	case "deprecated":
+		return RunQueryDeprecated(args[1:])
+	case "callgraph":
+		return RunQueryCallgraph(args[1:])
+	case "owned-by":
+		return RunQueryOwnedBy(args[1:])
+	case "signature-search":
+		return RunQuerySignatureSearch(args[1:])
+	case "constants":
+		return RunQueryConstants(args[1:])
+	case "impls-of-method":
+		return RunQueryImplsOfMethod(args[1:])
+	case "hotspots":
+		return RunQueryHotspots(args[1:])
+	case "weak-tests":
+		return RunQueryWeakTests(args[1:])
+	case "neighbors":
+		return RunQueryNeighbors(args[1:])
+	case "config-usage":
+		return RunQueryConfigUsage(args[1:])
+	case "calls-into":
+		return RunQueryCallsInto(args[1:])
+	default:
+		return UsageError(fmt.Errorf("unknown query command: %s\nRun 'palace help query' for usage", args[0]))
+	}
+}
+
+// openQueryIndex opens the workspace index for read-only queries.
+func openQueryIndex(root string) (*sql.DB, error) {
+	rootPath, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	dbPath := config.IndexDBPath(rootPath)
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("index missing; run 'palace scan' first: %w", err)
+	}
+	return index.Open(dbPath)
+}
+
+// QueryAnnotatedOptions contains the configuration for query annotated.
+type QueryAnnotatedOptions struct {
+	Root  string
+	Name  string
+	Limit int
+}
+
+// RunQueryAnnotated executes the query annotated subcommand.
+func RunQueryAnnotated(args []string) error {
+	fs := flag.NewFlagSet("query annotated", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	limit := fs.Int("limit", 0, "maximum number of symbols (0 = no limit)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace query annotated <name>"))
+	}
+
+	symbols, err := ExecuteQueryAnnotated(QueryAnnotatedOptions{Root: *root, Name: fs.Arg(0), Limit: *limit})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(symbols)
+	}
+	if len(symbols) == 0 {
+		fmt.Printf("No symbols annotated with %q.\n", fs.Arg(0))
+		return nil
+	}
+	items := make([]queryItem, len(symbols))
+	for i, sym := range symbols {
+		items[i] = queryItem{
+			Kind: sym.Kind, Name: sym.Name, File: sym.FilePath, Line: sym.LineStart,
+			Note:      "[" + strings.Join(sym.Annotations, ", ") + "]",
+			Signature: sym.Signature, Doc: sym.DocComment,
+		}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d symbols", len(items)))
+}
+
+// ExecuteQueryAnnotated returns the indexed symbols bearing the named annotation.
+func ExecuteQueryAnnotated(opts QueryAnnotatedOptions) ([]index.SymbolInfo, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.FindAnnotatedSymbols(db, opts.Name, opts.Limit)
+}
+
+// QueryUnresolvedOptions contains the configuration for query unresolved.
+type QueryUnresolvedOptions struct {
+	Root string
+	Top  int
+}
+
+// RunQueryUnresolved executes the query unresolved subcommand.
+func RunQueryUnresolved(args []string) error {
+	fs := flag.NewFlagSet("query unresolved", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	top := fs.Int("top", 50, "number of callee names to list (0 = all)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	report, err := ExecuteQueryUnresolved(QueryUnresolvedOptions{Root: *root, Top: *top})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printUnresolvedReport(report)
+	return nil
+}
+
+// ExecuteQueryUnresolved returns the unresolved call report for the workspace index.
+func ExecuteQueryUnresolved(opts QueryUnresolvedOptions) (*index.UnresolvedReport, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetUnresolvedCalls(db, opts.Top)
+}
+
+func printUnresolvedReport(report *index.UnresolvedReport) {
+	if report.TotalCalls == 0 {
+		fmt.Println("No call relationships in the index.")
+		return
+	}
+	fmt.Printf("%d of %d calls unresolved (%.0f%%)\n", report.Unresolved, report.TotalCalls,
+		float64(report.Unresolved)*100/float64(report.TotalCalls))
+	for _, cause := range []index.UnresolvedCause{index.UnresolvedExternal, index.UnresolvedUntracked, index.UnresolvedAmbiguous} {
+		if n := report.ByCause[cause]; n > 0 {
+			fmt.Printf("  %-10s %d\n", cause, n)
+		}
+	}
+	if len(report.Callees) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%6s  %-10s  %-40s  %s\n", "COUNT", "CAUSE", "CALLEE", "FIRST SEEN")
+	for _, c := range report.Callees {
+		fmt.Printf("%6d  %-10s  %-40s  %s\n", c.Count, c.Cause, c.Name, c.Example)
+	}
+	fmt.Println()
+	fmt.Println("external:  qualified call to a name not in the index; scan the dependency to resolve it")
+	fmt.Println("untracked: builtin, untracked import alias, or dynamic call")
+	fmt.Println("ambiguous: name defined in several files; the target cannot be chosen by name")
+}
+
+// QueryRecentChangesOptions contains the configuration for query recent-changes.
+type QueryRecentChangesOptions struct {
+	Root     string
+	Within   time.Duration
+	Language string
+	Kind     string
+	Now      time.Time // Reference time for the window (default: time.Now())
+}
+
+// RunQueryRecentChanges executes the query recent-changes subcommand.
+func RunQueryRecentChanges(args []string) error {
+	fs := flag.NewFlagSet("query recent-changes", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	withinStr := fs.String("within", "24h", "time window, e.g. 30m, 24h, 7d")
+	lang := fs.String("lang", "", "only files in this language")
+	kind := fs.String("kind", "", "only symbols of this kind (function, method, class, ...)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+	within, err := parseWindow(*withinStr)
+	if err != nil {
+		return err
+	}
+
+	files, err := ExecuteQueryRecentChanges(QueryRecentChangesOptions{Root: *root, Within: within, Language: *lang, Kind: *kind})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(files)
+	}
+	if len(files) == 0 {
+		fmt.Printf("No indexed files modified within %s.\n", *withinStr)
+		return nil
+	}
+	var items []queryItem
+	for _, f := range files {
+		note := "modified " + f.ModTime.Local().Format("2006-01-02 15:04")
+		if f.Stale {
+			note += " (changed since last scan)"
+		}
+		if len(f.Symbols) == 0 {
+			items = append(items, queryItem{Kind: "file", Name: f.Path, File: f.Path, Line: 1, Note: note})
+		}
+		items = appendRecentSymbols(items, f.Path, note, f.Symbols)
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d files", len(files)))
+}
+
+// ExecuteQueryRecentChanges returns indexed files modified within the window, most recent first.
+func ExecuteQueryRecentChanges(opts QueryRecentChangesOptions) ([]index.RecentFile, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openQueryIndex(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return index.GetRecentChanges(db, rootPath, index.RecentChangesOptions{
+		Since:    now.Add(-opts.Within),
+		Language: opts.Language,
+		Kind:     opts.Kind,
+	})
+}
+
+// appendRecentSymbols adds the symbols of a changed file, nested ones
+// included, to items.
+func appendRecentSymbols(items []queryItem, file, note string, symbols []index.SymbolInfo) []queryItem {
+	for _, sym := range symbols {
+		items = append(items, queryItem{
+			Kind: sym.Kind, Name: sym.Name, File: file, Line: sym.LineStart, Note: note,
+			Signature: sym.Signature, Doc: sym.DocComment,
+		})
+		items = appendRecentSymbols(items, file, note, sym.Children)
+	}
+	return items
+}
+
+// QueryImportsOptions contains the configuration for query imports.
+type QueryImportsOptions struct {
+	Root   string
+	Kind   string
+	Module string
+}
+
+// RunQueryImports executes the query imports subcommand.
+func RunQueryImports(args []string) error {
+	fs := flag.NewFlagSet("query imports", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	kind := fs.String("kind", "", "only imports of this kind (local, stdlib, thirdparty)")
+	module := fs.String("module", "", "only imports of this module or its submodules")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	refs, err := ExecuteQueryImports(QueryImportsOptions{Root: *root, Kind: *kind, Module: *module})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(refs)
+	}
+	if len(refs) == 0 {
+		fmt.Println("No matching imports in the index.")
+		return nil
+	}
+	items := make([]queryItem, len(refs))
+	for i, r := range refs {
+		items[i] = queryItem{Kind: r.Kind, Name: r.Module, File: r.File, Line: r.Line}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d imports", len(items)))
+}
+
+// ExecuteQueryImports returns the indexed imports matching the options.
+func ExecuteQueryImports(opts QueryImportsOptions) ([]index.ImportRef, error) {
+	switch opts.Kind {
+	case "", "local", "stdlib", "thirdparty":
+	default:
+		return nil, fmt.Errorf("invalid --kind %q (use local, stdlib, or thirdparty)", opts.Kind)
+	}
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetImports(db, index.ImportsOptions{Kind: opts.Kind, Module: opts.Module})
+}
+
+// QuerySecretsOptions contains the configuration for query secrets.
+type QuerySecretsOptions struct {
+	Root string
+}
+
+// RunQuerySecrets executes the query secrets subcommand.
+func RunQuerySecrets(args []string) error {
+	fs := flag.NewFlagSet("query secrets", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	findings, err := ExecuteQuerySecrets(QuerySecretsOptions{Root: *root})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(findings)
+	}
+	if len(findings) == 0 {
+		fmt.Println("No secret findings in the index. Secrets are only detected by 'palace scan --scan-secrets'.")
+		return nil
+	}
+	items := make([]queryItem, len(findings))
+	for i, f := range findings {
+		items[i] = queryItem{Kind: "secret", Name: f.Rule, File: f.File, Line: f.Line, Note: f.Description}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d findings", len(items)))
+}
+
+// ExecuteQuerySecrets returns the secret findings recorded in the workspace index.
+func ExecuteQuerySecrets(opts QuerySecretsOptions) ([]index.SecretFinding, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetSecretFindings(db)
+}
+
+// QueryCommentedCodeOptions contains the configuration for query commented-code.
+type QueryCommentedCodeOptions struct {
+	Root string
+}
+
+// RunQueryCommentedCode executes the query commented-code subcommand.
+func RunQueryCommentedCode(args []string) error {
+	fs := flag.NewFlagSet("query commented-code", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	blocks, err := ExecuteQueryCommentedCode(QueryCommentedCodeOptions{Root: *root})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(blocks)
+	}
+	if len(blocks) == 0 {
+		fmt.Println("No commented-out code found.")
+		return nil
+	}
+	total := 0
+	items := make([]queryItem, len(blocks))
+	for i, b := range blocks {
+		n := b.LineEnd - b.LineStart + 1
+		total += n
+		items[i] = queryItem{Kind: "comment", Name: "commented-out code", File: b.File, Line: b.LineStart, LineEnd: b.LineEnd, Note: fmt.Sprintf("(%d lines)", n)}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d blocks, %d lines", len(blocks), total))
+}
+
+// ExecuteQueryCommentedCode returns the blocks of commented-out code recorded
+// in the workspace index.
+func ExecuteQueryCommentedCode(opts QueryCommentedCodeOptions) ([]index.CommentedCode, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetCommentedCode(db)
+}
+
+// QueryDeprecatedOptions contains the configuration for query deprecated.
+type QueryDeprecatedOptions struct {
+	Root         string
+	Experimental bool
+}
+
+// RunQueryDeprecated executes the query deprecated subcommand.
+func RunQueryDeprecated(args []string) error {
+	fs := flag.NewFlagSet("query deprecated", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	experimental := fs.Bool("experimental", false, "list experimental symbols instead")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	symbols, err := ExecuteQueryDeprecated(QueryDeprecatedOptions{Root: *root, Experimental: *experimental})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(symbols)
+	}
+	label := "deprecated"
+	if *experimental {
+		label = "experimental"
+	}
+	if len(symbols) == 0 {
+		fmt.Printf("No %s symbols found.\n", label)
+		return nil
+	}
+	items := make([]queryItem, len(symbols))
+	for i, s := range symbols {
+		items[i] = queryItem{Kind: s.Kind, Name: s.Name, File: s.File, Line: s.Line}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d %s symbols", len(symbols), label))
+}
+
+// ExecuteQueryDeprecated returns the symbols marked deprecated, or
+// experimental, in the workspace index.
+func ExecuteQueryDeprecated(opts QueryDeprecatedOptions) ([]index.FlaggedSymbol, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetFlaggedSymbols(db, opts.Experimental)
+}
+
+// QueryCallgraphOptions contains the configuration for query callgraph.
+type QueryCallgraphOptions struct {
+	Root     string
+	Symbol   string
+	File     string // Defining file, when the name is defined in several
+	Depth    int
+	Incoming bool
+}
+
+// RunQueryCallgraph executes the query callgraph subcommand.
+func RunQueryCallgraph(args []string) error {
+	fs := flag.NewFlagSet("query callgraph", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	file := fs.String("file", "", "file defining the symbol, if its name is ambiguous")
+	depth := fs.Int("depth", 2, "levels of calls to follow (1-10)")
+	incoming := fs.Bool("incoming", false, "follow callers instead of callees")
+	format := fs.String("format", "mermaid", "output format: mermaid, dot, or json")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace query callgraph <symbol> [--depth N] [--incoming] [--format mermaid|dot|json]"))
+	}
+	// Accept flags after the symbol too: "callgraph Run --depth 1"
+	symbol := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return UsageError(err)
+	}
+	if *format != "mermaid" && *format != "dot" && *format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: mermaid, dot, json)", *format)
+	}
+
+	graph, err := ExecuteQueryCallgraph(QueryCallgraphOptions{
+		Root:     *root,
+		Symbol:   symbol,
+		File:     *file,
+		Depth:    *depth,
+		Incoming: *incoming,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(graph)
+	case "dot":
+		fmt.Print(graph.DOT())
+	default:
+		fmt.Print(graph.Mermaid())
+	}
+	return nil
+}
+
+// ExecuteQueryCallgraph returns the call subgraph rooted at a symbol.
+func ExecuteQueryCallgraph(opts QueryCallgraphOptions) (*index.CallSubgraph, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetCallSubgraph(db, opts.Symbol, opts.File, opts.Depth, opts.Incoming)
+}
+
+// parseWindow parses a Go duration, also accepting whole days ("7d").
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid --within %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --within %q", s)
+	}
+	return d, nil
+}
+
+// QueryOwnedByOptions contains the configuration for query owned-by.
+type QueryOwnedByOptions struct {
+	Root   string
+	Author string
+	Limit  int
+}
+
+// RunQueryOwnedBy executes the query owned-by subcommand.
+func RunQueryOwnedBy(args []string) error {
+	fs := flag.NewFlagSet("query owned-by", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	limit := fs.Int("limit", 0, "maximum number of symbols (0 = no limit)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace query owned-by <author>"))
+	}
+	author := strings.Join(fs.Args(), " ")
+
+	symbols, err := ExecuteQueryOwnedBy(QueryOwnedByOptions{Root: *root, Author: author, Limit: *limit})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(symbols)
+	}
+	if len(symbols) == 0 {
+		fmt.Printf("No symbols owned by %q. Owners are recorded by 'palace scan --blame'.\n", author)
+		return nil
+	}
+	items := make([]queryItem, len(symbols))
+	for i, s := range symbols {
+		commit := s.LastCommit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		items[i] = queryItem{
+			Kind: s.Kind, Name: s.Name, File: s.File, Line: s.LineStart, LineEnd: s.LineEnd,
+			Note: fmt.Sprintf("%s (%s)", s.Owner, commit),
+		}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d symbols", len(symbols)))
+}
+
+// ExecuteQueryOwnedBy returns the symbols whose blame owner matches the
+// author's name or email.
+func ExecuteQueryOwnedBy(opts QueryOwnedByOptions) ([]index.OwnedSymbol, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetSymbolsOwnedBy(db, opts.Author, opts.Limit)
+}
+
+// QuerySignatureSearchOptions contains the configuration for query
+// signature-search.
+type QuerySignatureSearchOptions struct {
+	Root    string
+	Params  []string
+	Returns []string
+	Fuzzy   bool
+	Limit   int
+}
+
+// RunQuerySignatureSearch executes the query signature-search subcommand.
+func RunQuerySignatureSearch(args []string) error {
+	fs := flag.NewFlagSet("query signature-search", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	var params, returns flags.StringList
+	fs.Var(&params, "param", "parameter type, in order (repeatable)")
+	fs.Var(&returns, "returns", "return type, in order (repeatable)")
+	fuzzy := fs.Bool("fuzzy", false, "match partial type names, parameters in any order, extra parameters allowed")
+	limit := fs.Int("limit", 0, "maximum number of symbols (0 = no limit)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+	if len(params) == 0 && len(returns) == 0 {
+		return UsageError(errors.New("usage: palace query signature-search --param <type>... [--returns <type>...] [--fuzzy]"))
+	}
+
+	matches, err := ExecuteQuerySignatureSearch(QuerySignatureSearchOptions{
+		Root:    *root,
+		Params:  params,
+		Returns: returns,
+		Fuzzy:   *fuzzy,
+		Limit:   *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		if matches == nil {
+			matches = []index.SignatureMatch{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matches)
+	}
+	if len(matches) == 0 {
+		if !*fuzzy {
+			fmt.Println("No matching functions. Try --fuzzy for partial matches.")
+		} else {
+			fmt.Println("No matching functions.")
+		}
+		return nil
+	}
+	items := make([]queryItem, len(matches))
+	for i, m := range matches {
+		items[i] = queryItem{Kind: m.Kind, Name: m.Name, File: m.File, Line: m.Line, Signature: m.Signature}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d functions", len(matches)))
+}
+
+// ExecuteQuerySignatureSearch returns the callables whose signatures have
+// the given parameter and return types.
+func ExecuteQuerySignatureSearch(opts QuerySignatureSearchOptions) ([]index.SignatureMatch, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	q := analysis.SignatureQuery{Fuzzy: opts.Fuzzy}
+	if len(opts.Params) > 0 {
+		q.Params = opts.Params
+	}
+	if len(opts.Returns) > 0 {
+		q.Returns = opts.Returns
+	}
+	return index.FindSymbolsBySignature(db, q, opts.Limit)
+}
+
+// QueryConstantsOptions contains the configuration for query constants.
+type QueryConstantsOptions struct {
+	Root      string
+	Path      string
+	Variables bool
+}
+
+// RunQueryConstants executes the query constants subcommand.
+func RunQueryConstants(args []string) error {
+	fs := flag.NewFlagSet("query constants", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	path := fs.String("path", "", "only files under this path")
+	variables := fs.Bool("variables", false, "include variables assigned a literal")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	constants, err := ExecuteQueryConstants(QueryConstantsOptions{Root: *root, Path: *path, Variables: *variables})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(constants)
+	}
+	if len(constants) == 0 {
+		fmt.Println("No constants with literal values found.")
+		return nil
+	}
+	items := make([]queryItem, len(constants))
+	for i, c := range constants {
+		items[i] = queryItem{Kind: c.Kind, Name: c.Name, File: c.File, Line: c.Line, Note: "= " + c.Value}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d constants", len(constants)))
+}
+
+// ExecuteQueryConstants returns the constants, and optionally variables,
+// whose literal values the index recorded.
+func ExecuteQueryConstants(opts QueryConstantsOptions) ([]index.ConstantValue, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetConstantValues(db, filepath.ToSlash(opts.Path), opts.Variables)
+}
+
+// QueryImplsOfMethodOptions contains the configuration for query impls-of-method.
+type QueryImplsOfMethodOptions struct {
+	Root      string
+	Interface string
+	Method    string
+}
+
+// RunQueryImplsOfMethod executes the query impls-of-method subcommand.
+func RunQueryImplsOfMethod(args []string) error {
+	fs := flag.NewFlagSet("query impls-of-method", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+	target := fs.Arg(0)
+	dot := strings.LastIndexByte(target, '.')
+	if fs.NArg() != 1 || dot <= 0 || dot == len(target)-1 {
+		return UsageError(errors.New("usage: palace query impls-of-method <Interface>.<method>"))
+	}
+	iface, method := target[:dot], target[dot+1:]
+
+	impls, err := ExecuteQueryImplsOfMethod(QueryImplsOfMethodOptions{Root: *root, Interface: iface, Method: method})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(impls)
+	}
+	if len(impls) == 0 {
+		fmt.Printf("No implementations of %s found.\n", target)
+		return nil
+	}
+	items := make([]queryItem, len(impls))
+	for i, impl := range impls {
+		items[i] = queryItem{
+			Kind: "method", Name: method, File: impl.File, Line: impl.Line,
+			Note: fmt.Sprintf("on %s (%s)", impl.Type, impl.Via), Signature: impl.Signature,
+		}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d implementations", len(impls)))
+}
+
+// ExecuteQueryImplsOfMethod returns each type's own version of an interface
+// method.
+func ExecuteQueryImplsOfMethod(opts QueryImplsOfMethodOptions) ([]index.MethodImpl, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.FindMethodImplementations(db, opts.Interface, opts.Method)
+}
+
+// QueryHotspotsOptions contains the configuration for query hotspots.
+type QueryHotspotsOptions struct {
+	Root string
+	Top  int
+}
+
+// RunQueryHotspots executes the query hotspots subcommand.
+func RunQueryHotspots(args []string) error {
+	fs := flag.NewFlagSet("query hotspots", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	top := fs.Int("top", 20, "number of hotspots to list (0 = all)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	hotspots, err := ExecuteQueryHotspots(QueryHotspotsOptions{Root: *root, Top: *top})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(hotspots)
+	}
+	if len(hotspots) == 0 {
+		fmt.Println("No functions indexed.")
+		return nil
+	}
+	items := make([]queryItem, len(hotspots))
+	for i, h := range hotspots {
+		note := fmt.Sprintf("score %.2f  complexity %d, %d lines", h.Score, h.Complexity, h.Lines)
+		if h.Churn > 0 {
+			note += fmt.Sprintf(", %d commits", h.Churn)
+		}
+		items[i] = queryItem{Kind: h.Kind, Name: h.Name, File: h.File, Line: h.LineStart, LineEnd: h.LineEnd, Note: note}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d hotspots", len(hotspots)))
+}
+
+// ExecuteQueryHotspots ranks the indexed functions by complexity, size, and
+// git churn, weighted as configured under "hotspots" in palace.jsonc. Outside
+// a git repository churn is left out.
+func ExecuteQueryHotspots(opts QueryHotspotsOptions) ([]index.Hotspot, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openQueryIndex(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	weights := index.DefaultHotspotWeights
+	since := ""
+	if cfg, err := config.LoadPalaceConfig(rootPath); err == nil && cfg.Hotspots != nil {
+		h := cfg.Hotspots
+		if h.ComplexityWeight != 0 || h.SizeWeight != 0 || h.ChurnWeight != 0 {
+			weights = index.HotspotWeights{Complexity: h.ComplexityWeight, Size: h.SizeWeight, Churn: h.ChurnWeight}
+		}
+		since = h.ChurnSince
+	}
+
+	var churn map[string]int
+	if weights.Churn != 0 && gitutil.IsGitRepo(rootPath) {
+		if churn, err = gitutil.FileChurn(rootPath, since); err != nil {
+			churn = nil
+		}
+	}
+	return index.GetHotspots(db, rootPath, churn, weights, opts.Top)
+}
+
+// QueryWeakTestsOptions contains the configuration for query weak-tests.
+type QueryWeakTestsOptions struct {
+	Root string
+}
+
+// RunQueryWeakTests executes the query weak-tests subcommand.
+func RunQueryWeakTests(args []string) error {
+	fs := flag.NewFlagSet("query weak-tests", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	tests, err := ExecuteQueryWeakTests(QueryWeakTestsOptions{Root: *root})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tests)
+	}
+	if len(tests) == 0 {
+		fmt.Println("No tests without assertions found.")
+		return nil
+	}
+	items := make([]queryItem, len(tests))
+	for i, w := range tests {
+		var note string
+		if w.Subtests > 0 {
+			note = fmt.Sprintf("%d subtests", w.Subtests)
+		}
+		items[i] = queryItem{Kind: w.Kind, Name: w.Name, File: w.File, Line: w.Line, Note: note}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d tests without assertions", len(tests)))
+}
+
+// ExecuteQueryWeakTests returns the indexed tests and subtests that make no
+// assertions.
+func ExecuteQueryWeakTests(opts QueryWeakTestsOptions) ([]index.WeakTest, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetWeakTests(db)
+}
+
+// QueryNeighborsOptions contains the configuration for query neighbors.
+type QueryNeighborsOptions struct {
+	Root  string
+	File  string
+	Depth int
+}
+
+// RunQueryNeighbors executes the query neighbors subcommand.
+func RunQueryNeighbors(args []string) error {
+	fs := flag.NewFlagSet("query neighbors", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	depth := fs.Int("depth", 1, "levels of dependencies to follow (1-10)")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace query neighbors <file> [--depth N]"))
+	}
+	// Accept flags after the file too: "neighbors auth/jwt.go --depth 2"
+	file := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return UsageError(err)
+	}
+
+	neighbors, err := ExecuteQueryNeighbors(QueryNeighborsOptions{Root: *root, File: file, Depth: *depth})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(neighbors)
+	}
+	printNeighbors := func(title string, list []index.FileNeighbor) {
+		fmt.Printf(title+":\n", len(list), neighbors.File)
+		for _, n := range list {
+			var kinds []string
+			for _, k := range []struct {
+				n    int
+				name string
+			}{{n.Imports, "imports"}, {n.Calls, "calls"}, {n.References, "references"}} {
+				if k.n > 0 {
+					kinds = append(kinds, fmt.Sprintf("%d %s", k.n, k.name))
+				}
+			}
+			line := fmt.Sprintf("  %s  %d edges (%s)", n.File, n.Edges, strings.Join(kinds, ", "))
+			if n.Via != "" {
+				line += fmt.Sprintf("  depth %d via %s", n.Depth, n.Via)
+			}
+			fmt.Println(line)
+		}
+	}
+	printNeighbors("Upstream: %d files %s depends on", neighbors.Upstream)
+	fmt.Println()
+	printNeighbors("Downstream: %d files depending on %s", neighbors.Downstream)
+	return nil
+}
+
+// ExecuteQueryNeighbors returns the files a file depends on and the files
+// depending on it, within opts.Depth levels.
+func ExecuteQueryNeighbors(opts QueryNeighborsOptions) (*index.FileNeighbors, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openQueryIndex(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	file := opts.File
+	if filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(rootPath, file); err == nil {
+			file = rel
+		}
+	}
+	return index.GetFileNeighbors(db, filepath.ToSlash(file), opts.Depth)
+}
+
+// QueryConfigUsageOptions contains the configuration for query config-usage.
+type QueryConfigUsageOptions struct {
+	Root string
+	Key  string
+}
+
+// RunQueryConfigUsage executes the query config-usage subcommand.
+func RunQueryConfigUsage(args []string) error {
+	fs := flag.NewFlagSet("query config-usage", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace query config-usage <key>"))
+	}
+	// Accept flags after the key too: "config-usage DB_HOST --json"
+	key := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	usages, err := ExecuteQueryConfigUsage(QueryConfigUsageOptions{Root: *root, Key: key})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usages)
+	}
+	if len(usages) == 0 {
+		fmt.Printf("No code reads config key %q.\n", key)
+		return nil
+	}
+	items := make([]queryItem, len(usages))
+	for i, u := range usages {
+		name, kind := u.Symbol, "read"
+		if name == "" {
+			name, kind = u.Key, "config_key"
+		}
+		items[i] = queryItem{Kind: kind, Name: name, File: u.File, Line: u.Line, Note: u.Accessor}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d reads of %s", len(items), key))
+}
+
+// ExecuteQueryConfigUsage returns the reads of a configuration or
+// environment key, with the functions reading it.
+func ExecuteQueryConfigUsage(opts QueryConfigUsageOptions) ([]index.ConfigUsage, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetConfigUsage(db, opts.Key)
+}
+
+// QueryCallsIntoOptions contains the configuration for query calls-into.
+type QueryCallsIntoOptions struct {
+	Root string
+	Dir  string
+}
+
+// RunQueryCallsInto executes the query calls-into subcommand.
+func RunQueryCallsInto(args []string) error {
+	fs := flag.NewFlagSet("query calls-into", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	output := addQueryOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if fs.NArg() == 0 {
+		return UsageError(errors.New("usage: palace query calls-into <dir>"))
+	}
+	// Accept flags after the directory too: "calls-into internal/auth --json"
+	dir := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return UsageError(err)
+	}
+	rich, err := output.richMode()
+	if err != nil {
+		return err
+	}
+
+	callees, err := ExecuteQueryCallsInto(QueryCallsIntoOptions{Root: *root, Dir: dir})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(callees)
+	}
+	if len(callees) == 0 {
+		fmt.Printf("No code outside %s calls into it.\n", dir)
+		return nil
+	}
+	items := make([]queryItem, len(callees))
+	for i, c := range callees {
+		note := fmt.Sprintf("%d callers, %d calls", c.Callers, c.Calls)
+		if c.References > 0 {
+			note += fmt.Sprintf(", %d references", c.References)
+		}
+		items[i] = queryItem{Kind: c.Kind, Name: c.Symbol, File: c.File, Line: c.Line, Note: note}
+	}
+	return renderQueryItems(os.Stdout, *root, rich, items, fmt.Sprintf("%d symbols of %s used from outside it", len(items), dir))
+}
+
+// ExecuteQueryCallsInto returns the symbols inside a directory called or
+// referenced from outside it, with their caller counts.
+func ExecuteQueryCallsInto(opts QueryCallsIntoOptions) ([]index.BoundaryCallee, error) {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openQueryIndex(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	dir := opts.Dir
+	if filepath.IsAbs(dir) {
+		if rel, err := filepath.Rel(rootPath, dir); err == nil {
+			dir = rel
+		}
+	}
+	return index.GetCallsInto(db, filepath.ToSlash(dir))
+}
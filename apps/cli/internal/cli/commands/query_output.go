@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+)
+
+// queryItem is one result of a query subcommand as the shared renderer
+// prints it. Most are symbols; imports, findings, and comment blocks use
+// Kind and Name for what they are.
+type queryItem struct {
+	Kind    string
+	Name    string
+	File    string
+	Line    int
+	LineEnd int    // Set to print a line range
+	Note    string // Short query-specific detail: an annotation, a value, an owner
+
+	// Shown in rich mode only, and looked up in the index when left empty
+	Signature string
+	Doc       string
+}
+
+// stdoutIsTerminal reports whether standard output is a terminal rather
+// than a pipe or file.
+func stdoutIsTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// queryOutputFlags are the --compact and --rich flags every listing query
+// accepts.
+type queryOutputFlags struct {
+	compact *bool
+	rich    *bool
+}
+
+func addQueryOutputFlags(fs *flag.FlagSet) *queryOutputFlags {
+	return &queryOutputFlags{
+		compact: fs.Bool("compact", false, "one line per result (default when output is piped)"),
+		rich:    fs.Bool("rich", false, "signature, doc comment, and calls for each result (default on a terminal)"),
+	}
+}
+
+// richMode resolves the flags to rich (true) or compact output. Without
+// either flag the output is rich on a terminal and compact otherwise, so
+// piping into grep or wc sees one line per result.
+func (f *queryOutputFlags) richMode() (bool, error) {
+	switch {
+	case *f.compact && *f.rich:
+		return false, errors.New("--compact and --rich cannot be used together")
+	case *f.compact:
+		return false, nil
+	case *f.rich:
+		return true, nil
+	}
+	return stdoutIsTerminal(), nil
+}
+
+// renderQueryItems prints items one line each, or in rich mode as a block
+// with the signature, doc comment, calls, and callers of each symbol, read
+// from the index of root. The summary, such as "12 constants", follows the
+// rich listing only: compact output is exactly one line per item.
+func renderQueryItems(w io.Writer, root string, rich bool, items []queryItem, summary string) error {
+	if !rich {
+		for _, it := range items {
+			line := fmt.Sprintf("%s  %s %s", it.location(), it.Kind, it.Name)
+			if it.Note != "" {
+				line += "  " + it.Note
+			}
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	}
+
+	db, err := openQueryIndex(root)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for i, it := range items {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		head := it.Kind + " " + it.Name
+		if it.Note != "" {
+			head += "  " + it.Note
+		}
+		fmt.Fprintln(w, head)
+		fmt.Fprintf(w, "    %s\n", it.location())
+
+		sig, doc, lineEnd, found := lookupQuerySymbol(db, it)
+		if it.Signature != "" {
+			sig = it.Signature
+		}
+		if it.Doc != "" {
+			doc = it.Doc
+		}
+		if sig != "" {
+			fmt.Fprintf(w, "    %s\n", sig)
+		}
+		for _, l := range strings.Split(strings.TrimSpace(doc), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				fmt.Fprintf(w, "    | %s\n", l)
+			}
+		}
+		if found {
+			for _, rel := range querySymbolRelations(db, it, lineEnd) {
+				fmt.Fprintf(w, "    %s\n", rel)
+			}
+		}
+	}
+	if summary != "" && len(items) > 0 {
+		fmt.Fprintf(w, "\n%s\n", summary)
+	}
+	return nil
+}
+
+func (it queryItem) location() string {
+	if it.LineEnd > it.Line {
+		return fmt.Sprintf("%s:%d-%d", it.File, it.Line, it.LineEnd)
+	}
+	return fmt.Sprintf("%s:%d", it.File, it.Line)
+}
+
+// lookupQuerySymbol finds the indexed symbol an item names and returns its
+// signature, doc comment, and last line.
+func lookupQuerySymbol(db *sql.DB, it queryItem) (sig, doc string, lineEnd int, found bool) {
+	err := db.QueryRowContext(context.Background(), `
+		SELECT COALESCE(signature, ''), COALESCE(doc_comment, ''), line_end FROM symbols
+		WHERE file_path = ? AND line_start = ? AND name = ?
+		LIMIT 1;
+	`, it.File, it.Line, it.Name).Scan(&sig, &doc, &lineEnd)
+	return sig, doc, lineEnd, err == nil
+}
+
+// maxRichCalls is how many callees a rich listing names before summarizing.
+const maxRichCalls = 8
+
+// querySymbolRelations describes what a symbol calls and how often it is
+// called. Lookups that fail leave their line out.
+func querySymbolRelations(db *sql.DB, it queryItem, lineEnd int) []string {
+	var lines []string
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT DISTINCT target_symbol FROM relationships
+		WHERE kind = 'call' AND source_file = ? AND line BETWEEN ? AND ?
+		ORDER BY line;
+	`, it.File, it.Line, lineEnd)
+	if err == nil {
+		var callees []string
+		for rows.Next() {
+			var name string
+			if rows.Scan(&name) == nil {
+				callees = append(callees, name)
+			}
+		}
+		rows.Close()
+		if n := len(callees); n > maxRichCalls {
+			callees = append(callees[:maxRichCalls], fmt.Sprintf("and %d more", n-maxRichCalls))
+		}
+		if len(callees) > 0 {
+			lines = append(lines, "calls: "+strings.Join(callees, ", "))
+		}
+	}
+	if n, err := index.GetCallersCount(db, it.Name); err == nil && n > 0 {
+		lines = append(lines, fmt.Sprintf("call sites: %d", n))
+	}
+	return lines
+}
@@ -4,12 +4,15 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/butler"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/util"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
 )
 
@@ -50,8 +53,12 @@ func runRecallList(args []string) error {
 	pending := fs.Bool("pending", false, "show decisions awaiting outcome")
 	since := fs.Int("since", 30, "for --pending: show decisions older than N days")
 	all := fs.Bool("all", false, "for --pending: show all pending regardless of age")
+	sinceLast := fs.Bool("since-last-session", false, "show records created or changed since the last catch-up, then advance it")
+	out := fs.String("out", "", "write the learnings found to this file")
+	copyOut := fs.Bool("copy", false, "copy the learnings found to the clipboard")
+	tmpl := fs.String("template", "", "export format: markdown (default), list, table, json, or a Go template")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Validate inputs
@@ -66,6 +73,10 @@ func runRecallList(args []string) error {
 	if *typeFilter != "" && *typeFilter != "decision" && *typeFilter != "idea" && *typeFilter != "learning" {
 		return fmt.Errorf("invalid --type %q; must be decision, idea, or learning", *typeFilter)
 	}
+	export := *out != "" || *copyOut
+	if export && (*pending || (*typeFilter != "" && *typeFilter != "learning")) {
+		return errors.New("--out and --copy export learnings; they cannot be combined with --pending or another --type")
+	}
 
 	remaining := fs.Args()
 	query := ""
@@ -84,6 +95,28 @@ func runRecallList(args []string) error {
 	}
 	defer mem.Close()
 
+	// Apply stemming/synonym settings (config is optional)
+	palaceCfg, _ := config.LoadPalaceConfig(rootPath)
+	rc := palaceCfg.RecallSettings()
+	mem.SetQueryExpansion(memory.QueryExpansion{Stemming: rc.StemmingEnabled(), Synonyms: rc.Synonyms})
+
+	if export {
+		return ExecuteRecallExport(RecallExportOptions{
+			Memory:    mem,
+			Query:     query,
+			Scope:     *scope,
+			ScopePath: *path,
+			Limit:     *limit,
+			Template:  *tmpl,
+			Out:       *out,
+			Copy:      *copyOut,
+		})
+	}
+
+	if *sinceLast {
+		return recallSinceLastSession(mem, *limit)
+	}
+
 	// Handle --pending flag (decisions awaiting outcome)
 	if *pending {
 		return recallPending(mem, *since, *all, *limit)
@@ -120,6 +153,11 @@ func recallLearnings(mem *memory.Memory, query, scope, scopePath string, limit i
 		fmt.Println("No learnings found.")
 		return nil
 	}
+	ids := make([]string, len(learnings))
+	for i := range learnings {
+		ids[i] = learnings[i].ID
+	}
+	_ = mem.RecordAccess("learning", ids...)
 
 	fmt.Printf("\n📝 Learnings\n")
 	fmt.Println(strings.Repeat("─", 60))
@@ -141,6 +179,55 @@ func recallLearnings(mem *memory.Memory, query, scope, scopePath string, limit i
 	return nil
 }
 
+// RecallExportOptions configures exporting recalled learnings.
+type RecallExportOptions struct {
+	Memory    *memory.Memory
+	Query     string
+	Scope     string
+	ScopePath string
+	Limit     int
+	Template  string // As for the recall MCP tool; "" is markdown
+	Out       string // File to write, if any
+	Copy      bool   // Copy to the clipboard
+}
+
+// ExecuteRecallExport renders the learnings a recall finds with the recall
+// templates and writes them to a file, the clipboard, or both. A missing
+// clipboard is reported as a warning, since the file (or a retry with
+// --out) still gets the results out.
+func ExecuteRecallExport(opts RecallExportOptions) error {
+	var learnings []memory.Learning
+	var err error
+	if opts.Query != "" {
+		learnings, err = opts.Memory.SearchLearnings(opts.Query, opts.Limit)
+	} else {
+		learnings, err = opts.Memory.GetLearnings(opts.Scope, opts.ScopePath, opts.Limit)
+	}
+	if err != nil {
+		return fmt.Errorf("recall learnings: %w", err)
+	}
+
+	text, err := butler.FormatLearnings(learnings, opts.Template)
+	if err != nil {
+		return err
+	}
+
+	if opts.Out != "" {
+		if err := os.WriteFile(opts.Out, []byte(text), 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", opts.Out, err)
+		}
+		fmt.Printf("Wrote %d learnings to %s\n", len(learnings), opts.Out)
+	}
+	if opts.Copy {
+		if err := util.CopyToClipboard(text); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not copy to clipboard: %v\n", err)
+		} else {
+			fmt.Printf("Copied %d learnings to the clipboard\n", len(learnings))
+		}
+	}
+	return nil
+}
+
 // recallDecisions retrieves decisions.
 func recallDecisions(mem *memory.Memory, query, scope, scopePath string, limit int) error {
 	var decisions []memory.Decision
@@ -218,6 +305,11 @@ func recallIdeas(mem *memory.Memory, query, scope, scopePath string, limit int)
 		fmt.Println("No ideas found.")
 		return nil
 	}
+	ids := make([]string, len(ideas))
+	for i := range ideas {
+		ids[i] = ideas[i].ID
+	}
+	_ = mem.RecordAccess("idea", ids...)
 
 	fmt.Printf("\n💡 Ideas\n")
 	fmt.Println(strings.Repeat("─", 60))
@@ -251,6 +343,55 @@ func recallIdeas(mem *memory.Memory, query, scope, scopePath string, limit int)
 	return nil
 }
 
+// recallSinceLastSession shows what changed since the last catch-up, up to
+// limit records of each kind, and advances the marker.
+func recallSinceLastSession(mem *memory.Memory, limit int) error {
+	c, err := mem.CatchUp()
+	if err != nil {
+		return fmt.Errorf("catch up: %w", err)
+	}
+
+	if c.Since.IsZero() {
+		fmt.Printf("\n🕒 Since last session (first session)\n")
+	} else {
+		fmt.Printf("\n🕒 Since last session (%s)\n", c.Since.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Println(strings.Repeat("─", 60))
+	if c.Total() == 0 {
+		fmt.Println("Nothing new since the last session.")
+		return nil
+	}
+
+	printSection := func(title string, n int, line func(i int) string) {
+		if n == 0 {
+			return
+		}
+		fmt.Printf("\n%s (%d)\n", title, n)
+		for i := 0; i < n; i++ {
+			if limit > 0 && i == limit {
+				fmt.Printf("  ...and %d more\n", n-limit)
+				break
+			}
+			fmt.Printf("  %s\n", line(i))
+		}
+	}
+	printSection("📋 Decisions", len(c.Decisions), func(i int) string {
+		d := &c.Decisions[i]
+		return fmt.Sprintf("[%s] %s", d.ID, util.TruncateLine(d.Content, 60))
+	})
+	printSection("📚 Learnings", len(c.Learnings), func(i int) string {
+		l := &c.Learnings[i]
+		return fmt.Sprintf("[%s] %s", l.ID, util.TruncateLine(l.Content, 60))
+	})
+	printSection("💡 Ideas", len(c.Ideas), func(i int) string {
+		idea := &c.Ideas[i]
+		return fmt.Sprintf("[%s] %s", idea.ID, util.TruncateLine(idea.Content, 60))
+	})
+	fmt.Println()
+
+	return nil
+}
+
 // recallPending shows decisions awaiting outcome.
 func recallPending(mem *memory.Memory, since int, all bool, limit int) error {
 	var decisions []memory.Decision
@@ -317,7 +458,7 @@ func runRecallUpdate(args []string) error {
 	root := fs.String("root", ".", "workspace root")
 	note := fs.String("note", "", "optional note about the outcome")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	remaining := fs.Args()
@@ -404,7 +545,7 @@ func runRecallLink(args []string) error {
 	deleteID := fs.String("delete", "", "delete a link by ID")
 
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	rootPath, err := filepath.Abs(*root)
@@ -453,7 +594,7 @@ func runRecallLink(args []string) error {
 	// Handle list operations
 	if *listSource || *listTarget || *listAll {
 		if len(remaining) == 0 {
-			return errors.New("usage: palace recall link --from|--to|--all <record-id>")
+			return UsageError(errors.New("usage: palace recall link --from|--to|--all <record-id>"))
 		}
 		recordID := remaining[0]
 
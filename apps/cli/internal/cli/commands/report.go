@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "report",
+		Description: "Report quality metrics of the indexed code",
+		Run:         RunReport,
+	})
+}
+
+// RunReport dispatches to the appropriate report subcommand.
+func RunReport(args []string) error {
+	if len(args) == 0 {
+		return UsageError(errors.New(`usage: palace report <command>
+
+Commands:
+  doc-coverage  Share of exported symbols with a doc comment, by language
+
+Examples:
+  palace report doc-coverage
+  palace report doc-coverage --list --min-coverage 80`))
+	}
+
+	switch args[0] {
+	case "doc-coverage":
+		return RunReportDocCoverage(args[1:])
+	default:
+		return UsageError(fmt.Errorf("unknown report command: %s\nRun 'palace help report' for usage", args[0]))
+	}
+}
+
+// ReportDocCoverageOptions contains the configuration for report doc-coverage.
+type ReportDocCoverageOptions struct {
+	Root string
+}
+
+// RunReportDocCoverage executes the report doc-coverage subcommand.
+func RunReportDocCoverage(args []string) error {
+	fs := flag.NewFlagSet("report doc-coverage", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	list := fs.Bool("list", false, "list the undocumented exported symbols")
+	minCoverage := fs.Float64("min-coverage", 0, "fail with exit code 3 when overall coverage is below this percentage")
+	jsonOut := fs.Bool("json", false, "output as JSON")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	if *minCoverage < 0 || *minCoverage > 100 {
+		return UsageError(errors.New("--min-coverage must be between 0 and 100"))
+	}
+
+	report, err := ExecuteReportDocCoverage(ReportDocCoverageOptions{Root: *root})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOut {
+		if !*list {
+			report.Undocumented = nil
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printDocCoverage(report, *list)
+	}
+
+	if report.Percent < *minCoverage {
+		return findingsError("documentation coverage %.1f%% is below --min-coverage %.1f%%", report.Percent, *minCoverage)
+	}
+	return nil
+}
+
+// ExecuteReportDocCoverage measures how many exported symbols are
+// documented.
+func ExecuteReportDocCoverage(opts ReportDocCoverageOptions) (*index.DocCoverageReport, error) {
+	db, err := openQueryIndex(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return index.GetDocCoverage(db)
+}
+
+func printDocCoverage(report *index.DocCoverageReport, list bool) {
+	fmt.Printf("Documentation coverage: %.1f%% (%d of %d exported symbols)\n", report.Percent, report.Documented, report.Exported)
+	if len(report.Languages) > 0 {
+		fmt.Println()
+		for _, name := range report.SortedLanguages() {
+			lang := report.Languages[name]
+			if name == "" {
+				name = "unknown"
+			}
+			fmt.Printf("  %-12s %5.1f%%  (%d/%d)\n", name, lang.Percent, lang.Documented, lang.Exported)
+		}
+	}
+	if !list || len(report.Undocumented) == 0 {
+		return
+	}
+	fmt.Printf("\nUndocumented exported symbols:\n")
+	for _, s := range report.Undocumented {
+		fmt.Printf("  %s:%d  %s %s\n", s.File, s.Line, s.Kind, s.Name)
+	}
+}
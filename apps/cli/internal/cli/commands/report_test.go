@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func TestRunReportUnknownCommand(t *testing.T) {
+	if err := RunReport([]string{"bogus"}); ExitCode(err) != ExitUsage || !strings.Contains(err.Error(), "unknown report command") {
+		t.Errorf("expected unknown command usage error, got %v", err)
+	}
+}
+
+func TestExecuteReportDocCoverage(t *testing.T) {
+	root := t.TempDir()
+	src := `package lib
+
+// Documented does something useful.
+func Documented() {}
+
+func Undocumented() {}
+
+func internal() {}
+`
+	if err := os.WriteFile(filepath.Join(root, "lib.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "config.json"), []byte(`{"name": "lib"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := scan.Run(root); err != nil {
+		t.Fatalf("scan.Run() error: %v", err)
+	}
+
+	report, err := ExecuteReportDocCoverage(ReportDocCoverageOptions{Root: root})
+	if err != nil {
+		t.Fatalf("ExecuteReportDocCoverage() error: %v", err)
+	}
+	if report.Exported != 2 || report.Documented != 1 || report.Percent != 50 {
+		t.Errorf("expected 1 of 2 exported symbols documented (50%%), got %+v", report.DocCoverage)
+	}
+	if got := report.Languages["go"]; got.Percent != 50 || len(report.Languages) != 1 {
+		t.Errorf("expected only go at 50%%, got %+v", report.Languages)
+	}
+	if len(report.Undocumented) != 1 || report.Undocumented[0].Name != "Undocumented" || report.Undocumented[0].Line != 6 {
+		t.Errorf("expected Undocumented at lib.go:6, got %+v", report.Undocumented)
+	}
+
+	err = RunReportDocCoverage([]string{"--root", root, "--min-coverage", "80"})
+	if ExitCode(err) != ExitFindings {
+		t.Errorf("coverage below --min-coverage should exit with %d, got %v", ExitFindings, err)
+	}
+	if err := RunReportDocCoverage([]string{"--root", root, "--min-coverage", "50"}); err != nil {
+		t.Errorf("coverage at --min-coverage should pass, got %v", err)
+	}
+}
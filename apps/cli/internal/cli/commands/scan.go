@@ -3,16 +3,22 @@ package commands
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/gitutil"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/limiter"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/logger"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
 )
@@ -27,12 +33,32 @@ func init() {
 
 // ScanOptions contains the configuration for the scan command.
 type ScanOptions struct {
-	Root        string
-	Full        bool
-	Incremental bool // Force git-based incremental scan
-	Deep        bool // Enable deep analysis (LSP-based call tracking for Dart)
-	Verbose     bool // Show detailed progress
-	Debug       bool // Show debug information
+	Root            string
+	Full            bool
+	Incremental     bool          // Force git-based incremental scan
+	Deep            bool          // Enable deep analysis (LSP-based call tracking for Dart)
+	Verbose         bool          // Show detailed progress
+	Debug           bool          // Show debug information
+	Timeout         time.Duration // Cancel the scan after this long (0 = no limit)
+	ScanSecrets     bool          // Record likely hardcoded secrets as findings
+	Profile         bool          // Print a timing breakdown per phase and language
+	ProfileOut      string        // Write the timing breakdown as JSON to this file
+	GenericFallback bool          // Extract low-confidence symbols from unsupported languages
+	TestsOnly       bool          // Index only test files
+	NoTests         bool          // Index only non-test files
+	LimitDepth      bool          // Apply MaxDepth
+	MaxDepth        int           // Deepest directory level indexed, the root being 0
+	Blame           bool          // Attribute symbols to owners with git blame
+	Strict          bool          // Rescan everything and fail if any file gave analysis warnings
+	RepairIndex     bool          // Rebuild the index from scratch and swap it in once verified
+	Jobs            int           // Concurrent parse jobs (0 = number of CPUs)
+	LSPConcurrency  int           // Concurrent language server requests in deep analysis (0 = 1)
+	MaxMemory       uint64        // Resident memory in bytes above which new work waits (0 = no limit)
+}
+
+// filtered reports whether opts narrow the scan to part of the tree.
+func (opts ScanOptions) filtered() bool {
+	return opts.TestsOnly || opts.NoTests || opts.LimitDepth
 }
 
 // RunScan executes the scan command with parsed arguments.
@@ -44,23 +70,71 @@ func RunScan(args []string) error {
 	deep := fs.Bool("deep", false, "enable deep analysis (LSP-based call tracking for Dart/Flutter)")
 	verbose := flags.AddVerboseFlag(fs)
 	debug := fs.Bool("debug", false, "show debug information")
+	timeout := fs.Duration("timeout", 0, "cancel the scan after this long, e.g. 5m (0 = no limit)")
+	scanSecrets := fs.Bool("scan-secrets", false, "record likely hardcoded secrets in string literals")
+	profile := fs.Bool("profile", false, "print a timing breakdown per phase and language")
+	profileOut := fs.String("profile-out", "", "write the timing breakdown as JSON to this file")
+	genericFallback := fs.Bool("generic-fallback", false, "extract low-confidence symbols from text files in unsupported languages")
+	testsOnly := fs.Bool("tests-only", false, "index only test files")
+	noTests := fs.Bool("no-tests", false, "leave test files out of the index")
+	maxDepth := fs.Int("max-depth", 0, "index only files at most this many directories below the root (root = 0)")
+	blame := fs.Bool("blame", false, "attribute symbols to their owners with git blame (slow)")
+	strict := fs.Bool("strict", false, "rescan every file and exit with code 3 if any gave analysis warnings")
+	repairIndex := fs.Bool("repair-index", false, "discard the index, rebuild it from scratch, and replace the old one once verified")
+	jobs := fs.Int("jobs", 0, "parse at most this many files at once (default: number of CPUs)")
+	lspConcurrency := fs.Int("lsp-concurrency", 0, "send at most this many requests at once to language servers during deep analysis (default: 1)")
+	maxMemory := fs.String("max-memory", "", "pause new work while the process uses more memory than this, e.g. 2GB (default: no limit)")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
+	}
+	var memLimit uint64
+	if *maxMemory != "" {
+		var err error
+		if memLimit, err = limiter.ParseSize(*maxMemory); err != nil {
+			return UsageError(fmt.Errorf("--max-memory: %w", err))
+		}
 	}
+	limitDepth := false
+	fs.Visit(func(f *flag.Flag) { limitDepth = limitDepth || f.Name == "max-depth" })
 
 	return ExecuteScan(ScanOptions{
-		Root:        *root,
-		Full:        *full,
-		Incremental: *incremental,
-		Deep:        *deep,
-		Verbose:     *verbose,
-		Debug:       *debug,
+		Root:            *root,
+		Full:            *full,
+		Incremental:     *incremental,
+		Deep:            *deep,
+		Verbose:         *verbose,
+		Debug:           *debug,
+		Timeout:         *timeout,
+		ScanSecrets:     *scanSecrets,
+		Profile:         *profile,
+		ProfileOut:      *profileOut,
+		GenericFallback: *genericFallback,
+		TestsOnly:       *testsOnly,
+		NoTests:         *noTests,
+		LimitDepth:      limitDepth,
+		MaxDepth:        *maxDepth,
+		Blame:           *blame,
+		Strict:          *strict,
+		RepairIndex:     *repairIndex,
+		Jobs:            *jobs,
+		LSPConcurrency:  *lspConcurrency,
+		MaxMemory:       memLimit,
 	})
 }
 
 // ExecuteScan performs the scan with the given options.
 // This is separated for easier testing.
 func ExecuteScan(opts ScanOptions) error {
+	if opts.TestsOnly && opts.NoTests {
+		return UsageError(errors.New("--tests-only and --no-tests cannot be used together"))
+	}
+	if opts.RepairIndex && (opts.Strict || opts.Incremental) {
+		return UsageError(errors.New("--repair-index cannot be used with --strict or --incremental"))
+	}
+	if opts.Jobs < 0 || opts.LSPConcurrency < 0 {
+		return UsageError(errors.New("--jobs and --lsp-concurrency cannot be negative"))
+	}
+
 	// Set logging level
 	if opts.Debug {
 		logger.SetLevel(logger.LevelDebug)
@@ -68,17 +142,102 @@ func ExecuteScan(opts ScanOptions) error {
 		logger.SetLevel(logger.LevelInfo)
 	}
 
+	if opts.ScanSecrets {
+		analysis.SetFindSecrets(true)
+		defer analysis.SetFindSecrets(false)
+	}
+	if opts.GenericFallback {
+		analysis.SetGenericFallback(true)
+		defer analysis.SetGenericFallback(false)
+	}
+	if opts.TestsOnly || opts.NoTests {
+		filter := index.TestsOnly
+		if opts.NoTests {
+			filter = index.NoTests
+		}
+		index.SetTestFilter(filter)
+		defer index.SetTestFilter(index.AllFiles)
+	}
+	if opts.LimitDepth {
+		if opts.MaxDepth < 0 {
+			return UsageError(fmt.Errorf("--max-depth must be at least 0, got %d", opts.MaxDepth))
+		}
+		index.SetMaxDepth(opts.MaxDepth)
+		defer index.SetMaxDepth(-1)
+	}
+	if opts.Blame {
+		if gitutil.IsGitRepo(opts.Root) {
+			index.SetBlameSource(index.GitBlame{})
+			defer index.SetBlameSource(nil)
+		} else {
+			fmt.Fprintln(os.Stderr, "warning: --blame needs a git repository; symbols will have no owners")
+		}
+	}
+
+	// Ctrl-C or the timeout stops the scan between files; whatever was
+	// indexed by then is kept and the scan is marked partial.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	ctx = limiter.WithManager(ctx, limiter.New(limiter.Limits{
+		Jobs:      opts.Jobs,
+		LSP:       opts.LSPConcurrency,
+		MaxMemory: opts.MaxMemory,
+	}))
+
+	var profile *index.ScanProfile
+	if opts.Profile || opts.ProfileOut != "" {
+		profile = index.NewScanProfile()
+		ctx = index.WithScanProfile(ctx, profile)
+	}
+
+	err := runScan(ctx, opts)
+	if err == nil && opts.LimitDepth {
+		reportDepthSkipped(opts)
+	}
+	if profile != nil {
+		if perr := reportScanProfile(profile.Report(), opts); perr != nil && err == nil {
+			err = perr
+		}
+	}
+	return err
+}
+
+// runScan runs the scan selected by opts, followed by deep analysis when it
+// applies.
+func runScan(ctx context.Context, opts ScanOptions) error {
 	var err error
+	warnings := 0
 	switch {
+	case opts.RepairIndex:
+		err = repairIndex(ctx, opts.Root)
+		if scanCancelled(err) {
+			return fmt.Errorf("%w\nthe index was not replaced", err)
+		}
+	case opts.Strict:
+		// Only a full scan analyzes every file, so only it sees every warning
+		warnings, err = fullScan(ctx, opts.Root)
 	case opts.Full:
-		err = executeFullScan(opts.Root)
+		err = executeFullScan(ctx, opts.Root)
 	case opts.Incremental:
-		err = executeGitIncrementalScan(opts.Root)
+		err = executeGitIncrementalScan(ctx, opts.Root)
+	case opts.filtered():
+		// Compare the whole tree against the index, so files indexed by an
+		// earlier scan that the filters now exclude are removed
+		err = executeIncrementalScan(ctx, opts.Root)
 	default:
 		// Auto-detect: try git-based if available, fall back to hash-based
-		err = executeAutoIncrementalScan(opts.Root)
+		err = executeAutoIncrementalScan(ctx, opts.Root)
 	}
 
+	if scanCancelled(err) {
+		return fmt.Errorf("%w\nthe index is partial; run 'palace scan' again to finish it", err)
+	}
 	if err != nil {
 		return err
 	}
@@ -87,9 +246,56 @@ func ExecuteScan(opts ScanOptions) error {
 	// unless explicitly disabled with --deep=false
 	rootPath, _ := filepath.Abs(opts.Root)
 	if opts.Deep || isDartFlutterProject(rootPath) {
-		return executeDeepAnalysis(opts.Root)
+		stopPhase := index.ScanProfileFrom(ctx).Start(index.PhaseDeepAnalysis)
+		err = executeDeepAnalysis(ctx, opts.Root)
+		stopPhase()
+		if err != nil {
+			return err
+		}
+	}
+
+	if warnings > 0 {
+		return findingsError("scan reported %d analysis warnings (--strict)", warnings)
 	}
+	return nil
+}
 
+// reportDepthSkipped prints how many directories --max-depth kept the scan
+// from walking. It must run before the depth limit is cleared.
+func reportDepthSkipped(opts ScanOptions) {
+	if n := index.DepthPruned(); n > 0 {
+		fmt.Printf("skipped %d directories deeper than --max-depth %d\n", n, opts.MaxDepth)
+	}
+}
+
+// reportScanProfile prints the profile when --profile is set and writes it
+// as JSON when --profile-out is set.
+func reportScanProfile(report index.ScanProfileReport, opts ScanOptions) error {
+	if opts.Profile {
+		fmt.Printf("\nscan profile (total %v):\n", report.Total.Round(time.Millisecond))
+		for _, p := range report.Phases {
+			share := 0.0
+			if report.Total > 0 {
+				share = float64(p.Duration) / float64(report.Total) * 100
+			}
+			fmt.Printf("  %-14s %10v  %5.1f%%\n", p.Name, p.Duration.Round(time.Microsecond), share)
+		}
+		if len(report.Languages) > 0 {
+			fmt.Println("parse time by language (summed across workers):")
+			for _, l := range report.Languages {
+				fmt.Printf("  %-14s %10v  %d files\n", l.Language, l.Duration.Round(time.Microsecond), l.Files)
+			}
+		}
+	}
+	if opts.ProfileOut != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(opts.ProfileOut, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("write profile: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -120,29 +326,61 @@ func isDartFlutterProject(rootPath string) bool {
 	return false
 }
 
-func executeFullScan(root string) error {
-	summary, fileCount, err := scan.Run(root)
+// scanCancelled reports whether err comes from an interrupted or timed-out scan.
+func scanCancelled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func executeFullScan(ctx context.Context, root string) error {
+	_, err := fullScan(ctx, root)
+	return err
+}
+
+// fullScan rescans every file and returns how many analysis warnings the
+// scan reported.
+func fullScan(ctx context.Context, root string) (int, error) {
+	summary, fileCount, err := scan.RunContext(ctx, root)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	fmt.Printf("full scan: indexed %d files, %d symbols, %d relationships\n", fileCount, summary.SymbolCount, summary.RelationshipCount)
 	fmt.Printf("scan hash: %s\n", summary.ScanHash)
-	fmt.Printf("scan artifact written to %s\n", filepath.Join(summary.Root, ".palace", "index", "scan.json"))
+	for _, w := range summary.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	fmt.Printf("scan artifact written to %s\n", filepath.Join(config.ResolveStore(summary.Root).IndexDir, "scan.json"))
+	return len(summary.Warnings), nil
+}
+
+// repairIndex rebuilds the index from scratch and replaces the old one.
+func repairIndex(ctx context.Context, root string) error {
+	summary, fileCount, err := scan.RunRepair(ctx, root)
+	if err != nil {
+		return fmt.Errorf("repair index: %w", err)
+	}
+	fmt.Printf("index repaired: indexed %d files, %d symbols, %d relationships\n", fileCount, summary.SymbolCount, summary.RelationshipCount)
+	fmt.Printf("scan hash: %s\n", summary.ScanHash)
+	for _, w := range summary.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
 	return nil
 }
 
-func executeIncrementalScan(root string) error {
-	summary, err := scan.RunIncremental(root)
+func executeIncrementalScan(ctx context.Context, root string) error {
+	summary, err := scan.RunIncrementalContext(ctx, root)
+	if scanCancelled(err) {
+		return err
+	}
 	if err != nil {
 		// If no index exists, fall back to full scan silently
 		// This is normal for fresh projects - no need for a warning
 		if strings.Contains(err.Error(), "no index found") {
-			return executeFullScan(root)
+			return executeFullScan(ctx, root)
 		}
 		// For other errors, show warning and try full scan
 		fmt.Fprintf(os.Stderr, "incremental scan failed: %v\n", err)
 		fmt.Fprintf(os.Stderr, "falling back to full scan...\n")
-		return executeFullScan(root)
+		return executeFullScan(ctx, root)
 	}
 
 	totalChanges := summary.FilesAdded + summary.FilesModified + summary.FilesDeleted
@@ -156,25 +394,28 @@ func executeIncrementalScan(root string) error {
 	return nil
 }
 
-func executeGitIncrementalScan(root string) error {
-	summary, err := scan.RunIncrementalGit(root)
+func executeGitIncrementalScan(ctx context.Context, root string) error {
+	summary, err := scan.RunIncrementalGitContext(ctx, root)
+	if scanCancelled(err) {
+		return err
+	}
 	if err != nil {
 		// If no index or not a git repo, fall back to hash-based
 		if strings.Contains(err.Error(), "no index found") {
-			return executeFullScan(root)
+			return executeFullScan(ctx, root)
 		}
 		if strings.Contains(err.Error(), "not a git repository") {
 			fmt.Fprintf(os.Stderr, "not a git repository, using hash-based incremental scan\n")
-			return executeIncrementalScan(root)
+			return executeIncrementalScan(ctx, root)
 		}
 		if strings.Contains(err.Error(), "no previous git-based scan") {
 			fmt.Fprintf(os.Stderr, "no previous git scan found, using hash-based incremental scan\n")
-			return executeIncrementalScan(root)
+			return executeIncrementalScan(ctx, root)
 		}
 		// For other errors, show warning and try hash-based
 		fmt.Fprintf(os.Stderr, "git-based scan failed: %v\n", err)
 		fmt.Fprintf(os.Stderr, "falling back to hash-based incremental scan...\n")
-		return executeIncrementalScan(root)
+		return executeIncrementalScan(ctx, root)
 	}
 
 	totalChanges := summary.FilesAdded + summary.FilesModified + summary.FilesDeleted
@@ -188,9 +429,12 @@ func executeGitIncrementalScan(root string) error {
 	return nil
 }
 
-func executeAutoIncrementalScan(root string) error {
+func executeAutoIncrementalScan(ctx context.Context, root string) error {
 	// Try git-based incremental first if available
-	summary, err := scan.RunIncrementalGit(root)
+	summary, err := scan.RunIncrementalGitContext(ctx, root)
+	if scanCancelled(err) {
+		return err
+	}
 	if err == nil {
 		totalChanges := summary.FilesAdded + summary.FilesModified + summary.FilesDeleted
 		if totalChanges == 0 {
@@ -204,11 +448,11 @@ func executeAutoIncrementalScan(root string) error {
 	}
 
 	// Fall back to hash-based incremental scan
-	return executeIncrementalScan(root)
+	return executeIncrementalScan(ctx, root)
 }
 
 // executeDeepAnalysis runs LSP-based deep analysis for Dart/Flutter projects
-func executeDeepAnalysis(root string) error {
+func executeDeepAnalysis(ctx context.Context, root string) error {
 	rootPath, err := filepath.Abs(root)
 	if err != nil {
 		return err
@@ -260,7 +504,7 @@ func executeDeepAnalysis(root string) error {
 	defer analyzer.Close()
 
 	// Get list of Dart files from the index
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	db, err := index.Open(dbPath)
 	if err != nil {
 		return fmt.Errorf("open index: %w", err)
@@ -280,7 +524,7 @@ func executeDeepAnalysis(root string) error {
 	fmt.Printf("analyzing %d Dart files for call relationships...\n", len(dartFiles))
 
 	// Extract calls using quick scan (public symbols only for speed)
-	calls, err := analyzer.QuickCallScan(dartFiles, func(current, total int, file string) {
+	calls, err := analyzer.QuickCallScanContext(ctx, dartFiles, func(current, total int, file string) {
 		if current%10 == 0 || current == total {
 			relFile := file
 			if strings.HasPrefix(file, rootPath) {
@@ -3,7 +3,10 @@ package commands
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
 )
 
 func TestRunScanInvalidFlag(t *testing.T) {
@@ -87,3 +90,156 @@ func TestExecuteScanIncrementalNoChanges(t *testing.T) {
 		t.Fatalf("Second ExecuteScan() error: %v", err)
 	}
 }
+
+func TestExecuteScanTestFilters(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\nfunc main() {}\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "main_test.go"), []byte("package main\nfunc TestMain() {}\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "app.spec.ts"), []byte("function specHelper() {}\n"), 0o644)
+
+	indexed := func() []string {
+		t.Helper()
+		db, err := index.Open(filepath.Join(root, ".palace", "index", "palace.db"))
+		if err != nil {
+			t.Fatalf("open index: %v", err)
+		}
+		defer db.Close()
+		rows, err := db.Query("SELECT path FROM files ORDER BY path")
+		if err != nil {
+			t.Fatalf("query files: %v", err)
+		}
+		defer rows.Close()
+		var paths []string
+		for rows.Next() {
+			var p string
+			rows.Scan(&p)
+			paths = append(paths, p)
+		}
+		return paths
+	}
+
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true, NoTests: true}); err != nil {
+		t.Fatalf("ExecuteScan(NoTests) error: %v", err)
+	}
+	if got := strings.Join(indexed(), ","); got != "main.go" {
+		t.Errorf("--no-tests indexed %q, want main.go", got)
+	}
+
+	// Without --full the filter still replaces what the index holds
+	if err := ExecuteScan(ScanOptions{Root: root, TestsOnly: true}); err != nil {
+		t.Fatalf("ExecuteScan(TestsOnly) error: %v", err)
+	}
+	if got := strings.Join(indexed(), ","); got != "app.spec.ts,main_test.go" {
+		t.Errorf("--tests-only indexed %q, want app.spec.ts,main_test.go", got)
+	}
+
+	if err := ExecuteScan(ScanOptions{Root: root, TestsOnly: true, NoTests: true}); err == nil {
+		t.Error("expected error for --tests-only with --no-tests")
+	}
+}
+
+func TestExecuteScanMaxDepth(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	for _, rel := range []string{"main.go", "a/a.go", "a/b/b.go", "a/b/c/c.go"} {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte("package x\nfunc F() {}\n"), 0o644)
+	}
+
+	indexed := func() string {
+		t.Helper()
+		db, err := index.Open(filepath.Join(root, ".palace", "index", "palace.db"))
+		if err != nil {
+			t.Fatalf("open index: %v", err)
+		}
+		defer db.Close()
+		rows, err := db.Query("SELECT path FROM files WHERE path LIKE '%.go' ORDER BY path")
+		if err != nil {
+			t.Fatalf("query files: %v", err)
+		}
+		defer rows.Close()
+		var paths []string
+		for rows.Next() {
+			var p string
+			rows.Scan(&p)
+			paths = append(paths, p)
+		}
+		return strings.Join(paths, ",")
+	}
+
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true, LimitDepth: true, MaxDepth: 1}); err != nil {
+		t.Fatalf("ExecuteScan(MaxDepth 1) error: %v", err)
+	}
+	if got := indexed(); got != "a/a.go,main.go" {
+		t.Errorf("--max-depth 1 indexed %q, want a/a.go,main.go", got)
+	}
+	if got := index.DepthPruned(); got != 1 {
+		t.Errorf("--max-depth 1 pruned %d directories, want 1 (a/b)", got)
+	}
+
+	if err := ExecuteScan(ScanOptions{Root: root, LimitDepth: true, MaxDepth: 0}); err != nil {
+		t.Fatalf("ExecuteScan(MaxDepth 0) error: %v", err)
+	}
+	if got := indexed(); got != "main.go" {
+		t.Errorf("--max-depth 0 indexed %q, want main.go", got)
+	}
+
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true}); err != nil {
+		t.Fatalf("ExecuteScan(Full) error: %v", err)
+	}
+	if got := indexed(); got != "a/a.go,a/b/b.go,a/b/c/c.go,main.go" {
+		t.Errorf("unlimited scan indexed %q", got)
+	}
+}
+
+func TestExecuteScanStoresLowConfidence(t *testing.T) {
+	root := t.TempDir()
+	if err := ExecuteInit(InitOptions{Root: root, NoScan: true}); err != nil {
+		t.Fatalf("ExecuteInit() error: %v", err)
+	}
+	os.WriteFile(filepath.Join(root, "hello.zork"), []byte("proc greet(names) {\n  say(names)\n}\n"), 0o644)
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644)
+
+	if err := ExecuteScan(ScanOptions{Root: root, Full: true, GenericFallback: true}); err != nil {
+		t.Fatalf("ExecuteScan() error: %v", err)
+	}
+	db, err := index.Open(filepath.Join(root, ".palace", "index", "palace.db"))
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer db.Close()
+
+	greet, err := index.GetSymbol(db, "greet", "hello.zork")
+	if err != nil {
+		t.Fatalf("GetSymbol(greet) error: %v", err)
+	}
+	if !greet.LowConfidence {
+		t.Errorf("generic fallback symbol read back without LowConfidence: %+v", greet)
+	}
+	main, err := index.GetSymbol(db, "main", "main.go")
+	if err != nil {
+		t.Fatalf("GetSymbol(main) error: %v", err)
+	}
+	if main.LowConfidence {
+		t.Errorf("tree-sitter symbol read back as low confidence: %+v", main)
+	}
+
+	// Incremental scans store the flag too
+	os.WriteFile(filepath.Join(root, "hello.zork"), []byte("proc wave(names) {\n  say(names)\n}\n"), 0o644)
+	if err := ExecuteScan(ScanOptions{Root: root, GenericFallback: true}); err != nil {
+		t.Fatalf("incremental ExecuteScan() error: %v", err)
+	}
+	wave, err := index.GetSymbol(db, "wave", "hello.zork")
+	if err != nil {
+		t.Fatalf("GetSymbol(wave) error: %v", err)
+	}
+	if !wave.LowConfidence {
+		t.Errorf("incrementally scanned symbol read back without LowConfidence: %+v", wave)
+	}
+}
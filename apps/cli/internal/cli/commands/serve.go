@@ -9,6 +9,7 @@ import (
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/butler"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
 )
 
@@ -32,7 +33,7 @@ func RunServe(args []string) error {
 	root := flags.AddRootFlag(fs)
 	mode := fs.String("mode", "agent", "MCP mode: 'agent' (restricted, default) or 'human' (full access)")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	return ExecuteServe(ServeOptions{Root: *root, Mode: *mode})
@@ -51,7 +52,7 @@ func ExecuteServe(opts ServeOptions) error {
 	}
 	mcpMode := butler.MCPMode(opts.Mode)
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); err != nil {
 		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
 	}
@@ -66,6 +67,15 @@ func ExecuteServe(opts ServeOptions) error {
 		return fmt.Errorf("initialize butler: %w", err)
 	}
 
+	if cfg := b.Config(); cfg != nil && cfg.Forget != nil && cfg.Forget.OnStartup && b.Memory() != nil {
+		archived, err := b.Memory().ApplyForgetPolicy(forgetPolicyFromConfig(cfg.Forget))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: forget policy failed: %v\n", err)
+		} else if len(archived) > 0 {
+			fmt.Fprintf(os.Stderr, "Forget policy archived %d low-value records.\n", len(archived))
+		}
+	}
+
 	server := butler.NewMCPServerWithMode(b, mcpMode)
 
 	modeDesc := "agent (restricted)"
@@ -49,7 +49,7 @@ Examples:
 	case "show":
 		return RunSessionShow(args[1:])
 	default:
-		return fmt.Errorf("unknown session command: %s\nRun 'palace help session' for usage", args[0])
+		return UsageError(fmt.Errorf("unknown session command: %s\nRun 'palace help session' for usage", args[0]))
 	}
 }
 
@@ -69,7 +69,7 @@ func RunSessionStart(args []string) error {
 	agentID := fs.String("agent-id", "", "unique agent instance ID")
 	goal := fs.String("goal", "", "session goal")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Use remaining args as goal if not set via flag
@@ -127,12 +127,12 @@ func RunSessionEnd(args []string) error {
 	state := fs.String("state", "completed", "final state (completed, abandoned)")
 	summary := fs.String("summary", "", "session summary")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	remaining := fs.Args()
 	if len(remaining) == 0 {
-		return errors.New("usage: palace session end SESSION_ID [--state completed|abandoned] [--summary \"...\"]")
+		return UsageError(errors.New("usage: palace session end SESSION_ID [--state completed|abandoned] [--summary \"...\"]"))
 	}
 
 	return ExecuteSessionEnd(SessionEndOptions{
@@ -178,7 +178,7 @@ func RunSessionList(args []string) error {
 	active := fs.Bool("active", false, "show only active sessions")
 	limit := flags.AddLimitFlag(fs, 10)
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Validate inputs
@@ -250,12 +250,12 @@ func RunSessionShow(args []string) error {
 	fs := flag.NewFlagSet("session show", flag.ContinueOnError)
 	root := flags.AddRootFlag(fs)
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	remaining := fs.Args()
 	if len(remaining) == 0 {
-		return errors.New("usage: palace session show SESSION_ID")
+		return UsageError(errors.New("usage: palace session show SESSION_ID"))
 	}
 
 	return ExecuteSessionShow(SessionShowOptions{
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
 )
@@ -25,7 +26,8 @@ func init() {
 
 // StatsOptions contains the configuration for the stats command.
 type StatsOptions struct {
-	Root string
+	Root      string
+	Recompute bool // Recount the index from scratch and repair the running counts
 }
 
 // IndexStats holds statistics about the indexed codebase.
@@ -53,12 +55,14 @@ type KnowledgeStats struct {
 func RunStats(args []string) error {
 	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
 	root := flags.AddRootFlag(fs)
+	recompute := fs.Bool("recompute", false, "recount the index from scratch and repair the running counts")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	return ExecuteStats(StatsOptions{
-		Root: *root,
+		Root:      *root,
+		Recompute: *recompute,
 	})
 }
 
@@ -74,7 +78,7 @@ func ExecuteStats(opts StatsOptions) error {
 	fmt.Println(strings.Repeat("=", 50))
 
 	// Index statistics
-	indexStats, err := getIndexStats(rootPath)
+	indexStats, err := getIndexStats(rootPath, opts.Recompute)
 	if err != nil {
 		fmt.Printf("\nIndex: not available (%v)\n", err)
 	} else {
@@ -137,9 +141,11 @@ func ExecuteStats(opts StatsOptions) error {
 	return nil
 }
 
-// getIndexStats retrieves statistics from the index database.
-func getIndexStats(rootPath string) (*IndexStats, error) {
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+// getIndexStats retrieves statistics from the index database. The counts
+// are the running counts kept as files are indexed; with recompute they are
+// recounted from the tables first, and any drift is reported and repaired.
+func getIndexStats(rootPath string, recompute bool) (*IndexStats, error) {
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("no index found")
 	}
@@ -151,58 +157,29 @@ func getIndexStats(rootPath string) (*IndexStats, error) {
 	defer db.Close()
 
 	ctx := context.Background()
-	stats := &IndexStats{
-		SymbolsByKind:       make(map[string]int),
-		RelationshipsByKind: make(map[string]int),
-	}
-
-	// Count files
-	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM files").Scan(&stats.FileCount); err != nil {
-		return nil, fmt.Errorf("count files: %w", err)
-	}
-
-	// Count chunks
-	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM chunks").Scan(&stats.ChunkCount); err != nil {
-		return nil, fmt.Errorf("count chunks: %w", err)
-	}
-
-	// Count symbols
-	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM symbols").Scan(&stats.SymbolCount); err != nil {
-		return nil, fmt.Errorf("count symbols: %w", err)
+	counts, err := index.GetStats(db)
+	if err != nil {
+		return nil, err
 	}
-
-	// Count symbols by kind
-	rows, err := db.QueryContext(ctx, "SELECT kind, COUNT(*) FROM symbols GROUP BY kind ORDER BY COUNT(*) DESC")
-	if err == nil {
-		defer rows.Close()
-		for rows.Next() {
-			var kind string
-			var count int
-			if err := rows.Scan(&kind, &count); err == nil {
-				stats.SymbolsByKind[kind] = count
-			}
+	if recompute {
+		var before *index.Stats
+		if before, counts, err = index.RecomputeStats(db); err != nil {
+			return nil, err
 		}
-		_ = rows.Err() // Check for iteration errors
-	}
-
-	// Count relationships
-	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM relationships").Scan(&stats.RelationshipCount); err != nil {
-		// Relationships table might not exist in older schemas
-		stats.RelationshipCount = 0
-	}
-
-	// Count relationships by kind
-	rows, err = db.QueryContext(ctx, "SELECT kind, COUNT(*) FROM relationships GROUP BY kind ORDER BY COUNT(*) DESC")
-	if err == nil {
-		defer rows.Close()
-		for rows.Next() {
-			var kind string
-			var count int
-			if err := rows.Scan(&kind, &count); err == nil {
-				stats.RelationshipsByKind[kind] = count
-			}
+		if before.Equal(counts) {
+			fmt.Println("\nRunning counts match a full recount.")
+		} else {
+			fmt.Printf("\nRunning counts had drifted and were repaired (files %d -> %d, symbols %d -> %d, relationships %d -> %d).\n",
+				before.Files, counts.Files, before.Symbols, counts.Symbols, before.Relationships, counts.Relationships)
 		}
-		_ = rows.Err() // Check for iteration errors
+	}
+	stats := &IndexStats{
+		FileCount:           counts.Files,
+		ChunkCount:          counts.Chunks,
+		SymbolCount:         counts.Symbols,
+		SymbolsByKind:       counts.SymbolsByKind,
+		RelationshipCount:   counts.Relationships,
+		RelationshipsByKind: counts.RelationshipsByKind,
 	}
 
 	// Get last scan info
@@ -72,7 +72,7 @@ func RunStore(args []string) error {
 	confidence := fs.Float64("confidence", 0.5, "confidence for learnings (0.0-1.0)")
 	direct := fs.Bool("direct", false, "direct write (bypass proposals) for decisions/learnings; audited, human-only")
 	if err := fs.Parse(flagArgs); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	// Validate inputs
@@ -161,8 +161,8 @@ func ExecuteStore(opts StoreOptions) error {
 		}
 		classification = memory.Classification{Kind: kind, Confidence: 1.0, Signals: []string{"explicit"}}
 	} else {
-		// Auto-classify
-		classification = memory.Classify(opts.Content)
+		// Auto-classify, consulting rules learned from past reviews
+		classification = mem.Classify(opts.Content)
 		kind = classification.Kind
 
 		// If low confidence, inform user
@@ -277,6 +277,12 @@ func ExecuteStore(opts StoreOptions) error {
 		}
 	}
 
+	// Queue uncertain auto-classifications of direct records for review
+	needsReview := false
+	if opts.AsType == "" && (kind == memory.RecordKindIdea || opts.Direct) {
+		needsReview, _ = mem.FlagForReview(id, classification)
+	}
+
 	// Output
 	if kind == memory.RecordKindIdea || opts.Direct {
 		kindIcon := "💡"
@@ -299,6 +305,9 @@ func ExecuteStore(opts StoreOptions) error {
 			fmt.Printf("  Tags: %s\n", strings.Join(opts.Tags, ", "))
 		}
 		fmt.Printf("  Content: %s\n", util.TruncateLine(opts.Content, 60))
+		if needsReview {
+			fmt.Println("  Queued for review: run 'palace memory review' to confirm or correct the kind.")
+		}
 	} else {
 		// Proposal output
 		fmt.Printf("📥 Proposal created (%s): %s\n", kind, id)
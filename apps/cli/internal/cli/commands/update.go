@@ -22,7 +22,7 @@ func init() {
 func RunUpdate(args []string) error {
 	fs := flag.NewFlagSet("update", flag.ContinueOnError)
 	if err := fs.Parse(args); err != nil {
-		return err
+		return UsageError(err)
 	}
 
 	return ExecuteUpdate(BuildVersion)
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/butler"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/flags"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "watch",
+		Description: "Keep the index fresh as files change (optionally serving MCP)",
+		Run:         RunWatch,
+	})
+}
+
+// WatchOptions contains the configuration for the watch command.
+type WatchOptions struct {
+	Root     string
+	MCP      bool   // Also run the MCP server on stdin/stdout
+	Mode     string // MCP mode: "agent" or "human"
+	Interval time.Duration
+}
+
+// RunWatch executes the watch command with parsed arguments.
+func RunWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	root := flags.AddRootFlag(fs)
+	mcp := fs.Bool("mcp", false, "also serve MCP on stdin/stdout from the live index")
+	mode := fs.String("mode", "agent", "MCP mode: 'agent' (restricted, default) or 'human' (full access)")
+	interval := fs.Duration("interval", scan.DefaultWatchInterval, "polling interval")
+	if err := fs.Parse(args); err != nil {
+		return UsageError(err)
+	}
+
+	return ExecuteWatch(WatchOptions{Root: *root, MCP: *mcp, Mode: *mode, Interval: *interval})
+}
+
+// ExecuteWatch runs the watcher until SIGINT/SIGTERM (or, with MCP, until the client disconnects).
+func ExecuteWatch(opts WatchOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runWatch(ctx, opts, os.Stdin, os.Stdout)
+}
+
+// runWatch is the testable core of ExecuteWatch with injectable I/O.
+func runWatch(ctx context.Context, opts WatchOptions, in io.Reader, out io.Writer) error {
+	rootPath, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return err
+	}
+	if opts.MCP && !butler.IsValidMCPMode(opts.Mode) {
+		return fmt.Errorf("invalid mode %q; must be 'agent' or 'human'", opts.Mode)
+	}
+
+	dbPath := config.IndexDBPath(rootPath)
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("index missing; run 'palace scan' first: %w", err)
+	}
+	db, err := index.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	watchOpts := scan.WatchOptions{
+		Interval: opts.Interval,
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		},
+	}
+
+	if !opts.MCP {
+		watchOpts.OnChange = func(changes []index.FileChange, summary index.IncrementalScanSummary) {
+			fmt.Fprintf(out, "[%s] reindexed: +%d ~%d -%d (%v)\n", time.Now().Format("15:04:05"),
+				summary.FilesAdded, summary.FilesModified, summary.FilesDeleted, summary.Duration.Round(time.Millisecond))
+		}
+		fmt.Fprintf(os.Stderr, "Watching %s for changes (Ctrl+C to stop)...\n", rootPath)
+		return scan.Watch(ctx, db, rootPath, watchOpts)
+	}
+
+	b, err := butler.New(db, rootPath)
+	if err != nil {
+		return fmt.Errorf("initialize butler: %w", err)
+	}
+	defer b.Close()
+
+	server := butler.NewMCPServerWithIO(b, butler.MCPMode(opts.Mode), bufio.NewReader(in), out)
+	server.EnableChangeNotifications()
+
+	watchOpts.OnChange = func(changes []index.FileChange, _ index.IncrementalScanSummary) {
+		var updated []string
+		listChanged := false
+		for _, c := range changes {
+			updated = append(updated, c.Path)
+			if c.Action != "modified" {
+				listChanged = true
+			}
+		}
+		if err := server.NotifyResourcesChanged(updated, listChanged); err != nil {
+			fmt.Fprintf(os.Stderr, "watch: notify: %v\n", err)
+		}
+	}
+
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+	watchErr := make(chan error, 1)
+	go func() { watchErr <- scan.Watch(watchCtx, db, rootPath, watchOpts) }()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve() }()
+
+	fmt.Fprintf(os.Stderr, "Mind Palace MCP server started in %s mode with live index updates.\n", opts.Mode)
+
+	var result error
+	select {
+	case <-ctx.Done():
+		// Interrupted: stop the watcher; the blocked stdin reader ends with the process.
+	case result = <-serveErr:
+		// Client disconnected: stop watching.
+	case result = <-watchErr:
+		return result
+	}
+	cancelWatch()
+	if err := <-watchErr; err != nil && result == nil {
+		result = err
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/scan"
+)
+
+func TestRunWatchInvalidFlag(t *testing.T) {
+	if err := RunWatch([]string{"--invalid-flag"}); err == nil {
+		t.Error("expected error for invalid flag")
+	}
+}
+
+func TestRunWatchNoIndex(t *testing.T) {
+	err := runWatch(context.Background(), WatchOptions{Root: t.TempDir()}, strings.NewReader(""), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "index missing") {
+		t.Errorf("expected index missing error, got %v", err)
+	}
+}
+
+func TestRunWatchMCPReflectsFileChanges(t *testing.T) {
+	root := t.TempDir()
+	mainPath := filepath.Join(root, "main.go")
+	if err := os.WriteFile(mainPath, []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := scan.Run(root); err != nil {
+		t.Fatalf("scan.Run() error: %v", err)
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runWatch(ctx, WatchOptions{Root: root, MCP: true, Mode: "agent", Interval: 50 * time.Millisecond}, inR, outW)
+		outW.Close()
+	}()
+
+	lines := make(chan string, 64)
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	waitFor := func(match func(string) bool) string {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatal("output closed before expected message")
+				}
+				if match(line) {
+					return line
+				}
+			case <-timeout:
+				t.Fatal("timed out waiting for MCP output")
+			}
+		}
+	}
+
+	sub := `{"jsonrpc":"2.0","id":0,"method":"resources/subscribe","params":{"uri":"palace://files/main.go"}}` + "\n"
+	if _, err := inW.Write([]byte(sub)); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(func(line string) bool { return strings.Contains(line, `"id":0`) })
+
+	// Give the watcher a moment to take its baseline snapshot, then edit the file.
+	time.Sleep(200 * time.Millisecond)
+	updated := "package main\n\nfunc main() {}\n\nfunc NewFeature() string {\n\treturn \"live\"\n}\n"
+	if err := os.WriteFile(mainPath, []byte(updated), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(func(line string) bool {
+		return strings.Contains(line, "notifications/resources/updated") && strings.Contains(line, "palace://files/main.go")
+	})
+
+	req := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"explore_symbol","arguments":{"name":"NewFeature"}}}` + "\n"
+	if _, err := inW.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	line := waitFor(func(line string) bool { return strings.Contains(line, `"id":1`) })
+
+	var resp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Result.IsError || len(resp.Result.Content) == 0 || !strings.Contains(resp.Result.Content[0].Text, "NewFeature") {
+		t.Errorf("expected live index to contain NewFeature, got: %s", line)
+	}
+
+	// Client disconnect stops both the server and the watcher.
+	inW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("runWatch() error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runWatch did not shut down")
+	}
+}
@@ -42,3 +42,18 @@ func (b *BoolFlag) String() string {
 
 // IsBoolFlag returns true, indicating this is a boolean flag that doesn't require a value.
 func (b *BoolFlag) IsBoolFlag() bool { return true }
+
+// StringList is a string flag that may be repeated, collecting each value
+// in order.
+type StringList []string
+
+// Set appends a value.
+func (l *StringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// String returns the values joined by commas.
+func (l *StringList) String() string {
+	return strings.Join(*l, ",")
+}
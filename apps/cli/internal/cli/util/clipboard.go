@@ -0,0 +1,47 @@
+package util
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoClipboard is returned by CopyToClipboard when no clipboard tool is
+// available, as on a headless server.
+var ErrNoClipboard = errors.New("no clipboard available (install pbcopy, wl-copy, xclip, or xsel)")
+
+// clipboardCommands are the clipboard tools tried in order, per OS.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux": {
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	},
+}
+
+// CopyToClipboard puts text on the system clipboard using the first
+// clipboard tool found on PATH.
+func CopyToClipboard(text string) error {
+	candidates, ok := clipboardCommands[runtime.GOOS]
+	if !ok {
+		candidates = clipboardCommands["linux"]
+	}
+	for _, argv := range candidates {
+		if _, err := exec.LookPath(argv[0]); err != nil {
+			continue
+		}
+		// Stdout and stderr stay unset, so they go to the null device: xclip
+		// and xsel fork a child that keeps serving the selection, and a pipe
+		// to its output would not close until that child exits.
+		cmd := exec.Command(argv[0], argv[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return errors.New(argv[0] + ": " + err.Error())
+		}
+		return nil
+	}
+	return ErrNoClipboard
+}
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCopyToClipboardDoesNotWaitForForkedTool(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses a shell script standing in for wl-copy")
+	}
+	// Like xclip, the fake tool reads the text and leaves a child running
+	// that still holds its stdout and stderr
+	bin := t.TempDir()
+	out := filepath.Join(bin, "copied")
+	script := "#!/bin/sh\ncat > " + out + "\nsleep 5 &\n"
+	if err := os.WriteFile(filepath.Join(bin, "wl-copy"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	start := time.Now()
+	if err := CopyToClipboard("hello"); err != nil {
+		t.Fatalf("CopyToClipboard() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("CopyToClipboard() waited %v for the forked child", elapsed)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "hello" {
+		t.Errorf("clipboard tool got %q, want hello", data)
+	}
+}
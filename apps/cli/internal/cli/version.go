@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 	"strings"
 
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/cli/commands"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/update"
 )
 
@@ -83,7 +84,7 @@ func cmdVersion(args []string) error {
 	fs := flag.NewFlagSet("version", flag.ContinueOnError)
 	check := fs.Bool("check", false, "check for updates")
 	if err := fs.Parse(args); err != nil {
-		return err
+		return commands.UsageError(err)
 	}
 
 	fmt.Printf("palace %s (commit %s, built %s)\n", GetVersion(), buildCommit, buildDate)
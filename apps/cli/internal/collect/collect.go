@@ -40,7 +40,7 @@ func Run(root, diffRange string, opts Options) (Result, error) {
 		return Result{}, err
 	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	db, err := index.Open(dbPath)
 	if err != nil {
 		return Result{}, fmt.Errorf("open index: %w", err)
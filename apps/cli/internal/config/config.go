@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/jsonc"
@@ -53,6 +56,11 @@ type PalaceConfig struct {
 		Language    string `json:"language"`
 		Repository  string `json:"repository"`
 	} `json:"project"`
+	// Directory the index and memory stores live under, one subdirectory per
+	// workspace (default: inside .palace). PALACE_DATA_DIR and --data-dir
+	// take precedence.
+	DataDir string `json:"dataDir,omitempty"`
+
 	DefaultRoom string                    `json:"defaultRoom"`
 	Guardrails  Guardrails                `json:"guardrails"`
 	Neighbors   map[string]NeighborConfig `json:"neighbors,omitempty"`
@@ -87,6 +95,133 @@ type PalaceConfig struct {
 
 	// Scope configuration for inheritance rules
 	Scope *ScopeConfig `json:"scope,omitempty"`
+
+	// Recall configuration for MCP result sizing
+	Recall *RecallConfig `json:"recall,omitempty"`
+
+	// Scan configuration
+	Scan *ScanConfig `json:"scan,omitempty"`
+
+	// Thresholds for 'palace memory relink'
+	Relink *RelinkConfig `json:"relink,omitempty"`
+
+	// Forget policy for 'palace memory gc'
+	Forget *ForgetConfig `json:"forget,omitempty"`
+
+	// Other workspaces whose memory recall can include, by name. Paths
+	// are workspace roots, relative to this one unless absolute.
+	RelatedWorkspaces map[string]string `json:"relatedWorkspaces,omitempty"`
+
+	// Content conventions for 'palace lint-memory' and the store tool
+	MemoryLint *MemoryLintConfig `json:"memoryLint,omitempty"`
+
+	// Scoring for 'palace query hotspots'
+	Hotspots *HotspotsConfig `json:"hotspots,omitempty"`
+
+	// Naming conventions for 'palace lint naming'
+	NamingLint *NamingLintConfig `json:"namingLint,omitempty"`
+}
+
+// RelatedWorkspaceRoots returns the absolute root of each related
+// workspace, by name.
+func (c *PalaceConfig) RelatedWorkspaceRoots(root string) map[string]string {
+	roots := make(map[string]string)
+	if c == nil {
+		return roots
+	}
+	for name, path := range c.RelatedWorkspaces {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		roots[name] = filepath.Clean(path)
+	}
+	return roots
+}
+
+// ForgetConfig holds the policy 'palace memory gc' archives low-value records
+// by. Zero values use the memory package defaults; decisions and records
+// tagged "pinned" are never archived.
+type ForgetConfig struct {
+	OnStartup      bool     `json:"onStartup,omitempty"`      // Also apply the policy when the MCP server starts
+	MinAgeDays     int      `json:"minAgeDays,omitempty"`     // Days since a record was created and last recalled (default: 90)
+	MaxAccessCount int      `json:"maxAccessCount,omitempty"` // Recalls a record may have had (default: 0, never recalled)
+	MaxConfidence  float64  `json:"maxConfidence,omitempty"`  // Confidence a learning may have (default: 0.3)
+	ExcludeKinds   []string `json:"excludeKinds,omitempty"`   // Kinds never archived: "idea", "learning"
+}
+
+// MemoryLintConfig holds the content conventions records are checked
+// against by 'palace lint-memory' and, with OnStore set, when stored.
+type MemoryLintConfig struct {
+	Rules   []MemoryLintRule `json:"rules,omitempty"`
+	OnStore string           `json:"onStore,omitempty"` // "off" (default), "warn", or "reject"
+}
+
+// MemoryLintRule is one convention. A record of Kind (or of any kind, if
+// Kind is empty) violates it if any of the set conditions fails.
+type MemoryLintRule struct {
+	Name             string   `json:"name"`
+	Kind             string   `json:"kind,omitempty"`             // "idea", "decision", or "learning"
+	Pattern          string   `json:"pattern,omitempty"`          // Regular expression the content must match
+	MinLength        int      `json:"minLength,omitempty"`        // Minimum content length in characters
+	MaxLength        int      `json:"maxLength,omitempty"`        // Maximum content length in characters
+	RequiredTags     []string `json:"requiredTags,omitempty"`     // Tags the record must carry
+	RequireRationale bool     `json:"requireRationale,omitempty"` // Decisions must record a rationale
+	Message          string   `json:"message,omitempty"`          // Shown instead of the generated description
+}
+
+// NamingLintConfig holds the naming conventions 'palace lint naming' checks
+// symbols against. Rules replace the built-in conventions for the same
+// language and kind; NoDefaults drops the built-in conventions altogether.
+type NamingLintConfig struct {
+	Rules      []NamingLintRule `json:"rules,omitempty"`
+	NoDefaults bool             `json:"noDefaults,omitempty"`
+}
+
+// NamingLintRule requires the names of symbols of Kinds in Language to
+// follow Style or match Pattern. Style "off" turns the conventions for
+// those kinds off.
+type NamingLintRule struct {
+	Language   string   `json:"language"`             // e.g. "python", "go"
+	Kinds      []string `json:"kinds"`                // e.g. "function", "method", "class", "constant"
+	Style      string   `json:"style,omitempty"`      // "snake_case", "camelCase", "PascalCase", "UPPER_CASE", "MixedCaps", or "off"
+	Pattern    string   `json:"pattern,omitempty"`    // Regular expression names must match, instead of a style
+	Visibility string   `json:"visibility,omitempty"` // "exported" or "unexported"; both when empty
+}
+
+// HotspotsConfig weighs the metrics 'palace query hotspots' ranks code by.
+// Only the ratios between weights matter; when none is set, all three count
+// equally.
+type HotspotsConfig struct {
+	ComplexityWeight float64 `json:"complexityWeight,omitempty"`
+	SizeWeight       float64 `json:"sizeWeight,omitempty"`
+	ChurnWeight      float64 `json:"churnWeight,omitempty"`
+	ChurnSince       string  `json:"churnSince,omitempty"` // Only count commits after this, e.g. "1 year ago"
+}
+
+// RelinkConfig holds the thresholds 'palace memory relink' uses to propose
+// "related" links. Zero values use the memory package defaults.
+type RelinkConfig struct {
+	MinSharedTags int     `json:"minSharedTags,omitempty"` // Tags two records must share (default: 2)
+	MinTagOverlap float64 `json:"minTagOverlap,omitempty"` // Jaccard overlap of their tag sets (default: 0.5)
+	MinSimilarity float64 `json:"minSimilarity,omitempty"` // Jaccard overlap of their content words (default: 0.6)
+}
+
+// ScanConfig holds configuration for full scans.
+type ScanConfig struct {
+	Processors      []string `json:"processors,omitempty"`      // Built-in post-scan processors to run, e.g. ["language-stats"]
+	MaxNestingDepth int      `json:"maxNestingDepth,omitempty"` // Deepest symbol nesting kept before flattening (default: 64)
+	SnapshotFormat  string   `json:"snapshotFormat,omitempty"`  // Format of index snapshots from 'palace export --what index': "json" (default) or "binary"
+
+	// CallExclusions are call targets to leave out of the call graph on top
+	// of each language's builtins, keyed by language ("*" for all), e.g.
+	// {"python": ["log", "self.assertEqual"], "*": ["trace.*"]}.
+	CallExclusions map[string][]string `json:"callExclusions,omitempty"`
+
+	// Strictness tunes the heuristic parsers (dart, nim, cue, and the
+	// generic fallback) per language: "loose", "balanced" (default), or
+	// "strict", e.g. {"dart": "strict", "generic": "loose"}. Other languages
+	// are rejected.
+	Strictness map[string]string `json:"strictness,omitempty"`
 }
 
 // DecayConfig holds configuration for confidence decay of learnings.
@@ -153,6 +288,125 @@ func DefaultScopeConfig() *ScopeConfig {
 	}
 }
 
+// RecallConfig holds configuration for sizing recall results returned to agents.
+type RecallConfig struct {
+	DefaultTokenBudget int                 `json:"defaultTokenBudget"` // Token budget when no limit is given (default: 2000)
+	MaxResults         int                 `json:"maxResults"`         // Upper bound on results fetched per page (default: 50)
+	Stemming           *bool               `json:"stemming,omitempty"` // Match word variants like cache/cached/caching (default: true)
+	Synonyms           map[string][]string `json:"synonyms,omitempty"` // Extra query synonyms, e.g. {"db": ["database"]}
+	RecencyHalfLife    string              `json:"recencyHalfLife"`    // Age at which the recency boost halves, e.g. "30d"; "0" disables (default: 14d)
+	DecisionBoost      float64             `json:"decisionBoost"`      // Relevance multiplier for decisions in context bundles; 1.0 disables (default: 1.5)
+	PinnedBoost        float64             `json:"pinnedBoost"`        // Relevance multiplier for records tagged "pinned"; 1.0 disables (default: 2.0)
+}
+
+// DefaultRecencyHalfLife is the recency half-life used when none is configured.
+const DefaultRecencyHalfLife = 14 * 24 * time.Hour
+
+// DefaultRecallConfig returns the default recall configuration.
+func DefaultRecallConfig() *RecallConfig {
+	stemming := true
+	return &RecallConfig{
+		DefaultTokenBudget: 2000,
+		MaxResults:         50,
+		Stemming:           &stemming,
+		RecencyHalfLife:    "14d",
+		DecisionBoost:      1.5,
+		PinnedBoost:        2.0,
+	}
+}
+
+// RecallSettings returns the recall configuration with defaults filled in.
+// It is safe to call on a nil config.
+func (c *PalaceConfig) RecallSettings() *RecallConfig {
+	defaults := DefaultRecallConfig()
+	if c == nil || c.Recall == nil {
+		return defaults
+	}
+	cfg := *c.Recall
+	if cfg.DefaultTokenBudget <= 0 {
+		cfg.DefaultTokenBudget = defaults.DefaultTokenBudget
+	}
+	if cfg.MaxResults <= 0 {
+		cfg.MaxResults = defaults.MaxResults
+	}
+	if cfg.Stemming == nil {
+		cfg.Stemming = defaults.Stemming
+	}
+	if cfg.RecencyHalfLife == "" {
+		cfg.RecencyHalfLife = defaults.RecencyHalfLife
+	}
+	if cfg.DecisionBoost <= 0 {
+		cfg.DecisionBoost = defaults.DecisionBoost
+	}
+	if cfg.PinnedBoost <= 0 {
+		cfg.PinnedBoost = defaults.PinnedBoost
+	}
+	return &cfg
+}
+
+// StemmingEnabled reports whether queries match word variants. Stemming is
+// on unless the config turns it off explicitly.
+func (c *RecallConfig) StemmingEnabled() bool {
+	return c == nil || c.Stemming == nil || *c.Stemming
+}
+
+// HalfLife returns the parsed recency half-life. Values accept Go durations
+// ("72h") plus day and week suffixes ("30d", "2w"). Zero and "off" disable
+// recency so it no longer affects ranking. An unparseable value, such as
+// "30days", falls back to DefaultRecencyHalfLife with a warning rather than
+// turning recency off unnoticed.
+func (c *RecallConfig) HalfLife() time.Duration {
+	if c == nil || c.RecencyHalfLife == "" {
+		return DefaultRecencyHalfLife
+	}
+	d, err := ParseHalfLife(c.RecencyHalfLife)
+	if err != nil {
+		if _, warned := halfLifeWarned.LoadOrStore(c.RecencyHalfLife, true); !warned {
+			fmt.Fprintf(warnOutput, "warning: recall.recencyHalfLife: %v; using the default of %s\n", err, DefaultRecallConfig().RecencyHalfLife)
+		}
+		return DefaultRecencyHalfLife
+	}
+	return d
+}
+
+var (
+	// warnOutput receives warnings about config values that fall back to
+	// defaults. It is stderr, as stdout may carry the MCP protocol.
+	warnOutput io.Writer = os.Stderr
+	// halfLifeWarned holds the bad half-lives already warned about, as
+	// HalfLife is called on every recall.
+	halfLifeWarned sync.Map
+)
+
+// ParseHalfLife parses a duration with optional day ("d") and week ("w") units.
+// "0", "off", and "none" parse to zero.
+func ParseHalfLife(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	switch s {
+	case "0", "off", "none", "disabled":
+		return 0, nil
+	}
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit == 0 {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid half-life %q", s)
+		}
+		return d, nil
+	}
+	n, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid half-life %q", s)
+	}
+	return time.Duration(n * float64(unit)), nil
+}
+
 func EnsureLayout(root string) (string, error) {
 	palaceDir := filepath.Join(root, ".palace")
 	dirs := []string{
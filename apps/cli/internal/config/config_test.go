@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/schemas"
 )
@@ -216,3 +217,95 @@ func TestEnsureLayoutErrors(t *testing.T) {
 		t.Error("expected error when root path prefix is a file")
 	}
 }
+
+func TestParseHalfLife(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"72h", 72 * time.Hour},
+		{"0", 0},
+		{"off", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseHalfLife(tt.in)
+		if err != nil {
+			t.Errorf("ParseHalfLife(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseHalfLife(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"soon", "-3d", "3x"} {
+		if _, err := ParseHalfLife(bad); err == nil {
+			t.Errorf("ParseHalfLife(%q) expected error", bad)
+		}
+	}
+
+	var cfg *PalaceConfig
+	if got := cfg.RecallSettings().HalfLife(); got != DefaultRecencyHalfLife {
+		t.Errorf("default half-life = %v, want %v", got, DefaultRecencyHalfLife)
+	}
+	cfg = &PalaceConfig{Recall: &RecallConfig{RecencyHalfLife: "0"}}
+	if got := cfg.RecallSettings().HalfLife(); got != 0 {
+		t.Errorf("disabled half-life = %v, want 0", got)
+	}
+}
+
+func TestHalfLifeTypoFallsBack(t *testing.T) {
+	var buf bytes.Buffer
+	warnOutput = &buf
+	t.Cleanup(func() { warnOutput = os.Stderr })
+
+	cfg := &PalaceConfig{Recall: &RecallConfig{RecencyHalfLife: "30days"}}
+	for i := 0; i < 2; i++ {
+		if got := cfg.RecallSettings().HalfLife(); got != DefaultRecencyHalfLife {
+			t.Errorf("half-life of a typo = %v, want the default %v", got, DefaultRecencyHalfLife)
+		}
+	}
+	if n := strings.Count(buf.String(), "30days"); n != 1 {
+		t.Errorf("expected one warning naming the bad value, got %d:\n%s", n, buf.String())
+	}
+}
+
+func TestRecallSettingsPartialBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		recall   string
+		stemming bool
+	}{
+		{"stemming left out", `{"maxResults": 20}`, true},
+		{"stemming off", `{"maxResults": 20, "stemming": false}`, false},
+		{"stemming on", `{"stemming": true}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, ".palace"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			content := `{"recall": ` + tt.recall + `}`
+			if err := os.WriteFile(filepath.Join(dir, ".palace", "palace.jsonc"), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadPalaceConfig(dir)
+			if err != nil {
+				t.Fatalf("LoadPalaceConfig: %v", err)
+			}
+			rc := cfg.RecallSettings()
+			if got := rc.StemmingEnabled(); got != tt.stemming {
+				t.Errorf("StemmingEnabled() = %v, want %v", got, tt.stemming)
+			}
+			if rc.Stemming == nil || *rc.Stemming != tt.stemming {
+				t.Errorf("RecallSettings().Stemming = %v, want %v", rc.Stemming, tt.stemming)
+			}
+			if rc.DefaultTokenBudget != DefaultRecallConfig().DefaultTokenBudget {
+				t.Errorf("DefaultTokenBudget = %d, want the default", rc.DefaultTokenBudget)
+			}
+		})
+	}
+}
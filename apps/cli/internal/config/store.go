@@ -0,0 +1,140 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// EnvDataDir is the environment variable that sets the data directory.
+const EnvDataDir = "PALACE_DATA_DIR"
+
+var (
+	dataDirMu       sync.RWMutex
+	dataDirOverride string
+)
+
+// SetDataDir sets the data directory for the rest of the process, ahead of
+// PALACE_DATA_DIR and palace.jsonc. The CLI calls it for --data-dir; an
+// empty dir clears the override.
+func SetDataDir(dir string) {
+	dataDirMu.Lock()
+	defer dataDirMu.Unlock()
+	dataDirOverride = dir
+}
+
+// DataDir returns the data directory the stores of the workspace at root
+// live under: the --data-dir override, else PALACE_DATA_DIR, else dataDir
+// in the workspace's palace.jsonc (relative to root). "" means none is
+// configured and the stores stay inside the workspace's .palace directory.
+func DataDir(root string) string {
+	dataDirMu.RLock()
+	dir := dataDirOverride
+	dataDirMu.RUnlock()
+	if dir == "" {
+		dir = os.Getenv(EnvDataDir)
+	}
+	if dir == "" {
+		if cfg, err := LoadPalaceConfig(root); err == nil && cfg.DataDir != "" {
+			dir = expandHome(cfg.DataDir)
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(root, dir)
+			}
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(expandHome(dir)); err == nil {
+		dir = abs
+	}
+	return dir
+}
+
+var workspaceIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// WorkspaceID names the store of the workspace at root inside a data
+// directory: the directory's base name, for people browsing the data
+// directory, and a hash of its absolute path, so that two checkouts with
+// the same name never share a store.
+func WorkspaceID(root string) string {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	sum := sha256.Sum256([]byte(abs))
+	name := strings.Trim(workspaceIDUnsafe.ReplaceAllString(filepath.Base(abs), "-"), "-.")
+	if name == "" {
+		name = "workspace"
+	}
+	return name + "-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// StorePaths locates the index and memory databases of one workspace.
+type StorePaths struct {
+	Root        string // Absolute workspace root
+	DataDir     string // Configured data directory, or "" for the in-workspace layout
+	WorkspaceID string // Set only when DataDir is
+	Dir         string // Directory holding the workspace's stores
+	IndexDir    string // Holds palace.db and scan.json
+	IndexDB     string
+	MemoryDir   string
+	MemoryDB    string
+}
+
+// ResolveStore returns where the stores of the workspace at root live.
+// With a data directory configured they are laid out as
+// <dataDir>/<workspaceID>/index/palace.db and
+// <dataDir>/<workspaceID>/memory/memory.db; without one they stay at
+// .palace/index/palace.db and .palace/memory.db in the workspace.
+func ResolveStore(root string) StorePaths {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	p := StorePaths{Root: abs, DataDir: DataDir(abs)}
+	if p.DataDir == "" {
+		p.Dir = filepath.Join(abs, ".palace")
+		p.IndexDir = filepath.Join(p.Dir, "index")
+		p.MemoryDir = p.Dir
+	} else {
+		p.WorkspaceID = WorkspaceID(abs)
+		p.Dir = filepath.Join(p.DataDir, p.WorkspaceID)
+		p.IndexDir = filepath.Join(p.Dir, "index")
+		p.MemoryDir = filepath.Join(p.Dir, "memory")
+	}
+	p.IndexDB = filepath.Join(p.IndexDir, "palace.db")
+	p.MemoryDB = filepath.Join(p.MemoryDir, "memory.db")
+	return p
+}
+
+// IndexDBPath returns the path of the index database of the workspace at
+// root.
+func IndexDBPath(root string) string {
+	return ResolveStore(root).IndexDB
+}
+
+// MemoryDBPath returns the path of the memory database of the workspace at
+// root.
+func MemoryDBPath(root string) string {
+	return ResolveStore(root).MemoryDB
+}
+
+// expandHome expands a leading "~" to the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
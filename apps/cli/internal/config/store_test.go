@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveStoreDefaultsToWorkspace(t *testing.T) {
+	t.Setenv(EnvDataDir, "")
+	root := t.TempDir()
+
+	p := ResolveStore(root)
+	if p.DataDir != "" || p.WorkspaceID != "" {
+		t.Fatalf("expected no data dir, got %+v", p)
+	}
+	if want := filepath.Join(root, ".palace", "index", "palace.db"); p.IndexDB != want {
+		t.Errorf("IndexDB = %s, want %s", p.IndexDB, want)
+	}
+	if want := filepath.Join(root, ".palace", "memory.db"); p.MemoryDB != want {
+		t.Errorf("MemoryDB = %s, want %s", p.MemoryDB, want)
+	}
+}
+
+func TestResolveStoreSeparatesWorkspaces(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(EnvDataDir, dataDir)
+	// Two checkouts with the same directory name
+	rootA := filepath.Join(t.TempDir(), "app")
+	rootB := filepath.Join(t.TempDir(), "app")
+
+	a, b := ResolveStore(rootA), ResolveStore(rootB)
+	if a.WorkspaceID == b.WorkspaceID {
+		t.Fatalf("workspace ids should differ, both %s", a.WorkspaceID)
+	}
+	if !strings.HasPrefix(a.WorkspaceID, "app-") {
+		t.Errorf("workspace id should start with the directory name, got %s", a.WorkspaceID)
+	}
+	for _, p := range []StorePaths{a, b} {
+		if want := filepath.Join(dataDir, p.WorkspaceID, "index", "palace.db"); p.IndexDB != want {
+			t.Errorf("IndexDB = %s, want %s", p.IndexDB, want)
+		}
+		if want := filepath.Join(dataDir, p.WorkspaceID, "memory", "memory.db"); p.MemoryDB != want {
+			t.Errorf("MemoryDB = %s, want %s", p.MemoryDB, want)
+		}
+	}
+	if ResolveStore(rootA) != a {
+		t.Error("resolving the same root twice should give the same paths")
+	}
+}
+
+func TestDataDirPrecedence(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".palace"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".palace", "palace.jsonc"), []byte(`{"dataDir": "store"}`), 0o644); err != nil {
+		t.Fatalf("write palace: %v", err)
+	}
+
+	t.Setenv(EnvDataDir, "")
+	if got, want := DataDir(root), filepath.Join(root, "store"); got != want {
+		t.Errorf("config: DataDir = %s, want %s", got, want)
+	}
+
+	env := t.TempDir()
+	t.Setenv(EnvDataDir, env)
+	if got := DataDir(root); got != env {
+		t.Errorf("env: DataDir = %s, want %s", got, env)
+	}
+
+	override := t.TempDir()
+	SetDataDir(override)
+	defer SetDataDir("")
+	if got := DataDir(root); got != override {
+		t.Errorf("override: DataDir = %s, want %s", got, override)
+	}
+}
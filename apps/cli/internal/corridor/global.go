@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
 	_ "modernc.org/sqlite"
 )
@@ -277,7 +278,7 @@ func (g *GlobalCorridor) GetLinkedLearnings(name string, limit int) ([]memory.Le
 	_, _ = g.db.ExecContext(context.Background(), `UPDATE links SET last_accessed = ? WHERE name = ?`, now, name)
 
 	// Open the linked workspace memory
-	memoryPath := filepath.Join(path, ".palace", "memory.db")
+	memoryPath := config.MemoryDBPath(path)
 	if _, err := os.Stat(memoryPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("no memory.db at linked workspace %s", name)
 	}
@@ -418,7 +419,7 @@ func (g *GlobalCorridor) ValidateLinks() ([]string, error) {
 	}
 
 	for _, link := range links {
-		memPath := filepath.Join(link.Path, ".palace", "memory.db")
+		memPath := config.MemoryDBPath(link.Path)
 		if _, err := os.Stat(memPath); os.IsNotExist(err) {
 			stale = append(stale, link.Name)
 		}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/butler"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
 )
@@ -147,7 +148,7 @@ func (s *Server) switchWorkspace(rootPath string) error {
 	// Non-fatal if memory fails - continue without it
 
 	// Try to open new butler for code search
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); err == nil { // lgtm[go/path-injection] dbPath from validated rootPath
 		db, err := index.Open(dbPath)
 		if err == nil {
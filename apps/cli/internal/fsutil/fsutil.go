@@ -59,7 +59,16 @@ func HashFile(path string) (string, error) {
 }
 
 func ListFiles(root string, guardrails config.Guardrails) ([]string, error) {
+	files, _, err := ListFilesToDepth(root, guardrails, -1)
+	return files, err
+}
+
+// ListFilesToDepth is ListFiles limited to files at most maxDepth
+// directories below root; a negative maxDepth lists every file. Directories
+// past the limit are not walked. It also returns how many were pruned.
+func ListFilesToDepth(root string, guardrails config.Guardrails, maxDepth int) ([]string, int, error) {
 	var files []string
+	pruned := 0
 	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			// Skip permission errors and other access issues gracefully
@@ -101,15 +110,20 @@ func ListFiles(root string, guardrails config.Guardrails) ([]string, error) {
 		}
 
 		if d.IsDir() {
+			// A directory n levels down holds files at depth n
+			if maxDepth >= 0 && strings.Count(rel, "/")+1 > maxDepth {
+				pruned++
+				return filepath.SkipDir
+			}
 			return nil
 		}
 		files = append(files, rel)
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return files, nil
+	return files, pruned, nil
 }
 
 func ChunkContent(content string, maxLines, maxBytes int) []Chunk {
@@ -185,6 +185,39 @@ func TestListFiles(t *testing.T) {
 	}
 }
 
+func TestListFilesToDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, f := range []string{"main.go", "a/a.go", "a/b/b.go", "a/b/c/c.go", "d/e/e.go"} {
+		path := filepath.Join(tmpDir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+
+	listed, pruned, err := fsutil.ListFilesToDepth(tmpDir, config.Guardrails{}, 1)
+	if err != nil {
+		t.Fatalf("ListFilesToDepth failed: %v", err)
+	}
+	if got := strings.Join(listed, ","); got != "a/a.go,main.go" {
+		t.Errorf("listed %q, want a/a.go,main.go", got)
+	}
+	// a/b and d/e are pruned; a/b/c below them is never visited
+	if pruned != 2 {
+		t.Errorf("pruned = %d, want 2", pruned)
+	}
+
+	listed, pruned, err = fsutil.ListFilesToDepth(tmpDir, config.Guardrails{}, -1)
+	if err != nil {
+		t.Fatalf("ListFilesToDepth failed: %v", err)
+	}
+	if len(listed) != 5 || pruned != 0 {
+		t.Errorf("unlimited listing = %q, pruned %d", listed, pruned)
+	}
+}
+
 func TestChunkContent(t *testing.T) {
 	// Create content with multiple lines
 	var lines []string
@@ -162,13 +162,14 @@ func GetChangedFilesSinceCommit(root, baseCommit string) (added, modified, delet
 // getUncommittedChanges returns files with uncommitted changes (staged + unstaged).
 func getUncommittedChanges(root string) (added, modified, deleted []string, err error) {
 	// Get status of all changed files
-	cmd := exec.CommandContext(context.Background(), "git", "-C", root, "status", "--porcelain")
+	cmd := exec.CommandContext(context.Background(), "git", "-C", root, "status", "--porcelain", "--untracked-files=all")
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	// Only trim the trailing newline: the leading space is part of the status column
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
 	for _, line := range lines {
 		if len(line) < 3 {
 			continue
@@ -251,3 +252,31 @@ func IsDirtyWorkingTree(root string) bool {
 	}
 	return strings.TrimSpace(string(out)) != ""
 }
+
+// ShowFileAtRef returns the contents of a file as it exists at the given ref.
+// The path must be relative to the repository root.
+func ShowFileAtRef(root, ref, path string) ([]byte, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "-C", root, "show", ref+":"+filepath.ToSlash(path))
+	return cmd.Output()
+}
+
+// FileChurn returns how many commits touched each file under root, keyed
+// by path relative to root. With since set, such as "6 months ago", only
+// commits after it are counted.
+func FileChurn(root, since string) (map[string]int, error) {
+	args := []string{"-C", root, "log", "--format=", "--name-only", "--relative"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	out, err := exec.CommandContext(context.Background(), "git", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	churn := make(map[string]int)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			churn[filepath.ToSlash(line)]++
+		}
+	}
+	return churn, nil
+}
@@ -0,0 +1,63 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// FindAnnotatedSymbols returns symbols carrying an annotation, decorator or
+// attribute matching name (see analysis.MatchesAnnotation), with all of
+// their annotations populated. Results are ordered by file and line.
+func FindAnnotatedSymbols(db *sql.DB, name string, limit int) ([]SymbolInfo, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT s.id, s.name, s.kind, s.file_path, s.line_start, s.line_end, s.signature, s.doc_comment, s.exported, a.annotation
+		FROM symbol_annotations a
+		JOIN symbols s ON s.id = a.symbol_id
+		ORDER BY s.file_path, s.line_start, s.id, a.rowid;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []SymbolInfo
+	var matched []bool
+	lastID := int64(-1)
+	for rows.Next() {
+		var id int64
+		var sym SymbolInfo
+		var exported int
+		var annotation string
+		if err := rows.Scan(&id, &sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported, &annotation); err != nil {
+			return nil, err
+		}
+		if id != lastID {
+			sym.Exported = exported == 1
+			symbols = append(symbols, sym)
+			matched = append(matched, false)
+			lastID = id
+		}
+		last := len(symbols) - 1
+		symbols[last].Annotations = append(symbols[last].Annotations, annotation)
+		if analysis.MatchesAnnotation(annotation, name) {
+			matched[last] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var result []SymbolInfo
+	for i, sym := range symbols {
+		if !matched[i] {
+			continue
+		}
+		result = append(result, sym)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
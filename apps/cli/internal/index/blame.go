@@ -0,0 +1,248 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// BlameLine records who last changed one line of a file.
+type BlameLine struct {
+	Author string `json:"a"` // "Name <email>"
+	Commit string `json:"c"`
+	Time   int64  `json:"t"` // Author time, Unix seconds
+}
+
+// uncommittedCommit is the commit git blame reports for lines not yet
+// committed.
+const uncommittedCommit = "0000000000000000000000000000000000000000"
+
+// BlameSource reports, for each line of the file at path (relative to
+// root), who last changed it. It returns nil lines for files it cannot
+// blame, such as untracked ones.
+type BlameSource interface {
+	Blame(root, path string) ([]BlameLine, error)
+}
+
+var (
+	blameMu     sync.RWMutex
+	blameSource BlameSource
+)
+
+// SetBlameSource enables ownership attribution for the scans that follow,
+// using src to blame files. Running git blame on every file is slow, so
+// attribution is off unless enabled; a nil src turns it off again.
+func SetBlameSource(src BlameSource) {
+	blameMu.Lock()
+	defer blameMu.Unlock()
+	blameSource = src
+}
+
+func currentBlameSource() BlameSource {
+	blameMu.RLock()
+	defer blameMu.RUnlock()
+	return blameSource
+}
+
+// GitBlame is the BlameSource that runs git blame.
+type GitBlame struct{}
+
+// Blame implements BlameSource.
+func (GitBlame) Blame(root, path string) ([]BlameLine, error) {
+	cmd := exec.CommandContext(context.Background(), "git", "-C", root, "blame", "--line-porcelain", "--", path)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Untracked file or not a repository: nothing to attribute.
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseLinePorcelain(out), nil
+}
+
+// parseLinePorcelain parses the output of git blame --line-porcelain, which
+// repeats the full commit header before every line.
+func parseLinePorcelain(out []byte) []BlameLine {
+	var lines []BlameLine
+	var cur BlameLine
+	var name, mail string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			cur.Author = strings.TrimSpace(name + " " + mail)
+			lines = append(lines, cur)
+			cur, name, mail = BlameLine{}, "", ""
+		case strings.HasPrefix(line, "author "):
+			name = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			mail = strings.TrimPrefix(line, "author-mail ")
+		case strings.HasPrefix(line, "author-time "):
+			cur.Time, _ = strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64)
+		case cur.Commit == "" && len(line) >= 40 && isHex(line[:40]):
+			cur.Commit = line[:40]
+		}
+	}
+	return lines
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// attributeOwners sets Owner and LastCommit on symbols, and their children,
+// from the blame of the file at path when a blame source is set. Blames are
+// cached by file hash, so unchanged files are not blamed again; blames with
+// uncommitted lines are not cached, as committing changes them.
+func attributeOwners(tx *sql.Tx, root, path, hash string, symbols []analysis.Symbol) error {
+	src := currentBlameSource()
+	if src == nil || len(symbols) == 0 {
+		return nil
+	}
+
+	var blame []BlameLine
+	var cachedHash, cached string
+	err := tx.QueryRowContext(context.Background(), `SELECT hash, lines FROM blame_cache WHERE path = ?`, path).Scan(&cachedHash, &cached)
+	switch {
+	case err == nil && cachedHash == hash:
+		if err := json.Unmarshal([]byte(cached), &blame); err != nil {
+			return fmt.Errorf("decode cached blame: %w", err)
+		}
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("read blame cache: %w", err)
+	default:
+		if blame, err = src.Blame(root, path); err != nil {
+			return err
+		}
+		if err := cacheBlame(tx, path, hash, blame); err != nil {
+			return err
+		}
+	}
+
+	setOwners(symbols, blame)
+	return nil
+}
+
+// cacheBlame stores blame as the cached blame of path at hash, or drops the
+// stale entry when blame cannot be cached.
+func cacheBlame(tx *sql.Tx, path, hash string, blame []BlameLine) error {
+	cacheable := len(blame) > 0
+	for _, l := range blame {
+		if l.Commit == uncommittedCommit {
+			cacheable = false
+			break
+		}
+	}
+	if !cacheable {
+		_, err := tx.ExecContext(context.Background(), `DELETE FROM blame_cache WHERE path = ?`, path)
+		return err
+	}
+	data, err := json.Marshal(blame)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(context.Background(), `INSERT OR REPLACE INTO blame_cache(path, hash, lines) VALUES(?, ?, ?)`, path, hash, string(data))
+	if err != nil {
+		return fmt.Errorf("write blame cache: %w", err)
+	}
+	return nil
+}
+
+func setOwners(symbols []analysis.Symbol, blame []BlameLine) {
+	for i := range symbols {
+		symbols[i].Owner, symbols[i].LastCommit = dominantOwner(blame, symbols[i].LineStart, symbols[i].LineEnd)
+		setOwners(symbols[i].Children, blame)
+	}
+}
+
+// dominantOwner returns the author of most of the committed lines from
+// start to end (1-based, inclusive) and the latest commit among them. Ties
+// go to the author who changed the range most recently.
+func dominantOwner(blame []BlameLine, start, end int) (owner, lastCommit string) {
+	if end < start {
+		end = start
+	}
+	lines := make(map[string]int)
+	latest := make(map[string]int64)
+	var lastTime int64
+	for n := max(start, 1); n <= end && n <= len(blame); n++ {
+		l := blame[n-1]
+		if l.Commit == uncommittedCommit || l.Author == "" {
+			continue
+		}
+		lines[l.Author]++
+		latest[l.Author] = max(latest[l.Author], l.Time)
+		if lastCommit == "" || l.Time > lastTime {
+			lastCommit, lastTime = l.Commit, l.Time
+		}
+	}
+	for author, n := range lines {
+		if owner == "" || n > lines[owner] || (n == lines[owner] && latest[author] > latest[owner]) ||
+			(n == lines[owner] && latest[author] == latest[owner] && author < owner) {
+			owner = author
+		}
+	}
+	return owner, lastCommit
+}
+
+// OwnedSymbol is a symbol attributed to an owner by git blame.
+type OwnedSymbol struct {
+	File       string `json:"file"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	LineStart  int    `json:"lineStart"`
+	LineEnd    int    `json:"lineEnd"`
+	Owner      string `json:"owner"`
+	LastCommit string `json:"lastCommit"`
+}
+
+// GetSymbolsOwnedBy returns the symbols whose owner contains author, case
+// insensitively, so a name or an email address both match, ordered by file
+// and line. limit <= 0 returns them all. It is empty unless a scan was run
+// with blame enrichment.
+func GetSymbolsOwnedBy(db *sql.DB, author string, limit int) ([]OwnedSymbol, error) {
+	query := `
+		SELECT file_path, name, kind, line_start, line_end, owner, COALESCE(last_commit, '')
+		FROM symbols
+		WHERE owner != '' AND instr(lower(owner), lower(?)) > 0
+		ORDER BY file_path, line_start, id`
+	args := []interface{}{author}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query owned symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var result []OwnedSymbol
+	for rows.Next() {
+		var s OwnedSymbol
+		if err := rows.Scan(&s.File, &s.Name, &s.Kind, &s.LineStart, &s.LineEnd, &s.Owner, &s.LastCommit); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,111 @@
+package index
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// fakeBlame serves fixed blames and counts the files it is asked about.
+type fakeBlame struct {
+	lines map[string][]BlameLine
+	calls int
+}
+
+func (f *fakeBlame) Blame(root, path string) ([]BlameLine, error) {
+	f.calls++
+	return f.lines[path], nil
+}
+
+func TestWriteScanAttributesDominantOwner(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	alice := BlameLine{Author: "Alice <alice@example.com>", Commit: "aaaa", Time: 100}
+	bob := BlameLine{Author: "Bob <bob@example.com>", Commit: "bbbb", Time: 200}
+	src := &fakeBlame{lines: map[string][]BlameLine{
+		// Lines 1-4 are Run (mostly Alice, last touched by Bob); 5-6 are Help.
+		"main.go": {alice, alice, bob, alice, bob, bob},
+	}}
+	SetBlameSource(src)
+	t.Cleanup(func() { SetBlameSource(nil) })
+
+	records := []FileRecord{{
+		Path: "main.go",
+		Hash: "h1",
+		Analysis: &analysis.FileAnalysis{Symbols: []analysis.Symbol{
+			{Name: "Run", Kind: analysis.KindFunction, LineStart: 1, LineEnd: 4},
+			{Name: "Help", Kind: analysis.KindFunction, LineStart: 5, LineEnd: 6},
+		}},
+	}}
+	if _, err := WriteScan(db, "/repo", records, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+
+	owned, err := GetSymbolsOwnedBy(db, "ALICE", 0)
+	if err != nil {
+		t.Fatalf("GetSymbolsOwnedBy() error = %v", err)
+	}
+	if len(owned) != 1 || owned[0].Name != "Run" || owned[0].Owner != alice.Author || owned[0].LastCommit != "bbbb" {
+		t.Fatalf("Alice owns %+v, want Run with last commit bbbb", owned)
+	}
+	owned, _ = GetSymbolsOwnedBy(db, "bob@example.com", 0)
+	if len(owned) != 1 || owned[0].Name != "Help" {
+		t.Fatalf("Bob owns %+v, want Help", owned)
+	}
+
+	// An unchanged file is attributed from the cache.
+	records[0].Analysis.Symbols[0].Owner = ""
+	if _, err := WriteScan(db, "/repo", records, time.Now()); err != nil {
+		t.Fatalf("second WriteScan() error = %v", err)
+	}
+	if src.calls != 1 {
+		t.Errorf("blamed %d times, want 1 (second scan cached)", src.calls)
+	}
+	if owned, _ = GetSymbolsOwnedBy(db, "alice", 0); len(owned) != 1 {
+		t.Errorf("cached attribution lost Alice's symbol: %+v", owned)
+	}
+
+	records[0].Hash = "h2"
+	if _, err := WriteScan(db, "/repo", records, time.Now()); err != nil {
+		t.Fatalf("third WriteScan() error = %v", err)
+	}
+	if src.calls != 2 {
+		t.Errorf("blamed %d times, want 2 (changed file re-blamed)", src.calls)
+	}
+}
+
+func TestDominantOwnerSkipsUncommittedLines(t *testing.T) {
+	blame := []BlameLine{
+		{Author: "Not Committed Yet <not.committed.yet>", Commit: uncommittedCommit, Time: 900},
+		{Author: "Not Committed Yet <not.committed.yet>", Commit: uncommittedCommit, Time: 900},
+		{Author: "Carol <carol@example.com>", Commit: "cccc", Time: 50},
+	}
+	owner, commit := dominantOwner(blame, 1, 3)
+	if owner != "Carol <carol@example.com>" || commit != "cccc" {
+		t.Errorf("dominantOwner() = %q, %q; want Carol, cccc", owner, commit)
+	}
+	if owner, _ := dominantOwner(blame, 1, 2); owner != "" {
+		t.Errorf("uncommitted range owned by %q, want none", owner)
+	}
+}
+
+func TestParseLinePorcelain(t *testing.T) {
+	out := "1234567890abcdef1234567890abcdef12345678 1 1 1\n" +
+		"author Dana\n" +
+		"author-mail <dana@example.com>\n" +
+		"author-time 1700000000\n" +
+		"summary first\n" +
+		"filename main.go\n" +
+		"\tpackage main\n"
+	lines := parseLinePorcelain([]byte(out))
+	want := BlameLine{Author: "Dana <dana@example.com>", Commit: "1234567890abcdef1234567890abcdef12345678", Time: 1700000000}
+	if len(lines) != 1 || lines[0] != want {
+		t.Errorf("parseLinePorcelain() = %+v, want [%+v]", lines, want)
+	}
+}
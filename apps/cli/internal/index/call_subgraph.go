@@ -0,0 +1,222 @@
+package index
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CallSubgraph is the call neighborhood of one symbol: every function reached
+// from it (or, for incoming graphs, reaching it) within a depth, and the
+// calls between them.
+type CallSubgraph struct {
+	Root     string          `json:"root"`
+	Incoming bool            `json:"incoming,omitempty"`
+	Depth    int             `json:"depth"`
+	Nodes    []CallGraphNode `json:"nodes"`
+	Edges    []CallGraphEdge `json:"edges"`
+}
+
+// CallGraphNode is a function in a CallSubgraph. Unresolved nodes are call
+// targets that match no single indexed symbol, such as library calls; they
+// are not expanded further.
+type CallGraphNode struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	FilePath   string `json:"filePath,omitempty"`
+	Unresolved bool   `json:"unresolved,omitempty"`
+}
+
+// CallGraphEdge is a call from one node to another, at the first call site.
+type CallGraphEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Line       int    `json:"line"`
+	Unresolved bool   `json:"unresolved,omitempty"`
+}
+
+// GetCallSubgraph walks calls breadth-first from symbolName up to depth
+// levels: outgoing calls by default, callers when incoming is set. filePath
+// picks the definition when the name is defined in several files. Callees
+// resolve the way unresolved-call analysis resolves them: by bare name, to
+// the caller's own file or to the one file that defines it.
+func GetCallSubgraph(db *sql.DB, symbolName, filePath string, depth int, incoming bool) (*CallSubgraph, error) {
+	if depth <= 0 {
+		depth = 2
+	}
+	if depth > 10 {
+		depth = 10 // Same cap as call chains
+	}
+	definedIn, err := symbolFilesByName(db)
+	if err != nil {
+		return nil, err
+	}
+	if filePath == "" {
+		filePath = singleFile(definedIn[symbolName])
+	}
+	if filePath == "" || !definedIn[symbolName][filePath] {
+		if len(definedIn[symbolName]) > 1 {
+			return nil, fmt.Errorf("symbol %s is defined in several files; pick one with --file", symbolName)
+		}
+		return nil, fmt.Errorf("symbol not found: %s", symbolName)
+	}
+
+	g := &CallSubgraph{Root: symbolName, Incoming: incoming, Depth: depth}
+	ids := make(map[string]string)
+	node := func(name, file string, unresolved bool) (string, bool) {
+		key := name + "\x00" + file
+		if id, ok := ids[key]; ok {
+			return id, false
+		}
+		id := fmt.Sprintf("n%d", len(g.Nodes))
+		ids[key] = id
+		g.Nodes = append(g.Nodes, CallGraphNode{ID: id, Name: name, FilePath: file, Unresolved: unresolved})
+		return id, true
+	}
+
+	type frontier struct{ name, file, id string }
+	rootID, _ := node(symbolName, filePath, false)
+	level := []frontier{{symbolName, filePath, rootID}}
+	edges := make(map[string]bool)
+	addEdge := func(from, to string, line int, unresolved bool) {
+		if edges[from+"->"+to] {
+			return
+		}
+		edges[from+"->"+to] = true
+		g.Edges = append(g.Edges, CallGraphEdge{From: from, To: to, Line: line, Unresolved: unresolved})
+	}
+
+	for d := 0; d < depth && len(level) > 0; d++ {
+		var next []frontier
+		for _, f := range level {
+			var calls []CallSite
+			if incoming {
+				calls, err = GetIncomingCalls(db, f.name)
+			} else {
+				calls, err = GetOutgoingCalls(db, f.name, f.file)
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range calls {
+				if incoming {
+					if _, name := splitCallee(c.CalleeSymbol); name != f.name || resolveCallee(definedIn, c.CalleeSymbol, c.FilePath) != f.file {
+						continue
+					}
+					if c.CallerSymbol == "" {
+						// A top-level call outside any function
+						id, _ := node(c.FilePath, "", true)
+						addEdge(id, f.id, c.Line, true)
+						continue
+					}
+					id, added := node(c.CallerSymbol, c.FilePath, false)
+					addEdge(id, f.id, c.Line, false)
+					if added {
+						next = append(next, frontier{c.CallerSymbol, c.FilePath, id})
+					}
+					continue
+				}
+
+				calleeFile := resolveCallee(definedIn, c.CalleeSymbol, f.file)
+				if calleeFile == "" {
+					id, _ := node(strings.TrimSuffix(c.CalleeSymbol, "()"), "", true)
+					addEdge(f.id, id, c.Line, true)
+					continue
+				}
+				_, name := splitCallee(c.CalleeSymbol)
+				id, added := node(name, calleeFile, false)
+				addEdge(f.id, id, c.Line, false)
+				if added {
+					next = append(next, frontier{name, calleeFile, id})
+				}
+			}
+		}
+		level = next
+	}
+	return g, nil
+}
+
+// resolveCallee returns the file defining the target of a call made from
+// callerFile, or "" if the call does not resolve to a single symbol.
+func resolveCallee(definedIn map[string]map[string]bool, target, callerFile string) string {
+	_, name := splitCallee(target)
+	files := definedIn[name]
+	if files[callerFile] {
+		return callerFile
+	}
+	return singleFile(files)
+}
+
+// singleFile returns the only file in files, or "" if there are none or
+// several.
+func singleFile(files map[string]bool) string {
+	if len(files) != 1 {
+		return ""
+	}
+	for f := range files {
+		return f
+	}
+	return ""
+}
+
+// Mermaid renders the subgraph as a Mermaid flowchart. Unresolved nodes and
+// the calls to them are drawn dashed.
+func (g *CallSubgraph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		label := mermaidEscape(n.Name)
+		if n.FilePath != "" {
+			label += "<br/><small>" + mermaidEscape(n.FilePath) + "</small>"
+		}
+		if n.Unresolved {
+			fmt.Fprintf(&sb, "  %s([\"%s\"]):::ghost\n", n.ID, label)
+		} else {
+			fmt.Fprintf(&sb, "  %s[\"%s\"]\n", n.ID, label)
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Unresolved {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s %s\n", e.From, arrow, e.To)
+	}
+	sb.WriteString("  classDef ghost stroke-dasharray: 5 5,color:#888\n")
+	return sb.String()
+}
+
+// DOT renders the subgraph in Graphviz DOT. Unresolved nodes and the calls
+// to them are drawn dashed.
+func (g *CallSubgraph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph callgraph {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		label := n.Name
+		if n.FilePath != "" {
+			label += "\n" + n.FilePath
+		}
+		attrs := []string{"label=" + dotQuote(label)}
+		if n.Unresolved {
+			attrs = append(attrs, "style=dashed", "fontcolor=gray40")
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", n.ID, strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		if e.Unresolved {
+			fmt.Fprintf(&sb, "  %s -> %s [style=dashed];\n", e.From, e.To)
+		} else {
+			fmt.Fprintf(&sb, "  %s -> %s;\n", e.From, e.To)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
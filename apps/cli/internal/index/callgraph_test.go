@@ -86,3 +86,60 @@ func TestCallGraph(t *testing.T) {
 		}
 	})
 }
+
+func TestGetUnresolvedCalls(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, f := range []string{"main.go", "a.go", "b.go"} {
+		db.ExecContext(context.Background(), `INSERT INTO files(path, hash, size, mod_time, indexed_at, language) VALUES (?, ?, ?, ?, ?, ?);`, f, "h", 100, now, now, "go")
+	}
+	insertSym := func(file, name string) {
+		db.ExecContext(context.Background(), `INSERT INTO symbols(file_path, name, kind, line_start, line_end) VALUES (?, ?, 'function', 1, 10);`, file, name)
+	}
+	insertCall := func(target string, line int) {
+		db.ExecContext(context.Background(), `INSERT INTO relationships(source_file, target_symbol, kind, line, column) VALUES ('main.go', ?, 'call', ?, 1);`, target, line)
+	}
+	insertSym("main.go", "main")
+	insertSym("main.go", "helper")
+	insertSym("a.go", "Load")
+	insertSym("b.go", "Load")
+
+	insertCall("helper", 2)        // resolved: same file
+	insertCall("undefinedFunc", 3) // untracked
+	insertCall("undefinedFunc", 4)
+	insertCall("fmt.Println", 5) // external
+	insertCall("Load", 6)        // ambiguous
+
+	report, err := GetUnresolvedCalls(db, 50)
+	if err != nil {
+		t.Fatalf("GetUnresolvedCalls failed: %v", err)
+	}
+	if report.TotalCalls != 5 || report.Unresolved != 4 {
+		t.Errorf("expected 5 calls / 4 unresolved, got %d / %d", report.TotalCalls, report.Unresolved)
+	}
+	if len(report.Callees) != 3 {
+		t.Fatalf("expected 3 callees, got %+v", report.Callees)
+	}
+	top := report.Callees[0]
+	if top.Name != "undefinedFunc" || top.Count != 2 || top.Cause != UnresolvedUntracked || top.Example != "main.go:3" {
+		t.Errorf("unexpected top callee %+v", top)
+	}
+	causes := map[string]UnresolvedCause{}
+	for _, c := range report.Callees {
+		causes[c.Name] = c.Cause
+	}
+	if causes["fmt.Println"] != UnresolvedExternal || causes["Load"] != UnresolvedAmbiguous {
+		t.Errorf("unexpected causes %v", causes)
+	}
+
+	report, _ = GetUnresolvedCalls(db, 1)
+	if len(report.Callees) != 1 {
+		t.Errorf("expected --top to limit callees, got %d", len(report.Callees))
+	}
+}
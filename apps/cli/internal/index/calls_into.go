@@ -0,0 +1,125 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// BoundaryCallee is a symbol inside a directory that code outside the
+// directory calls or references.
+type BoundaryCallee struct {
+	Symbol      string   `json:"symbol"`
+	Kind        string   `json:"kind,omitempty"`
+	File        string   `json:"file"`
+	Line        int      `json:"line,omitempty"`
+	Calls       int      `json:"calls"`
+	References  int      `json:"references,omitempty"`
+	Callers     int      `json:"callers"` // Distinct files calling or referencing it
+	CallerFiles []string `json:"callerFiles"`
+}
+
+// GetCallsInto returns the symbols inside dir called or referenced from
+// files outside it, which make up the API the rest of the code actually
+// uses. Edges are resolved the way GetCallSubgraph resolves callees; edges
+// that resolve to no single file are left out. Symbols are ordered by
+// number of calling files, then by number of edges.
+func GetCallsInto(db *sql.DB, dir string) ([]BoundaryCallee, error) {
+	dir = strings.TrimPrefix(path.Clean(strings.ReplaceAll(dir, "\\", "/")), "./")
+	dir = strings.TrimSuffix(dir, "/")
+	inside := func(file string) bool {
+		return dir == "." || file == dir || strings.HasPrefix(file, dir+"/")
+	}
+
+	definedIn, err := symbolFilesByName(db)
+	if err != nil {
+		return nil, err
+	}
+	indexed := false
+	for _, files := range definedIn {
+		for f := range files {
+			indexed = indexed || inside(f)
+		}
+	}
+	if !indexed {
+		return nil, fmt.Errorf("no indexed symbols under %s", dir)
+	}
+
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT source_file, COALESCE(target_symbol, ''), kind
+		FROM relationships
+		WHERE kind IN ('call', 'reference')
+		ORDER BY source_file, line;`)
+	if err != nil {
+		return nil, fmt.Errorf("query relationships: %w", err)
+	}
+	defer rows.Close()
+
+	type key struct{ file, name string }
+	byTarget := make(map[key]*BoundaryCallee)
+	callers := make(map[key]map[string]bool)
+	for rows.Next() {
+		var src, target, kind string
+		if err := rows.Scan(&src, &target, &kind); err != nil {
+			return nil, err
+		}
+		if inside(src) {
+			continue
+		}
+		dst := resolveCallee(definedIn, target, src)
+		if dst == "" || !inside(dst) {
+			continue
+		}
+		_, name := splitCallee(target)
+		k := key{dst, name}
+		c := byTarget[k]
+		if c == nil {
+			c = &BoundaryCallee{Symbol: name, File: dst}
+			byTarget[k] = c
+			callers[k] = make(map[string]bool)
+		}
+		if kind == "call" {
+			c.Calls++
+		} else {
+			c.References++
+		}
+		callers[k][src] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]BoundaryCallee, 0, len(byTarget))
+	for k, c := range byTarget {
+		for f := range callers[k] {
+			c.CallerFiles = append(c.CallerFiles, f)
+		}
+		sort.Strings(c.CallerFiles)
+		c.Callers = len(c.CallerFiles)
+		if err := db.QueryRowContext(context.Background(), `
+			SELECT kind, line_start FROM symbols
+			WHERE file_path = ? AND name = ?
+			ORDER BY line_start LIMIT 1;`, c.File, c.Symbol).Scan(&c.Kind, &c.Line); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("query symbol %s: %w", c.Symbol, err)
+		}
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Callers != b.Callers {
+			return a.Callers > b.Callers
+		}
+		if a.Calls+a.References != b.Calls+b.References {
+			return a.Calls+a.References > b.Calls+b.References
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.File < b.File
+	})
+	return result, nil
+}
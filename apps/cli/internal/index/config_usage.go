@@ -0,0 +1,60 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// ConfigUsage is one read of a configuration or environment key, with the
+// function or method reading it when the read is inside one.
+type ConfigUsage struct {
+	Key      string `json:"key"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Symbol   string `json:"symbol,omitempty"`
+	Accessor string `json:"accessor,omitempty"` // How the file first reads the key, e.g. os.environ[
+}
+
+// GetConfigUsage returns the reads of the configuration key key, ordered by
+// file and line. Keys are indexed from string literals passed to accessors
+// such as os.Getenv, os.environ[...], or process.env[...]; see
+// analysis.FindConfigKeys.
+func GetConfigUsage(db *sql.DB, key string) ([]ConfigUsage, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, fmt.Errorf("config key is required")
+	}
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT r.source_file, r.line, COALESCE(k.signature, ''),
+			COALESCE((
+				SELECT s.name FROM symbols s
+				WHERE s.file_path = r.source_file AND s.kind IN (?, ?, ?)
+					AND s.line_start <= r.line AND s.line_end >= r.line
+				ORDER BY s.line_start DESC, s.id DESC
+				LIMIT 1
+			), '')
+		FROM relationships r
+		JOIN symbols k ON k.file_path = r.source_file AND k.name = r.target_symbol AND k.kind = ?
+		WHERE r.kind = ? AND r.target_symbol = ?
+		ORDER BY r.source_file, r.line, r.column;`,
+		analysis.KindFunction, analysis.KindMethod, analysis.KindConstructor,
+		analysis.KindConfigKey, analysis.RelReference, key)
+	if err != nil {
+		return nil, fmt.Errorf("query config usage: %w", err)
+	}
+	defer rows.Close()
+
+	usages := []ConfigUsage{}
+	for rows.Next() {
+		u := ConfigUsage{Key: key}
+		if err := rows.Scan(&u.File, &u.Line, &u.Accessor, &u.Symbol); err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+	return usages, rows.Err()
+}
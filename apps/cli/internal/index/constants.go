@@ -0,0 +1,52 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// ConstantValue is a constant, or variable, assigned a literal value.
+type ConstantValue struct {
+	File  string `json:"file"`
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Line  int    `json:"line"`
+	Value string `json:"value"`
+}
+
+// GetConstantValues returns the constants whose literal value was recorded,
+// ordered by file and line. With variables set, variables assigned a literal
+// are included too. A non-empty pathPrefix keeps only files under it.
+func GetConstantValues(db *sql.DB, pathPrefix string, variables bool) ([]ConstantValue, error) {
+	variableKind := ""
+	if variables {
+		variableKind = string(analysis.KindVariable)
+	}
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, name, kind, line_start, value
+		FROM symbols
+		WHERE kind IN (?, ?) AND COALESCE(value, '') != ''
+		ORDER BY file_path, line_start, id;
+	`, analysis.KindConstant, variableKind)
+	if err != nil {
+		return nil, fmt.Errorf("query constant values: %w", err)
+	}
+	defer rows.Close()
+
+	var result []ConstantValue
+	for rows.Next() {
+		var c ConstantValue
+		if err := rows.Scan(&c.File, &c.Name, &c.Kind, &c.Line, &c.Value); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(c.File, pathPrefix) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
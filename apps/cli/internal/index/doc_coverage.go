@@ -0,0 +1,101 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// DocCoverage is how many exported symbols carry a doc comment.
+type DocCoverage struct {
+	Exported   int     `json:"exported"`
+	Documented int     `json:"documented"`
+	Percent    float64 `json:"percent"` // 100 when there is nothing to document
+}
+
+// DocCoverageReport is the documentation coverage of the indexed workspace,
+// overall and by language, with the exported symbols lacking documentation.
+type DocCoverageReport struct {
+	DocCoverage
+	Languages    map[string]DocCoverage `json:"languages"`
+	Undocumented []UndocumentedSymbol   `json:"undocumented"`
+}
+
+// UndocumentedSymbol is an exported symbol without a doc comment.
+type UndocumentedSymbol struct {
+	File     string `json:"file"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Line     int    `json:"line"`
+	Language string `json:"language"`
+}
+
+// GetDocCoverage reports which exported symbols have a non-empty doc
+// comment. Synthetic kinds such as findings, and the symbols of data and markup files, are not an
+// API and are left out.
+func GetDocCoverage(db *sql.DB) (*DocCoverageReport, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT s.file_path, s.name, s.kind, s.line_start, COALESCE(s.doc_comment, ''), COALESCE(f.language, '')
+		FROM symbols s
+		LEFT JOIN files f ON f.path = s.file_path
+		WHERE s.exported = 1 AND s.kind NOT IN (?, ?, ?)
+		ORDER BY s.file_path, s.line_start, s.id;
+	`, analysis.KindFinding, analysis.KindTest, analysis.KindConfigKey)
+	if err != nil {
+		return nil, fmt.Errorf("query exported symbols: %w", err)
+	}
+	defer rows.Close()
+
+	report := &DocCoverageReport{Languages: make(map[string]DocCoverage), Undocumented: []UndocumentedSymbol{}}
+	for rows.Next() {
+		var s UndocumentedSymbol
+		var doc string
+		if err := rows.Scan(&s.File, &s.Name, &s.Kind, &s.Line, &doc, &s.Language); err != nil {
+			return nil, err
+		}
+		if !analysis.HasAPI(analysis.Language(s.Language)) {
+			continue
+		}
+		lang := report.Languages[s.Language]
+		lang.Exported++
+		report.Exported++
+		if strings.TrimSpace(doc) != "" {
+			lang.Documented++
+			report.Documented++
+		} else {
+			report.Undocumented = append(report.Undocumented, s)
+		}
+		report.Languages[s.Language] = lang
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report.Percent = coveragePercent(report.Documented, report.Exported)
+	for name, lang := range report.Languages {
+		lang.Percent = coveragePercent(lang.Documented, lang.Exported)
+		report.Languages[name] = lang
+	}
+	return report, nil
+}
+
+// SortedLanguages returns the languages of the report by name.
+func (r *DocCoverageReport) SortedLanguages() []string {
+	names := make([]string, 0, len(r.Languages))
+	for name := range r.Languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func coveragePercent(documented, exported int) float64 {
+	if exported == 0 {
+		return 100
+	}
+	return float64(documented) * 100 / float64(exported)
+}
@@ -0,0 +1,148 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// ExportSymbol is one indexed symbol in a flat, tabular shape. Complexity
+// is the estimated cyclomatic complexity of functions, methods, and
+// constructors, and 0 for other kinds.
+type ExportSymbol struct {
+	FilePath   string
+	Name       string
+	Kind       string
+	Exported   bool
+	LineStart  int
+	LineCount  int
+	Complexity int
+}
+
+// ExportRelationship is one indexed relationship in a flat, tabular shape.
+// From is the innermost function or method enclosing the relationship, or
+// empty at file level; To is the target symbol, or the target file for imports.
+// Confidence is how surely To resolves to one definition: 1 for imports and
+// for targets defined in the relationship's own file, 1/n for targets defined
+// in n other files, and 0 for targets the index does not define.
+type ExportRelationship struct {
+	From       string
+	To         string
+	Kind       string
+	Confidence float64
+	File       string
+	Line       int
+}
+
+// GetExportSymbols returns every indexed symbol of the workspace at root
+// ordered by file and position. Complexity is estimated from each symbol's
+// source as it is on disk now.
+func GetExportSymbols(db *sql.DB, root string) ([]ExportSymbol, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, name, kind, exported, line_start, line_end
+		FROM symbols
+		ORDER BY file_path, line_start, id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var result []ExportSymbol
+	for rows.Next() {
+		var s ExportSymbol
+		var exported, lineEnd int
+		if err := rows.Scan(&s.FilePath, &s.Name, &s.Kind, &exported, &s.LineStart, &lineEnd); err != nil {
+			return nil, err
+		}
+		s.Exported = exported == 1
+		s.LineCount = lineEnd - s.LineStart + 1
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]string)
+	for i := range result {
+		s := &result[i]
+		switch analysis.SymbolKind(s.Kind) {
+		case analysis.KindFunction, analysis.KindMethod, analysis.KindConstructor:
+		default:
+			continue
+		}
+		lines, ok := files[s.FilePath]
+		if !ok {
+			if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(s.FilePath))); err == nil {
+				lines = strings.Split(string(data), "\n")
+			}
+			files[s.FilePath] = lines
+		}
+		s.Complexity = 1
+		if s.LineStart >= 1 && s.LineStart <= len(lines) {
+			end := min(s.LineStart+s.LineCount-1, len(lines))
+			s.Complexity = analysis.EstimateComplexity(strings.Join(lines[s.LineStart-1:end], "\n"))
+		}
+	}
+	return result, nil
+}
+
+// GetExportRelationships returns every indexed relationship ordered by file and line.
+func GetExportRelationships(db *sql.DB) ([]ExportRelationship, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT COALESCE((
+		           SELECT s.name FROM symbols s
+		           WHERE s.file_path = r.source_file
+		           AND s.line_start <= r.line AND s.line_end >= r.line
+		           AND s.kind IN ('function', 'method')
+		           ORDER BY (s.line_end - s.line_start) ASC
+		           LIMIT 1
+		       ), ''),
+		       COALESCE(NULLIF(r.target_symbol, ''), r.target_file, ''),
+		       r.kind, r.source_file, r.line
+		FROM relationships r
+		ORDER BY r.source_file, r.line, r.id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query relationships: %w", err)
+	}
+	defer rows.Close()
+
+	var result []ExportRelationship
+	for rows.Next() {
+		var r ExportRelationship
+		if err := rows.Scan(&r.From, &r.To, &r.Kind, &r.File, &r.Line); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byName, err := symbolFilesByName(db)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		r := &result[i]
+		if analysis.RelationshipKind(r.Kind) == analysis.RelImport {
+			r.Confidence = 1
+			continue
+		}
+		_, name := splitCallee(r.To)
+		files := byName[name]
+		switch {
+		case files[r.File]:
+			r.Confidence = 1
+		case len(files) > 0:
+			r.Confidence = 1 / float64(len(files))
+		}
+	}
+	return result, nil
+}
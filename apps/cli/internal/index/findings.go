@@ -0,0 +1,73 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// SecretFinding is a likely hardcoded secret recorded by a scan run with
+// secret detection enabled. It locates the secret without containing it.
+type SecretFinding struct {
+	File        string `json:"file"`
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Line        int    `json:"line"`
+}
+
+// GetSecretFindings returns the indexed secret findings ordered by file and
+// line. It is empty unless a scan was run with secret detection enabled.
+func GetSecretFindings(db *sql.DB) ([]SecretFinding, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, name, COALESCE(signature, ''), line_start
+		FROM symbols WHERE kind = ? AND name != ?
+		ORDER BY file_path, line_start, id;
+	`, string(analysis.KindFinding), analysis.CommentedCodeRule)
+	if err != nil {
+		return nil, fmt.Errorf("query findings: %w", err)
+	}
+	defer rows.Close()
+
+	var result []SecretFinding
+	for rows.Next() {
+		var f SecretFinding
+		if err := rows.Scan(&f.File, &f.Rule, &f.Description, &f.Line); err != nil {
+			return nil, err
+		}
+		result = append(result, f)
+	}
+	return result, rows.Err()
+}
+
+// CommentedCode is a block of commented-out code recorded by a scan.
+type CommentedCode struct {
+	File      string `json:"file"`
+	LineStart int    `json:"lineStart"`
+	LineEnd   int    `json:"lineEnd"`
+}
+
+// GetCommentedCode returns the indexed blocks of commented-out code ordered
+// by file and line.
+func GetCommentedCode(db *sql.DB) ([]CommentedCode, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, line_start, line_end
+		FROM symbols WHERE kind = ? AND name = ?
+		ORDER BY file_path, line_start, id;
+	`, string(analysis.KindFinding), analysis.CommentedCodeRule)
+	if err != nil {
+		return nil, fmt.Errorf("query commented-out code: %w", err)
+	}
+	defer rows.Close()
+
+	var result []CommentedCode
+	for rows.Next() {
+		var c CommentedCode
+		if err := rows.Scan(&c.File, &c.LineStart, &c.LineEnd); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
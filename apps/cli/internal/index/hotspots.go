@@ -0,0 +1,125 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// HotspotWeights weigh the metrics of a hotspot score against each other.
+// Only their ratios matter; a zero weight leaves a metric out.
+type HotspotWeights struct {
+	Complexity float64 `json:"complexity"`
+	Size       float64 `json:"size"`
+	Churn      float64 `json:"churn"`
+}
+
+// DefaultHotspotWeights counts complexity, size, and churn equally.
+var DefaultHotspotWeights = HotspotWeights{Complexity: 1, Size: 1, Churn: 1}
+
+// Hotspot is a function or method ranked by how risky it is to change.
+type Hotspot struct {
+	File       string  `json:"file"`
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	LineStart  int     `json:"lineStart"`
+	LineEnd    int     `json:"lineEnd"`
+	Lines      int     `json:"lines"`
+	Complexity int     `json:"complexity"`      // Estimated cyclomatic complexity
+	Churn      int     `json:"churn,omitempty"` // Commits touching the file
+	Score      float64 `json:"score"`           // 0 to 1
+}
+
+// GetHotspots scores the indexed functions and methods of the workspace at
+// root and returns the top highest scoring, or all of them for top <= 0.
+// Complexity is estimated from each symbol's source as it is on disk now.
+// churn holds commit counts by file; when it is nil, as outside a git
+// repository, the score uses complexity and size only.
+func GetHotspots(db *sql.DB, root string, churn map[string]int, weights HotspotWeights, top int) ([]Hotspot, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, name, kind, line_start, line_end
+		FROM symbols
+		WHERE kind IN (?, ?, ?)
+		ORDER BY file_path, line_start, id;
+	`, analysis.KindFunction, analysis.KindMethod, analysis.KindConstructor)
+	if err != nil {
+		return nil, fmt.Errorf("query hotspot candidates: %w", err)
+	}
+	var candidates []Hotspot
+	for rows.Next() {
+		var h Hotspot
+		if err := rows.Scan(&h.File, &h.Name, &h.Kind, &h.LineStart, &h.LineEnd); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidates = append(candidates, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]string)
+	for i := range candidates {
+		h := &candidates[i]
+		lines, ok := files[h.File]
+		if !ok {
+			if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(h.File))); err == nil {
+				lines = strings.Split(string(data), "\n")
+			}
+			files[h.File] = lines
+		}
+		h.Lines = h.LineEnd - h.LineStart + 1
+		h.Complexity = 1
+		if h.LineStart >= 1 && h.LineStart <= len(lines) {
+			end := min(h.LineEnd, len(lines))
+			h.Complexity = analysis.EstimateComplexity(strings.Join(lines[h.LineStart-1:end], "\n"))
+		}
+		h.Churn = churn[h.File]
+	}
+	return RankHotspots(candidates, weights, churn != nil, top), nil
+}
+
+// RankHotspots scores candidates and returns the top highest scoring, or
+// all of them for top <= 0. Each metric is scaled against its largest value
+// among the candidates, and the score is the weighted average of the scaled
+// metrics. Churn counts only when withChurn is set.
+func RankHotspots(candidates []Hotspot, weights HotspotWeights, withChurn bool, top int) []Hotspot {
+	if !withChurn {
+		weights.Churn = 0
+	}
+	total := weights.Complexity + weights.Size + weights.Churn
+	var maxComplexity, maxLines, maxChurn int
+	for _, h := range candidates {
+		maxComplexity = max(maxComplexity, h.Complexity)
+		maxLines = max(maxLines, h.Lines)
+		maxChurn = max(maxChurn, h.Churn)
+	}
+	scale := func(v, maxV int) float64 {
+		if maxV == 0 {
+			return 0
+		}
+		return float64(v) / float64(maxV)
+	}
+
+	ranked := append([]Hotspot(nil), candidates...)
+	for i := range ranked {
+		h := &ranked[i]
+		if total > 0 {
+			h.Score = (weights.Complexity*scale(h.Complexity, maxComplexity) +
+				weights.Size*scale(h.Lines, maxLines) +
+				weights.Churn*scale(h.Churn, maxChurn)) / total
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	if top > 0 && len(ranked) > top {
+		ranked = ranked[:top]
+	}
+	return ranked
+}
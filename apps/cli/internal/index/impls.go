@@ -0,0 +1,331 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// MethodImpl is a type's own version of an interface method.
+type MethodImpl struct {
+	Type      string `json:"type"`
+	TypeFile  string `json:"typeFile"`
+	TypeLine  int    `json:"typeLine"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Signature string `json:"signature"`
+	Via       string `json:"via"` // "implements", "extends", or "method set" (Go)
+}
+
+// typeRef is a type symbol found in the index.
+type typeRef struct {
+	id         int64
+	file, name string
+	line       int
+	via        string
+}
+
+// FindMethodImplementations lists the types that implement method of the
+// interface (or base class) named iface, with their own declaration of the
+// method. Types are found by following implements and extends
+// relationships transitively, so a class is found through any of its
+// supertypes, and, for Go, by method set: a type implements an interface
+// when it has methods named like all of the interface's. A type that only
+// inherits the method is not listed. Results are ordered by file and line.
+func FindMethodImplementations(db *sql.DB, iface, method string) ([]MethodImpl, error) {
+	ifaces, err := typesNamed(db, iface)
+	if err != nil {
+		return nil, err
+	}
+	if len(ifaces) > 0 {
+		found := false
+		for _, t := range ifaces {
+			if names, err := childMethodNames(db, t.id); err != nil {
+				return nil, err
+			} else if names[method] || len(names) == 0 {
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s has no method %s", iface, method)
+		}
+	}
+
+	var implementers []typeRef
+	seen := make(map[string]bool)
+	queue := []string{iface}
+	for visited := map[string]bool{iface: true}; len(queue) > 0; queue = queue[1:] {
+		subtypes, err := nominalSubtypes(db, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range subtypes {
+			key := fmt.Sprintf("%s:%d", t.file, t.line)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			implementers = append(implementers, t)
+			if !visited[t.name] {
+				visited[t.name] = true
+				queue = append(queue, t.name)
+			}
+		}
+	}
+
+	var result []MethodImpl
+	for _, t := range implementers {
+		rows, err := db.QueryContext(context.Background(), `
+			SELECT file_path, line_start, COALESCE(signature, '') FROM symbols
+			WHERE parent_id = ? AND name = ? AND kind IN (?, ?)
+			ORDER BY line_start;
+		`, t.id, method, analysis.KindMethod, analysis.KindFunction)
+		if err != nil {
+			return nil, fmt.Errorf("query methods of %s: %w", t.name, err)
+		}
+		for rows.Next() {
+			m := MethodImpl{Type: t.name, TypeFile: t.file, TypeLine: t.line, Via: t.via}
+			if err := rows.Scan(&m.File, &m.Line, &m.Signature); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			result = append(result, m)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	goImpls, err := goMethodSetImplementations(db, ifaces, method)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, goImpls...)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result, nil
+}
+
+// typesNamed returns the interfaces and classes called name.
+func typesNamed(db *sql.DB, name string) ([]typeRef, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT id, file_path, name, line_start FROM symbols
+		WHERE name = ? AND kind IN (?, ?)
+		ORDER BY file_path, line_start;
+	`, name, analysis.KindInterface, analysis.KindClass)
+	if err != nil {
+		return nil, fmt.Errorf("query type %s: %w", name, err)
+	}
+	defer rows.Close()
+	var types []typeRef
+	for rows.Next() {
+		var t typeRef
+		if err := rows.Scan(&t.id, &t.file, &t.name, &t.line); err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, rows.Err()
+}
+
+// childMethodNames returns the names of the methods declared in a type.
+func childMethodNames(db *sql.DB, typeID int64) (map[string]bool, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT name FROM symbols WHERE parent_id = ? AND kind IN (?, ?);
+	`, typeID, analysis.KindMethod, analysis.KindFunction)
+	if err != nil {
+		return nil, fmt.Errorf("query type methods: %w", err)
+	}
+	defer rows.Close()
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
+
+// nominalSubtypes returns the types declared to implement or extend name,
+// matching "Name", "pkg.Name", and "Name<T>" references. Each relationship
+// is attributed to the innermost type whose lines contain it.
+func nominalSubtypes(db *sql.DB, name string) ([]typeRef, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT r.id, r.kind, s.id, s.file_path, s.name, s.line_start
+		FROM relationships r
+		JOIN symbols s ON s.file_path = r.source_file
+			AND r.line BETWEEN s.line_start AND s.line_end
+			AND s.kind IN (?, ?, ?, ?)
+		WHERE r.kind IN (?, ?)
+			AND (r.target_symbol = ? OR r.target_symbol LIKE ? OR r.target_symbol LIKE ?)
+		ORDER BY r.id, s.line_end - s.line_start;
+	`, analysis.KindClass, analysis.KindInterface, analysis.KindEnum, analysis.KindType,
+		analysis.RelImplements, analysis.RelExtends,
+		name, "%."+name, name+"<%")
+	if err != nil {
+		return nil, fmt.Errorf("query subtypes of %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	var types []typeRef
+	seenRel := make(map[int64]bool)
+	for rows.Next() {
+		var relID int64
+		var t typeRef
+		if err := rows.Scan(&relID, &t.via, &t.id, &t.file, &t.name, &t.line); err != nil {
+			return nil, err
+		}
+		// Rows for one relationship come innermost type first; the types
+		// enclosing it are not the implementer
+		if seenRel[relID] {
+			continue
+		}
+		seenRel[relID] = true
+		if t.name != name {
+			types = append(types, t)
+		}
+	}
+	return types, rows.Err()
+}
+
+// goMethodSetImplementations finds the Go types whose methods include all of
+// those of the Go interfaces among ifaces, and returns their version of
+// method. Requiring the whole method set keeps a type that merely has a
+// method of the same name from being listed.
+func goMethodSetImplementations(db *sql.DB, ifaces []typeRef, method string) ([]MethodImpl, error) {
+	var required []map[string]bool
+	for _, t := range ifaces {
+		if !strings.HasSuffix(t.file, ".go") {
+			continue
+		}
+		names, err := childMethodNames(db, t.id)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			names = map[string]bool{method: true}
+		}
+		required = append(required, names)
+	}
+	if len(required) == 0 {
+		return nil, nil
+	}
+
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, name, line_start, COALESCE(signature, '') FROM symbols
+		WHERE kind = ? AND parent_id IS NULL AND file_path LIKE '%.go'
+		ORDER BY file_path, line_start;
+	`, analysis.KindMethod)
+	if err != nil {
+		return nil, fmt.Errorf("query go methods: %w", err)
+	}
+	defer rows.Close()
+
+	type methodSet struct {
+		dir   string
+		names map[string]bool
+		impl  *MethodImpl
+	}
+	sets := make(map[string]*methodSet) // By package directory and receiver type
+	var order []string
+	for rows.Next() {
+		var file, name, sig string
+		var line int
+		if err := rows.Scan(&file, &name, &line, &sig); err != nil {
+			return nil, err
+		}
+		recv := goReceiverType(sig)
+		if recv == "" {
+			continue
+		}
+		dir := file[:strings.LastIndex(file, "/")+1]
+		key := dir + recv
+		set, ok := sets[key]
+		if !ok {
+			set = &methodSet{dir: dir, names: make(map[string]bool)}
+			sets[key] = set
+			order = append(order, key)
+		}
+		set.names[name] = true
+		if name == method {
+			set.impl = &MethodImpl{Type: recv, File: file, Line: line, Signature: sig, Via: "method set"}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var result []MethodImpl
+	for _, key := range order {
+		set := sets[key]
+		if set.impl == nil {
+			continue
+		}
+		for _, names := range required {
+			if hasAll(set.names, names) {
+				impl := *set.impl
+				impl.TypeFile, impl.TypeLine = goTypeDecl(db, set.dir, impl.Type)
+				result = append(result, impl)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// goReceiverType reads the receiver's type name from a Go method signature:
+// "(s *Server) Serve(addr string) error" gives "Server".
+func goReceiverType(sig string) string {
+	if !strings.HasPrefix(sig, "(") {
+		return ""
+	}
+	end := strings.IndexByte(sig, ')')
+	if end < 0 {
+		return ""
+	}
+	fields := strings.Fields(sig[1:end])
+	if len(fields) == 0 {
+		return ""
+	}
+	recv := strings.TrimLeft(fields[len(fields)-1], "*")
+	if i := strings.IndexByte(recv, '['); i >= 0 {
+		recv = recv[:i] // Generic receiver: "(l *List[T])"
+	}
+	return recv
+}
+
+// goTypeDecl finds where a Go type is declared in a package directory.
+func goTypeDecl(db *sql.DB, dir, name string) (string, int) {
+	var file string
+	var line int
+	err := db.QueryRowContext(context.Background(), `
+		SELECT file_path, line_start FROM symbols
+		WHERE name = ? AND parent_id IS NULL AND file_path LIKE ? AND kind != ?
+		ORDER BY file_path LIMIT 1;
+	`, name, dir+"%.go", analysis.KindMethod).Scan(&file, &line)
+	if err != nil {
+		return "", 0
+	}
+	return file, line
+}
+
+func hasAll(have, want map[string]bool) bool {
+	for name := range want {
+		if !have[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package index
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+func TestFindMethodImplementations(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	files := map[string]string{
+		"server/server.go": `package server
+
+type Server interface {
+	Serve(addr string) error
+	Close() error
+}
+
+type HTTP struct{}
+
+func (h *HTTP) Serve(addr string) error { return nil }
+func (h *HTTP) Close() error            { return nil }
+
+type GRPC struct{}
+
+func (g GRPC) Serve(addr string) error { return nil }
+func (g GRPC) Close() error           { return nil }
+
+// Worker has a Serve method but no Close, so it is not a Server.
+type Worker struct{}
+
+func (w *Worker) Serve(addr string) error { return nil }
+`,
+		"java/Handler.java": `interface Handler { void handle(); }
+
+interface Named { String name(); }
+
+class Base implements Handler, Named {
+    public void handle() {}
+    public String name() { return "base"; }
+}
+
+class Derived extends Base {
+    public void handle() {}
+}
+
+class Other {
+    public void handle() {}
+}
+`,
+	}
+	var records []FileRecord
+	for path, src := range files {
+		fa, err := analysis.Analyze([]byte(src), path)
+		if err != nil {
+			t.Fatalf("Analyze(%s) error = %v", path, err)
+		}
+		records = append(records, FileRecord{Path: path, Hash: path, Analysis: fa})
+	}
+	if _, err := WriteScan(db, "/repo", records, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+
+	impls, err := FindMethodImplementations(db, "Server", "Serve")
+	if err != nil {
+		t.Fatalf("FindMethodImplementations(Server.Serve) error = %v", err)
+	}
+	if len(impls) != 2 || impls[0].Type != "HTTP" || impls[1].Type != "GRPC" {
+		t.Fatalf("Server.Serve implementations = %+v, want HTTP and GRPC", impls)
+	}
+	if impls[0].File != "server/server.go" || impls[0].Line != 10 || impls[0].TypeLine != 8 {
+		t.Errorf("HTTP.Serve at %s:%d (type line %d), want server/server.go:10 (type line 8)", impls[0].File, impls[0].Line, impls[0].TypeLine)
+	}
+
+	// Derived is found through Base; Other's handle is a name collision.
+	impls, err = FindMethodImplementations(db, "Handler", "handle")
+	if err != nil {
+		t.Fatalf("FindMethodImplementations(Handler.handle) error = %v", err)
+	}
+	var types []string
+	for _, impl := range impls {
+		types = append(types, impl.Type)
+	}
+	if len(types) != 2 || types[0] != "Base" || types[1] != "Derived" {
+		t.Fatalf("Handler.handle implementations = %v, want [Base Derived]", types)
+	}
+	// Base also implements its second interface.
+	if impls, _ = FindMethodImplementations(db, "Named", "name"); len(impls) != 1 || impls[0].Type != "Base" {
+		t.Errorf("Named.name implementations = %+v, want Base", impls)
+	}
+
+	if _, err := FindMethodImplementations(db, "Server", "Listen"); err == nil {
+		t.Error("FindMethodImplementations(Server.Listen) succeeded, want an error for a method the interface lacks")
+	}
+}
+
+func TestFindMethodImplementationsNestedClass(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	src := `interface Handler { void handle(); }
+
+class Outer {
+    public void handle() {}
+
+    static class Inner implements Handler {
+        public void handle() {}
+    }
+}
+`
+	fa, err := analysis.Analyze([]byte(src), "Outer.java")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if _, err := WriteScan(db, "/repo", []FileRecord{{Path: "Outer.java", Hash: "h", Analysis: fa}}, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+
+	impls, err := FindMethodImplementations(db, "Handler", "handle")
+	if err != nil {
+		t.Fatalf("FindMethodImplementations() error = %v", err)
+	}
+	if len(impls) != 1 || impls[0].Type != "Inner" || impls[0].Line != 7 {
+		t.Errorf("Handler.handle implementations = %+v, want only Inner at line 7", impls)
+	}
+}
@@ -0,0 +1,68 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ImportRef is one indexed import of a module by a file.
+type ImportRef struct {
+	File   string `json:"file"`
+	Module string `json:"module"`
+	Kind   string `json:"kind"` // local, stdlib, or thirdparty
+	Line   int    `json:"line"`
+}
+
+// ImportsOptions filters GetImports.
+type ImportsOptions struct {
+	Kind   string // Only imports of this kind (empty = all)
+	Module string // Only imports of this module or its submodules (empty = all)
+}
+
+// GetImports returns the indexed imports matching opts, ordered by file and
+// line. A module matches itself and anything nested under it, so "requests"
+// also matches "requests.adapters", "lodash/fp", and "serde::de".
+func GetImports(db *sql.DB, opts ImportsOptions) ([]ImportRef, error) {
+	query := `SELECT source_file, target_file, COALESCE(import_kind, ''), line
+		FROM relationships WHERE kind = 'import' AND COALESCE(target_file, '') != ''`
+	var args []any
+	if opts.Kind != "" {
+		query += ` AND import_kind = ?`
+		args = append(args, opts.Kind)
+	}
+	query += ` ORDER BY source_file, line, id`
+
+	rows, err := db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query imports: %w", err)
+	}
+	defer rows.Close()
+
+	var result []ImportRef
+	for rows.Next() {
+		var ref ImportRef
+		if err := rows.Scan(&ref.File, &ref.Module, &ref.Kind, &ref.Line); err != nil {
+			return nil, err
+		}
+		if opts.Module != "" && !moduleMatches(ref.Module, opts.Module) {
+			continue
+		}
+		result = append(result, ref)
+	}
+	return result, rows.Err()
+}
+
+// moduleMatches reports whether target is module or one of its submodules.
+func moduleMatches(target, module string) bool {
+	if target == module {
+		return true
+	}
+	for _, sep := range []string{".", "/", "::"} {
+		if strings.HasPrefix(target, module+sep) {
+			return true
+		}
+	}
+	return false
+}
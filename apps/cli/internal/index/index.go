@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite" // sqlite driver for database/sql
@@ -17,6 +18,7 @@ import (
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/limiter"
 )
 
 // FileRecord represents an indexed file with its content and analysis.
@@ -42,6 +44,8 @@ type ScanSummary struct {
 	RelationshipCount int
 	StartedAt         time.Time
 	CompletedAt       time.Time
+	Warnings          []string // Per-file analysis warnings, prefixed with the file path
+	Partial           bool     // The scan was cancelled; only some files were indexed
 }
 
 // Open opens the sqlite database at the given path and applies pragmas.
@@ -88,6 +92,26 @@ var indexMigrations = []func(*sql.Tx) error{
 	indexMigrateV0,
 	// Migration 1: Add git commit hash tracking to scans
 	indexMigrateV1,
+	// Migration 2: Add symbol annotations (decorators, attributes)
+	indexMigrateV2,
+	// Migration 3: Classify imports as local, stdlib, or thirdparty
+	indexMigrateV3,
+	// Migration 4: Mark scans that were cancelled before finishing
+	indexMigrateV4,
+	// Migration 5: Flag deprecated and experimental symbols
+	indexMigrateV5,
+	// Migration 6: Attribute symbols to owners from git blame
+	indexMigrateV6,
+	// Migration 7: Record the literal values of constants
+	indexMigrateV7,
+	// Migration 8: Running counts of files, chunks, symbols, and relationships
+	indexMigrateV8,
+	// Migration 9: Count the assertions of tests
+	indexMigrateV9,
+	// Migration 10: Key callables by parameter types to tell overloads apart
+	indexMigrateV10,
+	// Migration 11: Flag symbols guessed by heuristic parsers
+	indexMigrateV11,
 }
 
 // indexMigrateV0 creates the initial index schema (version 0)
@@ -227,6 +251,151 @@ func indexMigrateV1(tx *sql.Tx) error {
 	return nil
 }
 
+// indexMigrateV2 adds the symbol_annotations table
+func indexMigrateV2(tx *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS symbol_annotations (
+            symbol_id INTEGER NOT NULL,
+            annotation TEXT NOT NULL,
+            FOREIGN KEY(symbol_id) REFERENCES symbols(id) ON DELETE CASCADE
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_symbol_annotations_symbol ON symbol_annotations(symbol_id);`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			return fmt.Errorf("create symbol_annotations: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexMigrateV3 adds the import_kind column to relationships
+func indexMigrateV3(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE relationships ADD COLUMN import_kind TEXT DEFAULT '';`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return fmt.Errorf("add import_kind column: %w", err)
+	}
+	if _, err := tx.ExecContext(context.Background(), `CREATE INDEX IF NOT EXISTS idx_rel_import_kind ON relationships(import_kind);`); err != nil {
+		return fmt.Errorf("create import_kind index: %w", err)
+	}
+	return nil
+}
+
+// indexMigrateV4 adds the partial flag to scans
+func indexMigrateV4(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE scans ADD COLUMN partial INTEGER DEFAULT 0;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return fmt.Errorf("add partial column: %w", err)
+	}
+	return nil
+}
+
+// indexMigrateV5 adds the deprecated and experimental flags to symbols
+func indexMigrateV5(tx *sql.Tx) error {
+	for _, column := range []string{"deprecated", "experimental"} {
+		_, err := tx.ExecContext(context.Background(), `ALTER TABLE symbols ADD COLUMN `+column+` INTEGER DEFAULT 0;`)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			return fmt.Errorf("add %s column: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// indexMigrateV6 adds symbol ownership and the blame cache
+func indexMigrateV6(tx *sql.Tx) error {
+	for _, column := range []string{"owner", "last_commit"} {
+		_, err := tx.ExecContext(context.Background(), `ALTER TABLE symbols ADD COLUMN `+column+` TEXT DEFAULT '';`)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			return fmt.Errorf("add %s column: %w", column, err)
+		}
+	}
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS blame_cache (
+            path TEXT PRIMARY KEY,
+            hash TEXT NOT NULL,
+            lines TEXT NOT NULL
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_symbols_owner ON symbols(owner);`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			return fmt.Errorf("create blame cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexMigrateV7 adds the literal value of constants and variables to symbols
+func indexMigrateV7(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE symbols ADD COLUMN value TEXT DEFAULT '';`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return fmt.Errorf("add value column: %w", err)
+	}
+	return nil
+}
+
+// indexMigrateV8 adds index_stats, counts of the indexed rows kept current
+// by triggers, so statistics are read without scanning the tables. The
+// counts start from the rows already indexed.
+func indexMigrateV8(tx *sql.Tx) error {
+	stmts := []string{`CREATE TABLE IF NOT EXISTS index_stats (
+            metric TEXT NOT NULL,
+            kind TEXT NOT NULL DEFAULT '',
+            count INTEGER NOT NULL DEFAULT 0,
+            PRIMARY KEY (metric, kind)
+        );`}
+	for _, c := range statCounters {
+		stmts = append(stmts,
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_stats_insert AFTER INSERT ON %[1]s BEGIN
+                INSERT INTO index_stats(metric, kind, count) VALUES('%[2]s', %[3]s, 1)
+                ON CONFLICT(metric, kind) DO UPDATE SET count = count + 1;
+            END;`, c.table, c.metric, c.kindExpr("NEW")),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_stats_delete AFTER DELETE ON %[1]s BEGIN
+                UPDATE index_stats SET count = count - 1 WHERE metric = '%[2]s' AND kind = %[3]s;
+            END;`, c.table, c.metric, c.kindExpr("OLD")))
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+			return fmt.Errorf("create index stats: %w", err)
+		}
+	}
+	return resetStats(tx)
+}
+
+// indexMigrateV9 adds the assertion count of test functions and subtests to
+// symbols. It is NULL for symbols that are not tests.
+func indexMigrateV9(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE symbols ADD COLUMN assertions INTEGER;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return fmt.Errorf("add assertions column: %w", err)
+	}
+	return nil
+}
+
+// indexMigrateV10 adds the overload key of functions, methods, and
+// constructors to symbols (see analysis.SymbolKey). Symbols indexed before
+// it have an empty key until they are scanned again.
+func indexMigrateV10(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE symbols ADD COLUMN overload_key TEXT DEFAULT '';`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return fmt.Errorf("add overload_key column: %w", err)
+	}
+	if _, err := tx.ExecContext(context.Background(), `CREATE INDEX IF NOT EXISTS idx_symbols_overload_key ON symbols(overload_key);`); err != nil {
+		return fmt.Errorf("create overload_key index: %w", err)
+	}
+	return nil
+}
+
+// indexMigrateV11 adds the low-confidence flag of symbols guessed by
+// heuristic parsers, such as the generic fallback, to symbols
+func indexMigrateV11(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE symbols ADD COLUMN low_confidence INTEGER DEFAULT 0;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return fmt.Errorf("add low_confidence column: %w", err)
+	}
+	return nil
+}
+
 func ensureSchema(db *sql.DB) error {
 	// Create schema version table first
 	if _, err := db.ExecContext(context.Background(), indexSchemaVersionTable); err != nil {
@@ -282,51 +451,153 @@ func GetIndexSchemaVersion(db *sql.DB) (int, error) {
 
 // BuildFileRecords scans the project and builds record summaries and analysis.
 func BuildFileRecords(root string, guardrails config.Guardrails) ([]FileRecord, error) {
-	files, err := fsutil.ListFiles(root, guardrails)
+	return BuildFileRecordsContext(context.Background(), root, guardrails)
+}
+
+// BuildFileRecordsContext is BuildFileRecords with cancellation. Files are
+// read and parsed by a pool of workers, as many as the limiter.Manager
+// carried by ctx allows CPU-bound jobs, each taking a slot for every file so
+// that parsing waits out memory pressure. Workers parse in parallel, each
+// parse drawing its own parser from the registry. ctx is checked before each
+// file is handed out. When ctx is cancelled the records finished so far are
+// returned, in path order, together with the context's error.
+func BuildFileRecordsContext(ctx context.Context, root string, guardrails config.Guardrails) ([]FileRecord, error) {
+	profile := ScanProfileFrom(ctx)
+	stopWalk := profile.Start(PhaseWalk)
+	files, err := listFiles(root, guardrails)
+	stopWalk()
 	if err != nil {
 		return nil, err
 	}
+	files = selectFiles(files)
 	sort.Strings(files)
-	records := make([]FileRecord, 0, len(files))
-	for _, rel := range files {
-		abs := filepath.Join(root, rel)
-		info, err := os.Stat(abs)
-		if err != nil {
-			return nil, fmt.Errorf("stat %s: %w", rel, err)
-		}
-		data, err := os.ReadFile(abs)
-		if err != nil {
-			return nil, fmt.Errorf("read %s: %w", rel, err)
-		}
-		h := sha256.Sum256(data)
-		chunks := fsutil.ChunkContent(string(data), 120, 8*1024)
-
-		// Perform language analysis
-		lang := analysis.DetectLanguage(rel)
-		var fileAnalysis *analysis.FileAnalysis
-		if lang != analysis.LangUnknown {
-			fa, err := analysis.Analyze(data, rel)
-			if err == nil {
-				fileAnalysis = fa
+	stopResolve := profile.Start(PhaseResolve)
+	imports := analysis.NewImportResolver(root)
+	stopResolve()
+	defer profile.Start(PhaseParse)()
+
+	limits := limiter.From(ctx)
+	results := make([]*FileRecord, len(files))
+	next := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	failed := func() bool {
+		errMu.Lock()
+		defer errMu.Unlock()
+		return firstErr != nil
+	}
+	for w := 0; w < min(limits.Limits().Jobs, len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				release, err := limits.AcquireCPU(ctx)
+				if err != nil {
+					continue // Cancelled: the file is left out like those never handed out
+				}
+				record, err := buildFileRecord(root, files[i], imports, profile)
+				release()
+				if err != nil {
+					errMu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					errMu.Unlock()
+					continue
+				}
+				results[i] = record
 			}
+		}()
+	}
+	for i := range files {
+		if ctx.Err() != nil || failed() {
+			break
 		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
 
-		records = append(records, FileRecord{
-			Path:     rel,
-			Hash:     fmt.Sprintf("%x", h[:]),
-			Size:     info.Size(),
-			ModTime:  fsutil.NormalizeModTime(info.ModTime()),
-			Chunks:   chunks,
-			Language: string(lang),
-			Analysis: fileAnalysis,
-		})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	records := make([]FileRecord, 0, len(files))
+	for _, r := range results {
+		if r != nil {
+			records = append(records, *r)
+		}
+	}
+	if len(records) < len(files) {
+		return records, ctx.Err()
 	}
 	return records, nil
 }
 
+// buildFileRecord reads, chunks, and parses one file.
+func buildFileRecord(root, rel string, imports analysis.ImportResolver, profile *ScanProfile) (*FileRecord, error) {
+	abs := filepath.Join(root, rel)
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", rel, err)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", rel, err)
+	}
+	h := sha256.Sum256(data)
+	chunks := fsutil.ChunkContent(string(data), 120, 8*1024)
+
+	// Perform language analysis
+	lang := analysis.DetectLanguageDetailed(rel, data).Language
+	fileAnalysis, _ := analyzeFile(data, rel, lang, imports, profile)
+
+	return &FileRecord{
+		Path:     rel,
+		Hash:     fmt.Sprintf("%x", h[:]),
+		Size:     info.Size(),
+		ModTime:  fsutil.NormalizeModTime(info.ModTime()),
+		Chunks:   chunks,
+		Language: fileLanguage(lang, fileAnalysis),
+		Analysis: fileAnalysis,
+	}, nil
+}
+
+// analyzeFile parses a file and classifies its imports, recording the parse
+// time in profile. Files in unknown languages are only parsed when the
+// generic fallback is enabled. It returns nil if the file was not parsed or
+// parsing fails, along with the time spent.
+func analyzeFile(data []byte, rel string, lang analysis.Language, imports analysis.ImportResolver, profile *ScanProfile) (*analysis.FileAnalysis, time.Duration) {
+	start := time.Now()
+	fa, err := analysis.Analyze(data, rel)
+	elapsed := time.Since(start)
+	if err != nil {
+		profile.AddParse(string(lang), elapsed)
+		return nil, elapsed
+	}
+	if fa.Language == string(analysis.LangUnknown) {
+		return nil, 0
+	}
+	profile.AddParse(fa.Language, elapsed)
+	imports.ClassifyFile(fa)
+	return fa, elapsed
+}
+
+// fileLanguage is the language recorded for a file: the detected language,
+// or "generic" for an unknown file the generic fallback parsed.
+func fileLanguage(lang analysis.Language, fa *analysis.FileAnalysis) string {
+	if lang == analysis.LangUnknown && fa != nil {
+		return fa.Language
+	}
+	return string(lang)
+}
+
 // WriteScanOptions provides options for WriteScan.
 type WriteScanOptions struct {
 	CommitHash string // Git commit hash (optional)
+	Partial    bool   // Mark the scan as cancelled before all files were indexed
 }
 
 // WriteScan writes a batch of file records to the index database.
@@ -345,6 +616,7 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 	clearStmts := []string{
 		"DELETE FROM relationships;",
 		"DELETE FROM symbols_fts;",
+		"DELETE FROM symbol_annotations;",
 		"DELETE FROM symbols;",
 		"DELETE FROM chunks;",
 		"DELETE FROM chunks_fts;",
@@ -379,7 +651,7 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 	}
 	defer ftsStmt.Close()
 
-	symbolStmt, err := tx.PrepareContext(context.Background(), `INSERT INTO symbols(file_path, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?);`)
+	symbolStmt, err := tx.PrepareContext(context.Background(), `INSERT INTO symbols(file_path, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported, deprecated, experimental, owner, last_commit, value, assertions, overload_key, low_confidence) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`)
 	if err != nil {
 		return ScanSummary{}, err
 	}
@@ -391,7 +663,13 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 	}
 	defer symbolFtsStmt.Close()
 
-	relStmt, err := tx.PrepareContext(context.Background(), `INSERT INTO relationships(source_file, source_symbol_id, target_file, target_symbol, kind, line, column) VALUES(?, ?, ?, ?, ?, ?, ?);`)
+	annotationStmt, err := tx.PrepareContext(context.Background(), `INSERT INTO symbol_annotations(symbol_id, annotation) VALUES(?, ?);`)
+	if err != nil {
+		return ScanSummary{}, err
+	}
+	defer annotationStmt.Close()
+
+	relStmt, err := tx.PrepareContext(context.Background(), `INSERT INTO relationships(source_file, source_symbol_id, target_file, target_symbol, kind, line, column, import_kind) VALUES(?, ?, ?, ?, ?, ?, ?, ?);`)
 	if err != nil {
 		return ScanSummary{}, err
 	}
@@ -414,7 +692,10 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 
 		// Insert symbols and relationships from analysis
 		if r.Analysis != nil {
-			symCount, err := insertSymbols(symbolStmt, symbolFtsStmt, r.Path, r.Analysis.Symbols, nil)
+			if err := attributeOwners(tx, root, r.Path, r.Hash, r.Analysis.Symbols); err != nil {
+				return ScanSummary{}, fmt.Errorf("blame %s: %w", r.Path, err)
+			}
+			symCount, err := insertSymbols(symbolStmt, symbolFtsStmt, annotationStmt, r.Path, r.Analysis.Symbols, nil)
 			if err != nil {
 				return ScanSummary{}, fmt.Errorf("insert symbols %s: %w", r.Path, err)
 			}
@@ -422,15 +703,24 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 
 			for _, rel := range r.Analysis.Relationships {
 				relationshipCount++
-				if _, err := relStmt.ExecContext(context.Background(), r.Path, nil, rel.TargetFile, rel.TargetSymbol, string(rel.Kind), rel.Line, rel.Column); err != nil {
+				if _, err := relStmt.ExecContext(context.Background(), r.Path, nil, rel.TargetFile, rel.TargetSymbol, string(rel.Kind), rel.Line, rel.Column, string(rel.ImportKind)); err != nil {
 					return ScanSummary{}, fmt.Errorf("insert relationship %s: %w", r.Path, err)
 				}
 			}
 		}
 	}
 
+	var warnings []string
+	for _, r := range records {
+		if r.Analysis != nil {
+			for _, w := range r.Analysis.Warnings {
+				warnings = append(warnings, r.Path+": "+w)
+			}
+		}
+	}
+
 	scanHash := computeScanHash(records)
-	res, err := tx.ExecContext(context.Background(), `INSERT INTO scans(root, scan_hash, started_at, completed_at, commit_hash) VALUES(?, ?, ?, ?, ?);`, root, scanHash, startedAt.UTC().Format(time.RFC3339), now.Format(time.RFC3339), opts.CommitHash)
+	res, err := tx.ExecContext(context.Background(), `INSERT INTO scans(root, scan_hash, started_at, completed_at, commit_hash, partial) VALUES(?, ?, ?, ?, ?, ?);`, root, scanHash, startedAt.UTC().Format(time.RFC3339), now.Format(time.RFC3339), opts.CommitHash, opts.Partial)
 	if err != nil {
 		return ScanSummary{}, fmt.Errorf("insert scan: %w", err)
 	}
@@ -451,10 +741,12 @@ func WriteScanWithOptions(db *sql.DB, root string, records []FileRecord, started
 		RelationshipCount: relationshipCount,
 		StartedAt:         startedAt.UTC(),
 		CompletedAt:       now,
+		Warnings:          warnings,
+		Partial:           opts.Partial,
 	}, nil
 }
 
-func insertSymbols(symbolStmt, symbolFtsStmt *sql.Stmt, filePath string, symbols []analysis.Symbol, parentID *int64) (int, error) {
+func insertSymbols(symbolStmt, symbolFtsStmt, annotationStmt *sql.Stmt, filePath string, symbols []analysis.Symbol, parentID *int64) (int, error) {
 	count := 0
 	for _, sym := range symbols {
 		exported := 0
@@ -462,7 +754,7 @@ func insertSymbols(symbolStmt, symbolFtsStmt *sql.Stmt, filePath string, symbols
 			exported = 1
 		}
 
-		res, err := symbolStmt.ExecContext(context.Background(), filePath, sym.Name, string(sym.Kind), sym.LineStart, sym.LineEnd, sym.Signature, sym.DocComment, parentID, exported)
+		res, err := symbolStmt.ExecContext(context.Background(), filePath, sym.Name, string(sym.Kind), sym.LineStart, sym.LineEnd, sym.Signature, sym.DocComment, parentID, exported, sym.Deprecated, sym.Experimental, sym.Owner, sym.LastCommit, sym.Value, assertions(sym), analysis.SymbolKey(sym), sym.LowConfidence)
 		if err != nil {
 			return count, err
 		}
@@ -472,10 +764,16 @@ func insertSymbols(symbolStmt, symbolFtsStmt *sql.Stmt, filePath string, symbols
 			return count, err
 		}
 
+		symID, _ := res.LastInsertId()
+		for _, ann := range sym.Annotations {
+			if _, err := annotationStmt.ExecContext(context.Background(), symID, ann); err != nil {
+				return count, err
+			}
+		}
+
 		// Insert children recursively
 		if len(sym.Children) > 0 {
-			symID, _ := res.LastInsertId()
-			childCount, err := insertSymbols(symbolStmt, symbolFtsStmt, filePath, sym.Children, &symID)
+			childCount, err := insertSymbols(symbolStmt, symbolFtsStmt, annotationStmt, filePath, sym.Children, &symID)
 			if err != nil {
 				return count, err
 			}
@@ -509,7 +807,10 @@ func LatestScan(db *sql.DB) (ScanSummary, error) {
 	if err != nil {
 		return ScanSummary{}, fmt.Errorf("parse completed_at: %w", err)
 	}
-	return ScanSummary{ID: id, Root: root, ScanHash: hash, CommitHash: commitHash, CompletedAt: t}, nil
+	// Databases predating the partial column read as complete
+	var partial bool
+	_ = db.QueryRowContext(context.Background(), `SELECT COALESCE(partial, 0) FROM scans WHERE id = ?;`, id).Scan(&partial)
+	return ScanSummary{ID: id, Root: root, ScanHash: hash, CommitHash: commitHash, CompletedAt: t, Partial: partial}, nil
 }
 
 // FileMetadata contains basic information about an indexed file.
@@ -951,9 +951,58 @@ func TestGetIndexSchemaVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetIndexSchemaVersion() error = %v", err)
 	}
-	// Version 0: Initial schema, Version 1: Added commit_hash column
-	if version != 1 {
-		t.Fatalf("schema version = %d, want 1", version)
+	// Version 0: Initial schema, Version 1: Added commit_hash column,
+	// Version 2: Added symbol_annotations table, Version 3: Added import_kind column,
+	// Version 4: Added scans.partial, Version 5: Added symbols.deprecated/experimental,
+	// Version 6: Added symbols.owner/last_commit and blame_cache,
+	// Version 7: Added symbols.value, Version 8: Added index_stats,
+	// Version 9: Added symbols.assertions, Version 10: Added symbols.overload_key,
+	// Version 11: Added symbols.low_confidence
+	if version != 11 {
+		t.Fatalf("schema version = %d, want 11", version)
+	}
+}
+
+func TestGetSymbolByOverloadKey(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	records := []FileRecord{{
+		Path:     "Calc.java",
+		Hash:     "h1",
+		ModTime:  time.Now().UTC(),
+		Language: "java",
+		Analysis: &analysis.FileAnalysis{Symbols: []analysis.Symbol{{
+			Name: "Calc", Kind: analysis.KindClass, LineStart: 1, LineEnd: 9,
+			Children: []analysis.Symbol{
+				{Name: "add", Kind: analysis.KindMethod, LineStart: 2, LineEnd: 4, Signature: "int add(int a, int b)"},
+				{Name: "add", Kind: analysis.KindMethod, LineStart: 5, LineEnd: 7, Signature: "int add(int a, int b, int c)"},
+			},
+		}}},
+	}}
+	if _, err := WriteScan(db, "/repo", records, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+
+	var keys int
+	if err := db.QueryRow(`SELECT COUNT(DISTINCT overload_key) FROM symbols WHERE name = 'add'`).Scan(&keys); err != nil {
+		t.Fatal(err)
+	}
+	if keys != 2 {
+		t.Errorf("two add overloads stored under %d distinct keys, want 2", keys)
+	}
+
+	for key, line := range map[string]int{"add(int,int)": 2, "add(int, int, int)": 5} {
+		sym, err := GetSymbol(db, key, "")
+		if err != nil {
+			t.Fatalf("GetSymbol(%q) error = %v", key, err)
+		}
+		if sym.LineStart != line {
+			t.Errorf("GetSymbol(%q) found line %d, want %d", key, sym.LineStart, line)
+		}
 	}
 }
 
@@ -1012,3 +1061,55 @@ func TestWriteScanWithSymbols(t *testing.T) {
 		t.Fatalf("expected symbols and relationships, got %+v", summary)
 	}
 }
+
+func TestFindAnnotatedSymbols(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(filepath.Join(dir, "palace.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	records := []FileRecord{
+		{
+			Path:    "UserController.java",
+			Hash:    "h1",
+			ModTime: time.Now(),
+			Analysis: &analysis.FileAnalysis{
+				Path: "UserController.java",
+				Symbols: []analysis.Symbol{
+					{
+						Name: "UserController", Kind: analysis.KindClass, LineStart: 1, LineEnd: 10,
+						Annotations: []string{"RestController"},
+						Children: []analysis.Symbol{
+							{Name: "legacy", Kind: analysis.KindMethod, LineStart: 2, LineEnd: 3, Annotations: []string{"Deprecated"}},
+							{Name: "plain", Kind: analysis.KindMethod, LineStart: 4, LineEnd: 5},
+						},
+					},
+				},
+			},
+		},
+	}
+	if _, err := WriteScan(db, dir, records, time.Now()); err != nil {
+		t.Fatalf("write scan: %v", err)
+	}
+
+	results, err := FindAnnotatedSymbols(db, "@Deprecated", 0)
+	if err != nil {
+		t.Fatalf("FindAnnotatedSymbols: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "legacy" {
+		t.Fatalf("expected [legacy], got %+v", results)
+	}
+	if len(results[0].Annotations) != 1 || results[0].Annotations[0] != "Deprecated" {
+		t.Errorf("unexpected annotations %v", results[0].Annotations)
+	}
+
+	results, err = FindAnnotatedSymbols(db, "Missing", 0)
+	if err != nil {
+		t.Fatalf("FindAnnotatedSymbols: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %+v", results)
+	}
+}
@@ -0,0 +1,45 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// FlaggedSymbol is a symbol marked deprecated or experimental in its source.
+type FlaggedSymbol struct {
+	File         string `json:"file"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Line         int    `json:"line"`
+	Deprecated   bool   `json:"deprecated"`
+	Experimental bool   `json:"experimental"`
+}
+
+// GetFlaggedSymbols returns the deprecated symbols, or the experimental ones
+// when experimental is set, ordered by file and line.
+func GetFlaggedSymbols(db *sql.DB, experimental bool) ([]FlaggedSymbol, error) {
+	column := "deprecated"
+	if experimental {
+		column = "experimental"
+	}
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, name, kind, line_start, COALESCE(deprecated, 0), COALESCE(experimental, 0)
+		FROM symbols WHERE `+column+` = 1
+		ORDER BY file_path, line_start, id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query %s symbols: %w", column, err)
+	}
+	defer rows.Close()
+
+	var result []FlaggedSymbol
+	for rows.Next() {
+		var s FlaggedSymbol
+		if err := rows.Scan(&s.File, &s.Name, &s.Kind, &s.Line, &s.Deprecated, &s.Experimental); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
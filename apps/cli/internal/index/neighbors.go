@@ -0,0 +1,302 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// FileNeighbors is the file-level dependency context of one file: the files
+// it depends on (upstream) and the files depending on it (downstream),
+// within a depth.
+type FileNeighbors struct {
+	File       string         `json:"file"`
+	Depth      int            `json:"depth"`
+	Upstream   []FileNeighbor `json:"upstream"`
+	Downstream []FileNeighbor `json:"downstream"`
+}
+
+// FileNeighbor is a file in a FileNeighbors, with the edges linking it to
+// the file one level nearer: the queried file at depth 1, Via beyond it.
+type FileNeighbor struct {
+	File       string `json:"file"`
+	Depth      int    `json:"depth"`
+	Via        string `json:"via,omitempty"`
+	Edges      int    `json:"edges"`
+	Imports    int    `json:"imports,omitempty"`
+	Calls      int    `json:"calls,omitempty"`
+	References int    `json:"references,omitempty"`
+}
+
+// fileEdges counts the edges from one file to another, by kind.
+type fileEdges struct {
+	imports, calls, references int
+}
+
+func (e *fileEdges) total() int {
+	return e.imports + e.calls + e.references
+}
+
+// GetFileNeighbors walks the file graph breadth-first from file up to depth
+// levels in both directions. Edges are the file's imports, calls, and
+// references resolved to indexed files: calls and references the way
+// GetCallSubgraph resolves callees, local imports by matching the module
+// path against the indexed file paths. Edges that resolve to no single
+// file, such as library calls, are left out.
+func GetFileNeighbors(db *sql.DB, file string, depth int) (*FileNeighbors, error) {
+	if depth <= 0 {
+		depth = 1
+	}
+	if depth > 10 {
+		depth = 10 // Same cap as call graphs
+	}
+	file = strings.TrimPrefix(path.Clean(strings.ReplaceAll(file, "\\", "/")), "./")
+
+	graph, err := buildFileGraph(db)
+	if err != nil {
+		return nil, err
+	}
+	if !graph.files[file] {
+		return nil, fmt.Errorf("file not indexed: %s", file)
+	}
+
+	reverse := make(map[string]map[string]*fileEdges)
+	for src, targets := range graph.edges {
+		for dst, e := range targets {
+			if reverse[dst] == nil {
+				reverse[dst] = make(map[string]*fileEdges)
+			}
+			reverse[dst][src] = e
+		}
+	}
+	return &FileNeighbors{
+		File:       file,
+		Depth:      depth,
+		Upstream:   walkFileGraph(graph.edges, file, depth),
+		Downstream: walkFileGraph(reverse, file, depth),
+	}, nil
+}
+
+// walkFileGraph returns the files reached from start within depth levels
+// of edges, nearest first, then by edge count.
+func walkFileGraph(edges map[string]map[string]*fileEdges, start string, depth int) []FileNeighbor {
+	seen := map[string]bool{start: true}
+	result := []FileNeighbor{}
+	level := []string{start}
+	for d := 1; d <= depth && len(level) > 0; d++ {
+		found := make(map[string]*FileNeighbor)
+		for _, from := range level {
+			for to, e := range edges[from] {
+				if seen[to] {
+					continue
+				}
+				n := found[to]
+				if n == nil {
+					n = &FileNeighbor{File: to, Depth: d}
+					if d > 1 {
+						n.Via = from
+					}
+					found[to] = n
+				}
+				n.Imports += e.imports
+				n.Calls += e.calls
+				n.References += e.references
+				n.Edges += e.total()
+			}
+		}
+		var next []FileNeighbor
+		for _, n := range found {
+			next = append(next, *n)
+		}
+		sort.Slice(next, func(i, j int) bool {
+			if next[i].Edges != next[j].Edges {
+				return next[i].Edges > next[j].Edges
+			}
+			return next[i].File < next[j].File
+		})
+		level = level[:0]
+		for _, n := range next {
+			seen[n.File] = true
+			level = append(level, n.File)
+		}
+		result = append(result, next...)
+	}
+	return result
+}
+
+// fileGraph is the indexed code aggregated to files.
+type fileGraph struct {
+	files map[string]bool
+	edges map[string]map[string]*fileEdges // Source file -> target file
+}
+
+func buildFileGraph(db *sql.DB) (*fileGraph, error) {
+	rows, err := db.QueryContext(context.Background(), `SELECT path FROM files;`)
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+	g := &fileGraph{files: make(map[string]bool), edges: make(map[string]map[string]*fileEdges)}
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		g.files[p] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	definedIn, err := symbolFilesByName(db)
+	if err != nil {
+		return nil, err
+	}
+	modules := newModuleFiles(g.files)
+
+	rows, err = db.QueryContext(context.Background(), `
+		SELECT source_file, COALESCE(target_file, ''), COALESCE(target_symbol, ''), kind, COALESCE(import_kind, '')
+		FROM relationships
+		WHERE kind IN ('import', 'call', 'reference');`)
+	if err != nil {
+		return nil, fmt.Errorf("query relationships: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var src, targetFile, targetSymbol, kind, importKind string
+		if err := rows.Scan(&src, &targetFile, &targetSymbol, &kind, &importKind); err != nil {
+			return nil, err
+		}
+		switch kind {
+		case "import":
+			if importKind != "local" {
+				continue
+			}
+			for _, dst := range modules.resolve(targetFile, src) {
+				g.edge(src, dst).imports++
+			}
+		case "call":
+			if dst := resolveCallee(definedIn, targetSymbol, src); dst != "" {
+				g.edge(src, dst).calls++
+			}
+		case "reference":
+			if dst := resolveCallee(definedIn, targetSymbol, src); dst != "" {
+				g.edge(src, dst).references++
+			}
+		}
+	}
+	for src, targets := range g.edges {
+		delete(targets, src)
+	}
+	return g, rows.Err()
+}
+
+func (g *fileGraph) edge(src, dst string) *fileEdges {
+	if g.edges[src] == nil {
+		g.edges[src] = make(map[string]*fileEdges)
+	}
+	e := g.edges[src][dst]
+	if e == nil {
+		e = &fileEdges{}
+		g.edges[src][dst] = e
+	}
+	return e
+}
+
+// moduleFiles resolves local import paths to indexed files. Files are keyed
+// by every trailing part of their path without extension, so the import
+// "myapp/internal/auth" finds internal/auth/*.go and "pkg.util" finds
+// src/pkg/util.py; a package's index, __init__, or mod file also stands for
+// its directory.
+type moduleFiles struct {
+	stems map[string]map[string][]string // Path suffix -> stem -> files
+	dirs  map[string]map[string][]string // Path suffix -> directory -> files
+}
+
+func newModuleFiles(files map[string]bool) *moduleFiles {
+	m := &moduleFiles{stems: make(map[string]map[string][]string), dirs: make(map[string]map[string][]string)}
+	add := func(index map[string]map[string][]string, key, file string) {
+		parts := strings.Split(key, "/")
+		for i := range parts {
+			suffix := strings.Join(parts[i:], "/")
+			if index[suffix] == nil {
+				index[suffix] = make(map[string][]string)
+			}
+			index[suffix][key] = append(index[suffix][key], file)
+		}
+	}
+	for file := range files {
+		stem := strings.TrimSuffix(file, path.Ext(file))
+		add(m.stems, stem, file)
+		if dir := path.Dir(file); dir != "." {
+			add(m.dirs, dir, file)
+			switch path.Base(stem) {
+			case "index", "__init__", "mod":
+				add(m.stems, dir, file)
+			}
+		}
+	}
+	return m
+}
+
+// resolve returns the files the module imported by source refers to: the
+// file or package a relative import points at, or the one file or package
+// whose path ends with the longest trailing part of the module path.
+func (m *moduleFiles) resolve(module, source string) []string {
+	if strings.HasPrefix(module, "./") || strings.HasPrefix(module, "../") {
+		p := path.Join(path.Dir(source), module)
+		if files := m.exact(p); files != nil {
+			return files
+		}
+		return m.exact(strings.TrimSuffix(p, path.Ext(p)))
+	}
+	if rest := strings.TrimLeft(module, "."); rest != module {
+		// A Python relative import: "." is the source's package, ".." its parent
+		dir := path.Dir(source)
+		for range len(module) - len(rest) - 1 {
+			dir = path.Dir(dir)
+		}
+		return m.exact(path.Join(dir, strings.ReplaceAll(rest, ".", "/")))
+	}
+
+	p := strings.ReplaceAll(module, "::", "/")
+	if !strings.Contains(p, "/") {
+		p = strings.ReplaceAll(p, ".", "/")
+	}
+	p = strings.TrimPrefix(p, "crate/")
+	parts := strings.Split(p, "/")
+	for i := range parts {
+		suffix := strings.Join(parts[i:], "/")
+		if files := single(m.stems[suffix]); files != nil {
+			return files
+		}
+		if files := single(m.dirs[suffix]); files != nil {
+			return files
+		}
+	}
+	return nil
+}
+
+// exact returns the files of the stem or directory p.
+func (m *moduleFiles) exact(p string) []string {
+	if files := m.stems[p][p]; files != nil {
+		return files
+	}
+	return m.dirs[p][p]
+}
+
+// single returns the files of the only key in matches, or nil if there are
+// none or several.
+func single(matches map[string][]string) []string {
+	if len(matches) != 1 {
+		return nil
+	}
+	for _, files := range matches {
+		return files
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
 )
 
 // ContextResult represents the complete context for a task
@@ -43,15 +45,17 @@ type FileContext struct {
 
 // SymbolInfo represents a symbol with its metadata
 type SymbolInfo struct {
-	Name       string       `json:"name"`
-	Kind       string       `json:"kind"`
-	FilePath   string       `json:"filePath"`
-	LineStart  int          `json:"lineStart"`
-	LineEnd    int          `json:"lineEnd"`
-	Signature  string       `json:"signature,omitempty"`
-	DocComment string       `json:"docComment,omitempty"`
-	Exported   bool         `json:"exported"`
-	Children   []SymbolInfo `json:"children,omitempty"`
+	Name          string       `json:"name"`
+	Kind          string       `json:"kind"`
+	FilePath      string       `json:"filePath"`
+	LineStart     int          `json:"lineStart"`
+	LineEnd       int          `json:"lineEnd"`
+	Signature     string       `json:"signature,omitempty"`
+	DocComment    string       `json:"docComment,omitempty"`
+	Exported      bool         `json:"exported"`
+	LowConfidence bool         `json:"lowConfidence,omitempty"` // Guessed by a heuristic parser
+	Annotations   []string     `json:"annotations,omitempty"`
+	Children      []SymbolInfo `json:"children,omitempty"`
 }
 
 // ImportInfo represents an import relationship
@@ -443,7 +447,7 @@ func truncateImports(imports []ImportInfo, budget int) []ImportInfo {
 func searchSymbols(db *sql.DB, query string, limit int) ([]SymbolInfo, error) {
 	escaped := sanitizeFTSQuery(query)
 	rows, err := db.QueryContext(context.Background(), `
-		SELECT s.name, s.kind, s.file_path, s.line_start, s.line_end, s.signature, s.doc_comment, s.exported
+		SELECT s.name, s.kind, s.file_path, s.line_start, s.line_end, s.signature, s.doc_comment, s.exported, s.low_confidence
 		FROM symbols_fts
 		JOIN symbols s ON s.name = symbols_fts.name AND s.file_path = symbols_fts.file_path
 		WHERE symbols_fts MATCH ?
@@ -458,11 +462,12 @@ func searchSymbols(db *sql.DB, query string, limit int) ([]SymbolInfo, error) {
 	var symbols []SymbolInfo
 	for rows.Next() {
 		var sym SymbolInfo
-		var exported int
-		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported); err != nil {
+		var exported, lowConfidence int
+		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported, &lowConfidence); err != nil {
 			return nil, err
 		}
 		sym.Exported = exported == 1
+		sym.LowConfidence = lowConfidence == 1
 		symbols = append(symbols, sym)
 	}
 	return symbols, rows.Err()
@@ -471,7 +476,7 @@ func searchSymbols(db *sql.DB, query string, limit int) ([]SymbolInfo, error) {
 // getSymbolsForFile returns all symbols in a file
 func getSymbolsForFile(db *sql.DB, path string) ([]SymbolInfo, error) {
 	rows, err := db.QueryContext(context.Background(), `
-		SELECT id, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported
+		SELECT id, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported, low_confidence
 		FROM symbols
 		WHERE file_path = ?
 		ORDER BY line_start;
@@ -490,12 +495,13 @@ func getSymbolsForFile(db *sql.DB, path string) ([]SymbolInfo, error) {
 	var rawSymbols []rawSymbol
 	for rows.Next() {
 		var rs rawSymbol
-		var exported int
-		if err := rows.Scan(&rs.ID, &rs.SymbolInfo.Name, &rs.SymbolInfo.Kind, &rs.SymbolInfo.LineStart, &rs.SymbolInfo.LineEnd, &rs.SymbolInfo.Signature, &rs.SymbolInfo.DocComment, &rs.ParentID, &exported); err != nil {
+		var exported, lowConfidence int
+		if err := rows.Scan(&rs.ID, &rs.SymbolInfo.Name, &rs.SymbolInfo.Kind, &rs.SymbolInfo.LineStart, &rs.SymbolInfo.LineEnd, &rs.SymbolInfo.Signature, &rs.SymbolInfo.DocComment, &rs.ParentID, &exported, &lowConfidence); err != nil {
 			return nil, err
 		}
 		rs.SymbolInfo.FilePath = path
 		rs.SymbolInfo.Exported = exported == 1
+		rs.SymbolInfo.LowConfidence = lowConfidence == 1
 		rawSymbols = append(rawSymbols, rs)
 	}
 
@@ -666,7 +672,7 @@ func SearchSymbolsByKind(db *sql.DB, kind string, limit int) ([]SymbolInfo, erro
 	}
 
 	rows, err := db.QueryContext(context.Background(), `
-		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported
+		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported, low_confidence
 		FROM symbols
 		WHERE kind = ?
 		ORDER BY file_path, line_start
@@ -680,25 +686,31 @@ func SearchSymbolsByKind(db *sql.DB, kind string, limit int) ([]SymbolInfo, erro
 	var symbols []SymbolInfo
 	for rows.Next() {
 		var sym SymbolInfo
-		var exported int
-		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported); err != nil {
+		var exported, lowConfidence int
+		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported, &lowConfidence); err != nil {
 			return nil, err
 		}
 		sym.Exported = exported == 1
+		sym.LowConfidence = lowConfidence == 1
 		symbols = append(symbols, sym)
 	}
 	return symbols, rows.Err()
 }
 
-// GetSymbol returns a specific symbol by name and file
+// GetSymbol returns a specific symbol by name and file. A name written with
+// parameter types, such as "add(int, int)", picks that overload.
 func GetSymbol(db *sql.DB, name, filePath string) (*SymbolInfo, error) {
 	var sym SymbolInfo
-	var exported int
+	var exported, lowConfidence int
 
+	column := "name"
+	if analysis.IsOverloadKey(name) {
+		column, name = "overload_key", analysis.NormalizeOverloadKey(name)
+	}
 	query := `
-		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported
+		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported, low_confidence
 		FROM symbols
-		WHERE name = ?`
+		WHERE ` + column + ` = ?`
 	args := []any{name}
 
 	if filePath != "" {
@@ -707,18 +719,59 @@ func GetSymbol(db *sql.DB, name, filePath string) (*SymbolInfo, error) {
 	}
 	query += ` LIMIT 1;`
 
-	err := db.QueryRowContext(context.Background(), query, args...).Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported)
+	err := db.QueryRowContext(context.Background(), query, args...).Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported, &lowConfidence)
 	if err != nil {
 		return nil, err
 	}
 	sym.Exported = exported == 1
+	sym.LowConfidence = lowConfidence == 1
 	return &sym, nil
 }
 
+// GetSymbolsInRange returns the symbols of a file whose lines overlap start
+// to end, ordered by line. With start <= 0 it returns the file's top-level
+// symbols instead. Findings, subtests, and config keys are left out.
+func GetSymbolsInRange(db *sql.DB, filePath string, start, end int) ([]SymbolInfo, error) {
+	query := `
+		SELECT name, kind, file_path, line_start, line_end, COALESCE(signature, ''), COALESCE(doc_comment, ''), exported, low_confidence
+		FROM symbols
+		WHERE file_path = ? AND kind NOT IN (?, ?, ?)`
+	args := []any{filePath}
+	for _, kind := range analysis.SyntheticKinds {
+		args = append(args, kind)
+	}
+	if start > 0 {
+		query += ` AND line_start <= ? AND line_end >= ?`
+		args = append(args, end, start)
+	} else {
+		query += ` AND parent_id IS NULL`
+	}
+	query += ` ORDER BY line_start, id;`
+
+	rows, err := db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []SymbolInfo
+	for rows.Next() {
+		var sym SymbolInfo
+		var exported, lowConfidence int
+		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported, &lowConfidence); err != nil {
+			return nil, err
+		}
+		sym.Exported = exported == 1
+		sym.LowConfidence = lowConfidence == 1
+		symbols = append(symbols, sym)
+	}
+	return symbols, rows.Err()
+}
+
 // ListExportedSymbols returns all exported symbols for a file
 func ListExportedSymbols(db *sql.DB, filePath string) ([]SymbolInfo, error) {
 	rows, err := db.QueryContext(context.Background(), `
-		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported
+		SELECT name, kind, file_path, line_start, line_end, signature, doc_comment, exported, low_confidence
 		FROM symbols
 		WHERE file_path = ? AND exported = 1
 		ORDER BY line_start;
@@ -731,11 +784,12 @@ func ListExportedSymbols(db *sql.DB, filePath string) ([]SymbolInfo, error) {
 	var symbols []SymbolInfo
 	for rows.Next() {
 		var sym SymbolInfo
-		var exported int
-		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported); err != nil {
+		var exported, lowConfidence int
+		if err := rows.Scan(&sym.Name, &sym.Kind, &sym.FilePath, &sym.LineStart, &sym.LineEnd, &sym.Signature, &sym.DocComment, &exported, &lowConfidence); err != nil {
 			return nil, err
 		}
 		sym.Exported = exported == 1
+		sym.LowConfidence = lowConfidence == 1
 		symbols = append(symbols, sym)
 	}
 	return symbols, rows.Err()
@@ -44,7 +44,7 @@ func TestOracleIntegrated(t *testing.T) {
 		`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT, size INTEGER, mod_time TEXT, indexed_at TEXT, language TEXT);`,
 		`CREATE TABLE chunks (id INTEGER PRIMARY KEY, path TEXT, chunk_index INTEGER, start_line INTEGER, end_line INTEGER, content TEXT);`,
 		`CREATE VIRTUAL TABLE chunks_fts USING fts5(path, content, chunk_index);`,
-		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER);`,
+		`CREATE TABLE symbols (id INTEGER PRIMARY KEY, file_path TEXT, name TEXT, kind TEXT, line_start INTEGER, line_end INTEGER, signature TEXT, doc_comment TEXT, parent_id INTEGER, exported INTEGER, low_confidence INTEGER DEFAULT 0);`,
 		`CREATE VIRTUAL TABLE symbols_fts USING fts5(name, file_path, kind, doc_comment);`,
 		`CREATE TABLE relationships (id INTEGER PRIMARY KEY, source_file TEXT, source_symbol_id INTEGER, target_file TEXT, target_symbol TEXT, kind TEXT, line INTEGER, column INTEGER);`,
 		`CREATE TABLE decisions (id TEXT PRIMARY KEY, room TEXT, title TEXT, summary TEXT, rationale TEXT, affected_files TEXT, created_at TEXT, created_by TEXT);`,
@@ -55,9 +55,9 @@ func TestOracleIntegrated(t *testing.T) {
 
 	// Insert test data
 	db.ExecContext(context.Background(), `INSERT INTO files VALUES (?, ?, ?, ?, ?, ?);`, "auth.go", "h1", 100, "now", "now", "go")
-	db.ExecContext(context.Background(), `INSERT INTO symbols VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, 1, "auth.go", "Login", "function", 1, 10, "()", "Login function", nil, 1)
+	db.ExecContext(context.Background(), `INSERT INTO symbols(id, file_path, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, 1, "auth.go", "Login", "function", 1, 10, "()", "Login function", nil, 1)
 	db.ExecContext(context.Background(), `INSERT INTO symbols_fts VALUES (?, ?, ?, ?);`, "Login", "auth.go", "function", "Login function")
-	db.ExecContext(context.Background(), `INSERT INTO relationships VALUES (?, ?, ?, ?, ?, ?, ?, ?);`, 1, "main.go", nil, "auth.go", nil, "import", 5, 1)
+	db.ExecContext(context.Background(), `INSERT INTO relationships(id, source_file, source_symbol_id, target_file, target_symbol, kind, line, column) VALUES (?, ?, ?, ?, ?, ?, ?, ?);`, 1, "main.go", nil, "auth.go", nil, "import", 5, 1)
 
 	t.Run("GetSymbol", func(t *testing.T) {
 		sym, err := GetSymbol(db, "Login", "auth.go")
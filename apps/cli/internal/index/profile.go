@@ -0,0 +1,149 @@
+package index
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScanProfile records where a scan spends its time: wall time per phase, and
+// parse time per language. A nil *ScanProfile is valid and records nothing,
+// so the pipeline can be instrumented unconditionally.
+//
+// Phases are wall-clock. Per-language parse times are summed across the
+// workers that parse files in parallel, so together they may exceed the
+// parse phase itself.
+type ScanProfile struct {
+	mu        sync.Mutex
+	started   time.Time
+	order     []string
+	phases    map[string]time.Duration
+	languages map[string]*LanguageTiming
+}
+
+// Scan phases recorded by the pipeline.
+const (
+	PhaseWalk         = "walk"          // Listing files or detecting changes
+	PhaseResolve      = "resolve"       // Preparing import resolution
+	PhaseParse        = "parse"         // Reading, chunking, and parsing files
+	PhasePersist      = "persist"       // Writing the index
+	PhaseProcessors   = "processors"    // Post-scan processors
+	PhaseDeepAnalysis = "deep-analysis" // LSP-based call extraction
+)
+
+// PhaseTiming is the time spent in one phase.
+type PhaseTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"-"`
+	Millis   float64       `json:"ms"`
+}
+
+// LanguageTiming is the parse time spent on one language.
+type LanguageTiming struct {
+	Language string        `json:"language"`
+	Files    int           `json:"files"`
+	Duration time.Duration `json:"-"`
+	Millis   float64       `json:"ms"`
+}
+
+// ScanProfileReport is a snapshot of a ScanProfile.
+type ScanProfileReport struct {
+	Total     time.Duration    `json:"-"`
+	TotalMs   float64          `json:"totalMs"`
+	Phases    []PhaseTiming    `json:"phases"`
+	Languages []LanguageTiming `json:"languages"`
+}
+
+// NewScanProfile starts a profile; its total runs from now until Report.
+func NewScanProfile() *ScanProfile {
+	return &ScanProfile{
+		started:   time.Now(),
+		phases:    make(map[string]time.Duration),
+		languages: make(map[string]*LanguageTiming),
+	}
+}
+
+// Start begins timing a phase and returns the function that ends it.
+// Timing the same phase more than once accumulates.
+func (p *ScanProfile) Start(phase string) (stop func()) {
+	if p == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { p.Add(phase, time.Since(start)) }
+}
+
+// Add adds d to a phase.
+func (p *ScanProfile) Add(phase string, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.phases[phase]; !ok {
+		p.order = append(p.order, phase)
+	}
+	p.phases[phase] += d
+}
+
+// AddParse records the time taken to parse one file of a language.
+func (p *ScanProfile) AddParse(language string, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lt, ok := p.languages[language]
+	if !ok {
+		lt = &LanguageTiming{Language: language}
+		p.languages[language] = lt
+	}
+	lt.Files++
+	lt.Duration += d
+}
+
+// Report returns the phases in the order they first ran and the languages
+// from slowest to fastest.
+func (p *ScanProfile) Report() ScanProfileReport {
+	if p == nil {
+		return ScanProfileReport{}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	report := ScanProfileReport{Total: time.Since(p.started)}
+	report.TotalMs = millis(report.Total)
+	for _, name := range p.order {
+		d := p.phases[name]
+		report.Phases = append(report.Phases, PhaseTiming{Name: name, Duration: d, Millis: millis(d)})
+	}
+	for _, lt := range p.languages {
+		timing := *lt
+		timing.Millis = millis(timing.Duration)
+		report.Languages = append(report.Languages, timing)
+	}
+	sort.Slice(report.Languages, func(i, j int) bool {
+		if report.Languages[i].Duration != report.Languages[j].Duration {
+			return report.Languages[i].Duration > report.Languages[j].Duration
+		}
+		return report.Languages[i].Language < report.Languages[j].Language
+	})
+	return report
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+type scanProfileKey struct{}
+
+// WithScanProfile returns a context that carries p to the scan pipeline.
+func WithScanProfile(ctx context.Context, p *ScanProfile) context.Context {
+	return context.WithValue(ctx, scanProfileKey{}, p)
+}
+
+// ScanProfileFrom returns the profile carried by ctx, or nil.
+func ScanProfileFrom(ctx context.Context) *ScanProfile {
+	p, _ := ctx.Value(scanProfileKey{}).(*ScanProfile)
+	return p
+}
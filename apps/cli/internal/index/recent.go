@@ -0,0 +1,108 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+)
+
+// RecentFile is an indexed file whose on-disk modification time falls inside
+// a recency window, with the symbols the index holds for it.
+type RecentFile struct {
+	Path     string       `json:"path"`
+	Language string       `json:"language"`
+	ModTime  time.Time    `json:"modTime"`
+	Stale    bool         `json:"stale"` // Modified since it was indexed; symbols may be out of date
+	Symbols  []SymbolInfo `json:"symbols"`
+}
+
+// RecentChangesOptions filters GetRecentChanges.
+type RecentChangesOptions struct {
+	Since    time.Time
+	Language string // Only files in this language (empty = all)
+	Kind     string // Only symbols of this kind (empty = all); files without a match are dropped
+}
+
+// GetRecentChanges stats every indexed file under root and returns those
+// modified at or after opts.Since, most recent first. It needs no git
+// history: the window is judged from filesystem mtimes alone, while symbols
+// come from the persisted index. Files deleted since the scan are skipped.
+func GetRecentChanges(db *sql.DB, root string, opts RecentChangesOptions) ([]RecentFile, error) {
+	query := `SELECT path, language, mod_time FROM files`
+	var args []any
+	if opts.Language != "" {
+		query += ` WHERE language = ?`
+		args = append(args, opts.Language)
+	}
+	rows, err := db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+
+	var recent []RecentFile
+	for rows.Next() {
+		var path, language, indexedMod string
+		if err := rows.Scan(&path, &language, &indexedMod); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		info, err := os.Stat(filepath.Join(root, path))
+		if err != nil || info.ModTime().Before(opts.Since) {
+			continue
+		}
+		recent = append(recent, RecentFile{
+			Path:     path,
+			Language: language,
+			ModTime:  info.ModTime(),
+			Stale:    fsutil.NormalizeModTime(info.ModTime()).Format(time.RFC3339) != indexedMod,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := recent[:0]
+	for _, f := range recent {
+		symbols, err := getSymbolsForFile(db, f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("symbols for %s: %w", f.Path, err)
+		}
+		if opts.Kind != "" {
+			symbols = filterSymbolsByKind(symbols, opts.Kind)
+			if len(symbols) == 0 {
+				continue
+			}
+		}
+		f.Symbols = symbols
+		result = append(result, f)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].ModTime.Equal(result[j].ModTime) {
+			return result[i].ModTime.After(result[j].ModTime)
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+// filterSymbolsByKind flattens a symbol tree to the symbols of the given kind.
+func filterSymbolsByKind(symbols []SymbolInfo, kind string) []SymbolInfo {
+	var result []SymbolInfo
+	for _, sym := range symbols {
+		children := sym.Children
+		if sym.Kind == kind {
+			sym.Children = nil
+			result = append(result, sym)
+		}
+		result = append(result, filterSymbolsByKind(children, kind)...)
+	}
+	return result
+}
@@ -30,6 +30,7 @@ type IncrementalScanSummary struct {
 	FilesDeleted   int
 	FilesUnchanged int
 	Duration       time.Duration
+	Partial        bool // Cancelled before every change was applied
 }
 
 // DetectChanges compares the filesystem against the database index
@@ -55,10 +56,11 @@ func DetectChanges(db *sql.DB, root string, guardrails config.Guardrails) ([]Fil
 	}
 
 	// List files on disk
-	diskFiles, err := fsutil.ListFiles(root, guardrails)
+	diskFiles, err := listFiles(root, guardrails)
 	if err != nil {
 		return nil, fmt.Errorf("list files: %w", err)
 	}
+	diskFiles = selectFiles(diskFiles)
 	sort.Strings(diskFiles)
 
 	var changes []FileChange
@@ -114,6 +116,15 @@ func DetectChanges(db *sql.DB, root string, guardrails config.Guardrails) ([]Fil
 // IncrementalScan only processes files that have changed since the last scan.
 // It's much faster than a full scan for large codebases with few changes.
 func IncrementalScan(db *sql.DB, root string, changes []FileChange) (IncrementalScanSummary, error) {
+	return IncrementalScanContext(context.Background(), db, root, changes)
+}
+
+// IncrementalScanContext is IncrementalScan with cancellation, checked
+// before each file. On cancellation the changes applied so far are
+// committed, each file being either fully updated or untouched, and the
+// summary is marked partial alongside the context's error. The remaining
+// changes are picked up by the next scan.
+func IncrementalScanContext(ctx context.Context, db *sql.DB, root string, changes []FileChange) (IncrementalScanSummary, error) {
 	startTime := time.Now()
 	summary := IncrementalScanSummary{}
 
@@ -128,7 +139,24 @@ func IncrementalScan(db *sql.DB, root string, changes []FileChange) (Incremental
 	}
 	defer tx.Rollback()
 
+	profile := ScanProfileFrom(ctx)
+	stopResolve := profile.Start(PhaseResolve)
+	imports := analysis.NewImportResolver(root)
+	stopResolve()
+
+	// Files are parsed and written one at a time; parse time is split out
+	// and the rest of the loop counts as persisting.
+	var parsed time.Duration
+	loopStart := time.Now()
+	defer func() {
+		profile.Add(PhaseParse, parsed)
+		profile.Add(PhasePersist, time.Since(loopStart)-parsed)
+	}()
 	for _, change := range changes {
+		if ctx.Err() != nil {
+			summary.Partial = true
+			break
+		}
 		switch change.Action {
 		case "deleted":
 			if err := deleteFileFromIndex(tx, change.Path); err != nil {
@@ -143,8 +171,9 @@ func IncrementalScan(db *sql.DB, root string, changes []FileChange) (Incremental
 			}
 
 			// Read and index the file
-			absPath := filepath.Join(root, change.Path)
-			if err := indexSingleFile(tx, change.Path, absPath); err != nil {
+			elapsed, err := indexSingleFile(tx, root, change.Path, imports, profile)
+			parsed += elapsed
+			if err != nil {
 				return summary, fmt.Errorf("index %s: %w", change.Path, err)
 			}
 
@@ -161,6 +190,9 @@ func IncrementalScan(db *sql.DB, root string, changes []FileChange) (Incremental
 	}
 
 	summary.Duration = time.Since(startTime)
+	if summary.Partial {
+		return summary, ctx.Err()
+	}
 	return summary, nil
 }
 
@@ -182,17 +214,20 @@ func deleteFileFromIndex(tx *sql.Tx, relPath string) error {
 	return nil
 }
 
-// indexSingleFile indexes a single file into the database
-func indexSingleFile(tx *sql.Tx, relPath, absPath string) error {
+// indexSingleFile indexes a single file into the database, returning the
+// time spent parsing it.
+func indexSingleFile(tx *sql.Tx, root, relPath string, imports analysis.ImportResolver, profile *ScanProfile) (time.Duration, error) {
+	absPath := filepath.Join(root, relPath)
+
 	// Read file info and content
 	info, err := os.Stat(absPath)
 	if err != nil {
-		return fmt.Errorf("stat: %w", err)
+		return 0, fmt.Errorf("stat: %w", err)
 	}
 
 	data, err := os.ReadFile(absPath)
 	if err != nil {
-		return fmt.Errorf("read: %w", err)
+		return 0, fmt.Errorf("read: %w", err)
 	}
 
 	h := sha256.Sum256(data)
@@ -200,20 +235,14 @@ func indexSingleFile(tx *sql.Tx, relPath, absPath string) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	// Detect language and analyze
-	lang := analysis.DetectLanguage(relPath)
-	var fileAnalysis *analysis.FileAnalysis
-	if lang != analysis.LangUnknown {
-		fa, err := analysis.Analyze(data, relPath)
-		if err == nil {
-			fileAnalysis = fa
-		}
-	}
+	lang := analysis.DetectLanguageDetailed(relPath, data).Language
+	fileAnalysis, parseTime := analyzeFile(data, relPath, lang, imports, profile)
 
 	// Insert file record
 	_, err = tx.ExecContext(context.Background(), `INSERT INTO files(path, hash, size, mod_time, indexed_at, language) VALUES(?, ?, ?, ?, ?, ?);`,
-		relPath, hash, info.Size(), fsutil.NormalizeModTime(info.ModTime()).Format(time.RFC3339), now, string(lang))
+		relPath, hash, info.Size(), fsutil.NormalizeModTime(info.ModTime()).Format(time.RFC3339), now, fileLanguage(lang, fileAnalysis))
 	if err != nil {
-		return fmt.Errorf("insert file: %w", err)
+		return parseTime, fmt.Errorf("insert file: %w", err)
 	}
 
 	// Insert chunks
@@ -222,32 +251,35 @@ func indexSingleFile(tx *sql.Tx, relPath, absPath string) error {
 		_, err = tx.ExecContext(context.Background(), `INSERT INTO chunks(path, chunk_index, start_line, end_line, content) VALUES(?, ?, ?, ?, ?);`,
 			relPath, i, chunk.StartLine, chunk.EndLine, chunk.Content)
 		if err != nil {
-			return fmt.Errorf("insert chunk: %w", err)
+			return parseTime, fmt.Errorf("insert chunk: %w", err)
 		}
 		_, err = tx.ExecContext(context.Background(), `INSERT INTO chunks_fts(path, content, chunk_index) VALUES(?, ?, ?);`,
 			relPath, chunk.Content, i)
 		if err != nil {
-			return fmt.Errorf("insert chunk_fts: %w", err)
+			return parseTime, fmt.Errorf("insert chunk_fts: %w", err)
 		}
 	}
 
 	// Insert symbols if analysis succeeded
 	if fileAnalysis != nil {
+		if err := attributeOwners(tx, root, relPath, hash, fileAnalysis.Symbols); err != nil {
+			return parseTime, fmt.Errorf("blame: %w", err)
+		}
 		if err := insertSymbolsRecursive(tx, relPath, fileAnalysis.Symbols, nil); err != nil {
-			return fmt.Errorf("insert symbols: %w", err)
+			return parseTime, fmt.Errorf("insert symbols: %w", err)
 		}
 
 		// Insert relationships
 		for _, rel := range fileAnalysis.Relationships {
-			_, err = tx.ExecContext(context.Background(), `INSERT INTO relationships(source_file, source_symbol_id, target_file, target_symbol, kind, line, column) VALUES(?, ?, ?, ?, ?, ?, ?);`,
-				relPath, nil, rel.TargetFile, rel.TargetSymbol, string(rel.Kind), rel.Line, rel.Column)
+			_, err = tx.ExecContext(context.Background(), `INSERT INTO relationships(source_file, source_symbol_id, target_file, target_symbol, kind, line, column, import_kind) VALUES(?, ?, ?, ?, ?, ?, ?, ?);`,
+				relPath, nil, rel.TargetFile, rel.TargetSymbol, string(rel.Kind), rel.Line, rel.Column, string(rel.ImportKind))
 			if err != nil {
-				return fmt.Errorf("insert relationship: %w", err)
+				return parseTime, fmt.Errorf("insert relationship: %w", err)
 			}
 		}
 	}
 
-	return nil
+	return parseTime, nil
 }
 
 // insertSymbolsRecursive inserts symbols and their children recursively
@@ -258,8 +290,8 @@ func insertSymbolsRecursive(tx *sql.Tx, filePath string, symbols []analysis.Symb
 			exported = 1
 		}
 
-		result, err := tx.ExecContext(context.Background(), `INSERT INTO symbols(file_path, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?);`,
-			filePath, sym.Name, string(sym.Kind), sym.LineStart, sym.LineEnd, sym.Signature, sym.DocComment, parentID, exported)
+		result, err := tx.ExecContext(context.Background(), `INSERT INTO symbols(file_path, name, kind, line_start, line_end, signature, doc_comment, parent_id, exported, deprecated, experimental, owner, last_commit, value, assertions, overload_key, low_confidence) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+			filePath, sym.Name, string(sym.Kind), sym.LineStart, sym.LineEnd, sym.Signature, sym.DocComment, parentID, exported, sym.Deprecated, sym.Experimental, sym.Owner, sym.LastCommit, sym.Value, assertions(sym), analysis.SymbolKey(sym), sym.LowConfidence)
 		if err != nil {
 			return fmt.Errorf("insert symbol %s: %w", sym.Name, err)
 		}
@@ -271,12 +303,18 @@ func insertSymbolsRecursive(tx *sql.Tx, filePath string, symbols []analysis.Symb
 			return fmt.Errorf("insert symbol_fts %s: %w", sym.Name, err)
 		}
 
+		symID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get symbol id for %s: %w", sym.Name, err)
+		}
+		for _, ann := range sym.Annotations {
+			if _, err := tx.ExecContext(context.Background(), `INSERT INTO symbol_annotations(symbol_id, annotation) VALUES(?, ?);`, symID, ann); err != nil {
+				return fmt.Errorf("insert annotation for %s: %w", sym.Name, err)
+			}
+		}
+
 		// Insert children recursively
 		if len(sym.Children) > 0 {
-			symID, err := result.LastInsertId()
-			if err != nil {
-				return fmt.Errorf("get symbol id for %s: %w", sym.Name, err)
-			}
 			if err := insertSymbolsRecursive(tx, filePath, sym.Children, &symID); err != nil {
 				return err
 			}
@@ -0,0 +1,107 @@
+package index
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+)
+
+// Scans can be narrowed to part of the tree by a test filter and a maximum
+// directory depth. Both apply on top of the guardrails, to full scans and
+// to the change lists of incremental ones.
+
+// TestFilter selects the files a scan indexes by whether they hold tests
+// (see analysis.IsTestFile).
+type TestFilter int32
+
+// Test filters.
+const (
+	AllFiles  TestFilter = iota // Index test and non-test files
+	TestsOnly                   // Index only test files
+	NoTests                     // Index only non-test files
+)
+
+var (
+	testFilter atomic.Int32
+	maxDepth   atomic.Int32 // Deepest directory level indexed, plus one; 0 = unlimited
+	pruned     atomic.Int64 // Directories the last listing left out for depth
+)
+
+// SetTestFilter sets the test filter applied when scans list files.
+func SetTestFilter(f TestFilter) {
+	testFilter.Store(int32(f))
+}
+
+// SetMaxDepth limits scans to files at most depth directories below the
+// scan root, which is depth 0. A negative depth removes the limit. Setting
+// a limit also resets DepthPruned.
+func SetMaxDepth(depth int) {
+	if depth >= 0 {
+		pruned.Store(0)
+	}
+	maxDepth.Store(int32(depth + 1))
+}
+
+// DepthPruned returns how many directories the most recent scan listing
+// left unwalked because they lie deeper than the depth limit.
+func DepthPruned() int {
+	return int(pruned.Load())
+}
+
+// FileDepth returns how many directories below the scan root the file at
+// the slash-separated relative path lies; files in the root are at depth 0.
+func FileDepth(path string) int {
+	return strings.Count(strings.Trim(path, "/"), "/")
+}
+
+// BeyondMaxDepth reports whether the file at path lies deeper than the
+// current depth limit.
+func BeyondMaxDepth(path string) bool {
+	limit := int(maxDepth.Load())
+	return limit > 0 && FileDepth(path) > limit-1
+}
+
+// Selected reports whether the file at path passes the current test filter
+// and depth limit.
+func Selected(path string) bool {
+	if BeyondMaxDepth(path) {
+		return false
+	}
+	switch TestFilter(testFilter.Load()) {
+	case TestsOnly:
+		return analysis.IsTestFile(path, analysis.DetectLanguage(path))
+	case NoTests:
+		return !analysis.IsTestFile(path, analysis.DetectLanguage(path))
+	default:
+		return true
+	}
+}
+
+// listFiles lists the files under root that pass the guardrails, without
+// walking directories beyond the depth limit.
+func listFiles(root string, guardrails config.Guardrails) ([]string, error) {
+	files, n, err := fsutil.ListFilesToDepth(root, guardrails, int(maxDepth.Load())-1)
+	if err != nil {
+		return nil, err
+	}
+	pruned.Store(int64(n))
+	return files, nil
+}
+
+// selectFiles drops the files that fail the current test filter or depth
+// limit.
+func selectFiles(files []string) []string {
+	if TestFilter(testFilter.Load()) == AllFiles && maxDepth.Load() == 0 {
+		return files
+	}
+	kept := files[:0]
+	for _, f := range files {
+		if Selected(f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
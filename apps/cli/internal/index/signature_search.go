@@ -0,0 +1,56 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// SignatureMatch is a callable found by its signature's shape.
+type SignatureMatch struct {
+	File      string `json:"file"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Line      int    `json:"line"`
+	Signature string `json:"signature"`
+}
+
+// FindSymbolsBySignature returns the functions, methods, and constructors
+// whose signature has the queried shape (see analysis.SignatureQuery),
+// ordered by file and line. limit <= 0 returns them all.
+func FindSymbolsBySignature(db *sql.DB, q analysis.SignatureQuery, limit int) ([]SignatureMatch, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT file_path, name, kind, line_start, signature
+		FROM symbols
+		WHERE kind IN (?, ?, ?) AND COALESCE(signature, '') != ''
+		ORDER BY file_path, line_start, id;
+	`, analysis.KindFunction, analysis.KindMethod, analysis.KindConstructor)
+	if err != nil {
+		return nil, fmt.Errorf("query callables: %w", err)
+	}
+	defer rows.Close()
+
+	langs := make(map[string]analysis.Language)
+	var result []SignatureMatch
+	for rows.Next() {
+		var m SignatureMatch
+		if err := rows.Scan(&m.File, &m.Name, &m.Kind, &m.Line, &m.Signature); err != nil {
+			return nil, err
+		}
+		lang, ok := langs[m.File]
+		if !ok {
+			lang = analysis.DetectLanguage(m.File)
+			langs[m.File] = lang
+		}
+		if !q.Match(m.Signature, lang) {
+			continue
+		}
+		result = append(result, m)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, rows.Err()
+}
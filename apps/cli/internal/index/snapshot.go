@@ -0,0 +1,303 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// Snapshot formats.
+const (
+	SnapshotJSON   = "json"   // Readable, for debugging and diffing
+	SnapshotBinary = "binary" // Compact gob encoding, fast to load for large repositories
+)
+
+// SnapshotVersion is the version of the snapshot layout written by
+// WriteSnapshot. Readers accept this version and earlier ones.
+const SnapshotVersion = 1
+
+// snapshotMagic starts every binary snapshot, followed by one version byte.
+var snapshotMagic = []byte("PALIDX")
+
+// snapshotKind identifies a JSON snapshot.
+const snapshotKind = "palace/index-snapshot"
+
+// Snapshot is a portable copy of the symbol index: every symbol and every
+// relationship, keyed by their index IDs so parents and relationship sources
+// can be followed within the snapshot.
+type Snapshot struct {
+	Kind          string                 `json:"kind"`
+	Version       int                    `json:"version"`
+	Symbols       []SnapshotSymbol       `json:"symbols"`
+	Relationships []SnapshotRelationship `json:"relationships"`
+}
+
+// SnapshotSymbol is one symbol in a snapshot.
+type SnapshotSymbol struct {
+	ID         int64  `json:"id"`
+	File       string `json:"file"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	LineStart  int    `json:"lineStart"`
+	LineEnd    int    `json:"lineEnd"`
+	Signature  string `json:"signature,omitempty"`
+	DocComment string `json:"docComment,omitempty"`
+	ParentID   int64  `json:"parentId,omitempty"` // 0 for top-level symbols
+	Exported   bool   `json:"exported,omitempty"`
+
+	Deprecated    bool     `json:"deprecated,omitempty"`
+	Experimental  bool     `json:"experimental,omitempty"`
+	Owner         string   `json:"owner,omitempty"`
+	LastCommit    string   `json:"lastCommit,omitempty"`
+	Value         string   `json:"value,omitempty"`
+	Assertions    *int     `json:"assertions,omitempty"` // nil for symbols that are not tests
+	OverloadKey   string   `json:"overloadKey,omitempty"`
+	LowConfidence bool     `json:"lowConfidence,omitempty"`
+	Annotations   []string `json:"annotations,omitempty"`
+}
+
+// SnapshotRelationship is one relationship in a snapshot.
+type SnapshotRelationship struct {
+	SourceFile     string `json:"sourceFile"`
+	SourceSymbolID int64  `json:"sourceSymbolId,omitempty"` // 0 at file level
+	TargetFile     string `json:"targetFile,omitempty"`
+	TargetSymbol   string `json:"targetSymbol,omitempty"`
+	Kind           string `json:"kind"`
+	Line           int    `json:"line"`
+	Column         int    `json:"column,omitempty"`
+	ImportKind     string `json:"importKind,omitempty"`
+}
+
+// LoadSnapshot reads every symbol and relationship from the index.
+func LoadSnapshot(db *sql.DB) (*Snapshot, error) {
+	snap := &Snapshot{Kind: snapshotKind, Version: SnapshotVersion}
+
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT id, file_path, name, kind, line_start, line_end,
+		       COALESCE(signature, ''), COALESCE(doc_comment, ''),
+		       COALESCE(parent_id, 0), exported, COALESCE(deprecated, 0),
+		       COALESCE(experimental, 0), COALESCE(owner, ''), COALESCE(last_commit, ''),
+		       COALESCE(value, ''), assertions, COALESCE(overload_key, ''),
+		       COALESCE(low_confidence, 0)
+		FROM symbols
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols: %w", err)
+	}
+	byID := make(map[int64]int)
+	for rows.Next() {
+		var s SnapshotSymbol
+		var assertions sql.NullInt64
+		if err := rows.Scan(&s.ID, &s.File, &s.Name, &s.Kind, &s.LineStart, &s.LineEnd,
+			&s.Signature, &s.DocComment, &s.ParentID, &s.Exported, &s.Deprecated,
+			&s.Experimental, &s.Owner, &s.LastCommit, &s.Value, &assertions,
+			&s.OverloadKey, &s.LowConfidence); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if assertions.Valid {
+			n := int(assertions.Int64)
+			s.Assertions = &n
+		}
+		byID[s.ID] = len(snap.Symbols)
+		snap.Symbols = append(snap.Symbols, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(context.Background(), `
+		SELECT symbol_id, annotation FROM symbol_annotations ORDER BY rowid;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query annotations: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		var annotation string
+		if err := rows.Scan(&id, &annotation); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if i, ok := byID[id]; ok {
+			snap.Symbols[i].Annotations = append(snap.Symbols[i].Annotations, annotation)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(context.Background(), `
+		SELECT source_file, COALESCE(source_symbol_id, 0), COALESCE(target_file, ''),
+		       COALESCE(target_symbol, ''), kind, line, column, COALESCE(import_kind, '')
+		FROM relationships
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query relationships: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r SnapshotRelationship
+		if err := rows.Scan(&r.SourceFile, &r.SourceSymbolID, &r.TargetFile,
+			&r.TargetSymbol, &r.Kind, &r.Line, &r.Column, &r.ImportKind); err != nil {
+			return nil, err
+		}
+		snap.Relationships = append(snap.Relationships, r)
+	}
+	return snap, rows.Err()
+}
+
+// WriteSnapshot writes snap to w in the given format, SnapshotJSON or
+// SnapshotBinary. Binary snapshots start with a magic header and the layout
+// version; JSON snapshots carry both as their kind and version fields.
+func WriteSnapshot(w io.Writer, snap *Snapshot, format string) error {
+	out := *snap
+	out.Kind, out.Version = snapshotKind, SnapshotVersion
+	switch format {
+	case SnapshotJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(&out)
+	case SnapshotBinary:
+		bw := bufio.NewWriter(w)
+		if _, err := bw.Write(snapshotMagic); err != nil {
+			return fmt.Errorf("write snapshot header: %w", err)
+		}
+		if err := bw.WriteByte(SnapshotVersion); err != nil {
+			return fmt.Errorf("write snapshot header: %w", err)
+		}
+		if err := gob.NewEncoder(bw).Encode(&out); err != nil {
+			return fmt.Errorf("encode snapshot: %w", err)
+		}
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("write snapshot: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown snapshot format %q (use %s or %s)", format, SnapshotJSON, SnapshotBinary)
+	}
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot, telling the format
+// apart by its header. Snapshots of a newer version than this build
+// understands are rejected rather than misread.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(snapshotMagic) + 1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read snapshot header: %w", err)
+	}
+
+	var snap Snapshot
+	if bytes.HasPrefix(head, snapshotMagic) && len(head) > len(snapshotMagic) {
+		version := int(head[len(snapshotMagic)])
+		if version > SnapshotVersion {
+			return nil, fmt.Errorf("snapshot version %d is newer than supported version %d", version, SnapshotVersion)
+		}
+		br.Discard(len(head))
+		if err := gob.NewDecoder(br).Decode(&snap); err != nil {
+			return nil, fmt.Errorf("decode binary snapshot: %w", err)
+		}
+		snap.Version = version
+	} else {
+		if err := json.NewDecoder(br).Decode(&snap); err != nil {
+			return nil, fmt.Errorf("decode JSON snapshot: %w", err)
+		}
+		if snap.Kind != snapshotKind {
+			return nil, errors.New("not an index snapshot")
+		}
+		if snap.Version > SnapshotVersion {
+			return nil, fmt.Errorf("snapshot version %d is newer than supported version %d", snap.Version, SnapshotVersion)
+		}
+	}
+	return migrateSnapshot(&snap)
+}
+
+// migrateSnapshot brings a snapshot of an earlier version up to
+// SnapshotVersion. Version 1 is the first layout, so there is nothing to
+// migrate yet; later layout changes convert old snapshots here.
+func migrateSnapshot(snap *Snapshot) (*Snapshot, error) {
+	if snap.Version < 1 {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	snap.Version = SnapshotVersion
+	return snap, nil
+}
+
+// CompareSnapshots reports the symbol-level changes from an earlier snapshot
+// to a later one, matched the same way palace diff matches two versions of a
+// file, along with the number of files compared. Snapshots hold no source,
+// so a change to the body of a symbol alone is not detected.
+func CompareSnapshots(old, cur *Snapshot) ([]analysis.SymbolChange, int) {
+	oldFiles, curFiles := old.fileAnalyses(), cur.fileAnalyses()
+	paths := make([]string, 0, len(curFiles))
+	for path := range curFiles {
+		paths = append(paths, path)
+	}
+	for path := range oldFiles {
+		if _, ok := curFiles[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []analysis.SymbolChange
+	for _, path := range paths {
+		changes = append(changes, analysis.DiffSymbols(path, oldFiles[path], nil, curFiles[path], nil)...)
+	}
+	return changes, len(paths)
+}
+
+// fileAnalyses rebuilds the symbol tree of every file in the snapshot.
+func (snap *Snapshot) fileAnalyses() map[string]*analysis.FileAnalysis {
+	children := make(map[int64][]int64)
+	var roots []SnapshotSymbol
+	byID := make(map[int64]SnapshotSymbol, len(snap.Symbols))
+	for _, s := range snap.Symbols {
+		byID[s.ID] = s
+		if s.ParentID == 0 {
+			roots = append(roots, s)
+		} else {
+			children[s.ParentID] = append(children[s.ParentID], s.ID)
+		}
+	}
+
+	var build func(s SnapshotSymbol) analysis.Symbol
+	build = func(s SnapshotSymbol) analysis.Symbol {
+		sym := analysis.Symbol{
+			Name:      s.Name,
+			Kind:      analysis.SymbolKind(s.Kind),
+			LineStart: s.LineStart,
+			LineEnd:   s.LineEnd,
+			Signature: s.Signature,
+		}
+		for _, id := range children[s.ID] {
+			sym.Children = append(sym.Children, build(byID[id]))
+		}
+		return sym
+	}
+
+	files := make(map[string]*analysis.FileAnalysis)
+	for _, s := range roots {
+		fa := files[s.File]
+		if fa == nil {
+			fa = &analysis.FileAnalysis{Path: s.File}
+			files[s.File] = fa
+		}
+		fa.Symbols = append(fa.Symbols, build(s))
+	}
+	return files
+}
@@ -0,0 +1,160 @@
+package index
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	src := `import java.util.List;
+
+/** Answers requests. */
+public class Server implements Runnable {
+    /** Starts serving. */
+    @Override
+    public void run() {
+        listen(List.of());
+    }
+
+    @Deprecated
+    private void listen(List<String> addrs) {}
+}
+`
+	fa, err := analysis.Analyze([]byte(src), "Server.java")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if _, err := WriteScan(db, "/repo", []FileRecord{{Path: "Server.java", Hash: "h", Analysis: fa}}, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+
+	snap, err := LoadSnapshot(db)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	if len(snap.Symbols) < 3 || len(snap.Relationships) == 0 {
+		t.Fatalf("snapshot should hold the symbols and relationships, got %+v", snap)
+	}
+	var nested, annotated, deprecated, keyed bool
+	for _, s := range snap.Symbols {
+		nested = nested || s.ParentID != 0
+		annotated = annotated || len(s.Annotations) > 0
+		deprecated = deprecated || s.Deprecated
+		keyed = keyed || s.OverloadKey == "listen(List<String>)"
+	}
+	if !nested {
+		t.Errorf("methods should keep their parent: %+v", snap.Symbols)
+	}
+	if !annotated || !deprecated || !keyed {
+		t.Errorf("snapshot should hold annotations, flags, and overload keys: %+v", snap.Symbols)
+	}
+
+	for _, format := range []string{SnapshotBinary, SnapshotJSON} {
+		var buf bytes.Buffer
+		if err := WriteSnapshot(&buf, snap, format); err != nil {
+			t.Fatalf("WriteSnapshot(%s) error = %v", format, err)
+		}
+		if format == SnapshotBinary && !bytes.HasPrefix(buf.Bytes(), []byte("PALIDX\x01")) {
+			t.Errorf("binary snapshot should start with the magic header, got %q", buf.Bytes()[:8])
+		}
+		got, err := ReadSnapshot(&buf)
+		if err != nil {
+			t.Fatalf("ReadSnapshot(%s) error = %v", format, err)
+		}
+		if !reflect.DeepEqual(got, snap) {
+			t.Errorf("%s round trip changed the snapshot:\ngot  %+v\nwant %+v", format, got, snap)
+		}
+	}
+}
+
+func TestReadSnapshotRejectsUnknownInput(t *testing.T) {
+	for name, input := range map[string]string{
+		"newer binary": "PALIDX\x09",
+		"newer JSON":   `{"kind": "palace/index-snapshot", "version": 9}`,
+		"other JSON":   `{"symbols": []}`,
+		"garbage":      "not a snapshot",
+	} {
+		if _, err := ReadSnapshot(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: ReadSnapshot() should fail", name)
+		}
+	}
+	if err := WriteSnapshot(&bytes.Buffer{}, &Snapshot{}, "yaml"); err == nil {
+		t.Error("WriteSnapshot() should reject an unknown format")
+	}
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	old := &Snapshot{Symbols: []SnapshotSymbol{
+		{ID: 1, File: "a.go", Name: "Server", Kind: "class", LineStart: 1, LineEnd: 20},
+		{ID: 2, File: "a.go", Name: "Start", Kind: "method", LineStart: 2, LineEnd: 5, Signature: "Start(port int)", ParentID: 1},
+		{ID: 3, File: "a.go", Name: "Stop", Kind: "method", LineStart: 6, LineEnd: 8, Signature: "Stop()", ParentID: 1},
+		{ID: 4, File: "b.go", Name: "helper", Kind: "function", LineStart: 1, LineEnd: 3, Signature: "helper()"},
+	}}
+	cur := &Snapshot{Symbols: []SnapshotSymbol{
+		{ID: 7, File: "a.go", Name: "Server", Kind: "class", LineStart: 1, LineEnd: 30},
+		{ID: 8, File: "a.go", Name: "Start", Kind: "method", LineStart: 2, LineEnd: 5, Signature: "Start(port int, host string)", ParentID: 7},
+		{ID: 9, File: "a.go", Name: "Stop", Kind: "method", LineStart: 9, LineEnd: 12, Signature: "Stop()", ParentID: 7},
+		{ID: 10, File: "c.go", Name: "Run", Kind: "function", LineStart: 1, LineEnd: 3, Signature: "Run()"},
+	}}
+
+	changes, files := CompareSnapshots(old, cur)
+	if files != 3 {
+		t.Errorf("files = %d, want 3", files)
+	}
+	got := make(map[string]analysis.SymbolChangeKind)
+	for _, c := range changes {
+		got[c.ID] = c.Change
+	}
+	want := map[string]analysis.SymbolChangeKind{
+		"a.go#Server.Start": analysis.SymbolSignatureChanged,
+		"b.go#helper":       analysis.SymbolRemoved,
+		"c.go#Run":          analysis.SymbolAdded,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareSnapshots() = %v, want %v", got, want)
+	}
+}
+
+// BenchmarkReadSnapshot compares loading a large index from each format.
+func BenchmarkReadSnapshot(b *testing.B) {
+	snap := &Snapshot{}
+	for i := 0; i < 50000; i++ {
+		file := fmt.Sprintf("pkg%d/file%d.go", i/500, i/50)
+		snap.Symbols = append(snap.Symbols, SnapshotSymbol{
+			ID: int64(i + 1), File: file, Name: fmt.Sprintf("Symbol%d", i), Kind: "function",
+			LineStart: i % 1000, LineEnd: i%1000 + 20, Signature: fmt.Sprintf("func Symbol%d(ctx context.Context, n int) error", i),
+			DocComment: "Symbol does a thing.", Exported: i%2 == 0,
+		})
+		snap.Relationships = append(snap.Relationships, SnapshotRelationship{
+			SourceFile: file, SourceSymbolID: int64(i + 1), TargetSymbol: fmt.Sprintf("Symbol%d", i+1), Kind: "call", Line: i % 1000,
+		})
+	}
+	for _, format := range []string{SnapshotJSON, SnapshotBinary} {
+		var buf bytes.Buffer
+		if err := WriteSnapshot(&buf, snap, format); err != nil {
+			b.Fatal(err)
+		}
+		data := buf.Bytes()
+		b.Run(format, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, err := ReadSnapshot(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+)
+
+// Stats counts what the index holds.
+type Stats struct {
+	Files               int            `json:"files"`
+	Chunks              int            `json:"chunks"`
+	Symbols             int            `json:"symbols"`
+	SymbolsByKind       map[string]int `json:"symbolsByKind"`
+	Relationships       int            `json:"relationships"`
+	RelationshipsByKind map[string]int `json:"relationshipsByKind"`
+}
+
+// Equal reports whether s and o hold the same counts.
+func (s *Stats) Equal(o *Stats) bool {
+	return s.Files == o.Files && s.Chunks == o.Chunks &&
+		s.Symbols == o.Symbols && maps.Equal(s.SymbolsByKind, o.SymbolsByKind) &&
+		s.Relationships == o.Relationships && maps.Equal(s.RelationshipsByKind, o.RelationshipsByKind)
+}
+
+// statCounter is a table whose rows index_stats counts, by kind when
+// kindColumn is set.
+type statCounter struct {
+	table, metric, kindColumn string
+}
+
+// kindExpr is the SQL for the kind a row of the table is counted under.
+func (c statCounter) kindExpr(row string) string {
+	if c.kindColumn == "" {
+		return "''"
+	}
+	return row + "." + c.kindColumn
+}
+
+var statCounters = []statCounter{
+	{table: "files", metric: "files"},
+	{table: "chunks", metric: "chunks"},
+	{table: "symbols", metric: "symbols", kindColumn: "kind"},
+	{table: "relationships", metric: "relationships", kindColumn: "kind"},
+}
+
+// GetStats returns the running counts triggers keep as files are indexed
+// and removed, without scanning the indexed tables.
+func GetStats(db *sql.DB) (*Stats, error) {
+	return readStats(db, `SELECT metric, kind, count FROM index_stats WHERE count != 0`)
+}
+
+// ComputeStats counts the indexed rows from scratch. It agrees with
+// GetStats unless the running counts have drifted, and is much slower on a
+// large index.
+func ComputeStats(db *sql.DB) (*Stats, error) {
+	query := ""
+	for i, c := range statCounters {
+		if i > 0 {
+			query += " UNION ALL "
+		}
+		query += fmt.Sprintf(`SELECT '%s', %s, COUNT(*) FROM %s GROUP BY 2`, c.metric, c.kindExpr(c.table), c.table)
+	}
+	return readStats(db, query)
+}
+
+// RecomputeStats replaces the running counts with a full count and returns
+// the counts it replaced, so a caller can tell whether they had drifted.
+func RecomputeStats(db *sql.DB) (before, after *Stats, err error) {
+	before, err = GetStats(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := resetStats(tx); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("commit stats: %w", err)
+	}
+	after, err = GetStats(db)
+	return before, after, err
+}
+
+// resetStats sets the running counts from a full count of the tables.
+func resetStats(tx *sql.Tx) error {
+	if _, err := tx.ExecContext(context.Background(), `DELETE FROM index_stats`); err != nil {
+		return fmt.Errorf("clear index stats: %w", err)
+	}
+	for _, c := range statCounters {
+		_, err := tx.ExecContext(context.Background(), fmt.Sprintf(
+			`INSERT INTO index_stats(metric, kind, count) SELECT '%s', %s, COUNT(*) FROM %s GROUP BY 2`,
+			c.metric, c.kindExpr(c.table), c.table))
+		if err != nil {
+			return fmt.Errorf("count %s: %w", c.table, err)
+		}
+	}
+	return nil
+}
+
+// readStats runs a query selecting metric, kind, and count rows.
+func readStats(db *sql.DB, query string) (*Stats, error) {
+	rows, err := db.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("query index stats: %w", err)
+	}
+	defer rows.Close()
+
+	s := &Stats{SymbolsByKind: map[string]int{}, RelationshipsByKind: map[string]int{}}
+	for rows.Next() {
+		var metric, kind string
+		var count int
+		if err := rows.Scan(&metric, &kind, &count); err != nil {
+			return nil, fmt.Errorf("scan index stats: %w", err)
+		}
+		switch metric {
+		case "files":
+			s.Files += count
+		case "chunks":
+			s.Chunks += count
+		case "symbols":
+			s.Symbols += count
+			s.SymbolsByKind[kind] += count
+		case "relationships":
+			s.Relationships += count
+			s.RelationshipsByKind[kind] += count
+		}
+	}
+	return s, rows.Err()
+}
+
+// SymbolsPerFile returns every indexed file with its number of symbols,
+// nested ones included.
+func SymbolsPerFile(db *sql.DB) (map[string]int, error) {
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT f.path, COUNT(s.id) FROM files f
+		LEFT JOIN symbols s ON s.file_path = f.path
+		GROUP BY f.path;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("count symbols per file: %w", err)
+	}
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var file string
+		var n int
+		if err := rows.Scan(&file, &n); err != nil {
+			return nil, err
+		}
+		counts[file] = n
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,92 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+func TestStatsStayConsistentWithRecount(t *testing.T) {
+	root := t.TempDir()
+	db, err := Open(filepath.Join(root, "palace.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	files := map[string]string{
+		"a.go": "package a\n\nimport \"fmt\"\n\nfunc A() { fmt.Println(B()) }\n\nfunc B() string { return \"b\" }\n",
+		"b.go": "package a\n\ntype T struct{}\n\nfunc (T) M() {}\n",
+		"c.py": "import os\n\nclass C:\n    def m(self):\n        return os.getcwd()\n",
+	}
+	var records []FileRecord
+	for path, src := range files {
+		fa, err := analysis.Analyze([]byte(src), path)
+		if err != nil {
+			t.Fatalf("Analyze(%s) error = %v", path, err)
+		}
+		records = append(records, FileRecord{Path: path, Hash: path, Analysis: fa})
+	}
+	if _, err := WriteScan(db, root, records, time.Now()); err != nil {
+		t.Fatalf("WriteScan() error = %v", err)
+	}
+
+	check := func(step string) *Stats {
+		t.Helper()
+		running, err := GetStats(db)
+		if err != nil {
+			t.Fatalf("%s: GetStats() error = %v", step, err)
+		}
+		full, err := ComputeStats(db)
+		if err != nil {
+			t.Fatalf("%s: ComputeStats() error = %v", step, err)
+		}
+		if !running.Equal(full) {
+			t.Fatalf("%s: running stats %+v, full recount %+v", step, running, full)
+		}
+		return running
+	}
+	stats := check("full scan")
+	if stats.Files != 3 || stats.Symbols == 0 || stats.Relationships == 0 {
+		t.Fatalf("after full scan stats = %+v, want 3 files with symbols and relationships", stats)
+	}
+
+	// Upsert one file with new content, add another, and remove a third.
+	for path, src := range map[string]string{
+		"a.go": "package a\n\nfunc A() {}\n",
+		"d.go": "package a\n\nconst D = 1\n\nvar E = 2\n",
+	} {
+		if err := os.WriteFile(filepath.Join(root, path), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	changes := []FileChange{
+		{Path: "a.go", Action: "modified"},
+		{Path: "d.go", Action: "added"},
+		{Path: "c.py", Action: "deleted"},
+	}
+	if _, err := IncrementalScan(db, root, changes); err != nil {
+		t.Fatalf("IncrementalScan() error = %v", err)
+	}
+	stats = check("incremental scan")
+	if stats.Files != 3 || stats.SymbolsByKind[string(analysis.KindConstant)] != 1 {
+		t.Errorf("after incremental scan stats = %+v, want 3 files and d.go's constant", stats)
+	}
+
+	// Drift is repaired by a recompute.
+	if _, err := db.ExecContext(context.Background(), `UPDATE index_stats SET count = count + 5 WHERE metric = 'files'`); err != nil {
+		t.Fatal(err)
+	}
+	before, after, err := RecomputeStats(db)
+	if err != nil {
+		t.Fatalf("RecomputeStats() error = %v", err)
+	}
+	if before.Files != 8 || after.Files != 3 {
+		t.Errorf("RecomputeStats() files %d -> %d, want 8 -> 3", before.Files, after.Files)
+	}
+	check("recompute")
+}
@@ -145,7 +145,7 @@ func TruncateSymbols(symbols []SymbolInfo, budget int) []SymbolInfo {
 			priority = 3.0
 		case "class", "struct", "interface":
 			priority = 2.5
-		case "type":
+		case "type", "type_alias":
 			priority = 2.0
 		case "constant", "variable":
 			priority = 1.5
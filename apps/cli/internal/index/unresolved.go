@@ -0,0 +1,157 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnresolvedCause is the likely reason a call could not be resolved to a symbol.
+type UnresolvedCause string
+
+const (
+	// UnresolvedExternal is a qualified call (pkg.Func, mod::func) to a name
+	// not defined anywhere in the index, usually an unscanned dependency.
+	UnresolvedExternal UnresolvedCause = "external"
+	// UnresolvedAmbiguous is a name defined in several files, none of them the
+	// caller's, so the target cannot be picked by name alone.
+	UnresolvedAmbiguous UnresolvedCause = "ambiguous"
+	// UnresolvedUntracked is an unqualified call to a name not defined in the
+	// index: a builtin, an import alias the parser does not track, or a
+	// dynamically bound function.
+	UnresolvedUntracked UnresolvedCause = "untracked"
+)
+
+// UnresolvedCallee aggregates the unresolved calls to one callee name.
+type UnresolvedCallee struct {
+	Name    string          `json:"name"`
+	Cause   UnresolvedCause `json:"cause"`
+	Count   int             `json:"count"`
+	Example string          `json:"example"` // file:line of the first call site
+}
+
+// UnresolvedReport summarizes call relationships that do not resolve to an indexed symbol.
+type UnresolvedReport struct {
+	TotalCalls int                     `json:"totalCalls"`
+	Unresolved int                     `json:"unresolved"`
+	ByCause    map[UnresolvedCause]int `json:"byCause"`
+	Callees    []UnresolvedCallee      `json:"callees"`
+}
+
+// GetUnresolvedCalls returns the most common callee names whose calls do not
+// resolve to any indexed symbol, with counts and a likely cause. A call
+// resolves when its bare name is defined in the caller's file or in exactly
+// one other file. top limits the number of callees returned (0 = all).
+func GetUnresolvedCalls(db *sql.DB, top int) (*UnresolvedReport, error) {
+	definedIn, err := symbolFilesByName(db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT source_file, line, target_symbol
+		FROM relationships
+		WHERE kind = 'call'
+		ORDER BY source_file, line;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query calls: %w", err)
+	}
+	defer rows.Close()
+
+	report := &UnresolvedReport{ByCause: map[UnresolvedCause]int{}}
+	byName := map[string]*UnresolvedCallee{}
+	for rows.Next() {
+		var file, target string
+		var line int
+		if err := rows.Scan(&file, &line, &target); err != nil {
+			return nil, err
+		}
+		report.TotalCalls++
+
+		qualified, name := splitCallee(target)
+		files := definedIn[name]
+		var cause UnresolvedCause
+		switch {
+		case len(files) == 0 && qualified:
+			cause = UnresolvedExternal
+		case len(files) == 0:
+			cause = UnresolvedUntracked
+		case len(files) > 1 && !files[file]:
+			cause = UnresolvedAmbiguous
+		default:
+			continue
+		}
+
+		report.Unresolved++
+		report.ByCause[cause]++
+		callee, ok := byName[target]
+		if !ok {
+			callee = &UnresolvedCallee{Name: target, Cause: cause, Example: fmt.Sprintf("%s:%d", file, line)}
+			byName[target] = callee
+		}
+		callee.Count++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range byName {
+		report.Callees = append(report.Callees, *c)
+	}
+	sort.Slice(report.Callees, func(i, j int) bool {
+		if report.Callees[i].Count != report.Callees[j].Count {
+			return report.Callees[i].Count > report.Callees[j].Count
+		}
+		return report.Callees[i].Name < report.Callees[j].Name
+	})
+	if top > 0 && len(report.Callees) > top {
+		report.Callees = report.Callees[:top]
+	}
+	return report, nil
+}
+
+// symbolFilesByName maps each symbol name to the set of files defining it.
+func symbolFilesByName(db *sql.DB) (map[string]map[string]bool, error) {
+	rows, err := db.QueryContext(context.Background(), `SELECT DISTINCT name, file_path FROM symbols;`)
+	if err != nil {
+		return nil, fmt.Errorf("query symbols: %w", err)
+	}
+	defer rows.Close()
+
+	result := map[string]map[string]bool{}
+	for rows.Next() {
+		var name, file string
+		if err := rows.Scan(&name, &file); err != nil {
+			return nil, err
+		}
+		if result[name] == nil {
+			result[name] = map[string]bool{}
+		}
+		result[name][file] = true
+	}
+	return result, rows.Err()
+}
+
+// splitCallee returns the bare name of a call target and whether it was
+// qualified by a package or module. Receiver qualifiers such as "this." and
+// "self." do not count as qualification.
+func splitCallee(target string) (qualified bool, name string) {
+	name = strings.TrimSuffix(target, "()")
+	if idx := strings.LastIndex(name, " "); idx >= 0 {
+		name = name[idx+1:]
+	}
+	qualifier := ""
+	if idx := strings.LastIndex(name, "::"); idx >= 0 {
+		qualifier, name = name[:idx], name[idx+2:]
+	} else if idx := strings.LastIndex(name, "."); idx >= 0 {
+		qualifier, name = name[:idx], name[idx+1:]
+	}
+	switch qualifier {
+	case "", "this", "self", "super", "cls", "Self":
+		return false, name
+	}
+	return true, name
+}
@@ -0,0 +1,40 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// VerifyScan checks that db is a sound SQLite database holding exactly what
+// the scan behind summary wrote: as many files, chunks, symbols, and
+// relationships as the summary counts.
+func VerifyScan(db *sql.DB, summary ScanSummary) error {
+	var integrity string
+	if err := db.QueryRowContext(context.Background(), "PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	if integrity != "ok" {
+		return fmt.Errorf("integrity check: %s", integrity)
+	}
+
+	counts := []struct {
+		table string
+		want  int
+	}{
+		{"files", summary.FileCount},
+		{"chunks", summary.ChunkCount},
+		{"symbols", summary.SymbolCount},
+		{"relationships", summary.RelationshipCount},
+	}
+	for _, c := range counts {
+		var got int
+		if err := db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM "+c.table).Scan(&got); err != nil {
+			return fmt.Errorf("count %s: %w", c.table, err)
+		}
+		if got != c.want {
+			return fmt.Errorf("index holds %d %s, scan wrote %d", got, c.table, c.want)
+		}
+	}
+	return nil
+}
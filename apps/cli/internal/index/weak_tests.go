@@ -0,0 +1,65 @@
+package index
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+)
+
+// WeakTest is a test function or subtest without a single assertion.
+type WeakTest struct {
+	File     string `json:"file"`
+	Name     string `json:"name"` // Subtests are named by their full path, "Parent/child/name", as go test names them
+	Kind     string `json:"kind"`
+	Line     int    `json:"line"`
+	Subtests int    `json:"subtests,omitempty"`
+}
+
+// assertions returns the value stored for sym in the assertions column: its
+// assertion count if it is a test, and NULL otherwise.
+func assertions(sym analysis.Symbol) any {
+	if !sym.Test {
+		return nil
+	}
+	return sym.AssertionCount
+}
+
+// GetWeakTests returns the indexed tests and subtests that make no
+// assertions, ordered by file and line. A test whose only assertions are in
+// its subtests is not weak; its assertion count includes theirs.
+func GetWeakTests(db *sql.DB) ([]WeakTest, error) {
+	// path walks up from each weak test, prefixing the name of the parent
+	// for as long as the topmost symbol so far is a subtest, so nested
+	// subtests get their full path and the test function ends it.
+	rows, err := db.QueryContext(context.Background(), `
+		WITH RECURSIVE path(id, parent_id, kind, name) AS (
+			SELECT id, parent_id, kind, name FROM symbols WHERE assertions = 0
+			UNION ALL
+			SELECT path.id, p.parent_id, p.kind, p.name || '/' || path.name
+			FROM path JOIN symbols p ON p.id = path.parent_id
+			WHERE path.kind = ?1
+		)
+		SELECT s.file_path, path.name, s.kind, s.line_start,
+		       (SELECT COUNT(*) FROM symbols c WHERE c.parent_id = s.id AND c.kind = ?1)
+		FROM path
+		JOIN symbols s ON s.id = path.id
+		WHERE path.parent_id IS NULL OR path.kind != ?1
+		ORDER BY s.file_path, s.line_start, s.id;
+	`, analysis.KindTest)
+	if err != nil {
+		return nil, fmt.Errorf("query weak tests: %w", err)
+	}
+	defer rows.Close()
+
+	var result []WeakTest
+	for rows.Next() {
+		var w WeakTest
+		if err := rows.Scan(&w.File, &w.Name, &w.Kind, &w.Line, &w.Subtests); err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, rows.Err()
+}
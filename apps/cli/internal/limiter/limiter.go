@@ -0,0 +1,157 @@
+// Package limiter caps how much work a scan does at once: CPU-bound parse
+// workers, requests in flight to a language server, and, across both, the
+// memory the process may hold before new work waits.
+package limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Limits configures a Manager. Zero values pick the defaults.
+type Limits struct {
+	Jobs      int    // Concurrent CPU-bound tasks (default: the number of CPUs)
+	LSP       int    // Concurrent language server requests (default: 1)
+	MaxMemory uint64 // Resident set size in bytes above which new work waits (0 = no limit)
+}
+
+// memoryPoll is how often a task waiting on memory pressure checks again.
+const memoryPoll = 50 * time.Millisecond
+
+// Manager hands out slots for CPU-bound tasks and language server requests,
+// each from its own pool, so that a slow language server never holds up
+// parsing and parsing never floods the server. When the process is over its
+// memory limit, new tasks of either kind wait until it is back under, or
+// until nothing else is running that could free memory.
+type Manager struct {
+	limits   Limits
+	cpu, lsp chan struct{}
+	running  atomic.Int64
+	usage    func() uint64 // Current resident set size; replaced in tests
+}
+
+// New returns a manager enforcing limits.
+func New(limits Limits) *Manager {
+	if limits.Jobs <= 0 {
+		limits.Jobs = runtime.NumCPU()
+	}
+	if limits.LSP <= 0 {
+		limits.LSP = 1
+	}
+	return &Manager{
+		limits: limits,
+		cpu:    make(chan struct{}, limits.Jobs),
+		lsp:    make(chan struct{}, limits.LSP),
+		usage:  residentBytes,
+	}
+}
+
+// Limits returns the limits m enforces, with defaults filled in.
+func (m *Manager) Limits() Limits {
+	return m.limits
+}
+
+// AcquireCPU waits for a CPU slot and returns the function releasing it.
+func (m *Manager) AcquireCPU(ctx context.Context) (release func(), err error) {
+	return m.acquire(ctx, m.cpu)
+}
+
+// AcquireLSP waits for a language server slot and returns the function
+// releasing it.
+func (m *Manager) AcquireLSP(ctx context.Context) (release func(), err error) {
+	return m.acquire(ctx, m.lsp)
+}
+
+func (m *Manager) acquire(ctx context.Context, pool chan struct{}) (func(), error) {
+	if err := m.waitForMemory(ctx); err != nil {
+		return nil, err
+	}
+	select {
+	case pool <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	m.running.Add(1)
+	var released atomic.Bool
+	return func() {
+		if released.CompareAndSwap(false, true) {
+			m.running.Add(-1)
+			<-pool
+		}
+	}, nil
+}
+
+// waitForMemory returns once the process is under its memory limit. With
+// no other task running there is nothing to wait for, so it returns after
+// handing memory back to the OS once, however much is still held.
+func (m *Manager) waitForMemory(ctx context.Context) error {
+	if m.limits.MaxMemory == 0 || m.usage() <= m.limits.MaxMemory {
+		return nil
+	}
+	debug.FreeOSMemory()
+	ticker := time.NewTicker(memoryPoll)
+	defer ticker.Stop()
+	for m.usage() > m.limits.MaxMemory && m.running.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+type managerKey struct{}
+
+// WithManager returns a context that carries m to the scan pipeline.
+func WithManager(ctx context.Context, m *Manager) context.Context {
+	return context.WithValue(ctx, managerKey{}, m)
+}
+
+// From returns the manager carried by ctx, or a new one with the default
+// limits.
+func From(ctx context.Context) *Manager {
+	if m, ok := ctx.Value(managerKey{}).(*Manager); ok {
+		return m
+	}
+	return New(Limits{})
+}
+
+// ParseSize parses a memory size such as "512MB", "2G", or "1073741824"
+// (bytes). Units are binary: 1K = 1024 bytes.
+func ParseSize(s string) (uint64, error) {
+	t := strings.ToUpper(strings.TrimSpace(s))
+	t = strings.TrimSuffix(strings.TrimSuffix(t, "B"), "I")
+	shift := 0
+	if n := len(t); n > 0 {
+		switch t[n-1] {
+		case 'K':
+			shift = 10
+		case 'M':
+			shift = 20
+		case 'G':
+			shift = 30
+		case 'T':
+			shift = 40
+		}
+		if shift > 0 {
+			t = t[:n-1]
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512MB or 2G)", s)
+	}
+	size := n * float64(uint64(1)<<shift)
+	if size >= 1<<64 {
+		return 0, errors.New("size out of range: " + s)
+	}
+	return uint64(size), nil
+}
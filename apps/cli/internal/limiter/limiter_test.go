@@ -0,0 +1,165 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gauge counts tasks in flight and remembers the most seen at once.
+type gauge struct {
+	now, peak atomic.Int64
+}
+
+func (g *gauge) enter() {
+	n := g.now.Add(1)
+	for {
+		peak := g.peak.Load()
+		if n <= peak || g.peak.CompareAndSwap(peak, n) {
+			return
+		}
+	}
+}
+
+func (g *gauge) leave() { g.now.Add(-1) }
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestManagerSeparatePools(t *testing.T) {
+	m := New(Limits{Jobs: 3, LSP: 2})
+	ctx := context.Background()
+	var cpu, lsp gauge
+	gate := make(chan struct{})
+
+	var wg sync.WaitGroup
+	// Fake parse jobs hold their CPU slots until the gate opens
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := m.AcquireCPU(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			cpu.enter()
+			defer cpu.leave()
+			<-gate
+		}()
+	}
+	// Fake language server requests run meanwhile
+	var lspDone sync.WaitGroup
+	for range 20 {
+		lspDone.Add(1)
+		go func() {
+			defer lspDone.Done()
+			release, err := m.AcquireLSP(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			lsp.enter()
+			defer lsp.leave()
+			time.Sleep(time.Millisecond)
+		}()
+	}
+
+	waitFor(t, "the CPU pool to fill", func() bool { return cpu.now.Load() == 3 })
+	// Every request completes while parsing holds all of its slots
+	lspDone.Wait()
+	if peak := lsp.peak.Load(); peak > 2 {
+		t.Errorf("LSP requests in flight peaked at %d, over the cap of 2", peak)
+	}
+	if n := cpu.now.Load(); n != 3 {
+		t.Errorf("expected the CPU pool at its cap of 3, got %d", n)
+	}
+	close(gate)
+	wg.Wait()
+	if peak := cpu.peak.Load(); peak != 3 {
+		t.Errorf("CPU jobs in flight peaked at %d, want the cap of 3", peak)
+	}
+}
+
+func TestManagerMemoryPressure(t *testing.T) {
+	m := New(Limits{Jobs: 4, LSP: 4, MaxMemory: 1000})
+	var usage atomic.Uint64
+	usage.Store(2000)
+	m.usage = usage.Load
+	ctx := context.Background()
+
+	// Nothing else is running, so nothing could free memory: proceed
+	hold, err := m.AcquireCPU(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := m.AcquireLSP(ctx)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		release()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("new work should wait while over the memory limit")
+	case <-time.After(3 * memoryPoll):
+	}
+
+	usage.Store(500)
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("new work should proceed once under the memory limit")
+	}
+	hold()
+
+	usage.Store(2000)
+	hold, err = m.AcquireCPU(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hold()
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := m.AcquireLSP(cancelled); err == nil {
+		t.Error("waiting on memory should stop when the context is cancelled")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]uint64{
+		"1024":   1024,
+		"512MB":  512 << 20,
+		"2G":     2 << 30,
+		"1.5gib": 3 << 29,
+		"64k":    64 << 10,
+	}
+	for in, want := range tests {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "lots", "-1G"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) should fail", in)
+		}
+	}
+}
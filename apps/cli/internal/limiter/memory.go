@@ -0,0 +1,27 @@
+package limiter
+
+import (
+	"os"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+)
+
+// residentBytes returns the resident set size of the process: from
+// /proc/self/statm where there is one, otherwise the memory the Go runtime
+// has mapped, which is close for a process that is mostly Go.
+func residentBytes() uint64 {
+	if data, err := os.ReadFile("/proc/self/statm"); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 1 {
+			if pages, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+				return pages * uint64(os.Getpagesize())
+			}
+		}
+	}
+	sample := []metrics.Sample{{Name: "/memory/classes/total:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() == metrics.KindUint64 {
+		return sample[0].Value.Uint64()
+	}
+	return 0
+}
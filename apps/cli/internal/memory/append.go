@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// appendTables maps the kinds of record that can be appended to their
+// tables and the column that records when they were last touched. Learnings
+// have no updated_at; appending counts as using them.
+var appendTables = []struct {
+	kind, table, touched string
+}{
+	{"idea", "ideas", "updated_at"},
+	{"decision", "decisions", "updated_at"},
+	{"learning", "learnings", "last_used"},
+}
+
+// AppendContent adds text to the end of an idea's, decision's, or
+// learning's content in place, after a separator line carrying the time:
+//
+//	--- 2026-03-01 14:05 UTC ---
+//
+// The record keeps its ID, tags, and links, which makes it suitable for a
+// running note such as a room's scratchpad. Its embedding is dropped so it
+// is computed again from the new content. Given kinds, only records of
+// those kinds may be appended to. It returns the record's kind. The change
+// is journaled and can be undone.
+func (m *Memory) AppendContent(id, text string, kinds ...string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("nothing to append")
+	}
+	for _, probe := range appendTables {
+		var content string
+		err := m.db.QueryRowContext(context.Background(),
+			`SELECT content FROM `+probe.table+` WHERE id = ?`, id).Scan(&content)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("look up %s: %w", probe.kind, err)
+		}
+		if len(kinds) > 0 && !slices.Contains(kinds, probe.kind) {
+			return "", fmt.Errorf("%s %s cannot be appended to here (only %s)", probe.kind, id, strings.Join(kinds, ", "))
+		}
+
+		now := time.Now().UTC()
+		content = strings.TrimRight(content, "\n") + "\n\n--- " + now.Format("2006-01-02 15:04 UTC") + " ---\n" + text
+		before := m.snapshot(probe.kind, id)
+		if _, err := m.db.ExecContext(context.Background(),
+			`UPDATE `+probe.table+` SET content = ?, `+probe.touched+` = ? WHERE id = ?`,
+			content, now.Format(time.RFC3339), id); err != nil {
+			return "", fmt.Errorf("append to %s: %w", probe.kind, err)
+		}
+		m.DeleteEmbedding(id)
+		_ = m.appendJournal(JournalOpAppend, probe.kind, id, before, m.snapshot(probe.kind, id))
+		return probe.kind, nil
+	}
+	return "", fmt.Errorf("record not found: %s", id)
+}
+
+// revertContent restores the content recorded in an append entry's before
+// snapshot.
+func (m *Memory) revertContent(kind, id, before string) error {
+	var snap recordSnapshot
+	if err := json.Unmarshal([]byte(before), &snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	var r struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(snap.Record, &r); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	for _, probe := range appendTables {
+		if probe.kind == kind {
+			if _, err := m.db.ExecContext(context.Background(),
+				`UPDATE `+probe.table+` SET content = ? WHERE id = ?`, r.Content, id); err != nil {
+				return fmt.Errorf("restore content: %w", err)
+			}
+			m.DeleteEmbedding(id)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown record kind %q", kind)
+}
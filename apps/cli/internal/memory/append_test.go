@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAppendContent(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, err := mem.AddIdea(Idea{Content: "Scratchpad for the auth room", Scope: "room", ScopePath: "auth"})
+	if err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	if err := mem.AddTag(id, "idea", "scratchpad"); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+
+	for _, text := range []string{"Token refresh races on slow networks", "Retry with backoff fixes the race"} {
+		kind, err := mem.AppendContent(id, text)
+		if err != nil {
+			t.Fatalf("AppendContent failed: %v", err)
+		}
+		if kind != "idea" {
+			t.Errorf("kind = %q, want idea", kind)
+		}
+	}
+
+	idea, err := mem.GetIdea(id)
+	if err != nil {
+		t.Fatalf("GetIdea failed: %v", err)
+	}
+	first := strings.Index(idea.Content, "Token refresh races")
+	second := strings.Index(idea.Content, "Retry with backoff")
+	if !strings.HasPrefix(idea.Content, "Scratchpad for the auth room") || first < 0 || second < first {
+		t.Errorf("appended content out of order:\n%s", idea.Content)
+	}
+	if n := strings.Count(idea.Content, "\n--- "); n != 2 {
+		t.Errorf("expected a separator per append, got %d:\n%s", n, idea.Content)
+	}
+	if tags, _ := mem.GetTags(id, "idea"); len(tags) != 1 || tags[0] != "scratchpad" {
+		t.Errorf("tags should be kept, got %v", tags)
+	}
+
+	if _, err := mem.Undo(1); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if idea, _ := mem.GetIdea(id); strings.Contains(idea.Content, "Retry with backoff") || !strings.Contains(idea.Content, "Token refresh races") {
+		t.Errorf("undo should take back only the last append:\n%s", idea.Content)
+	}
+
+	if _, err := mem.AppendContent("i_missing", "text"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("appending to a missing record should fail with not found, got %v", err)
+	}
+	decisionID, err := mem.AddDecision(Decision{Content: "Use JWT for sessions"})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := mem.AppendContent(decisionID, "text", "idea"); err == nil {
+		t.Error("appending to a decision should fail when only ideas are allowed")
+	}
+}
@@ -0,0 +1,371 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BoolQuery is a boolean search over record content, such as
+// `(redis OR memcached) AND caching NOT "write through"`. Bare terms and
+// quoted phrases are joined by AND when no operator is given; NOT, or a
+// leading "-" as in -legacy, binds tightest, then AND, then OR, and parentheses group. A term matches any
+// content containing it, case-insensitively (or, with query expansion, any
+// content Matches it); a phrase matches only content containing its exact
+// word sequence. Operators must be written in capitals, so "and" and "or"
+// in running text stay terms.
+type BoolQuery struct {
+	root boolNode
+}
+
+// boolNode is a node of a parsed BoolQuery.
+type boolNode interface {
+	match(content, lower string, exp QueryExpansion) bool
+	sql(where *strings.Builder, args *[]interface{})
+	String() string
+}
+
+type (
+	boolTerm struct {
+		text   string
+		phrase bool
+	}
+	boolAnd struct{ left, right boolNode }
+	boolOr  struct{ left, right boolNode }
+	boolNot struct{ operand boolNode }
+)
+
+func (n boolTerm) match(content, lower string, exp QueryExpansion) bool {
+	if strings.Contains(lower, strings.ToLower(n.text)) {
+		return true
+	}
+	return !n.phrase && exp.Enabled() && exp.Matches(content, n.text)
+}
+
+func (n boolAnd) match(content, lower string, exp QueryExpansion) bool {
+	return n.left.match(content, lower, exp) && n.right.match(content, lower, exp)
+}
+
+func (n boolOr) match(content, lower string, exp QueryExpansion) bool {
+	return n.left.match(content, lower, exp) || n.right.match(content, lower, exp)
+}
+
+func (n boolNot) match(content, lower string, exp QueryExpansion) bool {
+	return !n.operand.match(content, lower, exp)
+}
+
+func (n boolTerm) sql(where *strings.Builder, args *[]interface{}) {
+	where.WriteString(`content LIKE ? ESCAPE '\'`)
+	*args = append(*args, "%"+likeEscaper.Replace(n.text)+"%")
+}
+
+func (n boolAnd) sql(where *strings.Builder, args *[]interface{}) {
+	binarySQL(where, args, n.left, "AND", n.right)
+}
+
+func (n boolOr) sql(where *strings.Builder, args *[]interface{}) {
+	binarySQL(where, args, n.left, "OR", n.right)
+}
+
+func (n boolNot) sql(where *strings.Builder, args *[]interface{}) {
+	where.WriteString("NOT (")
+	n.operand.sql(where, args)
+	where.WriteString(")")
+}
+
+func binarySQL(where *strings.Builder, args *[]interface{}, left boolNode, op string, right boolNode) {
+	where.WriteString("(")
+	left.sql(where, args)
+	where.WriteString(" " + op + " ")
+	right.sql(where, args)
+	where.WriteString(")")
+}
+
+func (n boolTerm) String() string {
+	if n.phrase {
+		return `"` + n.text + `"`
+	}
+	return n.text
+}
+
+func (n boolAnd) String() string { return "(" + n.left.String() + " AND " + n.right.String() + ")" }
+func (n boolOr) String() string  { return "(" + n.left.String() + " OR " + n.right.String() + ")" }
+func (n boolNot) String() string { return "NOT " + n.operand.String() }
+
+// likeEscaper escapes the LIKE wildcards of a literal.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Match reports whether content satisfies the query. exp extends terms (but
+// not phrases) to stems and synonyms when enabled.
+func (q *BoolQuery) Match(content string, exp QueryExpansion) bool {
+	return q.root.match(content, strings.ToLower(content), exp)
+}
+
+// String renders the query fully parenthesized, showing how it was parsed.
+func (q *BoolQuery) String() string {
+	return q.root.String()
+}
+
+// IsBoolQuery reports whether query uses boolean syntax: an AND, OR, or NOT
+// operator, a term negated by a leading "-", or a quoted phrase with its
+// quotes balanced. Other queries, such as "init()" or a stray quote, are
+// plain searches; parentheses alone do not make a query boolean.
+func IsBoolQuery(query string) bool {
+	if n := strings.Count(query, `"`); n > 0 && n%2 == 0 {
+		return true
+	}
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '(' || r == ')'
+	})
+	for _, word := range words {
+		if word == "AND" || word == "OR" || word == "NOT" || isNegation([]rune(word), 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNegation reports whether runes[i] is a "-" negating the term, phrase,
+// or group right after it. A dash followed by a space or another dash, as
+// in "--force", is part of a term.
+func isNegation(runes []rune, i int) bool {
+	if runes[i] != '-' || i+1 >= len(runes) {
+		return false
+	}
+	next := runes[i+1]
+	return next != '-' && next != ' ' && next != '\t' && next != '\n' && next != ')'
+}
+
+// boolToken is a lexical token of a boolean query.
+type boolToken struct {
+	kind string // "term", "phrase", "and", "or", "not", "(", ")"
+	text string
+	pos  int // 1-based column, for errors
+}
+
+// ParseBoolQuery parses a boolean query. Errors name the column where the
+// query stops making sense.
+func ParseBoolQuery(query string) (*BoolQuery, error) {
+	tokens, err := lexBoolQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	p := &boolParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		return nil, fmt.Errorf("unexpected %q at column %d", t.text, t.pos)
+	}
+	return &BoolQuery{root: root}, nil
+}
+
+func lexBoolQuery(query string) ([]boolToken, error) {
+	var tokens []boolToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, boolToken{kind: string(r), text: string(r), pos: i + 1})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated quote at column %d", i+1)
+			}
+			phrase := strings.TrimSpace(string(runes[i+1 : end]))
+			if phrase == "" {
+				return nil, fmt.Errorf("empty phrase at column %d", i+1)
+			}
+			tokens = append(tokens, boolToken{kind: "phrase", text: phrase, pos: i + 1})
+			i = end + 1
+		case isNegation(runes, i):
+			tokens = append(tokens, boolToken{kind: "not", text: "-", pos: i + 1})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !strings.ContainsRune(" \t\n()\"", runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := "term"
+			switch word {
+			case "AND":
+				kind = "and"
+			case "OR":
+				kind = "or"
+			case "NOT":
+				kind = "not"
+			}
+			tokens = append(tokens, boolToken{kind: kind, text: word, pos: start + 1})
+		}
+	}
+	return tokens, nil
+}
+
+// boolParser is a recursive-descent parser over the grammar
+//
+//	or   = and { "OR" and }
+//	and  = not { ["AND"] not }
+//	not  = "NOT" not | atom
+//	atom = term | phrase | "(" or ")"
+type boolParser struct {
+	tokens []boolToken
+	pos    int
+}
+
+func (p *boolParser) peek() (boolToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return boolToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *boolParser) parseOr() (boolNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != "or" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = boolOr{left, right}
+	}
+}
+
+func (p *boolParser) parseAnd() (boolNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == "or" || t.kind == ")" {
+			return left, nil
+		}
+		if t.kind == "and" {
+			p.pos++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = boolAnd{left, right}
+	}
+}
+
+func (p *boolParser) parseNot() (boolNode, error) {
+	if t, ok := p.peek(); ok && t.kind == "not" {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return boolNot{operand}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *boolParser) parseAtom() (boolNode, error) {
+	t, ok := p.peek()
+	if !ok {
+		prev := p.tokens[len(p.tokens)-1]
+		return nil, fmt.Errorf("query ends after %q at column %d; expected a term", prev.text, prev.pos)
+	}
+	p.pos++
+	switch t.kind {
+	case "term":
+		return boolTerm{text: t.text}, nil
+	case "phrase":
+		return boolTerm{text: t.text, phrase: true}, nil
+	case "(":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.peek()
+		if !ok || closing.kind != ")" {
+			return nil, fmt.Errorf("unclosed parenthesis at column %d", t.pos)
+		}
+		p.pos++
+		return inner, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at column %d; expected a term", t.text, t.pos)
+	}
+}
+
+// SearchLearningsBool returns the authoritative learnings matching q, most
+// confident first. Without query expansion the query is evaluated by the
+// database; with it, terms also match stems and synonyms, so candidates are
+// matched in Go.
+func (m *Memory) SearchLearningsBool(q *BoolQuery, limit int) ([]Learning, error) {
+	authVals := AuthoritativeValuesStrings()
+	where := `authority IN (` + SQLPlaceholders(len(authVals)) + `)`
+	var args []interface{}
+	for _, v := range authVals {
+		args = append(args, v)
+	}
+
+	expand := m.expansion.Enabled()
+	if !expand {
+		var content strings.Builder
+		q.root.sql(&content, &args)
+		where += " AND " + content.String()
+	}
+	sqlQuery := `
+		SELECT id, session_id, scope, scope_path, content, confidence, source, authority, promoted_from_proposal_id, created_at, last_used, use_count
+		FROM learnings
+		WHERE ` + where + `
+		ORDER BY confidence DESC, use_count DESC
+	`
+	if limit > 0 && !expand {
+		sqlQuery += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search learnings: %w", err)
+	}
+	defer rows.Close()
+
+	var learnings []Learning
+	for rows.Next() {
+		var l Learning
+		var createdAt, lastUsed string
+		if err := rows.Scan(&l.ID, &l.SessionID, &l.Scope, &l.ScopePath, &l.Content, &l.Confidence, &l.Source, &l.Authority, &l.PromotedFromProposalID, &createdAt, &lastUsed, &l.UseCount); err != nil {
+			return nil, fmt.Errorf("scan learning: %w", err)
+		}
+		if expand && !q.Match(l.Content, m.expansion) {
+			continue
+		}
+		l.CreatedAt = parseTimeOrZero(createdAt)
+		l.LastUsed = parseTimeOrZero(lastUsed)
+		learnings = append(learnings, l)
+		if expand && limit > 0 && len(learnings) >= limit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate learnings: %w", err)
+	}
+	return learnings, nil
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBoolQuery(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"redis caching", "(redis AND caching)"},
+		{"(redis OR memcached) AND caching", "((redis OR memcached) AND caching)"},
+		{"a OR b c", "(a OR (b AND c))"},
+		{`NOT legacy "write through"`, `(NOT legacy AND "write through")`},
+		{"NOT (a OR b)", "NOT (a OR b)"},
+		{"redis -memcached", "(redis AND NOT memcached)"},
+		{`-"write through" --force`, `(NOT "write through" AND --force)`},
+	}
+	for _, tt := range tests {
+		q, err := ParseBoolQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseBoolQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if got := q.String(); got != tt.want {
+			t.Errorf("ParseBoolQuery(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseBoolQueryErrors(t *testing.T) {
+	tests := map[string]string{
+		"(redis OR memcached": "unclosed parenthesis",
+		"redis OR":            "expected a term",
+		"redis )":             `unexpected ")"`,
+		`"write through`:      "unterminated quote",
+		"()":                  "expected a term",
+		"AND redis":           "expected a term",
+	}
+	for query, want := range tests {
+		_, err := ParseBoolQuery(query)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseBoolQuery(%q) error = %v, want one containing %q", query, err, want)
+		}
+	}
+}
+
+func TestIsBoolQuery(t *testing.T) {
+	for query, want := range map[string]bool{
+		"cache invalidation":   false,
+		"read and write":       false,
+		"redis OR memcached":   true,
+		`"write through"`:      true,
+		"(redis OR memcached)": true,
+		"NOT flaky":            true,
+		"redis -legacy":        true,
+		"init()":               false,
+		"(redis)":              false,
+		`"foo`:                 false,
+		"--force flag":         false,
+	} {
+		if got := IsBoolQuery(query); got != want {
+			t.Errorf("IsBoolQuery(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestSearchLearningsBool(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer mem.Close()
+
+	add := func(content string) string {
+		id, err := mem.AddLearning(Learning{Content: content, Scope: "palace", Confidence: 0.8, Authority: string(AuthorityApproved)})
+		if err != nil {
+			t.Fatalf("AddLearning: %v", err)
+		}
+		return id
+	}
+	redis := add("Use redis for caching sessions")
+	memcached := add("memcached caching is fine for fragments")
+	uncached := add("redis streams carry the events")
+	through := add("Prefer write through caches for prices")
+	reversed := add("Through the write path, nothing is cached")
+
+	search := func(query string) map[string]bool {
+		t.Helper()
+		q, err := ParseBoolQuery(query)
+		if err != nil {
+			t.Fatalf("ParseBoolQuery(%q): %v", query, err)
+		}
+		found := make(map[string]bool)
+		for _, exp := range []QueryExpansion{{}, {Stemming: true}} {
+			mem.SetQueryExpansion(exp)
+			learnings, err := mem.SearchLearningsBool(q, 0)
+			if err != nil {
+				t.Fatalf("SearchLearningsBool(%q): %v", query, err)
+			}
+			for _, l := range learnings {
+				found[l.ID] = true
+			}
+		}
+		mem.SetQueryExpansion(QueryExpansion{})
+		return found
+	}
+
+	got := search("(redis OR memcached) AND caching")
+	if !got[redis] || !got[memcached] || got[uncached] || got[through] {
+		t.Errorf("OR grouping matched %v", got)
+	}
+	got = search(`"write through"`)
+	if !got[through] || got[reversed] {
+		t.Errorf("phrase should match only the exact sequence, matched %v", got)
+	}
+}
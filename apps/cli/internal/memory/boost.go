@@ -0,0 +1,62 @@
+package memory
+
+import "sort"
+
+// PinnedTag marks a record as pinned. Pinned records are boosted when
+// records are ranked for a topic, so they surface ahead of closer matches.
+const PinnedTag = "pinned"
+
+// RelevanceBoost multiplies the relevance of decisions and pinned records
+// when records are ranked for a topic. A factor of 1.0 has no effect; zero
+// is treated as 1.0. Both apply to a pinned decision.
+type RelevanceBoost struct {
+	Decision float64
+	Pinned   float64
+}
+
+// RankedRecord is a record ranked by RankRecords.
+type RankedRecord struct {
+	ID      string
+	Kind    string
+	Content string
+	Pinned  bool
+	Match   float64 // Term-match score before boosts
+	Score   float64 // Match with boosts applied
+}
+
+// Factor returns the multiplier for a record of kind, pinned or not.
+func (b RelevanceBoost) Factor(kind string, pinned bool) float64 {
+	factor := 1.0
+	if kind == string(RecordKindDecision) && b.Decision > 0 {
+		factor *= b.Decision
+	}
+	if pinned && b.Pinned > 0 {
+		factor *= b.Pinned
+	}
+	return factor
+}
+
+// RankRecords scores records against query with the configured query
+// expansion, applies boost, and sorts them from most to least relevant.
+// Records with equal scores keep their order. Pinned is filled in from the
+// records' tags.
+func (m *Memory) RankRecords(query string, records []RankedRecord, boost RelevanceBoost) error {
+	for i := range records {
+		r := &records[i]
+		tags, err := m.GetTags(r.ID, r.Kind)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if tag == PinnedTag {
+				r.Pinned = true
+			}
+		}
+		r.Match = m.expansion.MatchScore(r.Content, query)
+		r.Score = r.Match * boost.Factor(r.Kind, r.Pinned)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Score > records[j].Score
+	})
+	return nil
+}
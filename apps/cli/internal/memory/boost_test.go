@@ -0,0 +1,56 @@
+package memory
+
+import "testing"
+
+func TestRankRecordsDecisionBoost(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	records := func() []RankedRecord {
+		return []RankedRecord{
+			{ID: "i_1", Kind: "idea", Content: "Cache the rendered pages"},             // 1 of 4 words
+			{ID: "d_1", Kind: "decision", Content: "Cache entries use versioned keys"}, // 1 of 5 words
+		}
+	}
+
+	unboosted := records()
+	if err := mem.RankRecords("cache", unboosted, RelevanceBoost{Decision: 1.0}); err != nil {
+		t.Fatalf("RankRecords failed: %v", err)
+	}
+	if unboosted[0].ID != "i_1" || unboosted[0].Match <= unboosted[1].Match {
+		t.Fatalf("expected the idea to have the higher raw match, got %+v", unboosted)
+	}
+
+	boosted := records()
+	if err := mem.RankRecords("cache", boosted, RelevanceBoost{Decision: 1.5}); err != nil {
+		t.Fatalf("RankRecords failed: %v", err)
+	}
+	if boosted[0].ID != "d_1" {
+		t.Errorf("expected the boosted decision to rank first, got %+v", boosted)
+	}
+}
+
+func TestRankRecordsPinnedBoost(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	if err := mem.SetTags("l_2", "learning", []string{PinnedTag}); err != nil {
+		t.Fatalf("SetTags failed: %v", err)
+	}
+	records := []RankedRecord{
+		{ID: "l_1", Kind: "learning", Content: "Cache misses spike on deploy"},
+		{ID: "l_2", Kind: "learning", Content: "Never bypass the cache layer from handlers"},
+	}
+	if err := mem.RankRecords("cache", records, RelevanceBoost{Pinned: 2.0}); err != nil {
+		t.Fatalf("RankRecords failed: %v", err)
+	}
+	if records[0].ID != "l_2" || !records[0].Pinned {
+		t.Errorf("expected the pinned learning to rank first, got %+v", records)
+	}
+}
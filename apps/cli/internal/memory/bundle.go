@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// BundleVersion is the version of the bundle layout written by WriteBundle.
+const BundleVersion = 1
+
+// bundleKind identifies a memory bundle.
+const bundleKind = "palace/memory-bundle"
+
+// Bundle is a self-contained copy of one investigation: the records carrying
+// a tag, the links among them and to code, and the code they are anchored
+// to as it was when the bundle was made, so that it reads the same in a
+// workspace without that code.
+type Bundle struct {
+	Kind      string              `json:"kind"`
+	Version   int                 `json:"version"`
+	Tag       string              `json:"tag"`
+	CreatedAt time.Time           `json:"createdAt"`
+	Decisions []Decision          `json:"decisions"`
+	Learnings []Learning          `json:"learnings"`
+	Ideas     []Idea              `json:"ideas"`
+	Tags      map[string][]string `json:"tags"`  // Record ID -> all of its tags
+	Links     []Link              `json:"links"` // Between bundled records, or from them to code and URLs
+	Code      []BundleCode        `json:"code"`  // One per anchor; see Anchors
+}
+
+// BundleCode is the code behind one anchor of a bundle: the lines it
+// points at and the symbols defined there, with their signatures.
+type BundleCode struct {
+	Anchor    string         `json:"anchor"` // "auth/jwt.go" or "auth/jwt.go:15-45"
+	File      string         `json:"file"`
+	StartLine int            `json:"startLine,omitempty"`
+	EndLine   int            `json:"endLine,omitempty"`
+	Excerpt   string         `json:"excerpt,omitempty"`
+	Symbols   []BundleSymbol `json:"symbols,omitempty"`
+	Missing   bool           `json:"missing,omitempty"` // The file no longer exists
+}
+
+// BundleSymbol is a symbol defined within a bundled anchor.
+type BundleSymbol struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Line      int    `json:"line"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// Records returns the number of records in b.
+func (b *Bundle) Records() int {
+	return len(b.Decisions) + len(b.Learnings) + len(b.Ideas)
+}
+
+// Anchors returns the code b's records are anchored to, sorted: the code
+// targets of their links, and the files of file-scoped records.
+func (b *Bundle) Anchors() []string {
+	seen := make(map[string]bool)
+	for _, l := range b.Links {
+		if l.TargetKind == TargetKindCode {
+			seen[l.TargetID] = true
+		}
+	}
+	for _, d := range b.Decisions {
+		if d.Scope == string(ScopeFile) && d.ScopePath != "" {
+			seen[d.ScopePath] = true
+		}
+	}
+	for _, l := range b.Learnings {
+		if l.Scope == string(ScopeFile) && l.ScopePath != "" {
+			seen[l.ScopePath] = true
+		}
+	}
+	for _, i := range b.Ideas {
+		if i.Scope == string(ScopeFile) && i.ScopePath != "" {
+			seen[i.ScopePath] = true
+		}
+	}
+	anchors := make([]string, 0, len(seen))
+	for a := range seen {
+		anchors = append(anchors, a)
+	}
+	sort.Strings(anchors)
+	return anchors
+}
+
+// Bundle collects the records tagged tag, with all their tags and the links
+// among them and from them to code and URLs. Links to records outside the
+// bundle are left out, as they would dangle wherever the bundle is opened.
+// The code context is filled in by the caller, which has the code index.
+func (m *Memory) Bundle(tag string) (*Bundle, error) {
+	tag = normalizeTag(tag)
+	if tag == "" {
+		return nil, errors.New("bundle tag is required")
+	}
+	rows, err := m.db.QueryContext(context.Background(),
+		`SELECT record_id, record_kind FROM record_tags WHERE tag = ? ORDER BY record_id`, tag)
+	if err != nil {
+		return nil, fmt.Errorf("query tagged records: %w", err)
+	}
+	type tagged struct{ id, kind string }
+	var records []tagged
+	for rows.Next() {
+		var r tagged
+		if err := rows.Scan(&r.id, &r.kind); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan tagged record: %w", err)
+		}
+		records = append(records, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		Kind: bundleKind, Version: BundleVersion, Tag: tag, CreatedAt: time.Now().UTC(),
+		Decisions: []Decision{}, Learnings: []Learning{}, Ideas: []Idea{},
+		Tags: make(map[string][]string), Links: []Link{}, Code: []BundleCode{},
+	}
+	inBundle := make(map[string]bool)
+	for _, r := range records {
+		var err error
+		switch r.kind {
+		case "decision":
+			var d *Decision
+			if d, err = m.GetDecision(r.id); err == nil {
+				b.Decisions = append(b.Decisions, *d)
+			}
+		case "learning":
+			var l *Learning
+			if l, err = m.GetLearning(r.id); err == nil {
+				b.Learnings = append(b.Learnings, *l)
+			}
+		case "idea":
+			var i *Idea
+			if i, err = m.GetIdea(r.id); err == nil {
+				b.Ideas = append(b.Ideas, *i)
+			}
+		default:
+			continue
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			continue // A tag left behind by a deleted record
+		}
+		if err != nil {
+			return nil, err
+		}
+		inBundle[r.id] = true
+		if b.Tags[r.id], err = m.GetTags(r.id, r.kind); err != nil {
+			return nil, err
+		}
+	}
+
+	for id := range inBundle {
+		links, err := m.GetLinksForSource(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range links {
+			if inBundle[l.TargetID] || l.TargetKind == TargetKindCode || l.TargetKind == TargetKindURL {
+				b.Links = append(b.Links, l)
+			}
+		}
+	}
+	sort.Slice(b.Links, func(i, j int) bool { return b.Links[i].ID < b.Links[j].ID })
+	return b, nil
+}
+
+// WriteBundle writes b to w as indented JSON.
+func WriteBundle(w io.Writer, b *Bundle) error {
+	out := *b
+	out.Kind, out.Version = bundleKind, BundleVersion
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&out)
+}
+
+// ReadBundle reads a bundle written by WriteBundle. Bundles of a newer
+// version than this build understands are rejected rather than misread.
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	var b Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("decode bundle: %w", err)
+	}
+	if b.Kind != bundleKind {
+		return nil, errors.New("not a memory bundle")
+	}
+	if b.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than supported version %d", b.Version, BundleVersion)
+	}
+	return &b, nil
+}
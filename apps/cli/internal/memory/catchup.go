@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// lastSessionMarker names the recall marker advanced by CatchUp.
+const lastSessionMarker = "last_session"
+
+// CatchUp lists the records created or changed since the previous catch-up.
+type CatchUp struct {
+	Since     time.Time  `json:"since,omitempty"` // Zero on the first catch-up
+	Ideas     []Idea     `json:"ideas"`
+	Decisions []Decision `json:"decisions"`
+	Learnings []Learning `json:"learnings"`
+}
+
+// Total returns the number of records listed.
+func (c *CatchUp) Total() int {
+	return len(c.Ideas) + len(c.Decisions) + len(c.Learnings)
+}
+
+// LastSession returns when CatchUp last ran, or the zero time if it never has.
+func (m *Memory) LastSession() (time.Time, error) {
+	var seen string
+	err := m.db.QueryRowContext(context.Background(), `SELECT seen_at FROM recall_markers WHERE name = ?`, lastSessionMarker).Scan(&seen)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read last session: %w", err)
+	}
+	return parseTimeOrZero(seen), nil
+}
+
+// MarkSession records t as the time of the last session.
+func (m *Memory) MarkSession(t time.Time) error {
+	_, err := m.db.ExecContext(context.Background(), `
+		INSERT INTO recall_markers (name, seen_at) VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET seen_at = excluded.seen_at
+	`, lastSessionMarker, t.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("mark session: %w", err)
+	}
+	return nil
+}
+
+// CatchUp returns all the records created or updated since the last
+// session, newest first, then advances the marker so the next catch-up
+// starts from now. Only authoritative decisions and learnings are listed,
+// as in recall, and archived ideas are left out. Timestamps are stored to
+// the second, so a record written in the same second as the previous
+// catch-up is listed again rather than missed.
+func (m *Memory) CatchUp() (*CatchUp, error) {
+	since, err := m.LastSession()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	c := &CatchUp{Since: since, Ideas: []Idea{}, Decisions: []Decision{}, Learnings: []Learning{}}
+	authVals := AuthoritativeValuesStrings()
+	authArgs := make([]interface{}, len(authVals))
+	for i, v := range authVals {
+		authArgs[i] = v
+	}
+
+	ideaIDs, err := m.changedSince(`SELECT id, created_at, COALESCE(updated_at, '') FROM ideas WHERE status != ?`, since, IdeaStatusArchived)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ideaIDs {
+		idea, err := m.GetIdea(id)
+		if err != nil {
+			return nil, err
+		}
+		c.Ideas = append(c.Ideas, *idea)
+	}
+
+	decisionIDs, err := m.changedSince(`SELECT id, created_at, COALESCE(updated_at, '') FROM decisions WHERE authority IN (`+SQLPlaceholders(len(authVals))+`)`, since, authArgs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range decisionIDs {
+		d, err := m.GetDecision(id)
+		if err != nil {
+			return nil, err
+		}
+		c.Decisions = append(c.Decisions, *d)
+	}
+
+	// Learnings have no update time; reinforcing one is not a change to it.
+	learningIDs, err := m.changedSince(`SELECT id, created_at, '' FROM learnings WHERE authority IN (`+SQLPlaceholders(len(authVals))+`)`, since, authArgs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range learningIDs {
+		l, err := m.GetLearning(id)
+		if err != nil {
+			return nil, err
+		}
+		c.Learnings = append(c.Learnings, *l)
+	}
+
+	if err := m.MarkSession(now); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// changedSince runs query, which selects id, created_at, and updated_at, and
+// returns the IDs of the rows created or updated at or after since, most
+// recently changed first.
+func (m *Memory) changedSince(query string, since time.Time, args ...interface{}) ([]string, error) {
+	rows, err := m.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query changes: %w", err)
+	}
+	defer rows.Close()
+
+	type change struct {
+		id string
+		at time.Time
+	}
+	since = since.Truncate(time.Second)
+	var changes []change
+	for rows.Next() {
+		var id, createdAt, updatedAt string
+		if err := rows.Scan(&id, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan change: %w", err)
+		}
+		at := parseTimeOrZero(createdAt)
+		if u := parseTimeOrZero(updatedAt); u.After(at) {
+			at = u
+		}
+		if !at.Before(since) {
+			changes = append(changes, change{id, at})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].at.After(changes[j].at) })
+	ids := make([]string, len(changes))
+	for i, ch := range changes {
+		ids[i] = ch.id
+	}
+	return ids, nil
+}
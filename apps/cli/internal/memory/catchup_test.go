@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCatchUp(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	old := time.Now().Add(-time.Hour)
+	if _, err := mem.AddIdea(Idea{Content: "Cache rendered pages on disk", CreatedAt: old, UpdatedAt: old}); err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	if _, err := mem.AddDecision(Decision{Content: "Use SQLite for the index", Authority: string(AuthorityApproved), CreatedAt: old, UpdatedAt: old}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+
+	first, err := mem.CatchUp()
+	if err != nil {
+		t.Fatalf("CatchUp failed: %v", err)
+	}
+	if !first.Since.IsZero() {
+		t.Errorf("first catch-up Since = %v, want zero", first.Since)
+	}
+	if first.Total() != 2 {
+		t.Errorf("first catch-up listed %d records, want 2", first.Total())
+	}
+
+	// Move the marker back a minute, as if the last session ended then.
+	if err := mem.MarkSession(time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("MarkSession failed: %v", err)
+	}
+	newID, err := mem.AddIdea(Idea{Content: "Batch webhook deliveries"})
+	if err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+
+	second, err := mem.CatchUp()
+	if err != nil {
+		t.Fatalf("CatchUp failed: %v", err)
+	}
+	if second.Total() != 1 || len(second.Ideas) != 1 || second.Ideas[0].ID != newID {
+		t.Fatalf("second catch-up = %+v, want only idea %s", second, newID)
+	}
+
+	last, err := mem.LastSession()
+	if err != nil {
+		t.Fatalf("LastSession failed: %v", err)
+	}
+	if time.Since(last) > time.Minute {
+		t.Errorf("LastSession = %v, want the marker advanced to now", last)
+	}
+}
@@ -181,9 +181,10 @@ func ExtractTags(text string) []string {
 }
 
 // ClassifyAndStore classifies text and stores it as the appropriate record type.
+// Records classified with low confidence are flagged for review.
 // Returns the record ID, kind, classification, and any error.
 func (m *Memory) ClassifyAndStore(text, source, sessionID string) (string, RecordKind, Classification, error) {
-	classification := Classify(text)
+	classification := m.Classify(text)
 	tags := ExtractTags(text)
 
 	var id string
@@ -224,5 +225,8 @@ func (m *Memory) ClassifyAndStore(text, source, sessionID string) (string, Recor
 		}
 	}
 
+	if err == nil {
+		_, err = m.FlagForReview(id, classification)
+	}
 	return id, classification.Kind, classification, err
 }
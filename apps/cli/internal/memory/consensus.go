@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DecisionConsensus is the current decision on one topic, with the earlier
+// decisions on it that it outweighs.
+type DecisionConsensus struct {
+	Topic   string     `json:"topic"`   // The words the topic's decisions share
+	Current Decision   `json:"current"` // Active over superseded or reversed, then newest
+	Earlier []Decision `json:"earlier"` // Newest first
+}
+
+// consensusOverlap is the share of the shorter decision's topic words that
+// two decisions must have in common to be on the same topic.
+const consensusOverlap = 0.5
+
+// DecisionConsensus groups decisions by topic and picks the current one of
+// each. Two decisions share a topic when one supersedes the other or when
+// they have at least two topic words (stemmed words of four letters or
+// more) in common, making up at least half of the shorter one's; topics are
+// the connected groups. Groups are ordered by their current decision,
+// newest first.
+func (m *Memory) DecisionConsensus(decisions []Decision) ([]DecisionConsensus, error) {
+	n := len(decisions)
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) { parent[find(a)] = find(b) }
+
+	words := make([]map[string]bool, n)
+	byID := make(map[string]int, n)
+	for i := range decisions {
+		words[i] = contentWords(decisions[i].Content)
+		byID[decisions[i].ID] = i
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if sameTopic(words[i], words[j]) {
+				union(i, j)
+			}
+		}
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), `SELECT source_id, target_id FROM links WHERE relation = ?`, RelationSupersedes)
+	if err != nil {
+		return nil, fmt.Errorf("load supersedes links: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var source, target string
+		if err := rows.Scan(&source, &target); err != nil {
+			return nil, fmt.Errorf("scan link: %w", err)
+		}
+		a, okA := byID[source]
+		b, okB := byID[target]
+		if okA && okB {
+			union(a, b)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := 0; i < n; i++ {
+		r := find(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], i)
+	}
+
+	result := make([]DecisionConsensus, 0, len(roots))
+	for _, r := range roots {
+		members := groups[r]
+		sort.SliceStable(members, func(a, b int) bool {
+			return outweighs(&decisions[members[a]], &decisions[members[b]])
+		})
+		c := DecisionConsensus{Current: decisions[members[0]], Earlier: []Decision{}}
+		for _, i := range members[1:] {
+			c.Earlier = append(c.Earlier, decisions[i])
+		}
+		sort.SliceStable(c.Earlier, func(a, b int) bool { return c.Earlier[a].CreatedAt.After(c.Earlier[b].CreatedAt) })
+		memberWords := make([]map[string]bool, len(members))
+		for k, i := range members {
+			memberWords[k] = words[i]
+		}
+		c.Topic = topicLabel(memberWords)
+		result = append(result, c)
+	}
+	sort.SliceStable(result, func(a, b int) bool { return result[a].Current.CreatedAt.After(result[b].Current.CreatedAt) })
+	return result, nil
+}
+
+// sameTopic reports whether two decisions' topic words overlap enough for
+// them to be about the same thing.
+func sameTopic(a, b map[string]bool) bool {
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	shorter := min(len(a), len(b))
+	return shared >= 2 && float64(shared) >= consensusOverlap*float64(shorter)
+}
+
+// outweighs reports whether decision a should be current over b: an active
+// decision beats a superseded or reversed one, then the newer one wins.
+func outweighs(a, b *Decision) bool {
+	aActive := a.Status == "" || a.Status == DecisionStatusActive
+	bActive := b.Status == "" || b.Status == DecisionStatusActive
+	if aActive != bActive {
+		return aActive
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
+
+// topicLabel names a topic by the (up to three) words most of its decisions
+// use, most common first.
+func topicLabel(members []map[string]bool) string {
+	counts := make(map[string]int)
+	for _, words := range members {
+		for w := range words {
+			counts[w]++
+		}
+	}
+	words := make([]string, 0, len(counts))
+	for w, c := range counts {
+		if len(members) == 1 || c > 1 {
+			words = append(words, w)
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	return strings.Join(words, " ")
+}
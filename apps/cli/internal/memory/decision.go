@@ -43,6 +43,15 @@ const (
 
 // AddDecision stores a new decision in the database.
 func (m *Memory) AddDecision(dec Decision) (string, error) {
+	id, err := m.addDecision(dec)
+	if err != nil {
+		return "", err
+	}
+	m.journalCreate(JournalOpStore, "decision", id)
+	return id, nil
+}
+
+func (m *Memory) addDecision(dec Decision) (string, error) {
 	if dec.ID == "" {
 		dec.ID = generateID("d")
 	}
@@ -79,7 +88,7 @@ func (m *Memory) AddDecision(dec Decision) (string, error) {
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, dec.ID, dec.Content, dec.Rationale, dec.Context, dec.Status, dec.Outcome, dec.OutcomeNote, outcomeAt,
 		dec.Scope, dec.ScopePath, dec.SessionID, dec.Source, dec.Authority, dec.PromotedFromProposalID,
-		dec.CreatedAt.Format(time.RFC3339), dec.UpdatedAt.Format(time.RFC3339))
+		dec.CreatedAt.UTC().Format(time.RFC3339), dec.UpdatedAt.UTC().Format(time.RFC3339))
 	if err != nil {
 		return "", fmt.Errorf("insert decision: %w", err)
 	}
@@ -356,15 +365,27 @@ func (m *Memory) UpdateDecision(id, content, rationale, contextInfo string) erro
 
 // DeleteDecision removes a decision and its associated data from the database.
 func (m *Memory) DeleteDecision(id string) error {
+	before := m.deletionSnapshot("decision", id)
+	if err := m.deleteDecision(id); err != nil {
+		return err
+	}
+	m.journalDelete(JournalOpForget, "decision", id, before)
+	return nil
+}
+
+func (m *Memory) deleteDecision(id string) error {
 	// Delete associated links first (both as source and target)
 	m.DeleteLinksForRecord(id)
 	// Delete associated tags
 	m.DeleteTagsForRecord(id, "decision")
-	// Delete associated embedding
+	// Delete associated embedding and past versions
 	m.DeleteEmbedding(id)
+	m.deleteHistory(id)
 	// Delete the decision
-	_, err := m.db.ExecContext(context.Background(), `DELETE FROM decisions WHERE id = ?`, id)
-	return err
+	if _, err := m.db.ExecContext(context.Background(), `DELETE FROM decisions WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return releaseIdempotencyKeys(m.db, id)
 }
 
 // CountDecisions returns the total number of decisions, optionally filtered.
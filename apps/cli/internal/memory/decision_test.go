@@ -521,12 +521,12 @@ func TestGetSchemaVersion(t *testing.T) {
 	mem, _ := Open(tmpDir)
 	defer mem.Close()
 
-	// After opening, schema version should be 7 (v0-v6 + v7 for authoritative views)
+	// After opening, schema version should be 16 (v0-v15 + v16 for rule confirmations)
 	version, err := mem.GetSchemaVersion()
 	if err != nil {
 		t.Fatalf("GetSchemaVersion failed: %v", err)
 	}
-	if version != 7 {
-		t.Errorf("Expected schema version 7, got %d", version)
+	if version != 16 {
+		t.Errorf("Expected schema version 16, got %d", version)
 	}
 }
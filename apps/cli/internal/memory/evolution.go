@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// EvolutionStep is one record in the history of a topic, with how it
+// differs from the record before it.
+type EvolutionStep struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"` // "decision", "learning", "idea"
+	Content   string    `json:"content"`
+	Status    string    `json:"status,omitempty"` // Decisions and ideas
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Supersedes lists the earlier steps this record replaces, by
+	// supersedes link.
+	Supersedes []string `json:"supersedes,omitempty"`
+	// Similarity is the share of content words this record has in common
+	// with the previous step, from 0 to 1. It is 0 for the first step.
+	Similarity float64 `json:"similarity"`
+	// Added and Dropped are the words this record gained and lost since the
+	// previous step, in the order they appear.
+	Added   []string `json:"added,omitempty"`
+	Dropped []string `json:"dropped,omitempty"`
+}
+
+// TopicEvolution returns the records about a topic oldest first, so the
+// way thinking about it changed can be read in order. The topic is searched
+// among decisions, learnings, and ideas, limit each; decisions that a
+// matching decision supersedes, or that supersede one, are pulled in even
+// when they do not mention the topic. Each step after the first is compared
+// with the one before it.
+func (m *Memory) TopicEvolution(topic string, limit int) ([]EvolutionStep, error) {
+	decisions, err := m.SearchDecisionsWithAuthority(topic, limit, false)
+	if err != nil {
+		return nil, err
+	}
+	learnings, err := m.SearchLearnings(topic, limit)
+	if err != nil {
+		return nil, err
+	}
+	ideas, err := m.SearchIdeas(topic, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []EvolutionStep
+	seen := make(map[string]bool)
+	addDecision := func(d *Decision) {
+		if !seen[d.ID] {
+			seen[d.ID] = true
+			steps = append(steps, EvolutionStep{ID: d.ID, Kind: "decision", Content: d.Content, Status: d.Status, CreatedAt: d.CreatedAt})
+		}
+	}
+	for i := range decisions {
+		addDecision(&decisions[i])
+	}
+	for i := range learnings {
+		seen[learnings[i].ID] = true
+		steps = append(steps, EvolutionStep{ID: learnings[i].ID, Kind: "learning", Content: learnings[i].Content, CreatedAt: learnings[i].CreatedAt})
+	}
+	for i := range ideas {
+		seen[ideas[i].ID] = true
+		steps = append(steps, EvolutionStep{ID: ideas[i].ID, Kind: "idea", Content: ideas[i].Content, Status: ideas[i].Status, CreatedAt: ideas[i].CreatedAt})
+	}
+
+	// Follow supersedes links out of the matched decisions, transitively,
+	// so a chain is shown whole.
+	supersedes := make(map[string][]string) // Source -> targets
+	for i := 0; i < len(steps); i++ {
+		if steps[i].Kind != "decision" {
+			continue
+		}
+		out, err := m.GetLinksForSource(steps[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		in, err := m.GetLinksForTarget(steps[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range append(out, in...) {
+			if l.Relation != RelationSupersedes {
+				continue
+			}
+			if l.SourceID == steps[i].ID {
+				supersedes[l.SourceID] = appendUnique(supersedes[l.SourceID], l.TargetID)
+			}
+			other := l.TargetID
+			if other == steps[i].ID {
+				other = l.SourceID
+			}
+			if seen[other] {
+				continue
+			}
+			if d, err := m.GetDecision(other); err == nil {
+				addDecision(d)
+			}
+		}
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		if !steps[i].CreatedAt.Equal(steps[j].CreatedAt) {
+			return steps[i].CreatedAt.Before(steps[j].CreatedAt)
+		}
+		return steps[i].ID < steps[j].ID
+	})
+	earlier := make(map[string]bool)
+	for i := range steps {
+		s := &steps[i]
+		for _, target := range supersedes[s.ID] {
+			if earlier[target] {
+				s.Supersedes = append(s.Supersedes, target)
+			}
+		}
+		earlier[s.ID] = true
+		if i > 0 {
+			s.Similarity, s.Added, s.Dropped = compareContent(steps[i-1].Content, s.Content)
+		}
+	}
+	return steps, nil
+}
+
+// compareContent compares the content words of two versions of a thought:
+// the Jaccard similarity of their stemmed words, and the words only the new
+// or only the old version has.
+func compareContent(oldContent, newContent string) (similarity float64, added, dropped []string) {
+	oldWords, newWords := contentWords(oldContent), contentWords(newContent)
+	shared := 0
+	for w := range newWords {
+		if oldWords[w] {
+			shared++
+		}
+	}
+	if union := len(oldWords) + len(newWords) - shared; union > 0 {
+		similarity = float64(shared) / float64(union)
+	}
+	return similarity, wordsMissingFrom(newContent, oldWords), wordsMissingFrom(oldContent, newWords)
+}
+
+// wordsMissingFrom returns the words of content, as written and without
+// repeats, whose stemmed form is not in words.
+func wordsMissingFrom(content string, words map[string]bool) []string {
+	var missing []string
+	listed := make(map[string]bool)
+	for _, tok := range searchTokens(content) {
+		for w := range contentWords(tok) {
+			if !words[w] && !listed[w] {
+				listed[w] = true
+				missing = append(missing, strings.ToLower(tok))
+			}
+		}
+	}
+	return missing
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ForgetPolicy selects the low-value records 'palace memory gc' archives:
+// records that are old, rarely or never recalled, unlinked and not pinned.
+// Decisions are never archived, whatever the policy says.
+type ForgetPolicy struct {
+	MinAge         time.Duration // Created and last recalled at least this long ago
+	MaxAccessCount int           // Recalled (or, for learnings, used) at most this many times
+	MaxConfidence  float64       // Learnings only: confidence at or below this
+	ExcludeKinds   []string      // Kinds ("idea", "learning") never archived
+}
+
+// DefaultForgetPolicy returns the policy used when palace.jsonc sets none:
+// records untouched for 90 days and never recalled, and learnings at or
+// below 30% confidence.
+func DefaultForgetPolicy() ForgetPolicy {
+	return ForgetPolicy{
+		MinAge:         90 * 24 * time.Hour,
+		MaxAccessCount: 0,
+		MaxConfidence:  0.3,
+	}
+}
+
+// ForgetCandidate is a record a forget policy would archive.
+type ForgetCandidate struct {
+	ID           string    `json:"id"`
+	Kind         string    `json:"kind"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"createdAt"`
+	LastAccessed time.Time `json:"lastAccessed,omitempty"`
+	AccessCount  int       `json:"accessCount"`
+	Confidence   float64   `json:"confidence,omitempty"`
+}
+
+// RecordAccess counts a recall of each of the records of kind with the
+// given IDs. Like journaling it is best-effort bookkeeping, so callers
+// usually ignore the error.
+func (m *Memory) RecordAccess(kind string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, id := range ids {
+		_, err := m.db.ExecContext(context.Background(), `
+			INSERT INTO record_access (record_id, record_kind, access_count, last_accessed_at)
+			VALUES (?, ?, 1, ?)
+			ON CONFLICT(record_id) DO UPDATE SET
+				access_count = access_count + 1,
+				last_accessed_at = excluded.last_accessed_at
+		`, id, kind, now)
+		if err != nil {
+			return fmt.Errorf("record access: %w", err)
+		}
+	}
+	return nil
+}
+
+// ForgetCandidates lists the records the policy would archive, oldest first.
+func (m *Memory) ForgetCandidates(p ForgetPolicy) ([]ForgetCandidate, error) {
+	excluded := make(map[string]bool, len(p.ExcludeKinds))
+	for _, k := range p.ExcludeKinds {
+		excluded[k] = true
+	}
+	cutoff := time.Now().Add(-p.MinAge)
+
+	// Pinned and linked records are kept, whatever their kind.
+	const forgettable = `
+		AND r.id NOT IN (SELECT record_id FROM record_tags WHERE tag = ?)
+		AND r.id NOT IN (SELECT source_id FROM links)
+		AND r.id NOT IN (SELECT target_id FROM links)`
+
+	var candidates []ForgetCandidate
+	if !excluded["idea"] {
+		found, err := m.forgetCandidates("idea", `
+			SELECT r.id, r.content, r.created_at, COALESCE(a.access_count, 0), COALESCE(a.last_accessed_at, ''), 0
+			FROM ideas r LEFT JOIN record_access a ON a.record_id = r.id
+			WHERE r.status != ?`+forgettable, IdeaStatusArchived, PinnedTag)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+	if !excluded["learning"] {
+		found, err := m.forgetCandidates("learning", `
+			SELECT r.id, r.content, r.created_at, COALESCE(r.use_count, 0) + COALESCE(a.access_count, 0),
+				MAX(CASE WHEN r.use_count > 0 THEN r.last_used ELSE '' END, COALESCE(a.last_accessed_at, '')), r.confidence
+			FROM learnings r LEFT JOIN record_access a ON a.record_id = r.id
+			WHERE r.status = ? AND r.confidence <= ?`+forgettable, LearningStatusActive, p.MaxConfidence, PinnedTag)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+
+	kept := candidates[:0]
+	for _, c := range candidates {
+		if c.AccessCount > p.MaxAccessCount || c.CreatedAt.After(cutoff) || c.LastAccessed.After(cutoff) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].CreatedAt.Before(kept[j].CreatedAt) })
+	return kept, nil
+}
+
+func (m *Memory) forgetCandidates(kind, query string, args ...interface{}) ([]ForgetCandidate, error) {
+	rows, err := m.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query %s candidates: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var candidates []ForgetCandidate
+	for rows.Next() {
+		c := ForgetCandidate{Kind: kind}
+		var createdAt, lastAccessed string
+		if err := rows.Scan(&c.ID, &c.Content, &createdAt, &c.AccessCount, &lastAccessed, &c.Confidence); err != nil {
+			return nil, fmt.Errorf("scan %s candidate: %w", kind, err)
+		}
+		c.CreatedAt = parseTimeOrZero(createdAt)
+		c.LastAccessed = parseTimeOrZero(lastAccessed)
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// ArchiveForgotten archives the candidates, a soft delete: archived ideas are
+// hidden from recall unless asked for by status, and archived learnings drop
+// out of the active lifecycle. Each archive is journaled and can be reverted
+// with Undo. It returns how many records were archived.
+func (m *Memory) ArchiveForgotten(candidates []ForgetCandidate) (int, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	archived := 0
+	for _, c := range candidates {
+		var query string
+		var args []interface{}
+		switch c.Kind {
+		case "idea":
+			query = `UPDATE ideas SET status = ?, updated_at = ? WHERE id = ? AND status != ?`
+			args = []interface{}{IdeaStatusArchived, now, c.ID, IdeaStatusArchived}
+		case "learning":
+			query = `UPDATE learnings SET status = ?, archived_at = ? WHERE id = ? AND status = ?`
+			args = []interface{}{LearningStatusArchived, now, c.ID, LearningStatusActive}
+		default:
+			return archived, fmt.Errorf("cannot archive a %s", c.Kind)
+		}
+		before := m.snapshot(c.Kind, c.ID)
+		res, err := m.db.ExecContext(context.Background(), query, args...)
+		if err != nil {
+			return archived, fmt.Errorf("archive %s %s: %w", c.Kind, c.ID, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			archived++
+			_ = m.appendJournal(JournalOpArchive, c.Kind, c.ID, before, m.snapshot(c.Kind, c.ID))
+		}
+	}
+	return archived, nil
+}
+
+// revertArchive brings back an archived record. An idea gets back the status
+// and update time in its snapshot; a learning, whose snapshot holds no
+// status, becomes active again, the only status a policy archives it from.
+func (m *Memory) revertArchive(kind, id, before string) error {
+	switch kind {
+	case "idea":
+		var snap recordSnapshot
+		if err := json.Unmarshal([]byte(before), &snap); err != nil {
+			return fmt.Errorf("decode snapshot: %w", err)
+		}
+		var r struct {
+			Status    string    `json:"status"`
+			UpdatedAt time.Time `json:"updatedAt"`
+		}
+		if err := json.Unmarshal(snap.Record, &r); err != nil {
+			return fmt.Errorf("decode snapshot: %w", err)
+		}
+		_, err := m.db.ExecContext(context.Background(), `UPDATE ideas SET status = ?, updated_at = ? WHERE id = ?`,
+			r.Status, r.UpdatedAt.Format(time.RFC3339), id)
+		return err
+	case "learning":
+		_, err := m.db.ExecContext(context.Background(), `UPDATE learnings SET status = ?, archived_at = '' WHERE id = ?`,
+			LearningStatusActive, id)
+		return err
+	default:
+		return fmt.Errorf("cannot restore an archived %s", kind)
+	}
+}
+
+// ApplyForgetPolicy archives the records the policy selects and returns them.
+func (m *Memory) ApplyForgetPolicy(p ForgetPolicy) ([]ForgetCandidate, error) {
+	candidates, err := m.ForgetCandidates(p)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.ArchiveForgotten(candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyForgetPolicy(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	old := time.Now().AddDate(0, 0, -200)
+	staleID, err := mem.AddIdea(Idea{Content: "Cache rendered pages on disk", CreatedAt: old})
+	if err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	recalledID, err := mem.AddIdea(Idea{Content: "Batch webhook deliveries", CreatedAt: old})
+	if err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	if err := mem.RecordAccess("idea", recalledID); err != nil {
+		t.Fatalf("RecordAccess failed: %v", err)
+	}
+	pinnedID, err := mem.AddIdea(Idea{Content: "Offer a self-hosted edition", CreatedAt: old})
+	if err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	if err := mem.AddTag(pinnedID, "idea", PinnedTag); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+	decisionID, err := mem.AddDecision(Decision{Content: "Use SQLite for the index", CreatedAt: old})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	weakID, err := mem.AddLearning(Learning{Content: "The build may need a clean", Confidence: 0.2, CreatedAt: old})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	if _, err := mem.AddLearning(Learning{Content: "Tests need Docker running", Confidence: 0.9, CreatedAt: old}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	policy := DefaultForgetPolicy()
+	candidates, err := mem.ForgetCandidates(policy)
+	if err != nil {
+		t.Fatalf("ForgetCandidates failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, c := range candidates {
+		got[c.ID] = c.Kind
+	}
+	if len(got) != 2 || got[staleID] != "idea" || got[weakID] != "learning" {
+		t.Fatalf("candidates = %+v, want the stale idea %s and weak learning %s", candidates, staleID, weakID)
+	}
+	if idea, _ := mem.GetIdea(staleID); idea.Status != IdeaStatusActive {
+		t.Errorf("listing candidates changed the idea's status to %q", idea.Status)
+	}
+
+	if _, err := mem.ApplyForgetPolicy(policy); err != nil {
+		t.Fatalf("ApplyForgetPolicy failed: %v", err)
+	}
+	if idea, _ := mem.GetIdea(staleID); idea.Status != IdeaStatusArchived {
+		t.Errorf("stale idea status = %q, want archived", idea.Status)
+	}
+	for _, id := range []string{recalledID, pinnedID} {
+		if idea, _ := mem.GetIdea(id); idea.Status != IdeaStatusActive {
+			t.Errorf("idea %s status = %q, want active", id, idea.Status)
+		}
+	}
+	if d, err := mem.GetDecision(decisionID); err != nil || d.Status != DecisionStatusActive {
+		t.Errorf("decision = %+v, %v; want it kept active", d, err)
+	}
+	ideas, err := mem.GetIdeas("", "", "", 0)
+	if err != nil {
+		t.Fatalf("GetIdeas failed: %v", err)
+	}
+	for _, idea := range ideas {
+		if idea.ID == staleID {
+			t.Errorf("archived idea still listed by GetIdeas")
+		}
+	}
+}
+
+func TestUndoForgetPolicy(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	old := time.Now().AddDate(0, 0, -200)
+	ideaID, err := mem.AddIdea(Idea{Content: "Cache rendered pages on disk", Status: IdeaStatusExploring, CreatedAt: old})
+	if err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	learningID, err := mem.AddLearning(Learning{Content: "The build may need a clean", Confidence: 0.2, CreatedAt: old})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	archived, err := mem.ApplyForgetPolicy(DefaultForgetPolicy())
+	if err != nil {
+		t.Fatalf("ApplyForgetPolicy failed: %v", err)
+	}
+	if len(archived) != 2 {
+		t.Fatalf("archived %+v, want the idea and the learning", archived)
+	}
+	entries, err := mem.GetJournal(2)
+	if err != nil {
+		t.Fatalf("GetJournal failed: %v", err)
+	}
+	for _, e := range entries {
+		if e.Op != JournalOpArchive || e.Before == "" || e.After == "" {
+			t.Errorf("journal entry %+v, want an archive with both snapshots", e)
+		}
+	}
+
+	if _, err := mem.Undo(2); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if idea, _ := mem.GetIdea(ideaID); idea.Status != IdeaStatusExploring {
+		t.Errorf("idea status after undo = %q, want exploring", idea.Status)
+	}
+	var status string
+	if err := mem.db.QueryRow(`SELECT status FROM learnings WHERE id = ?`, learningID).Scan(&status); err != nil {
+		t.Fatalf("query learning status: %v", err)
+	}
+	if status != LearningStatusActive {
+		t.Errorf("learning status after undo = %q, want active", status)
+	}
+}
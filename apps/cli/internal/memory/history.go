@@ -0,0 +1,324 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultHistoryLimit is the number of past versions kept per record; the
+// oldest are dropped as edits push new ones.
+const DefaultHistoryLimit = 20
+
+// RecordVersion is a past state of an idea, decision, or learning, as it
+// was before an edit replaced it.
+type RecordVersion struct {
+	Version    int        `json:"version"` // 1 for the original, counting up
+	Kind       RecordKind `json:"kind"`
+	Content    string     `json:"content"`
+	Tags       []string   `json:"tags,omitempty"`
+	ReplacedAt time.Time  `json:"replacedAt"`
+}
+
+// RecordEdit is a change to a record. Empty fields are left as they are;
+// Tags replaces all of the record's tags when not nil, so an empty non-nil
+// slice clears them.
+type RecordEdit struct {
+	Content string
+	Kind    RecordKind
+	Tags    []string
+}
+
+// EditRecord changes the content, kind, or tags of an idea, decision, or
+// learning, and pushes its prior state onto the record's history, so the
+// edit can be looked up with RecordHistory and taken back with
+// RestoreVersion. Content and tag changes keep the record's ID and are
+// journaled as an edit, in the same transaction as the change. A kind
+// change re-stores the record under the new kind, as correcting a review
+// does, and the history is carried over to it. Given kinds, only records
+// of those kinds may be edited. It returns the record's ID, which is new
+// when the kind changed.
+func (m *Memory) EditRecord(id string, edit RecordEdit, kinds ...string) (string, error) {
+	kind, content, err := m.recordKindAndContent(id)
+	if err != nil {
+		return "", err
+	}
+	if len(kinds) > 0 && !slices.Contains(kinds, kind) {
+		return "", fmt.Errorf("%s %s cannot be edited here (only %s)", kind, id, strings.Join(kinds, ", "))
+	}
+	switch edit.Kind {
+	case "", RecordKindIdea, RecordKindDecision, RecordKindLearning:
+	default:
+		return "", fmt.Errorf("invalid kind %q (use idea, decision, or learning)", edit.Kind)
+	}
+	tags, err := m.GetTags(id, kind)
+	if err != nil {
+		return "", err
+	}
+
+	newContent := strings.TrimSpace(edit.Content)
+	contentChanged := newContent != "" && newContent != content
+	var newTags []string
+	for _, t := range edit.Tags {
+		if t = normalizeTag(t); t != "" && !slices.Contains(newTags, t) {
+			newTags = append(newTags, t)
+		}
+	}
+	slices.Sort(newTags)
+	tagsChanged := edit.Tags != nil && !slices.Equal(newTags, tags)
+	kindChanged := edit.Kind != "" && string(edit.Kind) != kind
+	if !contentChanged && !tagsChanged && !kindChanged {
+		return "", errors.New("nothing to change")
+	}
+	prior := RecordVersion{Kind: RecordKind(kind), Content: content, Tags: tags}
+
+	if contentChanged || tagsChanged {
+		if !contentChanged {
+			newContent = ""
+		}
+		if !tagsChanged {
+			newTags = nil
+		} else if newTags == nil {
+			newTags = []string{}
+		}
+		if err := m.editInPlace(id, prior, newContent, newTags); err != nil {
+			return "", err
+		}
+	}
+
+	if kindChanged {
+		past, err := m.versions(id)
+		if err != nil {
+			return "", err
+		}
+		newID, err := m.reclassify(id, kind, edit.Kind)
+		if err != nil {
+			return "", err
+		}
+		// The old record took its history into the forget snapshot, so
+		// the new record gets a copy. A kind-only edit's version goes onto
+		// the new record alone, which undoing the reclassify deletes.
+		tx, err := m.db.BeginTx(context.Background(), nil)
+		if err != nil {
+			return "", fmt.Errorf("begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		if err := insertVersions(tx, newID, past); err != nil {
+			return "", err
+		}
+		if !contentChanged && !tagsChanged {
+			if err := pushVersion(tx, newID, prior); err != nil {
+				return "", err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return "", fmt.Errorf("carry over history: %w", err)
+		}
+		id = newID
+	}
+	return id, nil
+}
+
+// editInPlace pushes prior onto a record's history, replaces its content
+// unless content is empty and its tags unless tags is nil, and journals the
+// edit, all in one transaction.
+func (m *Memory) editInPlace(id string, prior RecordVersion, content string, tags []string) error {
+	kind := string(prior.Kind)
+	before := m.snapshot(kind, id)
+	tx, err := m.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := pushVersion(tx, id, prior); err != nil {
+		return err
+	}
+	if content != "" {
+		for _, probe := range appendTables {
+			if probe.kind != kind {
+				continue
+			}
+			if _, err := tx.ExecContext(context.Background(),
+				`UPDATE `+probe.table+` SET content = ?, `+probe.touched+` = ? WHERE id = ?`,
+				content, time.Now().UTC().Format(time.RFC3339), id); err != nil {
+				return fmt.Errorf("edit %s: %w", kind, err)
+			}
+		}
+		if _, err := tx.ExecContext(context.Background(), `DELETE FROM embeddings WHERE record_id = ?`, id); err != nil {
+			return fmt.Errorf("drop embedding: %w", err)
+		}
+	}
+	if tags != nil {
+		if err := setTags(tx, id, kind, tags); err != nil {
+			return err
+		}
+	}
+	seq, err := appendJournalTo(tx, JournalOpEdit, kind, id, before, "")
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit edit: %w", err)
+	}
+
+	// Snapshots read outside the transaction, so the entry's after state
+	// is filled in once the edit is committed
+	if seq > 0 {
+		_, _ = m.db.ExecContext(context.Background(),
+			`UPDATE memory_journal SET after = ? WHERE seq = ?`, m.snapshot(kind, id), seq)
+	}
+	return nil
+}
+
+// RecordHistory returns the kind of a record and its past versions, newest
+// first. The record itself holds the current version.
+func (m *Memory) RecordHistory(id string) (string, []RecordVersion, error) {
+	kind, _, err := m.recordKindAndContent(id)
+	if err != nil {
+		return "", nil, err
+	}
+	versions, err := m.versions(id)
+	if err != nil {
+		return "", nil, err
+	}
+	return kind, versions, nil
+}
+
+// versions returns the past versions of a record, newest first.
+func (m *Memory) versions(id string) ([]RecordVersion, error) {
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT version, record_kind, content, tags, replaced_at
+		FROM record_history WHERE record_id = ? ORDER BY version DESC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query history: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []RecordVersion
+	for rows.Next() {
+		var v RecordVersion
+		var recordKind, tags, replacedAt string
+		if err := rows.Scan(&v.Version, &recordKind, &v.Content, &tags, &replacedAt); err != nil {
+			return nil, fmt.Errorf("scan history: %w", err)
+		}
+		v.Kind = RecordKind(recordKind)
+		_ = json.Unmarshal([]byte(tags), &v.Tags)
+		v.ReplacedAt, _ = time.Parse(time.RFC3339Nano, replacedAt)
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// RestoreVersion makes a past version of a record current again, with its
+// content, kind, and tags. Restoring is itself an edit, so the version it
+// replaces is kept in the history too. It returns the record's ID, which is
+// new when the kind changed back.
+func (m *Memory) RestoreVersion(id string, version int) (string, error) {
+	var recordKind, content, tags string
+	err := m.db.QueryRowContext(context.Background(), `
+		SELECT record_kind, content, tags FROM record_history WHERE record_id = ? AND version = ?
+	`, id, version).Scan(&recordKind, &content, &tags)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("record %s has no version %d", id, version)
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up version: %w", err)
+	}
+	var past []string
+	_ = json.Unmarshal([]byte(tags), &past)
+	// A version without tags clears the current ones
+	edit := RecordEdit{Content: content, Kind: RecordKind(recordKind), Tags: append([]string{}, past...)}
+	return m.EditRecord(id, edit)
+}
+
+// pushVersion adds v, a record's state before an edit, to the record's
+// history as its newest version and drops the versions beyond
+// DefaultHistoryLimit.
+func pushVersion(db execer, id string, v RecordVersion) error {
+	data, err := json.Marshal(v.Tags)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO record_history (record_id, record_kind, version, content, tags, replaced_at)
+		VALUES (?, ?, (SELECT COALESCE(MAX(version), 0) + 1 FROM record_history WHERE record_id = ?), ?, ?, ?)
+	`, id, string(v.Kind), id, v.Content, string(data), time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+		return fmt.Errorf("save history: %w", err)
+	}
+	_, err = db.ExecContext(context.Background(), `
+		DELETE FROM record_history WHERE record_id = ? AND version NOT IN (
+			SELECT version FROM record_history WHERE record_id = ? ORDER BY version DESC LIMIT ?
+		)
+	`, id, id, DefaultHistoryLimit)
+	return err
+}
+
+// insertVersions adds past versions to a record's history as they are,
+// keeping their numbers, as when a deleted record is restored.
+func insertVersions(db execer, id string, versions []RecordVersion) error {
+	for _, v := range versions {
+		data, err := json.Marshal(v.Tags)
+		if err != nil {
+			return err
+		}
+		if _, err := db.ExecContext(context.Background(), `
+			INSERT OR REPLACE INTO record_history (record_id, record_kind, version, content, tags, replaced_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, id, string(v.Kind), v.Version, v.Content, string(data), v.ReplacedAt.UTC().Format(time.RFC3339Nano)); err != nil {
+			return fmt.Errorf("save history: %w", err)
+		}
+	}
+	return nil
+}
+
+// deleteHistory drops the past versions of a record that is deleted.
+func (m *Memory) deleteHistory(id string) error {
+	_, err := m.db.ExecContext(context.Background(), `DELETE FROM record_history WHERE record_id = ?`, id)
+	return err
+}
+
+// recordKindAndContent returns the kind and content of an idea, decision,
+// or learning.
+func (m *Memory) recordKindAndContent(id string) (string, string, error) {
+	for _, probe := range appendTables {
+		var content string
+		err := m.db.QueryRowContext(context.Background(),
+			`SELECT content FROM `+probe.table+` WHERE id = ?`, id).Scan(&content)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("look up %s: %w", probe.kind, err)
+		}
+		return probe.kind, content, nil
+	}
+	return "", "", fmt.Errorf("record not found: %s", id)
+}
+
+// revertEdit restores the content and tags recorded in an edit entry's
+// before snapshot and drops the history version the edit pushed.
+func (m *Memory) revertEdit(kind, id, before string) error {
+	if err := m.revertContent(kind, id, before); err != nil {
+		return err
+	}
+	var snap recordSnapshot
+	if err := json.Unmarshal([]byte(before), &snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	if err := m.SetTags(id, kind, snap.Tags); err != nil {
+		return err
+	}
+	_, err := m.db.ExecContext(context.Background(), `
+		DELETE FROM record_history WHERE record_id = ? AND version = (
+			SELECT MAX(version) FROM record_history WHERE record_id = ?
+		)
+	`, id, id)
+	return err
+}
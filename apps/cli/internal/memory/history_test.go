@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEditRecordKeepsHistory(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, err := mem.AddDecision(Decision{Content: "Use JWT for sessions", Authority: string(AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if err := mem.AddTag(id, "decision", "auth"); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+
+	if _, err := mem.EditRecord(id, RecordEdit{Content: "Use JWT for sessions, rotated daily"}); err != nil {
+		t.Fatalf("EditRecord failed: %v", err)
+	}
+	if _, err := mem.EditRecord(id, RecordEdit{Tags: []string{"auth", "Security"}}); err != nil {
+		t.Fatalf("EditRecord failed: %v", err)
+	}
+	if _, err := mem.EditRecord(id, RecordEdit{Tags: []string{"security", "auth"}}); err == nil || !strings.Contains(err.Error(), "nothing to change") {
+		t.Errorf("an edit changing nothing should fail, got %v", err)
+	}
+
+	d, _ := mem.GetDecision(id)
+	if d.Content != "Use JWT for sessions, rotated daily" {
+		t.Errorf("current content = %q, want the latest edit", d.Content)
+	}
+	kind, versions, err := mem.RecordHistory(id)
+	if err != nil {
+		t.Fatalf("RecordHistory failed: %v", err)
+	}
+	if kind != "decision" || len(versions) != 2 {
+		t.Fatalf("expected 2 past versions of a decision, got %s %+v", kind, versions)
+	}
+	if v := versions[1]; v.Version != 1 || v.Content != "Use JWT for sessions" || !slices.Equal(v.Tags, []string{"auth"}) {
+		t.Errorf("version 1 should hold the original, got %+v", v)
+	}
+	if v := versions[0]; v.Version != 2 || v.Content != "Use JWT for sessions, rotated daily" || v.Kind != RecordKindDecision {
+		t.Errorf("version 2 should hold the state before the retag, got %+v", v)
+	}
+
+	// Restoring is an edit too, so the replaced version joins the history
+	if _, err := mem.RestoreVersion(id, 1); err != nil {
+		t.Fatalf("RestoreVersion failed: %v", err)
+	}
+	d, _ = mem.GetDecision(id)
+	if d.Content != "Use JWT for sessions" {
+		t.Errorf("restored content = %q, want the original", d.Content)
+	}
+	if tags, _ := mem.GetTags(id, "decision"); !slices.Equal(tags, []string{"auth"}) {
+		t.Errorf("restored tags = %v, want [auth]", tags)
+	}
+	if _, versions, _ = mem.RecordHistory(id); len(versions) != 3 || !slices.Equal(versions[0].Tags, []string{"auth", "security"}) {
+		t.Errorf("restoring should push the replaced version, got %+v", versions)
+	}
+	if _, err := mem.RestoreVersion(id, 9); err == nil {
+		t.Error("restoring a missing version should fail")
+	}
+
+	// Undo takes back the restore along with its history entry
+	if _, err := mem.Undo(1); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if d, _ = mem.GetDecision(id); d.Content != "Use JWT for sessions, rotated daily" {
+		t.Errorf("undo should take back the restore, got %q", d.Content)
+	}
+	if _, versions, _ = mem.RecordHistory(id); len(versions) != 2 {
+		t.Errorf("undo should drop the version the restore pushed, got %d", len(versions))
+	}
+}
+
+func TestEditRecordKind(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, _ := mem.AddIdea(Idea{Content: "Cache tokens in Redis"})
+	newID, err := mem.EditRecord(id, RecordEdit{Kind: RecordKindDecision})
+	if err != nil {
+		t.Fatalf("EditRecord failed: %v", err)
+	}
+	if newID == id || !strings.HasPrefix(newID, "d_") {
+		t.Fatalf("a kind change should re-store the record as a decision, got %s", newID)
+	}
+	kind, versions, err := mem.RecordHistory(newID)
+	if err != nil {
+		t.Fatalf("RecordHistory failed: %v", err)
+	}
+	if kind != "decision" || len(versions) != 1 || versions[0].Kind != RecordKindIdea {
+		t.Errorf("the history should move to the new record, got %s %+v", kind, versions)
+	}
+
+	restoredID, err := mem.RestoreVersion(newID, 1)
+	if err != nil {
+		t.Fatalf("RestoreVersion failed: %v", err)
+	}
+	if idea, err := mem.GetIdea(restoredID); err != nil || idea.Content != "Cache tokens in Redis" {
+		t.Errorf("restoring should make the record an idea again, got %v, %v", idea, err)
+	}
+
+	if _, err := mem.EditRecord(restoredID, RecordEdit{Content: "x"}, "decision"); err == nil {
+		t.Error("editing an idea should fail when only decisions are allowed")
+	}
+	if _, err := mem.EditRecord("i_missing", RecordEdit{Content: "x"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("editing a missing record should fail with not found, got %v", err)
+	}
+}
+
+func TestEditRecordBoundsHistory(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, _ := mem.AddIdea(Idea{Content: "draft 0"})
+	for i := 1; i <= DefaultHistoryLimit+5; i++ {
+		if _, err := mem.EditRecord(id, RecordEdit{Content: "draft " + strings.Repeat("x", i)}); err != nil {
+			t.Fatalf("EditRecord failed: %v", err)
+		}
+	}
+	_, versions, _ := mem.RecordHistory(id)
+	if len(versions) != DefaultHistoryLimit {
+		t.Fatalf("expected %d versions kept, got %d", DefaultHistoryLimit, len(versions))
+	}
+	if newest := versions[0].Version; newest != DefaultHistoryLimit+5 {
+		t.Errorf("newest version = %d, want %d", newest, DefaultHistoryLimit+5)
+	}
+}
+
+func TestForgetKeepsHistoryForUndo(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, _ := mem.AddIdea(Idea{Content: "Shard by tenant"})
+	if _, err := mem.EditRecord(id, RecordEdit{Content: "Shard by tenant and region"}); err != nil {
+		t.Fatalf("EditRecord failed: %v", err)
+	}
+	entries, _ := mem.GetJournal(1)
+	if len(entries) != 1 || entries[0].Op != JournalOpEdit || !strings.Contains(entries[0].After, "and region") {
+		t.Errorf("the edit should be journaled with its after state, got %+v", entries)
+	}
+
+	if _, err := mem.Forget(id); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	if n := historyRows(t, mem, id); n != 0 {
+		t.Errorf("forgetting a record should drop its history, %d versions left", n)
+	}
+
+	if _, err := mem.Undo(1); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	_, versions, err := mem.RecordHistory(id)
+	if err != nil {
+		t.Fatalf("RecordHistory failed: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != 1 || versions[0].Content != "Shard by tenant" {
+		t.Errorf("undoing the forget should restore the history, got %+v", versions)
+	}
+}
+
+func TestUndoKindChangeDropsHistory(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, _ := mem.AddIdea(Idea{Content: "Cache tokens in Redis"})
+	newID, err := mem.EditRecord(id, RecordEdit{Kind: RecordKindDecision})
+	if err != nil {
+		t.Fatalf("EditRecord failed: %v", err)
+	}
+	if n := historyRows(t, mem, newID); n != 1 {
+		t.Fatalf("the kind change should push one version onto the new record, got %d", n)
+	}
+
+	// The reclassify is journaled as storing the decision and forgetting the idea
+	if _, err := mem.Undo(2); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if _, err := mem.GetIdea(id); err != nil {
+		t.Errorf("undo should bring the idea back: %v", err)
+	}
+	if n := historyRows(t, mem, newID); n != 0 {
+		t.Errorf("undo should leave no history under the decision's ID, got %d versions", n)
+	}
+	if n := historyRows(t, mem, id); n != 0 {
+		t.Errorf("the idea was never edited in place, yet has %d versions", n)
+	}
+}
+
+// historyRows counts the history rows stored for a record, whether or not
+// the record exists.
+func historyRows(t *testing.T, mem *Memory, id string) int {
+	t.Helper()
+	var n int
+	if err := mem.db.QueryRow(`SELECT COUNT(*) FROM record_history WHERE record_id = ?`, id).Scan(&n); err != nil {
+		t.Fatalf("count history: %v", err)
+	}
+	return n
+}
@@ -26,10 +26,31 @@ const (
 	IdeaStatusExploring   = "exploring"
 	IdeaStatusImplemented = "implemented"
 	IdeaStatusDropped     = "dropped"
+	IdeaStatusArchived    = "archived" // Set by the forget policy; hidden unless asked for
 )
 
 // AddIdea stores a new idea in the database.
 func (m *Memory) AddIdea(idea Idea) (string, error) {
+	id, err := m.addIdea(idea)
+	if err != nil {
+		return "", err
+	}
+	m.journalCreate(JournalOpStore, "idea", id)
+	return id, nil
+}
+
+func (m *Memory) addIdea(idea Idea) (string, error) {
+	id, err := insertIdea(m.db, idea)
+	if err != nil {
+		return "", err
+	}
+	// Enqueue embedding generation (non-blocking)
+	m.enqueueEmbedding(id, "idea", idea.Content)
+	return id, nil
+}
+
+// insertIdea fills in the defaults of an idea and inserts it through db.
+func insertIdea(db execer, idea Idea) (string, error) {
 	if idea.ID == "" {
 		idea.ID = generateID("i")
 	}
@@ -50,18 +71,14 @@ func (m *Memory) AddIdea(idea Idea) (string, error) {
 		idea.UpdatedAt = now
 	}
 
-	_, err := m.db.ExecContext(context.Background(), `
+	_, err := db.ExecContext(context.Background(), `
 		INSERT INTO ideas (id, content, context, status, scope, scope_path, session_id, source, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, idea.ID, idea.Content, idea.Context, idea.Status, idea.Scope, idea.ScopePath, idea.SessionID, idea.Source,
-		idea.CreatedAt.Format(time.RFC3339), idea.UpdatedAt.Format(time.RFC3339))
+		idea.CreatedAt.UTC().Format(time.RFC3339), idea.UpdatedAt.UTC().Format(time.RFC3339))
 	if err != nil {
 		return "", fmt.Errorf("insert idea: %w", err)
 	}
-
-	// Enqueue embedding generation (non-blocking)
-	m.enqueueEmbedding(idea.ID, "idea", idea.Content)
-
 	return idea.ID, nil
 }
 
@@ -93,6 +110,9 @@ func (m *Memory) GetIdeas(status, scope, scopePath string, limit int) ([]Idea, e
 	if status != "" {
 		query += ` AND status = ?`
 		args = append(args, status)
+	} else {
+		query += ` AND status != ?`
+		args = append(args, IdeaStatusArchived)
 	}
 	if scope != "" {
 		query += ` AND scope = ?`
@@ -138,10 +158,10 @@ func (m *Memory) SearchIdeas(query string, limit int) ([]Idea, error) {
 		SELECT i.id, i.content, i.context, i.status, i.scope, i.scope_path, i.session_id, i.source, i.created_at, i.updated_at
 		FROM ideas i
 		JOIN ideas_fts fts ON i.rowid = fts.rowid
-		WHERE ideas_fts MATCH ?
+		WHERE ideas_fts MATCH ? AND i.status != ?
 		ORDER BY rank
 	`
-	args := []interface{}{query}
+	args := []interface{}{query, IdeaStatusArchived}
 	if limit > 0 {
 		sqlQuery += ` LIMIT ?`
 		args = append(args, limit)
@@ -177,11 +197,11 @@ func (m *Memory) searchIdeasLike(query string, limit int) ([]Idea, error) {
 	sqlQuery := `
 		SELECT id, content, context, status, scope, scope_path, session_id, source, created_at, updated_at
 		FROM ideas
-		WHERE content LIKE ? OR context LIKE ?
+		WHERE (content LIKE ? OR context LIKE ?) AND status != ?
 		ORDER BY created_at DESC
 	`
 	pattern := "%" + query + "%"
-	args := []interface{}{pattern, pattern}
+	args := []interface{}{pattern, pattern, IdeaStatusArchived}
 	if limit > 0 {
 		sqlQuery += ` LIMIT ?`
 		args = append(args, limit)
@@ -242,15 +262,27 @@ func (m *Memory) UpdateIdea(id, content, contextInfo string) error {
 
 // DeleteIdea removes an idea and its associated data from the database.
 func (m *Memory) DeleteIdea(id string) error {
+	before := m.deletionSnapshot("idea", id)
+	if err := m.deleteIdea(id); err != nil {
+		return err
+	}
+	m.journalDelete(JournalOpForget, "idea", id, before)
+	return nil
+}
+
+func (m *Memory) deleteIdea(id string) error {
 	// Delete associated links first (both as source and target)
 	m.DeleteLinksForRecord(id)
 	// Delete associated tags
 	m.DeleteTagsForRecord(id, "idea")
-	// Delete associated embedding
+	// Delete associated embedding and past versions
 	m.DeleteEmbedding(id)
+	m.deleteHistory(id)
 	// Delete the idea
-	_, err := m.db.ExecContext(context.Background(), `DELETE FROM ideas WHERE id = ?`, id)
-	return err
+	if _, err := m.db.ExecContext(context.Background(), `DELETE FROM ideas WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return releaseIdempotencyKeys(m.db, id)
 }
 
 // CountIdeas returns the total number of ideas, optionally filtered by status.
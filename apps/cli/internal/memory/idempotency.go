@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IdempotentRecord is the record a client-supplied idempotency key resolved to.
+type IdempotentRecord struct {
+	Key        string    `json:"key"`
+	RecordID   string    `json:"recordId"`
+	RecordKind string    `json:"recordKind"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// GetIdempotencyKey returns the record stored under key, or nil if the key is unused.
+func (m *Memory) GetIdempotencyKey(key string) (*IdempotentRecord, error) {
+	var rec IdempotentRecord
+	var createdAt string
+	err := m.db.QueryRowContext(context.Background(),
+		`SELECT key, record_id, record_kind, created_at FROM idempotency_keys WHERE key = ?`, key,
+	).Scan(&rec.Key, &rec.RecordID, &rec.RecordKind, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	rec.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &rec, nil
+}
+
+// AddIdeaIdempotent stores an idea under a client-supplied idempotency key.
+// The key is claimed in the same transaction as the idea is inserted, so
+// when two stores with one key race only the first creates a record; the
+// other gets the record the key already resolves to, with created false.
+func (m *Memory) AddIdeaIdempotent(idea Idea, key string) (rec *IdempotentRecord, created bool, err error) {
+	if idea.ID == "" {
+		idea.ID = generateID("i")
+	}
+	rec, created, err = m.storeIdempotent(key, "idea", idea.ID, func(db execer) error {
+		_, err := insertIdea(db, idea)
+		return err
+	})
+	if created {
+		m.enqueueEmbedding(idea.ID, "idea", idea.Content)
+	}
+	return rec, created, err
+}
+
+// AddProposalIdempotent stores a proposal under a client-supplied
+// idempotency key, as AddIdeaIdempotent does an idea.
+func (m *Memory) AddProposalIdempotent(p Proposal, key string) (rec *IdempotentRecord, created bool, err error) {
+	if p.ID == "" {
+		p.ID = generateID("prop")
+	}
+	return m.storeIdempotent(key, "proposal", p.ID, func(db execer) error {
+		_, err := insertProposal(db, p)
+		return err
+	})
+}
+
+// storeIdempotent claims key for the record id and inserts the record in
+// one transaction. The key is claimed first, so a store losing the race
+// returns the record that won before its own insert can trip a uniqueness
+// constraint such as a proposal's dedupe key. A record that is created is
+// journaled once committed.
+func (m *Memory) storeIdempotent(key, kind, id string, insert func(db execer) error) (*IdempotentRecord, bool, error) {
+	tx, err := m.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	res, err := tx.ExecContext(context.Background(),
+		`INSERT OR IGNORE INTO idempotency_keys (key, record_id, record_kind, created_at) VALUES (?, ?, ?, ?)`,
+		key, id, kind, now.Format(time.RFC3339))
+	if err != nil {
+		return nil, false, fmt.Errorf("set idempotency key: %w", err)
+	}
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("set idempotency key: %w", err)
+	}
+	if claimed == 0 {
+		if err := tx.Rollback(); err != nil {
+			return nil, false, fmt.Errorf("roll back: %w", err)
+		}
+		existing, err := m.GetIdempotencyKey(key)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing == nil {
+			return nil, false, fmt.Errorf("idempotency key %q was released while in use", key)
+		}
+		return existing, false, nil
+	}
+	if err := insert(tx); err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("commit: %w", err)
+	}
+
+	m.journalCreate(JournalOpStore, kind, id)
+	return &IdempotentRecord{Key: key, RecordID: id, RecordKind: kind, CreatedAt: now}, true, nil
+}
+
+// releaseIdempotencyKeys drops the keys resolving to a deleted record, so a
+// retry with one of them stores the record afresh rather than returning an
+// ID that no longer exists.
+func releaseIdempotencyKeys(db execer, recordID string) error {
+	if _, err := db.ExecContext(context.Background(), `DELETE FROM idempotency_keys WHERE record_id = ?`, recordID); err != nil {
+		return fmt.Errorf("release idempotency keys: %w", err)
+	}
+	return nil
+}
+
+// moveIdempotencyKeys points the keys resolving to a record at the record
+// that replaced it, as when a proposal is approved or a record re-stored
+// under another kind.
+func moveIdempotencyKeys(db execer, fromID, toID, toKind string) error {
+	if _, err := db.ExecContext(context.Background(),
+		`UPDATE idempotency_keys SET record_id = ?, record_kind = ? WHERE record_id = ?`, toID, toKind, fromID); err != nil {
+		return fmt.Errorf("move idempotency keys: %w", err)
+	}
+	return nil
+}
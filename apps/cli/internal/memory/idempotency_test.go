@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAddIdempotentConcurrent(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	const stores = 8
+	var wg sync.WaitGroup
+	ideas := make([]*IdempotentRecord, stores)
+	proposals := make([]*IdempotentRecord, stores)
+	errs := make(chan error, 2*stores)
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < stores; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			rec, ok, err := mem.AddIdeaIdempotent(Idea{Content: "Retry-safe idea"}, "idea-key")
+			if err != nil {
+				errs <- err
+				return
+			}
+			ideas[i] = rec
+			if ok {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			// Same content, so a losing insert would trip the dedupe key
+			p := Proposal{ProposedAs: ProposedAsDecision, Content: "Pool database connections"}
+			rec, ok, err := mem.AddProposalIdempotent(p, "proposal-key")
+			if err != nil {
+				errs <- err
+				return
+			}
+			proposals[i] = rec
+			if ok {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("idempotent store failed: %v", err)
+	}
+
+	if created != 2 {
+		t.Errorf("expected one idea and one proposal created, got %d records", created)
+	}
+	for i := 1; i < stores; i++ {
+		if ideas[i].RecordID != ideas[0].RecordID || proposals[i].RecordID != proposals[0].RecordID {
+			t.Fatalf("every store should resolve to the record that won, got %+v and %+v", ideas, proposals)
+		}
+	}
+	if n, _ := mem.CountIdeas(""); n != 1 {
+		t.Errorf("expected 1 idea, got %d", n)
+	}
+	if n, _ := mem.CountProposals(""); n != 1 {
+		t.Errorf("expected 1 proposal, got %d", n)
+	}
+	if rec, err := mem.GetIdempotencyKey("idea-key"); err != nil || rec == nil || rec.RecordID != ideas[0].RecordID || rec.RecordKind != "idea" {
+		t.Errorf("the key should resolve to the stored idea, got %+v, %v", rec, err)
+	}
+}
+
+func TestIdempotencyKeyFollowsRecord(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	// Forgetting the record releases its key: a retry stores it afresh
+	first, _, err := mem.AddIdeaIdempotent(Idea{Content: "Cache rendered pages"}, "forget-key")
+	if err != nil {
+		t.Fatalf("AddIdeaIdempotent failed: %v", err)
+	}
+	if _, err := mem.Forget(first.RecordID); err != nil {
+		t.Fatalf("Forget failed: %v", err)
+	}
+	retry, created, err := mem.AddIdeaIdempotent(Idea{Content: "Cache rendered pages"}, "forget-key")
+	if err != nil {
+		t.Fatalf("AddIdeaIdempotent retry failed: %v", err)
+	}
+	if !created || retry.RecordID == first.RecordID {
+		t.Errorf("retry after forget = %+v (created %v), want a new record", retry, created)
+	}
+	if _, err := mem.GetIdea(retry.RecordID); err != nil {
+		t.Errorf("retried idea not stored: %v", err)
+	}
+
+	// Re-kinding the record moves its key to the new record
+	newID, err := mem.EditRecord(retry.RecordID, RecordEdit{Kind: RecordKindLearning})
+	if err != nil {
+		t.Fatalf("EditRecord failed: %v", err)
+	}
+	rec, err := mem.GetIdempotencyKey("forget-key")
+	if err != nil || rec == nil || rec.RecordID != newID || rec.RecordKind != "learning" {
+		t.Errorf("key after re-kind = %+v (err %v), want learning %s", rec, err, newID)
+	}
+
+	// Approving a proposal moves its key to the promoted record
+	prop, _, err := mem.AddProposalIdempotent(Proposal{ProposedAs: ProposedAsDecision, Content: "Pool database connections"}, "approve-key")
+	if err != nil {
+		t.Fatalf("AddProposalIdempotent failed: %v", err)
+	}
+	promotedID, err := mem.ApproveProposal(prop.RecordID, "reviewer", "")
+	if err != nil {
+		t.Fatalf("ApproveProposal failed: %v", err)
+	}
+	again, created, err := mem.AddProposalIdempotent(Proposal{ProposedAs: ProposedAsDecision, Content: "Pool database connections"}, "approve-key")
+	if err != nil {
+		t.Fatalf("AddProposalIdempotent retry failed: %v", err)
+	}
+	if created || again.RecordID != promotedID || again.RecordKind != "decision" {
+		t.Errorf("retry after approval = %+v (created %v), want decision %s", again, created, promotedID)
+	}
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// importHeader is the part of an import line shared by every kind: which
+// kind of record the line holds, and its tags. The rest of the line is the
+// record in its usual JSON form.
+type importHeader struct {
+	Kind string   `json:"kind"` // "decision", "learning", or "idea"
+	Tags []string `json:"tags,omitempty"`
+}
+
+// StreamImportOptions controls ImportStream.
+type StreamImportOptions struct {
+	ByContent bool // Also skip records whose ContentHash m already holds under another ID
+	Replace   bool // Overwrite a record held under the same ID instead of reporting a conflict
+	DryRun    bool // Report without writing
+	Strict    bool // Stop at the first malformed line instead of skipping it
+
+	// Progress, when set, is called after each line with the report so far.
+	Progress func(report *StreamImportReport)
+}
+
+// ImportLineError is a line ImportStream could not import.
+type ImportLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// StreamImportReport tells what ImportStream did, or would do on a dry run.
+// Records are counted rather than listed so that the report stays small
+// however large the import.
+type StreamImportReport struct {
+	Lines      int               `json:"lines"`      // Lines read, blank ones included
+	Imported   int               `json:"imported"`   // Records added
+	Replaced   int               `json:"replaced"`   // Records overwritten, with Replace
+	Duplicates int               `json:"duplicates"` // Records already held
+	Conflicts  []MergeConflict   `json:"conflicts"`  // Left for manual resolution
+	Malformed  []ImportLineError `json:"malformed"`  // Lines skipped
+}
+
+// ImportStream imports decisions, learnings, and ideas from JSON lines, one
+// record per line, reading and storing them one at a time so that memory
+// use does not grow with the size of the input. Each line is a record in
+// its JSON form with a "kind" field and optional "tags":
+//
+//	{"kind": "learning", "id": "lrn_1", "content": "...", "tags": ["db"]}
+//
+// Records are matched against m as by MergeFrom, except that with Replace a
+// record held under the same ID with different content is overwritten.
+// Malformed lines are reported and skipped; with Strict the first one ends
+// the import with an error, keeping what was imported before it.
+func (m *Memory) ImportStream(r io.Reader, opts StreamImportOptions) (*StreamImportReport, error) {
+	hashes := make(map[string]bool)
+	if opts.ByContent {
+		local, err := m.mergeRecords(true)
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range local {
+			hashes[ContentHash(rec.kind, rec.content, rec.scope, rec.scopePath)] = true
+		}
+	}
+
+	report := &StreamImportReport{Conflicts: []MergeConflict{}, Malformed: []ImportLineError{}}
+	br := bufio.NewReader(r)
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return report, fmt.Errorf("read line %d: %w", report.Lines+1, readErr)
+		}
+		if len(line) > 0 {
+			report.Lines++
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				if err := m.importLine(line, hashes, opts, report); err != nil {
+					var malformed *malformedLineError
+					if !errors.As(err, &malformed) {
+						return report, err
+					}
+					if opts.Strict {
+						return report, fmt.Errorf("line %d: %w", report.Lines, err)
+					}
+					report.Malformed = append(report.Malformed, ImportLineError{Line: report.Lines, Error: err.Error()})
+				}
+			}
+			if opts.Progress != nil {
+				opts.Progress(report)
+			}
+		}
+		if readErr != nil {
+			return report, nil
+		}
+	}
+}
+
+// malformedLineError is an import line that is not a valid record.
+type malformedLineError struct{ msg string }
+
+func (e *malformedLineError) Error() string { return e.msg }
+
+func malformed(format string, args ...any) error {
+	return &malformedLineError{msg: fmt.Sprintf(format, args...)}
+}
+
+// importLine imports the record on one line. Errors other than
+// malformedLineError come from the store.
+func (m *Memory) importLine(line []byte, hashes map[string]bool, opts StreamImportOptions, report *StreamImportReport) error {
+	var header importHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return malformed("invalid JSON: %v", err)
+	}
+
+	var id, content, scope, scopePath string
+	var add func() (string, error)      // Stores the record, returning its ID
+	var existing func() (string, error) // Content held under the record's ID, if any
+	switch header.Kind {
+	case "decision":
+		var d Decision
+		if err := json.Unmarshal(line, &d); err != nil {
+			return malformed("invalid decision: %v", err)
+		}
+		id, content, scope, scopePath = d.ID, d.Content, d.Scope, d.ScopePath
+		add = func() (string, error) { return m.AddDecision(d) }
+		existing = func() (string, error) {
+			held, err := m.GetDecision(d.ID)
+			if err != nil {
+				return "", err
+			}
+			return held.Content, nil
+		}
+	case "learning":
+		var l Learning
+		if err := json.Unmarshal(line, &l); err != nil {
+			return malformed("invalid learning: %v", err)
+		}
+		id, content, scope, scopePath = l.ID, l.Content, l.Scope, l.ScopePath
+		add = func() (string, error) { return m.AddLearning(l) }
+		existing = func() (string, error) {
+			held, err := m.GetLearning(l.ID)
+			if err != nil {
+				return "", err
+			}
+			return held.Content, nil
+		}
+	case "idea":
+		var i Idea
+		if err := json.Unmarshal(line, &i); err != nil {
+			return malformed("invalid idea: %v", err)
+		}
+		id, content, scope, scopePath = i.ID, i.Content, i.Scope, i.ScopePath
+		add = func() (string, error) { return m.AddIdea(i) }
+		existing = func() (string, error) {
+			held, err := m.GetIdea(i.ID)
+			if err != nil {
+				return "", err
+			}
+			return held.Content, nil
+		}
+	case "":
+		return malformed(`missing "kind"`)
+	default:
+		return malformed("unknown kind %q (use decision, learning, or idea)", header.Kind)
+	}
+	if content == "" {
+		return malformed(`missing "content"`)
+	}
+	if scope == "" {
+		scope = "palace"
+	}
+
+	hash := ContentHash(header.Kind, content, scope, scopePath)
+	replace := false
+	if id != "" {
+		held, err := existing()
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return err
+		case ContentHash(header.Kind, held, scope, scopePath) == hash:
+			report.Duplicates++
+			return nil
+		case !opts.Replace:
+			report.Conflicts = append(report.Conflicts, MergeConflict{ID: id, Kind: header.Kind, Local: held, Incoming: content})
+			return nil
+		default:
+			replace = true
+		}
+	}
+	if !replace && opts.ByContent && hashes[hash] {
+		report.Duplicates++
+		return nil
+	}
+	if opts.ByContent {
+		hashes[hash] = true
+	}
+
+	if !opts.DryRun {
+		if replace {
+			if err := m.forgetRecord(header.Kind, id); err != nil {
+				return err
+			}
+		}
+		stored, err := add()
+		if err != nil {
+			return err
+		}
+		if len(header.Tags) > 0 {
+			if err := m.SetTags(stored, header.Kind, header.Tags); err != nil {
+				return err
+			}
+		}
+	}
+	if replace {
+		report.Replaced++
+	} else {
+		report.Imported++
+	}
+	return nil
+}
+
+// forgetRecord deletes a decision, learning, or idea by kind, journaled so
+// that undoing a replacing import brings the old record back.
+func (m *Memory) forgetRecord(kind, id string) error {
+	switch kind {
+	case "decision":
+		return m.DeleteDecision(id)
+	case "learning":
+		return m.DeleteLearning(id)
+	default:
+		return m.DeleteIdea(id)
+	}
+}
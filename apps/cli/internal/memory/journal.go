@@ -0,0 +1,393 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JournalOp identifies the kind of memory mutation recorded in the journal.
+type JournalOp string
+
+const (
+	// JournalOpStore is recorded when an idea, decision, learning, or proposal is created.
+	JournalOpStore JournalOp = "store"
+	// JournalOpForget is recorded when an idea, decision, learning, or proposal is deleted.
+	JournalOpForget JournalOp = "forget"
+	// JournalOpLink is recorded when a link is created.
+	JournalOpLink JournalOp = "link"
+	// JournalOpUnlink is recorded when a link is deleted.
+	JournalOpUnlink JournalOp = "unlink"
+	// JournalOpRescope is recorded when a record moves to another scope.
+	JournalOpRescope JournalOp = "rescope"
+	// JournalOpAppend is recorded when content is appended to a record.
+	JournalOpAppend JournalOp = "append"
+	// JournalOpEdit is recorded when a record's content or tags are edited.
+	JournalOpEdit JournalOp = "edit"
+	// JournalOpArchive is recorded when a forget policy archives a record.
+	JournalOpArchive JournalOp = "archive"
+)
+
+// DefaultJournalLimit is the number of journal entries kept; older entries
+// are dropped as new ones are appended.
+const DefaultJournalLimit = 1000
+
+// JournalEntry is one recorded memory mutation. Before and After hold JSON
+// snapshots of the record (with its links and tags) on either side of the change.
+type JournalEntry struct {
+	Seq        int64     `json:"seq"`
+	Op         JournalOp `json:"op"`
+	RecordKind string    `json:"recordKind"` // "idea", "decision", "learning", "proposal", "link"
+	RecordID   string    `json:"recordId"`
+	Before     string    `json:"before,omitempty"`
+	After      string    `json:"after,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UndoneAt   time.Time `json:"undoneAt,omitempty"`
+}
+
+// Undone reports whether the entry has been reverted.
+func (e JournalEntry) Undone() bool {
+	return !e.UndoneAt.IsZero()
+}
+
+// recordSnapshot captures a record together with the links, tags, and
+// history that are deleted alongside it, so a forget can be fully reverted.
+// History is only captured for deletions.
+type recordSnapshot struct {
+	Record  json.RawMessage `json:"record"`
+	Links   []Link          `json:"links,omitempty"`
+	Tags    []string        `json:"tags,omitempty"`
+	History []RecordVersion `json:"history,omitempty"`
+}
+
+// snapshot serializes the current state of a record, or returns "" if it does not exist.
+func (m *Memory) snapshot(kind, id string) string {
+	return m.snapshotOf(kind, id, false)
+}
+
+// deletionSnapshot is snapshot with the record's past versions, which
+// deleting the record drops.
+func (m *Memory) deletionSnapshot(kind, id string) string {
+	return m.snapshotOf(kind, id, true)
+}
+
+func (m *Memory) snapshotOf(kind, id string, withHistory bool) string {
+	var record any
+	var err error
+	switch kind {
+	case "idea":
+		record, err = m.GetIdea(id)
+	case "decision":
+		record, err = m.GetDecision(id)
+	case "learning":
+		record, err = m.GetLearning(id)
+	case "proposal":
+		record, err = m.GetProposal(id)
+	case "link":
+		record, err = m.GetLink(id)
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return ""
+	}
+
+	snap := recordSnapshot{Record: data}
+	if kind != "link" {
+		snap.Links, _ = m.GetAllLinksFor(id)
+		snap.Tags, _ = m.GetTags(id, kind)
+	}
+	if withHistory {
+		snap.History, _ = m.versions(id)
+	}
+	out, err := json.Marshal(snap)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// journalCreate records the creation of a record. Journaling is best-effort:
+// the mutation has already succeeded and is not rolled back on failure.
+func (m *Memory) journalCreate(op JournalOp, kind, id string) {
+	_ = m.appendJournal(op, kind, id, "", m.snapshot(kind, id))
+}
+
+// journalDelete records the deletion of a record whose prior state is before.
+func (m *Memory) journalDelete(op JournalOp, kind, id, before string) {
+	if before == "" {
+		return
+	}
+	_ = m.appendJournal(op, kind, id, before, "")
+}
+
+func (m *Memory) appendJournal(op JournalOp, kind, id, before, after string) error {
+	_, err := appendJournalTo(m.db, op, kind, id, before, after)
+	return err
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx, so a write can be made part
+// of a transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// appendJournalTo appends a journal entry through db and returns its seq,
+// or 0 if the driver does not report it.
+func appendJournalTo(db execer, op JournalOp, kind, id, before, after string) (int64, error) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	res, err := db.ExecContext(context.Background(), `
+		INSERT INTO memory_journal (op, record_kind, record_id, before, after, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, string(op), kind, id, before, after, now)
+	if err != nil {
+		return 0, fmt.Errorf("append journal: %w", err)
+	}
+	seq, err := res.LastInsertId()
+	if err != nil {
+		return 0, nil
+	}
+	_, err = db.ExecContext(context.Background(), `DELETE FROM memory_journal WHERE seq <= ?`, seq-DefaultJournalLimit)
+	return seq, err
+}
+
+// GetJournal returns the most recent journal entries, newest first.
+func (m *Memory) GetJournal(limit int) ([]JournalEntry, error) {
+	query := `SELECT seq, op, record_kind, record_id, before, after, created_at, undone_at FROM memory_journal ORDER BY seq DESC`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := m.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query journal: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []JournalEntry
+	for rows.Next() {
+		var e JournalEntry
+		var op, createdAt, undoneAt string
+		if err := rows.Scan(&e.Seq, &op, &e.RecordKind, &e.RecordID, &e.Before, &e.After, &createdAt, &undoneAt); err != nil {
+			return nil, fmt.Errorf("scan journal: %w", err)
+		}
+		e.Op = JournalOp(op)
+		e.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		if undoneAt != "" {
+			e.UndoneAt, _ = time.Parse(time.RFC3339Nano, undoneAt)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Undo reverts the n most recent mutations that have not been undone yet,
+// newest first, and returns the reverted entries. Reverting is not itself
+// journaled; the entries are marked undone instead.
+func (m *Memory) Undo(n int) ([]JournalEntry, error) {
+	if n <= 0 {
+		n = 1
+	}
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT seq, op, record_kind, record_id, before, after, created_at
+		FROM memory_journal WHERE undone_at = '' ORDER BY seq DESC LIMIT ?
+	`, n)
+	if err != nil {
+		return nil, fmt.Errorf("query journal: %w", err)
+	}
+	var pending []JournalEntry
+	for rows.Next() {
+		var e JournalEntry
+		var op, createdAt string
+		if err := rows.Scan(&e.Seq, &op, &e.RecordKind, &e.RecordID, &e.Before, &e.After, &createdAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan journal: %w", err)
+		}
+		e.Op = JournalOp(op)
+		e.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		pending = append(pending, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var undone []JournalEntry
+	for _, e := range pending {
+		if err := m.revert(e); err != nil {
+			return undone, fmt.Errorf("undo #%d (%s %s %s): %w", e.Seq, e.Op, e.RecordKind, e.RecordID, err)
+		}
+		now := time.Now().UTC()
+		if _, err := m.db.ExecContext(context.Background(), `UPDATE memory_journal SET undone_at = ? WHERE seq = ?`,
+			now.Format(time.RFC3339Nano), e.Seq); err != nil {
+			return undone, fmt.Errorf("mark journal entry undone: %w", err)
+		}
+		e.UndoneAt = now
+		undone = append(undone, e)
+	}
+	return undone, nil
+}
+
+// revert applies the inverse of a journal entry without journaling it.
+func (m *Memory) revert(e JournalEntry) error {
+	switch e.Op {
+	case JournalOpStore:
+		return m.deleteRecord(e.RecordKind, e.RecordID)
+	case JournalOpLink:
+		return m.deleteLink(e.RecordID)
+	case JournalOpForget, JournalOpUnlink:
+		return m.restoreSnapshot(e.RecordKind, e.Before)
+	case JournalOpRescope:
+		return m.revertScope(e.RecordKind, e.RecordID, e.Before)
+	case JournalOpAppend:
+		return m.revertContent(e.RecordKind, e.RecordID, e.Before)
+	case JournalOpEdit:
+		return m.revertEdit(e.RecordKind, e.RecordID, e.Before)
+	case JournalOpArchive:
+		return m.revertArchive(e.RecordKind, e.RecordID, e.Before)
+	default:
+		return fmt.Errorf("unknown journal op %q", e.Op)
+	}
+}
+
+func (m *Memory) deleteRecord(kind, id string) error {
+	switch kind {
+	case "idea":
+		return m.deleteIdea(id)
+	case "decision":
+		return m.deleteDecision(id)
+	case "learning":
+		return m.deleteLearning(id)
+	case "proposal":
+		return m.deleteProposal(id)
+	default:
+		return fmt.Errorf("unknown record kind %q", kind)
+	}
+}
+
+func (m *Memory) restoreSnapshot(kind, data string) error {
+	var snap recordSnapshot
+	if err := json.Unmarshal([]byte(data), &snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	var err error
+	switch kind {
+	case "idea":
+		var r Idea
+		if err = json.Unmarshal(snap.Record, &r); err == nil {
+			_, err = m.addIdea(r)
+		}
+	case "decision":
+		var r Decision
+		if err = json.Unmarshal(snap.Record, &r); err == nil {
+			_, err = m.addDecision(r)
+		}
+	case "learning":
+		var r Learning
+		if err = json.Unmarshal(snap.Record, &r); err == nil {
+			_, err = m.addLearning(r)
+		}
+	case "proposal":
+		var r Proposal
+		if err = json.Unmarshal(snap.Record, &r); err == nil {
+			_, err = m.addProposal(r)
+		}
+	case "link":
+		var r Link
+		if err = json.Unmarshal(snap.Record, &r); err == nil {
+			_, err = m.addLink(r)
+		}
+	default:
+		return fmt.Errorf("unknown record kind %q", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, l := range snap.Links {
+		// The other end of a link may itself have been removed since
+		_, _ = m.addLink(l)
+	}
+	if len(snap.Tags) > 0 {
+		if err := m.SetTags(idFromSnapshot(snap), kind, snap.Tags); err != nil {
+			return err
+		}
+	}
+	if len(snap.History) > 0 {
+		return insertVersions(m.db, idFromSnapshot(snap), snap.History)
+	}
+	return nil
+}
+
+func idFromSnapshot(snap recordSnapshot) string {
+	var r struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(snap.Record, &r)
+	return r.ID
+}
+
+// CompactJournal drops all but the keep most recent journal entries and
+// returns how many were removed.
+func (m *Memory) CompactJournal(keep int) (int64, error) {
+	if keep < 0 {
+		keep = 0
+	}
+	res, err := m.db.ExecContext(context.Background(), `
+		DELETE FROM memory_journal WHERE seq NOT IN (
+			SELECT seq FROM memory_journal ORDER BY seq DESC LIMIT ?
+		)
+	`, keep)
+	if err != nil {
+		return 0, fmt.Errorf("compact journal: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Forget deletes an idea, decision, learning, or proposal by ID, detecting its
+// kind, and returns the kind that was removed. The deletion is journaled and
+// can be reverted with Undo.
+func (m *Memory) Forget(id string) (string, error) {
+	for _, probe := range []struct {
+		kind  string
+		table string
+	}{
+		{"idea", "ideas"},
+		{"decision", "decisions"},
+		{"learning", "learnings"},
+		{"proposal", "proposals"},
+	} {
+		var found string
+		err := m.db.QueryRowContext(context.Background(), `SELECT id FROM `+probe.table+` WHERE id = ?`, id).Scan(&found)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("look up %s: %w", probe.kind, err)
+		}
+		switch probe.kind {
+		case "idea":
+			err = m.DeleteIdea(id)
+		case "decision":
+			err = m.DeleteDecision(id)
+		case "learning":
+			err = m.DeleteLearning(id)
+		case "proposal":
+			err = m.DeleteProposal(id)
+		}
+		if err != nil {
+			return "", err
+		}
+		return probe.kind, nil
+	}
+	return "", fmt.Errorf("record not found: %s", id)
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"os"
+	"testing"
+)
+
+func TestJournalUndoForgetRestoresRecord(t *testing.T) {
+	tmpDir, _ := os.MkdirTemp("", "journal-test-*")
+	defer os.RemoveAll(tmpDir)
+	mem, _ := Open(tmpDir)
+	defer mem.Close()
+
+	ideaID, _ := mem.AddIdea(Idea{Content: "Cache permissions in Redis"})
+	decID, _ := mem.AddDecision(Decision{Content: "Use Redis for caching"})
+	mem.SetTags(ideaID, "idea", []string{"cache"})
+	if _, err := mem.AddLink(Link{SourceID: decID, SourceKind: "decision", TargetID: ideaID, TargetKind: "idea", Relation: RelationInspiredBy}); err != nil {
+		t.Fatalf("AddLink failed: %v", err)
+	}
+
+	kind, err := mem.Forget(ideaID)
+	if err != nil || kind != "idea" {
+		t.Fatalf("Forget() = %q, %v", kind, err)
+	}
+	if _, err := mem.GetIdea(ideaID); err == nil {
+		t.Fatal("idea should be deleted")
+	}
+
+	undone, err := mem.Undo(1)
+	if err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if len(undone) != 1 || undone[0].Op != JournalOpForget {
+		t.Fatalf("expected the forget to be undone, got %+v", undone)
+	}
+	idea, err := mem.GetIdea(ideaID)
+	if err != nil || idea.Content != "Cache permissions in Redis" {
+		t.Fatalf("idea not restored: %v", err)
+	}
+	if tags, _ := mem.GetTags(ideaID, "idea"); len(tags) != 1 || tags[0] != "cache" {
+		t.Errorf("tags not restored: %v", tags)
+	}
+	if links, _ := mem.GetAllLinksFor(ideaID); len(links) != 1 {
+		t.Errorf("links not restored: %v", links)
+	}
+
+	// The restore itself is not journaled; the entry is marked undone
+	entries, _ := mem.GetJournal(0)
+	if len(entries) != 4 || !entries[0].Undone() {
+		t.Errorf("expected 4 entries with the newest undone, got %+v", entries)
+	}
+}
+
+func TestJournalUndoStoreAndCompact(t *testing.T) {
+	tmpDir, _ := os.MkdirTemp("", "journal-test-*")
+	defer os.RemoveAll(tmpDir)
+	mem, _ := Open(tmpDir)
+	defer mem.Close()
+
+	first, _ := mem.AddLearning(Learning{Content: "first", Authority: string(AuthorityApproved)})
+	second, _ := mem.AddLearning(Learning{Content: "second", Authority: string(AuthorityApproved)})
+
+	if _, err := mem.Undo(2); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	for _, id := range []string{first, second} {
+		if _, err := mem.GetLearning(id); err == nil {
+			t.Errorf("learning %s should have been removed", id)
+		}
+	}
+
+	// Nothing left to undo
+	if undone, _ := mem.Undo(1); len(undone) != 0 {
+		t.Errorf("expected nothing to undo, got %+v", undone)
+	}
+
+	removed, err := mem.CompactJournal(1)
+	if err != nil || removed != 1 {
+		t.Fatalf("CompactJournal() = %d, %v", removed, err)
+	}
+	if entries, _ := mem.GetJournal(0); len(entries) != 1 {
+		t.Errorf("expected 1 entry after compaction, got %d", len(entries))
+	}
+}
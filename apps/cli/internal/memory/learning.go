@@ -16,6 +16,15 @@ func parseTimeOrZero(s string) time.Time {
 
 // AddLearning stores a new learning in the database.
 func (m *Memory) AddLearning(l Learning) (string, error) {
+	id, err := m.addLearning(l)
+	if err != nil {
+		return "", err
+	}
+	m.journalCreate(JournalOpStore, "learning", id)
+	return id, nil
+}
+
+func (m *Memory) addLearning(l Learning) (string, error) {
 	if l.ID == "" {
 		l.ID = generateID("lrn")
 	}
@@ -43,7 +52,7 @@ func (m *Memory) AddLearning(l Learning) (string, error) {
 		INSERT INTO learnings (id, session_id, scope, scope_path, content, confidence, source, authority, promoted_from_proposal_id, created_at, last_used, use_count)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, l.ID, l.SessionID, l.Scope, l.ScopePath, l.Content, l.Confidence, l.Source, l.Authority, l.PromotedFromProposalID,
-		l.CreatedAt.Format(time.RFC3339), l.LastUsed.Format(time.RFC3339), l.UseCount)
+		l.CreatedAt.UTC().Format(time.RFC3339), l.LastUsed.UTC().Format(time.RFC3339), l.UseCount)
 	if err != nil {
 		return "", fmt.Errorf("insert learning: %w", err)
 	}
@@ -147,14 +156,24 @@ func (m *Memory) SearchLearningsWithAuthority(query string, limit int, authorita
 		}
 	}
 
+	// With query expansion the substring filter is too narrow ("cache" would
+	// miss "caching"), so candidates are matched in Go after loading.
+	expand := m.expansion.Enabled()
+	contentFilter := `content LIKE ?`
+	args := []interface{}{"%" + query + "%"}
+	if expand {
+		contentFilter = `1=1`
+		args = nil
+	}
+
 	sqlQuery := `
 		SELECT id, session_id, scope, scope_path, content, confidence, source, authority, promoted_from_proposal_id, created_at, last_used, use_count
 		FROM learnings
-		WHERE content LIKE ?` + authFilter + `
+		WHERE ` + contentFilter + authFilter + `
 		ORDER BY confidence DESC, use_count DESC
 	`
-	args := append([]interface{}{"%" + query + "%"}, authArgs...)
-	if limit > 0 {
+	args = append(args, authArgs...)
+	if limit > 0 && !expand {
 		sqlQuery += ` LIMIT ?`
 		args = append(args, limit)
 	}
@@ -172,9 +191,15 @@ func (m *Memory) SearchLearningsWithAuthority(query string, limit int, authorita
 		if err := rows.Scan(&l.ID, &l.SessionID, &l.Scope, &l.ScopePath, &l.Content, &l.Confidence, &l.Source, &l.Authority, &l.PromotedFromProposalID, &createdAt, &lastUsed, &l.UseCount); err != nil {
 			return nil, fmt.Errorf("scan learning: %w", err)
 		}
+		if expand && !strings.Contains(strings.ToLower(l.Content), strings.ToLower(query)) && !m.expansion.Matches(l.Content, query) {
+			continue
+		}
 		l.CreatedAt = parseTimeOrZero(createdAt)
 		l.LastUsed = parseTimeOrZero(lastUsed)
 		learnings = append(learnings, l)
+		if expand && limit > 0 && len(learnings) >= limit {
+			break
+		}
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate learnings: %w", err)
@@ -209,8 +234,25 @@ func (m *Memory) WeakenLearning(id string) error {
 
 // DeleteLearning removes a learning from the database.
 func (m *Memory) DeleteLearning(id string) error {
-	_, err := m.db.ExecContext(context.Background(), `DELETE FROM learnings WHERE id = ?`, id)
-	return err
+	before := m.deletionSnapshot("learning", id)
+	if err := m.deleteLearning(id); err != nil {
+		return err
+	}
+	m.journalDelete(JournalOpForget, "learning", id, before)
+	return nil
+}
+
+func (m *Memory) deleteLearning(id string) error {
+	if _, err := m.db.ExecContext(context.Background(), `DELETE FROM learnings WHERE id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := m.db.ExecContext(context.Background(), `DELETE FROM learning_reviews WHERE learning_id = ?`, id); err != nil {
+		return err
+	}
+	if err := releaseIdempotencyKeys(m.db, id); err != nil {
+		return err
+	}
+	return m.deleteHistory(id)
 }
 
 // GetRelevantLearnings finds learnings relevant to a given file or query.
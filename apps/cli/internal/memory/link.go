@@ -65,6 +65,17 @@ type CodeTarget struct {
 
 // AddLink creates a new link between records.
 func (m *Memory) AddLink(link Link) (string, error) {
+	id, err := m.addLink(link)
+	if err != nil {
+		return "", err
+	}
+	m.journalCreate(JournalOpLink, "link", id)
+	return id, nil
+}
+
+// addLink inserts a link, keeping its ID and creation time when set (as when
+// a journal undo restores a removed link).
+func (m *Memory) addLink(link Link) (string, error) {
 	if link.SourceID == "" {
 		return "", fmt.Errorf("source_id is required")
 	}
@@ -87,8 +98,12 @@ func (m *Memory) AddLink(link Link) (string, error) {
 	}
 
 	// Generate ID
-	link.ID = "l_" + uuid.New().String()[:8]
-	link.CreatedAt = time.Now().UTC()
+	if link.ID == "" {
+		link.ID = "l_" + uuid.New().String()[:8]
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now().UTC()
+	}
 
 	// Handle target mtime for code links
 	targetMtime := ""
@@ -178,6 +193,15 @@ func (m *Memory) GetLinksByRelation(relation string, limit int) ([]Link, error)
 
 // DeleteLink deletes a link by ID.
 func (m *Memory) DeleteLink(id string) error {
+	before := m.snapshot("link", id)
+	if err := m.deleteLink(id); err != nil {
+		return err
+	}
+	m.journalDelete(JournalOpUnlink, "link", id, before)
+	return nil
+}
+
+func (m *Memory) deleteLink(id string) error {
 	result, err := m.db.ExecContext(context.Background(), `DELETE FROM links WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("delete link: %w", err)
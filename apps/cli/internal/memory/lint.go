@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// LintRule is a content convention records are checked against. Zero
+// fields are not checked.
+type LintRule struct {
+	Name             string
+	Kind             string         // "idea", "decision", "learning"; empty for every kind
+	Pattern          *regexp.Regexp // Content must match
+	MinLength        int            // In characters
+	MaxLength        int            // In characters
+	RequiredTags     []string
+	RequireRationale bool // Decisions only
+	Message          string
+}
+
+// LintViolation is a record that breaks a rule, with every reason it does.
+type LintViolation struct {
+	RecordID string   `json:"recordId,omitempty"` // Empty for a record not yet stored
+	Kind     string   `json:"kind"`
+	Rule     string   `json:"rule"`
+	Problems []string `json:"problems"`
+	Content  string   `json:"content"`
+}
+
+// LintRecord is what linting reads from a record.
+type LintRecord struct {
+	ID        string
+	Kind      string
+	Content   string
+	Rationale string
+	Tags      []string
+}
+
+// NewLintRule returns a rule named name for records of kind, or of every
+// kind when kind is empty, whose content must match pattern when it is not
+// empty. The length, tag, and rationale checks are set on the result.
+// Unknown kinds and invalid patterns are errors.
+func NewLintRule(name, kind, pattern string) (LintRule, error) {
+	rule := LintRule{Name: name, Kind: kind}
+	switch kind {
+	case "", "idea", "decision", "learning":
+	default:
+		return rule, fmt.Errorf("unknown kind %q", kind)
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return rule, err
+		}
+		rule.Pattern = re
+	}
+	return rule, nil
+}
+
+// LintCheck checks one record against rules and returns a violation for
+// each rule it breaks.
+func LintCheck(rules []LintRule, rec LintRecord) []LintViolation {
+	var violations []LintViolation
+	for i := range rules {
+		r := &rules[i]
+		if r.Kind != "" && r.Kind != rec.Kind {
+			continue
+		}
+		var problems []string
+		if r.Pattern != nil && !r.Pattern.MatchString(rec.Content) {
+			problems = append(problems, fmt.Sprintf("content does not match %q", r.Pattern.String()))
+		}
+		n := utf8.RuneCountInString(strings.TrimSpace(rec.Content))
+		if r.MinLength > 0 && n < r.MinLength {
+			problems = append(problems, fmt.Sprintf("content is %d characters, under the minimum of %d", n, r.MinLength))
+		}
+		if r.MaxLength > 0 && n > r.MaxLength {
+			problems = append(problems, fmt.Sprintf("content is %d characters, over the maximum of %d", n, r.MaxLength))
+		}
+		for _, tag := range r.RequiredTags {
+			if !containsTag(rec.Tags, tag) {
+				problems = append(problems, fmt.Sprintf("missing tag %q", tag))
+			}
+		}
+		if r.RequireRationale && rec.Kind == "decision" && strings.TrimSpace(rec.Rationale) == "" {
+			problems = append(problems, "no rationale recorded")
+		}
+		if len(problems) == 0 {
+			continue
+		}
+		if r.Message != "" {
+			problems = []string{r.Message}
+		}
+		violations = append(violations, LintViolation{
+			RecordID: rec.ID,
+			Kind:     rec.Kind,
+			Rule:     r.Name,
+			Problems: problems,
+			Content:  rec.Content,
+		})
+	}
+	return violations
+}
+
+// Lint checks the records recall would return against rules: active
+// ideas, and authoritative decisions and learnings that are still active.
+func (m *Memory) Lint(rules []LintRule) ([]LintViolation, error) {
+	violations := []LintViolation{}
+	if len(rules) == 0 {
+		return violations, nil
+	}
+	check := func(rec LintRecord) error {
+		tags, err := m.GetTags(rec.ID, rec.Kind)
+		if err != nil {
+			return err
+		}
+		rec.Tags = tags
+		violations = append(violations, LintCheck(rules, rec)...)
+		return nil
+	}
+
+	decisions, err := m.GetDecisions(DecisionStatusActive, "", "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range decisions {
+		d := &decisions[i]
+		if err := check(LintRecord{ID: d.ID, Kind: "decision", Content: d.Content, Rationale: d.Rationale}); err != nil {
+			return nil, err
+		}
+	}
+	learnings, err := m.GetLearnings("", "", 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range learnings {
+		if err := check(LintRecord{ID: learnings[i].ID, Kind: "learning", Content: learnings[i].Content}); err != nil {
+			return nil, err
+		}
+	}
+	ideas, err := m.GetIdeas("", "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ideas {
+		if err := check(LintRecord{ID: ideas[i].ID, Kind: "idea", Content: ideas[i].Content}); err != nil {
+			return nil, err
+		}
+	}
+	return violations, nil
+}
+
+func containsTag(tags []string, tag string) bool {
+	tag = normalizeTag(tag)
+	for _, t := range tags {
+		if normalizeTag(t) == tag {
+			return true
+		}
+	}
+	return false
+}
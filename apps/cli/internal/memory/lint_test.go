@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	prefix, err := NewLintRule("decision-prefix", "decision", "^Decided to ")
+	if err != nil {
+		t.Fatalf("NewLintRule failed: %v", err)
+	}
+	length, err := NewLintRule("learning-length", "learning", "")
+	if err != nil {
+		t.Fatalf("NewLintRule failed: %v", err)
+	}
+	length.MinLength = 20
+	rules := []LintRule{prefix, length}
+
+	badID, err := mem.AddDecision(Decision{Content: "Use SQLite for the index", Authority: string(AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := mem.AddDecision(Decision{Content: "Decided to use WAL mode for the index", Authority: string(AuthorityApproved)}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := mem.AddLearning(Learning{Content: "SQLite needs WAL mode for concurrent readers", Confidence: 0.8, Authority: string(AuthorityApproved)}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	violations, err := mem.Lint(rules)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(violations) != 1 {
+		t.Fatalf("expected only the unprefixed decision to be flagged, got %+v", violations)
+	}
+	if v := violations[0]; v.RecordID != badID || v.Rule != "decision-prefix" || len(v.Problems) != 1 {
+		t.Errorf("unexpected violation %+v", v)
+	}
+
+	short := LintCheck(rules, LintRecord{Kind: "learning", Content: "Use WAL"})
+	if len(short) != 1 || short[0].Rule != "learning-length" {
+		t.Errorf("a short learning should break the length rule, got %+v", short)
+	}
+
+	if _, err := NewLintRule("notes", "note", ""); err == nil {
+		t.Error("a rule for an unknown kind should be rejected")
+	}
+	if _, err := NewLintRule("broken", "", "("); err == nil {
+		t.Error("an invalid pattern should be rejected")
+	}
+}
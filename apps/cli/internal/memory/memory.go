@@ -11,6 +11,8 @@ import (
 	"time"
 
 	_ "modernc.org/sqlite"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 )
 
 // Memory manages the session memory database for a workspace.
@@ -18,6 +20,8 @@ type Memory struct {
 	db       *sql.DB
 	root     string
 	pipeline *EmbeddingPipeline // optional, may be nil
+
+	expansion QueryExpansion // stemming/synonym matching for searches
 }
 
 // Session represents an agent work session in the workspace.
@@ -69,15 +73,17 @@ type FileIntel struct {
 	Learnings    []string  `json:"learnings"` // Learning IDs associated with this file
 }
 
-// Open opens or creates the memory database at the given workspace root.
+// Open opens or creates the memory database of the workspace at root, in
+// the workspace's store (see config.ResolveStore).
 func Open(root string) (*Memory, error) {
-	dbDir := filepath.Join(root, ".palace")
-	if err := os.MkdirAll(dbDir, 0o755); err != nil { // lgtm[go/path-injection] root is trusted CLI workspace path
-		return nil, fmt.Errorf("create .palace dir: %w", err)
+	dbPath := config.MemoryDBPath(root)
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil { // lgtm[go/path-injection] root is trusted CLI workspace path
+		return nil, fmt.Errorf("create memory dir: %w", err)
 	}
 
-	dbPath := filepath.Join(dbDir, "memory.db")
-	db, err := sql.Open("sqlite", dbPath)
+	// The busy timeout goes in the DSN so that every pooled connection
+	// waits for a concurrent writer instead of failing with SQLITE_BUSY
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -86,7 +92,6 @@ func Open(root string) (*Memory, error) {
 	pragmas := []string{
 		"PRAGMA journal_mode=WAL",
 		"PRAGMA foreign_keys=ON",
-		"PRAGMA busy_timeout=5000",
 	}
 	for _, pragma := range pragmas {
 		if _, err := db.ExecContext(context.Background(), pragma); err != nil {
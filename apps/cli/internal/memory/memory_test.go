@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 )
 
 func TestMemoryBasics(t *testing.T) {
@@ -584,3 +586,44 @@ func TestCounters(t *testing.T) {
 		t.Errorf("Expected 1 file tracked, got %d", c)
 	}
 }
+
+func TestOpenSeparatesWorkspacesInDataDir(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	rootA, rootB := t.TempDir(), t.TempDir()
+
+	memA, err := Open(rootA)
+	if err != nil {
+		t.Fatalf("open A: %v", err)
+	}
+	defer memA.Close()
+	memB, err := Open(rootB)
+	if err != nil {
+		t.Fatalf("open B: %v", err)
+	}
+	defer memB.Close()
+
+	if _, err := memA.AddLearning(Learning{Content: "Only workspace A knows this", Scope: "palace", Authority: string(AuthorityApproved)}); err != nil {
+		t.Fatalf("add learning: %v", err)
+	}
+
+	if _, err := os.Stat(config.MemoryDBPath(rootA)); err != nil {
+		t.Errorf("memory A not in the data dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rootA, ".palace", "memory.db")); !os.IsNotExist(err) {
+		t.Error("memory A should not be created inside the workspace")
+	}
+	learnings, err := memB.GetLearnings("", "", 10)
+	if err != nil {
+		t.Fatalf("get learnings: %v", err)
+	}
+	if len(learnings) != 0 {
+		t.Errorf("workspace B sees %d learnings from workspace A", len(learnings))
+	}
+	learnings, err = memA.GetLearnings("", "", 10)
+	if err != nil {
+		t.Fatalf("get learnings: %v", err)
+	}
+	if len(learnings) != 1 {
+		t.Errorf("workspace A has %d learnings, want 1", len(learnings))
+	}
+}
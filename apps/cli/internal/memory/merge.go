@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ContentHash returns a stable hash of what a record says and where it
+// applies: its kind, scope, scope path, and content, with the content
+// lowercased and its whitespace collapsed. Two records with the same hash
+// are the same knowledge, whatever their IDs, so stores kept on different
+// machines can be merged without duplicating it.
+func ContentHash(kind, content, scope, scopePath string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(content), " "))
+	normalized = strings.TrimRight(normalized, ".!")
+	sum := sha256.Sum256([]byte(kind + "\x00" + scope + "\x00" + scopePath + "\x00" + normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// MergeConflict is a record both stores have under one ID but whose content
+// differs: it was edited on one side, or both, since they last shared it.
+type MergeConflict struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Local    string `json:"local"`
+	Incoming string `json:"incoming"`
+}
+
+// MergeOptions controls MergeFrom.
+type MergeOptions struct {
+	ByContent bool // Also skip records whose ContentHash m already holds under another ID
+	DryRun    bool // Report without writing
+}
+
+// MergeReport tells what MergeFrom did, or would do on a dry run.
+type MergeReport struct {
+	Imported   []string        `json:"imported"`   // IDs of the records added
+	Duplicates []string        `json:"duplicates"` // IDs of incoming records already held
+	Conflicts  []MergeConflict `json:"conflicts"`  // Left for manual resolution
+}
+
+// mergeRecord is a record of any kind reduced to what merging compares,
+// with the function that stores it.
+type mergeRecord struct {
+	id, kind                  string
+	content, scope, scopePath string
+	add                       func(m *Memory) error
+}
+
+// MergeFrom imports the ideas, decisions, and learnings of src that m does
+// not already hold, with their IDs, timestamps, and tags. An incoming record
+// is a duplicate when m has a record with its ID and content or, with
+// ByContent, one with a different ID but the same ContentHash. A record m
+// has under the same ID with different content is a conflict: it is
+// reported and neither side is changed. Archived ideas are not imported,
+// and neither are links.
+func (m *Memory) MergeFrom(src *Memory, opts MergeOptions) (*MergeReport, error) {
+	incoming, err := src.mergeRecords(false)
+	if err != nil {
+		return nil, fmt.Errorf("read source store: %w", err)
+	}
+	// Archived ideas still hold their IDs locally.
+	local, err := m.mergeRecords(true)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]mergeRecord, len(local))
+	hashes := make(map[string]bool, len(local))
+	for _, r := range local {
+		byID[r.id] = r
+		hashes[ContentHash(r.kind, r.content, r.scope, r.scopePath)] = true
+	}
+
+	report := &MergeReport{Imported: []string{}, Duplicates: []string{}, Conflicts: []MergeConflict{}}
+	for _, r := range incoming {
+		hash := ContentHash(r.kind, r.content, r.scope, r.scopePath)
+		if existing, ok := byID[r.id]; ok {
+			if ContentHash(existing.kind, existing.content, existing.scope, existing.scopePath) == hash {
+				report.Duplicates = append(report.Duplicates, r.id)
+			} else {
+				report.Conflicts = append(report.Conflicts, MergeConflict{ID: r.id, Kind: r.kind, Local: existing.content, Incoming: r.content})
+			}
+			continue
+		}
+		if opts.ByContent && hashes[hash] {
+			report.Duplicates = append(report.Duplicates, r.id)
+			continue
+		}
+		hashes[hash] = true
+		if !opts.DryRun {
+			if err := r.add(m); err != nil {
+				return report, err
+			}
+			tags, err := src.GetTags(r.id, r.kind)
+			if err != nil {
+				return report, err
+			}
+			if len(tags) > 0 {
+				if err := m.SetTags(r.id, r.kind, tags); err != nil {
+					return report, err
+				}
+			}
+		}
+		report.Imported = append(report.Imported, r.id)
+	}
+	return report, nil
+}
+
+// mergeRecords lists every record merging considers: decisions, then
+// ideas, then learnings.
+func (m *Memory) mergeRecords(includeArchived bool) ([]mergeRecord, error) {
+	var records []mergeRecord
+
+	decisions, err := m.GetDecisionsWithAuthority("", "", "", "", 0, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range decisions {
+		d := decisions[i]
+		records = append(records, mergeRecord{
+			id: d.ID, kind: "decision", content: d.Content, scope: d.Scope, scopePath: d.ScopePath,
+			add: func(dst *Memory) error { _, err := dst.AddDecision(d); return err },
+		})
+	}
+
+	ideas, err := m.GetIdeas("", "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+	if includeArchived {
+		archived, err := m.GetIdeas(IdeaStatusArchived, "", "", 0)
+		if err != nil {
+			return nil, err
+		}
+		ideas = append(ideas, archived...)
+	}
+	for i := range ideas {
+		idea := ideas[i]
+		records = append(records, mergeRecord{
+			id: idea.ID, kind: "idea", content: idea.Content, scope: idea.Scope, scopePath: idea.ScopePath,
+			add: func(dst *Memory) error { _, err := dst.AddIdea(idea); return err },
+		})
+	}
+
+	learnings, err := m.GetLearningsWithAuthority("", "", 0, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range learnings {
+		l := learnings[i]
+		records = append(records, mergeRecord{
+			id: l.ID, kind: "learning", content: l.Content, scope: l.Scope, scopePath: l.ScopePath,
+			add: func(dst *Memory) error { _, err := dst.AddLearning(l); return err },
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,77 @@
+package memory
+
+import "testing"
+
+func TestMergeFromSkipsContentDuplicates(t *testing.T) {
+	local, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer local.Close()
+	remote, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer remote.Close()
+
+	if _, err := local.AddDecision(Decision{Content: "Use SQLite for the index"}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	// The same decision, stored separately on the other machine.
+	dupID, err := remote.AddDecision(Decision{Content: "  use sqlite for the   index."})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	newID, err := remote.AddIdea(Idea{Content: "Cache rendered pages on disk"})
+	if err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	if err := remote.AddTag(newID, "idea", "perf"); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+
+	report, err := local.MergeFrom(remote, MergeOptions{ByContent: true})
+	if err != nil {
+		t.Fatalf("MergeFrom failed: %v", err)
+	}
+	if len(report.Imported) != 1 || report.Imported[0] != newID {
+		t.Errorf("imported %v, want only %s", report.Imported, newID)
+	}
+	if len(report.Duplicates) != 1 || report.Duplicates[0] != dupID {
+		t.Errorf("duplicates %v, want %s", report.Duplicates, dupID)
+	}
+	if n, _ := local.CountDecisions("", ""); n != 1 {
+		t.Errorf("local has %d decisions after merging, want 1", n)
+	}
+	if tags, _ := local.GetTags(newID, "idea"); len(tags) != 1 || tags[0] != "perf" {
+		t.Errorf("imported idea tags = %v, want [perf]", tags)
+	}
+
+	// The same record edited differently on each side is a conflict.
+	if err := local.UpdateIdea(newID, "Cache rendered pages in Redis", ""); err != nil {
+		t.Fatalf("UpdateIdea failed: %v", err)
+	}
+	report, err = local.MergeFrom(remote, MergeOptions{ByContent: true})
+	if err != nil {
+		t.Fatalf("MergeFrom failed: %v", err)
+	}
+	if len(report.Imported) != 0 || len(report.Conflicts) != 1 || report.Conflicts[0].ID != newID {
+		t.Errorf("second merge = %+v, want a conflict on %s and nothing imported", report, newID)
+	}
+	if idea, _ := local.GetIdea(newID); idea.Content != "Cache rendered pages in Redis" {
+		t.Errorf("conflicting idea changed to %q", idea.Content)
+	}
+}
+
+func TestContentHashNormalizes(t *testing.T) {
+	a := ContentHash("learning", "Run  tests with -race.", "room", "auth")
+	if b := ContentHash("learning", "run tests with -race", "room", "auth"); a != b {
+		t.Error("hash changed with case, spacing, or a trailing period")
+	}
+	if b := ContentHash("learning", "Run tests with -race", "room", "billing"); a == b {
+		t.Error("hash ignored the scope path")
+	}
+	if b := ContentHash("idea", "Run tests with -race", "room", "auth"); a == b {
+		t.Error("hash ignored the kind")
+	}
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PinnedRecord is a record on the pinboard.
+type PinnedRecord struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Content   string    `json:"content"`
+	Scope     string    `json:"scope"`
+	ScopePath string    `json:"scopePath,omitempty"`
+	PinnedAt  time.Time `json:"pinnedAt,omitempty"` // Zero if pinned before pin times were kept
+}
+
+// PinFilter narrows the pinboard. The zero value lists every pinned record.
+type PinFilter struct {
+	Scope     string
+	ScopePath string
+	Query     string // Keep records matching the query, with query expansion
+}
+
+// Pinned returns the records tagged PinnedTag, in the order they were
+// pinned. Archived ideas and learnings and non-authoritative decisions and
+// learnings are left out, as in recall.
+func (m *Memory) Pinned(f PinFilter) ([]PinnedRecord, error) {
+	authVals := AuthoritativeValuesStrings()
+	args := []interface{}{IdeaStatusArchived}
+	for range 2 {
+		for _, v := range authVals {
+			args = append(args, v)
+		}
+	}
+	args = append(args, LearningStatusArchived, PinnedTag)
+	query := `
+		SELECT t.record_id, t.record_kind, r.content, r.scope, r.scope_path, COALESCE(t.tagged_at, '')
+		FROM record_tags t
+		JOIN (
+			SELECT id, 'idea' AS kind, content, scope, scope_path FROM ideas WHERE status != ?
+			UNION ALL
+			SELECT id, 'decision', content, scope, scope_path FROM decisions WHERE authority IN (` + SQLPlaceholders(len(authVals)) + `)
+			UNION ALL
+			SELECT id, 'learning', content, scope, scope_path FROM learnings WHERE authority IN (` + SQLPlaceholders(len(authVals)) + `) AND status != ?
+		) r ON r.id = t.record_id AND r.kind = t.record_kind
+		WHERE t.tag = ?`
+	if f.Scope != "" {
+		query += ` AND r.scope = ?`
+		args = append(args, f.Scope)
+	}
+	if f.ScopePath != "" {
+		query += ` AND r.scope_path = ?`
+		args = append(args, f.ScopePath)
+	}
+	query += ` ORDER BY t.tagged_at, t.record_id`
+
+	rows, err := m.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query pinned records: %w", err)
+	}
+	defer rows.Close()
+
+	pinned := []PinnedRecord{}
+	for rows.Next() {
+		var p PinnedRecord
+		var at string
+		if err := rows.Scan(&p.ID, &p.Kind, &p.Content, &p.Scope, &p.ScopePath, &at); err != nil {
+			return nil, fmt.Errorf("scan pinned record: %w", err)
+		}
+		if f.Query != "" && m.expansion.MatchScore(p.Content, f.Query) == 0 {
+			continue
+		}
+		p.PinnedAt = parseTimeOrZero(at)
+		pinned = append(pinned, p)
+	}
+	return pinned, rows.Err()
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPinned(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	decisionID, err := mem.AddDecision(Decision{Content: "Use SQLite for the index", Authority: string(AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	learningID, err := mem.AddLearning(Learning{Content: "SQLite needs WAL mode for concurrent readers", Scope: "room", ScopePath: "storage", Confidence: 0.8, Authority: string(AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	if _, err := mem.AddIdea(Idea{Content: "Use SQLite for the cache too"}); err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+
+	if err := mem.AddTag(learningID, "learning", PinnedTag); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+	if err := mem.SetTags(decisionID, "decision", []string{"storage", PinnedTag}); err != nil {
+		t.Fatalf("SetTags failed: %v", err)
+	}
+	// Pin times are kept to the second; make the learning the older pin.
+	if _, err := mem.db.ExecContext(context.Background(), `UPDATE record_tags SET tagged_at = '2026-01-01T00:00:00Z' WHERE record_id = ?`, learningID); err != nil {
+		t.Fatalf("backdate pin: %v", err)
+	}
+
+	pinned, err := mem.Pinned(PinFilter{})
+	if err != nil {
+		t.Fatalf("Pinned failed: %v", err)
+	}
+	if len(pinned) != 2 || pinned[0].ID != learningID || pinned[1].ID != decisionID {
+		t.Fatalf("Pinned() = %+v, want the learning then the decision", pinned)
+	}
+	if pinned[0].PinnedAt.Year() != 2026 || pinned[0].ScopePath != "storage" {
+		t.Errorf("pinned learning = %+v, want pinned in 2026 with scope path storage", pinned[0])
+	}
+
+	// Retagging keeps the original pin time.
+	if err := mem.SetTags(learningID, "learning", []string{PinnedTag, "sqlite"}); err != nil {
+		t.Fatalf("SetTags failed: %v", err)
+	}
+	if pinned, _ = mem.Pinned(PinFilter{}); len(pinned) != 2 || pinned[0].ID != learningID {
+		t.Errorf("after retagging Pinned() = %+v, want the learning still first", pinned)
+	}
+
+	if pinned, _ = mem.Pinned(PinFilter{Scope: "room", ScopePath: "storage"}); len(pinned) != 1 || pinned[0].ID != learningID {
+		t.Errorf("Pinned(room:storage) = %+v, want only the learning", pinned)
+	}
+	if pinned, _ = mem.Pinned(PinFilter{Query: "index"}); len(pinned) != 1 || pinned[0].ID != decisionID {
+		t.Errorf("Pinned(query index) = %+v, want only the decision", pinned)
+	}
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// reviewTimeFormat is fixed-width so stored review times sort correctly as text.
+const reviewTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// ReviewPrompt is a learning framed as a spaced-review question.
+type ReviewPrompt struct {
+	Learning       Learning  `json:"learning"`
+	Question       string    `json:"question"`
+	LastReviewedAt time.Time `json:"lastReviewedAt,omitempty"` // Zero if never reviewed
+	ReviewCount    int       `json:"reviewCount"`
+}
+
+// GetReviewPrompts selects authoritative learnings for active recall
+// practice, least recently reviewed first: learnings never reviewed come
+// before all others, and ties favor higher confidence. Scope and scopePath
+// filter like GetLearnings. A limit of 0 returns every learning.
+func (m *Memory) GetReviewPrompts(scope, scopePath string, limit int) ([]ReviewPrompt, error) {
+	query := `SELECT l.id, l.session_id, l.scope, l.scope_path, l.content, l.confidence, l.source, l.authority,
+			l.promoted_from_proposal_id, l.created_at, l.last_used, l.use_count,
+			COALESCE(r.last_reviewed_at, ''), COALESCE(r.review_count, 0)
+		FROM learnings l LEFT JOIN learning_reviews r ON r.learning_id = l.id
+		WHERE l.authority IN (` + SQLPlaceholders(len(AuthoritativeValuesStrings())) + `)`
+	var args []any
+	for _, v := range AuthoritativeValuesStrings() {
+		args = append(args, v)
+	}
+	if scope != "" {
+		query += ` AND l.scope = ?`
+		args = append(args, scope)
+	}
+	if scopePath != "" {
+		query += ` AND l.scope_path = ?`
+		args = append(args, scopePath)
+	}
+	query += ` ORDER BY r.last_reviewed_at IS NOT NULL, r.last_reviewed_at ASC, l.confidence DESC, l.created_at ASC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query review prompts: %w", err)
+	}
+	defer rows.Close()
+
+	var prompts []ReviewPrompt
+	for rows.Next() {
+		var p ReviewPrompt
+		l := &p.Learning
+		var createdAt, lastUsed, reviewedAt string
+		if err := rows.Scan(&l.ID, &l.SessionID, &l.Scope, &l.ScopePath, &l.Content, &l.Confidence, &l.Source, &l.Authority,
+			&l.PromotedFromProposalID, &createdAt, &lastUsed, &l.UseCount, &reviewedAt, &p.ReviewCount); err != nil {
+			return nil, fmt.Errorf("scan review prompt: %w", err)
+		}
+		l.CreatedAt = parseTimeOrZero(createdAt)
+		l.LastUsed = parseTimeOrZero(lastUsed)
+		p.LastReviewedAt = parseTimeOrZero(reviewedAt)
+		p.Question = "Do you remember: " + l.Content
+		prompts = append(prompts, p)
+	}
+	return prompts, rows.Err()
+}
+
+// MarkReviewed records that the given learnings were just reviewed, pushing
+// them to the back of the review order.
+func (m *Memory) MarkReviewed(learningIDs ...string) error {
+	now := time.Now().UTC().Format(reviewTimeFormat)
+	for _, id := range learningIDs {
+		_, err := m.db.ExecContext(context.Background(), `
+			INSERT INTO learning_reviews (learning_id, last_reviewed_at, review_count) VALUES (?, ?, 1)
+			ON CONFLICT(learning_id) DO UPDATE SET last_reviewed_at = excluded.last_reviewed_at,
+				review_count = review_count + 1
+		`, id, now)
+		if err != nil {
+			return fmt.Errorf("mark %s reviewed: %w", id, err)
+		}
+	}
+	return nil
+}
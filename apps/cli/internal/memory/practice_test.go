@@ -0,0 +1,52 @@
+package memory
+
+import "testing"
+
+func TestGetReviewPromptsPrefersUnreviewed(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	reviewed, err := mem.AddLearning(Learning{Content: "Retries use exponential backoff", Scope: "palace", Confidence: 0.9, Authority: string(AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	fresh, err := mem.AddLearning(Learning{Content: "Migrations run inside a transaction", Scope: "palace", Confidence: 0.5, Authority: string(AuthorityApproved)})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	if err := mem.MarkReviewed(reviewed); err != nil {
+		t.Fatalf("MarkReviewed failed: %v", err)
+	}
+
+	prompts, err := mem.GetReviewPrompts("", "", 0)
+	if err != nil {
+		t.Fatalf("GetReviewPrompts failed: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 prompts, got %d", len(prompts))
+	}
+	if prompts[0].Learning.ID != fresh || !prompts[0].LastReviewedAt.IsZero() {
+		t.Errorf("expected the never-reviewed learning first, got %+v", prompts[0])
+	}
+	if prompts[0].Question != "Do you remember: Migrations run inside a transaction" {
+		t.Errorf("unexpected question %q", prompts[0].Question)
+	}
+	if prompts[1].Learning.ID != reviewed || prompts[1].LastReviewedAt.IsZero() || prompts[1].ReviewCount != 1 {
+		t.Errorf("expected the reviewed learning last with its history, got %+v", prompts[1])
+	}
+
+	// Reviewing the fresh one makes the older review due first
+	if err := mem.MarkReviewed(fresh); err != nil {
+		t.Fatalf("MarkReviewed failed: %v", err)
+	}
+	prompts, err = mem.GetReviewPrompts("", "", 1)
+	if err != nil {
+		t.Fatalf("GetReviewPrompts failed: %v", err)
+	}
+	if len(prompts) != 1 || prompts[0].Learning.ID != reviewed {
+		t.Errorf("expected the least recently reviewed learning, got %+v", prompts)
+	}
+}
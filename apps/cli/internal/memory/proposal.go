@@ -70,6 +70,20 @@ func GenerateDedupeKey(proposedAs, content, scope, scopePath string) string {
 
 // AddProposal stores a new proposal in the database.
 func (m *Memory) AddProposal(p Proposal) (string, error) {
+	id, err := m.addProposal(p)
+	if err != nil {
+		return "", err
+	}
+	m.journalCreate(JournalOpStore, "proposal", id)
+	return id, nil
+}
+
+func (m *Memory) addProposal(p Proposal) (string, error) {
+	return insertProposal(m.db, p)
+}
+
+// insertProposal fills in the defaults of a proposal and inserts it through db.
+func insertProposal(db execer, p Proposal) (string, error) {
 	if p.ID == "" {
 		p.ID = generateID("prop")
 	}
@@ -119,7 +133,7 @@ func (m *Memory) AddProposal(p Proposal) (string, error) {
 		archivedAt = p.ArchivedAt.Format(time.RFC3339)
 	}
 
-	_, err := m.db.ExecContext(context.Background(), `
+	_, err := db.ExecContext(context.Background(), `
 		INSERT INTO proposals (id, proposed_as, content, context, rationale, scope, scope_path, source, session_id, agent_type, evidence_refs, classification_confidence, classification_signals, dedupe_key, status, reviewed_by, reviewed_at, review_note, promoted_to_id, created_at, expires_at, archived_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, p.ID, p.ProposedAs, p.Content, p.Context, p.Rationale, p.Scope, p.ScopePath, p.Source, p.SessionID, p.AgentType, p.EvidenceRefs, p.ClassificationConfidence, p.ClassificationSignals, p.DedupeKey, p.Status, p.ReviewedBy, reviewedAt, p.ReviewNote, p.PromotedToID,
@@ -395,6 +409,10 @@ func (m *Memory) ApproveProposal(proposalID, reviewedBy, reviewNote string) (str
 	if err != nil {
 		return "", fmt.Errorf("update proposal status: %w", err)
 	}
+	// A retried store of the proposal resolves to the record it became
+	if err := moveIdempotencyKeys(tx, proposalID, promotedID, string(proposal.ProposedAs)); err != nil {
+		return "", err
+	}
 
 	// Commit the transaction
 	if err = tx.Commit(); err != nil {
@@ -468,8 +486,19 @@ func (m *Memory) ExpireOldProposals() (int64, error) {
 
 // DeleteProposal removes a proposal from the database (admin only).
 func (m *Memory) DeleteProposal(id string) error {
-	_, err := m.db.ExecContext(context.Background(), `DELETE FROM proposals WHERE id = ?`, id)
-	return err
+	before := m.snapshot("proposal", id)
+	if err := m.deleteProposal(id); err != nil {
+		return err
+	}
+	m.journalDelete(JournalOpForget, "proposal", id, before)
+	return nil
+}
+
+func (m *Memory) deleteProposal(id string) error {
+	if _, err := m.db.ExecContext(context.Background(), `DELETE FROM proposals WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return releaseIdempotencyKeys(m.db, id)
 }
 
 // SetEvidenceRefs sets the evidence references for a proposal as JSON.
@@ -0,0 +1,332 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Suggestion types produced by Reflect.
+const (
+	SuggestConflict        = "conflict"         // Active decisions that disagree
+	SuggestUnrealizedIdeas = "unrealized_ideas" // Tagged ideas with no decision
+	SuggestPendingOutcome  = "pending_outcome"  // Old decisions with no outcome
+	SuggestWeakLearnings   = "weak_learnings"   // Low-confidence learnings
+)
+
+// reflectPendingAge is how old a decision must be before a missing outcome
+// is worth asking about.
+const reflectPendingAge = 30 * 24 * time.Hour
+
+// reflectWeakConfidence is the confidence below which a learning is worth
+// confirming or archiving.
+const reflectWeakConfidence = 0.3
+
+// ReflectFilter selects the records Reflect looks at. Empty fields leave
+// that side unrestricted; with Tags set a record must carry at least one.
+type ReflectFilter struct {
+	Scope     string
+	ScopePath string
+	Tags      []string
+	Kinds     []string // "idea", "decision", "learning"
+}
+
+// Suggestion is one thing worth doing about the knowledge base, with the
+// records it concerns and the tool call that would act on it.
+type Suggestion struct {
+	Type      string   `json:"type"`
+	Message   string   `json:"message"`
+	RecordIDs []string `json:"recordIds"`
+	Action    string   `json:"action"`
+}
+
+// Reflection is the result of Reflect: how many records matched the filter
+// and what could be done about them, most pressing first.
+type Reflection struct {
+	Ideas       int          `json:"ideas"`
+	Decisions   int          `json:"decisions"`
+	Learnings   int          `json:"learnings"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// Reflect looks over the authoritative records the filter selects and
+// suggests what to do about them: conflicting active decisions (linked as
+// contradicting, or on the same topic per DecisionConsensus) to resolve,
+// tagged ideas that never led to a decision, old decisions with no recorded
+// outcome, and low-confidence learnings. Records outside the filter are
+// neither listed nor compared against.
+func (m *Memory) Reflect(f ReflectFilter) (*Reflection, error) {
+	wantKind := func(kind string) bool {
+		if len(f.Kinds) == 0 {
+			return true
+		}
+		for _, k := range f.Kinds {
+			if k == kind {
+				return true
+			}
+		}
+		return false
+	}
+	tagged, err := m.taggedRecords(f.Tags)
+	if err != nil {
+		return nil, err
+	}
+	keep := func(id string) bool { return tagged == nil || tagged[id] }
+
+	var decisions []Decision
+	if wantKind("decision") {
+		all, err := m.GetDecisions("", "", f.Scope, f.ScopePath, 0)
+		if err != nil {
+			return nil, err
+		}
+		for i := range all {
+			if keep(all[i].ID) {
+				decisions = append(decisions, all[i])
+			}
+		}
+	}
+	var ideas []Idea
+	if wantKind("idea") {
+		all, err := m.GetIdeas("", f.Scope, f.ScopePath, 0)
+		if err != nil {
+			return nil, err
+		}
+		for i := range all {
+			if keep(all[i].ID) {
+				ideas = append(ideas, all[i])
+			}
+		}
+	}
+	var learnings []Learning
+	if wantKind("learning") {
+		all, err := m.GetLearnings(f.Scope, f.ScopePath, 0)
+		if err != nil {
+			return nil, err
+		}
+		for i := range all {
+			if keep(all[i].ID) {
+				learnings = append(learnings, all[i])
+			}
+		}
+	}
+
+	r := &Reflection{Ideas: len(ideas), Decisions: len(decisions), Learnings: len(learnings), Suggestions: []Suggestion{}}
+
+	conflicts, err := m.decisionConflicts(decisions)
+	if err != nil {
+		return nil, err
+	}
+	r.Suggestions = append(r.Suggestions, conflicts...)
+
+	unrealized, err := m.unrealizedIdeas(ideas)
+	if err != nil {
+		return nil, err
+	}
+	r.Suggestions = append(r.Suggestions, unrealized...)
+
+	var pending []string
+	for i := range decisions {
+		d := &decisions[i]
+		if d.Status == DecisionStatusActive && d.Outcome == DecisionOutcomeUnknown && time.Since(d.CreatedAt) >= reflectPendingAge {
+			pending = append(pending, d.ID)
+		}
+	}
+	if len(pending) > 0 {
+		r.Suggestions = append(r.Suggestions, Suggestion{
+			Type:      SuggestPendingOutcome,
+			Message:   fmt.Sprintf("%d %s older than 30 days %s no recorded outcome", len(pending), plural(len(pending), "decision", "decisions"), plural(len(pending), "has", "have")),
+			RecordIDs: pending,
+			Action:    "Record how each turned out with recall_outcome (successful, failed, or mixed).",
+		})
+	}
+
+	var weak []string
+	for i := range learnings {
+		if learnings[i].Confidence < reflectWeakConfidence {
+			weak = append(weak, learnings[i].ID)
+		}
+	}
+	if len(weak) > 0 {
+		r.Suggestions = append(r.Suggestions, Suggestion{
+			Type:      SuggestWeakLearnings,
+			Message:   fmt.Sprintf("%d %s below %.0f%% confidence", len(weak), plural(len(weak), "learning is", "learnings are"), reflectWeakConfidence*100),
+			RecordIDs: weak,
+			Action:    "Confirm the ones that still hold with decay_reinforce, and archive the rest with recall_archive.",
+		})
+	}
+	return r, nil
+}
+
+// taggedRecords returns the IDs of the records carrying any of tags, or nil
+// when no tags are given.
+func (m *Memory) taggedRecords(tags []string) (map[string]bool, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	ids := make(map[string]bool)
+	for _, tag := range tags {
+		found, err := m.GetRecordsByTag(tag, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range found {
+			ids[id] = true
+		}
+	}
+	return ids, nil
+}
+
+// DecisionConflictPair is two active decisions that disagree.
+type DecisionConflictPair struct {
+	A, B   Decision
+	Reason string // "linked as contradicting", or both active on a topic
+}
+
+// DecisionConflictPairs finds the pairs of active decisions among decisions
+// that disagree: those linked as contradicting, then those DecisionConsensus
+// puts on one topic. Each pair is reported once.
+func (m *Memory) DecisionConflictPairs(decisions []Decision) ([]DecisionConflictPair, error) {
+	active := make(map[string]*Decision)
+	var current []Decision
+	for i := range decisions {
+		if decisions[i].Status == DecisionStatusActive {
+			active[decisions[i].ID] = &decisions[i]
+			current = append(current, decisions[i])
+		}
+	}
+	if len(active) < 2 {
+		return nil, nil
+	}
+
+	var pairs []DecisionConflictPair
+	reported := make(map[string]bool)
+	conflict := func(a, b *Decision, why string) {
+		key := a.ID + "|" + b.ID
+		if a.ID > b.ID {
+			key = b.ID + "|" + a.ID
+		}
+		if reported[key] {
+			return
+		}
+		reported[key] = true
+		pairs = append(pairs, DecisionConflictPair{A: *a, B: *b, Reason: why})
+	}
+
+	links, err := m.GetLinksByRelation(RelationContradicts, -1)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		a, okA := active[links[i].SourceID]
+		b, okB := active[links[i].TargetID]
+		if okA && okB {
+			conflict(a, b, "linked as contradicting")
+		}
+	}
+
+	groups, err := m.DecisionConsensus(current)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		g := &groups[i]
+		for j := range g.Earlier {
+			// Only active decisions were grouped, so each earlier one still
+			// stands beside the current one until it is retired.
+			conflict(active[g.Current.ID], active[g.Earlier[j].ID], fmt.Sprintf("both active on %q", g.Topic))
+		}
+	}
+	return pairs, nil
+}
+
+// decisionConflicts suggests resolving each of DecisionConflictPairs.
+func (m *Memory) decisionConflicts(decisions []Decision) ([]Suggestion, error) {
+	pairs, err := m.DecisionConflictPairs(decisions)
+	if err != nil {
+		return nil, err
+	}
+	var suggestions []Suggestion
+	for _, p := range pairs {
+		suggestions = append(suggestions, Suggestion{
+			Type:      SuggestConflict,
+			Message:   fmt.Sprintf("Resolve the conflict between %q and %q (%s)", truncateForDisplay(p.A.Content, 60), truncateForDisplay(p.B.Content, 60), p.Reason),
+			RecordIDs: []string{p.A.ID, p.B.ID},
+			Action:    "Keep one: link it to the other with recall_link (relation 'supersedes'), or record the loser's outcome with recall_outcome.",
+		})
+	}
+	return suggestions, nil
+}
+
+// unrealizedIdeas groups the open ideas no decision is linked to by tag.
+func (m *Memory) unrealizedIdeas(ideas []Idea) ([]Suggestion, error) {
+	if len(ideas) == 0 {
+		return nil, nil
+	}
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT source_id FROM links WHERE target_kind = 'decision'
+		UNION SELECT target_id FROM links WHERE source_kind = 'decision'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query decision links: %w", err)
+	}
+	linked := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan decision link: %w", err)
+		}
+		linked[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byTag := make(map[string][]string)
+	for i := range ideas {
+		idea := &ideas[i]
+		if linked[idea.ID] || (idea.Status != IdeaStatusActive && idea.Status != IdeaStatusExploring) {
+			continue
+		}
+		tags, err := m.GetTags(idea.ID, "idea")
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			if tag != PinnedTag {
+				byTag[tag] = append(byTag[tag], idea.ID)
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if len(byTag[tags[i]]) != len(byTag[tags[j]]) {
+			return len(byTag[tags[i]]) > len(byTag[tags[j]])
+		}
+		return tags[i] < tags[j]
+	})
+	suggestions := make([]Suggestion, 0, len(tags))
+	for _, tag := range tags {
+		ids := byTag[tag]
+		suggestions = append(suggestions, Suggestion{
+			Type:      SuggestUnrealizedIdeas,
+			Message:   fmt.Sprintf("%d %s tagged %q never became %s", len(ids), plural(len(ids), "idea", "ideas"), tag, plural(len(ids), "a decision", "decisions")),
+			RecordIDs: ids,
+			Action:    "Decide on them: store a decision and link it to each idea with recall_link, or drop the ones no longer wanted.",
+		})
+	}
+	return suggestions, nil
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReflectScopedToRoom(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	approved := string(AuthorityApproved)
+	mysqlID, err := mem.AddDecision(Decision{Content: "Use MySQL for the user database", Scope: "room", ScopePath: "storage", Authority: approved})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	postgresID, err := mem.AddDecision(Decision{Content: "Use PostgreSQL for the user database", Scope: "room", ScopePath: "storage", Authority: approved})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	// The same disagreement in another room must not be reported.
+	if _, err := mem.AddDecision(Decision{Content: "Use Redis for the session cache", Scope: "room", ScopePath: "auth", Authority: approved}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := mem.AddDecision(Decision{Content: "Use Memcached for the session cache", Scope: "room", ScopePath: "auth", Authority: approved}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	for _, content := range []string{"Batch inserts", "Add a read replica"} {
+		ideaID, err := mem.AddIdea(Idea{Content: content, Scope: "room", ScopePath: "storage"})
+		if err != nil {
+			t.Fatalf("AddIdea failed: %v", err)
+		}
+		if err := mem.AddTag(ideaID, "idea", "perf"); err != nil {
+			t.Fatalf("AddTag failed: %v", err)
+		}
+	}
+
+	r, err := mem.Reflect(ReflectFilter{Scope: "room", ScopePath: "storage"})
+	if err != nil {
+		t.Fatalf("Reflect failed: %v", err)
+	}
+	if r.Decisions != 2 || r.Ideas != 2 {
+		t.Errorf("looked at %d decisions and %d ideas, want 2 and 2", r.Decisions, r.Ideas)
+	}
+
+	var conflicts, unrealized []Suggestion
+	for _, s := range r.Suggestions {
+		switch s.Type {
+		case SuggestConflict:
+			conflicts = append(conflicts, s)
+		case SuggestUnrealizedIdeas:
+			unrealized = append(unrealized, s)
+		}
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want only the MySQL vs PostgreSQL one", conflicts)
+	}
+	ids := strings.Join(conflicts[0].RecordIDs, ",")
+	if !strings.Contains(ids, mysqlID) || !strings.Contains(ids, postgresID) {
+		t.Errorf("conflict records = %v, want %s and %s", conflicts[0].RecordIDs, mysqlID, postgresID)
+	}
+	if len(unrealized) != 1 || !strings.Contains(unrealized[0].Message, `2 ideas tagged "perf" never became decisions`) {
+		t.Errorf("unrealized ideas = %+v, want 2 ideas tagged perf", unrealized)
+	}
+
+	// Limiting to learnings leaves nothing to compare.
+	r, err = mem.Reflect(ReflectFilter{Scope: "room", ScopePath: "storage", Kinds: []string{"learning"}})
+	if err != nil {
+		t.Fatalf("Reflect failed: %v", err)
+	}
+	if len(r.Suggestions) != 0 {
+		t.Errorf("learnings-only reflection suggested %+v", r.Suggestions)
+	}
+}
@@ -0,0 +1,275 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RelinkOptions sets how related two records must be before Relink proposes
+// a "related" link between them. Zero values fall back to the defaults.
+type RelinkOptions struct {
+	MinSharedTags int     // Tags two records must share (default 2)
+	MinTagOverlap float64 // Jaccard overlap of their tag sets (default 0.5)
+	MinSimilarity float64 // Jaccard overlap of their stemmed content words (default 0.6)
+}
+
+// Relink defaults.
+const (
+	DefaultRelinkMinSharedTags = 2
+	DefaultRelinkMinTagOverlap = 0.5
+	DefaultRelinkMinSimilarity = 0.6
+)
+
+// RelinkProposal is a suggested "related" link and why it was suggested.
+type RelinkProposal struct {
+	SourceID   string  `json:"sourceId"`
+	SourceKind string  `json:"sourceKind"`
+	TargetID   string  `json:"targetId"`
+	TargetKind string  `json:"targetKind"`
+	Reason     string  `json:"reason"` // "anchor", "tags", or "content"
+	Detail     string  `json:"detail"` // The shared file, the shared tags, or the similarity
+	Score      float64 `json:"score"`
+}
+
+// relinkRecord is a record with what Relink compares it on.
+type relinkRecord struct {
+	id, kind string
+	anchors  map[string]bool
+	tags     map[string]bool
+	words    map[string]bool
+}
+
+// ProposeRelinks suggests "related" links between ideas, decisions, and
+// learnings that are anchored to the same file (by file scope or a code
+// link), share enough tags, or have closely matching content. Pairs that are
+// already linked in either direction, by any relation, are skipped: an
+// explicit link always says at least as much as "related". Proposals are
+// ordered by reason (anchor, tags, content), then by score.
+func (m *Memory) ProposeRelinks(opts RelinkOptions) ([]RelinkProposal, error) {
+	if opts.MinSharedTags <= 0 {
+		opts.MinSharedTags = DefaultRelinkMinSharedTags
+	}
+	if opts.MinTagOverlap <= 0 {
+		opts.MinTagOverlap = DefaultRelinkMinTagOverlap
+	}
+	if opts.MinSimilarity <= 0 {
+		opts.MinSimilarity = DefaultRelinkMinSimilarity
+	}
+
+	records, err := m.loadRelinkRecords()
+	if err != nil {
+		return nil, err
+	}
+	linked, err := m.linkedPairs()
+	if err != nil {
+		return nil, err
+	}
+
+	var proposals []RelinkProposal
+	for i := range records {
+		for j := i + 1; j < len(records); j++ {
+			a, b := records[i], records[j]
+			if linked[pairKey(a.id, b.id)] {
+				continue
+			}
+			if p, ok := relinkPair(a, b, opts); ok {
+				proposals = append(proposals, p)
+			}
+		}
+	}
+
+	reasonRank := map[string]int{"anchor": 0, "tags": 1, "content": 2}
+	sort.SliceStable(proposals, func(i, j int) bool {
+		if proposals[i].Reason != proposals[j].Reason {
+			return reasonRank[proposals[i].Reason] < reasonRank[proposals[j].Reason]
+		}
+		return proposals[i].Score > proposals[j].Score
+	})
+	return proposals, nil
+}
+
+// ApplyRelinks creates the proposed links, journaled like any other link,
+// and returns how many were created.
+func (m *Memory) ApplyRelinks(proposals []RelinkProposal) (int, error) {
+	created := 0
+	for _, p := range proposals {
+		if _, err := m.AddLink(Link{
+			SourceID:   p.SourceID,
+			SourceKind: p.SourceKind,
+			TargetID:   p.TargetID,
+			TargetKind: p.TargetKind,
+			Relation:   RelationRelated,
+		}); err != nil {
+			return created, fmt.Errorf("link %s to %s: %w", p.SourceID, p.TargetID, err)
+		}
+		created++
+	}
+	return created, nil
+}
+
+// relinkPair checks the strongest signal first, so a pair is proposed once
+// with the best reason it qualifies for.
+func relinkPair(a, b relinkRecord, opts RelinkOptions) (RelinkProposal, bool) {
+	p := RelinkProposal{SourceID: a.id, SourceKind: a.kind, TargetID: b.id, TargetKind: b.kind}
+
+	var sharedAnchors []string
+	for anchor := range a.anchors {
+		if b.anchors[anchor] {
+			sharedAnchors = append(sharedAnchors, anchor)
+		}
+	}
+	if len(sharedAnchors) > 0 {
+		sort.Strings(sharedAnchors)
+		p.Reason, p.Detail, p.Score = "anchor", strings.Join(sharedAnchors, ", "), 1
+		return p, true
+	}
+
+	if shared, overlap := setOverlap(a.tags, b.tags); len(shared) >= opts.MinSharedTags && overlap >= opts.MinTagOverlap {
+		p.Reason, p.Detail, p.Score = "tags", strings.Join(shared, ", "), overlap
+		return p, true
+	}
+
+	if _, similarity := setOverlap(a.words, b.words); similarity >= opts.MinSimilarity {
+		p.Reason, p.Detail, p.Score = "content", fmt.Sprintf("%.0f%% similar", similarity*100), similarity
+		return p, true
+	}
+	return p, false
+}
+
+// setOverlap returns the sorted intersection of two sets and their Jaccard
+// index.
+func setOverlap(a, b map[string]bool) ([]string, float64) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, 0
+	}
+	var shared []string
+	for k := range a {
+		if b[k] {
+			shared = append(shared, k)
+		}
+	}
+	sort.Strings(shared)
+	union := len(a) + len(b) - len(shared)
+	return shared, float64(len(shared)) / float64(union)
+}
+
+// loadRelinkRecords loads every idea, decision, and learning with its
+// anchors, tags, and content words.
+func (m *Memory) loadRelinkRecords() ([]relinkRecord, error) {
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT id, 'idea', scope, scope_path, content FROM ideas
+		UNION ALL SELECT id, 'decision', scope, scope_path, content FROM decisions
+		UNION ALL SELECT id, 'learning', scope, scope_path, content FROM learnings
+		ORDER BY 1`)
+	if err != nil {
+		return nil, fmt.Errorf("load records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []relinkRecord
+	byID := make(map[string]*relinkRecord)
+	for rows.Next() {
+		var r relinkRecord
+		var scope, scopePath, content string
+		if err := rows.Scan(&r.id, &r.kind, &scope, &scopePath, &content); err != nil {
+			return nil, fmt.Errorf("scan record: %w", err)
+		}
+		r.anchors = make(map[string]bool)
+		r.tags = make(map[string]bool)
+		if scope == string(ScopeFile) && scopePath != "" {
+			r.anchors[scopePath] = true
+		}
+		r.words = contentWords(content)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range records {
+		byID[records[i].id] = &records[i]
+	}
+
+	tagRows, err := m.db.QueryContext(context.Background(), `SELECT record_id, tag FROM record_tags`)
+	if err != nil {
+		return nil, fmt.Errorf("load tags: %w", err)
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var id, tag string
+		if err := tagRows.Scan(&id, &tag); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		if r := byID[id]; r != nil {
+			r.tags[tag] = true
+		}
+	}
+	if err := tagRows.Err(); err != nil {
+		return nil, err
+	}
+
+	codeLinks, err := m.db.QueryContext(context.Background(),
+		`SELECT source_id, target_id FROM links WHERE target_kind = ?`, TargetKindCode)
+	if err != nil {
+		return nil, fmt.Errorf("load code links: %w", err)
+	}
+	defer codeLinks.Close()
+	for codeLinks.Next() {
+		var id, target string
+		if err := codeLinks.Scan(&id, &target); err != nil {
+			return nil, fmt.Errorf("scan link: %w", err)
+		}
+		r := byID[id]
+		if r == nil {
+			continue
+		}
+		if ct, err := ParseCodeTarget(target); err == nil {
+			r.anchors[ct.FilePath] = true
+		}
+	}
+	return records, codeLinks.Err()
+}
+
+// linkedPairs returns every pair of records already linked to each other.
+func (m *Memory) linkedPairs() (map[string]bool, error) {
+	rows, err := m.db.QueryContext(context.Background(), `SELECT source_id, target_id FROM links`)
+	if err != nil {
+		return nil, fmt.Errorf("load links: %w", err)
+	}
+	defer rows.Close()
+	pairs := make(map[string]bool)
+	for rows.Next() {
+		var source, target string
+		if err := rows.Scan(&source, &target); err != nil {
+			return nil, fmt.Errorf("scan link: %w", err)
+		}
+		pairs[pairKey(source, target)] = true
+	}
+	return pairs, rows.Err()
+}
+
+// pairKey identifies an unordered pair of record IDs.
+func pairKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "\x00" + b
+}
+
+// contentWords returns the stemmed words of content, skipping words too
+// short to say anything about what a record is about.
+func contentWords(content string) map[string]bool {
+	words := make(map[string]bool)
+	for _, tok := range searchTokens(content) {
+		if len(tok) < 4 {
+			continue
+		}
+		if isCodeToken(tok) {
+			words[strings.ToLower(tok)] = true
+		} else {
+			words[Stem(tok)] = true
+		}
+	}
+	return words
+}
@@ -0,0 +1,78 @@
+package memory
+
+import "testing"
+
+func TestProposeRelinksSharedAnchor(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	learningID, err := mem.AddLearning(Learning{Content: "Tokens expire after fifteen minutes", Scope: "file", ScopePath: "auth/jwt.go"})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	decisionID, err := mem.AddDecision(Decision{Content: "Sign tokens with RS256", Scope: "file", ScopePath: "auth/jwt.go"})
+	if err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := mem.AddIdea(Idea{Content: "Render charts with a canvas backend", Scope: "palace"}); err != nil {
+		t.Fatalf("AddIdea failed: %v", err)
+	}
+	if _, err := mem.AddLearning(Learning{Content: "Migrations run inside one transaction", Scope: "file", ScopePath: "db/migrate.go"}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	proposals, err := mem.ProposeRelinks(RelinkOptions{})
+	if err != nil {
+		t.Fatalf("ProposeRelinks failed: %v", err)
+	}
+	if len(proposals) != 1 {
+		t.Fatalf("expected exactly one proposal, got %+v", proposals)
+	}
+	p := proposals[0]
+	if pairKey(p.SourceID, p.TargetID) != pairKey(learningID, decisionID) || p.Reason != "anchor" || p.Detail != "auth/jwt.go" {
+		t.Errorf("expected an anchor proposal between %s and %s, got %+v", learningID, decisionID, p)
+	}
+
+	created, err := mem.ApplyRelinks(proposals)
+	if err != nil || created != 1 {
+		t.Fatalf("ApplyRelinks = %d, %v", created, err)
+	}
+	links, _ := mem.GetAllLinksFor(learningID)
+	if len(links) != 1 || links[0].Relation != RelationRelated {
+		t.Errorf("expected one related link, got %+v", links)
+	}
+
+	// Once linked, the pair is not proposed again
+	if again, _ := mem.ProposeRelinks(RelinkOptions{}); len(again) != 0 {
+		t.Errorf("expected no proposals after linking, got %+v", again)
+	}
+}
+
+func TestProposeRelinksSkipsExplicitLinks(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	oldID, _ := mem.AddDecision(Decision{Content: "Cache sessions in memory", Scope: "palace"})
+	newID, _ := mem.AddDecision(Decision{Content: "Cache sessions in Redis", Scope: "palace"})
+	for _, id := range []string{oldID, newID} {
+		if err := mem.SetTags(id, "decision", []string{"cache", "sessions"}); err != nil {
+			t.Fatalf("SetTags failed: %v", err)
+		}
+	}
+	if proposals, _ := mem.ProposeRelinks(RelinkOptions{}); len(proposals) != 1 || proposals[0].Reason != "tags" {
+		t.Fatalf("expected a tag proposal, got %+v", proposals)
+	}
+
+	if _, err := mem.AddLink(Link{SourceID: newID, SourceKind: "decision", TargetID: oldID, TargetKind: "decision", Relation: RelationSupersedes}); err != nil {
+		t.Fatalf("AddLink failed: %v", err)
+	}
+	if proposals, _ := mem.ProposeRelinks(RelinkOptions{}); len(proposals) != 0 {
+		t.Errorf("records linked by supersedes should not be relinked, got %+v", proposals)
+	}
+}
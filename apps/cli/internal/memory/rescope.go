@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// scopedTables maps the kinds of record that carry a scope to their tables.
+var scopedTables = []struct {
+	kind  string
+	table string
+}{
+	{"idea", "ideas"},
+	{"decision", "decisions"},
+	{"learning", "learnings"},
+}
+
+// ValidateScope checks that a scope and path form a valid target: palace
+// scope takes no path, while room and file scopes need one.
+func ValidateScope(scope Scope, scopePath string) error {
+	switch scope {
+	case ScopePalace:
+		if scopePath != "" {
+			return fmt.Errorf("palace scope does not take a path (got %q)", scopePath)
+		}
+	case ScopeRoom, ScopeFile:
+		if scopePath == "" {
+			return fmt.Errorf("%s scope requires a path", scope)
+		}
+	default:
+		return fmt.Errorf("invalid scope %q (use palace, room, or file)", scope)
+	}
+	return nil
+}
+
+// ChangeScope moves an idea, decision, or learning to another scope in place.
+// The record keeps its ID, tags, links, and timestamps, so scoped queries
+// find it under the new scope immediately. It returns the record's kind.
+// The change is journaled and can be undone.
+func (m *Memory) ChangeScope(id string, scope Scope, scopePath string) (string, error) {
+	if err := ValidateScope(scope, scopePath); err != nil {
+		return "", err
+	}
+	for _, probe := range scopedTables {
+		var current, currentPath string
+		err := m.db.QueryRowContext(context.Background(),
+			`SELECT scope, scope_path FROM `+probe.table+` WHERE id = ?`, id).Scan(&current, &currentPath)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("look up %s: %w", probe.kind, err)
+		}
+		if Scope(current) == scope && currentPath == scopePath {
+			return "", fmt.Errorf("%s %s is already scoped to %s", probe.kind, id, describeScope(scope, scopePath))
+		}
+
+		before := m.snapshot(probe.kind, id)
+		if err := m.setScope(probe.table, id, string(scope), scopePath); err != nil {
+			return "", err
+		}
+		_ = m.appendJournal(JournalOpRescope, probe.kind, id, before, m.snapshot(probe.kind, id))
+		return probe.kind, nil
+	}
+	return "", fmt.Errorf("no idea, decision, or learning with ID %s", id)
+}
+
+func (m *Memory) setScope(table, id, scope, scopePath string) error {
+	if _, err := m.db.ExecContext(context.Background(),
+		`UPDATE `+table+` SET scope = ?, scope_path = ? WHERE id = ?`, scope, scopePath, id); err != nil {
+		return fmt.Errorf("update scope: %w", err)
+	}
+	return nil
+}
+
+// revertScope restores the scope recorded in a rescope entry's before snapshot.
+func (m *Memory) revertScope(kind, id, before string) error {
+	var snap recordSnapshot
+	if err := json.Unmarshal([]byte(before), &snap); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	var r struct {
+		Scope     string `json:"scope"`
+		ScopePath string `json:"scopePath"`
+	}
+	if err := json.Unmarshal(snap.Record, &r); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+	for _, probe := range scopedTables {
+		if probe.kind == kind {
+			return m.setScope(probe.table, id, r.Scope, r.ScopePath)
+		}
+	}
+	return fmt.Errorf("unknown record kind %q", kind)
+}
+
+func describeScope(scope Scope, scopePath string) string {
+	if scopePath == "" {
+		return string(scope)
+	}
+	return string(scope) + ":" + scopePath
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"testing"
+)
+
+func containsLearning(learnings []Learning, id string) bool {
+	for _, l := range learnings {
+		if l.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestChangeScopePromotesToPalace(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, err := mem.AddLearning(Learning{
+		Content: "Always wrap errors with context", Scope: "file", ScopePath: "auth/jwt.go",
+		Authority: string(AuthorityApproved),
+	})
+	if err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+	mem.SetTags(id, "learning", []string{"errors"})
+	before, _ := mem.GetLearning(id)
+
+	palace, _ := mem.GetLearnings("palace", "", 0)
+	if containsLearning(palace, id) {
+		t.Fatal("file-scoped learning should not be in palace-wide recall yet")
+	}
+
+	kind, err := mem.ChangeScope(id, ScopePalace, "")
+	if err != nil {
+		t.Fatalf("ChangeScope failed: %v", err)
+	}
+	if kind != "learning" {
+		t.Errorf("kind = %q, want learning", kind)
+	}
+
+	palace, _ = mem.GetLearnings("palace", "", 0)
+	if !containsLearning(palace, id) {
+		t.Fatal("promoted learning should appear in palace-wide recall")
+	}
+	after, _ := mem.GetLearning(id)
+	if after.ScopePath != "" || !after.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("expected palace scope with original timestamps, got %+v", after)
+	}
+	if tags, _ := mem.GetTags(id, "learning"); len(tags) != 1 || tags[0] != "errors" {
+		t.Errorf("tags should be preserved, got %v", tags)
+	}
+
+	// Undo moves it back
+	if _, err := mem.Undo(1); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	restored, _ := mem.GetLearning(id)
+	if restored.Scope != "file" || restored.ScopePath != "auth/jwt.go" {
+		t.Errorf("undo should restore the file scope, got %s:%s", restored.Scope, restored.ScopePath)
+	}
+}
+
+func TestChangeScopeRejectsInvalidTransitions(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, _ := mem.AddDecision(Decision{Content: "Use Postgres"})
+
+	tests := []struct {
+		scope Scope
+		path  string
+	}{
+		{ScopeFile, ""},
+		{ScopeRoom, ""},
+		{ScopePalace, "auth"},
+		{"corridor", ""},
+		{ScopePalace, ""}, // already palace-scoped
+	}
+	for _, tt := range tests {
+		if _, err := mem.ChangeScope(id, tt.scope, tt.path); err == nil {
+			t.Errorf("ChangeScope(%s, %q) should fail", tt.scope, tt.path)
+		}
+	}
+
+	if _, err := mem.ChangeScope(id, ScopeRoom, "storage"); err != nil {
+		t.Fatalf("demoting to a room failed: %v", err)
+	}
+	if d, _ := mem.GetDecision(id); d.Scope != "room" || d.ScopePath != "storage" {
+		t.Errorf("expected room:storage, got %s:%s", d.Scope, d.ScopePath)
+	}
+	if _, err := mem.ChangeScope("d_missing", ScopePalace, ""); err == nil {
+		t.Error("expected error for a missing record")
+	}
+}
@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ReviewItem is a record whose auto-classification fell below
+// ConfidenceThreshold and awaits confirmation or correction.
+type ReviewItem struct {
+	RecordID   string    `json:"recordId"`
+	RecordKind string    `json:"recordKind"`
+	Content    string    `json:"content"`
+	Confidence float64   `json:"confidence"`
+	Signals    []string  `json:"signals,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Rules learned from reviews start out below ConfidenceThreshold, so records
+// they classify are still queued for review, and gain confidence each time
+// review confirms the same kind for the phrase again.
+const (
+	ruleBaseConfidence = 0.5 // After the first review
+	ruleConfidenceStep = 0.2 // Added by each further review that agrees
+	ruleMaxConfidence  = 0.9
+)
+
+// rulePhraseWords is how many leading words of a reviewed record become its rule.
+const rulePhraseWords = 2
+
+// ruleConfidence returns the confidence of a rule confirmed by n reviews.
+func ruleConfidence(n int) float64 {
+	return min(ruleMaxConfidence, ruleBaseConfidence+ruleConfidenceStep*float64(n-1))
+}
+
+// Classify classifies text like the package-level Classify, but first
+// consults the rules learned from reviewed classifications, so a correction
+// applies to later records that open the same way. A rule wins over the
+// built-in signals only when it is at least as confident as they are.
+func (m *Memory) Classify(text string) Classification {
+	c := Classify(text)
+	words := " " + leadingPhrase(text, -1) + " "
+	rows, err := m.db.QueryContext(context.Background(),
+		`SELECT phrase, kind, confirmations FROM classification_rules ORDER BY length(phrase) DESC, phrase`)
+	if err != nil {
+		return c
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var phrase, kind string
+		var confirmations int
+		if rows.Scan(&phrase, &kind, &confirmations) != nil {
+			continue
+		}
+		if strings.HasPrefix(words, " "+phrase+" ") {
+			if conf := ruleConfidence(confirmations); conf >= c.Confidence {
+				return Classification{Kind: RecordKind(kind), Confidence: conf, Signals: []string{"rule:" + phrase}}
+			}
+			break
+		}
+	}
+	return c
+}
+
+// FlagForReview queues a record for review when its classification is
+// uncertain. Confident classifications are not queued. It reports whether
+// the record was flagged.
+func (m *Memory) FlagForReview(recordID string, c Classification) (bool, error) {
+	if !c.NeedsConfirmation() {
+		return false, nil
+	}
+	signals := "[]"
+	if len(c.Signals) > 0 {
+		if data, err := json.Marshal(c.Signals); err == nil {
+			signals = string(data)
+		}
+	}
+	_, err := m.db.ExecContext(context.Background(), `
+		INSERT OR REPLACE INTO classification_reviews (record_id, record_kind, confidence, signals, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, recordID, string(c.Kind), c.Confidence, signals, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return false, fmt.Errorf("flag for review: %w", err)
+	}
+	return true, nil
+}
+
+// NeedsReview reports whether a record is waiting in the review queue.
+func (m *Memory) NeedsReview(recordID string) (bool, error) {
+	var n int
+	err := m.db.QueryRowContext(context.Background(),
+		`SELECT COUNT(*) FROM classification_reviews WHERE record_id = ?`, recordID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check review: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetReviewQueue returns records awaiting review, least confident first.
+// A limit of 0 returns all of them.
+func (m *Memory) GetReviewQueue(limit int) ([]ReviewItem, error) {
+	query := `SELECT record_id, record_kind, confidence, signals, created_at
+		FROM classification_reviews ORDER BY confidence ASC, created_at ASC`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := m.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query review queue: %w", err)
+	}
+	defer rows.Close()
+
+	var items []ReviewItem
+	for rows.Next() {
+		var item ReviewItem
+		var signals, createdAt string
+		if err := rows.Scan(&item.RecordID, &item.RecordKind, &item.Confidence, &signals, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan review item: %w", err)
+		}
+		_ = json.Unmarshal([]byte(signals), &item.Signals)
+		item.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Content, _, _ = m.GetRecordContent(items[i].RecordID, items[i].RecordKind)
+	}
+	return items, nil
+}
+
+// ResolveReview confirms or corrects the kind of a record in the review
+// queue and clears its flag. Correcting the kind re-stores the record under
+// the new kind, keeping its content, scope, tags, and links, and returns the
+// new ID. Either way the record's leading words become a classification rule
+// for the confirmed kind. A new rule is tentative; it becomes confident only
+// once further reviews confirm the same kind for the same phrase, and a
+// review that picks another kind starts it over.
+func (m *Memory) ResolveReview(recordID string, kind RecordKind) (string, error) {
+	switch kind {
+	case RecordKindIdea, RecordKindDecision, RecordKindLearning:
+	default:
+		return "", fmt.Errorf("invalid kind %q (use idea, decision, or learning)", kind)
+	}
+
+	var current string
+	err := m.db.QueryRowContext(context.Background(),
+		`SELECT record_kind FROM classification_reviews WHERE record_id = ?`, recordID).Scan(&current)
+	if err != nil {
+		return "", fmt.Errorf("record %s is not awaiting review", recordID)
+	}
+
+	content, _, err := m.GetRecordContent(recordID, current)
+	if err != nil {
+		return "", fmt.Errorf("get record: %w", err)
+	}
+
+	newID := recordID
+	if RecordKind(current) != kind {
+		if newID, err = m.reclassify(recordID, current, kind); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := m.db.ExecContext(context.Background(),
+		`DELETE FROM classification_reviews WHERE record_id = ?`, recordID); err != nil {
+		return "", fmt.Errorf("clear review: %w", err)
+	}
+	if phrase := leadingPhrase(content, rulePhraseWords); phrase != "" {
+		if _, err := m.db.ExecContext(context.Background(), `
+			INSERT INTO classification_rules (phrase, kind, confirmations, created_at) VALUES (?, ?, 1, ?)
+			ON CONFLICT(phrase) DO UPDATE SET
+				confirmations = CASE WHEN kind = excluded.kind THEN confirmations + 1 ELSE 1 END,
+				kind = excluded.kind,
+				created_at = excluded.created_at
+		`, phrase, string(kind), time.Now().UTC().Format(time.RFC3339)); err != nil {
+			return "", fmt.Errorf("save classification rule: %w", err)
+		}
+	}
+	return newID, nil
+}
+
+// reclassify re-stores a record as another kind and removes the original.
+func (m *Memory) reclassify(id, from string, to RecordKind) (string, error) {
+	var content, ctx, scope, scopePath, sessionID, source string
+	var createdAt time.Time
+	switch from {
+	case "idea":
+		r, err := m.GetIdea(id)
+		if err != nil {
+			return "", err
+		}
+		content, ctx, scope, scopePath, sessionID, source, createdAt = r.Content, r.Context, r.Scope, r.ScopePath, r.SessionID, r.Source, r.CreatedAt
+	case "decision":
+		r, err := m.GetDecision(id)
+		if err != nil {
+			return "", err
+		}
+		content, ctx, scope, scopePath, sessionID, source, createdAt = r.Content, r.Context, r.Scope, r.ScopePath, r.SessionID, r.Source, r.CreatedAt
+	case "learning":
+		r, err := m.GetLearning(id)
+		if err != nil {
+			return "", err
+		}
+		content, scope, scopePath, sessionID, source, createdAt = r.Content, r.Scope, r.ScopePath, r.SessionID, r.Source, r.CreatedAt
+	default:
+		return "", fmt.Errorf("unknown record kind %q", from)
+	}
+
+	tags, _ := m.GetTags(id, from)
+	links, _ := m.GetAllLinksFor(id)
+
+	var newID string
+	var err error
+	switch to {
+	case RecordKindIdea:
+		newID, err = m.AddIdea(Idea{Content: content, Context: ctx, Scope: scope, ScopePath: scopePath,
+			SessionID: sessionID, Source: source, CreatedAt: createdAt})
+	case RecordKindDecision:
+		newID, err = m.AddDecision(Decision{Content: content, Context: ctx, Scope: scope, ScopePath: scopePath,
+			SessionID: sessionID, Source: source, CreatedAt: createdAt})
+	case RecordKindLearning:
+		newID, err = m.AddLearning(Learning{Content: content, Scope: scope, ScopePath: scopePath,
+			SessionID: sessionID, Source: source, CreatedAt: createdAt})
+	}
+	if err != nil {
+		return "", fmt.Errorf("store as %s: %w", to, err)
+	}
+
+	if len(tags) > 0 {
+		if err := m.SetTags(newID, string(to), tags); err != nil {
+			return "", err
+		}
+	}
+	for _, l := range links {
+		l.ID = ""
+		if l.SourceID == id {
+			l.SourceID, l.SourceKind = newID, string(to)
+		}
+		if l.TargetID == id {
+			l.TargetID, l.TargetKind = newID, string(to)
+		}
+		_, _ = m.addLink(l)
+	}
+
+	if err := moveIdempotencyKeys(m.db, id, newID, string(to)); err != nil {
+		return "", err
+	}
+	if err := m.deleteReclassified(from, id); err != nil {
+		return "", err
+	}
+	return newID, nil
+}
+
+func (m *Memory) deleteReclassified(kind, id string) error {
+	switch kind {
+	case "idea":
+		return m.DeleteIdea(id)
+	case "decision":
+		return m.DeleteDecision(id)
+	case "learning":
+		return m.DeleteLearning(id)
+	}
+	return fmt.Errorf("unknown record kind %q", kind)
+}
+
+// leadingPhrase returns the first n words of text (all of them if n is
+// negative), lowercased and stripped of punctuation, joined by single spaces.
+func leadingPhrase(text string, n int) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	if n >= 0 && len(words) > n {
+		words = words[:n]
+	}
+	return strings.Join(words, " ")
+}
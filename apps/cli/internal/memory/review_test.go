@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"testing"
+)
+
+func TestReviewQueueAmbiguousStore(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	// No signal matches, so this falls back to an uncertain idea
+	id, kind, c, err := mem.ClassifyAndStore("Connection pool size is capped at twenty #database", "test", "")
+	if err != nil {
+		t.Fatalf("ClassifyAndStore failed: %v", err)
+	}
+	if kind != RecordKindIdea || !c.NeedsConfirmation() {
+		t.Fatalf("expected an uncertain idea, got %s at %.2f", kind, c.Confidence)
+	}
+	if flagged, _ := mem.NeedsReview(id); !flagged {
+		t.Fatal("ambiguous store should set NeedsReview")
+	}
+
+	queue, err := mem.GetReviewQueue(0)
+	if err != nil {
+		t.Fatalf("GetReviewQueue failed: %v", err)
+	}
+	if len(queue) != 1 || queue[0].RecordID != id || queue[0].Content == "" {
+		t.Fatalf("unexpected review queue: %+v", queue)
+	}
+
+	newID, err := mem.ResolveReview(id, RecordKindLearning)
+	if err != nil {
+		t.Fatalf("ResolveReview failed: %v", err)
+	}
+	if newID == id {
+		t.Fatal("correcting the kind should re-store the record under a new ID")
+	}
+	if flagged, _ := mem.NeedsReview(id); flagged {
+		t.Error("review should clear NeedsReview")
+	}
+	if _, err := mem.GetIdea(id); err == nil {
+		t.Error("the misfiled idea should be removed")
+	}
+	l, err := mem.GetLearning(newID)
+	if err != nil || l.Content != "Connection pool size is capped at twenty #database" {
+		t.Fatalf("expected the record as a learning, got %+v, %v", l, err)
+	}
+	if tags, _ := mem.GetTags(newID, "learning"); len(tags) != 1 || tags[0] != "database" {
+		t.Errorf("tags should carry over, got %v", tags)
+	}
+
+	// One correction makes a tentative rule: records phrased the same way are
+	// classified by it but still queued for review
+	c = mem.Classify("Connection pool timeouts default to five seconds")
+	if c.Kind != RecordKindLearning || !c.NeedsConfirmation() {
+		t.Errorf("expected a tentative learning from a single review, got %s at %.2f", c.Kind, c.Confidence)
+	}
+
+	if _, err := mem.ResolveReview(newID, RecordKindLearning); err == nil {
+		t.Error("expected error resolving a record that is not queued")
+	}
+}
+
+func TestReviewRuleGainsConfidenceWhenRepeated(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	review := func(content string, kind RecordKind) {
+		t.Helper()
+		id, _, _, err := mem.ClassifyAndStore(content, "test", "")
+		if err != nil {
+			t.Fatalf("ClassifyAndStore failed: %v", err)
+		}
+		if flagged, _ := mem.NeedsReview(id); !flagged {
+			t.Fatalf("expected %q to be queued for review", content)
+		}
+		if _, err := mem.ResolveReview(id, kind); err != nil {
+			t.Fatalf("ResolveReview failed: %v", err)
+		}
+	}
+
+	review("Connection pool size is capped at twenty", RecordKindLearning)
+	review("Connection pool idle time is one minute", RecordKindLearning)
+	c := mem.Classify("Connection pool timeouts default to five seconds")
+	if c.Kind != RecordKindLearning || c.NeedsConfirmation() {
+		t.Errorf("expected a confident learning after two agreeing reviews, got %s at %.2f", c.Kind, c.Confidence)
+	}
+
+	// A review that disagrees starts the rule over
+	id, kind, _, err := mem.ClassifyAndStore("Connection pool sharding could cut contention", "test", "")
+	if err != nil {
+		t.Fatalf("ClassifyAndStore failed: %v", err)
+	}
+	if _, err := mem.FlagForReview(id, Classification{Kind: kind, Confidence: 0.1}); err != nil {
+		t.Fatalf("FlagForReview failed: %v", err)
+	}
+	if _, err := mem.ResolveReview(id, RecordKindIdea); err != nil {
+		t.Fatalf("ResolveReview failed: %v", err)
+	}
+	c = mem.Classify("Connection pool warmup on boot")
+	if c.Kind != RecordKindIdea || !c.NeedsConfirmation() {
+		t.Errorf("expected a tentative idea after a disagreeing review, got %s at %.2f", c.Kind, c.Confidence)
+	}
+}
+
+func TestFlagForReviewSkipsConfidentClassifications(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	id, _, _, err := mem.ClassifyAndStore("We decided to use Go", "test", "")
+	if err != nil {
+		t.Fatalf("ClassifyAndStore failed: %v", err)
+	}
+	if flagged, _ := mem.NeedsReview(id); flagged {
+		t.Error("confident classification should not be queued for review")
+	}
+}
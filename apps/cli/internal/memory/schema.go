@@ -36,6 +36,24 @@ var migrations = []func(*sql.Tx) error{
 	migrateV6,
 	// Migration 7: Authoritative state views for bounded queries
 	migrateV7,
+	// Migration 8: Idempotency keys for safe store retries
+	migrateV8,
+	// Migration 9: Journal of memory mutations for audit and undo
+	migrateV9,
+	// Migration 10: Classification review queue and learned rules
+	migrateV10,
+	// Migration 11: Spaced-review history for learnings
+	migrateV11,
+	// Migration 12: Recall counts for the forget policy
+	migrateV12,
+	// Migration 13: Recall markers for catching up since the last session
+	migrateV13,
+	// Migration 14: Tagging times, so pinned records keep their pin order
+	migrateV14,
+	// Migration 15: Past versions of edited records
+	migrateV15,
+	// Migration 16: How many reviews confirmed each classification rule
+	migrateV16,
 }
 
 // migrateV0 creates the initial database schema (version 0)
@@ -637,3 +655,137 @@ ORDER BY record_type, scope, scope_path;
 
 	return nil
 }
+
+// migrateV8 adds the idempotency_keys table so retried stores return the original record
+func migrateV8(tx *sql.Tx) error {
+	schema := `
+-- Idempotency keys supplied by clients on store
+-- The memory database is per workspace, so keys are workspace-scoped
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT PRIMARY KEY,
+    record_id TEXT NOT NULL,
+    record_kind TEXT NOT NULL,         -- 'idea', 'decision', 'learning', 'proposal'
+    created_at TEXT NOT NULL
+);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV9 adds the memory_journal table recording mutations for audit and undo
+func migrateV9(tx *sql.Tx) error {
+	schema := `
+-- Journal of memory mutations (store, forget, link, unlink)
+-- Bounded to the most recent entries; undone entries are kept for audit
+CREATE TABLE IF NOT EXISTS memory_journal (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    op TEXT NOT NULL,                  -- 'store', 'forget', 'link', 'unlink'
+    record_kind TEXT NOT NULL,         -- 'idea', 'decision', 'learning', 'proposal', 'link'
+    record_id TEXT NOT NULL,
+    before TEXT DEFAULT '',            -- JSON snapshot before the mutation
+    after TEXT DEFAULT '',             -- JSON snapshot after the mutation
+    created_at TEXT NOT NULL,
+    undone_at TEXT DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_memory_journal_record ON memory_journal(record_id);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV10 adds the classification review queue and the rules learned from reviews
+func migrateV10(tx *sql.Tx) error {
+	schema := `
+-- Records whose auto-classification was too uncertain to trust
+CREATE TABLE IF NOT EXISTS classification_reviews (
+    record_id TEXT PRIMARY KEY,
+    record_kind TEXT NOT NULL,         -- 'idea', 'decision', 'learning'
+    confidence REAL NOT NULL,
+    signals TEXT DEFAULT '[]',         -- JSON array of matched signals
+    created_at TEXT NOT NULL
+);
+
+-- Leading phrases confirmed by review, consulted before the built-in signals
+CREATE TABLE IF NOT EXISTS classification_rules (
+    phrase TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,                -- 'idea', 'decision', 'learning'
+    created_at TEXT NOT NULL
+);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV11 tracks when each learning was last practiced as a review question
+func migrateV11(tx *sql.Tx) error {
+	schema := `
+CREATE TABLE IF NOT EXISTS learning_reviews (
+    learning_id TEXT PRIMARY KEY,
+    last_reviewed_at TEXT NOT NULL,
+    review_count INTEGER DEFAULT 0
+);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV12 adds record_access, which counts how often records are recalled
+// so that 'palace memory gc' can tell never-recalled records apart.
+func migrateV12(tx *sql.Tx) error {
+	schema := `
+CREATE TABLE IF NOT EXISTS record_access (
+    record_id TEXT PRIMARY KEY,
+    record_kind TEXT NOT NULL,
+    access_count INTEGER DEFAULT 0,
+    last_accessed_at TEXT NOT NULL
+);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV13 adds recall_markers, named timestamps recording when recalls
+// last looked at the store, such as the "since last session" marker.
+func migrateV13(tx *sql.Tx) error {
+	schema := `
+CREATE TABLE IF NOT EXISTS recall_markers (
+    name TEXT PRIMARY KEY,
+    seen_at TEXT NOT NULL
+);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV14 records when each tag was applied, which orders the pinboard.
+// Tags applied before this migration have no time and sort first.
+func migrateV14(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE record_tags ADD COLUMN tagged_at TEXT DEFAULT ''`)
+	return err
+}
+
+// migrateV15 adds record_history, the past versions of edited ideas,
+// decisions, and learnings, bounded per record.
+func migrateV15(tx *sql.Tx) error {
+	schema := `
+CREATE TABLE IF NOT EXISTS record_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    record_id TEXT NOT NULL,
+    record_kind TEXT NOT NULL,         -- 'idea', 'decision', 'learning'
+    version INTEGER NOT NULL,          -- 1 for the original, counting up
+    content TEXT NOT NULL,
+    tags TEXT DEFAULT '[]',            -- JSON array
+    replaced_at TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_record_history_version ON record_history(record_id, version);
+`
+	_, err := tx.ExecContext(context.Background(), schema)
+	return err
+}
+
+// migrateV16 counts the reviews that confirmed each classification rule.
+// Rules learned before it count as confirmed once.
+func migrateV16(tx *sql.Tx) error {
+	_, err := tx.ExecContext(context.Background(), `ALTER TABLE classification_rules ADD COLUMN confirmations INTEGER NOT NULL DEFAULT 1`)
+	return err
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+)
+
+// ScopeCount is how many records of each kind are scoped to one room or
+// file, or to the palace.
+type ScopeCount struct {
+	Scope  string         `json:"scope"`
+	Path   string         `json:"path,omitempty"`
+	ByKind map[string]int `json:"byKind"`
+	Total  int            `json:"total"`
+}
+
+// CountByScopePath counts the ideas, decisions, and learnings under each
+// scope and path, ordered by scope and path. Archived and obsolete records
+// are counted too: they still show where thinking went.
+func (m *Memory) CountByScopePath() ([]ScopeCount, error) {
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT scope, scope_path, kind, COUNT(*) FROM (
+			SELECT scope, scope_path, 'idea' AS kind FROM ideas
+			UNION ALL SELECT scope, scope_path, 'decision' FROM decisions
+			UNION ALL SELECT scope, scope_path, 'learning' FROM learnings
+		)
+		GROUP BY scope, scope_path, kind
+		ORDER BY scope, scope_path`)
+	if err != nil {
+		return nil, fmt.Errorf("count records by scope: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []ScopeCount
+	for rows.Next() {
+		var scope, path, kind string
+		var n int
+		if err := rows.Scan(&scope, &path, &kind, &n); err != nil {
+			return nil, fmt.Errorf("scan scope count: %w", err)
+		}
+		if len(counts) == 0 || counts[len(counts)-1].Scope != scope || counts[len(counts)-1].Path != path {
+			counts = append(counts, ScopeCount{Scope: scope, Path: path, ByKind: map[string]int{}})
+		}
+		c := &counts[len(counts)-1]
+		c.ByKind[kind] += n
+		c.Total += n
+	}
+	return counts, rows.Err()
+}
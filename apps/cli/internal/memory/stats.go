@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Stats describes the shape and growth of the knowledge store: its ideas,
+// decisions, and learnings.
+type Stats struct {
+	Total            int            `json:"total"`
+	ByKind           map[string]int `json:"byKind"`
+	ByScope          map[string]int `json:"byScope"`
+	Tags             []TagCount     `json:"tags"`
+	Weekly           []WeekCount    `json:"weekly"`
+	AvgContentLength float64        `json:"avgContentLength"`
+}
+
+// TagCount is how many records carry a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// WeekCount is how many records were created in the week starting on Week,
+// a Monday (YYYY-MM-DD, UTC).
+type WeekCount struct {
+	Week  string `json:"week"`
+	Count int    `json:"count"`
+}
+
+// Stats aggregates the store: records by kind and scope, tag frequencies
+// (most used first), records created per week from the first record's week
+// to the last one's, with empty weeks included so growth reads off
+// directly, and the mean content length in characters. Every kind and
+// scope is present in the maps, with zero counts in an empty store.
+func (m *Memory) Stats() (*Stats, error) {
+	stats := &Stats{
+		ByKind:  map[string]int{"idea": 0, "decision": 0, "learning": 0},
+		ByScope: map[string]int{string(ScopeFile): 0, string(ScopeRoom): 0, string(ScopePalace): 0},
+		Tags:    []TagCount{},
+		Weekly:  []WeekCount{},
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT 'idea', scope, content, created_at FROM ideas
+		UNION ALL SELECT 'decision', scope, content, created_at FROM decisions
+		UNION ALL SELECT 'learning', scope, content, created_at FROM learnings`)
+	if err != nil {
+		return nil, fmt.Errorf("load records: %w", err)
+	}
+	defer rows.Close()
+
+	weeks := make(map[string]int)
+	totalLength := 0
+	for rows.Next() {
+		var kind, scope, content, createdAt string
+		if err := rows.Scan(&kind, &scope, &content, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan record: %w", err)
+		}
+		stats.Total++
+		stats.ByKind[kind]++
+		stats.ByScope[scope]++
+		totalLength += len([]rune(content))
+		if t := parseTimeOrZero(createdAt); !t.IsZero() {
+			weeks[weekStart(t).Format("2006-01-02")]++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if stats.Total > 0 {
+		stats.AvgContentLength = float64(totalLength) / float64(stats.Total)
+	}
+	stats.Weekly = fillWeeks(weeks)
+
+	tagRows, err := m.db.QueryContext(context.Background(), `
+		SELECT tag, COUNT(*) FROM record_tags
+		WHERE record_id IN (SELECT id FROM ideas UNION SELECT id FROM decisions UNION SELECT id FROM learnings)
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag`)
+	if err != nil {
+		return nil, fmt.Errorf("count tags: %w", err)
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var tc TagCount
+		if err := tagRows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		stats.Tags = append(stats.Tags, tc)
+	}
+	return stats, tagRows.Err()
+}
+
+// weekStart returns the Monday, in UTC, of the week containing t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7 // Days since Monday
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// fillWeeks lists the weeks from the earliest to the latest in counts, in
+// order, including the weeks with no records.
+func fillWeeks(counts map[string]int) []WeekCount {
+	if len(counts) == 0 {
+		return []WeekCount{}
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	first, _ := time.Parse("2006-01-02", keys[0])
+	last, _ := time.Parse("2006-01-02", keys[len(keys)-1])
+
+	var weeks []WeekCount
+	for w := first; !w.After(last); w = w.AddDate(0, 0, 7) {
+		key := w.Format("2006-01-02")
+		weeks = append(weeks, WeekCount{Week: key, Count: counts[key]})
+	}
+	return weeks
+}
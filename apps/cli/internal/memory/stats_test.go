@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer mem.Close()
+
+	empty, err := mem.Stats()
+	if err != nil {
+		t.Fatalf("Stats on empty store: %v", err)
+	}
+	if empty.Total != 0 || empty.ByKind["learning"] != 0 || len(empty.Tags) != 0 || len(empty.Weekly) != 0 || empty.AvgContentLength != 0 {
+		t.Errorf("empty store should report zeros, got %+v", empty)
+	}
+
+	l1, _ := mem.AddLearning(Learning{Content: "abcd", Scope: "palace", Confidence: 0.8})
+	l2, _ := mem.AddLearning(Learning{Content: "abcdef", Scope: "file", ScopePath: "a.go", Confidence: 0.8})
+	d1, _ := mem.AddDecision(Decision{Content: "abcdefgh", Scope: "palace"})
+	i1, _ := mem.AddIdea(Idea{Content: "ab", Scope: "room", ScopePath: "api"})
+	for _, r := range []struct {
+		id, kind string
+		tags     []string
+	}{
+		{l1, "learning", []string{"perf", "cache"}},
+		{l2, "learning", []string{"perf"}},
+		{d1, "decision", []string{"perf", "db"}},
+		{i1, "idea", []string{"cache"}},
+	} {
+		if err := mem.SetTags(r.id, r.kind, r.tags); err != nil {
+			t.Fatalf("SetTags: %v", err)
+		}
+	}
+
+	stats, err := mem.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Total != 4 {
+		t.Errorf("Total = %d, want 4", stats.Total)
+	}
+	for kind, want := range map[string]int{"learning": 2, "decision": 1, "idea": 1} {
+		if stats.ByKind[kind] != want {
+			t.Errorf("ByKind[%s] = %d, want %d", kind, stats.ByKind[kind], want)
+		}
+	}
+	for scope, want := range map[string]int{"palace": 2, "file": 1, "room": 1} {
+		if stats.ByScope[scope] != want {
+			t.Errorf("ByScope[%s] = %d, want %d", scope, stats.ByScope[scope], want)
+		}
+	}
+	wantTags := []TagCount{{"perf", 3}, {"cache", 2}, {"db", 1}}
+	if len(stats.Tags) != len(wantTags) {
+		t.Fatalf("Tags = %v, want %v", stats.Tags, wantTags)
+	}
+	for i, tc := range wantTags {
+		if stats.Tags[i] != tc {
+			t.Errorf("Tags[%d] = %v, want %v", i, stats.Tags[i], tc)
+		}
+	}
+	if stats.AvgContentLength != 5 {
+		t.Errorf("AvgContentLength = %v, want 5", stats.AvgContentLength)
+	}
+	thisWeek := weekStart(time.Now()).Format("2006-01-02")
+	if len(stats.Weekly) != 1 || stats.Weekly[0] != (WeekCount{thisWeek, 4}) {
+		t.Errorf("Weekly = %v, want [{%s 4}]", stats.Weekly, thisWeek)
+	}
+}
+
+func TestFillWeeks(t *testing.T) {
+	weeks := fillWeeks(map[string]int{"2026-01-05": 2, "2026-01-26": 1})
+	want := []WeekCount{{"2026-01-05", 2}, {"2026-01-12", 0}, {"2026-01-19", 0}, {"2026-01-26", 1}}
+	if len(weeks) != len(want) {
+		t.Fatalf("fillWeeks = %v, want %v", weeks, want)
+	}
+	for i := range want {
+		if weeks[i] != want[i] {
+			t.Errorf("week %d = %v, want %v", i, weeks[i], want[i])
+		}
+	}
+	if got := weekStart(time.Date(2026, 1, 11, 23, 0, 0, 0, time.UTC)); got.Format("2006-01-02") != "2026-01-05" {
+		t.Errorf("weekStart(Sunday) = %s, want the Monday before", got)
+	}
+}
@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// QueryExpansion controls how recall queries are matched against stored content.
+// When enabled, both the query and the content are normalized through the same
+// light stemmer, and query terms may also match configured synonyms.
+type QueryExpansion struct {
+	Stemming bool                // Match "cache", "cached", and "caching" to one another
+	Synonyms map[string][]string // Optional term -> synonyms map (applied to query terms)
+}
+
+// Enabled reports whether any expansion is configured.
+func (q QueryExpansion) Enabled() bool {
+	return q.Stemming || len(q.Synonyms) > 0
+}
+
+// SetQueryExpansion configures stemming and synonym matching for searches.
+func (m *Memory) SetQueryExpansion(q QueryExpansion) {
+	normalized := QueryExpansion{Stemming: q.Stemming}
+	if len(q.Synonyms) > 0 {
+		normalized.Synonyms = make(map[string][]string, len(q.Synonyms))
+		for term, syns := range q.Synonyms {
+			key := strings.ToLower(strings.TrimSpace(term))
+			for _, s := range syns {
+				normalized.Synonyms[key] = append(normalized.Synonyms[key], strings.ToLower(strings.TrimSpace(s)))
+			}
+		}
+	}
+	m.expansion = normalized
+}
+
+// Stem reduces an English word to a crude root using a small set of suffix rules.
+// It is deliberately conservative: short words are returned unchanged, and the
+// same rules are applied to content and queries so the roots only need to agree.
+func Stem(word string) string {
+	w := strings.ToLower(word)
+	if len(w) <= 3 {
+		return w
+	}
+
+	switch {
+	case strings.HasSuffix(w, "sses"):
+		w = w[:len(w)-2]
+	case strings.HasSuffix(w, "ies") && len(w) > 4:
+		w = w[:len(w)-3] + "y"
+	case strings.HasSuffix(w, "ing") && len(w) > 5:
+		w = undouble(w[:len(w)-3])
+	case strings.HasSuffix(w, "ed") && len(w) > 4:
+		w = undouble(w[:len(w)-2])
+	case strings.HasSuffix(w, "es") && len(w) > 4 && hasSibilantEnding(w[:len(w)-2]):
+		w = w[:len(w)-2]
+	case strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		w = w[:len(w)-1]
+	}
+
+	if strings.HasSuffix(w, "e") && len(w) > 3 {
+		w = w[:len(w)-1]
+	}
+	return w
+}
+
+// undouble collapses a trailing doubled consonant ("runn" -> "run").
+func undouble(w string) string {
+	n := len(w)
+	if n >= 2 && w[n-1] == w[n-2] && !strings.ContainsRune("aeiouls", rune(w[n-1])) {
+		return w[:n-1]
+	}
+	return w
+}
+
+func hasSibilantEnding(w string) bool {
+	return strings.HasSuffix(w, "s") || strings.HasSuffix(w, "x") || strings.HasSuffix(w, "z") ||
+		strings.HasSuffix(w, "ch") || strings.HasSuffix(w, "sh")
+}
+
+// isCodeToken reports whether a token looks like an identifier rather than an
+// English word (snake_case, digits, or internal capitals). Such tokens are never
+// stemmed so "getUsers" or "max_retries" keep their exact form.
+func isCodeToken(tok string) bool {
+	for i, r := range tok {
+		if r == '_' || unicode.IsDigit(r) {
+			return true
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchTokens splits text into words on anything that is not a letter, digit, or underscore.
+func searchTokens(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+// normalizeTerm maps a token to the form used for matching.
+func (q QueryExpansion) normalizeTerm(tok string) string {
+	if isCodeToken(tok) {
+		return strings.ToLower(tok)
+	}
+	if q.Stemming {
+		return Stem(tok)
+	}
+	return strings.ToLower(tok)
+}
+
+// Matches reports whether content satisfies every query term, where a term is
+// satisfied by any content word sharing its normalized form or that of a synonym.
+func (q QueryExpansion) Matches(content, query string) bool {
+	terms := searchTokens(query)
+	if len(terms) == 0 {
+		return false
+	}
+
+	words := make(map[string]bool)
+	for _, tok := range searchTokens(content) {
+		words[q.normalizeTerm(tok)] = true
+	}
+
+	for _, term := range terms {
+		matched := words[q.normalizeTerm(term)]
+		if !matched {
+			for _, syn := range q.Synonyms[strings.ToLower(term)] {
+				if words[q.normalizeTerm(syn)] {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchScore rates how closely content matches query as the share of content
+// words that satisfy a query term, so a short record that is about the query
+// outscores a long one that mentions it in passing. A term boosted with
+// term^factor (see SplitTermBoosts) counts factor times for each word it
+// matches, which keeps the score between 0 and 1.
+func (q QueryExpansion) MatchScore(content, query string) float64 {
+	rest, boosts, err := SplitTermBoosts(query)
+	if err != nil {
+		rest, boosts = query, nil
+	}
+	terms := make(map[string]float64)
+	weigh := func(term string, weight float64) {
+		for _, tok := range searchTokens(term) {
+			terms[q.normalizeTerm(tok)] = max(terms[q.normalizeTerm(tok)], weight)
+			for _, syn := range q.Synonyms[strings.ToLower(tok)] {
+				terms[q.normalizeTerm(syn)] = max(terms[q.normalizeTerm(syn)], weight)
+			}
+		}
+	}
+	weigh(rest, 1)
+	for term, factor := range boosts {
+		weigh(term, factor)
+	}
+	words := searchTokens(content)
+	if len(terms) == 0 || len(words) == 0 {
+		return 0
+	}
+	matched, unmatched := 0.0, 0
+	for _, tok := range words {
+		if weight, ok := terms[q.normalizeTerm(tok)]; ok {
+			matched += weight
+		} else {
+			unmatched++
+		}
+	}
+	return matched / (matched + float64(unmatched))
+}
+
+// MatchScore rates how closely content matches query with the configured
+// query expansion, from 0 (no query term) to 1 (every word a query term).
+func (m *Memory) MatchScore(content, query string) float64 {
+	return m.expansion.MatchScore(content, query)
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"cache", "cach"},
+		{"cached", "cach"},
+		{"caching", "cach"},
+		{"caches", "cach"},
+		{"running", "run"},
+		{"policies", "policy"},
+		{"classes", "class"},
+		{"api", "api"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestQueryExpansionLeavesCodeTokens(t *testing.T) {
+	q := QueryExpansion{Stemming: true}
+	if q.Matches("call getUsers before rendering", "getUser") {
+		t.Error("camelCase identifiers should not be stemmed into each other")
+	}
+	if !q.Matches("set max_retries to 3", "max_retries") {
+		t.Error("snake_case identifiers should match exactly")
+	}
+}
+
+func TestSearchLearningsStemming(t *testing.T) {
+	tmpDir, _ := os.MkdirTemp("", "stem-test-*")
+	defer os.RemoveAll(tmpDir)
+	mem, _ := Open(tmpDir)
+	defer mem.Close()
+
+	if _, err := mem.AddLearning(Learning{Content: "Avoid caching auth tokens in memory", Confidence: 0.8, Authority: string(AuthorityApproved)}); err != nil {
+		t.Fatalf("AddLearning failed: %v", err)
+	}
+
+	// Stemming off: plain substring search misses the variant
+	results, err := mem.SearchLearnings("cache", 10)
+	if err != nil {
+		t.Fatalf("SearchLearnings failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no match with stemming off, got %d", len(results))
+	}
+
+	// Stemming on: "cache" matches "caching"
+	mem.SetQueryExpansion(QueryExpansion{Stemming: true})
+	results, err = mem.SearchLearnings("cache", 10)
+	if err != nil {
+		t.Fatalf("SearchLearnings failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected 1 match with stemming on, got %d", len(results))
+	}
+}
+
+func TestSearchLearningsSynonyms(t *testing.T) {
+	tmpDir, _ := os.MkdirTemp("", "syn-test-*")
+	defer os.RemoveAll(tmpDir)
+	mem, _ := Open(tmpDir)
+	defer mem.Close()
+
+	mem.AddLearning(Learning{Content: "The database connection must be pooled", Confidence: 0.8, Authority: string(AuthorityApproved)})
+	mem.SetQueryExpansion(QueryExpansion{Synonyms: map[string][]string{"DB": {"database"}}})
+
+	results, _ := mem.SearchLearnings("db", 10)
+	if len(results) != 1 {
+		t.Errorf("Expected synonym match, got %d results", len(results))
+	}
+}
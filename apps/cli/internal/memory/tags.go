@@ -2,11 +2,14 @@ package memory
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 )
 
-// SetTags sets the tags for a record, replacing any existing tags.
+// SetTags sets the tags for a record, replacing any existing tags. Tags the
+// record already had keep the time they were first applied.
 func (m *Memory) SetTags(recordID, recordKind string, tags []string) error {
 	// Start a transaction
 	tx, err := m.db.BeginTx(context.Background(), nil)
@@ -15,6 +18,20 @@ func (m *Memory) SetTags(recordID, recordKind string, tags []string) error {
 	}
 	defer tx.Rollback()
 
+	if err := setTags(tx, recordID, recordKind, tags); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setTags is SetTags within tx.
+func setTags(tx *sql.Tx, recordID, recordKind string, tags []string) error {
+	taggedAt, err := tagTimes(tx, recordID, recordKind)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
 	// Delete existing tags
 	_, err = tx.ExecContext(context.Background(), `DELETE FROM record_tags WHERE record_id = ? AND record_kind = ?`, recordID, recordKind)
 	if err != nil {
@@ -27,14 +44,17 @@ func (m *Memory) SetTags(recordID, recordKind string, tags []string) error {
 		if tag == "" {
 			continue
 		}
-		_, err = tx.ExecContext(context.Background(), `INSERT OR IGNORE INTO record_tags (record_id, record_kind, tag) VALUES (?, ?, ?)`,
-			recordID, recordKind, tag)
+		at, ok := taggedAt[tag]
+		if !ok {
+			at = now
+		}
+		_, err = tx.ExecContext(context.Background(), `INSERT OR IGNORE INTO record_tags (record_id, record_kind, tag, tagged_at) VALUES (?, ?, ?, ?)`,
+			recordID, recordKind, tag, at)
 		if err != nil {
 			return fmt.Errorf("insert tag: %w", err)
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 // AddTag adds a single tag to a record.
@@ -44,14 +64,34 @@ func (m *Memory) AddTag(recordID, recordKind, tag string) error {
 		return fmt.Errorf("tag cannot be empty")
 	}
 
-	_, err := m.db.ExecContext(context.Background(), `INSERT OR IGNORE INTO record_tags (record_id, record_kind, tag) VALUES (?, ?, ?)`,
-		recordID, recordKind, tag)
+	_, err := m.db.ExecContext(context.Background(), `INSERT OR IGNORE INTO record_tags (record_id, record_kind, tag, tagged_at) VALUES (?, ?, ?, ?)`,
+		recordID, recordKind, tag, time.Now().UTC().Format(time.RFC3339))
 	if err != nil {
 		return fmt.Errorf("insert tag: %w", err)
 	}
 	return nil
 }
 
+// tagTimes returns when each of a record's tags was applied.
+func tagTimes(tx *sql.Tx, recordID, recordKind string) (map[string]string, error) {
+	rows, err := tx.QueryContext(context.Background(), `SELECT tag, COALESCE(tagged_at, '') FROM record_tags WHERE record_id = ? AND record_kind = ?`,
+		recordID, recordKind)
+	if err != nil {
+		return nil, fmt.Errorf("query tag times: %w", err)
+	}
+	defer rows.Close()
+
+	times := make(map[string]string)
+	for rows.Next() {
+		var tag, at string
+		if err := rows.Scan(&tag, &at); err != nil {
+			return nil, fmt.Errorf("scan tag time: %w", err)
+		}
+		times[tag] = at
+	}
+	return times, rows.Err()
+}
+
 // RemoveTag removes a single tag from a record.
 func (m *Memory) RemoveTag(recordID, recordKind, tag string) error {
 	tag = normalizeTag(tag)
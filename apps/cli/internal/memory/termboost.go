@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SplitTermBoosts takes the boosted terms out of a recall query. A term
+// written term^factor, as in "redis^2 caching", weighs factor times as much
+// as other terms in MatchScore but does not have to match: it ranks records
+// mentioning it higher without leaving out those that do not. It returns
+// the rest of the query, to filter records by, and the factor of each
+// boosted term, keyed in lower case. A ^ inside a quoted phrase is part of
+// the phrase; a factor that is not a positive number is an error.
+func SplitTermBoosts(query string) (string, map[string]float64, error) {
+	var rest []string
+	var boosts map[string]float64
+	inPhrase := false
+	for _, field := range strings.Fields(query) {
+		quoted := inPhrase || strings.Contains(field, `"`)
+		if strings.Count(field, `"`)%2 == 1 {
+			inPhrase = !inPhrase
+		}
+		idx := strings.LastIndex(field, "^")
+		if quoted || idx < 0 {
+			rest = append(rest, field)
+			continue
+		}
+		term, raw := field[:idx], field[idx+1:]
+		factor, err := strconv.ParseFloat(raw, 64)
+		if term == "" || strings.ContainsAny(term, "()") || err != nil || factor <= 0 {
+			return "", nil, fmt.Errorf("invalid boost %q (use term^factor with a positive factor, e.g. redis^2)", field)
+		}
+		if boosts == nil {
+			boosts = make(map[string]float64)
+		}
+		boosts[strings.ToLower(term)] = factor
+	}
+	return strings.Join(rest, " "), boosts, nil
+}
+
+// BoostedTermsQuery returns a boolean query matching any of the boosted
+// terms, for a recall query made of nothing else. The terms are in lower
+// case, so none is read as an operator.
+func BoostedTermsQuery(boosts map[string]float64) (*BoolQuery, error) {
+	terms := make([]string, 0, len(boosts))
+	for term := range boosts {
+		terms = append(terms, term)
+	}
+	slices.Sort(terms)
+	return ParseBoolQuery(strings.Join(terms, " OR "))
+}
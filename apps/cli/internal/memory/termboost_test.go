@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitTermBoosts(t *testing.T) {
+	tests := []struct {
+		query  string
+		rest   string
+		boosts map[string]float64
+	}{
+		{"caching", "caching", nil},
+		{"Redis^2 caching", "caching", map[string]float64{"redis": 2}},
+		{"redis^1.5 memcached^3", "", map[string]float64{"redis": 1.5, "memcached": 3}},
+		{`"x^2 y" z^2`, `"x^2 y"`, map[string]float64{"z": 2}},
+	}
+	for _, tt := range tests {
+		rest, boosts, err := SplitTermBoosts(tt.query)
+		if err != nil {
+			t.Errorf("SplitTermBoosts(%q): %v", tt.query, err)
+			continue
+		}
+		if rest != tt.rest || !reflect.DeepEqual(boosts, tt.boosts) {
+			t.Errorf("SplitTermBoosts(%q) = %q, %v; want %q, %v", tt.query, rest, boosts, tt.rest, tt.boosts)
+		}
+	}
+
+	for _, query := range []string{"redis^", "redis^x", "redis^0", "^2", "(redis^2)"} {
+		if _, _, err := SplitTermBoosts(query); err == nil {
+			t.Errorf("SplitTermBoosts(%q) should fail", query)
+		}
+	}
+}
+
+func TestMatchScoreBoostedTerms(t *testing.T) {
+	var q QueryExpansion
+	content := "caching layer backed by redis"
+	plain := q.MatchScore(content, "caching redis")
+	boosted := q.MatchScore(content, "caching redis^4")
+	if plain != 0.4 {
+		t.Errorf("MatchScore without boosts = %v, want 0.4", plain)
+	}
+	// caching counts once and redis four times, against three other words
+	if boosted != 5.0/8 {
+		t.Errorf("MatchScore with redis^4 = %v, want %v", boosted, 5.0/8)
+	}
+	if got := q.MatchScore("postgres tuning", "redis^4"); got != 0 {
+		t.Errorf("MatchScore without a matching term = %v, want 0", got)
+	}
+}
@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TimelinePeriod groups the records created within one day or week.
+type TimelinePeriod struct {
+	Start     time.Time  `json:"start"`
+	End       time.Time  `json:"end"` // Exclusive
+	Decisions []Decision `json:"decisions"`
+	Learnings []Learning `json:"learnings"`
+	Ideas     []Idea     `json:"ideas"`
+}
+
+// Total returns the number of records in p.
+func (p *TimelinePeriod) Total() int {
+	return len(p.Decisions) + len(p.Learnings) + len(p.Ideas)
+}
+
+// Timeline is the records created within a time range, by period, oldest
+// first. Periods without records are left out.
+type Timeline struct {
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Weekly  bool             `json:"weekly"`
+	Periods []TimelinePeriod `json:"periods"`
+	// Truncated is set when a kind had more records in the range than the
+	// limit; only the most recent were kept.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Total returns the number of records in t.
+func (t *Timeline) Total() int {
+	total := 0
+	for i := range t.Periods {
+		total += t.Periods[i].Total()
+	}
+	return total
+}
+
+// Timeline returns the decisions, learnings, and ideas created from from up
+// to to, grouped by UTC day, or by week starting Monday when weekly is set,
+// each period oldest first. limit caps each kind, keeping the most recent;
+// 0 means no limit. As in recall, only authoritative decisions and
+// learnings are included.
+func (m *Memory) Timeline(from, to time.Time, weekly bool, limit int) (*Timeline, error) {
+	if !to.After(from) {
+		return nil, errors.New("timeline range is empty: the end must be after the start")
+	}
+	t := &Timeline{From: from, To: to, Weekly: weekly, Periods: []TimelinePeriod{}}
+	periods := make(map[time.Time]*TimelinePeriod)
+	period := func(at time.Time) *TimelinePeriod {
+		start := at.UTC()
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		end := start.AddDate(0, 0, 1)
+		if weekly {
+			start = weekStart(at)
+			end = start.AddDate(0, 0, 7)
+		}
+		p := periods[start]
+		if p == nil {
+			p = &TimelinePeriod{Start: start, End: end, Decisions: []Decision{}, Learnings: []Learning{}, Ideas: []Idea{}}
+			periods[start] = p
+		}
+		return p
+	}
+
+	// created_at is stored as RFC 3339 in UTC, so the bounds are formatted
+	// the same way to compare as strings. Stored times have whole seconds:
+	// a fractional end is rounded up so the second it falls in is kept.
+	lo := from.UTC().Truncate(time.Second)
+	hi := to.UTC()
+	if hi.Truncate(time.Second).Before(hi) {
+		hi = hi.Truncate(time.Second).Add(time.Second)
+	}
+	rangeArgs := []interface{}{lo.Format(time.RFC3339), hi.Format(time.RFC3339)}
+	limitClause := ""
+	if limit > 0 {
+		limitClause = ` LIMIT ?`
+		rangeArgs = append(rangeArgs, limit+1)
+	}
+	authVals := AuthoritativeValuesStrings()
+	args := make([]interface{}, 0, len(authVals)+len(rangeArgs))
+	for _, v := range authVals {
+		args = append(args, v)
+	}
+	args = append(args, rangeArgs...)
+
+	rows, err := m.db.QueryContext(context.Background(), `
+		SELECT id, content, rationale, context, status, outcome, outcome_note, outcome_at, scope, scope_path, session_id, source, authority, promoted_from_proposal_id, created_at, updated_at
+		FROM decisions
+		WHERE authority IN (`+SQLPlaceholders(len(authVals))+`) AND created_at >= ? AND created_at < ?
+		ORDER BY created_at DESC`+limitClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query decisions: %w", err)
+	}
+	var decisions []Decision
+	for rows.Next() {
+		var dec Decision
+		var createdAt, updatedAt, outcomeAt string
+		if err := rows.Scan(&dec.ID, &dec.Content, &dec.Rationale, &dec.Context, &dec.Status, &dec.Outcome,
+			&dec.OutcomeNote, &outcomeAt, &dec.Scope, &dec.ScopePath, &dec.SessionID, &dec.Source, &dec.Authority, &dec.PromotedFromProposalID, &createdAt, &updatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan decision: %w", err)
+		}
+		dec.CreatedAt = parseTimeOrZero(createdAt)
+		dec.UpdatedAt = parseTimeOrZero(updatedAt)
+		dec.OutcomeAt = parseTimeOrZero(outcomeAt)
+		decisions = append(decisions, dec)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query decisions: %w", err)
+	}
+	if limit > 0 && len(decisions) > limit {
+		decisions, t.Truncated = decisions[:limit], true
+	}
+	for i := len(decisions) - 1; i >= 0; i-- {
+		p := period(decisions[i].CreatedAt)
+		p.Decisions = append(p.Decisions, decisions[i])
+	}
+
+	rows, err = m.db.QueryContext(context.Background(), `
+		SELECT id, session_id, scope, scope_path, content, confidence, source, authority, promoted_from_proposal_id, created_at, last_used, use_count
+		FROM learnings
+		WHERE authority IN (`+SQLPlaceholders(len(authVals))+`) AND created_at >= ? AND created_at < ?
+		ORDER BY created_at DESC`+limitClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query learnings: %w", err)
+	}
+	var learnings []Learning
+	for rows.Next() {
+		var l Learning
+		var createdAt, lastUsed string
+		if err := rows.Scan(&l.ID, &l.SessionID, &l.Scope, &l.ScopePath, &l.Content, &l.Confidence,
+			&l.Source, &l.Authority, &l.PromotedFromProposalID, &createdAt, &lastUsed, &l.UseCount); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan learning: %w", err)
+		}
+		l.CreatedAt = parseTimeOrZero(createdAt)
+		l.LastUsed = parseTimeOrZero(lastUsed)
+		learnings = append(learnings, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query learnings: %w", err)
+	}
+	if limit > 0 && len(learnings) > limit {
+		learnings, t.Truncated = learnings[:limit], true
+	}
+	for i := len(learnings) - 1; i >= 0; i-- {
+		p := period(learnings[i].CreatedAt)
+		p.Learnings = append(p.Learnings, learnings[i])
+	}
+
+	rows, err = m.db.QueryContext(context.Background(), `
+		SELECT id, content, context, status, scope, scope_path, session_id, source, created_at, updated_at
+		FROM ideas
+		WHERE created_at >= ? AND created_at < ?
+		ORDER BY created_at DESC`+limitClause, rangeArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("query ideas: %w", err)
+	}
+	var ideas []Idea
+	for rows.Next() {
+		var idea Idea
+		var createdAt, updatedAt string
+		if err := rows.Scan(&idea.ID, &idea.Content, &idea.Context, &idea.Status, &idea.Scope, &idea.ScopePath,
+			&idea.SessionID, &idea.Source, &createdAt, &updatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan idea: %w", err)
+		}
+		idea.CreatedAt = parseTimeOrZero(createdAt)
+		idea.UpdatedAt = parseTimeOrZero(updatedAt)
+		ideas = append(ideas, idea)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query ideas: %w", err)
+	}
+	if limit > 0 && len(ideas) > limit {
+		ideas, t.Truncated = ideas[:limit], true
+	}
+	for i := len(ideas) - 1; i >= 0; i-- {
+		p := period(ideas[i].CreatedAt)
+		p.Ideas = append(p.Ideas, ideas[i])
+	}
+
+	for _, p := range periods {
+		t.Periods = append(t.Periods, *p)
+	}
+	sort.Slice(t.Periods, func(i, j int) bool { return t.Periods[i].Start.Before(t.Periods[j].Start) })
+	return t, nil
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	mem, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open memory: %v", err)
+	}
+	defer mem.Close()
+
+	// Records and bounds in different zones: the timeline compares instants
+	plus5 := time.FixedZone("UTC+5", 5*60*60)
+	minus7 := time.FixedZone("UTC-7", -7*60*60)
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	for i, content := range []string{"Cache rendered pages", "Batch webhook deliveries", "Shard the index"} {
+		at := day.Add(time.Duration(9+i) * time.Hour).In(plus5)
+		if _, err := mem.AddIdea(Idea{Content: content, CreatedAt: at, UpdatedAt: at}); err != nil {
+			t.Fatalf("AddIdea failed: %v", err)
+		}
+	}
+	at := day.Add(12 * time.Hour).In(plus5)
+	if _, err := mem.AddDecision(Decision{Content: "Use SQLite for the index", Authority: string(AuthorityApproved), CreatedAt: at, UpdatedAt: at}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+	if _, err := mem.AddDecision(Decision{Content: "Maybe drop the cache", CreatedAt: at, UpdatedAt: at}); err != nil {
+		t.Fatalf("AddDecision failed: %v", err)
+	}
+
+	// 09:30 UTC to 11:00 UTC, given as local times in UTC-7
+	from := day.Add(9*time.Hour + 30*time.Minute).In(minus7)
+	to := day.Add(11 * time.Hour).In(minus7)
+	tl, err := mem.Timeline(from, to, false, 0)
+	if err != nil {
+		t.Fatalf("Timeline failed: %v", err)
+	}
+	if tl.Total() != 1 || len(tl.Periods) != 1 || tl.Periods[0].Ideas[0].Content != "Batch webhook deliveries" {
+		t.Fatalf("Timeline(09:30-11:00 UTC) = %+v, want only the 10:00 idea", tl)
+	}
+
+	tl, err = mem.Timeline(day, day.AddDate(0, 0, 1), false, 2)
+	if err != nil {
+		t.Fatalf("Timeline failed: %v", err)
+	}
+	if len(tl.Periods) != 1 || !tl.Periods[0].Start.Equal(day) {
+		t.Fatalf("Timeline periods = %+v, want one period for %s", tl.Periods, day)
+	}
+	p := tl.Periods[0]
+	if len(p.Decisions) != 1 || p.Decisions[0].Content != "Use SQLite for the index" {
+		t.Errorf("decisions = %+v, want only the approved one", p.Decisions)
+	}
+	if len(p.Ideas) != 2 || p.Ideas[0].Content != "Batch webhook deliveries" || p.Ideas[1].Content != "Shard the index" {
+		t.Errorf("ideas = %+v, want the two most recent, oldest first", p.Ideas)
+	}
+	if !tl.Truncated {
+		t.Error("Truncated should be set when a kind exceeds the limit")
+	}
+
+	if _, err := mem.Timeline(day, day, false, 0); err == nil {
+		t.Error("Timeline with an empty range should fail")
+	}
+}
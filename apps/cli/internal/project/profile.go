@@ -1,8 +1,11 @@
 package project
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
@@ -116,12 +119,121 @@ func detectLanguages(root string) []string {
 		langs = append(langs, "php")
 	}
 
+	// Without a manifest, fall back to the source files themselves
+	if len(langs) == 0 {
+		langs = detectLanguagesFromSources(root)
+	}
+
 	if len(langs) == 0 {
 		langs = append(langs, "unknown")
 	}
 	return langs
 }
 
+// sourceLanguages maps source file extensions to profile language names.
+var sourceLanguages = map[string]string{
+	".go":    "go",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".ts":    "javascript",
+	".tsx":   "javascript",
+	".dart":  "dart",
+	".rs":    "rust",
+	".py":    "python",
+	".rb":    "ruby",
+	".java":  "java",
+	".kt":    "java",
+	".cs":    "csharp",
+	".swift": "swift",
+	".php":   "php",
+}
+
+// maxDetectFiles bounds the walk in detectLanguagesFromSources so init stays
+// fast on large trees.
+const maxDetectFiles = 5000
+
+// detectLanguagesFromSources counts source files by extension and returns the
+// languages found, most common first. Hidden and dependency directories are skipped.
+func detectLanguagesFromSources(root string) []string {
+	counts := map[string]int{}
+	visited := 0
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		visited++
+		if visited > maxDetectFiles {
+			return filepath.SkipAll
+		}
+		if lang, ok := sourceLanguages[strings.ToLower(filepath.Ext(path))]; ok {
+			counts[lang]++
+		}
+		return nil
+	})
+
+	langs := make([]string, 0, len(counts))
+	for lang := range counts {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if counts[langs[i]] != counts[langs[j]] {
+			return counts[langs[i]] > counts[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+	return langs
+}
+
+// languageIgnoreGlobs lists per-language caches and environments that are
+// not covered by the default guardrails.
+var languageIgnoreGlobs = map[string][]string{
+	"python": {"**/__pycache__/**", ".venv/**", "venv/**", ".tox/**", ".mypy_cache/**", ".pytest_cache/**", "**/*.egg-info/**"},
+	"ruby":   {".bundle/**"},
+	"csharp": {"**/bin/**", "**/obj/**"},
+	"swift":  {".build/**"},
+}
+
+// IgnoreGlobs returns the doNotTouch globs to scaffold into palace.jsonc for
+// the detected languages: a small base set plus each language's caches.
+func IgnoreGlobs(langs []string) []string {
+	globs := []string{".git/**", ".palace/**", "node_modules/**", "vendor/**"}
+	for _, lang := range langs {
+		globs = append(globs, languageIgnoreGlobs[lang]...)
+	}
+	return globs
+}
+
+// languageReadOnlyGlobs lists per-language lockfiles and generated sources
+// that are worth reading but not editing by hand, beyond the *.lock files
+// and generated files the default guardrails already skip.
+var languageReadOnlyGlobs = map[string][]string{
+	"go":         {"go.sum", "**/*.pb.go", "**/zz_generated*.go"},
+	"javascript": {"package-lock.json", "pnpm-lock.yaml"},
+	"python":     {"**/*_pb2.py", "**/*_pb2_grpc.py"},
+	"java":       {"**/generated-sources/**"},
+	"csharp":     {"**/*.Designer.cs"},
+	"swift":      {"Package.resolved"},
+	"ruby":       {"db/schema.rb"},
+}
+
+// ReadOnlyGlobs returns the readOnly globs to scaffold into palace.jsonc for
+// the detected languages: generated directories plus each language's
+// lockfiles and generated sources.
+func ReadOnlyGlobs(langs []string) []string {
+	globs := []string{"**/generated/**"}
+	for _, lang := range langs {
+		globs = append(globs, languageReadOnlyGlobs[lang]...)
+	}
+	return globs
+}
+
 // hasMonorepoSubproject checks common monorepo directories for a specific file
 // This handles patterns like apps/*/pubspec.yaml, packages/*/pubspec.yaml, etc.
 func hasMonorepoSubproject(root, filename string) bool {
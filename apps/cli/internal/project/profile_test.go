@@ -168,6 +168,11 @@ func TestDetectLanguages(t *testing.T) {
 			files:    map[string]string{"setup.py": ""},
 			expected: []string{"python"},
 		},
+		{
+			name:     "Sources without a manifest",
+			files:    map[string]string{"app.py": "", "util.py": "", "tool.go": ""},
+			expected: []string{"python", "go"},
+		},
 	}
 
 	for _, tt := range tests {
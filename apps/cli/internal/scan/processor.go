@@ -0,0 +1,130 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+)
+
+// ScanProcessor runs custom analysis over the results of a full scan, after
+// the index has been written. Processors see every file that was parsed;
+// files without a parser are not included.
+type ScanProcessor interface {
+	Name() string
+	Process(analyses []*analysis.FileAnalysis) error
+}
+
+var (
+	processorsMu sync.Mutex
+	processors   []ScanProcessor
+)
+
+// RegisterProcessor adds a processor that runs after every full scan, in
+// registration order. It returns a function that removes the processor again.
+func RegisterProcessor(p ScanProcessor) (unregister func()) {
+	processorsMu.Lock()
+	defer processorsMu.Unlock()
+	processors = append(processors, p)
+	return func() {
+		processorsMu.Lock()
+		defer processorsMu.Unlock()
+		for i, existing := range processors {
+			if existing == p {
+				processors = append(processors[:i], processors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// builtinProcessors are the processors that can be enabled by name in
+// palace.jsonc ("scan": {"processors": [...]}).
+var builtinProcessors = map[string]func(root string) ScanProcessor{
+	"language-stats": func(root string) ScanProcessor { return &languageStatsProcessor{root: root} },
+}
+
+// runProcessors runs the configured built-in processors followed by the
+// registered ones. The first failure stops the pipeline.
+func runProcessors(root string, analyses []*analysis.FileAnalysis) error {
+	var pipeline []ScanProcessor
+	if cfg, err := config.LoadPalaceConfig(root); err == nil && cfg.Scan != nil {
+		for _, name := range cfg.Scan.Processors {
+			newProcessor, ok := builtinProcessors[name]
+			if !ok {
+				return fmt.Errorf("unknown scan processor %q in palace.jsonc", name)
+			}
+			pipeline = append(pipeline, newProcessor(root))
+		}
+	}
+	processorsMu.Lock()
+	pipeline = append(pipeline, processors...)
+	processorsMu.Unlock()
+
+	for _, p := range pipeline {
+		if err := p.Process(analyses); err != nil {
+			return fmt.Errorf("scan processor %s: %w", p.Name(), err)
+		}
+	}
+	return nil
+}
+
+// LanguageStats counts parsed files, symbols, and relationships for one language.
+type LanguageStats struct {
+	Language      string `json:"language"`
+	Files         int    `json:"files"`
+	Symbols       int    `json:"symbols"`
+	Relationships int    `json:"relationships"`
+}
+
+// languageStatsProcessor writes per-language counts to
+// .palace/outputs/language-stats.json.
+type languageStatsProcessor struct {
+	root string
+}
+
+func (p *languageStatsProcessor) Name() string { return "language-stats" }
+
+func (p *languageStatsProcessor) Process(analyses []*analysis.FileAnalysis) error {
+	byLang := make(map[string]*LanguageStats)
+	for _, fa := range analyses {
+		stats, ok := byLang[fa.Language]
+		if !ok {
+			stats = &LanguageStats{Language: fa.Language}
+			byLang[fa.Language] = stats
+		}
+		stats.Files++
+		stats.Symbols += countSymbols(fa.Symbols)
+		stats.Relationships += len(fa.Relationships)
+	}
+
+	result := make([]LanguageStats, 0, len(byLang))
+	for _, stats := range byLang {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Files != result[j].Files {
+			return result[i].Files > result[j].Files
+		}
+		return result[i].Language < result[j].Language
+	})
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(p.root, ".palace", "outputs", "language-stats.json"), data, 0o600)
+}
+
+func countSymbols(symbols []analysis.Symbol) int {
+	n := len(symbols)
+	for i := range symbols {
+		n += countSymbols(symbols[i].Children)
+	}
+	return n
+}
@@ -0,0 +1,106 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/gitutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+)
+
+// RunRepair rebuilds the index of the workspace at root from scratch, for
+// when the persisted index is corrupt or out of step with the sources. The
+// old index is never read: every file is scanned into a fresh database next
+// to it, which is checked with index.VerifyScan and only then renamed over
+// the old one. If the rebuild fails or is cancelled, the old index is left
+// as it was. The memory store is a separate database and is not touched.
+func RunRepair(ctx context.Context, root string) (index.ScanSummary, int, error) {
+	rootPath, err := resolveAndValidateRoot(root)
+	if err != nil {
+		return index.ScanSummary{}, 0, err
+	}
+
+	if _, err := config.EnsureLayout(rootPath); err != nil {
+		return index.ScanSummary{}, 0, err
+	}
+
+	guardrails := config.LoadGuardrails(rootPath)
+	if err := ApplyParserLimits(rootPath); err != nil {
+		return index.ScanSummary{}, 0, err
+	}
+	startedAt := time.Now().UTC()
+
+	records, err := index.BuildFileRecordsContext(ctx, rootPath, guardrails)
+	if err != nil {
+		return index.ScanSummary{}, 0, fmt.Errorf("rebuild index: %w", err)
+	}
+
+	var commitHash string
+	if gitutil.IsGitRepo(rootPath) {
+		commitHash, _ = gitutil.GetHeadCommit(rootPath)
+	}
+
+	dbPath := config.IndexDBPath(rootPath)
+	tmpPath := dbPath + ".repair"
+	removeDB(tmpPath)
+	summary, err := buildVerifiedIndex(tmpPath, rootPath, records, startedAt, commitHash)
+	if err != nil {
+		removeDB(tmpPath)
+		return index.ScanSummary{}, 0, err
+	}
+
+	// The rename swaps the file atomically. Only then do the journal files
+	// of the old index go, since a write-ahead log left behind would be
+	// replayed onto the new one; were they removed first, a failed rename
+	// would leave the old index without its log.
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		removeDB(tmpPath)
+		return index.ScanSummary{}, 0, fmt.Errorf("replace index: %w", err)
+	}
+	removeJournals(dbPath)
+
+	if err := writeScanArtifact(rootPath, summary); err != nil {
+		return index.ScanSummary{}, 0, err
+	}
+	if err := runProcessors(rootPath, recordAnalyses(records)); err != nil {
+		return summary, len(records), err
+	}
+	return summary, len(records), nil
+}
+
+// buildVerifiedIndex writes records into a new index database at dbPath and
+// verifies it. The database is left in rollback-journal mode and closed, so
+// that the single file at dbPath holds all of it.
+func buildVerifiedIndex(dbPath, rootPath string, records []index.FileRecord, startedAt time.Time, commitHash string) (index.ScanSummary, error) {
+	db, err := index.Open(dbPath)
+	if err != nil {
+		return index.ScanSummary{}, fmt.Errorf("create index: %w", err)
+	}
+	summary, err := index.WriteScanWithOptions(db, rootPath, records, startedAt, index.WriteScanOptions{CommitHash: commitHash})
+	if err == nil {
+		if err = index.VerifyScan(db, summary); err != nil {
+			err = fmt.Errorf("verify rebuilt index: %w", err)
+		}
+	}
+	if err == nil {
+		_, err = db.ExecContext(context.Background(), "PRAGMA journal_mode=DELETE;")
+	}
+	return summary, errors.Join(err, db.Close())
+}
+
+// removeDB removes the SQLite database at path with its journal files.
+func removeDB(path string) {
+	_ = os.Remove(path)
+	removeJournals(path)
+}
+
+// removeJournals removes the journal files of the SQLite database at path.
+func removeJournals(path string) {
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		_ = os.Remove(path + suffix)
+	}
+}
@@ -0,0 +1,149 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/memory"
+)
+
+// writeRepairFixture writes n Go files, each calling the next, and returns
+// the path of the index database.
+func writeRepairFixture(t *testing.T, dir string, n int) string {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		src := fmt.Sprintf("package main\n\nfunc f%d() {\n\tf%d()\n}\n", i, (i+1)%n)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, ".palace", "index", "palace.db")
+}
+
+// indexCounts returns the number of files, symbols, and relationships in
+// the index at dbPath, after checking its integrity.
+func indexCounts(t *testing.T, dbPath string) [3]int {
+	t.Helper()
+	db, err := index.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer db.Close()
+	var integrity string
+	if err := db.QueryRowContext(context.Background(), "PRAGMA integrity_check").Scan(&integrity); err != nil || integrity != "ok" {
+		t.Fatalf("integrity_check = %q, %v", integrity, err)
+	}
+	var counts [3]int
+	for i, table := range []string{"files", "symbols", "relationships"} {
+		if err := db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM "+table).Scan(&counts[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return counts
+}
+
+func TestRunRepairRebuildsCorruptIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := writeRepairFixture(t, tmpDir, 5)
+	if _, _, err := Run(tmpDir); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := indexCounts(t, dbPath)
+	if want[0] != 5 || want[1] == 0 || want[2] == 0 {
+		t.Fatalf("unexpected initial index: %v", want)
+	}
+
+	mem, err := memory.Open(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := mem.AddLearning(memory.Learning{Content: "survives repair"})
+	mem.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		os.Remove(dbPath + suffix)
+	}
+	if err := os.WriteFile(dbPath, []byte("NOT A SQLITE DB"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.Open(dbPath); err == nil {
+		t.Fatal("corrupted index should not open")
+	}
+
+	summary, count, err := RunRepair(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("RunRepair: %v", err)
+	}
+	if count != 5 || summary.SymbolCount != want[1] || summary.RelationshipCount != want[2] {
+		t.Errorf("repair summary: %d files, %+v", count, summary)
+	}
+	if got := indexCounts(t, dbPath); got != want {
+		t.Errorf("repaired index holds %v, want %v", got, want)
+	}
+	if _, err := os.Stat(dbPath + ".repair"); !os.IsNotExist(err) {
+		t.Errorf("temporary index should be gone, stat: %v", err)
+	}
+
+	mem, err = memory.Open(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	if l, err := mem.GetLearning(id); err != nil || l.Content != "survives repair" {
+		t.Errorf("memory should be untouched, got %+v, %v", l, err)
+	}
+}
+
+func TestRunRepairCancelledKeepsOldIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := writeRepairFixture(t, tmpDir, 10)
+	if _, _, err := Run(tmpDir); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := indexCounts(t, dbPath)
+
+	if _, _, err := RunRepair(newCountdownContext(3), tmpDir); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a context.Canceled error, got %v", err)
+	}
+	if got := indexCounts(t, dbPath); got != want {
+		t.Errorf("old index should be kept, holds %v, want %v", got, want)
+	}
+	if _, err := os.Stat(dbPath + ".repair"); !os.IsNotExist(err) {
+		t.Errorf("temporary index should not be left behind, stat: %v", err)
+	}
+}
+
+func TestRunRepairFailedRenameKeepsJournal(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := writeRepairFixture(t, tmpDir, 3)
+	if _, _, err := Run(tmpDir); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// A non-empty directory in place of the index cannot be renamed over.
+	removeDB(dbPath)
+	if err := os.MkdirAll(filepath.Join(dbPath, "busy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dbPath+"-wal", []byte("old log"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := RunRepair(context.Background(), tmpDir); err == nil {
+		t.Fatal("expected the rename to fail")
+	}
+	if _, err := os.Stat(dbPath + "-wal"); err != nil {
+		t.Errorf("the old index's write-ahead log should be kept: %v", err)
+	}
+	if _, err := os.Stat(dbPath + ".repair"); !os.IsNotExist(err) {
+		t.Errorf("temporary index should not be left behind, stat: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package scan
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/gitutil"
@@ -35,18 +37,27 @@ func resolveAndValidateRoot(root string) (string, error) {
 // This function uses hash-based change detection by default.
 // For git-based detection, use RunIncrementalGit.
 func RunIncremental(root string) (index.IncrementalScanSummary, error) {
+	return RunIncrementalContext(context.Background(), root)
+}
+
+// RunIncrementalContext is RunIncremental with cancellation; see
+// index.IncrementalScanContext for what a cancelled scan leaves behind.
+func RunIncrementalContext(ctx context.Context, root string) (index.IncrementalScanSummary, error) {
 	rootPath, err := resolveAndValidateRoot(root)
 	if err != nil {
 		return index.IncrementalScanSummary{}, err
 	}
 
 	// Check if index exists - if not, need full scan
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
 		return index.IncrementalScanSummary{}, fmt.Errorf("no index found")
 	}
 
 	guardrails := config.LoadGuardrails(rootPath)
+	if err := ApplyParserLimits(rootPath); err != nil {
+		return index.IncrementalScanSummary{}, err
+	}
 
 	db, err := index.Open(dbPath)
 	if err != nil {
@@ -55,7 +66,9 @@ func RunIncremental(root string) (index.IncrementalScanSummary, error) {
 	defer db.Close()
 
 	// Detect changes
+	stopWalk := index.ScanProfileFrom(ctx).Start(index.PhaseWalk)
 	changes, err := index.DetectChanges(db, rootPath, guardrails)
+	stopWalk()
 	if err != nil {
 		return index.IncrementalScanSummary{}, fmt.Errorf("detect changes: %w", err)
 	}
@@ -78,10 +91,15 @@ func RunIncremental(root string) (index.IncrementalScanSummary, error) {
 	}
 
 	// Apply incremental changes
-	summary, err := index.IncrementalScan(db, rootPath, changes)
+	summary, err := index.IncrementalScanContext(ctx, db, rootPath, changes)
+	if summary.Partial {
+		markPartial(db, true)
+		return summary, fmt.Errorf("incremental scan cancelled: %w", err)
+	}
 	if err != nil {
 		return summary, fmt.Errorf("incremental scan: %w", err)
 	}
+	markPartial(db, false)
 
 	// Calculate unchanged files:
 	// Unchanged = (files that existed before) - (files that were modified) - (files that were deleted)
@@ -105,6 +123,13 @@ func RunIncremental(root string) (index.IncrementalScanSummary, error) {
 // This is faster than hash-based detection for large repositories.
 // Falls back to RunIncremental if not in a git repo or if git diff fails.
 func RunIncrementalGit(root string) (index.IncrementalScanSummary, error) {
+	return RunIncrementalGitContext(context.Background(), root)
+}
+
+// RunIncrementalGitContext is RunIncrementalGit with cancellation. A
+// cancelled scan keeps the previous commit hash, so the files it did not
+// reach are still diffed next time.
+func RunIncrementalGitContext(ctx context.Context, root string) (index.IncrementalScanSummary, error) {
 	rootPath, err := resolveAndValidateRoot(root)
 	if err != nil {
 		return index.IncrementalScanSummary{}, err
@@ -116,7 +141,7 @@ func RunIncrementalGit(root string) (index.IncrementalScanSummary, error) {
 	}
 
 	// Check if index exists - if not, need full scan
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
 		return index.IncrementalScanSummary{}, fmt.Errorf("no index found")
 	}
@@ -134,13 +159,18 @@ func RunIncrementalGit(root string) (index.IncrementalScanSummary, error) {
 	}
 
 	// If no previous scan or no commit hash, fall back to hash-based detection
-	if lastScan.ID == 0 || lastScan.CommitHash == "" {
+	if lastScan.ID == 0 || lastScan.CommitHash == "" || lastScan.Partial {
 		return index.IncrementalScanSummary{}, fmt.Errorf("no previous git-based scan found")
 	}
 
 	// Get changed files from git
 	guardrails := config.LoadGuardrails(rootPath)
+	if err := ApplyParserLimits(rootPath); err != nil {
+		return index.IncrementalScanSummary{}, err
+	}
+	stopWalk := index.ScanProfileFrom(ctx).Start(index.PhaseWalk)
 	added, modified, deleted, err := gitutil.GetChangedFilesSinceCommit(rootPath, lastScan.CommitHash)
+	stopWalk()
 	if err != nil {
 		return index.IncrementalScanSummary{}, fmt.Errorf("git diff: %w", err)
 	}
@@ -180,10 +210,15 @@ func RunIncrementalGit(root string) (index.IncrementalScanSummary, error) {
 	}
 
 	// Apply incremental changes
-	summary, err := index.IncrementalScan(db, rootPath, changes)
+	summary, err := index.IncrementalScanContext(ctx, db, rootPath, changes)
+	if summary.Partial {
+		markPartial(db, true)
+		return summary, fmt.Errorf("incremental scan cancelled: %w", err)
+	}
 	if err != nil {
 		return summary, fmt.Errorf("incremental scan: %w", err)
 	}
+	markPartial(db, false)
 
 	summary.FilesUnchanged = initialCount - summary.FilesModified - summary.FilesDeleted
 
@@ -198,20 +233,59 @@ func RunIncrementalGit(root string) (index.IncrementalScanSummary, error) {
 	return summary, nil
 }
 
-// filterFiles filters a list of file paths based on guardrails.
+// markPartial records on the latest scan whether the index is missing
+// changes because a scan was cancelled.
+func markPartial(db *sql.DB, partial bool) {
+	_, _ = db.ExecContext(context.Background(),
+		"UPDATE scans SET partial = ? WHERE id = (SELECT MAX(id) FROM scans)", partial)
+}
+
+// ApplyParserLimits configures the parsers from the workspace's scan
+// settings: maxNestingDepth, callExclusions, and strictness. Scans call it
+// before parsing, and so must commands that parse the workspace themselves,
+// so that they see the code as the index does.
+func ApplyParserLimits(rootPath string) error {
+	depth := 0
+	var exclusions map[string][]string
+	var strictness map[string]string
+	if cfg, err := config.LoadPalaceConfig(rootPath); err == nil && cfg.Scan != nil {
+		depth = cfg.Scan.MaxNestingDepth
+		exclusions = cfg.Scan.CallExclusions
+		strictness = cfg.Scan.Strictness
+	}
+	analysis.SetMaxNestingDepth(depth)
+	analysis.SetCallExclusions(exclusions)
+	if err := analysis.SetStrictness(strictness); err != nil {
+		return fmt.Errorf("scan.strictness: %w", err)
+	}
+	return nil
+}
+
+// filterFiles filters a list of file paths based on guardrails and the
+// test filter.
 func filterFiles(files []string, rootPath string, guardrails config.Guardrails) []string {
 	var result []string
 	for _, file := range files {
 		// Check if file matches guardrails (should be excluded)
-		if !fsutil.MatchesGuardrail(file, guardrails) {
+		if !fsutil.MatchesGuardrail(file, guardrails) && index.Selected(file) {
 			result = append(result, file)
 		}
 	}
 	return result
 }
 
-// Run performs a full scan of the workspace
+// Run performs a full scan of the workspace, then runs the post-scan
+// processors (see RegisterProcessor) over the parsed files.
 func Run(root string) (index.ScanSummary, int, error) {
+	return RunContext(context.Background(), root)
+}
+
+// RunContext is Run with cancellation. When ctx is cancelled mid-scan the
+// files parsed so far are written as a consistent index whose scan is marked
+// partial, post-scan processors are skipped, and an error wrapping the
+// context's error is returned. If ctx carries an index.ScanProfile, the
+// scan's phases are timed into it.
+func RunContext(ctx context.Context, root string) (index.ScanSummary, int, error) {
 	rootPath, err := resolveAndValidateRoot(root)
 	if err != nil {
 		return index.ScanSummary{}, 0, err
@@ -222,34 +296,65 @@ func Run(root string) (index.ScanSummary, int, error) {
 	}
 
 	guardrails := config.LoadGuardrails(rootPath)
+	if err := ApplyParserLimits(rootPath); err != nil {
+		return index.ScanSummary{}, 0, err
+	}
 	startedAt := time.Now().UTC()
 
-	records, err := index.BuildFileRecords(rootPath, guardrails)
-	if err != nil {
+	records, err := index.BuildFileRecordsContext(ctx, rootPath, guardrails)
+	partial := err != nil && ctx.Err() != nil
+	if err != nil && !partial {
 		return index.ScanSummary{}, 0, err
 	}
+	if partial && len(records) == 0 {
+		// Nothing was parsed; keep the existing index rather than emptying it
+		return index.ScanSummary{}, 0, fmt.Errorf("scan cancelled: %w", err)
+	}
 
-	dbPath := filepath.Join(rootPath, ".palace", "index", "palace.db")
+	dbPath := config.IndexDBPath(rootPath)
 	db, err := index.Open(dbPath)
 	if err != nil {
 		return index.ScanSummary{}, 0, err
 	}
 	defer db.Close()
 
-	// Get current git commit hash if in a git repo
+	// Get current git commit hash if in a git repo. A partial scan records
+	// none, so the next incremental scan falls back to comparing hashes.
 	var commitHash string
-	if gitutil.IsGitRepo(rootPath) {
+	if gitutil.IsGitRepo(rootPath) && !partial {
 		commitHash, _ = gitutil.GetHeadCommit(rootPath)
 	}
 
+	profile := index.ScanProfileFrom(ctx)
+	stopPersist := profile.Start(index.PhasePersist)
 	summary, err := index.WriteScanWithOptions(db, rootPath, records, startedAt, index.WriteScanOptions{
 		CommitHash: commitHash,
+		Partial:    partial,
 	})
+	stopPersist()
 	if err != nil {
 		return index.ScanSummary{}, 0, err
 	}
+	if partial {
+		return summary, len(records), fmt.Errorf("scan cancelled after %d files: %w", len(records), ctx.Err())
+	}
+
+	if err := writeScanArtifact(rootPath, summary); err != nil {
+		return index.ScanSummary{}, 0, err
+	}
+
+	defer profile.Start(index.PhaseProcessors)()
+	if err := runProcessors(rootPath, recordAnalyses(records)); err != nil {
+		return summary, len(records), err
+	}
+
+	return summary, len(records), nil
+}
 
-	scanArtifactPath := filepath.Join(rootPath, ".palace", "index", "scan.json")
+// writeScanArtifact writes the scan.json summary of a completed full scan
+// and validates it against its schema.
+func writeScanArtifact(rootPath string, summary index.ScanSummary) error {
+	scanArtifactPath := filepath.Join(config.ResolveStore(rootPath).IndexDir, "scan.json")
 	now := time.Now().UTC().Format(time.RFC3339)
 	artifact := model.ScanSummary{
 		SchemaVersion:     "1.0.0",
@@ -270,11 +375,19 @@ func Run(root string) (index.ScanSummary, int, error) {
 	}
 
 	if err := model.WriteScanSummary(scanArtifactPath, artifact); err != nil {
-		return index.ScanSummary{}, 0, err
-	}
-	if err := validate.JSON(scanArtifactPath, "scan"); err != nil {
-		return index.ScanSummary{}, 0, err
+		return err
 	}
+	return validate.JSON(scanArtifactPath, "scan")
+}
 
-	return summary, len(records), nil
+// recordAnalyses returns the analyses of the parsed records, for the
+// post-scan processors.
+func recordAnalyses(records []index.FileRecord) []*analysis.FileAnalysis {
+	analyses := make([]*analysis.FileAnalysis, 0, len(records))
+	for _, r := range records {
+		if r.Analysis != nil {
+			analyses = append(analyses, r.Analysis)
+		}
+	}
+	return analyses
 }
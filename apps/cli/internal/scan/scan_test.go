@@ -2,10 +2,18 @@ package scan
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/analysis"
 	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
 )
 
@@ -401,3 +409,188 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+type countingProcessor struct {
+	paths []string
+}
+
+func (p *countingProcessor) Name() string { return "counting" }
+
+func (p *countingProcessor) Process(analyses []*analysis.FileAnalysis) error {
+	for _, fa := range analyses {
+		p.paths = append(p.paths, fa.Path)
+	}
+	return nil
+}
+
+func TestRunInvokesProcessors(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"main.go":        "package main\n\nfunc main() {}\n",
+		"util/util.go":   "package util\n\nfunc Help() {}\n",
+		"scripts/a.py":   "def run():\n    pass\n",
+		"pyproject.toml": "[project]\nname = \"demo\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counter := &countingProcessor{}
+	unregister := RegisterProcessor(counter)
+	defer unregister()
+
+	if _, _, err := Run(tmpDir); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	sort.Strings(counter.paths)
+	want := []string{"main.go", "pyproject.toml", "scripts/a.py", "util/util.go"}
+	if strings.Join(counter.paths, ",") != strings.Join(want, ",") {
+		t.Errorf("processor saw %v, want %v", counter.paths, want)
+	}
+}
+
+func TestRunBuiltinProcessorFromConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".palace"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"schemaVersion": "1.0.0", "kind": "palace/config", "scan": {"processors": ["language-stats"]}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".palace", "palace.jsonc"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Run(tmpDir); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".palace", "outputs", "language-stats.json"))
+	if err != nil {
+		t.Fatalf("expected language-stats.json: %v", err)
+	}
+	var stats []LanguageStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Language != "go" || stats[0].Files != 1 || stats[0].Symbols != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+// countdownContext reports itself cancelled once Err has been checked n times,
+// which cancels a scan after a deterministic number of files.
+type countdownContext struct {
+	context.Context
+	remaining atomic.Int32
+}
+
+func newCountdownContext(n int32) *countdownContext {
+	ctx := &countdownContext{Context: context.Background()}
+	ctx.remaining.Store(n)
+	return ctx
+}
+
+func (c *countdownContext) Err() error {
+	if c.remaining.Add(-1) < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestRunContextCancelledMidScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	for i := 0; i < 10; i++ {
+		src := fmt.Sprintf("package main\n\nfunc f%d() {}\n", i)
+		if err := os.WriteFile(filepath.Join(tmpDir, fmt.Sprintf("f%d.go", i)), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	summary, count, err := RunContext(newCountdownContext(3), tmpDir)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a context.Canceled error, got %v", err)
+	}
+	if count != 3 || !summary.Partial {
+		t.Fatalf("expected a partial scan of 3 files, got %d files (partial=%v)", count, summary.Partial)
+	}
+
+	db, err := index.Open(filepath.Join(tmpDir, ".palace", "index", "palace.db"))
+	if err != nil {
+		t.Fatalf("index should open after a cancelled scan: %v", err)
+	}
+	defer db.Close()
+	var integrity string
+	if err := db.QueryRowContext(context.Background(), "PRAGMA integrity_check").Scan(&integrity); err != nil || integrity != "ok" {
+		t.Fatalf("integrity_check = %q, %v", integrity, err)
+	}
+	var files, symbols int
+	if err := db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM files").Scan(&files); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM symbols").Scan(&symbols); err != nil {
+		t.Fatal(err)
+	}
+	if files != 3 || symbols != 3 {
+		t.Errorf("expected 3 files with their symbols, got %d files and %d symbols", files, symbols)
+	}
+	if latest, err := index.LatestScan(db); err != nil || !latest.Partial {
+		t.Errorf("latest scan should be marked partial, got %+v, %v", latest, err)
+	}
+
+	// The next scan completes the index
+	if _, count, err := Run(tmpDir); err != nil || count != 10 {
+		t.Fatalf("follow-up scan: %d files, %v", count, err)
+	}
+	if latest, err := index.LatestScan(db); err != nil || latest.Partial {
+		t.Errorf("completed scan should not be partial, got %+v, %v", latest, err)
+	}
+}
+
+func TestRunContextProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"a.go": "package main\n\nfunc a() {}\n",
+		"b.go": "package main\n\nfunc b() {}\n",
+		"c.py": "def c():\n    pass\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	profile := index.NewScanProfile()
+	if _, _, err := RunContext(index.WithScanProfile(context.Background(), profile), tmpDir); err != nil {
+		t.Fatalf("RunContext() error: %v", err)
+	}
+
+	report := profile.Report()
+	phases := make(map[string]time.Duration)
+	for _, p := range report.Phases {
+		phases[p.Name] = p.Duration
+	}
+	for _, name := range []string{index.PhaseWalk, index.PhaseParse, index.PhasePersist} {
+		if _, ok := phases[name]; !ok {
+			t.Errorf("phase %q missing from profile %+v", name, report.Phases)
+		}
+	}
+	if phases[index.PhaseParse] <= 0 {
+		t.Errorf("expected non-zero parse time, got %v", phases[index.PhaseParse])
+	}
+	languages := make(map[string]int)
+	for _, l := range report.Languages {
+		languages[l.Language] = l.Files
+	}
+	if languages["go"] != 2 || languages["python"] != 1 {
+		t.Errorf("expected 2 go files and 1 python file parsed, got %+v", report.Languages)
+	}
+}
@@ -0,0 +1,131 @@
+package scan
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/config"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/fsutil"
+	"github.com/koksalmehmet/mind-palace/apps/cli/internal/index"
+)
+
+// DefaultWatchInterval is how often the watcher polls the workspace for changes.
+const DefaultWatchInterval = 2 * time.Second
+
+// WatchOptions configures the workspace watcher.
+type WatchOptions struct {
+	Interval time.Duration
+	// OnChange is called after each batch of changes has been applied to the index.
+	OnChange func(changes []index.FileChange, summary index.IncrementalScanSummary)
+	// OnError is called for errors that do not stop the watcher (optional).
+	OnError func(err error)
+}
+
+// fileStamp is the cheap per-file signature used to decide whether to rehash.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// Watch polls the workspace and keeps the index at db up to date until ctx is
+// cancelled. Files are only rehashed when a size or mtime change is observed,
+// so an idle workspace costs one directory walk per interval.
+func Watch(ctx context.Context, db *sql.DB, root string, opts WatchOptions) error {
+	rootPath, err := resolveAndValidateRoot(root)
+	if err != nil {
+		return err
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	guardrails := config.LoadGuardrails(rootPath)
+
+	reportErr := func(err error) {
+		if opts.OnError != nil {
+			opts.OnError(err)
+		}
+	}
+
+	// Bring the index up to date before watching.
+	last, err := snapshotFiles(rootPath, guardrails)
+	if err != nil {
+		return err
+	}
+	if err := applyChanges(db, rootPath, guardrails, opts.OnChange); err != nil {
+		reportErr(err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			current, err := snapshotFiles(rootPath, guardrails)
+			if err != nil {
+				reportErr(err)
+				continue
+			}
+			if sameSnapshot(last, current) {
+				continue
+			}
+			last = current
+			if err := applyChanges(db, rootPath, guardrails, opts.OnChange); err != nil {
+				reportErr(err)
+			}
+		}
+	}
+}
+
+func applyChanges(db *sql.DB, rootPath string, guardrails config.Guardrails, onChange func([]index.FileChange, index.IncrementalScanSummary)) error {
+	changes, err := index.DetectChanges(db, rootPath, guardrails)
+	if err != nil {
+		return fmt.Errorf("detect changes: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	summary, err := index.IncrementalScan(db, rootPath, changes)
+	if err != nil {
+		return fmt.Errorf("incremental scan: %w", err)
+	}
+	if onChange != nil {
+		onChange(changes, summary)
+	}
+	return nil
+}
+
+func snapshotFiles(rootPath string, guardrails config.Guardrails) (map[string]fileStamp, error) {
+	files, err := fsutil.ListFiles(rootPath, guardrails)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+	stamps := make(map[string]fileStamp, len(files))
+	for _, rel := range files {
+		info, err := os.Stat(filepath.Join(rootPath, rel))
+		if err != nil {
+			continue
+		}
+		stamps[rel] = fileStamp{size: info.Size(), modTime: info.ModTime()}
+	}
+	return stamps, nil
+}
+
+func sameSnapshot(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		other, ok := b[path]
+		if !ok || other.size != stamp.size || !other.modTime.Equal(stamp.modTime) {
+			return false
+		}
+	}
+	return true
+}
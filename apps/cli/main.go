@@ -8,8 +8,10 @@ import (
 )
 
 func main() {
-	if err := cli.Run(os.Args[1:]); err != nil {
+	err := cli.Run(os.Args[1:])
+	code := cli.ExitCode(err)
+	if code != 0 {
 		fmt.Fprintf(os.Stderr, "palace: %v\n", err)
-		os.Exit(1)
 	}
+	os.Exit(code)
 }